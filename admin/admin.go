@@ -8,6 +8,8 @@ import (
 
 	"github.com/dracory/blockeditor"
 	adminBlocks "github.com/dracory/cmsstore/admin/blocks"
+	adminExperiments "github.com/dracory/cmsstore/admin/experiments"
+	adminForms "github.com/dracory/cmsstore/admin/forms"
 	adminMenus "github.com/dracory/cmsstore/admin/menus"
 	adminPages "github.com/dracory/cmsstore/admin/pages"
 	"github.com/dracory/cmsstore/admin/shared"
@@ -29,15 +31,16 @@ type admin struct {
 		Scripts    []string
 		ScriptURLs []string
 	}) string
-	logger          *slog.Logger
-	store           cmsstore.StoreInterface
-	adminHomeURL    string
-	mediaManagerURL string
-	paddingTopPx    int
-	paddingRightPx  int
-	paddingBottomPx int
-	paddingLeftPx   int
-	flags           map[string]bool
+	logger            *slog.Logger
+	store             cmsstore.StoreInterface
+	blockTypeRegistry *cmsstore.BlockTypeRegistry
+	adminHomeURL      string
+	mediaManagerURL   string
+	paddingTopPx      int
+	paddingRightPx    int
+	paddingBottomPx   int
+	paddingLeftPx     int
+	flags             map[string]bool
 }
 
 // == INTERFACE IMPLEMENTATION CHECK ==========================================
@@ -74,6 +77,11 @@ func (a *admin) getRoute(route string) func(w http.ResponseWriter, r *http.Reque
 	}
 
 	maps.Copy(routes, a.blockRoutes())
+	maps.Copy(routes, a.experimentRoutes())
+
+	if a.store.FormsEnabled() {
+		maps.Copy(routes, a.formRoutes())
+	}
 
 	if a.store.MenusEnabled() {
 		maps.Copy(routes, a.menuRoutes())
@@ -124,6 +132,7 @@ func (a *admin) blockRoutes() map[string]func(w http.ResponseWriter, r *http.Req
 	blockRoutes := map[string]func(w http.ResponseWriter, r *http.Request){
 		shared.PathBlocksBlockCreate:     adminBlocks.UI(a.uiConfig()).BlockCreate,
 		shared.PathBlocksBlockDelete:     adminBlocks.UI(a.uiConfig()).BlockDelete,
+		shared.PathBlocksBlockLayout:     adminBlocks.UI(a.uiConfig()).BlockLayout,
 		shared.PathBlocksBlockManager:    adminBlocks.UI(a.uiConfig()).BlockManager,
 		shared.PathBlocksBlockUpdate:     adminBlocks.UI(a.uiConfig()).BlockUpdate,
 		shared.PathBlocksBlockVersioning: adminBlocks.UI(a.uiConfig()).BlockVersioning,
@@ -131,6 +140,22 @@ func (a *admin) blockRoutes() map[string]func(w http.ResponseWriter, r *http.Req
 	return blockRoutes
 }
 
+func (a *admin) experimentRoutes() map[string]func(w http.ResponseWriter, r *http.Request) {
+	return map[string]func(w http.ResponseWriter, r *http.Request){
+		shared.PathExperimentsExperimentManager: adminExperiments.UI(a.uiConfig()).ExperimentManager,
+		shared.PathExperimentsExperimentUpdate:  adminExperiments.UI(a.uiConfig()).ExperimentUpdate,
+	}
+}
+
+func (a *admin) formRoutes() map[string]func(w http.ResponseWriter, r *http.Request) {
+	return map[string]func(w http.ResponseWriter, r *http.Request){
+		shared.PathFormsSubmissionDelete:  adminForms.UI(a.uiConfig()).SubmissionDelete,
+		shared.PathFormsSubmissionExport:  adminForms.UI(a.uiConfig()).SubmissionExport,
+		shared.PathFormsSubmissionManager: adminForms.UI(a.uiConfig()).SubmissionManager,
+		shared.PathFormsSubmissionView:    adminForms.UI(a.uiConfig()).SubmissionView,
+	}
+}
+
 func (a *admin) menuRoutes() map[string]func(w http.ResponseWriter, r *http.Request) {
 	menuRoutes := map[string]func(w http.ResponseWriter, r *http.Request){
 		shared.PathMenusMenuCreate:     adminMenus.UI(a.uiConfig()).MenuCreate,
@@ -147,6 +172,8 @@ func (a *admin) pageRoutes() map[string]func(w http.ResponseWriter, r *http.Requ
 		shared.PathPagesPageCreate:     adminPages.UI(a.uiConfig()).PageCreate,
 		shared.PathPagesPageDelete:     adminPages.UI(a.uiConfig()).PageDelete,
 		shared.PathPagesPageManager:    adminPages.UI(a.uiConfig()).PageManager,
+		shared.PathPagesPageSeoReport:  adminPages.UI(a.uiConfig()).PageSeoReport,
+		shared.PathPagesPageTree:       adminPages.UI(a.uiConfig()).PageTree,
 		shared.PathPagesPageUpdate:     adminPages.UI(a.uiConfig()).PageUpdate,
 		shared.PathPagesPageVersioning: adminPages.UI(a.uiConfig()).PageVersioning,
 	}
@@ -197,5 +224,6 @@ func (a *admin) uiConfig() shared.UiConfig {
 		Layout:                 a.render,
 		Logger:                 a.logger,
 		Store:                  a.store,
+		BlockTypeRegistry:      a.blockTypeRegistry,
 	}
 }
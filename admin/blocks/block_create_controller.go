@@ -196,8 +196,8 @@ func (controller *blockCreateController) prepareDataAndValidate(r *http.Request)
 func (controller *blockCreateController) getBlockTypeOptions() []form.FieldOption {
 	options := make([]form.FieldOption, 0)
 
-	// Get from global registry (new unified system)
-	globalTypes := cmsstore.GetAllBlockTypes()
+	// Get from the block type registry (new unified system)
+	globalTypes := controller.ui.BlockTypeRegistry().GetAll()
 	for typeKey, blockType := range globalTypes {
 		options = append(options, form.FieldOption{
 			Value: blockType.TypeLabel(),
@@ -235,7 +235,7 @@ func (controller *blockCreateController) saveBlock(r *http.Request, data blockCr
 	}
 
 	// Validate block type against registries
-	globalBlockType := cmsstore.GetBlockType(data.blockType)
+	globalBlockType := controller.ui.BlockTypeRegistry().Get(data.blockType)
 	registry := controller.ui.BlockAdminRegistry()
 	localProvider := registry.GetProvider(data.blockType)
 
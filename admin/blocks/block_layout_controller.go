@@ -0,0 +1,312 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/dracory/api"
+	"github.com/dracory/cdn"
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+const ActionBlockMove = "block_move"
+
+// == CONTROLLER ==============================================================
+
+// blockLayoutController shows the blocks of a site as a tree, where
+// blocks are nested in the container blocks (sections, rows, tabs, etc.)
+// and reordered by drag and drop
+type blockLayoutController struct {
+	ui UiInterface
+}
+
+// == CONSTRUCTOR =============================================================
+
+func NewBlockLayoutController(ui UiInterface) *blockLayoutController {
+	return &blockLayoutController{
+		ui: ui,
+	}
+}
+
+func (controller *blockLayoutController) Handler(w http.ResponseWriter, r *http.Request) string {
+	data, errorMessage := controller.prepareData(r)
+
+	if errorMessage != "" {
+		return api.Error(errorMessage).ToString()
+	}
+
+	if data.action == ActionBlockMove {
+		return controller.moveBlock(r)
+	}
+
+	options := struct {
+		Styles     []string
+		StyleURLs  []string
+		Scripts    []string
+		ScriptURLs []string
+	}{
+		Styles: []string{
+			`.block-layout-list { list-style: none; min-height: 12px; padding-left: 0; }
+			.block-layout-list .block-layout-list { margin: 8px 0 0 24px; padding: 4px; border: 1px dashed #ccc; border-radius: 4px; }
+			.block-layout-handle { cursor: move; }
+			.block-layout-placeholder { height: 40px; margin-bottom: 8px; border: 2px dashed #0d6efd; border-radius: 4px; }`,
+		},
+		Scripts: []string{
+			controller.script(data),
+		},
+		ScriptURLs: []string{
+			cdn.Jquery_3_7_1(),
+			cdn.JqueryUiJs_1_14_2(),
+			cdn.Sweetalert2_11(),
+		},
+	}
+
+	return controller.ui.Layout(w, r, "Block Layout | CMS", controller.page(data).ToHTML(), options)
+}
+
+// moveBlock moves a block into a container block (or to the top level),
+// at the dropped position
+func (controller *blockLayoutController) moveBlock(r *http.Request) string {
+	blockID := req.GetStringTrimmed(r, "block_id")
+	parentID := req.GetStringTrimmed(r, "parent_id")
+	position := cast.ToInt(req.GetStringTrimmed(r, "position"))
+
+	if blockID == "" {
+		return api.Error("block id is required").ToString()
+	}
+
+	block, err := controller.ui.Store().BlockFindByID(r.Context(), blockID)
+
+	if err != nil {
+		controller.ui.Logger().Error("At blockLayoutController > moveBlock", "error", err.Error())
+		return api.Error("error retrieving block").ToString()
+	}
+
+	if block == nil {
+		return api.Error("block not found").ToString()
+	}
+
+	if parentID != "" {
+		parent, err := controller.ui.Store().BlockFindByID(r.Context(), parentID)
+
+		if err != nil {
+			controller.ui.Logger().Error("At blockLayoutController > moveBlock", "error", err.Error())
+			return api.Error("error retrieving parent block").ToString()
+		}
+
+		if parent == nil {
+			return api.Error("parent block not found").ToString()
+		}
+
+		parentType := controller.ui.BlockTypeRegistry().Get(parent.Type())
+
+		if parentType == nil || !cmsstore.BlockTypeAllowsChild(parentType, block.Type()) {
+			return api.Error("a " + block.Type() + " block cannot be nested in a " + parent.Type() + " block").ToString()
+		}
+	}
+
+	if err := controller.ui.Store().BlockMove(r.Context(), block.ID(), parentID, position); err != nil {
+		return api.Error(err.Error()).ToString()
+	}
+
+	return api.Success("block moved successfully").ToString()
+}
+
+func (controller *blockLayoutController) page(data blockLayoutControllerData) hb.TagInterface {
+	adminHeader := shared.AdminHeader(controller.ui.Store(), controller.ui.Logger(), data.request)
+
+	breadcrumbs := shared.AdminBreadcrumbs(data.request, []shared.Breadcrumb{
+		{
+			Name: "Block Manager",
+			URL:  shared.URLR(data.request, shared.PathBlocksBlockManager, nil),
+		},
+		{
+			Name: "Block Layout",
+			URL:  shared.URLR(data.request, shared.PathBlocksBlockLayout, map[string]string{"site_id": data.siteID}),
+		},
+	}, struct{ SiteList []cmsstore.SiteInterface }{
+		SiteList: data.siteList,
+	})
+
+	buttonBack := hb.Hyperlink().
+		Class("btn btn-secondary ms-2 float-end").
+		Child(hb.I().Class("bi bi-chevron-left").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
+		HTML("Back").
+		Href(shared.URLR(data.request, shared.PathBlocksBlockManager, nil))
+
+	pageTitle := hb.Heading1().
+		HTML("CMS. Block Layout").
+		Child(buttonBack)
+
+	siteSelect := hb.Select().
+		Class("form-select").
+		Name("site_id").
+		OnChange(`window.location.href = '` + shared.URLR(data.request, shared.PathBlocksBlockLayout, nil) + `&site_id=' + encodeURIComponent(this.value);`).
+		Children(lo.Map(data.siteList, func(site cmsstore.SiteInterface, _ int) hb.TagInterface {
+			return hb.Option().
+				Value(site.ID()).
+				AttrIf(site.ID() == data.siteID, "selected", "selected").
+				Text(site.Name())
+		}))
+
+	return hb.Div().
+		Class("container").
+		Child(breadcrumbs).
+		Child(hb.HR()).
+		Child(adminHeader).
+		Child(hb.HR()).
+		Child(pageTitle).
+		Child(hb.Div().
+			Class("row mb-3").
+			Child(hb.Div().Class("col-md-4").Child(siteSelect)).
+			Child(hb.Div().
+				Class("col-md-8 text-muted").
+				Text("Drag the blocks by their handle to reorder them, or to nest them in a container block (section, grid row or column, tabs, accordion). Child blocks are rendered by their container in this order."))).
+		Child(hb.Div().
+			Class("card").
+			Child(hb.Div().
+				Class("card-body").
+				Child(controller.blockList(data, ""))))
+}
+
+// blockList renders the child blocks of the parent as a sortable list,
+// the top level blocks if the parent ID is empty
+func (controller *blockLayoutController) blockList(data blockLayoutControllerData, parentID string) hb.TagInterface {
+	list := hb.UL().
+		Class("block-layout-list").
+		Data("parent-id", parentID)
+
+	for _, block := range data.children[parentID] {
+		blockType := controller.ui.BlockTypeRegistry().Get(block.Type())
+		typeLabel := lo.IfF(blockType != nil, func() string { return blockType.TypeLabel() }).Else(block.Type())
+
+		item := hb.LI().
+			Class("block-layout-item mb-2").
+			Data("block-id", block.ID()).
+			Child(hb.Div().
+				Class("border rounded bg-light px-2 py-1").
+				Child(hb.I().Class("bi bi-grip-vertical block-layout-handle me-2")).
+				Child(hb.Hyperlink().
+					Text(lo.Ternary(block.Name() == "", block.ID(), block.Name())).
+					Href(shared.URLR(data.request, shared.PathBlocksBlockUpdate, map[string]string{
+						"block_id": block.ID(),
+					}))).
+				Child(hb.Span().Class("badge bg-info ms-2").Style("font-size: 11px;").Text(typeLabel)).
+				ChildIf(!block.IsActive(), hb.Span().Class("badge bg-secondary ms-2").Style("font-size: 11px;").Text(block.Status())))
+
+		if blockType != nil && cmsstore.BlockTypeIsContainer(blockType) {
+			item.Child(controller.blockList(data, block.ID()))
+		}
+
+		list.Child(item)
+	}
+
+	return list
+}
+
+// script makes the block lists sortable, posting each move
+func (controller *blockLayoutController) script(data blockLayoutControllerData) string {
+	moveURL := shared.URLR(data.request, shared.PathBlocksBlockLayout, map[string]string{
+		"action": ActionBlockMove,
+	})
+
+	return `
+$(function () {
+	$(".block-layout-list").sortable({
+		connectWith: ".block-layout-list",
+		handle: ".block-layout-handle",
+		placeholder: "block-layout-placeholder",
+		tolerance: "pointer",
+		update: function (event, ui) {
+			// Moving between lists triggers an update on both, only post once
+			if (this !== ui.item.parent()[0]) {
+				return;
+			}
+
+			$.post("` + moveURL + `", {
+				block_id: ui.item.data("block-id"),
+				parent_id: $(this).data("parent-id"),
+				position: ui.item.index()
+			}).done(function (response) {
+				response = typeof response === "string" ? JSON.parse(response) : response;
+				if (response.status !== "success") {
+					Swal.fire({icon: "error", text: response.message}).then(function () {
+						window.location.reload();
+					});
+				}
+			}).fail(function () {
+				Swal.fire({icon: "error", text: "Moving the block failed"}).then(function () {
+					window.location.reload();
+				});
+			});
+		}
+	});
+});`
+}
+
+func (controller *blockLayoutController) prepareData(r *http.Request) (data blockLayoutControllerData, errorMessage string) {
+	var err error
+	data.request = r
+	data.action = req.GetStringTrimmed(r, "action")
+	data.siteID = req.GetStringTrimmed(r, "site_id")
+
+	if data.action == ActionBlockMove {
+		return data, ""
+	}
+
+	data.siteList, err = controller.ui.Store().SiteList(r.Context(), cmsstore.SiteQuery().
+		SetOrderBy(cmsstore.COLUMN_NAME).
+		SetSortOrder(cmsstore.SORT_ORDER_ASC).
+		SetOffset(0).
+		SetLimit(100))
+
+	if err != nil {
+		controller.ui.Logger().Error("At blockLayoutController > prepareData", "error", err.Error())
+		return data, "error retrieving sites"
+	}
+
+	if data.siteID == "" && len(data.siteList) > 0 {
+		data.siteID = data.siteList[0].ID()
+	}
+
+	data.children = map[string][]cmsstore.BlockInterface{}
+
+	if data.siteID == "" {
+		return data, ""
+	}
+
+	blocks, err := controller.ui.Store().BlockList(r.Context(), cmsstore.BlockQuery().
+		SetSiteID(data.siteID).
+		SetOrderBy(cmsstore.COLUMN_SEQUENCE).
+		SetSortOrder(cmsstore.SORT_ORDER_ASC))
+
+	if err != nil {
+		controller.ui.Logger().Error("At blockLayoutController > prepareData", "error", err.Error())
+		return data, "error retrieving web blocks"
+	}
+
+	blockIDs := lo.Map(blocks, func(block cmsstore.BlockInterface, _ int) string { return block.ID() })
+
+	for _, block := range blocks {
+		// Blocks with a missing parent are shown at the top level
+		parentID := lo.Ternary(lo.Contains(blockIDs, block.ParentID()), block.ParentID(), "")
+		data.children[parentID] = append(data.children[parentID], block)
+	}
+
+	return data, ""
+}
+
+type blockLayoutControllerData struct {
+	request *http.Request
+	action  string
+	siteID  string
+
+	siteList []cmsstore.SiteInterface
+
+	// children are the blocks of the site, by parent ID
+	children map[string][]cmsstore.BlockInterface
+}
@@ -0,0 +1,168 @@
+package admin
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/cmsstore/testutils"
+	"github.com/dracory/test"
+	_ "modernc.org/sqlite"
+)
+
+func initBlockLayoutHandler(store cmsstore.StoreInterface) func(w http.ResponseWriter, r *http.Request) string {
+	ui := UI(shared.UiConfig{
+		Layout: shared.Layout,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Store:  store,
+	})
+
+	return NewBlockLayoutController(ui).Handler
+}
+
+func seedLayoutTestBlock(t *testing.T, store cmsstore.StoreInterface, siteID, blockType, name, parentID string) cmsstore.BlockInterface {
+	t.Helper()
+
+	block := cmsstore.NewBlock()
+	block.SetName(name)
+	block.SetType(blockType)
+	block.SetSiteID(siteID)
+	block.SetParentID(parentID)
+	block.SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	return block
+}
+
+func Test_BlockLayoutController_Index(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	section := seedLayoutTestBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_SECTION, "Hero Section", "")
+	child := seedLayoutTestBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_HTML, "Hero Text", section.ID())
+
+	handler := initBlockLayoutHandler(store)
+
+	body, response, err := test.CallStringEndpoint(http.MethodGet, handler, test.NewRequestOptions{})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, response.StatusCode)
+	}
+
+	expected := []string{
+		"Block Layout",
+		"Hero Section",
+		"Hero Text",
+		`data-parent-id="` + section.ID() + `"`,
+		`data-block-id="` + child.ID() + `"`,
+		"sortable",
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(body, s) {
+			t.Errorf("Expected body to contain %q", s)
+		}
+	}
+}
+
+func Test_BlockLayoutController_MoveBlock(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	section := seedLayoutTestBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_SECTION, "Section", "")
+	block := seedLayoutTestBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_HTML, "Text", "")
+
+	handler := initBlockLayoutHandler(store)
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		PostValues: map[string][]string{
+			"action":    {ActionBlockMove},
+			"block_id":  {block.ID()},
+			"parent_id": {section.ID()},
+			"position":  {"0"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	if !strings.Contains(body, "block moved successfully") {
+		t.Errorf("Expected body to contain 'block moved successfully', got: %s", body)
+	}
+
+	movedBlock, err := store.BlockFindByID(context.Background(), block.ID())
+	if err != nil {
+		t.Fatalf("Failed to find block: %v", err)
+	}
+	if movedBlock.ParentID() != section.ID() {
+		t.Errorf("Expected parent %s, got %s", section.ID(), movedBlock.ParentID())
+	}
+}
+
+func Test_BlockLayoutController_MoveBlock_DisallowedChild(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	row := seedLayoutTestBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_ROW, "Row", "")
+	block := seedLayoutTestBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_HTML, "Text", "")
+	nonContainer := seedLayoutTestBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_HTML, "Other Text", "")
+
+	handler := initBlockLayoutHandler(store)
+
+	for _, parent := range []cmsstore.BlockInterface{row, nonContainer} {
+		body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+			PostValues: map[string][]string{
+				"action":    {ActionBlockMove},
+				"block_id":  {block.ID()},
+				"parent_id": {parent.ID()},
+				"position":  {"0"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to call endpoint: %v", err)
+		}
+
+		if !strings.Contains(body, "cannot be nested") {
+			t.Errorf("Expected body to contain 'cannot be nested', got: %s", body)
+		}
+	}
+
+	unmovedBlock, err := store.BlockFindByID(context.Background(), block.ID())
+	if err != nil {
+		t.Fatalf("Failed to find block: %v", err)
+	}
+	if unmovedBlock.ParentID() != "" {
+		t.Errorf("Expected no parent, got %s", unmovedBlock.ParentID())
+	}
+}
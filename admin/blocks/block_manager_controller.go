@@ -259,9 +259,16 @@ func (controller *blockManagerController) page(data blockManagerControllerData)
 		HxTarget("body").
 		HxSwap("beforeend")
 
+	buttonLayout := hb.Hyperlink().
+		Class("btn btn-secondary float-end me-2").
+		Child(hb.I().Class("bi bi-diagram-3").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
+		HTML("Layout").
+		Href(shared.URLR(data.request, shared.PathBlocksBlockLayout, nil))
+
 	pageTitle := hb.Heading1().
 		HTML("CMS. Block Manager").
-		Child(buttonPageNew)
+		Child(buttonPageNew).
+		Child(buttonLayout)
 
 	return hb.Div().
 		Class("container").
@@ -365,9 +372,9 @@ func (controller *blockManagerController) tableRecords(data blockManagerControll
 
 				// Create type badge
 				blockType := block.Type()
-				blockTypeDef := cmsstore.GetBlockType(blockType)
+				blockTypeDef := controller.ui.BlockTypeRegistry().Get(blockType)
 				typeLabel := lo.IfF(blockTypeDef != nil, func() string { return blockTypeDef.TypeLabel() }).Else(blockType)
-				typeOrigin := cmsstore.GetBlockTypeOrigin(blockType)
+				typeOrigin := controller.ui.BlockTypeRegistry().GetOrigin(blockType)
 				typeBadge := hb.Span().
 					Class("badge").
 					Style("font-size: 11px;").
@@ -1,9 +1,12 @@
 package admin
 
 import (
+	"io"
+	"log/slog"
 	"testing"
 
 	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
 	"github.com/dracory/cmsstore/blocks/breadcrumbs"
 	"github.com/dracory/cmsstore/blocks/navbar"
 	"github.com/dracory/cmsstore/testutils"
@@ -50,3 +53,36 @@ func TestBlockRegistration(t *testing.T) {
 		}
 	}
 }
+
+// TestBlockRegistration_OwnRegistry tests that the built-in blocks are registered
+// in the registry of the UI config, keeping the block types of the app
+func TestBlockRegistration_OwnRegistry(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	registry := cmsstore.NewBlockTypeRegistry()
+	appNavbar := navbar.NewNavbarBlockType(store)
+	registry.RegisterWithOrigin(appNavbar, cmsstore.BLOCK_ORIGIN_CUSTOM)
+
+	ui := UI(shared.UiConfig{
+		Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Store:             store,
+		BlockTypeRegistry: registry,
+	})
+
+	if ui.BlockTypeRegistry() != registry {
+		t.Fatal("Expected the UI to use the registry of the config")
+	}
+
+	for _, typeKey := range []string{cmsstore.BLOCK_TYPE_HTML, cmsstore.BLOCK_TYPE_MENU, cmsstore.BLOCK_TYPE_BREADCRUMBS} {
+		if registry.Get(typeKey) == nil {
+			t.Errorf("Expected %s block to be registered", typeKey)
+		}
+	}
+
+	if registry.Get(cmsstore.BLOCK_TYPE_NAVBAR) != appNavbar {
+		t.Error("Expected the navbar block of the app to be kept")
+	}
+}
@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/form"
+)
+
+// blockSettingsFields generates the admin form fields for the typed
+// settings of a block type (see cmsstore.BlockTypeWithSettings)
+func blockSettingsFields(block cmsstore.BlockInterface, schema []cmsstore.BlockSettingDefinition) []form.FieldInterface {
+	values := cmsstore.BlockSettingsValues(block, schema)
+	fields := make([]form.FieldInterface, 0, len(schema))
+
+	for _, setting := range schema {
+		label := setting.Label
+		if label == "" {
+			label = setting.Name
+		}
+
+		options := form.FieldOptions{
+			Label:    label,
+			Name:     setting.Name,
+			Type:     form.FORM_FIELD_TYPE_STRING,
+			Value:    values[setting.Name],
+			Required: setting.Required,
+			Help:     setting.Help,
+		}
+
+		switch setting.Type {
+		case cmsstore.BLOCK_SETTING_TYPE_TEXT:
+			options.Type = form.FORM_FIELD_TYPE_TEXTAREA
+		case cmsstore.BLOCK_SETTING_TYPE_INT, cmsstore.BLOCK_SETTING_TYPE_FLOAT:
+			options.Type = form.FORM_FIELD_TYPE_NUMBER
+		case cmsstore.BLOCK_SETTING_TYPE_BOOL:
+			options.Type = form.FORM_FIELD_TYPE_SELECT
+			options.Options = []form.FieldOption{
+				{Value: "No", Key: "false"},
+				{Value: "Yes", Key: "true"},
+			}
+		case cmsstore.BLOCK_SETTING_TYPE_ENUM:
+			options.Type = form.FORM_FIELD_TYPE_SELECT
+
+			if !setting.Required {
+				options.Options = append(options.Options, form.FieldOption{Value: "", Key: ""})
+			}
+
+			for _, option := range setting.Options {
+				options.Options = append(options.Options, form.FieldOption{Value: option.Label, Key: option.Value})
+			}
+		}
+
+		fields = append(fields, form.NewField(options))
+	}
+
+	return fields
+}
@@ -0,0 +1,158 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+	"github.com/dracory/test"
+	_ "modernc.org/sqlite"
+)
+
+// settingsTestBlockType is a block type declaring a settings schema,
+// with no hand written admin fields
+type settingsTestBlockType struct{}
+
+func (t *settingsTestBlockType) TypeKey() string   { return "admin_settings_test" }
+func (t *settingsTestBlockType) TypeLabel() string { return "Settings Test" }
+func (t *settingsTestBlockType) Render(_ context.Context, _ cmsstore.BlockInterface, _ ...cmsstore.RenderOption) (string, error) {
+	return "", nil
+}
+func (t *settingsTestBlockType) GetAdminFields(_ cmsstore.BlockInterface, _ *http.Request) interface{} {
+	return nil
+}
+func (t *settingsTestBlockType) SaveAdminFields(_ *http.Request, _ cmsstore.BlockInterface) error {
+	return nil
+}
+func (t *settingsTestBlockType) GetCustomVariables() []cmsstore.BlockCustomVariable { return nil }
+func (t *settingsTestBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	one := 1.0
+
+	return []cmsstore.BlockSettingDefinition{
+		{Name: "heading", Label: "Heading", Type: cmsstore.BLOCK_SETTING_TYPE_STRING, Required: true},
+		{Name: "columns", Label: "Columns", Type: cmsstore.BLOCK_SETTING_TYPE_INT, Default: "3", MinValue: &one},
+		{Name: "layout", Label: "Layout", Type: cmsstore.BLOCK_SETTING_TYPE_ENUM, Default: "grid", Options: []cmsstore.BlockSettingOption{
+			{Value: "grid", Label: "Grid"},
+			{Value: "list", Label: "List"},
+		}},
+	}
+}
+
+func seedSettingsTestBlock(t *testing.T) (cmsstore.StoreInterface, cmsstore.BlockInterface) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	cmsstore.RegisterCustomBlockType(&settingsTestBlockType{})
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	block := cmsstore.NewBlock()
+	block.SetName("Settings Block")
+	block.SetType("admin_settings_test")
+	block.SetSiteID(site.ID())
+	block.SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+	err = store.BlockCreate(context.Background(), block)
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	return store, block
+}
+
+func Test_BlockUpdateController_SettingsSchema_ViewContent(t *testing.T) {
+	store, block := seedSettingsTestBlock(t)
+
+	handler := initBlockUpdateHandler(store)
+
+	body, response, err := test.CallStringEndpoint(http.MethodGet, handler, test.NewRequestOptions{
+		GetValues: map[string][]string{
+			"block_id": {block.ID()},
+			"view":     {"content"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, response.StatusCode)
+	}
+
+	for _, expected := range []string{`name="heading"`, `name="columns"`, `name="layout"`, "List"} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected body to contain %q", expected)
+		}
+	}
+}
+
+func Test_BlockUpdateController_SettingsSchema_Save(t *testing.T) {
+	store, block := seedSettingsTestBlock(t)
+
+	handler := initBlockUpdateHandler(store)
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		PostValues: map[string][]string{
+			"block_id": {block.ID()},
+			"view":     {"content"},
+			"heading":  {"Latest News"},
+			"columns":  {"4"},
+			"layout":   {"list"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+	if !strings.Contains(strings.ToLower(body), "block updated successfully") {
+		t.Errorf("Expected body to contain 'block updated successfully'")
+	}
+
+	updatedBlock, err := store.BlockFindByID(context.Background(), block.ID())
+	if err != nil {
+		t.Fatalf("Failed to find block: %v", err)
+	}
+	if updatedBlock.Meta("heading") != "Latest News" {
+		t.Errorf("Expected heading %q, got %q", "Latest News", updatedBlock.Meta("heading"))
+	}
+	if updatedBlock.Meta("columns") != "4" {
+		t.Errorf("Expected columns %q, got %q", "4", updatedBlock.Meta("columns"))
+	}
+	if updatedBlock.Meta("layout") != "list" {
+		t.Errorf("Expected layout %q, got %q", "list", updatedBlock.Meta("layout"))
+	}
+}
+
+func Test_BlockUpdateController_SettingsSchema_ValidationError(t *testing.T) {
+	store, block := seedSettingsTestBlock(t)
+
+	handler := initBlockUpdateHandler(store)
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		PostValues: map[string][]string{
+			"block_id": {block.ID()},
+			"view":     {"content"},
+			"heading":  {"Latest News"},
+			"columns":  {"0"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+	if !strings.Contains(body, "Columns must be at least 1") {
+		t.Errorf("Expected body to contain the validation error")
+	}
+
+	updatedBlock, err := store.BlockFindByID(context.Background(), block.ID())
+	if err != nil {
+		t.Fatalf("Failed to find block: %v", err)
+	}
+	if updatedBlock.Meta("heading") != "" {
+		t.Errorf("Expected heading not to be saved, got %q", updatedBlock.Meta("heading"))
+	}
+}
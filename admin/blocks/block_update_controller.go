@@ -18,6 +18,7 @@ import (
 
 const VIEW_SETTINGS = "settings"
 const VIEW_CONTENT = "content"
+const VIEW_USAGE = "usage"
 
 const codemirrorCss = "//cdnjs.cloudflare.com/ajax/libs/codemirror/3.20.0/codemirror.min.css"
 const codemirrorJs = "//cdnjs.cloudflare.com/ajax/libs/codemirror/3.20.0/codemirror.min.js"
@@ -111,6 +112,7 @@ func (controller blockUpdateController) page(data blockUpdateControllerData) hb.
 
 	buttonSave := hb.Button().
 		Class("btn btn-primary ms-2 float-end").
+		ClassIf(data.view == VIEW_USAGE, "d-none").
 		Child(hb.I().Class("bi bi-save").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
 		HTML("Save").
 		HxInclude("#FormBlockUpdate").
@@ -148,6 +150,7 @@ func (controller blockUpdateController) page(data blockUpdateControllerData) hb.
 		Text(" ").
 		Text(data.block.Name()).
 		Child(hb.Sup().Child(badgeStatus)).
+		ChildIf(data.block.IsLibrary(), hb.Sup().Child(hb.Div().Class("badge fs-6 ms-2 bg-info").Text("Library"))).
 		Child(buttonSave).
 		Child(buttonVersion).
 		Child(buttonCancel)
@@ -161,13 +164,15 @@ func (controller blockUpdateController) page(data blockUpdateControllerData) hb.
 				Child(hb.Heading4().
 					HTMLIf(data.view == VIEW_CONTENT, "Block Content").
 					HTMLIf(data.view == VIEW_SETTINGS, "Block Settings").
+					HTMLIf(data.view == VIEW_USAGE, "Where Used").
 					Style("margin-bottom:0;display:inline-block;")).
 				Child(buttonSave),
 		).
 		Child(
 			hb.Div().
 				Class("card-body").
-				Child(controller.form(data)))
+				ChildIf(data.view != VIEW_USAGE, controller.form(data)).
+				ChildIf(data.view == VIEW_USAGE, controller.usageTable(data)))
 
 	tabs := bs.NavTabs().
 		Class("mb-3").
@@ -186,7 +191,15 @@ func (controller blockUpdateController) page(data blockUpdateControllerData) hb.
 					"block_id": data.blockID,
 					"view":     VIEW_SETTINGS,
 				})).
-				HTML("Settings")))
+				HTML("Settings"))).
+		Child(bs.NavItem().
+			Child(bs.NavLink().
+				ClassIf(data.view == VIEW_USAGE, "active").
+				Href(shared.URLR(data.request, shared.PathBlocksBlockUpdate, map[string]string{
+					"block_id": data.blockID,
+					"view":     VIEW_USAGE,
+				})).
+				HTML("Where Used")))
 
 	return hb.Div().
 		Class("container").
@@ -220,10 +233,60 @@ func (controller blockUpdateController) page(data blockUpdateControllerData) hb.
 		Child(controller.customVariablesSection(data))
 }
 
+// usageTable renders where the block is used, with the library fields
+// overridden by each of the references
+func (controller blockUpdateController) usageTable(data blockUpdateControllerData) hb.TagInterface {
+	if len(data.usages) == 0 {
+		return hb.Div().
+			Class("alert alert-info mb-0").
+			Text("This block is not used in any page, template or block of its site.")
+	}
+
+	rows := hb.Tbody()
+	for _, usage := range data.usages {
+		url, label := "", usage.EntityType
+		switch usage.EntityType {
+		case cmsstore.BLOCK_USAGE_ENTITY_PAGE:
+			label = "Page"
+			url = shared.URLR(data.request, shared.PathPagesPageUpdate, map[string]string{"page_id": usage.EntityID})
+		case cmsstore.BLOCK_USAGE_ENTITY_TEMPLATE:
+			label = "Template"
+			url = shared.URLR(data.request, shared.PathTemplatesTemplateUpdate, map[string]string{"template_id": usage.EntityID})
+		case cmsstore.BLOCK_USAGE_ENTITY_BLOCK:
+			label = "Block"
+			url = shared.URLR(data.request, shared.PathBlocksBlockUpdate, map[string]string{"block_id": usage.EntityID})
+		}
+
+		overrides := hb.Div()
+		for _, field := range data.block.LibraryFields() {
+			if value, exists := usage.Overrides[field]; exists {
+				overrides.Child(hb.Div().
+					Child(hb.Code().Text(field)).
+					Text(" = ").
+					Text(value))
+			}
+		}
+
+		rows.Child(hb.TR().
+			Child(hb.TD().Text(label)).
+			Child(hb.TD().Child(hb.Hyperlink().Href(url).Text(lo.Ternary(usage.EntityName == "", usage.EntityID, usage.EntityName)))).
+			Child(hb.TD().Child(overrides)))
+	}
+
+	return hb.Table().
+		Class("table table-sm table-bordered mb-0").
+		Child(hb.Thead().
+			Child(hb.TR().
+				Child(hb.TH().Text("Type")).
+				Child(hb.TH().Text("Name")).
+				Child(hb.TH().Text("Overrides")))).
+		Child(rows)
+}
+
 // customVariablesSection renders a reference table of custom variables exposed by the block type.
 // Returns nil if the block type exposes no custom variables.
 func (controller blockUpdateController) customVariablesSection(data blockUpdateControllerData) hb.TagInterface {
-	blockType := cmsstore.GetBlockType(data.block.Type())
+	blockType := controller.ui.BlockTypeRegistry().Get(data.block.Type())
 	if blockType == nil {
 		return hb.Div()
 	}
@@ -318,17 +381,22 @@ func (controller blockUpdateController) fieldsContent(data blockUpdateController
 	var fieldsContent []form.FieldInterface
 
 	// First, check global BlockType registry
-	globalBlockType := cmsstore.GetBlockType(blockType)
+	globalBlockType := controller.ui.BlockTypeRegistry().Get(blockType)
 	if globalBlockType != nil {
 		fields := globalBlockType.GetAdminFields(data.block, data.request)
 		if formFields, ok := fields.([]form.FieldInterface); ok {
 			fieldsContent = formFields
-		} else {
+		} else if fields != nil {
 			controller.ui.Logger().Error("GetAdminFields returned unexpected type",
 				"blockType", blockType,
 				"actualType", fmt.Sprintf("%T", fields))
 			// Fall through to legacy provider fallback
 		}
+
+		// Typed settings are generated from the block type settings schema
+		if schema := cmsstore.BlockTypeSettingsSchema(globalBlockType); len(schema) > 0 {
+			fieldsContent = append(fieldsContent, blockSettingsFields(data.block, schema)...)
+		}
 	}
 
 	if len(fieldsContent) == 0 {
@@ -470,8 +538,8 @@ func (controller blockUpdateController) fieldsSettings(data blockUpdateControlle
 			OptionsF: func() []form.FieldOption {
 				options := []form.FieldOption{}
 
-				// Add block types from global registry
-				globalTypes := cmsstore.GetAllBlockTypes()
+				// Add block types from the block type registry
+				globalTypes := controller.ui.BlockTypeRegistry().GetAll()
 				for typeKey, blockType := range globalTypes {
 					options = append(options, form.FieldOption{
 						Value: blockType.TypeLabel(),
@@ -520,6 +588,32 @@ func (controller blockUpdateController) fieldsSettings(data blockUpdateControlle
 		Help:  "Admin notes for this block. These notes will not be visible to the public.",
 	})
 
+	fieldLibrary := form.NewField(form.FieldOptions{
+		Label: "Library Block",
+		Name:  "block_library",
+		Type:  form.FORM_FIELD_TYPE_SELECT,
+		Value: data.formLibrary,
+		Help:  "Library blocks can be embedded on any page as linked instances, i.e. <block id=\"cta\" title=\"...\" />. Changes to the library block apply to all its instances.",
+		Options: []form.FieldOption{
+			{
+				Value: "No",
+				Key:   "no",
+			},
+			{
+				Value: "Yes",
+				Key:   "yes",
+			},
+		},
+	})
+
+	fieldLibraryFields := form.NewField(form.FieldOptions{
+		Label: "Overridable Fields",
+		Name:  "block_library_fields",
+		Type:  form.FORM_FIELD_TYPE_STRING,
+		Value: data.formLibraryFields,
+		Help:  "Comma separated names of the metas, which the instances can override with attributes. The values are available as [[name]] placeholders in the block content.",
+	})
+
 	fieldBlockID := form.NewField(form.FieldOptions{
 		Label:    "Block Reference / ID",
 		Name:     "block_id",
@@ -543,10 +637,13 @@ func (controller blockUpdateController) fieldsSettings(data blockUpdateControlle
 		fieldBlockName,
 		fieldSiteID,
 		fieldMemo,
-		fieldBlockID,
-		fieldView,
+		fieldLibrary,
+		fieldLibraryFields,
 	}
 
+	fieldsSettings = append(fieldsSettings, blockVisibilityFields(data.block, controller.ui.Store().TranslationLanguages())...)
+	fieldsSettings = append(fieldsSettings, fieldBlockID, fieldView)
+
 	return fieldsSettings
 }
 
@@ -558,6 +655,12 @@ func (controller blockUpdateController) saveBlock(r *http.Request, data blockUpd
 	data.formStatus = req.GetStringTrimmed(r, "block_status")
 	data.formTitle = req.GetStringTrimmed(r, "block_title")
 	data.formType = req.GetStringTrimmed(r, "block_type")
+	data.formLibrary = req.GetStringTrimmed(r, "block_library")
+	data.formLibraryFields = req.GetStringTrimmed(r, "block_library_fields")
+
+	if data.view == VIEW_USAGE {
+		return data, "the where used view cannot be saved"
+	}
 
 	if data.view == VIEW_SETTINGS {
 		if data.formStatus == "" {
@@ -579,7 +682,7 @@ func (controller blockUpdateController) saveBlock(r *http.Request, data blockUpd
 			}
 
 			// Validate that the new type exists in the registry
-			globalBlockType := cmsstore.GetBlockType(data.formType)
+			globalBlockType := controller.ui.BlockTypeRegistry().Get(data.formType)
 			if globalBlockType == nil {
 				// Check if it's a basic fallback type
 				validBasicTypes := map[string]bool{
@@ -609,6 +712,26 @@ func (controller blockUpdateController) saveBlock(r *http.Request, data blockUpd
 			data.block.SetMetas(map[string]string{})
 			data.block.SetType(data.formType)
 		}
+
+		// Only store the library metas when changed, non-library blocks keep no metas
+		if isLibrary := data.formLibrary == "yes"; isLibrary != data.block.IsLibrary() {
+			if err := data.block.SetLibrary(isLibrary); err != nil {
+				data.formErrorMessage = err.Error()
+				return data, ""
+			}
+		}
+
+		if data.formLibraryFields != strings.Join(data.block.LibraryFields(), ", ") {
+			if err := data.block.SetLibraryFields(strings.Split(data.formLibraryFields, ",")); err != nil {
+				data.formErrorMessage = err.Error()
+				return data, ""
+			}
+		}
+
+		if err := cmsstore.SetBlockVisibilityRules(data.block, blockVisibilityFromRequest(r)); err != nil {
+			data.formErrorMessage = err.Error()
+			return data, ""
+		}
 	}
 
 	if data.view == VIEW_CONTENT {
@@ -618,13 +741,21 @@ func (controller blockUpdateController) saveBlock(r *http.Request, data blockUpd
 		}
 
 		// First, check global BlockType registry
-		globalBlockType := cmsstore.GetBlockType(blockType)
+		globalBlockType := controller.ui.BlockTypeRegistry().Get(blockType)
 		if globalBlockType != nil {
 			err := globalBlockType.SaveAdminFields(r, data.block)
 			if err != nil {
 				data.formErrorMessage = err.Error()
 				return data, ""
 			}
+
+			if schema := cmsstore.BlockTypeSettingsSchema(globalBlockType); len(schema) > 0 {
+				err := cmsstore.ApplyBlockSettings(data.block, schema, cmsstore.BlockSettingsFromRequest(r, schema))
+				if err != nil {
+					data.formErrorMessage = err.Error()
+					return data, ""
+				}
+			}
 		} else {
 			// Fall back to local admin provider registry
 			registry := controller.ui.BlockAdminRegistry()
@@ -703,8 +834,19 @@ func (controller blockUpdateController) prepareDataAndValidate(r *http.Request)
 	data.formSiteID = data.block.SiteID()
 	data.formStatus = data.block.Status()
 	data.formType = data.block.Type()
+	data.formLibrary = lo.Ternary(data.block.IsLibrary(), "yes", "no")
+	data.formLibraryFields = strings.Join(data.block.LibraryFields(), ", ")
 
 	if r.Method != http.MethodPost {
+		if data.view == VIEW_USAGE {
+			data.usages, err = controller.ui.Store().BlockUsageList(r.Context(), data.block)
+
+			if err != nil {
+				controller.ui.Logger().Error("At blockUpdateController > prepareDataAndValidate", "error", err.Error())
+				return data, "Block usage failed to be retrieved. " + err.Error()
+			}
+		}
+
 		return data, ""
 	}
 
@@ -719,6 +861,7 @@ type blockUpdateControllerData struct {
 	view    string
 
 	siteList []cmsstore.SiteInterface
+	usages   []cmsstore.BlockUsage
 
 	formErrorMessage   string
 	formRedirectURL    string
@@ -730,6 +873,8 @@ type blockUpdateControllerData struct {
 	formStatus         string
 	formTitle          string
 	formType           string
+	formLibrary        string
+	formLibraryFields  string
 }
 
 // codeSnippet creates a code snippet card with copy-to-clipboard functionality
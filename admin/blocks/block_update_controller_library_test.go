@@ -0,0 +1,126 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+	"github.com/dracory/test"
+)
+
+func Test_BlockUpdateController_UpdateLibrarySettings(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	block := cmsstore.NewBlock()
+	block.SetName("Call to Action")
+	block.SetSiteID(site.ID())
+	block.SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+	err = store.BlockCreate(context.Background(), block)
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	handler := initBlockUpdateHandler(store)
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		PostValues: map[string][]string{
+			"block_id":             {block.ID()},
+			"block_name":           {"Call to Action"},
+			"block_site_id":        {site.ID()},
+			"block_status":         {cmsstore.BLOCK_STATUS_ACTIVE},
+			"block_library":        {"yes"},
+			"block_library_fields": {"title, url"},
+			"view":                 {"settings"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	if !strings.Contains(strings.ToLower(body), "block updated successfully") {
+		t.Errorf("Expected body to contain 'block updated successfully'")
+	}
+
+	updatedBlock, err := store.BlockFindByID(context.Background(), block.ID())
+	if err != nil {
+		t.Fatalf("Failed to find block: %v", err)
+	}
+	if !updatedBlock.IsLibrary() {
+		t.Error("Expected the block to be a library block")
+	}
+	if fields := updatedBlock.LibraryFields(); strings.Join(fields, ",") != "title,url" {
+		t.Errorf("Expected library fields %q, got %q", "title,url", strings.Join(fields, ","))
+	}
+}
+
+func Test_BlockUpdateController_ViewUsage(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	block := cmsstore.NewBlock()
+	block.SetName("Call to Action")
+	block.SetHandle("cta")
+	block.SetSiteID(site.ID())
+	block.SetLibrary(true)
+	block.SetLibraryFields([]string{"title"})
+	err = store.BlockCreate(context.Background(), block)
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	page := cmsstore.NewPage()
+	page.SetName("Pricing Page")
+	page.SetSiteID(site.ID())
+	page.SetContent(`<block id="cta" title="Start your trial" />`)
+	err = store.PageCreate(context.Background(), page)
+	if err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	handler := initBlockUpdateHandler(store)
+
+	body, response, err := test.CallStringEndpoint(http.MethodGet, handler, test.NewRequestOptions{
+		GetValues: map[string][]string{
+			"block_id": {block.ID()},
+			"view":     {VIEW_USAGE},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, response.StatusCode)
+	}
+
+	expecteds := []string{
+		"Where Used",
+		"Library",
+		"Pricing Page",
+		"page_id=" + page.ID(),
+		"Start your trial",
+	}
+
+	for _, expected := range expecteds {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected body to contain %q", expected)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+	"github.com/dracory/test"
+)
+
+func Test_BlockUpdateController_UpdateVisibilitySettings(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	block := cmsstore.NewBlock()
+	block.SetName("Summer Promotion")
+	block.SetSiteID(site.ID())
+	block.SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+	err = store.BlockCreate(context.Background(), block)
+	if err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	handler := initBlockUpdateHandler(store)
+
+	postValues := map[string][]string{
+		"block_id":                     {block.ID()},
+		"block_name":                   {"Summer Promotion"},
+		"block_site_id":                {site.ID()},
+		"block_status":                 {cmsstore.BLOCK_STATUS_ACTIVE},
+		"block_visibility_date_from":   {"2026-06-01T00:00"},
+		"block_visibility_date_to":     {"2026-08-31T23:59"},
+		"block_visibility_devices":     {"Mobile, tablet"},
+		"block_visibility_logged_in":   {cmsstore.BLOCK_VISIBILITY_LOGGED_IN_NO},
+		"block_visibility_percentage":  {"50"},
+		"block_visibility_query_param": {"utm_campaign=summer"},
+		"view":                         {"settings"},
+	}
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		PostValues: postValues,
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	if !strings.Contains(strings.ToLower(body), "block updated successfully") {
+		t.Errorf("Expected body to contain 'block updated successfully'")
+	}
+
+	updatedBlock, err := store.BlockFindByID(context.Background(), block.ID())
+	if err != nil {
+		t.Fatalf("Failed to find block: %v", err)
+	}
+
+	rules, err := cmsstore.BlockVisibilityRules(updatedBlock)
+	if err != nil {
+		t.Fatalf("Failed to read visibility rules: %v", err)
+	}
+
+	if rules.DateFrom != "2026-06-01 00:00:00" || rules.DateTo != "2026-08-31 23:59:00" {
+		t.Errorf("Unexpected dates %q - %q", rules.DateFrom, rules.DateTo)
+	}
+	if strings.Join(rules.Devices, ",") != "mobile,tablet" {
+		t.Errorf("Expected devices mobile,tablet, got %v", rules.Devices)
+	}
+	if rules.LoggedIn != cmsstore.BLOCK_VISIBILITY_LOGGED_IN_NO || rules.Percentage != 50 || rules.QueryParam != "utm_campaign=summer" {
+		t.Errorf("Unexpected rules %+v", rules)
+	}
+
+	postValues["block_visibility_devices"] = []string{"watch"}
+
+	body, _, err = test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		PostValues: postValues,
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	if !strings.Contains(body, "visibility device is not supported") {
+		t.Errorf("Expected the invalid device error, got %s", body)
+	}
+}
+
+func Test_BlockUpdateController_VisibilityFieldsDisplayed(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	block := cmsstore.NewBlock()
+	block.SetName("Summer Promotion")
+	block.SetSiteID(site.ID())
+	block.SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+	if err := cmsstore.SetBlockVisibilityRules(block, cmsstore.BlockVisibility{DateFrom: "2026-06-01 08:30:00"}); err != nil {
+		t.Fatalf("Failed to set visibility rules: %v", err)
+	}
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	body, _, err := test.CallStringEndpoint(http.MethodGet, initBlockUpdateHandler(store), test.NewRequestOptions{
+		GetValues: map[string][]string{
+			"block_id": {block.ID()},
+			"view":     {"settings"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	for _, expected := range []string{"Visibility Rules", "block_visibility_date_from", `value="2026-06-01T08:30"`, "Rollout Percentage"} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected body to contain %q", expected)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package admin
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/form"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/spf13/cast"
+)
+
+// blockVisibilityFields generates the admin form fields for the visibility
+// rules of the block (see cmsstore.BlockVisibility)
+func blockVisibilityFields(block cmsstore.BlockInterface, languages map[string]string) []form.FieldInterface {
+	rules, _ := cmsstore.BlockVisibilityRules(block)
+
+	languagesHelp := "Comma separated language codes, i.e. en, fr. Leave empty for all the languages."
+	if len(languages) > 0 {
+		languagesHelp = "Comma separated language codes (" + strings.Join(slices.Sorted(maps.Keys(languages)), ", ") + "). Leave empty for all the languages."
+	}
+
+	percentage := ""
+	if rules.Percentage > 0 && rules.Percentage < 100 {
+		percentage = strconv.Itoa(rules.Percentage)
+	}
+
+	return []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Type:  form.FORM_FIELD_TYPE_RAW,
+			Value: hb.Heading5().Class("mt-4").Text("Visibility Rules").ToHTML(),
+		}),
+		form.NewField(form.FieldOptions{
+			Type:  form.FORM_FIELD_TYPE_RAW,
+			Value: hb.Paragraph().Class("text-muted").Text("The rules apply to published blocks only. All the rules set must match for the block to be displayed.").ToHTML(),
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Display From (UTC)",
+			Name:  "block_visibility_date_from",
+			Type:  form.FORM_FIELD_TYPE_DATETIME,
+			Value: visibilityDateInputValue(rules.DateFrom),
+			Help:  "The block is displayed from this date. Leave empty to display it immediately.",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Display Until (UTC)",
+			Name:  "block_visibility_date_to",
+			Type:  form.FORM_FIELD_TYPE_DATETIME,
+			Value: visibilityDateInputValue(rules.DateTo),
+			Help:  "The block is displayed until this date. Leave empty to display it indefinitely.",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Languages",
+			Name:  "block_visibility_languages",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: strings.Join(rules.Languages, ", "),
+			Help:  languagesHelp,
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Devices",
+			Name:  "block_visibility_devices",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: strings.Join(rules.Devices, ", "),
+			Help:  "Comma separated device classes (" + strings.Join(cmsstore.BlockVisibilityDevices(), ", ") + "), detected from the User-Agent. Leave empty for all the devices.",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Query Parameter",
+			Name:  "block_visibility_query_param",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: rules.QueryParam,
+			Help:  "The query parameter which must be present, as name or name=value, i.e. utm_campaign=summer.",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Cookie",
+			Name:  "block_visibility_cookie",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: rules.Cookie,
+			Help:  "The cookie which must be present, as name or name=value.",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Logged In Visitors",
+			Name:  "block_visibility_logged_in",
+			Type:  form.FORM_FIELD_TYPE_SELECT,
+			Value: rules.LoggedIn,
+			Help:  "Whether the visitor is logged in, as told by the application.",
+			Options: []form.FieldOption{
+				{Value: "All visitors", Key: ""},
+				{Value: "Logged in only", Key: cmsstore.BLOCK_VISIBILITY_LOGGED_IN_YES},
+				{Value: "Not logged in only", Key: cmsstore.BLOCK_VISIBILITY_LOGGED_IN_NO},
+			},
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Rollout Percentage",
+			Name:  "block_visibility_percentage",
+			Type:  form.FORM_FIELD_TYPE_NUMBER,
+			Value: percentage,
+			Help:  "The percentage of the visitors (1-99) the block is displayed to, the same visitor always seeing the same. Leave empty for all the visitors.",
+		}),
+	}
+}
+
+// blockVisibilityFromRequest returns the visibility rules posted
+// with the block settings form
+func blockVisibilityFromRequest(r *http.Request) cmsstore.BlockVisibility {
+	return cmsstore.BlockVisibility{
+		DateFrom:   req.GetStringTrimmed(r, "block_visibility_date_from"),
+		DateTo:     req.GetStringTrimmed(r, "block_visibility_date_to"),
+		Languages:  splitVisibilityList(req.GetStringTrimmed(r, "block_visibility_languages")),
+		Devices:    splitVisibilityList(strings.ToLower(req.GetStringTrimmed(r, "block_visibility_devices"))),
+		QueryParam: req.GetStringTrimmed(r, "block_visibility_query_param"),
+		Cookie:     req.GetStringTrimmed(r, "block_visibility_cookie"),
+		LoggedIn:   req.GetStringTrimmed(r, "block_visibility_logged_in"),
+		Percentage: cast.ToInt(req.GetStringTrimmed(r, "block_visibility_percentage")),
+	}
+}
+
+// splitVisibilityList splits the comma separated list, skipping
+// the empty and duplicate values
+func splitVisibilityList(value string) []string {
+	list := []string{}
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// visibilityDateInputValue converts the visibility date
+// to the value of a datetime-local input
+func visibilityDateInputValue(date string) string {
+	if len(date) < 16 {
+		return date
+	}
+
+	return strings.Replace(date[:16], " ", "T", 1)
+}
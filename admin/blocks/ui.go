@@ -8,7 +8,11 @@ import (
 	"github.com/dracory/cmsstore"
 	"github.com/dracory/cmsstore/admin/shared"
 	breadcrumbsblock "github.com/dracory/cmsstore/blocks/breadcrumbs"
+	entitylistblock "github.com/dracory/cmsstore/blocks/entitylist"
+	formsblock "github.com/dracory/cmsstore/blocks/forms"
 	htmlblock "github.com/dracory/cmsstore/blocks/html"
+	layoutblock "github.com/dracory/cmsstore/blocks/layout"
+	mediablock "github.com/dracory/cmsstore/blocks/media"
 	menublock "github.com/dracory/cmsstore/blocks/menu"
 	navbarblock "github.com/dracory/cmsstore/blocks/navbar"
 )
@@ -18,7 +22,15 @@ var (
 )
 
 func UI(config shared.UiConfig) UiInterface {
-	registry := initBlockAdminProviders(config.Store, config.Logger)
+	blockTypeRegistry := config.BlockTypeRegistry
+	if blockTypeRegistry == nil && config.Store != nil {
+		blockTypeRegistry = config.Store.BlockTypeRegistry()
+	}
+	if blockTypeRegistry == nil {
+		blockTypeRegistry = cmsstore.DefaultBlockTypeRegistry()
+	}
+
+	registry := initBlockAdminProviders(config.Store, config.Logger, blockTypeRegistry)
 
 	return ui{
 		//
@@ -26,28 +38,55 @@ func UI(config shared.UiConfig) UiInterface {
 		logger:             config.Logger,
 		store:              config.Store,
 		blockAdminRegistry: registry,
+		blockTypeRegistry:  blockTypeRegistry,
 	}
 }
 
 // initBlockAdminProviders initializes and registers all built-in block types.
 //
-// Built-in block types are now registered globally using the unified BlockType system.
-// This ensures frontend rendering and admin UI are always in sync.
+// Built-in block types are registered in the block type registry using the unified
+// BlockType system. This ensures frontend rendering and admin UI are always in sync.
 //
 // The local registry is kept for backward compatibility with any custom blocks
 // that still use the old separate registration system.
-func initBlockAdminProviders(store cmsstore.StoreInterface, logger *slog.Logger) *BlockAdminFieldProviderRegistry {
+func initBlockAdminProviders(store cmsstore.StoreInterface, logger *slog.Logger, blockTypeRegistry *cmsstore.BlockTypeRegistry) *BlockAdminFieldProviderRegistry {
 	registry := NewBlockAdminFieldProviderRegistry()
 
-	// Register built-in block types globally (new unified system) - only once
-	// These are defined in blocks/html, blocks/menu, blocks/navbar, and blocks/breadcrumbs
-	// Using sync.Once prevents race conditions and duplicate registrations
-	blockTypesRegistered.Do(func() {
-		cmsstore.RegisterSystemBlockType(htmlblock.NewHTMLBlockType())
-		cmsstore.RegisterSystemBlockType(menublock.NewMenuBlockType(store, logger))
-		cmsstore.RegisterSystemBlockType(navbarblock.NewNavbarBlockType(store))
-		cmsstore.RegisterSystemBlockType(breadcrumbsblock.NewBreadcrumbsBlockType(store))
-	})
+	// These are defined in blocks/html, blocks/menu, blocks/navbar, blocks/breadcrumbs, blocks/layout, blocks/media,
+	// blocks/entitylist and blocks/forms
+	registerBuiltInBlockTypes := func() {
+		builtInBlockTypes := []cmsstore.BlockType{
+			htmlblock.NewHTMLBlockType(),
+			menublock.NewMenuBlockType(store, logger),
+			navbarblock.NewNavbarBlockType(store),
+			breadcrumbsblock.NewBreadcrumbsBlockType(store),
+			layoutblock.NewSectionBlockType(store),
+			layoutblock.NewRowBlockType(store),
+			layoutblock.NewColumnBlockType(store),
+			layoutblock.NewTabsBlockType(store),
+			layoutblock.NewAccordionBlockType(store),
+			mediablock.NewImageBlockType(store),
+			mediablock.NewGalleryBlockType(store),
+			mediablock.NewVideoBlockType(store),
+			entitylistblock.NewEntityListBlockType(store),
+			formsblock.NewFormBlockType(store),
+		}
+
+		for _, blockType := range builtInBlockTypes {
+			// Block types registered by the app (i.e. bound to another store) take precedence
+			if blockTypeRegistry.Get(blockType.TypeKey()) == nil {
+				blockTypeRegistry.RegisterWithOrigin(blockType, cmsstore.BLOCK_ORIGIN_SYSTEM)
+			}
+		}
+	}
+
+	if blockTypeRegistry == cmsstore.DefaultBlockTypeRegistry() {
+		// Register in the global registry only once
+		// Using sync.Once prevents race conditions and duplicate registrations
+		blockTypesRegistered.Do(registerBuiltInBlockTypes)
+	} else {
+		registerBuiltInBlockTypes()
+	}
 
 	// The local registry is kept empty for backward compatibility
 	// Custom blocks using the old system can still register here
@@ -60,9 +99,11 @@ type UiInterface interface {
 	BlockCreate(w http.ResponseWriter, r *http.Request)
 	BlockManager(w http.ResponseWriter, r *http.Request)
 	BlockDelete(w http.ResponseWriter, r *http.Request)
+	BlockLayout(w http.ResponseWriter, r *http.Request)
 	BlockUpdate(w http.ResponseWriter, r *http.Request)
 	BlockVersioning(w http.ResponseWriter, r *http.Request)
 	BlockAdminRegistry() *BlockAdminFieldProviderRegistry
+	BlockTypeRegistry() *cmsstore.BlockTypeRegistry
 }
 
 type ui struct {
@@ -76,6 +117,7 @@ type ui struct {
 	logger             *slog.Logger
 	store              cmsstore.StoreInterface
 	blockAdminRegistry *BlockAdminFieldProviderRegistry
+	blockTypeRegistry  *cmsstore.BlockTypeRegistry
 }
 
 // func (ui ui) Endpoint() string {
@@ -120,6 +162,13 @@ func (ui ui) BlockDelete(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(html))
 }
 
+func (ui ui) BlockLayout(w http.ResponseWriter, r *http.Request) {
+	controller := NewBlockLayoutController(ui)
+	html := controller.Handler(w, r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(html))
+}
+
 func (ui ui) BlockUpdate(w http.ResponseWriter, r *http.Request) {
 	controller := NewBlockUpdateController(ui)
 	html := controller.Handler(w, r)
@@ -155,3 +204,9 @@ func (ui ui) BlockVersioning(w http.ResponseWriter, r *http.Request) {
 func (ui ui) BlockAdminRegistry() *BlockAdminFieldProviderRegistry {
 	return ui.blockAdminRegistry
 }
+
+// BlockTypeRegistry returns the registry the block types are looked up in,
+// the registry of the store unless set in the UI config.
+func (ui ui) BlockTypeRegistry() *cmsstore.BlockTypeRegistry {
+	return ui.blockTypeRegistry
+}
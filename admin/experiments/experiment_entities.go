@@ -0,0 +1,151 @@
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dracory/cmsstore"
+)
+
+// Types of the entities running experiments
+const (
+	EntityTypeBlock = "block"
+	EntityTypePage  = "page"
+)
+
+// experimentEntity is a page or a block, with its experiment
+type experimentEntity struct {
+	Type       string
+	ID         string
+	Name       string
+	SiteID     string
+	Experiment *cmsstore.Experiment
+
+	block cmsstore.BlockInterface
+	page  cmsstore.PageInterface
+}
+
+// experimentEntityFromBlock returns the block as an experiment entity
+func experimentEntityFromBlock(block cmsstore.BlockInterface) (experimentEntity, error) {
+	experiment, err := cmsstore.BlockExperiment(block)
+
+	return experimentEntity{
+		Type:       EntityTypeBlock,
+		ID:         block.ID(),
+		Name:       block.Name(),
+		SiteID:     block.SiteID(),
+		Experiment: experiment,
+		block:      block,
+	}, err
+}
+
+// experimentEntityFromPage returns the page as an experiment entity
+func experimentEntityFromPage(page cmsstore.PageInterface) (experimentEntity, error) {
+	experiment, err := cmsstore.PageExperiment(page)
+
+	return experimentEntity{
+		Type:       EntityTypePage,
+		ID:         page.ID(),
+		Name:       page.Name(),
+		SiteID:     page.SiteID(),
+		Experiment: experiment,
+		page:       page,
+	}, err
+}
+
+// experimentEntityFind returns the page or block, nil if not found
+func experimentEntityFind(ctx context.Context, store cmsstore.StoreInterface, entityType, entityID string) (*experimentEntity, error) {
+	switch entityType {
+	case EntityTypeBlock:
+		block, err := store.BlockFindByID(ctx, entityID)
+		if err != nil || block == nil {
+			return nil, err
+		}
+
+		entity, err := experimentEntityFromBlock(block)
+		return &entity, err
+	case EntityTypePage:
+		page, err := store.PageFindByID(ctx, entityID)
+		if err != nil || page == nil {
+			return nil, err
+		}
+
+		entity, err := experimentEntityFromPage(page)
+		return &entity, err
+	}
+
+	return nil, errors.New("entity type is not supported: " + entityType)
+}
+
+// experimentEntityList returns the pages and blocks, with their experiments
+func experimentEntityList(ctx context.Context, store cmsstore.StoreInterface) ([]experimentEntity, error) {
+	entities := []experimentEntity{}
+
+	pages, err := store.PageList(ctx, cmsstore.PageQuery().
+		SetOrderBy(cmsstore.COLUMN_NAME).
+		SetSortOrder(cmsstore.SORT_ORDER_ASC))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, page := range pages {
+		// Invalid experiments are ignored, as if there was none
+		entity, _ := experimentEntityFromPage(page)
+		entities = append(entities, entity)
+	}
+
+	blocks, err := store.BlockList(ctx, cmsstore.BlockQuery().
+		SetOrderBy(cmsstore.COLUMN_NAME).
+		SetSortOrder(cmsstore.SORT_ORDER_ASC))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range blocks {
+		entity, _ := experimentEntityFromBlock(block)
+		entities = append(entities, entity)
+	}
+
+	return entities, nil
+}
+
+// save stores the experiment of the page or block, a nil experiment removes it
+func (entity *experimentEntity) save(ctx context.Context, store cmsstore.StoreInterface, experiment *cmsstore.Experiment) error {
+	if entity.Type == EntityTypeBlock {
+		if err := cmsstore.SetBlockExperiment(entity.block, experiment); err != nil {
+			return err
+		}
+
+		if err := store.BlockUpdate(ctx, entity.block); err != nil {
+			return err
+		}
+	} else {
+		if err := cmsstore.SetPageExperiment(entity.page, experiment); err != nil {
+			return err
+		}
+
+		if err := store.PageUpdate(ctx, entity.page); err != nil {
+			return err
+		}
+	}
+
+	entity.Experiment = experiment
+
+	return nil
+}
+
+// label returns the type and name of the page or block
+func (entity experimentEntity) label() string {
+	name := entity.Name
+	if name == "" {
+		name = entity.ID
+	}
+
+	if entity.Type == EntityTypeBlock {
+		return "Block: " + name
+	}
+
+	return "Page: " + name
+}
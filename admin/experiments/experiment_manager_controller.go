@@ -0,0 +1,183 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dracory/api"
+	"github.com/dracory/bs"
+	"github.com/dracory/cdn"
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/hb"
+	"github.com/samber/lo"
+)
+
+// == CONTROLLER ==============================================================
+
+type experimentManagerController struct {
+	ui UiInterface
+}
+
+// == CONSTRUCTOR =============================================================
+
+func NewExperimentManagerController(ui UiInterface) *experimentManagerController {
+	return &experimentManagerController{
+		ui: ui,
+	}
+}
+
+// Handler lists the pages and blocks with an experiment, and the form
+// starting a new experiment on a page or block
+func (controller *experimentManagerController) Handler(w http.ResponseWriter, r *http.Request) string {
+	data, errorMessage := controller.prepareData(r)
+
+	if errorMessage != "" {
+		return api.Error(errorMessage).ToString()
+	}
+
+	options := struct {
+		Styles     []string
+		StyleURLs  []string
+		Scripts    []string
+		ScriptURLs []string
+	}{
+		ScriptURLs: []string{
+			cdn.Htmx_2_0_0(),
+			cdn.Sweetalert2_11(),
+		},
+	}
+	return controller.ui.Layout(w, r, "Experiments | CMS", controller.page(data).ToHTML(), options)
+}
+
+func (controller *experimentManagerController) page(data experimentManagerControllerData) hb.TagInterface {
+	adminHeader := shared.AdminHeader(controller.ui.Store(), controller.ui.Logger(), data.request)
+
+	breadcrumbs := shared.AdminBreadcrumbs(data.request, []shared.Breadcrumb{
+		{
+			Name: "Experiments",
+			URL:  shared.URLR(data.request, shared.PathExperimentsExperimentManager, nil),
+		},
+	}, struct{ SiteList []cmsstore.SiteInterface }{
+		SiteList: []cmsstore.SiteInterface{},
+	})
+
+	title := hb.Heading1().
+		HTML("Experiments")
+
+	return hb.Div().
+		Class("container").
+		Child(breadcrumbs).
+		Child(hb.HR()).
+		Child(adminHeader).
+		Child(hb.HR()).
+		Child(title).
+		Child(controller.formNew(data)).
+		Child(controller.tableRecords(data))
+}
+
+// formNew renders the form starting a new experiment on a page or block
+func (controller *experimentManagerController) formNew(data experimentManagerControllerData) hb.TagInterface {
+	selectEntity := hb.Select().
+		Class("form-select").
+		Name("entity").
+		Child(hb.Option().Value("").Text("- select page or block -"))
+
+	for _, entity := range data.entityList {
+		if entity.Experiment == nil {
+			selectEntity.Child(hb.Option().Value(entity.Type + ":" + entity.ID).Text(entity.label()))
+		}
+	}
+
+	return bs.Card().Class("mt-3 mb-3").
+		Child(bs.CardBody().
+			Child(hb.Form().
+				Method(http.MethodGet).
+				Action(shared.URLR(data.request, shared.PathExperimentsExperimentUpdate, nil)).
+				// !!! Needed or it loses the path from the get submission
+				Child(hb.Input().Type(hb.TYPE_HIDDEN).Name("path").Value(shared.PathExperimentsExperimentUpdate)).
+				Child(hb.Div().Class("input-group").
+					Child(selectEntity).
+					Child(hb.Button().
+						Type(hb.TYPE_SUBMIT).
+						Class("btn btn-success").
+						Child(hb.I().Class("bi bi-plus-circle me-2")).
+						Text("New Experiment")))))
+}
+
+func (controller *experimentManagerController) tableRecords(data experimentManagerControllerData) hb.TagInterface {
+	experiments := lo.Filter(data.entityList, func(entity experimentEntity, _ int) bool {
+		return entity.Experiment != nil
+	})
+
+	if len(experiments) == 0 {
+		return hb.Div().Class("alert alert-info").Text("No experiments yet")
+	}
+
+	return hb.Table().
+		Class("table table-striped table-hover table-bordered").
+		Children([]hb.TagInterface{
+			hb.Thead().Children([]hb.TagInterface{
+				hb.TR().Children([]hb.TagInterface{
+					hb.TH().HTML("Experiment"),
+					hb.TH().HTML("Variants"),
+					hb.TH().HTML("Status").Style("width: 1px;"),
+					hb.TH().HTML("Started").Style("width: 1px;"),
+					hb.TH().HTML("Actions").Style("width: 1px;"),
+				}),
+			}),
+			hb.Tbody().Children(lo.Map(experiments, func(entity experimentEntity, _ int) hb.TagInterface {
+				updateURL := shared.URLR(data.request, shared.PathExperimentsExperimentUpdate, map[string]string{
+					"entity": entity.Type + ":" + entity.ID,
+				})
+
+				variants := lo.Map(entity.Experiment.Variants, func(variant cmsstore.ExperimentVariant, _ int) string {
+					return variant.Key + " (" + strconv.Itoa(variant.Weight) + ")"
+				})
+
+				status := hb.Span().
+					Style(`font-weight: bold;`).
+					StyleIf(entity.Experiment.IsRunning(), `color:green;`).
+					StyleIf(entity.Experiment.Status == cmsstore.EXPERIMENT_STATUS_STOPPED, `color:silver;`).
+					Text(entity.Experiment.Status)
+
+				buttonEdit := hb.Hyperlink().
+					Class("btn btn-primary").
+					Child(hb.I().Class("bi bi-pencil-square")).
+					Title("Edit").
+					Href(updateURL)
+
+				return hb.TR().Children([]hb.TagInterface{
+					hb.TD().
+						Child(hb.Div().Child(hb.Hyperlink().Text(entity.Experiment.Key).Href(updateURL))).
+						Child(hb.Div().
+							Style("font-size: 11px;").
+							Text(entity.label())),
+					hb.TD().Text(strings.Join(variants, ", ")),
+					hb.TD().Child(status),
+					hb.TD().Style("white-space: nowrap;").Text(entity.Experiment.StartedAt),
+					hb.TD().Child(buttonEdit),
+				})
+			})),
+		})
+}
+
+func (controller *experimentManagerController) prepareData(r *http.Request) (data experimentManagerControllerData, errorMessage string) {
+	data.request = r
+
+	var err error
+	data.entityList, err = experimentEntityList(r.Context(), controller.ui.Store())
+
+	if err != nil {
+		controller.ui.Logger().Error("At experimentManagerController > prepareData", "error", err.Error())
+		return data, "error retrieving experiments"
+	}
+
+	return data, ""
+}
+
+type experimentManagerControllerData struct {
+	request    *http.Request
+	entityList []experimentEntity
+}
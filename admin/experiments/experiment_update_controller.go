@@ -0,0 +1,368 @@
+package admin
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dracory/api"
+	"github.com/dracory/bs"
+	"github.com/dracory/cdn"
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+// Actions of the experiment form
+const (
+	ActionExperimentDelete = "delete"
+	ActionExperimentSave   = "save"
+	ActionExperimentStart  = "start"
+	ActionExperimentStop   = "stop"
+)
+
+// experimentBlankVariants is the number of blank variant rows,
+// for adding variants
+const experimentBlankVariants = 2
+
+// == CONTROLLER ==============================================================
+
+type experimentUpdateController struct {
+	ui UiInterface
+}
+
+// == CONSTRUCTOR =============================================================
+
+func NewExperimentUpdateController(ui UiInterface) *experimentUpdateController {
+	return &experimentUpdateController{
+		ui: ui,
+	}
+}
+
+// Handler shows the variants of the experiment of a page or block,
+// saving, starting, stopping or deleting the experiment on POST
+func (controller *experimentUpdateController) Handler(w http.ResponseWriter, r *http.Request) string {
+	data, errorMessage := controller.prepareDataAndValidate(r)
+
+	if errorMessage != "" {
+		return api.Error(errorMessage).ToString()
+	}
+
+	if data.formRedirectURL != "" {
+		return hb.Script(`window.location.href = "` + data.formRedirectURL + `";`).ToHTML()
+	}
+
+	options := struct {
+		Styles     []string
+		StyleURLs  []string
+		Scripts    []string
+		ScriptURLs []string
+	}{
+		ScriptURLs: []string{
+			cdn.Htmx_2_0_0(),
+			cdn.Sweetalert2_11(),
+		},
+	}
+	return controller.ui.Layout(w, r, "Edit Experiment | CMS", controller.page(data).ToHTML(), options)
+}
+
+func (controller *experimentUpdateController) page(data experimentUpdateControllerData) hb.TagInterface {
+	adminHeader := shared.AdminHeader(controller.ui.Store(), controller.ui.Logger(), data.request)
+
+	breadcrumbs := shared.AdminBreadcrumbs(data.request, []shared.Breadcrumb{
+		{
+			Name: "Experiments",
+			URL:  shared.URLR(data.request, shared.PathExperimentsExperimentManager, nil),
+		},
+		{
+			Name: "Edit Experiment",
+			URL:  shared.URLR(data.request, shared.PathExperimentsExperimentUpdate, map[string]string{"entity": data.entityParam}),
+		},
+	}, struct{ SiteList []cmsstore.SiteInterface }{
+		SiteList: []cmsstore.SiteInterface{},
+	})
+
+	buttonBack := hb.Hyperlink().
+		Class("btn btn-secondary float-end").
+		Child(hb.I().Class("bi bi-chevron-left me-2")).
+		Text("Back").
+		Href(shared.URLR(data.request, shared.PathExperimentsExperimentManager, nil))
+
+	title := hb.Heading1().
+		Text("Experiment").
+		Child(hb.Small().Class("text-muted ms-3 fs-5").Text(data.entity.label())).
+		Child(buttonBack)
+
+	alerts := hb.Div()
+
+	if data.formErrorMessage != "" {
+		alerts.Child(hb.Div().Class("alert alert-danger").Text(data.formErrorMessage))
+	}
+
+	if data.formSuccessMessage != "" {
+		alerts.Child(hb.Div().Class("alert alert-success").Text(data.formSuccessMessage))
+	}
+
+	return hb.Div().
+		Class("container").
+		Child(breadcrumbs).
+		Child(hb.HR()).
+		Child(adminHeader).
+		Child(hb.HR()).
+		Child(title).
+		Child(alerts).
+		Child(controller.form(data))
+}
+
+// form renders the experiment form, with a row per variant
+func (controller *experimentUpdateController) form(data experimentUpdateControllerData) hb.TagInterface {
+	status := cmsstore.EXPERIMENT_STATUS_DRAFT
+	details := []string{}
+
+	if data.entity.Experiment != nil {
+		status = data.entity.Experiment.Status
+
+		if data.entity.Experiment.StartedAt != "" {
+			details = append(details, "started "+data.entity.Experiment.StartedAt)
+		}
+
+		if data.entity.Experiment.StoppedAt != "" {
+			details = append(details, "stopped "+data.entity.Experiment.StoppedAt)
+		}
+	}
+
+	isRunning := status == cmsstore.EXPERIMENT_STATUS_RUNNING
+
+	statusLine := hb.Paragraph().
+		Text("Status: ").
+		Child(hb.Span().
+			Style(`font-weight: bold;`).
+			StyleIf(isRunning, `color:green;`).
+			StyleIf(status == cmsstore.EXPERIMENT_STATUS_STOPPED, `color:silver;`).
+			Text(status)).
+		TextIf(len(details) > 0, " ("+strings.Join(details, ", ")+")")
+
+	fieldKey := hb.Div().Class("mb-3").
+		Child(hb.Label().Class("form-label").Text("Experiment Key")).
+		Child(hb.Input().
+			Class("form-control").
+			Name("experiment_key").
+			Value(data.formKey)).
+		Child(hb.Div().Class("form-text").
+			Text("Lowercase letters, digits and underscores, i.e. homepage_hero. The variant assigned to the visitor is available as the [[" + cmsstore.EXPERIMENT_VARIABLE_PREFIX + lo.Ternary(data.formKey == "", "key", data.formKey) + "]] variable, i.e. for the analytics."))
+
+	variantsTable := hb.Table().
+		Class("table table-bordered").
+		Child(hb.Thead().Child(hb.TR().
+			Child(hb.TH().Text("Variant Key").Style("width: 200px;")).
+			Child(hb.TH().Text("Content")).
+			Child(hb.TH().Text("Weight").Style("width: 150px;"))))
+
+	tbody := hb.Tbody()
+	for _, variant := range data.formVariants {
+		tbody.Child(controller.variantRow(data, variant))
+	}
+	for range experimentBlankVariants {
+		tbody.Child(controller.variantRow(data, cmsstore.ExperimentVariant{}))
+	}
+	variantsTable.Child(tbody)
+
+	button := func(action, label, icon, class string) hb.TagInterface {
+		return hb.Button().
+			Type(hb.TYPE_SUBMIT).
+			Class("btn " + class + " me-2").
+			Name("action").
+			Value(action).
+			Child(hb.I().Class("bi " + icon + " me-2")).
+			Text(label)
+	}
+
+	buttons := hb.Div().
+		Child(button(ActionExperimentSave, "Save", "bi-save", "btn-primary")).
+		ChildIf(!isRunning, button(ActionExperimentStart, "Start", "bi-play-fill", "btn-success")).
+		ChildIf(isRunning, button(ActionExperimentStop, "Stop", "bi-stop-fill", "btn-warning")).
+		ChildIf(data.entity.Experiment != nil, button(ActionExperimentDelete, "Delete", "bi-trash", "btn-danger float-end"))
+
+	return bs.Card().Class("mt-3").
+		Child(bs.CardBody().
+			Child(hb.Form().
+				Method(http.MethodPost).
+				Action(shared.URLR(data.request, shared.PathExperimentsExperimentUpdate, map[string]string{
+					"entity": data.entityParam,
+				})).
+				Child(statusLine).
+				Child(fieldKey).
+				Child(hb.Label().Class("form-label").Text("Variants")).
+				Child(variantsTable).
+				Child(hb.Div().Class("form-text mb-3").
+					Text("Each visitor is assigned a variant by weight, i.e. 50 and 50 for an even split, and keeps it. The variants are displayed while the experiment is running, the original otherwise. Leave the key empty to remove a variant.")).
+				Child(buttons)))
+}
+
+// variantRow renders the inputs of a variant
+func (controller *experimentUpdateController) variantRow(data experimentUpdateControllerData, variant cmsstore.ExperimentVariant) hb.TagInterface {
+	selectContent := hb.Select().
+		Class("form-select").
+		Name("variant_content_id[]").
+		Child(hb.Option().Value("").Text("Original " + data.entity.Type))
+
+	for _, candidate := range data.candidateList {
+		selectContent.Child(hb.Option().
+			Value(candidate.ID).
+			Text(candidate.label()).
+			AttrIf(candidate.ID == variant.ContentID, "selected", "selected"))
+	}
+
+	weight := ""
+	if variant.Key != "" {
+		weight = cast.ToString(variant.Weight)
+	}
+
+	return hb.TR().
+		Child(hb.TD().Child(hb.Input().Class("form-control").Name("variant_key[]").Value(variant.Key))).
+		Child(hb.TD().Child(selectContent)).
+		Child(hb.TD().Child(hb.Input().Class("form-control").Type(hb.TYPE_NUMBER).Attr("min", "0").Name("variant_weight[]").Value(weight)))
+}
+
+func (controller *experimentUpdateController) prepareDataAndValidate(r *http.Request) (data experimentUpdateControllerData, errorMessage string) {
+	data.request = r
+	data.entityParam = req.GetStringTrimmed(r, "entity")
+
+	entityType, entityID, _ := strings.Cut(data.entityParam, ":")
+
+	if entityID == "" {
+		return data, "page or block is required"
+	}
+
+	entity, err := experimentEntityFind(r.Context(), controller.ui.Store(), entityType, entityID)
+
+	if err != nil {
+		controller.ui.Logger().Error("At experimentUpdateController > prepareDataAndValidate", "error", err.Error())
+		return data, "error retrieving " + entityType
+	}
+
+	if entity == nil {
+		return data, entityType + " not found"
+	}
+
+	data.entity = entity
+
+	entityList, err := experimentEntityList(r.Context(), controller.ui.Store())
+
+	if err != nil {
+		controller.ui.Logger().Error("At experimentUpdateController > prepareDataAndValidate", "error", err.Error())
+		return data, "error retrieving variants"
+	}
+
+	// The variants are pages (blocks) of the same site
+	data.candidateList = lo.Filter(entityList, func(candidate experimentEntity, _ int) bool {
+		return candidate.Type == entity.Type && candidate.SiteID == entity.SiteID && candidate.ID != entity.ID
+	})
+
+	if entity.Experiment != nil {
+		data.formKey = entity.Experiment.Key
+		data.formVariants = entity.Experiment.Variants
+	} else {
+		data.formVariants = []cmsstore.ExperimentVariant{
+			{Key: "a", Weight: 50},
+			{Key: "b", Weight: 50},
+		}
+	}
+
+	if r.Method != http.MethodPost {
+		return data, ""
+	}
+
+	return controller.save(r, data)
+}
+
+// save applies the posted action to the experiment
+func (controller *experimentUpdateController) save(r *http.Request, data experimentUpdateControllerData) (experimentUpdateControllerData, string) {
+	action := req.GetStringTrimmed(r, "action")
+
+	if action == ActionExperimentDelete {
+		if err := data.entity.save(r.Context(), controller.ui.Store(), nil); err != nil {
+			controller.ui.Logger().Error("At experimentUpdateController > save", "error", err.Error())
+			return data, "error deleting experiment"
+		}
+
+		data.formRedirectURL = shared.URLR(r, shared.PathExperimentsExperimentManager, nil)
+		return data, ""
+	}
+
+	data.formKey = req.GetStringTrimmed(r, "experiment_key")
+	data.formVariants = []cmsstore.ExperimentVariant{}
+
+	keys := r.PostForm["variant_key[]"]
+	contentIDs := r.PostForm["variant_content_id[]"]
+	weights := r.PostForm["variant_weight[]"]
+
+	for i, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+
+		variant := cmsstore.ExperimentVariant{Key: key}
+
+		if i < len(contentIDs) {
+			variant.ContentID = strings.TrimSpace(contentIDs[i])
+		}
+
+		if i < len(weights) {
+			variant.Weight = cast.ToInt(strings.TrimSpace(weights[i]))
+		}
+
+		data.formVariants = append(data.formVariants, variant)
+	}
+
+	experiment := &cmsstore.Experiment{
+		Key:      data.formKey,
+		Status:   cmsstore.EXPERIMENT_STATUS_DRAFT,
+		Variants: data.formVariants,
+	}
+
+	if data.entity.Experiment != nil {
+		experiment.Status = data.entity.Experiment.Status
+		experiment.StartedAt = data.entity.Experiment.StartedAt
+		experiment.StoppedAt = data.entity.Experiment.StoppedAt
+	}
+
+	switch action {
+	case ActionExperimentStart:
+		experiment.Start()
+	case ActionExperimentStop:
+		experiment.Stop()
+	}
+
+	if err := data.entity.save(r.Context(), controller.ui.Store(), experiment); err != nil {
+		data.formErrorMessage = err.Error()
+		return data, ""
+	}
+
+	data.formSuccessMessage = map[string]string{
+		ActionExperimentStart: "experiment started successfully",
+		ActionExperimentStop:  "experiment stopped successfully",
+	}[action]
+
+	if data.formSuccessMessage == "" {
+		data.formSuccessMessage = "experiment saved successfully"
+	}
+
+	return data, ""
+}
+
+type experimentUpdateControllerData struct {
+	request       *http.Request
+	entityParam   string
+	entity        *experimentEntity
+	candidateList []experimentEntity
+
+	formErrorMessage   string
+	formRedirectURL    string
+	formSuccessMessage string
+	formKey            string
+	formVariants       []cmsstore.ExperimentVariant
+}
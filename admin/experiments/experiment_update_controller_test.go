@@ -0,0 +1,195 @@
+package admin
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/cmsstore/testutils"
+	"github.com/dracory/test"
+	_ "modernc.org/sqlite"
+)
+
+func initExperimentUI(store cmsstore.StoreInterface) UiInterface {
+	return UI(shared.UiConfig{
+		Layout: shared.Layout,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Store:  store,
+	})
+}
+
+// seedExperimentBlocks creates a block and a variant block
+func seedExperimentBlocks(t *testing.T, store cmsstore.StoreInterface) (cmsstore.BlockInterface, cmsstore.BlockInterface) {
+	t.Helper()
+
+	blocks := []cmsstore.BlockInterface{}
+
+	for _, name := range []string{"Call To Action", "Call To Action B"} {
+		block := cmsstore.NewBlock().
+			SetSiteID(testutils.SITE_01).
+			SetName(name).
+			SetType(cmsstore.BLOCK_TYPE_HTML).
+			SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+		if err := store.BlockCreate(context.Background(), block); err != nil {
+			t.Fatalf("Failed to create block: %v", err)
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	return blocks[0], blocks[1]
+}
+
+func postExperiment(t *testing.T, ui UiInterface, block cmsstore.BlockInterface, action string, values url.Values) string {
+	t.Helper()
+
+	values.Set("action", action)
+
+	body, response, err := test.CallStringEndpoint(http.MethodPost, NewExperimentUpdateController(ui).Handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"entity": {EntityTypeBlock + ":" + block.ID()},
+		},
+		PostValues: values,
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", response.StatusCode)
+	}
+
+	return body
+}
+
+func TestExperimentUpdateController_SaveStartStop(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	block, variantBlock := seedExperimentBlocks(t, store)
+	ui := initExperimentUI(store)
+
+	body := postExperiment(t, ui, block, ActionExperimentSave, url.Values{
+		"experiment_key":       {"cta"},
+		"variant_key[]":        {"control", "b", ""},
+		"variant_content_id[]": {"", variantBlock.ID(), ""},
+		"variant_weight[]":     {"70", "30", ""},
+	})
+
+	if !strings.Contains(body, "experiment saved successfully") {
+		t.Fatalf("Expected success message, got %s", body)
+	}
+
+	assertExperiment := func(expectedStatus string) *cmsstore.Experiment {
+		t.Helper()
+
+		saved, err := store.BlockFindByID(context.Background(), block.ID())
+		if err != nil {
+			t.Fatalf("Failed to find block: %v", err)
+		}
+
+		experiment, err := cmsstore.BlockExperiment(saved)
+		if err != nil || experiment == nil {
+			t.Fatalf("Expected the experiment to be saved, got %v %v", experiment, err)
+		}
+
+		if experiment.Status != expectedStatus {
+			t.Fatalf("Expected status %s, got %s", expectedStatus, experiment.Status)
+		}
+
+		return experiment
+	}
+
+	experiment := assertExperiment(cmsstore.EXPERIMENT_STATUS_DRAFT)
+
+	if len(experiment.Variants) != 2 || experiment.Variants[1].ContentID != variantBlock.ID() || experiment.Variants[0].Weight != 70 {
+		t.Fatalf("Unexpected variants %v", experiment.Variants)
+	}
+
+	postExperiment(t, ui, block, ActionExperimentStart, url.Values{
+		"experiment_key":       {"cta"},
+		"variant_key[]":        {"control", "b"},
+		"variant_content_id[]": {"", variantBlock.ID()},
+		"variant_weight[]":     {"70", "30"},
+	})
+
+	if experiment := assertExperiment(cmsstore.EXPERIMENT_STATUS_RUNNING); experiment.StartedAt == "" {
+		t.Fatal("Expected the start date to be set")
+	}
+
+	postExperiment(t, ui, block, ActionExperimentStop, url.Values{
+		"experiment_key":       {"cta"},
+		"variant_key[]":        {"control", "b"},
+		"variant_content_id[]": {"", variantBlock.ID()},
+		"variant_weight[]":     {"70", "30"},
+	})
+
+	if experiment := assertExperiment(cmsstore.EXPERIMENT_STATUS_STOPPED); experiment.StartedAt == "" || experiment.StoppedAt == "" {
+		t.Fatal("Expected the start and stop dates to be kept")
+	}
+}
+
+func TestExperimentUpdateController_InvalidExperiment(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	block, _ := seedExperimentBlocks(t, store)
+
+	body := postExperiment(t, initExperimentUI(store), block, ActionExperimentSave, url.Values{
+		"experiment_key":       {"Invalid Key"},
+		"variant_key[]":        {"a"},
+		"variant_content_id[]": {""},
+		"variant_weight[]":     {"100"},
+	})
+
+	if !strings.Contains(body, "alert-danger") {
+		t.Fatalf("Expected validation error, got %s", body)
+	}
+
+	saved, _ := store.BlockFindByID(context.Background(), block.ID())
+
+	if experiment, _ := cmsstore.BlockExperiment(saved); experiment != nil {
+		t.Errorf("Expected the invalid experiment not to be saved, got %v", experiment)
+	}
+}
+
+func TestExperimentManagerController_ListsExperiments(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	block, variantBlock := seedExperimentBlocks(t, store)
+	ui := initExperimentUI(store)
+
+	postExperiment(t, ui, block, ActionExperimentSave, url.Values{
+		"experiment_key":       {"cta"},
+		"variant_key[]":        {"control", "b"},
+		"variant_content_id[]": {"", variantBlock.ID()},
+		"variant_weight[]":     {"50", "50"},
+	})
+
+	body, _, err := test.CallStringEndpoint(http.MethodGet, NewExperimentManagerController(ui).Handler, test.NewRequestOptions{})
+
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	for _, expected := range []string{"cta", "Block: Call To Action", "control (50), b (50)", EntityTypeBlock + ":" + variantBlock.ID()} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected %q in %s", expected, body)
+		}
+	}
+}
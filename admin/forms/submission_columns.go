@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/blocks/forms"
+)
+
+// submissionColumn is a column of the submission values,
+// a field of the form or a value no longer in the form
+type submissionColumn struct {
+	Name  string
+	Label string
+}
+
+// submissionColumns returns the columns of the submission values, the
+// fields of the form in their order, followed by the other submitted
+// values (i.e. of fields since removed from the form) sorted by name
+func submissionColumns(block cmsstore.BlockInterface, submissions []cmsstore.FormSubmissionInterface) []submissionColumn {
+	columns := []submissionColumn{}
+	names := map[string]bool{}
+
+	for _, field := range forms.Fields(block) {
+		columns = append(columns, submissionColumn{Name: field.Name, Label: field.Label})
+		names[field.Name] = true
+	}
+
+	extra := []string{}
+	for _, submission := range submissions {
+		values, err := submission.FormData()
+		if err != nil {
+			continue
+		}
+
+		for name := range values {
+			if !names[name] {
+				names[name] = true
+				extra = append(extra, name)
+			}
+		}
+	}
+
+	sort.Strings(extra)
+
+	for _, name := range extra {
+		columns = append(columns, submissionColumn{Name: name, Label: name})
+	}
+
+	return columns
+}
+
+// formBlockList returns the form blocks, sorted by name
+func formBlockList(ctx context.Context, store cmsstore.StoreInterface) ([]cmsstore.BlockInterface, error) {
+	blocks, err := store.BlockList(ctx, cmsstore.BlockQuery().
+		SetType(cmsstore.BLOCK_TYPE_FORM).
+		SetOrderBy(cmsstore.COLUMN_NAME).
+		SetSortOrder(cmsstore.SORT_ORDER_ASC))
+
+	if err != nil {
+		return []cmsstore.BlockInterface{}, err
+	}
+
+	return blocks, nil
+}
+
+// formBlockName returns the name of the form block, the ID if unnamed
+func formBlockName(blocks []cmsstore.BlockInterface, blockID string) string {
+	for _, block := range blocks {
+		if block.ID() == blockID {
+			if block.Name() != "" {
+				return block.Name()
+			}
+			break
+		}
+	}
+
+	return blockID
+}
+
+// findFormBlock returns the form block by ID, nil if not found
+func findFormBlock(blocks []cmsstore.BlockInterface, blockID string) cmsstore.BlockInterface {
+	for _, block := range blocks {
+		if block.ID() == blockID {
+			return block
+		}
+	}
+
+	return nil
+}
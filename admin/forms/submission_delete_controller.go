@@ -0,0 +1,169 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/dracory/bs"
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+)
+
+// == CONTROLLER ==============================================================
+
+type submissionDeleteController struct {
+	ui UiInterface
+}
+
+// == CONSTRUCTOR =============================================================
+
+type submissionDeleteControllerData struct {
+	request        *http.Request
+	submissionID   string
+	submission     cmsstore.FormSubmissionInterface
+	successMessage string
+}
+
+func NewSubmissionDeleteController(ui UiInterface) *submissionDeleteController {
+	return &submissionDeleteController{
+		ui: ui,
+	}
+}
+
+func (controller submissionDeleteController) Handler(w http.ResponseWriter, r *http.Request) string {
+	data, errorMessage := controller.prepareDataAndValidate(r)
+
+	if errorMessage != "" {
+		return hb.Swal(hb.SwalOptions{
+			Icon: "error",
+			Text: errorMessage,
+		}).ToHTML()
+	}
+
+	if data.successMessage != "" {
+		redirectURL := shared.URLR(data.request, shared.PathFormsSubmissionManager, map[string]string{
+			"filter_form_id": data.submission.BlockID(),
+		})
+
+		return hb.Wrap().
+			Child(hb.Swal(hb.SwalOptions{
+				Icon: "success",
+				Text: data.successMessage,
+			})).
+			Child(hb.Script("setTimeout(() => {window.location.href = '" + redirectURL + "'}, 2000)")).
+			ToHTML()
+	}
+
+	return controller.
+		modal(data).
+		ToHTML()
+}
+
+func (controller *submissionDeleteController) modal(data submissionDeleteControllerData) hb.TagInterface {
+	submitUrl := shared.URLR(data.request, shared.PathFormsSubmissionDelete, map[string]string{
+		"submission_id": data.submissionID,
+	})
+
+	modalID := "ModalSubmissionDelete"
+	modalBackdropClass := "ModalBackdrop"
+
+	formGroupSubmissionId := hb.Input().
+		Type(hb.TYPE_HIDDEN).
+		Name("submission_id").
+		Value(data.submissionID)
+
+	buttonDelete := hb.Button().
+		HTML("Delete").
+		Class("btn btn-primary float-end").
+		HxInclude("#Modal" + modalID).
+		HxPost(submitUrl).
+		HxSelectOob("#ModalSubmissionDelete").
+		HxTarget("body").
+		HxSwap("beforeend")
+
+	modalCloseScript := `closeModal` + modalID + `();`
+
+	modalHeading := hb.Heading5().HTML("Delete Form Submission").Style(`margin:0px;`)
+
+	modalClose := hb.Button().Type("button").
+		Class("btn-close").
+		Data("bs-dismiss", "modal").
+		OnClick(modalCloseScript)
+
+	jsCloseFn := `function closeModal` + modalID + `() {document.getElementById('ModalSubmissionDelete').remove();[...document.getElementsByClassName('` + modalBackdropClass + `')].forEach(el => el.remove());}`
+
+	modal := bs.Modal().
+		ID(modalID).
+		Class("fade show").
+		Style(`display:block;position:fixed;top:50%;left:50%;transform:translate(-50%,-50%);z-index:1051;`).
+		Child(hb.Script(jsCloseFn)).
+		Child(bs.ModalDialog().
+			Child(bs.ModalContent().
+				Child(
+					bs.ModalHeader().
+						Child(modalHeading).
+						Child(modalClose)).
+				Child(
+					bs.ModalBody().
+						Child(hb.Paragraph().Text("Are you sure you want to delete this form submission?").Style(`margin-bottom:20px;color:red;`)).
+						Child(hb.Paragraph().Text("This action cannot be undone.")).
+						Child(formGroupSubmissionId)).
+				Child(bs.ModalFooter().
+					Style(`display:flex;justify-content:space-between;`).
+					Child(
+						hb.Button().HTML("Close").
+							Class("btn btn-secondary float-start").
+							Data("bs-dismiss", "modal").
+							OnClick(modalCloseScript)).
+					Child(buttonDelete)),
+			))
+
+	backdrop := hb.Div().Class(modalBackdropClass).
+		Class("modal-backdrop fade show").
+		Style("display:block;z-index:1000;")
+
+	return hb.Wrap().
+		Children([]hb.TagInterface{
+			modal,
+			backdrop,
+		})
+}
+
+func (controller *submissionDeleteController) prepareDataAndValidate(r *http.Request) (data submissionDeleteControllerData, errorMessage string) {
+	data.request = r
+	data.submissionID = req.GetString(r, "submission_id")
+
+	if data.submissionID == "" {
+		return data, "submission id is required"
+	}
+
+	submission, err := controller.ui.Store().FormSubmissionFindByID(r.Context(), data.submissionID)
+
+	if err != nil {
+		controller.ui.Logger().Error("Error. At submissionDeleteController > prepareDataAndValidate", "error", err.Error())
+		return data, err.Error()
+	}
+
+	if submission == nil {
+		return data, "Form submission not found"
+	}
+
+	data.submission = submission
+
+	if r.Method != "POST" {
+		return data, ""
+	}
+
+	err = controller.ui.Store().FormSubmissionSoftDelete(r.Context(), submission)
+
+	if err != nil {
+		controller.ui.Logger().Error("Error. At submissionDeleteController > prepareDataAndValidate", "error", err.Error())
+		return data, err.Error()
+	}
+
+	data.successMessage = "form submission deleted successfully."
+
+	return data, ""
+
+}
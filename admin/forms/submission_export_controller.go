@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"strings"
+
+	"github.com/dracory/api"
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/req"
+	"github.com/dromara/carbon/v2"
+)
+
+// == CONTROLLER ==============================================================
+
+type submissionExportController struct {
+	ui UiInterface
+}
+
+// == CONSTRUCTOR =============================================================
+
+func NewSubmissionExportController(ui UiInterface) *submissionExportController {
+	return &submissionExportController{
+		ui: ui,
+	}
+}
+
+// Handler exports the submissions matching the filters of the submission
+// manager as a CSV file, the values in the order of the fields of the form
+func (controller *submissionExportController) Handler(w http.ResponseWriter, r *http.Request) string {
+	formID := req.GetStringTrimmed(r, "filter_form_id")
+	siteID := req.GetStringTrimmed(r, "filter_site_id")
+	status := req.GetStringTrimmed(r, "filter_status")
+
+	if !controller.ui.Store().FormsEnabled() {
+		return api.Error("forms are not enabled").ToString()
+	}
+
+	formList, err := formBlockList(r.Context(), controller.ui.Store())
+
+	if err != nil {
+		controller.ui.Logger().Error("At submissionExportController > Handler", "error", err.Error())
+		return api.Error("error retrieving forms").ToString()
+	}
+
+	submissions, err := controller.ui.Store().FormSubmissionList(r.Context(), submissionListQuery(formID, siteID, status))
+
+	if err != nil {
+		controller.ui.Logger().Error("At submissionExportController > Handler", "error", err.Error())
+		return api.Error("error retrieving form submissions").ToString()
+	}
+
+	content, err := submissionsCSV(formList, findFormBlock(formList, formID), submissions)
+
+	if err != nil {
+		controller.ui.Logger().Error("At submissionExportController > Handler", "error", err.Error())
+		return api.Error("error exporting form submissions").ToString()
+	}
+
+	fileName := "form_submissions_" + carbon.Now(carbon.UTC).Format("Ymd_His") + ".csv"
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+fileName+`"`)
+
+	return content
+}
+
+// submissionsCSV returns the submissions as CSV, with a header row
+func submissionsCSV(formList []cmsstore.BlockInterface, block cmsstore.BlockInterface, submissions []cmsstore.FormSubmissionInterface) (string, error) {
+	columns := submissionColumns(block, submissions)
+
+	header := []string{"ID", "Form", "Status", "Submitted"}
+	for _, column := range columns {
+		header = append(header, column.Label)
+	}
+	header = append(header, "Page", "IP Address")
+
+	buffer := bytes.Buffer{}
+	writer := csv.NewWriter(&buffer)
+
+	if err := writer.Write(header); err != nil {
+		return "", err
+	}
+
+	for _, submission := range submissions {
+		row := []string{
+			submission.ID(),
+			formBlockName(formList, submission.BlockID()),
+			submission.Status(),
+			submission.CreatedAt(),
+		}
+
+		for _, column := range columns {
+			row = append(row, csvSafe(submission.FormValue(column.Name)))
+		}
+
+		row = append(row, csvSafe(submission.SourceURL()), submission.IPAddress())
+
+		if err := writer.Write(row); err != nil {
+			return "", err
+		}
+	}
+
+	writer.Flush()
+
+	return buffer.String(), writer.Error()
+}
+
+// csvSafe prevents the submitted value from being run as a formula,
+// when the file is opened in a spreadsheet (CSV injection)
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
@@ -0,0 +1,352 @@
+package admin
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dracory/api"
+	"github.com/dracory/bs"
+	"github.com/dracory/cdn"
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+// == CONTROLLER ==============================================================
+
+type submissionManagerController struct {
+	ui UiInterface
+}
+
+// == CONSTRUCTOR =============================================================
+
+func NewSubmissionManagerController(ui UiInterface) *submissionManagerController {
+	return &submissionManagerController{
+		ui: ui,
+	}
+}
+
+func (controller *submissionManagerController) Handler(w http.ResponseWriter, r *http.Request) string {
+	data, errorMessage := controller.prepareData(r)
+
+	if errorMessage != "" {
+		return api.Error(errorMessage).ToString()
+	}
+
+	options := struct {
+		Styles     []string
+		StyleURLs  []string
+		Scripts    []string
+		ScriptURLs []string
+	}{
+		ScriptURLs: []string{
+			cdn.Htmx_2_0_0(),
+			cdn.Sweetalert2_11(),
+		},
+	}
+	return controller.ui.Layout(w, r, "Form Submissions | CMS", controller.page(data).ToHTML(), options)
+}
+
+func (controller *submissionManagerController) page(data submissionManagerControllerData) hb.TagInterface {
+	adminHeader := shared.AdminHeader(controller.ui.Store(), controller.ui.Logger(), data.request)
+
+	breadcrumbs := shared.AdminBreadcrumbs(data.request, []shared.Breadcrumb{
+		{
+			Name: "Form Submissions",
+			URL:  shared.URLR(data.request, shared.PathFormsSubmissionManager, nil),
+		},
+	}, struct{ SiteList []cmsstore.SiteInterface }{
+		SiteList: data.siteList,
+	})
+
+	buttonExport := hb.Hyperlink().
+		Class("btn btn-success float-end").
+		Child(hb.I().Class("bi bi-download").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
+		HTML("Export CSV").
+		Href(shared.URLR(data.request, shared.PathFormsSubmissionExport, map[string]string{
+			"filter_form_id": data.formFormID,
+			"filter_site_id": data.formSiteID,
+			"filter_status":  data.formStatus,
+		}))
+
+	title := hb.Heading1().
+		HTML("Form Submissions").
+		Child(buttonExport)
+
+	return hb.Div().
+		Class("container").
+		Child(breadcrumbs).
+		Child(hb.HR()).
+		Child(adminHeader).
+		Child(hb.HR()).
+		Child(title).
+		Child(controller.tableFilter(data)).
+		Child(controller.tableRecords(data)).
+		Child(controller.tablePagination(data, int(data.recordCount), data.pageInt, data.perPage))
+}
+
+func (controller *submissionManagerController) tableRecords(data submissionManagerControllerData) hb.TagInterface {
+	return hb.Table().
+		Class("table table-striped table-hover table-bordered").
+		Children([]hb.TagInterface{
+			hb.Thead().Children([]hb.TagInterface{
+				hb.TR().Children([]hb.TagInterface{
+					hb.TH().HTML("Submission"),
+					hb.TH().HTML("Form").Style("width: 200px;"),
+					hb.TH().HTML("Status").Style("width: 1px;"),
+					hb.TH().HTML("Submitted").Style("width: 1px;"),
+					hb.TH().HTML("Actions").Style("width: 1px;"),
+				}),
+			}),
+			hb.Tbody().Children(lo.Map(data.recordList, func(submission cmsstore.FormSubmissionInterface, _ int) hb.TagInterface {
+				viewURL := shared.URLR(data.request, shared.PathFormsSubmissionView, map[string]string{
+					"submission_id": submission.ID(),
+				})
+
+				summary := hb.Hyperlink().
+					Text(controller.summary(data, submission)).
+					Href(viewURL).
+					StyleIf(submission.IsNew(), "font-weight: bold;")
+
+				status := hb.Span().
+					Style(`font-weight: bold;`).
+					StyleIf(submission.IsNew(), `color:green;`).
+					StyleIf(submission.IsArchived(), `color:silver;`).
+					Text(submission.Status())
+
+				buttonView := hb.Hyperlink().
+					Class("btn btn-primary me-2").
+					Child(hb.I().Class("bi bi-eye")).
+					Title("View").
+					Href(viewURL)
+
+				buttonDelete := hb.Hyperlink().
+					Class("btn btn-danger").
+					Child(hb.I().Class("bi bi-trash")).
+					Title("Delete").
+					HxGet(shared.URLR(data.request, shared.PathFormsSubmissionDelete, map[string]string{
+						"submission_id": submission.ID(),
+					})).
+					HxTarget("body").
+					HxSwap("beforeend")
+
+				return hb.TR().Children([]hb.TagInterface{
+					hb.TD().
+						Child(hb.Div().Child(summary)).
+						Child(hb.Div().
+							Style("font-size: 11px;").
+							Text("Ref: " + submission.ID())),
+					hb.TD().
+						Text(formBlockName(data.formList, submission.BlockID())),
+					hb.TD().
+						Child(status),
+					hb.TD().
+						Child(hb.Div().
+							Style("font-size: 13px;white-space: nowrap;").
+							Text(submission.CreatedAtCarbon().Format("d M Y H:i"))),
+					hb.TD().
+						Style("white-space: nowrap;").
+						Child(buttonView).
+						Child(buttonDelete),
+				})
+			})),
+		})
+}
+
+// summary returns the first values of the submission, as a preview
+func (controller *submissionManagerController) summary(data submissionManagerControllerData, submission cmsstore.FormSubmissionInterface) string {
+	block := findFormBlock(data.formList, submission.BlockID())
+	columns := submissionColumns(block, []cmsstore.FormSubmissionInterface{submission})
+
+	values := []string{}
+	for _, column := range columns {
+		if value := submission.FormValue(column.Name); value != "" {
+			values = append(values, value)
+		}
+
+		if len(values) == 3 {
+			break
+		}
+	}
+
+	summary := strings.Join(values, " | ")
+	if len([]rune(summary)) > 100 {
+		summary = string([]rune(summary)[:100]) + "..."
+	}
+
+	return lo.Ternary(summary == "", "(empty)", summary)
+}
+
+func (controller *submissionManagerController) tableFilter(data submissionManagerControllerData) hb.TagInterface {
+	formSelect := hb.Select().
+		Class("form-select form-select-sm").
+		Name("filter_form_id").
+		Child(hb.Option().Value("").Text("All forms"))
+
+	for _, block := range data.formList {
+		formSelect.Child(hb.Option().
+			Value(block.ID()).
+			Text(formBlockName(data.formList, block.ID())).
+			AttrIf(block.ID() == data.formFormID, "selected", "selected"))
+	}
+
+	statusSelect := hb.Select().
+		Class("form-select form-select-sm").
+		Name("filter_status").
+		Child(hb.Option().Value("").Text("Any status"))
+
+	for _, status := range []string{cmsstore.FORM_SUBMISSION_STATUS_NEW, cmsstore.FORM_SUBMISSION_STATUS_READ, cmsstore.FORM_SUBMISSION_STATUS_ARCHIVED} {
+		statusSelect.Child(hb.Option().
+			Value(status).
+			Text(status).
+			AttrIf(status == data.formStatus, "selected", "selected"))
+	}
+
+	filterForm := hb.Form().
+		Method(http.MethodGet).
+		Action(shared.URLR(data.request, shared.PathFormsSubmissionManager, nil)).
+		Class("row g-2 align-items-center").
+		// !!! Needed or it loses the path from the get submission
+		Child(hb.Input().Type(hb.TYPE_HIDDEN).Name("path").Value(shared.PathFormsSubmissionManager)).
+		Child(hb.Input().Type(hb.TYPE_HIDDEN).Name("filter_site_id").Value(data.formSiteID)).
+		Child(hb.Div().Class("col-auto").Child(formSelect)).
+		Child(hb.Div().Class("col-auto").Child(statusSelect)).
+		Child(hb.Div().Class("col-auto").Child(hb.Button().
+			Type(hb.TYPE_SUBMIT).
+			Class("btn btn-sm btn-info text-white").
+			Child(hb.I().Class("bi bi-filter me-2")).
+			Text("Filter")))
+
+	description := hb.Span().Text("Showing " + cast.ToString(data.recordCount) + " submissions")
+
+	if data.formSiteID != "" {
+		description = hb.Span().
+			Child(description).
+			Text(" ").
+			Child(shared.FilterDescriptionSite(data.request.Context(), controller.ui.Store(), data.formSiteID))
+	}
+
+	return hb.Div().
+		Class("card bg-light mb-3").
+		Child(hb.Div().Class("card-body").
+			Child(filterForm).
+			Child(hb.Div().Class("mt-2").Child(description)))
+}
+
+func (controller *submissionManagerController) tablePagination(data submissionManagerControllerData, count int, page int, perPage int) hb.TagInterface {
+	url := shared.URLR(data.request, shared.PathFormsSubmissionManager, map[string]string{
+		"filter_form_id": data.formFormID,
+		"filter_site_id": data.formSiteID,
+		"filter_status":  data.formStatus,
+	})
+
+	url = lo.Ternary(strings.Contains(url, "?"), url+"&page=", url+"?page=") // page must be last
+
+	pagination := bs.Pagination(bs.PaginationOptions{
+		NumberItems:       count,
+		CurrentPageNumber: page,
+		PagesToShow:       5,
+		PerPage:           perPage,
+		URL:               url,
+	})
+
+	return hb.Div().
+		Class(`d-flex justify-content-left mt-5 pagination-primary-soft rounded mb-0`).
+		HTML(pagination)
+}
+
+func (controller *submissionManagerController) prepareData(r *http.Request) (data submissionManagerControllerData, errorMessage string) {
+	var err error
+	initialPerPage := 20
+	data.request = r
+	data.page = req.GetStringTrimmedOr(r, "page", "0")
+	data.pageInt = cast.ToInt(data.page)
+	data.perPage = cast.ToInt(req.GetStringTrimmedOr(r, "per_page", cast.ToString(initialPerPage)))
+
+	data.formFormID = req.GetStringTrimmed(r, "filter_form_id")
+	data.formSiteID = req.GetStringTrimmed(r, "filter_site_id")
+	data.formStatus = req.GetStringTrimmed(r, "filter_status")
+
+	if !controller.ui.Store().FormsEnabled() {
+		return data, "forms are not enabled"
+	}
+
+	data.formList, err = formBlockList(r.Context(), controller.ui.Store())
+
+	if err != nil {
+		controller.ui.Logger().Error("At submissionManagerController > prepareData", "error", err.Error())
+		return data, "error retrieving forms"
+	}
+
+	query := submissionListQuery(data.formFormID, data.formSiteID, data.formStatus)
+
+	data.recordList, err = controller.ui.Store().FormSubmissionList(r.Context(), query.
+		SetLimit(data.perPage).
+		SetOffset(data.pageInt*data.perPage))
+
+	if err != nil {
+		controller.ui.Logger().Error("At submissionManagerController > prepareData", "error", err.Error())
+		return data, "error retrieving form submissions"
+	}
+
+	data.recordCount, err = controller.ui.Store().FormSubmissionCount(r.Context(), submissionListQuery(data.formFormID, data.formSiteID, data.formStatus))
+
+	if err != nil {
+		controller.ui.Logger().Error("At submissionManagerController > prepareData", "error", err.Error())
+		return data, "error retrieving form submissions"
+	}
+
+	data.siteList, err = controller.ui.Store().SiteList(r.Context(), cmsstore.SiteQuery().
+		SetOrderBy(cmsstore.COLUMN_NAME).
+		SetSortOrder(cmsstore.SORT_ORDER_ASC).
+		SetOffset(0).
+		SetLimit(100))
+
+	if err != nil {
+		controller.ui.Logger().Error("At submissionManagerController > prepareData", "error", err.Error())
+		return data, "error retrieving sites"
+	}
+
+	return data, ""
+}
+
+// submissionListQuery returns the query of the submissions matching the
+// filters, the newest first
+func submissionListQuery(formID string, siteID string, status string) cmsstore.FormSubmissionQueryInterface {
+	query := cmsstore.FormSubmissionQuery().
+		SetOrderBy(cmsstore.COLUMN_CREATED_AT).
+		SetSortOrder(cmsstore.SORT_ORDER_DESC)
+
+	if formID != "" {
+		query.SetBlockID(formID)
+	}
+
+	if siteID != "" {
+		query.SetSiteID(siteID)
+	}
+
+	if status != "" {
+		query.SetStatus(status)
+	}
+
+	return query
+}
+
+type submissionManagerControllerData struct {
+	request  *http.Request
+	siteList []cmsstore.SiteInterface
+	formList []cmsstore.BlockInterface
+	page     string
+	pageInt  int
+	perPage  int
+
+	formFormID string
+	formSiteID string
+	formStatus string
+
+	recordList  []cmsstore.FormSubmissionInterface
+	recordCount int64
+}
@@ -0,0 +1,219 @@
+package admin
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/cmsstore/blocks/forms"
+	"github.com/dracory/cmsstore/testutils"
+	"github.com/dracory/test"
+	_ "modernc.org/sqlite"
+)
+
+func initSubmissionUI(store cmsstore.StoreInterface) UiInterface {
+	return UI(shared.UiConfig{
+		Layout: shared.Layout,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Store:  store,
+	})
+}
+
+// seedFormSubmissions creates a contact form block with two submissions
+func seedFormSubmissions(t *testing.T, store cmsstore.StoreInterface) (cmsstore.BlockInterface, []cmsstore.FormSubmissionInterface) {
+	t.Helper()
+
+	block := cmsstore.NewBlock().
+		SetSiteID(testutils.SITE_01).
+		SetName("Contact Form").
+		SetType(cmsstore.BLOCK_TYPE_FORM).
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := forms.SetFields(block, []forms.Field{
+		{Name: "name", Label: "Name", Type: forms.FIELD_TYPE_TEXT},
+		{Name: "email", Label: "Email", Type: forms.FIELD_TYPE_EMAIL},
+	}); err != nil {
+		t.Fatalf("Failed to set fields: %v", err)
+	}
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	submissions := []cmsstore.FormSubmissionInterface{}
+
+	for _, values := range []map[string]string{
+		{"name": "Jane", "email": "jane@example.com"},
+		{"email": "=HYPERLINK(\"http://evil.example.com\")", "removed_field": "Old value"},
+	} {
+		submission := cmsstore.NewFormSubmission().
+			SetSiteID(block.SiteID()).
+			SetBlockID(block.ID())
+
+		if err := submission.SetFormData(values); err != nil {
+			t.Fatalf("Failed to set form data: %v", err)
+		}
+
+		if err := store.FormSubmissionCreate(context.Background(), submission); err != nil {
+			t.Fatalf("Failed to create submission: %v", err)
+		}
+
+		submissions = append(submissions, submission)
+	}
+
+	return block, submissions
+}
+
+func Test_SubmissionManagerController_Index(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	block, submissions := seedFormSubmissions(t, store)
+
+	handler := NewSubmissionManagerController(initSubmissionUI(store)).Handler
+
+	body, response, err := test.CallStringEndpoint(http.MethodGet, handler, test.NewRequestOptions{
+		GetValues: map[string][]string{
+			"filter_form_id": {block.ID()},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, response.StatusCode)
+	}
+
+	for _, expected := range []string{"Form Submissions", "Export CSV", "Contact Form", "Jane | jane@example.com", submissions[1].ID()} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected body to contain %q", expected)
+		}
+	}
+}
+
+func Test_SubmissionViewController_MarksRead(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	_, submissions := seedFormSubmissions(t, store)
+
+	handler := NewSubmissionViewController(initSubmissionUI(store)).Handler
+
+	body, _, err := test.CallStringEndpoint(http.MethodGet, handler, test.NewRequestOptions{
+		GetValues: map[string][]string{
+			"submission_id": {submissions[0].ID()},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	if !strings.Contains(body, "jane@example.com") || !strings.Contains(body, "Contact Form") {
+		t.Errorf("Expected body to contain the submission values")
+	}
+
+	submission, err := store.FormSubmissionFindByID(context.Background(), submissions[0].ID())
+	if err != nil || submission == nil {
+		t.Fatalf("Failed to find submission: %v", err)
+	}
+
+	if !submission.IsRead() {
+		t.Errorf("Expected the viewed submission to be read, got %s", submission.Status())
+	}
+
+	_, _, err = test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: map[string][]string{
+			"submission_id": {submissions[0].ID()},
+		},
+		PostValues: map[string][]string{
+			"action": {ActionSubmissionArchive},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	submission, _ = store.FormSubmissionFindByID(context.Background(), submissions[0].ID())
+	if submission == nil || !submission.IsArchived() {
+		t.Errorf("Expected the submission to be archived")
+	}
+}
+
+func Test_SubmissionExportController_CSV(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	block, _ := seedFormSubmissions(t, store)
+
+	handler := NewSubmissionExportController(initSubmissionUI(store)).Handler
+
+	body, response, err := test.CallStringEndpoint(http.MethodGet, handler, test.NewRequestOptions{
+		GetValues: map[string][]string{
+			"filter_form_id": {block.ID()},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	if !strings.HasPrefix(response.Header.Get("Content-Type"), "text/csv") {
+		t.Errorf("Expected CSV content type, got %s", response.Header.Get("Content-Type"))
+	}
+
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %q", body)
+	}
+
+	if lines[0] != "ID,Form,Status,Submitted,Name,Email,removed_field,Page,IP Address" {
+		t.Errorf("Unexpected header: %s", lines[0])
+	}
+
+	if !strings.Contains(body, `"'=HYPERLINK(""http://evil.example.com"")"`) {
+		t.Errorf("Expected the formula to be escaped, got %s", body)
+	}
+}
+
+func Test_SubmissionDeleteController_Delete(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	_, submissions := seedFormSubmissions(t, store)
+
+	handler := NewSubmissionDeleteController(initSubmissionUI(store)).Handler
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		PostValues: map[string][]string{
+			"submission_id": {submissions[0].ID()},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	if !strings.Contains(body, "form submission deleted successfully") {
+		t.Errorf("Expected success message, got %s", body)
+	}
+
+	submission, err := store.FormSubmissionFindByID(context.Background(), submissions[0].ID())
+	if err != nil {
+		t.Fatalf("Failed to find submission: %v", err)
+	}
+
+	if submission != nil {
+		t.Error("Expected the submission to be deleted")
+	}
+}
@@ -0,0 +1,233 @@
+package admin
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/dracory/api"
+	"github.com/dracory/bs"
+	"github.com/dracory/cdn"
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+)
+
+// Actions changing the status of the submission
+const (
+	ActionSubmissionArchive  = "archive"
+	ActionSubmissionMarkNew  = "mark_new"
+	ActionSubmissionMarkRead = "mark_read"
+)
+
+// == CONTROLLER ==============================================================
+
+type submissionViewController struct {
+	ui UiInterface
+}
+
+// == CONSTRUCTOR =============================================================
+
+func NewSubmissionViewController(ui UiInterface) *submissionViewController {
+	return &submissionViewController{
+		ui: ui,
+	}
+}
+
+// Handler shows the submission, marking a new submission as read
+func (controller *submissionViewController) Handler(w http.ResponseWriter, r *http.Request) string {
+	data, errorMessage := controller.prepareDataAndValidate(r)
+
+	if errorMessage != "" {
+		return api.Error(errorMessage).ToString()
+	}
+
+	options := struct {
+		Styles     []string
+		StyleURLs  []string
+		Scripts    []string
+		ScriptURLs []string
+	}{
+		ScriptURLs: []string{
+			cdn.Htmx_2_0_0(),
+			cdn.Sweetalert2_11(),
+		},
+	}
+	return controller.ui.Layout(w, r, "Form Submission | CMS", controller.page(data).ToHTML(), options)
+}
+
+func (controller *submissionViewController) page(data submissionViewControllerData) hb.TagInterface {
+	adminHeader := shared.AdminHeader(controller.ui.Store(), controller.ui.Logger(), data.request)
+
+	breadcrumbs := shared.AdminBreadcrumbs(data.request, []shared.Breadcrumb{
+		{
+			Name: "Form Submissions",
+			URL:  shared.URLR(data.request, shared.PathFormsSubmissionManager, nil),
+		},
+		{
+			Name: "View Submission",
+			URL:  shared.URLR(data.request, shared.PathFormsSubmissionView, map[string]string{"submission_id": data.submissionID}),
+		},
+	}, struct{ SiteList []cmsstore.SiteInterface }{
+		SiteList: []cmsstore.SiteInterface{},
+	})
+
+	buttonBack := hb.Hyperlink().
+		Class("btn btn-secondary me-2").
+		Child(hb.I().Class("bi bi-chevron-left me-2")).
+		Text("Back").
+		Href(shared.URLR(data.request, shared.PathFormsSubmissionManager, map[string]string{
+			"filter_form_id": data.submission.BlockID(),
+		}))
+
+	title := hb.Heading1().
+		Text("Form Submission").
+		Child(hb.Div().Class("float-end").
+			Child(buttonBack).
+			Child(controller.statusForm(data)))
+
+	valuesTable := hb.Table().Class("table table-bordered")
+	for _, column := range data.columns {
+		valuesTable.Child(hb.TR().
+			Child(hb.TH().Style("width: 250px;").Text(column.Label)).
+			Child(hb.TD().Style("white-space: pre-wrap;").Text(data.submission.FormValue(column.Name))))
+	}
+
+	detailsTable := hb.Table().Class("table table-sm table-bordered").Style("font-size: 13px;")
+	for _, detail := range [][2]string{
+		{"Form", data.formName},
+		{"Status", data.submission.Status()},
+		{"Submitted", data.submission.CreatedAtCarbon().Format("d M Y H:i:s")},
+		{"Page", data.submission.SourceURL()},
+		{"IP Address", data.submission.IPAddress()},
+		{"User Agent", data.submission.UserAgent()},
+		{"Reference", data.submission.ID()},
+	} {
+		detailsTable.Child(hb.TR().
+			Child(hb.TH().Style("width: 250px;").Text(detail[0])).
+			Child(hb.TD().Text(detail[1])))
+	}
+
+	return hb.Div().
+		Class("container").
+		Child(breadcrumbs).
+		Child(hb.HR()).
+		Child(adminHeader).
+		Child(hb.HR()).
+		Child(title).
+		Child(bs.Card().Class("mt-3").
+			Child(bs.CardHeader().Text("Values")).
+			Child(bs.CardBody().Child(valuesTable))).
+		Child(bs.Card().Class("mt-3").
+			Child(bs.CardHeader().Text("Details")).
+			Child(bs.CardBody().Child(detailsTable)))
+}
+
+// statusForm renders the buttons changing the status of the submission
+func (controller *submissionViewController) statusForm(data submissionViewControllerData) hb.TagInterface {
+	statusForm := hb.Form().
+		Method(http.MethodPost).
+		Action(shared.URLR(data.request, shared.PathFormsSubmissionView, map[string]string{
+			"submission_id": data.submissionID,
+		})).
+		Style("display: inline;")
+
+	button := func(action string, label string, icon string) hb.TagInterface {
+		return hb.Button().
+			Type(hb.TYPE_SUBMIT).
+			Class("btn btn-info text-white me-2").
+			Name("action").
+			Value(action).
+			Child(hb.I().Class("bi " + icon + " me-2")).
+			Text(label)
+	}
+
+	if !data.submission.IsNew() {
+		statusForm.Child(button(ActionSubmissionMarkNew, "Mark as New", "bi-envelope"))
+	}
+
+	if !data.submission.IsArchived() {
+		statusForm.Child(button(ActionSubmissionArchive, "Archive", "bi-archive"))
+	} else {
+		statusForm.Child(button(ActionSubmissionMarkRead, "Unarchive", "bi-envelope-open"))
+	}
+
+	return statusForm
+}
+
+func (controller *submissionViewController) prepareDataAndValidate(r *http.Request) (data submissionViewControllerData, errorMessage string) {
+	data.request = r
+	data.submissionID = req.GetStringTrimmed(r, "submission_id")
+
+	if !controller.ui.Store().FormsEnabled() {
+		return data, "forms are not enabled"
+	}
+
+	if data.submissionID == "" {
+		return data, "submission id is required"
+	}
+
+	submission, err := controller.ui.Store().FormSubmissionFindByID(r.Context(), data.submissionID)
+
+	if err != nil {
+		controller.ui.Logger().Error("At submissionViewController > prepareDataAndValidate", "error", err.Error())
+		return data, "error retrieving form submission"
+	}
+
+	if submission == nil {
+		return data, "form submission not found"
+	}
+
+	data.submission = submission
+
+	status := ""
+	if r.Method == http.MethodPost {
+		status = map[string]string{
+			ActionSubmissionArchive:  cmsstore.FORM_SUBMISSION_STATUS_ARCHIVED,
+			ActionSubmissionMarkNew:  cmsstore.FORM_SUBMISSION_STATUS_NEW,
+			ActionSubmissionMarkRead: cmsstore.FORM_SUBMISSION_STATUS_READ,
+		}[req.GetStringTrimmed(r, "action")]
+	} else if submission.IsNew() {
+		status = cmsstore.FORM_SUBMISSION_STATUS_READ
+	}
+
+	if status != "" && status != submission.Status() {
+		submission.SetStatus(status)
+
+		if err := controller.ui.Store().FormSubmissionUpdate(r.Context(), submission); err != nil {
+			controller.ui.Logger().Error("At submissionViewController > prepareDataAndValidate", "error", err.Error())
+			return data, "error updating form submission"
+		}
+	}
+
+	block, err := controller.ui.Store().BlockFindByID(r.Context(), submission.BlockID())
+
+	if err != nil {
+		controller.ui.Logger().Error("At submissionViewController > prepareDataAndValidate", "error", err.Error())
+		return data, "error retrieving form"
+	}
+
+	data.formName = submission.BlockID()
+	if block != nil && block.Name() != "" {
+		data.formName = block.Name()
+	}
+
+	data.columns = submissionColumns(block, []cmsstore.FormSubmissionInterface{submission})
+
+	// Hide the fields added to the form after the submission
+	values, _ := submission.FormData()
+	data.columns = slices.DeleteFunc(data.columns, func(column submissionColumn) bool {
+		_, found := values[column.Name]
+		return !found
+	})
+
+	return data, ""
+}
+
+type submissionViewControllerData struct {
+	request      *http.Request
+	submissionID string
+	submission   cmsstore.FormSubmissionInterface
+	formName     string
+	columns      []submissionColumn
+}
@@ -3,6 +3,8 @@ package admin
 import (
 	"context"
 	"encoding/json"
+	"maps"
+	"slices"
 	"sort"
 
 	"github.com/dracory/cmsstore"
@@ -12,14 +14,24 @@ import (
 )
 
 type Node struct {
-	ID       string
-	Name     string
-	ParentID string
-	Sequence int
-	PageID   string
-	URL      string
-	Target   string
-	Status   string
+	ID         string
+	Name       string
+	ParentID   string
+	Sequence   int
+	PageID     string
+	URL        string
+	Target     string
+	Status     string
+	Type       string
+	Icon       string
+	CSSClass   string
+	Rel        string
+	Anchor     string
+	EntityID   string
+	BlockID    string
+	Labels     map[string]string
+	URLs       map[string]string
+	Visibility cmsstore.BlockVisibility
 }
 
 type Tree struct {
@@ -116,12 +128,12 @@ func (tree *Tree) Children(parentID string) []Node {
 //
 // Remember to update the ID, Sequence, and ParentID of the copy with new values
 func (tree *Tree) Clone(node Node) Node {
-	return Node{
-		ID:       node.ID,
-		Name:     node.Name,
-		ParentID: node.ParentID,
-		Sequence: node.Sequence,
-	}
+	clone := node
+	clone.Labels = maps.Clone(node.Labels)
+	clone.URLs = maps.Clone(node.URLs)
+	clone.Visibility.Languages = slices.Clone(node.Visibility.Languages)
+	clone.Visibility.Devices = slices.Clone(node.Visibility.Devices)
+	return clone
 }
 
 // Duplicate creates a deep clone of a Node (with children)
@@ -469,15 +481,40 @@ func (tree *Tree) ToJSON() (jsonString string, err error) {
 }
 
 func nodeToMap(node Node) map[string]any {
+	labels := map[string]any{}
+	for language, label := range node.Labels {
+		labels[language] = label
+	}
+
+	urls := map[string]any{}
+	for language, url := range node.URLs {
+		urls[language] = url
+	}
+
+	visibility := map[string]any{}
+	if visibilityJSON, err := json.Marshal(node.Visibility); err == nil {
+		_ = json.Unmarshal(visibilityJSON, &visibility)
+	}
+
 	return map[string]any{
-		"id":        node.ID,
-		"name":      node.Name,
-		"parent_id": node.ParentID,
-		"sequence":  node.Sequence,
-		"page_id":   node.PageID,
-		"url":       node.URL,
-		"target":    node.Target,
-		"status":    node.Status,
+		"id":         node.ID,
+		"name":       node.Name,
+		"parent_id":  node.ParentID,
+		"sequence":   node.Sequence,
+		"page_id":    node.PageID,
+		"url":        node.URL,
+		"target":     node.Target,
+		"status":     node.Status,
+		"type":       node.Type,
+		"icon":       node.Icon,
+		"css_class":  node.CSSClass,
+		"rel":        node.Rel,
+		"anchor":     node.Anchor,
+		"entity_id":  node.EntityID,
+		"block_id":   node.BlockID,
+		"labels":     labels,
+		"urls":       urls,
+		"visibility": visibility,
 	}
 }
 
@@ -486,15 +523,48 @@ func nodeFromMap(nodeMap map[string]interface{}) Node {
 	if status == "" {
 		status = cmsstore.MENU_ITEM_STATUS_DRAFT
 	}
+
+	labels := map[string]string{}
+	for language, label := range cast.ToStringMapString(nodeMap["labels"]) {
+		if label != "" {
+			labels[language] = label
+		}
+	}
+
+	urls := map[string]string{}
+	for language, url := range cast.ToStringMapString(nodeMap["urls"]) {
+		if url != "" {
+			urls[language] = url
+		}
+	}
+
+	// Invalid visibility rules are dropped, as the menu items without rules
+	visibility := cmsstore.BlockVisibility{}
+	if visibilityJSON, err := json.Marshal(nodeMap["visibility"]); err == nil {
+		if err := json.Unmarshal(visibilityJSON, &visibility); err != nil {
+			visibility = cmsstore.BlockVisibility{}
+		}
+	}
+
 	return Node{
-		ID:       nodeMap["id"].(string),
-		Name:     nodeMap["name"].(string),
-		ParentID: nodeMap["parent_id"].(string),
-		Sequence: cast.ToInt(nodeMap["sequence"]),
-		PageID:   nodeMap["page_id"].(string),
-		URL:      nodeMap["url"].(string),
-		Target:   nodeMap["target"].(string),
-		Status:   status,
+		ID:         cast.ToString(nodeMap["id"]),
+		Name:       cast.ToString(nodeMap["name"]),
+		ParentID:   cast.ToString(nodeMap["parent_id"]),
+		Sequence:   cast.ToInt(nodeMap["sequence"]),
+		PageID:     cast.ToString(nodeMap["page_id"]),
+		URL:        cast.ToString(nodeMap["url"]),
+		Target:     cast.ToString(nodeMap["target"]),
+		Status:     status,
+		Type:       cast.ToString(nodeMap["type"]),
+		Icon:       cast.ToString(nodeMap["icon"]),
+		CSSClass:   cast.ToString(nodeMap["css_class"]),
+		Rel:        cast.ToString(nodeMap["rel"]),
+		Anchor:     cast.ToString(nodeMap["anchor"]),
+		EntityID:   cast.ToString(nodeMap["entity_id"]),
+		BlockID:    cast.ToString(nodeMap["block_id"]),
+		Labels:     labels,
+		URLs:       urls,
+		Visibility: visibility,
 	}
 }
 
@@ -579,28 +649,35 @@ func childrenMenuItems(menuItems []cmsstore.MenuItemInterface, parentID string)
 }
 
 func menuItemToNodeWithParentAndSequence(menuItem cmsstore.MenuItemInterface, parentID string, sequence int) Node {
-	return Node{
-		ID:       menuItem.ID(),
-		Name:     menuItem.Name(),
-		ParentID: parentID,
-		Sequence: sequence,
-		PageID:   menuItem.PageID(),
-		URL:      menuItem.URL(),
-		Target:   menuItem.Target(),
-		Status:   menuItem.Status(),
-	}
+	node := menuItemToNode(menuItem)
+	node.ParentID = parentID
+	node.Sequence = sequence
+	return node
 }
 
 func menuItemToNode(menuItem cmsstore.MenuItemInterface) Node {
+	// Invalid visibility rules are edited as no rules
+	visibility, _ := cmsstore.MenuItemVisibilityRules(menuItem)
+
 	return Node{
-		ID:       menuItem.ID(),
-		Name:     menuItem.Name(),
-		ParentID: menuItem.ParentID(),
-		Sequence: menuItem.SequenceInt(),
-		PageID:   menuItem.PageID(),
-		URL:      menuItem.URL(),
-		Target:   menuItem.Target(),
-		Status:   menuItem.Status(),
+		ID:         menuItem.ID(),
+		Name:       menuItem.Name(),
+		ParentID:   menuItem.ParentID(),
+		Sequence:   menuItem.SequenceInt(),
+		PageID:     menuItem.PageID(),
+		URL:        menuItem.URL(),
+		Target:     menuItem.Target(),
+		Status:     menuItem.Status(),
+		Type:       menuItem.Meta(cmsstore.MENU_ITEM_META_TYPE),
+		Icon:       menuItem.Icon(),
+		CSSClass:   menuItem.CSSClass(),
+		Rel:        menuItem.Rel(),
+		Anchor:     menuItem.Anchor(),
+		EntityID:   menuItem.EntityID(),
+		BlockID:    menuItem.BlockID(),
+		Labels:     menuItem.Labels(),
+		URLs:       menuItem.TranslatedURLs(),
+		Visibility: visibility,
 	}
 }
 
@@ -675,6 +752,10 @@ func SaveMenuItems(ctx context.Context, store cmsstore.StoreInterface, menuID st
 			menuItem.SetStatus(node.Status)
 		}
 
+		if err = saveMenuItemAttributes(menuItem, node); err != nil {
+			return err
+		}
+
 		err = store.MenuItemUpdate(ctx, menuItem)
 
 		if err != nil {
@@ -702,3 +783,38 @@ func SaveMenuItems(ctx context.Context, store cmsstore.StoreInterface, menuID st
 
 	return nil
 }
+
+// saveMenuItemAttributes sets the type, the attributes, the labels, the
+// URLs and the visibility rules of the node to the menu item, the ones
+// removed from the node are removed from the menu item
+func saveMenuItemAttributes(menuItem cmsstore.MenuItemInterface, node Node) error {
+	menuItem.SetType(node.Type)
+	menuItem.SetIcon(node.Icon)
+	menuItem.SetCSSClass(node.CSSClass)
+	menuItem.SetRel(node.Rel)
+	menuItem.SetAnchor(node.Anchor)
+	menuItem.SetEntityID(node.EntityID)
+	menuItem.SetBlockID(node.BlockID)
+
+	for language := range menuItem.Labels() {
+		if _, exists := node.Labels[language]; !exists {
+			menuItem.SetLabel(language, "")
+		}
+	}
+
+	for language, label := range node.Labels {
+		menuItem.SetLabel(language, label)
+	}
+
+	for language := range menuItem.TranslatedURLs() {
+		if _, exists := node.URLs[language]; !exists {
+			menuItem.SetTranslatedURL(language, "")
+		}
+	}
+
+	for language, url := range node.URLs {
+		menuItem.SetTranslatedURL(language, url)
+	}
+
+	return cmsstore.SetMenuItemVisibilityRules(menuItem, node.Visibility)
+}
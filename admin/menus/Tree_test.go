@@ -1,9 +1,11 @@
 package admin
 
 import (
+	"context"
 	"testing"
 
 	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
 )
 
 func Test_Tree_FromJSON(t *testing.T) {
@@ -516,3 +518,125 @@ func Test_Tree_Update(t *testing.T) {
 		t.Fatal("Expected type 'updated', got:", foundNode.Name)
 	}
 }
+
+func Test_Tree_JSONAttributes(t *testing.T) {
+	menuItem := cmsstore.NewMenuItem().
+		SetID("1").
+		SetName("Products").
+		SetType(cmsstore.MENU_ITEM_TYPE_MEGA_MENU).
+		SetIcon("bi bi-box").
+		SetCSSClass("highlight").
+		SetRel("nofollow").
+		SetBlockID("BLOCK_01").
+		SetLabel("fr", "Produits").
+		SetTranslatedURL("fr", "/fr/produits")
+
+	if err := cmsstore.SetMenuItemVisibilityRules(menuItem, cmsstore.BlockVisibility{Languages: []string{"fr"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	jsonString, err := NewTreeFromMenuItems([]cmsstore.MenuItemInterface{menuItem}).ToJSON()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree, err := NewTreeFromJSON(jsonString)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := tree.Find("1")
+
+	if node == nil {
+		t.Fatal("node not found")
+	}
+
+	if node.Type != cmsstore.MENU_ITEM_TYPE_MEGA_MENU || node.Icon != "bi bi-box" || node.CSSClass != "highlight" || node.Rel != "nofollow" || node.BlockID != "BLOCK_01" {
+		t.Errorf("attributes not preserved: %+v", *node)
+	}
+
+	if node.Labels["fr"] != "Produits" {
+		t.Errorf("label not preserved: %v", node.Labels)
+	}
+
+	if node.URLs["fr"] != "/fr/produits" {
+		t.Errorf("URL not preserved: %v", node.URLs)
+	}
+
+	if len(node.Visibility.Languages) != 1 || node.Visibility.Languages[0] != "fr" {
+		t.Errorf("visibility not preserved: %+v", node.Visibility)
+	}
+}
+
+func Test_Tree_FromJSON_MissingKeys(t *testing.T) {
+	tree, err := NewTreeFromJSON(`[{"id":"1","name":"Home"}]`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := tree.Find("1")
+
+	if node == nil || node.Name != "Home" || node.Status != cmsstore.MENU_ITEM_STATUS_DRAFT {
+		t.Errorf("unexpected node: %+v", node)
+	}
+}
+
+func Test_SaveMenuItems_Attributes(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	menuItemsJSON := `[{"id":"1","name":"Docs","parent_id":"","sequence":0,"url":"/docs","status":"active",` +
+		`"type":"url","icon":"bi bi-book","rel":"noopener","labels":{"fr":"Docs FR"},"urls":{"fr":"/fr/docs"},` +
+		`"visibility":{"logged_in":"yes"}}]`
+
+	if err := SaveMenuItems(ctx, store, "MENU_01", menuItemsJSON, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	menuItem, err := store.MenuItemFindByID(ctx, "1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if menuItem == nil {
+		t.Fatal("menu item not found")
+	}
+
+	if menuItem.Icon() != "bi bi-book" || menuItem.Rel() != "noopener" || menuItem.Label("fr") != "Docs FR" || menuItem.TranslatedURL("fr") != "/fr/docs" {
+		t.Errorf("attributes not saved: %v", menuItem.Data())
+	}
+
+	rules, err := cmsstore.MenuItemVisibilityRules(menuItem)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rules.LoggedIn != cmsstore.BLOCK_VISIBILITY_LOGGED_IN_YES {
+		t.Errorf("visibility not saved: %+v", rules)
+	}
+
+	// Removing the attributes from the node removes them from the menu item
+	if err := SaveMenuItems(ctx, store, "MENU_01", `[{"id":"1","name":"Docs","parent_id":"","sequence":0,"url":"/docs","status":"active"}]`, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	menuItem, err = store.MenuItemFindByID(ctx, "1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if menuItem.Icon() != "" || len(menuItem.Labels()) != 0 || len(menuItem.TranslatedURLs()) != 0 || menuItem.Meta(cmsstore.MENU_ITEM_META_VISIBILITY) != "" {
+		t.Errorf("attributes not removed: %v", menuItem.Data())
+	}
+}
@@ -90,7 +90,8 @@ func (controller *menuUpdateController) treeEditorHandle(r *http.Request, data m
 			"menu_id": data.menuID,
 			"action":  ACTION_TREEEDITOR_HANDLE,
 		}),
-		pageList: pageList,
+		pageList:  pageList,
+		languages: controller.ui.Store().TranslationLanguages(),
 	}
 
 	return treeControl.Render(r)
@@ -175,6 +176,7 @@ func (controller menuUpdateController) page(data menuUpdateControllerData) hb.Ta
 				Child(hb.Heading4().
 					HTMLIf(data.view == VIEW_MENU_ITEMS, "Menu Items").
 					HTMLIf(data.view == VIEW_SETTINGS, "Menu Settings").
+					HTMLIf(data.view == VIEW_SOURCES, "Menu Sources").
 					Style("margin-bottom:0;display:inline-block;")).
 				Child(buttonSave),
 		).
@@ -193,6 +195,14 @@ func (controller menuUpdateController) page(data menuUpdateControllerData) hb.Ta
 					"view":    VIEW_MENU_ITEMS,
 				})).
 				HTML("Menu Items"))).
+		Child(bs.NavItem().
+			Child(bs.NavLink().
+				ClassIf(data.view == VIEW_SOURCES, "active").
+				Href(shared.URLR(data.request, shared.PathMenusMenuUpdate, map[string]string{
+					"menu_id": data.menuID,
+					"view":    VIEW_SOURCES,
+				})).
+				HTML("Sources"))).
 		Child(bs.NavItem().
 			Child(bs.NavLink().
 				ClassIf(data.view == VIEW_SETTINGS, "active").
@@ -229,6 +239,10 @@ func (controller menuUpdateController) form(data menuUpdateControllerData) hb.Ta
 		formpageUpdate.SetFields(fieldsMenuItems)
 	}
 
+	if data.view == VIEW_SOURCES {
+		formpageUpdate.SetFields(controller.fieldsSources(data))
+	}
+
 	if data.formErrorMessage != "" {
 		formpageUpdate.AddField(&form.Field{
 			Type:  form.FORM_FIELD_TYPE_RAW,
@@ -472,6 +486,15 @@ func (controller menuUpdateController) saveMenu(r *http.Request, data menuUpdate
 		}
 	}
 
+	if data.view == VIEW_SOURCES {
+		data.formSources = sourcesFromRequest(r)
+
+		if err := cmsstore.SetMenuSources(data.menu, data.formSources); err != nil {
+			data.formErrorMessage = err.Error()
+			return data, ""
+		}
+	}
+
 	err := controller.ui.Store().MenuUpdate(data.request.Context(), data.menu)
 
 	if err != nil {
@@ -532,6 +555,18 @@ func (controller menuUpdateController) prepareDataAndValidate(r *http.Request) (
 		return data, err.Error()
 	}
 
+	if data.view == VIEW_SOURCES {
+		data.pageList, err = controller.ui.Store().PageList(r.Context(), cmsstore.PageQuery().
+			SetSiteID(data.menu.SiteID()).
+			SetOrderBy(cmsstore.COLUMN_NAME).
+			SetSortOrder(cmsstore.SORT_ORDER_ASC))
+
+		if err != nil {
+			controller.ui.Logger().Error("At menuUpdateController > prepareDataAndValidate", "error", err.Error())
+			return data, err.Error()
+		}
+	}
+
 	// 2. Populate form data
 	menuItemsJson, err := controller.buildMenuItemsJson(data.menuItemList)
 
@@ -545,6 +580,12 @@ func (controller menuUpdateController) prepareDataAndValidate(r *http.Request) (
 	data.formMemo = data.menu.Memo()
 	data.formSiteID = data.menu.SiteID()
 	data.formStatus = data.menu.Status()
+	data.formSources, err = cmsstore.MenuSources(data.menu)
+
+	if err != nil {
+		controller.ui.Logger().Error("At menuUpdateController > prepareDataAndValidate", "error", err.Error())
+		return data, err.Error()
+	}
 
 	// 3. Show the webpage, if GET request
 	if r.Method != http.MethodPost {
@@ -577,6 +618,7 @@ type menuUpdateControllerData struct {
 	menuID       string
 	menu         cmsstore.MenuInterface
 	menuItemList []cmsstore.MenuItemInterface
+	pageList     []cmsstore.PageInterface
 	siteList     []cmsstore.SiteInterface
 	view         string
 
@@ -589,4 +631,5 @@ type menuUpdateControllerData struct {
 	formMemo           string
 	formSiteID         string
 	formStatus         string
+	formSources        []cmsstore.MenuSource
 }
@@ -269,3 +269,95 @@ func Test_MenuUpdateController_UpdateSettings_ValidationError(t *testing.T) {
 		t.Errorf("Expected body to contain 'success' or 'error'")
 	}
 }
+
+func Test_MenuUpdateController_UpdateSources(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	handler := initMenuUpdateHandler(store)
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	page, err := testutils.SeedPage(store, site.ID(), "About")
+	if err != nil {
+		t.Fatalf("Failed to seed page: %v", err)
+	}
+
+	menu := cmsstore.NewMenu()
+	menu.SetSiteID(site.ID())
+	menu.SetName("Test Menu")
+	menu.SetStatus(cmsstore.MENU_STATUS_ACTIVE)
+	err = store.MenuCreate(context.Background(), menu)
+	if err != nil {
+		t.Fatalf("Failed to create menu: %v", err)
+	}
+
+	body, _, err := test.CallStringEndpoint(http.MethodGet, handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"menu_id": {menu.ID()},
+			"view":    {"sources"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+	if !strings.Contains(body, `name="source_type[]"`) {
+		t.Errorf("Expected body to contain the sources table")
+	}
+
+	// The second row has no type and is skipped
+	body, _, err = test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"menu_id": {menu.ID()},
+			"view":    {"sources"},
+		},
+		PostValues: url.Values{
+			"source_type[]":           {cmsstore.MENU_SOURCE_TYPE_PAGE_CHILDREN, ""},
+			"source_parent_item_id[]": {"", ""},
+			"source_page_id[]":        {page.ID(), ""},
+			"source_depth[]":          {"2", ""},
+			"source_limit[]":          {"", ""},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+	if !strings.Contains(strings.ToLower(body), "menu saved successfully") {
+		t.Fatalf("Expected body to contain success message, got: %s", body)
+	}
+
+	menuFound, err := store.MenuFindByID(context.Background(), menu.ID())
+	if err != nil {
+		t.Fatalf("Failed to find menu: %v", err)
+	}
+
+	sources, err := cmsstore.MenuSources(menuFound)
+	if err != nil {
+		t.Fatalf("Failed to parse sources: %v", err)
+	}
+	if len(sources) != 1 || sources[0].PageID != page.ID() || sources[0].Depth != 2 {
+		t.Fatalf("Expected one page children source, got: %+v", sources)
+	}
+
+	// An invalid source is rejected
+	body, _, err = test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"menu_id": {menu.ID()},
+			"view":    {"sources"},
+		},
+		PostValues: url.Values{
+			"source_type[]": {cmsstore.MENU_SOURCE_TYPE_ENTITIES},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+	if !strings.Contains(body, "menu source entity type is required") {
+		t.Errorf("Expected body to contain the validation error")
+	}
+}
@@ -0,0 +1,172 @@
+package admin
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/form"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+const VIEW_SOURCES = "sources"
+
+// fieldsSources returns the table of the menu sources, one row per
+// source, with a blank row to add a new source. A row without a type
+// is removed on save.
+func (controller menuUpdateController) fieldsSources(data menuUpdateControllerData) []form.FieldInterface {
+	sources := append(append([]cmsstore.MenuSource{}, data.formSources...), cmsstore.MenuSource{})
+
+	typeOptions := func(selected string) []hb.TagInterface {
+		options := []hb.TagInterface{hb.Option().Value("").Text("- none -")}
+		for _, sourceType := range cmsstore.MenuSourceTypes() {
+			options = append(options, hb.Option().
+				Value(sourceType).
+				Text(sourceType).
+				AttrIf(sourceType == selected, "selected", "selected"))
+		}
+		return options
+	}
+
+	parentOptions := func(selected string) []hb.TagInterface {
+		options := []hb.TagInterface{hb.Option().Value("").Text("- top level -")}
+		for _, menuItem := range data.menuItemList {
+			options = append(options, hb.Option().
+				Value(menuItem.ID()).
+				Text(menuItem.Name()).
+				AttrIf(menuItem.ID() == selected, "selected", "selected"))
+		}
+		return options
+	}
+
+	pageOptions := func(selected string) []hb.TagInterface {
+		options := []hb.TagInterface{hb.Option().Value("").Text("- none -")}
+		for _, page := range data.pageList {
+			options = append(options, hb.Option().
+				Value(page.ID()).
+				Text(page.Name()).
+				AttrIf(page.ID() == selected, "selected", "selected"))
+		}
+		return options
+	}
+
+	input := func(name, value, placeholder string) hb.TagInterface {
+		return hb.Input().
+			Class("form-control form-control-sm").
+			Name(name + "[]").
+			Value(value).
+			Placeholder(placeholder)
+	}
+
+	number := func(name string, value int) hb.TagInterface {
+		return hb.Input().
+			Class("form-control form-control-sm").
+			Type(hb.TYPE_NUMBER).
+			Attr("min", "0").
+			Name(name + "[]").
+			Value(lo.Ternary(value > 0, cast.ToString(value), ""))
+	}
+
+	rows := lo.Map(sources, func(source cmsstore.MenuSource, _ int) hb.TagInterface {
+		return hb.TR().
+			Child(hb.TD().Child(hb.Select().Class("form-select form-select-sm").Name("source_type[]").Children(typeOptions(source.Type)))).
+			Child(hb.TD().Child(hb.Select().Class("form-select form-select-sm").Name("source_parent_item_id[]").Children(parentOptions(source.ParentItemID)))).
+			Child(hb.TD().Child(hb.Select().Class("form-select form-select-sm").Name("source_page_id[]").Children(pageOptions(source.PageID)))).
+			Child(hb.TD().Child(input("source_alias_like", source.AliasLike, "/blog/%"))).
+			Child(hb.TD().Child(input("source_template_id", source.TemplateID, "template ID"))).
+			Child(hb.TD().Child(input("source_entity_type", source.EntityType, "product"))).
+			Child(hb.TD().Child(input("source_label_attribute", source.LabelAttribute, "title"))).
+			Child(hb.TD().Child(input("source_taxonomy_id", source.TaxonomyID, "taxonomy ID"))).
+			Child(hb.TD().Child(input("source_url_pattern", source.URLPattern, "/products/[[handle]]"))).
+			Child(hb.TD().Child(number("source_depth", source.Depth))).
+			Child(hb.TD().Child(number("source_limit", source.Limit)))
+	})
+
+	table := hb.Table().
+		Class("table table-sm align-middle").
+		Child(hb.Thead().Child(hb.TR().
+			Child(hb.TH().Text("Type")).
+			Child(hb.TH().Text("Under Item")).
+			Child(hb.TH().Text("Parent Page")).
+			Child(hb.TH().Text("Alias Like")).
+			Child(hb.TH().Text("Template")).
+			Child(hb.TH().Text("Entity Type")).
+			Child(hb.TH().Text("Label Attribute")).
+			Child(hb.TH().Text("Taxonomy")).
+			Child(hb.TH().Text("URL Pattern")).
+			Child(hb.TH().Text("Depth")).
+			Child(hb.TH().Text("Limit")))).
+		Child(hb.Tbody().Children(rows))
+
+	help := hb.Div().
+		Class("form-text mb-3").
+		HTML(`Sources generate menu items dynamically when the menu is rendered, after the menu items of the same parent. ` +
+			`<b>page_children</b> uses the parent page, depth and limit. ` +
+			`<b>page_query</b> uses the alias like, template and limit. ` +
+			`<b>entities</b> uses the entity type, label attribute and URL pattern. ` +
+			`<b>taxonomy</b> uses the taxonomy, URL pattern and depth. ` +
+			`The URL pattern supports [[id]], [[handle]], [[slug]] and the entity attributes, i.e. [[title]]. ` +
+			`Clear the type to remove a source.`)
+
+	return []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Type:  form.FORM_FIELD_TYPE_RAW,
+			Value: hb.Div().Class("table-responsive").Child(table).ToHTML() + help.ToHTML(),
+		}),
+		form.NewField(form.FieldOptions{
+			Label:    "Menu ID",
+			Name:     "menu_id",
+			Type:     form.FORM_FIELD_TYPE_HIDDEN,
+			Value:    data.menuID,
+			Readonly: true,
+		}),
+		form.NewField(form.FieldOptions{
+			Label:    "View",
+			Name:     "view",
+			Type:     form.FORM_FIELD_TYPE_HIDDEN,
+			Value:    VIEW_SOURCES,
+			Readonly: true,
+		}),
+	}
+}
+
+// sourcesFromRequest returns the sources posted by the sources table,
+// the rows without a type are skipped
+func sourcesFromRequest(r *http.Request) []cmsstore.MenuSource {
+	types := req.GetArray(r, "source_type", []string{})
+
+	column := func(name string, index int) string {
+		values := req.GetArray(r, name, []string{})
+		if index < len(values) {
+			return strings.TrimSpace(values[index])
+		}
+		return ""
+	}
+
+	sources := []cmsstore.MenuSource{}
+
+	for index, sourceType := range types {
+		if sourceType == "" {
+			continue
+		}
+
+		sources = append(sources, cmsstore.MenuSource{
+			Type:           sourceType,
+			ParentItemID:   column("source_parent_item_id", index),
+			PageID:         column("source_page_id", index),
+			AliasLike:      column("source_alias_like", index),
+			TemplateID:     column("source_template_id", index),
+			EntityType:     column("source_entity_type", index),
+			LabelAttribute: column("source_label_attribute", index),
+			TaxonomyID:     column("source_taxonomy_id", index),
+			URLPattern:     column("source_url_pattern", index),
+			Depth:          cast.ToInt(column("source_depth", index)),
+			Limit:          cast.ToInt(column("source_limit", index)),
+		})
+	}
+
+	return sources
+}
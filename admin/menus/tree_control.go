@@ -19,6 +19,7 @@ type treeControl struct {
 	treeJSON         string
 	targetTextareaID string
 	pageList         []cmsstore.PageInterface
+	languages        map[string]string
 }
 
 func (t *treeControl) Render(r *http.Request) hb.TagInterface {
@@ -122,6 +123,10 @@ func (t *treeControl) Render(r *http.Request) hb.TagInterface {
 		node.Name = name
 		node.Status = status
 
+		if err := t.nodeAttributesFromRequest(r, node); err != nil {
+			return hb.Div().Text(`ERROR: ` + err.Error())
+		}
+
 		tree.Update(*node)
 	}
 
@@ -310,88 +315,93 @@ func (t *treeControl) modalNodeUpdate(node Node) hb.TagInterface {
 		status = cmsstore.MENU_ITEM_STATUS_DRAFT
 	}
 
-	form := form.NewForm(form.FormOptions{
-		ID: "FormMenuUpdate",
-		Fields: []form.FieldInterface{
-			form.NewField(form.FieldOptions{
-				Label:    "Menu Item name",
-				Name:     "treectl_name",
-				Type:     form.FORM_FIELD_TYPE_STRING,
-				Value:    name,
-				Required: true,
-			}),
-			form.NewField(form.FieldOptions{
-				Label:    "Status",
-				Name:     "treectl_status",
-				Type:     form.FORM_FIELD_TYPE_SELECT,
-				Value:    status,
-				Required: true,
-				Help:     "The status of this menu item. Only active items will be displayed on the website.",
-				Options: []form.FieldOption{
-					{
-						Value: "Draft",
-						Key:   cmsstore.MENU_ITEM_STATUS_DRAFT,
-					},
-					{
-						Value: "Active",
-						Key:   cmsstore.MENU_ITEM_STATUS_ACTIVE,
-					},
-					{
-						Value: "Inactive",
-						Key:   cmsstore.MENU_ITEM_STATUS_INACTIVE,
-					},
+	fields := []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Label:    "Menu Item name",
+			Name:     "treectl_name",
+			Type:     form.FORM_FIELD_TYPE_STRING,
+			Value:    name,
+			Required: true,
+		}),
+		form.NewField(form.FieldOptions{
+			Label:    "Status",
+			Name:     "treectl_status",
+			Type:     form.FORM_FIELD_TYPE_SELECT,
+			Value:    status,
+			Required: true,
+			Help:     "The status of this menu item. Only active items will be displayed on the website.",
+			Options: []form.FieldOption{
+				{
+					Value: "Draft",
+					Key:   cmsstore.MENU_ITEM_STATUS_DRAFT,
+				},
+				{
+					Value: "Active",
+					Key:   cmsstore.MENU_ITEM_STATUS_ACTIVE,
+				},
+				{
+					Value: "Inactive",
+					Key:   cmsstore.MENU_ITEM_STATUS_INACTIVE,
+				},
+			},
+		}),
+		form.NewField(form.FieldOptions{
+			Label:    "Page",
+			Name:     "treectl_page_id",
+			Type:     form.FORM_FIELD_TYPE_SELECT,
+			Value:    pageID,
+			Required: true,
+			Help:     "Select a page to link to, if you want to link to a page",
+			Options: append([]form.FieldOption{
+				{
+					Value: "Select site",
+					Key:   "",
 				},
-			}),
-			form.NewField(form.FieldOptions{
-				Label:    "Page",
-				Name:     "treectl_page_id",
-				Type:     form.FORM_FIELD_TYPE_SELECT,
-				Value:    pageID,
-				Required: true,
-				Help:     "Select a page to link to, if you want to link to a page",
-				Options: append([]form.FieldOption{
-					{
-						Value: "Select site",
-						Key:   "",
-					},
+			},
+				lo.Map(t.pageList, func(page cmsstore.PageInterface, index int) form.FieldOption {
+					return form.FieldOption{
+						Value: page.Name(),
+						Key:   page.ID(),
+					}
+				})...),
+		}),
+		form.NewField(form.FieldOptions{
+			Label:    "Menu Item URL",
+			Name:     "treectl_url",
+			Type:     form.FORM_FIELD_TYPE_STRING,
+			Value:    url,
+			Required: true,
+			Help:     "The URL to link to (if page is not selected)",
+		}),
+		form.NewField(form.FieldOptions{
+			Label:    "Target",
+			Name:     "treectl_target",
+			Type:     form.FORM_FIELD_TYPE_SELECT,
+			Value:    target,
+			Required: true,
+			Options: []form.FieldOption{
+				{
+					Value: "_self",
+					Key:   "_self",
 				},
-					lo.Map(t.pageList, func(page cmsstore.PageInterface, index int) form.FieldOption {
-						return form.FieldOption{
-							Value: page.Name(),
-							Key:   page.ID(),
-						}
-					})...),
-			}),
-			form.NewField(form.FieldOptions{
-				Label:    "Menu Item URL",
-				Name:     "treectl_url",
-				Type:     form.FORM_FIELD_TYPE_STRING,
-				Value:    url,
-				Required: true,
-				Help:     "The URL to link to (if page is not selected)",
-			}),
-			form.NewField(form.FieldOptions{
-				Label:    "Target",
-				Name:     "treectl_target",
-				Type:     form.FORM_FIELD_TYPE_SELECT,
-				Value:    target,
-				Required: true,
-				Options: []form.FieldOption{
-					{
-						Value: "_self",
-						Key:   "_self",
-					},
-					{
-						Value: "_blank",
-						Key:   "_blank",
-					},
-					{
-						Value: "_parent",
-						Key:   "_parent",
-					},
+				{
+					Value: "_blank",
+					Key:   "_blank",
 				},
-			}),
-		},
+				{
+					Value: "_parent",
+					Key:   "_parent",
+				},
+			},
+		}),
+	}
+
+	fields = append(fields, t.nodeAttributeFields(node)...)
+	fields = append(fields, t.nodeVisibilityFields(node)...)
+
+	form := form.NewForm(form.FormOptions{
+		ID:     "FormMenuUpdate",
+		Fields: fields,
 	})
 
 	modalID := "ModalNodeUpdate"
@@ -0,0 +1,242 @@
+package admin
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/form"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/spf13/cast"
+)
+
+// nodeAttributeFields generates the modal form fields for the type, the
+// attributes and the per-language labels and URLs of the menu item
+func (t *treeControl) nodeAttributeFields(node Node) []form.FieldInterface {
+	typeOptions := []form.FieldOption{
+		{Value: "Automatic (page or URL)", Key: ""},
+	}
+
+	for _, menuItemType := range cmsstore.MenuItemTypes() {
+		typeOptions = append(typeOptions, form.FieldOption{Value: menuItemType, Key: menuItemType})
+	}
+
+	fields := []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Label:   "Type",
+			Name:    "treectl_type",
+			Type:    form.FORM_FIELD_TYPE_SELECT,
+			Value:   node.Type,
+			Help:    "Page and URL items are links. An anchor links to the anchor on the page, if selected, or else on the current page. An entity links to the URL, with the entity placeholders, i.e. /products/[[handle]]. A mega menu displays the block.",
+			Options: typeOptions,
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Anchor",
+			Name:  "treectl_anchor",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: node.Anchor,
+			Help:  "The anchor of an anchor item, without #",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Entity ID",
+			Name:  "treectl_entity_id",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: node.EntityID,
+			Help:  "The custom entity of an entity item",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Block ID",
+			Name:  "treectl_block_id",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: node.BlockID,
+			Help:  "The block displayed in the panel of a mega menu item",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Icon",
+			Name:  "treectl_icon",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: node.Icon,
+			Help:  "The CSS class of the icon, i.e. bi bi-house",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "CSS Class",
+			Name:  "treectl_css_class",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: node.CSSClass,
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Rel",
+			Name:  "treectl_rel",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: node.Rel,
+			Help:  "The rel attribute of the link, i.e. nofollow noopener",
+		}),
+	}
+
+	for _, language := range slices.Sorted(maps.Keys(t.languages)) {
+		fields = append(fields, form.NewField(form.FieldOptions{
+			Label: "Label (" + t.languages[language] + ")",
+			Name:  "treectl_label_" + language,
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: node.Labels[language],
+			Help:  "Leave empty to display the menu item name",
+		}))
+		fields = append(fields, form.NewField(form.FieldOptions{
+			Label: "URL (" + t.languages[language] + ")",
+			Name:  "treectl_url_" + language,
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: node.URLs[language],
+			Help:  "Leave empty to link to the menu item URL or page",
+		}))
+	}
+
+	return fields
+}
+
+// nodeVisibilityFields generates the modal form fields for the
+// visibility rules of the menu item (see cmsstore.BlockVisibility)
+func (t *treeControl) nodeVisibilityFields(node Node) []form.FieldInterface {
+	rules := node.Visibility
+
+	languagesHelp := "Comma separated language codes, i.e. en, fr. Leave empty for all the languages."
+	if len(t.languages) > 0 {
+		languagesHelp = "Comma separated language codes (" + strings.Join(slices.Sorted(maps.Keys(t.languages)), ", ") + "). Leave empty for all the languages."
+	}
+
+	percentage := ""
+	if rules.Percentage > 0 && rules.Percentage < 100 {
+		percentage = strconv.Itoa(rules.Percentage)
+	}
+
+	return []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Type:  form.FORM_FIELD_TYPE_RAW,
+			Value: hb.Heading6().Class("mt-3").Text("Visibility Rules").ToHTML(),
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Display From (UTC)",
+			Name:  "treectl_visibility_date_from",
+			Type:  form.FORM_FIELD_TYPE_DATETIME,
+			Value: visibilityDateInputValue(rules.DateFrom),
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Display Until (UTC)",
+			Name:  "treectl_visibility_date_to",
+			Type:  form.FORM_FIELD_TYPE_DATETIME,
+			Value: visibilityDateInputValue(rules.DateTo),
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Languages",
+			Name:  "treectl_visibility_languages",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: strings.Join(rules.Languages, ", "),
+			Help:  languagesHelp,
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Devices",
+			Name:  "treectl_visibility_devices",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: strings.Join(rules.Devices, ", "),
+			Help:  "Comma separated device classes (" + strings.Join(cmsstore.BlockVisibilityDevices(), ", ") + "). Leave empty for all the devices.",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Query Parameter",
+			Name:  "treectl_visibility_query_param",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: rules.QueryParam,
+			Help:  "The query parameter which must be present, as name or name=value.",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Cookie",
+			Name:  "treectl_visibility_cookie",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: rules.Cookie,
+			Help:  "The cookie which must be present, as name or name=value.",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Logged In Visitors",
+			Name:  "treectl_visibility_logged_in",
+			Type:  form.FORM_FIELD_TYPE_SELECT,
+			Value: rules.LoggedIn,
+			Options: []form.FieldOption{
+				{Value: "All visitors", Key: ""},
+				{Value: "Logged in only", Key: cmsstore.BLOCK_VISIBILITY_LOGGED_IN_YES},
+				{Value: "Not logged in only", Key: cmsstore.BLOCK_VISIBILITY_LOGGED_IN_NO},
+			},
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Rollout Percentage",
+			Name:  "treectl_visibility_percentage",
+			Type:  form.FORM_FIELD_TYPE_NUMBER,
+			Value: percentage,
+			Help:  "The percentage of the visitors (1-99) the menu item is displayed to. Leave empty for all the visitors.",
+		}),
+	}
+}
+
+// nodeAttributesFromRequest sets the type, the attributes, the labels,
+// the URLs and the visibility rules posted with the modal form to the node
+func (t *treeControl) nodeAttributesFromRequest(r *http.Request, node *Node) error {
+	node.Type = req.GetStringTrimmed(r, "treectl_type")
+	node.Anchor = strings.TrimPrefix(req.GetStringTrimmed(r, "treectl_anchor"), "#")
+	node.EntityID = req.GetStringTrimmed(r, "treectl_entity_id")
+	node.BlockID = req.GetStringTrimmed(r, "treectl_block_id")
+	node.Icon = req.GetStringTrimmed(r, "treectl_icon")
+	node.CSSClass = req.GetStringTrimmed(r, "treectl_css_class")
+	node.Rel = req.GetStringTrimmed(r, "treectl_rel")
+
+	labels := map[string]string{}
+	urls := map[string]string{}
+	for language := range t.languages {
+		if label := req.GetStringTrimmed(r, "treectl_label_"+language); label != "" {
+			labels[language] = label
+		}
+		if url := req.GetStringTrimmed(r, "treectl_url_"+language); url != "" {
+			urls[language] = url
+		}
+	}
+	node.Labels = labels
+	node.URLs = urls
+
+	node.Visibility = cmsstore.BlockVisibility{
+		DateFrom:   req.GetStringTrimmed(r, "treectl_visibility_date_from"),
+		DateTo:     req.GetStringTrimmed(r, "treectl_visibility_date_to"),
+		Languages:  splitVisibilityList(req.GetStringTrimmed(r, "treectl_visibility_languages")),
+		Devices:    splitVisibilityList(strings.ToLower(req.GetStringTrimmed(r, "treectl_visibility_devices"))),
+		QueryParam: req.GetStringTrimmed(r, "treectl_visibility_query_param"),
+		Cookie:     req.GetStringTrimmed(r, "treectl_visibility_cookie"),
+		LoggedIn:   req.GetStringTrimmed(r, "treectl_visibility_logged_in"),
+		Percentage: cast.ToInt(req.GetStringTrimmed(r, "treectl_visibility_percentage")),
+	}
+
+	return node.Visibility.Validate()
+}
+
+// splitVisibilityList splits the comma separated list, skipping
+// the empty and duplicate values
+func splitVisibilityList(value string) []string {
+	list := []string{}
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// visibilityDateInputValue converts the visibility date
+// to the value of a datetime-local input
+func visibilityDateInputValue(date string) string {
+	if len(date) < 16 {
+		return date
+	}
+
+	return strings.Replace(date[:16], " ", "T", 1)
+}
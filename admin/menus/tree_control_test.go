@@ -285,3 +285,78 @@ func Test_TreeControl_Render_JSONOutput2(t *testing.T) {
 		t.Errorf("Expected body to contain 'menu_items'")
 	}
 }
+
+func Test_TreeControl_Render_NodeUpdateAttributes(t *testing.T) {
+	treeJSON := `[
+		{"id":"1","name":"Home","page_id":"","parent_id":"","sequence":0,"target":"","url":"/"}
+	]`
+
+	control := initTreeControl(treeJSON, "/test", "menu_items")
+	control.languages = map[string]string{"fr": "French"}
+
+	req, _ := test.NewRequest("POST", "/test", test.NewRequestOptions{
+		GetValues: map[string][]string{
+			"treectl_action":               {"node_update"},
+			"treectl_node_id":              {"1"},
+			"treectl_name":                 {"Home"},
+			"treectl_url":                  {"/"},
+			"treectl_type":                 {cmsstore.MENU_ITEM_TYPE_ANCHOR},
+			"treectl_anchor":               {"#top"},
+			"treectl_icon":                 {"bi bi-house"},
+			"treectl_label_fr":             {"Accueil"},
+			"treectl_url_fr":               {"/fr"},
+			"treectl_visibility_languages": {"fr, en"},
+		},
+	})
+
+	html := control.Render(req).ToHTML()
+
+	if strings.Contains(html, "ERROR:") {
+		t.Fatalf("Expected body to not contain 'ERROR:', got: %s", html)
+	}
+
+	for _, want := range []string{`anchor`, `top`, `bi bi-house`, `Accueil`, `/fr`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected body to contain %q", want)
+		}
+	}
+
+	req, _ = test.NewRequest("POST", "/test", test.NewRequestOptions{
+		GetValues: map[string][]string{
+			"treectl_action":               {"node_update"},
+			"treectl_node_id":              {"1"},
+			"treectl_name":                 {"Home"},
+			"treectl_visibility_logged_in": {"maybe"},
+		},
+	})
+
+	html = control.Render(req).ToHTML()
+
+	if !strings.Contains(html, "ERROR:") {
+		t.Errorf("Expected an error for invalid visibility rules")
+	}
+}
+
+func Test_TreeControl_Render_NodeUpdateModalAttributes(t *testing.T) {
+	treeJSON := `[
+		{"id":"1","name":"Home","page_id":"","parent_id":"","sequence":0,"target":"","url":"/","icon":"bi bi-house","labels":{"fr":"Accueil"},"urls":{"fr":"/fr/accueil"}}
+	]`
+
+	control := initTreeControl(treeJSON, "/test", "menu_items")
+	control.languages = map[string]string{"fr": "French"}
+
+	req, _ := test.NewRequest("POST", "/test", test.NewRequestOptions{
+		GetValues: map[string][]string{
+			"treectl_action":  {"node_update_modal"},
+			"treectl_node_id": {"1"},
+		},
+	})
+
+	html := control.Render(req).ToHTML()
+
+	for _, want := range []string{`treectl_type`, `treectl_icon`, `bi bi-house`, `treectl_label_fr`, `Accueil`, `treectl_url_fr`, `/fr/accueil`, `treectl_visibility_logged_in`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected body to contain %q", want)
+		}
+	}
+}
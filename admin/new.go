@@ -32,6 +32,10 @@ type AdminOptions struct {
 	// Store is the cmsstore.StoreInterface to use by the admin panel
 	Store cmsstore.StoreInterface
 
+	// BlockTypeRegistry is the registry the block types are looked up in.
+	// Optional, defaults to the registry of the store
+	BlockTypeRegistry *cmsstore.BlockTypeRegistry
+
 	AdminHomeURL    string
 	MediaManagerURL string
 	PaddingTopPx    int
@@ -56,6 +60,7 @@ func New(options AdminOptions) (*admin, error) {
 		blockEditorDefinitions: options.BlockEditorDefinitions,
 		logger:                 options.Logger,
 		store:                  options.Store,
+		blockTypeRegistry:      lo.Ternary(options.BlockTypeRegistry != nil, options.BlockTypeRegistry, options.Store.BlockTypeRegistry()),
 		funcLayout:             options.FuncLayout,
 		adminHomeURL:           options.AdminHomeURL,
 		mediaManagerURL:        options.MediaManagerURL,
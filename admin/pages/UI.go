@@ -26,6 +26,8 @@ type UiInterface interface {
 	PageCreate(w http.ResponseWriter, r *http.Request)
 	PageManager(w http.ResponseWriter, r *http.Request)
 	PageDelete(w http.ResponseWriter, r *http.Request)
+	PageSeoReport(w http.ResponseWriter, r *http.Request)
+	PageTree(w http.ResponseWriter, r *http.Request)
 	PageUpdate(w http.ResponseWriter, r *http.Request)
 	PageVersioning(w http.ResponseWriter, r *http.Request)
 }
@@ -84,6 +86,20 @@ func (ui ui) PageDelete(w http.ResponseWriter, r *http.Request) {
 	ui.PageManager(w, r)
 }
 
+func (ui ui) PageSeoReport(w http.ResponseWriter, r *http.Request) {
+	controller := NewPageSeoReportController(ui)
+	html := controller.Handler(w, r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(html))
+}
+
+func (ui ui) PageTree(w http.ResponseWriter, r *http.Request) {
+	controller := NewPageTreeController(ui)
+	html := controller.Handler(w, r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(html))
+}
+
 func (ui ui) PageUpdate(w http.ResponseWriter, r *http.Request) {
 	controller := pageUpdate.NewPageUpdateController(ui)
 	html := controller.Handler(w, r)
@@ -43,7 +43,19 @@ func handleRenderPage(ui shared.UiInterface, store cmsstore.StoreInterface, w ht
 		HTML("New Page").
 		ID("btn-page-new")
 
-	actionButtons = actionButtons.Child(buttonPageNew)
+	buttonPageTree := hb.Hyperlink().
+		Class("btn btn-secondary d-inline-flex align-items-center").
+		Child(hb.I().Class("bi bi-diagram-3 me-2")).
+		HTML("Page Tree").
+		Href(shared.URLR(r, shared.PathPagesPageTree, nil))
+
+	buttonSeoReport := hb.Hyperlink().
+		Class("btn btn-secondary d-inline-flex align-items-center").
+		Child(hb.I().Class("bi bi-clipboard-check me-2")).
+		HTML("SEO Report").
+		Href(shared.URLR(r, shared.PathPagesPageSeoReport, nil))
+
+	actionButtons = actionButtons.Child(buttonSeoReport).Child(buttonPageTree).Child(buttonPageNew)
 
 	heading := hb.Heading1().HTML("Page Manager").Child(actionButtons)
 
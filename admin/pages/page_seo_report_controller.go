@@ -0,0 +1,212 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dracory/api"
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/samber/lo"
+)
+
+// == CONTROLLER ==============================================================
+
+// pageSeoReportController shows the SEO audit of the pages of a site
+// (see cmsstore.StoreInterface.SiteSeoAudit), the pages with errors first
+type pageSeoReportController struct {
+	ui UiInterface
+}
+
+// == CONSTRUCTOR =============================================================
+
+func NewPageSeoReportController(ui UiInterface) *pageSeoReportController {
+	return &pageSeoReportController{
+		ui: ui,
+	}
+}
+
+func (controller *pageSeoReportController) Handler(w http.ResponseWriter, r *http.Request) string {
+	data, errorMessage := controller.prepareData(r)
+
+	if errorMessage != "" {
+		return api.Error(errorMessage).ToString()
+	}
+
+	options := struct {
+		Styles     []string
+		StyleURLs  []string
+		Scripts    []string
+		ScriptURLs []string
+	}{}
+
+	return controller.ui.Layout(w, r, "SEO Report | CMS", controller.page(data).ToHTML(), options)
+}
+
+func (controller *pageSeoReportController) page(data pageSeoReportControllerData) hb.TagInterface {
+	adminHeader := shared.AdminHeader(controller.ui.Store(), controller.ui.Logger(), data.request)
+
+	breadcrumbs := shared.AdminBreadcrumbs(data.request, []shared.Breadcrumb{
+		{
+			Name: "Page Manager",
+			URL:  shared.URLR(data.request, shared.PathPagesPageManager, nil),
+		},
+		{
+			Name: "SEO Report",
+			URL:  shared.URLR(data.request, shared.PathPagesPageSeoReport, map[string]string{"site_id": data.siteID}),
+		},
+	}, struct{ SiteList []cmsstore.SiteInterface }{
+		SiteList: data.siteList,
+	})
+
+	buttonBack := hb.Hyperlink().
+		Class("btn btn-secondary ms-2 float-end").
+		Child(hb.I().Class("bi bi-chevron-left").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
+		HTML("Back").
+		Href(shared.URLR(data.request, shared.PathPagesPageManager, nil))
+
+	pageTitle := hb.Heading1().
+		HTML("CMS. SEO Report").
+		Child(buttonBack)
+
+	siteSelect := hb.Select().
+		Class("form-select").
+		Name("site_id").
+		OnChange(`window.location.href = '` + shared.URLR(data.request, shared.PathPagesPageSeoReport, nil) + `&site_id=' + encodeURIComponent(this.value);`).
+		Children(lo.Map(data.siteList, func(site cmsstore.SiteInterface, _ int) hb.TagInterface {
+			return hb.Option().
+				Value(site.ID()).
+				AttrIf(site.ID() == data.siteID, "selected", "selected").
+				Text(site.Name())
+		}))
+
+	errorCount, warningCount := 0, 0
+	for _, report := range data.reports {
+		errorCount += len(controller.issues(report, cmsstore.SEO_AUDIT_SEVERITY_ERROR))
+		warningCount += len(controller.issues(report, cmsstore.SEO_AUDIT_SEVERITY_WARNING))
+	}
+
+	summary := hb.Div().
+		Class("col-md-8 text-muted").
+		Text(strconv.Itoa(len(data.reports)) + " pages audited, " +
+			strconv.Itoa(errorCount) + " errors, " +
+			strconv.Itoa(warningCount) + " warnings. The headings and the images are checked in the page content.")
+
+	return hb.Div().
+		Class("container").
+		Child(breadcrumbs).
+		Child(hb.HR()).
+		Child(adminHeader).
+		Child(hb.HR()).
+		Child(pageTitle).
+		Child(hb.Div().
+			Class("row mb-3").
+			Child(hb.Div().Class("col-md-4").Child(siteSelect)).
+			Child(summary)).
+		Child(controller.table(data))
+}
+
+// table lists the pages with their issues, the pages with errors first,
+// then the pages with warnings
+func (controller *pageSeoReportController) table(data pageSeoReportControllerData) hb.TagInterface {
+	reports := append(
+		lo.Filter(data.reports, func(report cmsstore.SeoAuditReport, _ int) bool {
+			return len(controller.issues(report, cmsstore.SEO_AUDIT_SEVERITY_ERROR)) > 0
+		}),
+		lo.Filter(data.reports, func(report cmsstore.SeoAuditReport, _ int) bool {
+			return len(controller.issues(report, cmsstore.SEO_AUDIT_SEVERITY_ERROR)) == 0
+		})...)
+
+	tbody := hb.Tbody()
+
+	for _, report := range reports {
+		page := report.Page
+
+		issues := hb.UL().Class("list-unstyled mb-0")
+		for _, issue := range report.Issues {
+			issues.Child(hb.LI().
+				Child(hb.Span().
+					Class(lo.Ternary(issue.Severity == cmsstore.SEO_AUDIT_SEVERITY_ERROR, "badge bg-danger me-2", "badge bg-warning text-dark me-2")).
+					Text(issue.Check)).
+				Text(issue.Message))
+		}
+
+		if len(report.Issues) == 0 {
+			issues.Child(hb.LI().Class("text-success").Text("No issues found"))
+		}
+
+		tbody.Child(hb.TR().
+			Child(hb.TD().
+				Child(hb.Hyperlink().
+					Text(lo.Ternary(page.Name() == "", page.ID(), page.Name())).
+					Href(shared.URLR(data.request, shared.PathPagesPageUpdate, map[string]string{
+						"page_id": page.ID(),
+						"view":    "seo",
+					}))).
+				Child(hb.Div().Class("text-muted").Style("font-size: 12px;").Text(page.Alias()))).
+			Child(hb.TD().Child(issues)))
+	}
+
+	return hb.Table().
+		Class("table table-striped table-hover").
+		Child(hb.Thead().
+			Child(hb.TR().
+				Child(hb.TH().Style("width: 30%;").Text("Page")).
+				Child(hb.TH().Text("Issues")))).
+		Child(tbody)
+}
+
+// issues returns the issues of the report with the severity
+func (controller *pageSeoReportController) issues(report cmsstore.SeoAuditReport, severity string) []cmsstore.SeoAuditIssue {
+	return lo.Filter(report.Issues, func(issue cmsstore.SeoAuditIssue, _ int) bool {
+		return issue.Severity == severity
+	})
+}
+
+func (controller *pageSeoReportController) prepareData(r *http.Request) (data pageSeoReportControllerData, errorMessage string) {
+	var err error
+	data.request = r
+	data.siteID = req.GetStringTrimmed(r, "site_id")
+
+	data.siteList, err = controller.ui.Store().SiteList(r.Context(), cmsstore.SiteQuery().
+		SetOrderBy(cmsstore.COLUMN_NAME).
+		SetSortOrder(cmsstore.SORT_ORDER_ASC).
+		SetOffset(0).
+		SetLimit(100))
+
+	if err != nil {
+		controller.ui.Logger().Error("At pageSeoReportController > prepareData", "error", err.Error())
+		return data, "error retrieving sites"
+	}
+
+	if data.siteID == "" && len(data.siteList) > 0 {
+		data.siteID = data.siteList[0].ID()
+	}
+
+	data.reports = []cmsstore.SeoAuditReport{}
+
+	if data.siteID == "" {
+		return data, ""
+	}
+
+	data.reports, err = controller.ui.Store().SiteSeoAudit(r.Context(), data.siteID)
+
+	if err != nil {
+		controller.ui.Logger().Error("At pageSeoReportController > prepareData", "error", err.Error())
+		return data, "error auditing pages"
+	}
+
+	return data, ""
+}
+
+type pageSeoReportControllerData struct {
+	request *http.Request
+	siteID  string
+
+	siteList []cmsstore.SiteInterface
+
+	// reports are the SEO audits of the pages of the site, by name
+	reports []cmsstore.SeoAuditReport
+}
@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/cmsstore/testutils"
+	"github.com/dracory/test"
+	_ "modernc.org/sqlite"
+)
+
+func initPageSeoReportHandler(store cmsstore.StoreInterface) func(w http.ResponseWriter, r *http.Request) string {
+	return NewPageSeoReportController(UI(shared.UiConfig{
+		Layout: shared.Layout,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Store:  store,
+	})).Handler
+}
+
+func Test_PageSeoReportController_Index(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	about := seedTreeTestPage(t, store, site.ID(), "About Us", "/about", "")
+	about.SetTitle("About us, our company and the people behind it")
+
+	if err := store.PageUpdate(context.Background(), about); err != nil {
+		t.Fatalf("Failed to update page: %v", err)
+	}
+
+	contact := seedTreeTestPage(t, store, site.ID(), "Contact", "/contact", "")
+	contact.SetContent(`<a href="[[PAGE_URL_MISSING]]">Gone</a>`)
+
+	if err := store.PageUpdate(context.Background(), contact); err != nil {
+		t.Fatalf("Failed to update page: %v", err)
+	}
+
+	body, response, err := test.CallStringEndpoint(http.MethodGet, initPageSeoReportHandler(store), test.NewRequestOptions{
+		GetValues: url.Values{
+			"site_id": {site.ID()},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, response.StatusCode)
+	}
+
+	expected := []string{
+		"SEO Report",
+		"2 pages audited",
+		"About Us",
+		"Contact",
+		"Links to missing pages: MISSING",
+		"Title is missing",
+		"Canonical URL is missing",
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(body, s) {
+			t.Errorf("Expected body to contain %q", s)
+		}
+	}
+
+	// The pages with errors are listed first
+	if strings.Index(body, ">Contact<") > strings.Index(body, ">About Us<") {
+		t.Error("Expected the page with errors before the page with warnings")
+	}
+}
@@ -0,0 +1,268 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/dracory/api"
+	"github.com/dracory/cdn"
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+const ActionPageMove = "page_move"
+
+// == CONTROLLER ==============================================================
+
+// pageTreeController shows the pages of a site as a tree, where
+// pages are nested under their parent page and reordered (or moved
+// to another parent) by drag and drop
+type pageTreeController struct {
+	ui UiInterface
+}
+
+// == CONSTRUCTOR =============================================================
+
+func NewPageTreeController(ui UiInterface) *pageTreeController {
+	return &pageTreeController{
+		ui: ui,
+	}
+}
+
+func (controller *pageTreeController) Handler(w http.ResponseWriter, r *http.Request) string {
+	data, errorMessage := controller.prepareData(r)
+
+	if errorMessage != "" {
+		return api.Error(errorMessage).ToString()
+	}
+
+	if data.action == ActionPageMove {
+		return controller.movePage(r)
+	}
+
+	options := struct {
+		Styles     []string
+		StyleURLs  []string
+		Scripts    []string
+		ScriptURLs []string
+	}{
+		Styles: []string{
+			`.page-tree-list { list-style: none; min-height: 12px; padding-left: 0; }
+			.page-tree-list .page-tree-list { margin: 8px 0 0 24px; }
+			.page-tree-handle { cursor: move; }
+			.page-tree-placeholder { height: 40px; margin-bottom: 8px; border: 2px dashed #0d6efd; border-radius: 4px; }`,
+		},
+		Scripts: []string{
+			controller.script(data),
+		},
+		ScriptURLs: []string{
+			cdn.Jquery_3_7_1(),
+			cdn.JqueryUiJs_1_14_2(),
+			cdn.Sweetalert2_11(),
+		},
+	}
+
+	return controller.ui.Layout(w, r, "Page Tree | CMS", controller.page(data).ToHTML(), options)
+}
+
+// movePage moves a page under another page (or to the top level),
+// at the dropped position
+func (controller *pageTreeController) movePage(r *http.Request) string {
+	pageID := req.GetStringTrimmed(r, "page_id")
+	parentID := req.GetStringTrimmed(r, "parent_id")
+	position := cast.ToInt(req.GetStringTrimmed(r, "position"))
+
+	if pageID == "" {
+		return api.Error("page id is required").ToString()
+	}
+
+	if err := controller.ui.Store().PageMove(r.Context(), pageID, parentID, position); err != nil {
+		return api.Error(err.Error()).ToString()
+	}
+
+	return api.Success("page moved successfully").ToString()
+}
+
+func (controller *pageTreeController) page(data pageTreeControllerData) hb.TagInterface {
+	adminHeader := shared.AdminHeader(controller.ui.Store(), controller.ui.Logger(), data.request)
+
+	breadcrumbs := shared.AdminBreadcrumbs(data.request, []shared.Breadcrumb{
+		{
+			Name: "Page Manager",
+			URL:  shared.URLR(data.request, shared.PathPagesPageManager, nil),
+		},
+		{
+			Name: "Page Tree",
+			URL:  shared.URLR(data.request, shared.PathPagesPageTree, map[string]string{"site_id": data.siteID}),
+		},
+	}, struct{ SiteList []cmsstore.SiteInterface }{
+		SiteList: data.siteList,
+	})
+
+	buttonBack := hb.Hyperlink().
+		Class("btn btn-secondary ms-2 float-end").
+		Child(hb.I().Class("bi bi-chevron-left").Style("margin-top:-4px;margin-right:8px;font-size:16px;")).
+		HTML("Back").
+		Href(shared.URLR(data.request, shared.PathPagesPageManager, nil))
+
+	pageTitle := hb.Heading1().
+		HTML("CMS. Page Tree").
+		Child(buttonBack)
+
+	siteSelect := hb.Select().
+		Class("form-select").
+		Name("site_id").
+		OnChange(`window.location.href = '` + shared.URLR(data.request, shared.PathPagesPageTree, nil) + `&site_id=' + encodeURIComponent(this.value);`).
+		Children(lo.Map(data.siteList, func(site cmsstore.SiteInterface, _ int) hb.TagInterface {
+			return hb.Option().
+				Value(site.ID()).
+				AttrIf(site.ID() == data.siteID, "selected", "selected").
+				Text(site.Name())
+		}))
+
+	return hb.Div().
+		Class("container").
+		Child(breadcrumbs).
+		Child(hb.HR()).
+		Child(adminHeader).
+		Child(hb.HR()).
+		Child(pageTitle).
+		Child(hb.Div().
+			Class("row mb-3").
+			Child(hb.Div().Class("col-md-4").Child(siteSelect)).
+			Child(hb.Div().
+				Class("col-md-8 text-muted").
+				Text("Drag the pages by their handle to reorder them, or to nest them under another page. Pages with an automatic alias get the alias of their new parent as prefix."))).
+		Child(hb.Div().
+			Class("card").
+			Child(hb.Div().
+				Class("card-body").
+				Child(controller.pageList(data, "", data.tree))))
+}
+
+// pageList renders the tree nodes as a sortable list, every page has
+// a (possibly empty) list of its own, so pages can be dropped under it
+func (controller *pageTreeController) pageList(data pageTreeControllerData, parentID string, nodes []*cmsstore.PageTreeNode) hb.TagInterface {
+	list := hb.UL().
+		Class("page-tree-list").
+		Data("parent-id", parentID)
+
+	for _, node := range nodes {
+		page := node.Page
+
+		item := hb.LI().
+			Class("page-tree-item mb-2").
+			Data("page-id", page.ID()).
+			Child(hb.Div().
+				Class("border rounded bg-light px-2 py-1").
+				Child(hb.I().Class("bi bi-grip-vertical page-tree-handle me-2")).
+				Child(hb.Hyperlink().
+					Text(lo.Ternary(page.Name() == "", page.ID(), page.Name())).
+					Href(shared.URLR(data.request, shared.PathPagesPageUpdate, map[string]string{
+						"page_id": page.ID(),
+					}))).
+				Child(hb.Span().Class("text-muted ms-2").Style("font-size: 12px;").Text(page.Alias())).
+				ChildIf(cmsstore.PageAliasIsAuto(page), hb.Span().Class("badge bg-info ms-2").Style("font-size: 11px;").Text("auto alias")).
+				ChildIf(!page.IsActive(), hb.Span().Class("badge bg-secondary ms-2").Style("font-size: 11px;").Text(page.Status()))).
+			Child(controller.pageList(data, page.ID(), node.Children))
+
+		list.Child(item)
+	}
+
+	return list
+}
+
+// script makes the page lists sortable, posting each move
+func (controller *pageTreeController) script(data pageTreeControllerData) string {
+	moveURL := shared.URLR(data.request, shared.PathPagesPageTree, map[string]string{
+		"action": ActionPageMove,
+	})
+
+	return `
+$(function () {
+	$(".page-tree-list").sortable({
+		connectWith: ".page-tree-list",
+		handle: ".page-tree-handle",
+		placeholder: "page-tree-placeholder",
+		tolerance: "pointer",
+		update: function (event, ui) {
+			// Moving between lists triggers an update on both, only post once
+			if (this !== ui.item.parent()[0]) {
+				return;
+			}
+
+			$.post("` + moveURL + `", {
+				page_id: ui.item.data("page-id"),
+				parent_id: $(this).data("parent-id"),
+				position: ui.item.index()
+			}).done(function (response) {
+				response = typeof response === "string" ? JSON.parse(response) : response;
+				if (response.status !== "success") {
+					Swal.fire({icon: "error", text: response.message}).then(function () {
+						window.location.reload();
+					});
+				}
+			}).fail(function () {
+				Swal.fire({icon: "error", text: "Moving the page failed"}).then(function () {
+					window.location.reload();
+				});
+			});
+		}
+	});
+});`
+}
+
+func (controller *pageTreeController) prepareData(r *http.Request) (data pageTreeControllerData, errorMessage string) {
+	var err error
+	data.request = r
+	data.action = req.GetStringTrimmed(r, "action")
+	data.siteID = req.GetStringTrimmed(r, "site_id")
+
+	if data.action == ActionPageMove {
+		return data, ""
+	}
+
+	data.siteList, err = controller.ui.Store().SiteList(r.Context(), cmsstore.SiteQuery().
+		SetOrderBy(cmsstore.COLUMN_NAME).
+		SetSortOrder(cmsstore.SORT_ORDER_ASC).
+		SetOffset(0).
+		SetLimit(100))
+
+	if err != nil {
+		controller.ui.Logger().Error("At pageTreeController > prepareData", "error", err.Error())
+		return data, "error retrieving sites"
+	}
+
+	if data.siteID == "" && len(data.siteList) > 0 {
+		data.siteID = data.siteList[0].ID()
+	}
+
+	data.tree = []*cmsstore.PageTreeNode{}
+
+	if data.siteID == "" {
+		return data, ""
+	}
+
+	data.tree, err = controller.ui.Store().PageTree(r.Context(), data.siteID)
+
+	if err != nil {
+		controller.ui.Logger().Error("At pageTreeController > prepareData", "error", err.Error())
+		return data, "error retrieving pages"
+	}
+
+	return data, ""
+}
+
+type pageTreeControllerData struct {
+	request *http.Request
+	action  string
+	siteID  string
+
+	siteList []cmsstore.SiteInterface
+
+	// tree are the pages of the site, nested by parent
+	tree []*cmsstore.PageTreeNode
+}
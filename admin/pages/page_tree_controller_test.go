@@ -0,0 +1,148 @@
+package admin
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/admin/shared"
+	"github.com/dracory/cmsstore/testutils"
+	"github.com/dracory/test"
+	_ "modernc.org/sqlite"
+)
+
+func initPageTreeHandler(store cmsstore.StoreInterface) func(w http.ResponseWriter, r *http.Request) string {
+	return NewPageTreeController(UI(shared.UiConfig{
+		Layout: shared.Layout,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Store:  store,
+	})).Handler
+}
+
+func seedTreeTestPage(t *testing.T, store cmsstore.StoreInterface, siteID, name, alias, parentID string) cmsstore.PageInterface {
+	t.Helper()
+
+	page := cmsstore.NewPage().
+		SetName(name).
+		SetAlias(alias).
+		SetSiteID(siteID).
+		SetParentID(parentID).
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE)
+
+	if err := store.PageCreate(context.Background(), page); err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	return page
+}
+
+func Test_PageTreeController_Index(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	about := seedTreeTestPage(t, store, site.ID(), "About Us", "/about", "")
+	team := seedTreeTestPage(t, store, site.ID(), "Our Team", "/about/team", about.ID())
+
+	body, response, err := test.CallStringEndpoint(http.MethodGet, initPageTreeHandler(store), test.NewRequestOptions{})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, response.StatusCode)
+	}
+
+	expected := []string{
+		"Page Tree",
+		"About Us",
+		"Our Team",
+		"/about/team",
+		`data-parent-id="` + about.ID() + `"`,
+		`data-page-id="` + team.ID() + `"`,
+		"sortable",
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(body, s) {
+			t.Errorf("Expected body to contain %q", s)
+		}
+	}
+}
+
+func Test_PageTreeController_MovePage(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	about := seedTreeTestPage(t, store, site.ID(), "About Us", "/about", "")
+	team := seedTreeTestPage(t, store, site.ID(), "Our Team", "/team", "")
+
+	if err := cmsstore.SetPageAliasAuto(team, true); err != nil {
+		t.Fatalf("Failed to set alias auto: %v", err)
+	}
+	if err := store.PageUpdate(context.Background(), team); err != nil {
+		t.Fatalf("Failed to update page: %v", err)
+	}
+
+	handler := initPageTreeHandler(store)
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		PostValues: map[string][]string{
+			"action":    {ActionPageMove},
+			"page_id":   {team.ID()},
+			"parent_id": {about.ID()},
+			"position":  {"0"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	if !strings.Contains(body, "page moved successfully") {
+		t.Errorf("Expected body to contain 'page moved successfully', got: %s", body)
+	}
+
+	movedPage, err := store.PageFindByID(context.Background(), team.ID())
+	if err != nil {
+		t.Fatalf("Failed to find page: %v", err)
+	}
+	if movedPage.ParentID() != about.ID() {
+		t.Errorf("Expected parent %s, got %s", about.ID(), movedPage.ParentID())
+	}
+	if movedPage.Alias() != "/about/team" {
+		t.Errorf("Expected alias /about/team, got %s", movedPage.Alias())
+	}
+
+	// Moving a page under its own child is rejected
+	body, _, err = test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		PostValues: map[string][]string{
+			"action":    {ActionPageMove},
+			"page_id":   {about.ID()},
+			"parent_id": {team.ID()},
+			"position":  {"0"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to call endpoint: %v", err)
+	}
+
+	if !strings.Contains(body, "cannot be moved into itself") {
+		t.Errorf("Expected body to contain 'cannot be moved into itself', got: %s", body)
+	}
+}
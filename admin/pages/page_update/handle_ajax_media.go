@@ -64,6 +64,14 @@ func handleAjaxUploadMedia(store cmsstore.StoreInterface, w http.ResponseWriter,
 		return api.Error("No files uploaded").ToString()
 	}
 
+	page, err := store.PageFindByID(r.Context(), pageID)
+	if err != nil {
+		return api.Error("Failed to find page: " + err.Error()).ToString()
+	}
+	if page == nil {
+		return api.Error("Page not found").ToString()
+	}
+
 	existingFiles, _ := store.MediaListByEntityID(r.Context(), pageID, "page")
 	startSequence := len(existingFiles)
 
@@ -92,6 +100,7 @@ func handleAjaxUploadMedia(store cmsstore.StoreInterface, w http.ResponseWriter,
 		media := cmsstore.NewMedia().
 			SetEntityID(pageID).
 			SetEntityType("page").
+			SetSiteID(page.SiteID()).
 			SetTitle(fileHeader.Filename).
 			SetURL(dataURI).
 			SetType(contentType).
@@ -100,6 +109,13 @@ func handleAjaxUploadMedia(store cmsstore.StoreInterface, w http.ResponseWriter,
 			SetSequenceInt(startSequence + i).
 			SetStatus(cmsstore.MEDIA_STATUS_ACTIVE)
 
+		// The dimensions and resized variants of the images, for their srcset
+		if metas := mediaImageMetas(data); metas != nil {
+			if err := media.SetMetas(metas); err != nil {
+				return api.Error("Failed to set file metas: " + err.Error()).ToString()
+			}
+		}
+
 		if err := store.MediaCreate(r.Context(), media); err != nil {
 			return api.Error("Failed to save file record: " + err.Error()).ToString()
 		}
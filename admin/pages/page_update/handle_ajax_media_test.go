@@ -0,0 +1,144 @@
+package page_update
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/blocks/media"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+func Test_AjaxUploadMedia_ResponsiveImage(t *testing.T) {
+	store := initStore(t)
+	handler := initHandler(store)
+
+	seededPage, err := seedTestPage(store)
+	if err != nil {
+		t.Fatalf("Failed to seed page: %v", err)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 1200, 600))
+	for y := 0; y < 600; y++ {
+		for x := 0; x < 1200; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x % 256), G: uint8(y % 256), B: 128, A: 255})
+		}
+	}
+
+	var imageData bytes.Buffer
+	if err := png.Encode(&imageData, img); err != nil {
+		t.Fatalf("Failed to encode image: %v", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="files[]"; filename="hero.png"`)
+	header.Set("Content-Type", "image/png")
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatalf("Failed to create part: %v", err)
+	}
+
+	if _, err := part.Write(imageData.Bytes()); err != nil {
+		t.Fatalf("Failed to write part: %v", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close writer: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/?action="+actionUploadMedia+"&page_id="+seededPage.ID(), &body)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+
+	response := handler(httptest.NewRecorder(), r)
+
+	if !strings.Contains(response, `"status":"success"`) {
+		t.Fatalf("Expected success status, got: %s", response)
+	}
+
+	files, err := store.MediaListByEntityID(context.Background(), seededPage.ID(), "page")
+	if err != nil {
+		t.Fatalf("Failed to list media: %v", err)
+	}
+
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 media, got: %d", len(files))
+	}
+
+	uploaded := files[0]
+
+	if uploaded.SiteID() != testutils.SITE_01 {
+		t.Errorf("Expected the site of the page, got: %s", uploaded.SiteID())
+	}
+
+	if uploaded.Meta(cmsstore.MEDIA_META_WIDTH) != "1200" || uploaded.Meta(cmsstore.MEDIA_META_HEIGHT) != "600" {
+		t.Errorf("Expected the dimensions 1200x600, got: %sx%s", uploaded.Meta(cmsstore.MEDIA_META_WIDTH), uploaded.Meta(cmsstore.MEDIA_META_HEIGHT))
+	}
+
+	for width, height := range map[string]int{"480": 240, "960": 480} {
+		variant := uploaded.Meta(cmsstore.MEDIA_META_VARIANT_PREFIX + width)
+
+		if !strings.HasPrefix(variant, "data:image/png;base64,") {
+			t.Fatalf("Expected the %s variant as a PNG data URI, got: %.40s", width, variant)
+		}
+
+		config, err := png.DecodeConfig(strings.NewReader(mustDecodeDataURI(t, variant)))
+		if err != nil {
+			t.Fatalf("Failed to decode the %s variant: %v", width, err)
+		}
+
+		if config.Height != height {
+			t.Errorf("Expected the %s variant to be %d high, got: %d", width, height, config.Height)
+		}
+	}
+
+	if uploaded.Meta(cmsstore.MEDIA_META_VARIANT_PREFIX+"1440") != "" {
+		t.Error("Expected no variant wider than the image")
+	}
+
+	block := cmsstore.NewBlock().
+		SetSiteID(testutils.SITE_01).
+		SetType(cmsstore.BLOCK_TYPE_IMAGE)
+
+	if err := block.SetMeta(cmsstore.BLOCK_META_IMAGE_MEDIA_ID, uploaded.ID()); err != nil {
+		t.Fatalf("Failed to set meta: %v", err)
+	}
+
+	html, err := media.NewImageBlockType(store).Render(context.Background(), block)
+	if err != nil {
+		t.Fatalf("Failed to render: %v", err)
+	}
+
+	serveURL := uploaded.ServeURL()
+	expected := `srcset="` + serveURL + `?w=480 480w, ` + serveURL + `?w=960 960w, ` + serveURL + ` 1200w"`
+
+	if !strings.Contains(html, expected) {
+		t.Errorf("Expected %s, got: %s", expected, html)
+	}
+}
+
+func mustDecodeDataURI(t *testing.T, dataURI string) string {
+	t.Helper()
+
+	_, encoded, _ := strings.Cut(dataURI, "base64,")
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode data URI: %v", err)
+	}
+
+	return string(data)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/dracory/api"
 	"github.com/dracory/cmsstore"
@@ -20,12 +22,61 @@ func handleAjaxLoadSEO(store cmsstore.StoreInterface, w http.ResponseWriter, r *
 		return api.Error("Page not found").ToString()
 	}
 
+	schemaFAQ, err := cmsstore.PageSchemaFAQ(page)
+	if err != nil {
+		return api.Error("Failed to load the FAQ questions").ToString()
+	}
+
+	// The images of the page, for the Open Graph image
+	ogImages := []map[string]any{}
+	if store.MediaEnabled() {
+		files, err := store.MediaListByEntityID(r.Context(), page.ID(), "page")
+		if err != nil {
+			return api.Error("Failed to load media").ToString()
+		}
+
+		for _, f := range files {
+			if f.IsImage() {
+				ogImages = append(ogImages, map[string]any{
+					"id":        f.ID(),
+					"name":      f.Title(),
+					"serve_url": f.ServeURL(),
+				})
+			}
+		}
+	}
+
+	seoAudit, err := store.PageSeoAudit(r.Context(), page, "")
+	if err != nil {
+		slog.Error("Failed to audit the page SEO", "page_id", page.ID(), "error", err)
+		seoAudit = []cmsstore.SeoAuditIssue{}
+	}
+
 	return api.SuccessWithData("SEO data loaded successfully", map[string]any{
-		"alias":            page.Alias(),
-		"canonical_url":    page.CanonicalUrl(),
-		"meta_description": page.MetaDescription(),
-		"meta_keywords":    page.MetaKeywords(),
-		"meta_robots":      page.MetaRobots(),
+		"alias":                 page.Alias(),
+		"alias_auto":            cmsstore.PageAliasIsAuto(page),
+		"canonical_url":         page.CanonicalUrl(),
+		"meta_description":      page.MetaDescription(),
+		"meta_keywords":         page.MetaKeywords(),
+		"meta_robots":           page.MetaRobots(),
+		"schema_type":           page.Meta(cmsstore.PAGE_META_SCHEMA_TYPE),
+		"schema_types":          cmsstore.PageSchemaTypes(),
+		"schema_image":          page.Meta(cmsstore.PAGE_META_SCHEMA_IMAGE),
+		"schema_author":         page.Meta(cmsstore.PAGE_META_SCHEMA_AUTHOR),
+		"schema_sku":            page.Meta(cmsstore.PAGE_META_SCHEMA_SKU),
+		"schema_brand":          page.Meta(cmsstore.PAGE_META_SCHEMA_BRAND),
+		"schema_price":          page.Meta(cmsstore.PAGE_META_SCHEMA_PRICE),
+		"schema_price_currency": page.Meta(cmsstore.PAGE_META_SCHEMA_PRICE_CURRENCY),
+		"schema_faq":            schemaFAQ,
+		"og_title":              page.OpenGraphTitle(),
+		"og_description":        page.OpenGraphDescription(),
+		"og_image_media_id":     page.OpenGraphImageMediaID(),
+		"og_images":             ogImages,
+		"og_type":               page.OpenGraphType(),
+		"og_types":              cmsstore.PageOpenGraphTypes(),
+		"twitter_card":          page.TwitterCard(),
+		"twitter_cards":         cmsstore.PageTwitterCards(),
+		"seo_audit":             seoAudit,
 	}).ToString()
 }
 
@@ -35,10 +86,26 @@ func handleAjaxSaveSEO(store cmsstore.StoreInterface, w http.ResponseWriter, r *
 	var reqData struct {
 		PageID          string `json:"page_id"`
 		Alias           string `json:"page_alias"`
+		AliasAuto       bool   `json:"page_alias_auto"`
 		CanonicalURL    string `json:"page_canonical_url"`
 		MetaDescription string `json:"page_meta_description"`
 		MetaKeywords    string `json:"page_meta_keywords"`
 		MetaRobots      string `json:"page_meta_robots"`
+
+		SchemaType          string                        `json:"page_schema_type"`
+		SchemaImage         string                        `json:"page_schema_image"`
+		SchemaAuthor        string                        `json:"page_schema_author"`
+		SchemaSKU           string                        `json:"page_schema_sku"`
+		SchemaBrand         string                        `json:"page_schema_brand"`
+		SchemaPrice         string                        `json:"page_schema_price"`
+		SchemaPriceCurrency string                        `json:"page_schema_price_currency"`
+		SchemaFAQ           []cmsstore.PageSchemaQuestion `json:"page_schema_faq"`
+
+		OgTitle        string `json:"page_og_title"`
+		OgDescription  string `json:"page_og_description"`
+		OgImageMediaID string `json:"page_og_image_media_id"`
+		OgType         string `json:"page_og_type"`
+		TwitterCard    string `json:"page_twitter_card"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
@@ -53,6 +120,18 @@ func handleAjaxSaveSEO(store cmsstore.StoreInterface, w http.ResponseWriter, r *
 		return api.Error("Page ID is required").ToString()
 	}
 
+	if reqData.SchemaType != "" && !slices.Contains(cmsstore.PageSchemaTypes(), reqData.SchemaType) {
+		return api.Error("Schema type is invalid").ToString()
+	}
+
+	if reqData.OgType != "" && !slices.Contains(cmsstore.PageOpenGraphTypes(), reqData.OgType) {
+		return api.Error("Open Graph type is invalid").ToString()
+	}
+
+	if reqData.TwitterCard != "" && !slices.Contains(cmsstore.PageTwitterCards(), reqData.TwitterCard) {
+		return api.Error("Twitter card is invalid").ToString()
+	}
+
 	page, err := store.PageFindByID(r.Context(), reqData.PageID)
 	if err != nil || page == nil {
 		return api.Error("Page not found").ToString()
@@ -63,11 +142,45 @@ func handleAjaxSaveSEO(store cmsstore.StoreInterface, w http.ResponseWriter, r *
 	page.SetMetaDescription(reqData.MetaDescription)
 	page.SetMetaKeywords(reqData.MetaKeywords)
 	page.SetMetaRobots(reqData.MetaRobots)
+	page.SetOpenGraphTitle(strings.TrimSpace(reqData.OgTitle))
+	page.SetOpenGraphDescription(strings.TrimSpace(reqData.OgDescription))
+	page.SetOpenGraphImageMediaID(reqData.OgImageMediaID)
+	page.SetOpenGraphType(reqData.OgType)
+	page.SetTwitterCard(reqData.TwitterCard)
+
+	if err := cmsstore.SetPageAliasAuto(page, reqData.AliasAuto); err != nil {
+		return api.Error("Failed to save page SEO").ToString()
+	}
+
+	err = page.UpsertMetas(map[string]string{
+		cmsstore.PAGE_META_SCHEMA_TYPE:           reqData.SchemaType,
+		cmsstore.PAGE_META_SCHEMA_IMAGE:          strings.TrimSpace(reqData.SchemaImage),
+		cmsstore.PAGE_META_SCHEMA_AUTHOR:         strings.TrimSpace(reqData.SchemaAuthor),
+		cmsstore.PAGE_META_SCHEMA_SKU:            strings.TrimSpace(reqData.SchemaSKU),
+		cmsstore.PAGE_META_SCHEMA_BRAND:          strings.TrimSpace(reqData.SchemaBrand),
+		cmsstore.PAGE_META_SCHEMA_PRICE:          strings.TrimSpace(reqData.SchemaPrice),
+		cmsstore.PAGE_META_SCHEMA_PRICE_CURRENCY: strings.ToUpper(strings.TrimSpace(reqData.SchemaPriceCurrency)),
+	})
+
+	if err == nil {
+		err = cmsstore.SetPageSchemaFAQ(page, reqData.SchemaFAQ)
+	}
+
+	if err != nil {
+		return api.Error("Failed to save page SEO").ToString()
+	}
 
 	if err := store.PageUpdate(r.Context(), page); err != nil {
 		slog.Error("Failed to save page SEO", "error", err)
 		return api.Error("Failed to save page SEO").ToString()
 	}
 
+	// Derives the alias from the parent page (if automatic),
+	// and updates the automatic aliases of the child pages
+	if err := store.PageAliasesRefresh(r.Context(), page.ID()); err != nil {
+		slog.Error("Failed to refresh page aliases", "error", err)
+		return api.Error("Failed to refresh page aliases").ToString()
+	}
+
 	return api.Success("Page saved successfully").ToString()
 }
@@ -1,11 +1,13 @@
 package page_update
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"strings"
 	"testing"
 
+	"github.com/dracory/cmsstore"
 	"github.com/dracory/test"
 )
 
@@ -33,6 +35,44 @@ func Test_AjaxLoadSEO_Success(t *testing.T) {
 	}
 }
 
+func Test_AjaxLoadSEO_Audit(t *testing.T) {
+	store := initStore(t)
+	handler := initHandler(store)
+
+	seededPage, err := seedTestPage(store)
+	if err != nil {
+		t.Fatalf("Failed to seed page: %v", err)
+	}
+
+	seededPage.SetContent(`<img src="/logo.png"><a href="[[PAGE_URL_MISSING]]">Gone</a>`)
+	if err := store.PageUpdate(context.Background(), seededPage); err != nil {
+		t.Fatalf("Failed to update page: %v", err)
+	}
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"page_id": {seededPage.ID()},
+			"action":  {actionLoadSEO},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	expected := []string{
+		`"seo_audit":[`,
+		`"check":"image_alt"`,
+		`"message":"1 image(s) without alt text"`,
+		`"message":"Links to missing pages: MISSING"`,
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(body, e) {
+			t.Errorf("Expected body to contain %s, got: %s", e, body)
+		}
+	}
+}
+
 func Test_AjaxSaveSEO_Success(t *testing.T) {
 	store := initStore(t)
 	handler := initHandler(store)
@@ -63,3 +103,209 @@ func Test_AjaxSaveSEO_Success(t *testing.T) {
 		t.Fatalf("Expected success status, got: %s", body)
 	}
 }
+
+func Test_AjaxSaveSEO_AliasAuto(t *testing.T) {
+	store := initStore(t)
+	handler := initHandler(store)
+
+	seededPage, err := seedTestPage(store)
+	if err != nil {
+		t.Fatalf("Failed to seed page: %v", err)
+	}
+
+	parent := cmsstore.NewPage().
+		SetSiteID(seededPage.SiteID()).
+		SetName("About").
+		SetAlias("/about")
+
+	if err := store.PageCreate(context.Background(), parent); err != nil {
+		t.Fatalf("Failed to create parent page: %v", err)
+	}
+
+	if err := store.PageMove(context.Background(), seededPage.ID(), parent.ID(), 0); err != nil {
+		t.Fatalf("Failed to move page: %v", err)
+	}
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"page_id": {seededPage.ID()},
+			"action":  {actionSaveSEO},
+		},
+		JSONData: map[string]any{
+			"page_id":         seededPage.ID(),
+			"page_alias":      "/team",
+			"page_alias_auto": true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	if !strings.Contains(body, `"status":"success"`) {
+		t.Fatalf("Expected success status, got: %s", body)
+	}
+
+	page, err := store.PageFindByID(context.Background(), seededPage.ID())
+	if err != nil {
+		t.Fatalf("Failed to find page: %v", err)
+	}
+
+	if page.Alias() != "/about/team" {
+		t.Errorf("Expected alias /about/team, got %s", page.Alias())
+	}
+
+	if !cmsstore.PageAliasIsAuto(page) {
+		t.Error("Expected the alias to be automatic")
+	}
+}
+
+func Test_AjaxSaveSEO_Schema(t *testing.T) {
+	store := initStore(t)
+	handler := initHandler(store)
+
+	seededPage, err := seedTestPage(store)
+	if err != nil {
+		t.Fatalf("Failed to seed page: %v", err)
+	}
+
+	save := func(schemaType string) string {
+		body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+			GetValues: url.Values{
+				"page_id": {seededPage.ID()},
+				"action":  {actionSaveSEO},
+			},
+			JSONData: map[string]any{
+				"page_id":                    seededPage.ID(),
+				"page_alias":                 "/product",
+				"page_schema_type":           schemaType,
+				"page_schema_price":          " 19.99 ",
+				"page_schema_price_currency": "usd",
+				"page_schema_faq": []map[string]string{
+					{"question": "Shipping?", "answer": "Free"},
+					{"question": "", "answer": "Skipped"},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %s", err)
+		}
+		return body
+	}
+
+	if body := save("Recipe"); !strings.Contains(body, "Schema type is invalid") {
+		t.Fatalf("Expected the invalid schema type to be rejected, got: %s", body)
+	}
+
+	if body := save(cmsstore.PAGE_SCHEMA_TYPE_PRODUCT); !strings.Contains(body, `"status":"success"`) {
+		t.Fatalf("Expected success status, got: %s", body)
+	}
+
+	page, err := store.PageFindByID(context.Background(), seededPage.ID())
+	if err != nil || page == nil {
+		t.Fatalf("Failed to find page: %v", err)
+	}
+
+	if page.Meta(cmsstore.PAGE_META_SCHEMA_TYPE) != cmsstore.PAGE_SCHEMA_TYPE_PRODUCT {
+		t.Errorf("Expected the schema type to be saved, got %q", page.Meta(cmsstore.PAGE_META_SCHEMA_TYPE))
+	}
+	if page.Meta(cmsstore.PAGE_META_SCHEMA_PRICE) != "19.99" || page.Meta(cmsstore.PAGE_META_SCHEMA_PRICE_CURRENCY) != "USD" {
+		t.Errorf("Expected the trimmed price and currency, got %q %q", page.Meta(cmsstore.PAGE_META_SCHEMA_PRICE), page.Meta(cmsstore.PAGE_META_SCHEMA_PRICE_CURRENCY))
+	}
+
+	questions, err := cmsstore.PageSchemaFAQ(page)
+	if err != nil {
+		t.Fatalf("Failed to read the FAQ: %v", err)
+	}
+	if len(questions) != 1 || questions[0].Question != "Shipping?" {
+		t.Errorf("Expected only the complete question, got %v", questions)
+	}
+}
+
+func Test_AjaxSaveSEO_OpenGraph(t *testing.T) {
+	store := initStore(t)
+	handler := initHandler(store)
+
+	seededPage, err := seedTestPage(store)
+	if err != nil {
+		t.Fatalf("Failed to seed page: %v", err)
+	}
+
+	image := cmsstore.NewMedia().
+		SetEntityID(seededPage.ID()).
+		SetEntityType("page").
+		SetTitle("Cover").
+		SetType("image/png").
+		SetURL("https://example.com/cover.png")
+
+	if err := store.MediaCreate(context.Background(), image); err != nil {
+		t.Fatalf("Failed to create media: %v", err)
+	}
+
+	save := func(ogType, twitterCard string) string {
+		body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+			GetValues: url.Values{
+				"page_id": {seededPage.ID()},
+				"action":  {actionSaveSEO},
+			},
+			JSONData: map[string]any{
+				"page_id":                seededPage.ID(),
+				"page_alias":             "/cover",
+				"page_og_title":          " Share me ",
+				"page_og_description":    "Shared description",
+				"page_og_image_media_id": image.ID(),
+				"page_og_type":           ogType,
+				"page_twitter_card":      twitterCard,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %s", err)
+		}
+		return body
+	}
+
+	if body := save("video", ""); !strings.Contains(body, "Open Graph type is invalid") {
+		t.Fatalf("Expected the invalid Open Graph type to be rejected, got: %s", body)
+	}
+
+	if body := save("", "player"); !strings.Contains(body, "Twitter card is invalid") {
+		t.Fatalf("Expected the invalid Twitter card to be rejected, got: %s", body)
+	}
+
+	if body := save(cmsstore.PAGE_OG_TYPE_ARTICLE, cmsstore.PAGE_TWITTER_CARD_SUMMARY); !strings.Contains(body, `"status":"success"`) {
+		t.Fatalf("Expected success status, got: %s", body)
+	}
+
+	page, err := store.PageFindByID(context.Background(), seededPage.ID())
+	if err != nil || page == nil {
+		t.Fatalf("Failed to find page: %v", err)
+	}
+
+	if page.OpenGraphTitle() != "Share me" || page.OpenGraphDescription() != "Shared description" {
+		t.Errorf("Expected the trimmed title and the description, got %q %q", page.OpenGraphTitle(), page.OpenGraphDescription())
+	}
+	if page.OpenGraphImageMediaID() != image.ID() || page.OpenGraphType() != cmsstore.PAGE_OG_TYPE_ARTICLE || page.TwitterCard() != cmsstore.PAGE_TWITTER_CARD_SUMMARY {
+		t.Errorf("Expected the image, type and card to be saved, got %q %q %q", page.OpenGraphImageMediaID(), page.OpenGraphType(), page.TwitterCard())
+	}
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"page_id": {seededPage.ID()},
+			"action":  {actionLoadSEO},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	expected := []string{
+		`"og_title":"Share me"`,
+		`"og_images":[{"id":"` + image.ID() + `","name":"Cover"`,
+		`"og_types":["article","product","website"]`,
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(body, e) {
+			t.Errorf("Expected body to contain %s, got: %s", e, body)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package page_update
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strconv"
+
+	_ "image/gif"
+
+	"github.com/dracory/cmsstore"
+)
+
+// mediaVariantWidths are the widths of the resized variants created
+// for the uploaded images, used for their responsive srcset
+var mediaVariantWidths = []int{480, 960, 1440}
+
+// mediaVariantMaxPixels is the size above which no variants are created,
+// to keep the decoding of the uploads bounded
+const mediaVariantMaxPixels = 40_000_000
+
+// mediaImageMetas returns the media metas of an uploaded image,
+// its dimensions and its resized variants
+//
+// Business Logic:
+//   - files which are not images have no metas
+//   - the width and height are set for all the images
+//   - variants are only created for JPEG and PNG images, narrower than the image
+//   - the variants keep the format of the image, and are stored as data URIs
+//     like the uploads, under MEDIA_META_VARIANT_PREFIX and their width
+func mediaImageMetas(data []byte) map[string]string {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return nil
+	}
+
+	metas := map[string]string{
+		cmsstore.MEDIA_META_WIDTH:  strconv.Itoa(config.Width),
+		cmsstore.MEDIA_META_HEIGHT: strconv.Itoa(config.Height),
+	}
+
+	if format != "jpeg" && format != "png" {
+		return metas
+	}
+
+	if config.Width*config.Height > mediaVariantMaxPixels || config.Width <= mediaVariantWidths[0] {
+		return metas
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		return metas
+	}
+
+	for _, width := range mediaVariantWidths {
+		if width >= config.Width {
+			break
+		}
+
+		height := max(1, config.Height*width/config.Width)
+		variant := mediaImageResize(img, width, height)
+
+		var buffer bytes.Buffer
+
+		if format == "png" {
+			err = png.Encode(&buffer, variant)
+		} else {
+			err = jpeg.Encode(&buffer, variant, &jpeg.Options{Quality: 85})
+		}
+
+		if err != nil {
+			continue
+		}
+
+		metas[cmsstore.MEDIA_META_VARIANT_PREFIX+strconv.Itoa(width)] = "data:image/" + format + ";base64," +
+			base64.StdEncoding.EncodeToString(buffer.Bytes())
+	}
+
+	return metas
+}
+
+// mediaImageResize downscales the image to the given size,
+// each pixel is the average of the pixels of its area in the image
+func mediaImageResize(img image.Image, width int, height int) *image.RGBA {
+	bounds := img.Bounds()
+	resized := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, count uint64
+
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					count++
+				}
+			}
+
+			resized.Set(x, y, color.RGBA64{
+				R: uint16(r / count),
+				G: uint16(g / count),
+				B: uint16(b / count),
+				A: uint16(a / count),
+			})
+		}
+	}
+
+	return resized
+}
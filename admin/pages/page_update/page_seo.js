@@ -4,16 +4,41 @@ const PageSEOApp = {
       loading: true,
       saving: false,
       pageId: '',
+      schemaTypes: [],
+      ogImages: [],
+      ogTypes: [],
+      twitterCards: [],
+      seoAudit: [],
       form: {
         alias: '',
+        aliasAuto: false,
         canonicalUrl: '',
         metaDescription: '',
         metaKeywords: '',
-        metaRobots: ''
+        metaRobots: '',
+        schemaType: '',
+        schemaImage: '',
+        schemaAuthor: '',
+        schemaSku: '',
+        schemaBrand: '',
+        schemaPrice: '',
+        schemaPriceCurrency: '',
+        schemaFaq: [],
+        ogTitle: '',
+        ogDescription: '',
+        ogImageMediaId: '',
+        ogType: '',
+        twitterCard: ''
       }
     };
   },
 
+  computed: {
+    ogImage() {
+      return this.ogImages.find(image => image.id === this.form.ogImageMediaId) || null;
+    }
+  },
+
   mounted() {
     if (typeof pageID !== 'undefined') {
       this.pageId = pageID;
@@ -22,6 +47,18 @@ const PageSEOApp = {
   },
 
   methods: {
+    addQuestion() {
+      this.form.schemaFaq.push({ question: '', answer: '' });
+    },
+
+    seoAuditClass(issue) {
+      return issue.severity === 'error' ? 'list-group-item-danger' : 'list-group-item-warning';
+    },
+
+    removeQuestion(index) {
+      this.form.schemaFaq.splice(index, 1);
+    },
+
     async loadSEO() {
       this.loading = true;
       try {
@@ -33,10 +70,29 @@ const PageSEOApp = {
         const data = await response.json();
         if (data.status === 'success') {
           this.form.alias = data.data?.alias || '';
+          this.form.aliasAuto = data.data?.alias_auto === true;
           this.form.canonicalUrl = data.data?.canonical_url || '';
           this.form.metaDescription = data.data?.meta_description || '';
           this.form.metaKeywords = data.data?.meta_keywords || '';
           this.form.metaRobots = data.data?.meta_robots || '';
+          this.schemaTypes = data.data?.schema_types || [];
+          this.form.schemaType = data.data?.schema_type || '';
+          this.form.schemaImage = data.data?.schema_image || '';
+          this.form.schemaAuthor = data.data?.schema_author || '';
+          this.form.schemaSku = data.data?.schema_sku || '';
+          this.form.schemaBrand = data.data?.schema_brand || '';
+          this.form.schemaPrice = data.data?.schema_price || '';
+          this.form.schemaPriceCurrency = data.data?.schema_price_currency || '';
+          this.form.schemaFaq = data.data?.schema_faq || [];
+          this.ogImages = data.data?.og_images || [];
+          this.ogTypes = data.data?.og_types || [];
+          this.twitterCards = data.data?.twitter_cards || [];
+          this.form.ogTitle = data.data?.og_title || '';
+          this.form.ogDescription = data.data?.og_description || '';
+          this.form.ogImageMediaId = data.data?.og_image_media_id || '';
+          this.form.ogType = data.data?.og_type || '';
+          this.form.twitterCard = data.data?.twitter_card || '';
+          this.seoAudit = data.data?.seo_audit || [];
         } else {
           Swal.fire({ icon: 'error', title: 'Error', text: data.message || 'Failed to load SEO data' });
         }
@@ -58,14 +114,29 @@ const PageSEOApp = {
           body: JSON.stringify({
             page_id: this.pageId,
             page_alias: this.form.alias,
+            page_alias_auto: this.form.aliasAuto,
             page_canonical_url: this.form.canonicalUrl,
             page_meta_description: this.form.metaDescription,
             page_meta_keywords: this.form.metaKeywords,
-            page_meta_robots: this.form.metaRobots
+            page_meta_robots: this.form.metaRobots,
+            page_schema_type: this.form.schemaType,
+            page_schema_image: this.form.schemaImage,
+            page_schema_author: this.form.schemaAuthor,
+            page_schema_sku: this.form.schemaSku,
+            page_schema_brand: this.form.schemaBrand,
+            page_schema_price: this.form.schemaPrice,
+            page_schema_price_currency: this.form.schemaPriceCurrency,
+            page_schema_faq: this.form.schemaFaq,
+            page_og_title: this.form.ogTitle,
+            page_og_description: this.form.ogDescription,
+            page_og_image_media_id: this.form.ogImageMediaId,
+            page_og_type: this.form.ogType,
+            page_twitter_card: this.form.twitterCard
           })
         });
         const data = await response.json();
         if (data.status === 'success') {
+          await this.loadSEO();
           Swal.fire({ icon: 'success', title: 'Success', text: 'Page saved successfully', position: 'top-end', timer: 3000, timerProgressBar: true, showConfirmButton: false });
         } else {
           Swal.fire({ icon: 'error', title: 'Error', text: data.message || 'Failed to save SEO data' });
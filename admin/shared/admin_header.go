@@ -18,6 +18,14 @@ func AdminHeader(store cmsstore.StoreInterface, logger *slog.Logger, r *http.Req
 		HTML("Blocks ").
 		Href(URLR(r, PathBlocksBlockManager, nil)).
 		Class("nav-link")
+	linkExperiments := hb.Hyperlink().
+		HTML("Experiments").
+		Href(URLR(r, PathExperimentsExperimentManager, nil)).
+		Class("nav-link")
+	linkForms := hb.Hyperlink().
+		HTML("Forms ").
+		Href(URLR(r, PathFormsSubmissionManager, nil)).
+		Class("nav-link")
 	linkMenus := hb.NewHyperlink().
 		HTML("Menus ").
 		Href(URLR(r, PathMenusMenuManager, nil)).
@@ -133,6 +141,30 @@ func AdminHeader(store cmsstore.StoreInterface, logger *slog.Logger, r *http.Req
 				Class("badge bg-secondary").
 				HTML(cast.ToString(blocksCount)))))
 
+	ulNav.Child(hb.
+		LI().
+		Class("nav-item").
+		Child(linkExperiments))
+
+	if store.FormsEnabled() {
+		newSubmissionsCount, err := store.FormSubmissionCount(r.Context(), cmsstore.FormSubmissionQuery().
+			SetStatus(cmsstore.FORM_SUBMISSION_STATUS_NEW))
+
+		if err != nil {
+			logger.Error(err.Error())
+			newSubmissionsCount = -1
+		}
+
+		ulNav.Child(hb.
+			LI().
+			Class("nav-item").
+			Child(linkForms.
+				Child(hb.NewSpan().
+					Class("badge bg-secondary").
+					Title("New submissions").
+					HTML(cast.ToString(newSubmissionsCount)))))
+	}
+
 	// if cms.widgetsEnabled {
 	// 	ulNav.AddChild(hb.NewLI().Class("nav-item").AddChild(linkWidgets.AddChild(hb.NewSpan().Class("badge bg-secondary").HTML(strconv.FormatInt(widgetsCount, 10)))))
 	// }
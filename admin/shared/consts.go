@@ -11,9 +11,16 @@ const PathHome = "/"
 
 const PathBlocksBlockCreate = "/blocks/block-create"
 const PathBlocksBlockDelete = "/blocks/block-delete"
+const PathBlocksBlockLayout = "/blocks/block-layout"
 const PathBlocksBlockManager = "/blocks/block-manager"
 const PathBlocksBlockUpdate = "/blocks/block-update"
 const PathBlocksBlockVersioning = "/blocks/block-versioning"
+const PathExperimentsExperimentManager = "/experiments/experiment-manager"
+const PathExperimentsExperimentUpdate = "/experiments/experiment-update"
+const PathFormsSubmissionDelete = "/forms/submission-delete"
+const PathFormsSubmissionExport = "/forms/submission-export"
+const PathFormsSubmissionManager = "/forms/submission-manager"
+const PathFormsSubmissionView = "/forms/submission-view"
 const PathMenusMenuCreate = "/menus/menu-create"
 const PathMenusMenuDelete = "/menus/menu-delete"
 const PathMenusMenuManager = "/menus/menu-manager"
@@ -22,6 +29,8 @@ const PathMenusMenuVersioning = "/menus/menu-versioning"
 const PathPagesPageCreate = "/pages/page-create"
 const PathPagesPageDelete = "/pages/page-delete"
 const PathPagesPageManager = "/pages/page-manager"
+const PathPagesPageSeoReport = "/pages/page-seo-report"
+const PathPagesPageTree = "/pages/page-tree"
 const PathPagesPageUpdate = "/pages/page-update"
 const PathPagesPageVersioning = "/pages/page-versioning"
 const PathSitesSiteCreate = "/sites/site-create"
@@ -19,4 +19,8 @@ type UiConfig struct {
 	}) string
 	Logger *slog.Logger
 	Store  cmsstore.StoreInterface
+
+	// BlockTypeRegistry is the registry the block types are looked up in,
+	// defaults to the registry of the store
+	BlockTypeRegistry *cmsstore.BlockTypeRegistry
 }
@@ -315,6 +315,24 @@ func (siteUpdateController) fieldsSettings(data siteUpdateControllerData) []form
 		},
 	})
 
+	fieldPlaceholderEscaping := form.NewField(form.FieldOptions{
+		Label: "Placeholder Escaping",
+		Name:  "site_placeholder_escaping",
+		Type:  form.FORM_FIELD_TYPE_SELECT,
+		Value: data.formPlaceholderEscaping,
+		Help:  "How placeholder values (i.e. [[PageTitle]]) are escaped, when no modifier is given. A modifier overrides it for a single placeholder, i.e. [[PageTitle|attr]]. The page content is never escaped, unless a modifier is given.",
+		Options: []form.FieldOption{
+			{
+				Value: "HTML (recommended)",
+				Key:   cmsstore.PLACEHOLDER_ESCAPING_HTML,
+			},
+			{
+				Value: "Raw (no escaping, legacy)",
+				Key:   cmsstore.PLACEHOLDER_ESCAPING_RAW,
+			},
+		},
+	})
+
 	fieldSiteID := form.NewField(form.FieldOptions{
 		Label:    "Site Reference / ID",
 		Name:     "site_id",
@@ -337,6 +355,7 @@ func (siteUpdateController) fieldsSettings(data siteUpdateControllerData) []form
 		fieldStatus,
 		fieldSiteName,
 		fieldDomainNames,
+		fieldPlaceholderEscaping,
 		fieldMemo,
 		fieldSiteID,
 		fieldView,
@@ -347,6 +366,27 @@ func (siteUpdateController) fieldsSettings(data siteUpdateControllerData) []form
 
 func (siteUpdateController) fieldsSEO(data siteUpdateControllerData) []form.FieldInterface {
 	fieldsSEO := []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Label: "Organization Name",
+			Name:  "site_organization_name",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: data.formOrganizationName,
+			Help:  "The public name of the organization behind this site, used in the structured data ([[PageJsonLd]]) of the pages. Defaults to the site name.",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Organization Logo URL",
+			Name:  "site_organization_logo",
+			Type:  form.FORM_FIELD_TYPE_STRING,
+			Value: data.formOrganizationLogo,
+			Help:  "The URL of the logo of the organization, shown by search engines.",
+		}),
+		form.NewField(form.FieldOptions{
+			Label: "Organization Profiles",
+			Name:  "site_organization_same_as",
+			Type:  form.FORM_FIELD_TYPE_TEXTAREA,
+			Value: data.formOrganizationSameAs,
+			Help:  "The URLs of the profiles of the organization (i.e. social networks), one per line.",
+		}),
 		form.NewField(form.FieldOptions{
 			Label:    "Website ID",
 			Name:     "site_id",
@@ -372,18 +412,34 @@ func (controller siteUpdateController) saveSite(r *http.Request, data siteUpdate
 	data.formTitle = req.GetStringTrimmed(r, "site_title")
 	data.formDomainNames = controller.requestMapToDomainNames(r)
 
+	if escaping := req.GetStringTrimmed(r, "site_placeholder_escaping"); escaping != "" {
+		data.formPlaceholderEscaping = escaping
+	}
+
 	if data.view == VIEW_SETTINGS {
 		if data.formStatus == "" {
 			data.formErrorMessage = "Status is required"
 			return data, ""
 		}
+
+		if !lo.Contains([]string{cmsstore.PLACEHOLDER_ESCAPING_HTML, cmsstore.PLACEHOLDER_ESCAPING_RAW}, data.formPlaceholderEscaping) {
+			data.formErrorMessage = "Placeholder escaping is invalid"
+			return data, ""
+		}
 	}
 
 	if data.view == VIEW_SETTINGS {
 		data.site.SetMemo(data.formMemo)
 		data.site.SetName(data.formName)
 		data.site.SetStatus(data.formStatus)
-		_, err := data.site.SetDomainNames(data.formDomainNames)
+		err := data.site.SetMeta(cmsstore.SITE_META_PLACEHOLDER_ESCAPING, data.formPlaceholderEscaping)
+
+		if err != nil {
+			data.formErrorMessage = err.Error()
+			return data, ""
+		}
+
+		_, err = data.site.SetDomainNames(data.formDomainNames)
 
 		if err != nil {
 			data.formErrorMessage = err.Error()
@@ -392,7 +448,16 @@ func (controller siteUpdateController) saveSite(r *http.Request, data siteUpdate
 	}
 
 	if data.view == VIEW_SEO {
-		// nothing here yet
+		err := data.site.UpsertMetas(map[string]string{
+			cmsstore.SITE_META_ORGANIZATION_NAME:    req.GetStringTrimmed(r, "site_organization_name"),
+			cmsstore.SITE_META_ORGANIZATION_LOGO:    req.GetStringTrimmed(r, "site_organization_logo"),
+			cmsstore.SITE_META_ORGANIZATION_SAME_AS: req.GetStringTrimmed(r, "site_organization_same_as"),
+		})
+
+		if err != nil {
+			data.formErrorMessage = err.Error()
+			return data, ""
+		}
 	}
 
 	err := controller.ui.Store().SiteUpdate(data.request.Context(), data.site)
@@ -452,6 +517,16 @@ func (controller siteUpdateController) prepareDataAndValidate(r *http.Request) (
 	data.formName = data.site.Name()
 	data.formMemo = data.site.Memo()
 	data.formStatus = data.site.Status()
+	data.formPlaceholderEscaping = data.site.Meta(cmsstore.SITE_META_PLACEHOLDER_ESCAPING)
+	data.formOrganizationName = data.site.Meta(cmsstore.SITE_META_ORGANIZATION_NAME)
+	data.formOrganizationLogo = data.site.Meta(cmsstore.SITE_META_ORGANIZATION_LOGO)
+	data.formOrganizationSameAs = data.site.Meta(cmsstore.SITE_META_ORGANIZATION_SAME_AS)
+
+	if data.formPlaceholderEscaping == "" {
+		// Sites created before escaping was introduced render placeholders raw
+		data.formPlaceholderEscaping = cmsstore.PLACEHOLDER_ESCAPING_RAW
+	}
+
 	data.formDomainNames, err = data.site.DomainNames()
 
 	if err != nil {
@@ -536,13 +611,17 @@ type siteUpdateControllerData struct {
 	siteList []cmsstore.SiteInterface
 	view     string
 
-	formErrorMessage   string
-	formRedirectURL    string
-	formSuccessMessage string
-	formHandler        string
-	formName           string
-	formDomainNames    []string
-	formMemo           string
-	formStatus         string
-	formTitle          string
+	formErrorMessage        string
+	formRedirectURL         string
+	formSuccessMessage      string
+	formHandler             string
+	formName                string
+	formDomainNames         []string
+	formMemo                string
+	formOrganizationName    string
+	formOrganizationLogo    string
+	formOrganizationSameAs  string
+	formPlaceholderEscaping string
+	formStatus              string
+	formTitle               string
 }
@@ -83,3 +83,114 @@ func Test_SiteUpdateController_RepeaterAdd_AppendsEmptyDomain(t *testing.T) {
 		t.Fatalf("Expected appended input to be blank, got: %s", body)
 	}
 }
+
+func Test_SiteUpdateController_Save_PlaceholderEscaping(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("InitStore should succeed, got error: %v", err)
+	}
+
+	handler := initSiteUpdateHandler(store)
+
+	site, err := testutils.SeedSite(store, testutils.SITE_01)
+	if err != nil {
+		t.Fatalf("Seeding site should succeed, got error: %v", err)
+	}
+
+	getValues := url.Values{
+		"site_id": {site.ID()},
+		"view":    {VIEW_SETTINGS},
+	}
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: getValues,
+		PostValues: url.Values{
+			"site_status":               {cmsstore.SITE_STATUS_ACTIVE},
+			"site_placeholder_escaping": {"invalid"},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("CallStringEndpoint should succeed, got error: %v", err)
+	}
+
+	if !strings.Contains(body, "Placeholder escaping is invalid") {
+		t.Fatalf("Expected invalid escaping error, got: %s", body)
+	}
+
+	body, _, err = test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: getValues,
+		PostValues: url.Values{
+			"site_status":               {cmsstore.SITE_STATUS_ACTIVE},
+			"site_placeholder_escaping": {cmsstore.PLACEHOLDER_ESCAPING_RAW},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("CallStringEndpoint should succeed, got error: %v", err)
+	}
+
+	if !strings.Contains(body, "site saved successfully") {
+		t.Fatalf("Expected success message, got: %s", body)
+	}
+
+	siteFound, err := store.SiteFindByID(context.Background(), site.ID())
+	if err != nil {
+		t.Fatalf("Finding site should succeed, got error: %v", err)
+	}
+
+	if siteFound.Meta(cmsstore.SITE_META_PLACEHOLDER_ESCAPING) != cmsstore.PLACEHOLDER_ESCAPING_RAW {
+		t.Fatalf("Expected escaping %q, got %q", cmsstore.PLACEHOLDER_ESCAPING_RAW, siteFound.Meta(cmsstore.SITE_META_PLACEHOLDER_ESCAPING))
+	}
+}
+
+func Test_SiteUpdateController_Save_Organization(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("InitStore should succeed, got error: %v", err)
+	}
+
+	handler := initSiteUpdateHandler(store)
+
+	site, err := testutils.SeedSite(store, testutils.SITE_01)
+	if err != nil {
+		t.Fatalf("Seeding site should succeed, got error: %v", err)
+	}
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"site_id": {site.ID()},
+			"view":    {VIEW_SEO},
+		},
+		PostValues: url.Values{
+			"site_organization_name":    {"Acme Inc."},
+			"site_organization_logo":    {"/media/logo.png"},
+			"site_organization_same_as": {"https://x.com/acme\nhttps://github.com/acme"},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("CallStringEndpoint should succeed, got error: %v", err)
+	}
+
+	if !strings.Contains(body, "site saved successfully") {
+		t.Fatalf("Expected success message, got: %s", body)
+	}
+
+	siteFound, err := store.SiteFindByID(context.Background(), site.ID())
+	if err != nil {
+		t.Fatalf("Finding site should succeed, got error: %v", err)
+	}
+
+	if siteFound.Meta(cmsstore.SITE_META_ORGANIZATION_NAME) != "Acme Inc." {
+		t.Fatalf("Expected organization name %q, got %q", "Acme Inc.", siteFound.Meta(cmsstore.SITE_META_ORGANIZATION_NAME))
+	}
+
+	if siteFound.Meta(cmsstore.SITE_META_ORGANIZATION_LOGO) != "/media/logo.png" {
+		t.Fatalf("Expected organization logo %q, got %q", "/media/logo.png", siteFound.Meta(cmsstore.SITE_META_ORGANIZATION_LOGO))
+	}
+
+	if !strings.Contains(siteFound.Meta(cmsstore.SITE_META_ORGANIZATION_SAME_AS), "https://github.com/acme") {
+		t.Fatalf("Expected the organization profiles, got %q", siteFound.Meta(cmsstore.SITE_META_ORGANIZATION_SAME_AS))
+	}
+}
@@ -258,13 +258,27 @@ func (controller templateUpdateController) form(data templateUpdateControllerDat
 }
 
 func (templateUpdateController) fieldsContent(data templateUpdateControllerData) []form.FieldInterface {
+	variablesInfo := hb.Div().
+		Class(`alert alert-info`).
+		Child(hb.Text("Go template engine. Available data: {{ .Page.Title }}, {{ .Page.Content }}, {{ .Page.MetaDescription }}, {{ .Page.MetaKeywords }}, {{ .Page.MetaRobots }}, {{ .Page.CanonicalURL }}, {{ .Page.Metas }}, {{ .Site }}, {{ .Vars }}, {{ .Language }}")).
+		Child(hb.BR()).
+		Child(hb.Text(`Functions: {{ blockContent "BLOCK_ID" }}, {{ translation "HANDLE" }}, {{ pageUrl "PAGE_ID" }}, {{ media "MEDIA_ID" }}, {{ variable "NAME" }}, {{ pageJsonLd }}, {{ pageOpenGraph }}`)).
+		Child(hb.BR()).
+		Child(hb.Text(`Sections: [[section name="sidebar"]]default content[[/section]] or [[SECTION_sidebar]].`))
+
+	if data.formEditor != cmsstore.TEMPLATE_EDITOR_GOTEMPLATE {
+		variablesInfo = hb.Div().
+			Class(`alert alert-info`).
+			Child(hb.Text("Available variables: [[PageContent]], [[PageCanonicalUrl]], [[PageMetaDescription]], [[PageMetaKeywords]], [[PageMetaRobots]], [[PageTitle]], [[PageJsonLd]], [[PageOpenGraph]]")).
+			Child(hb.BR()).
+			Child(hb.Text(`Sections: [[section name="sidebar"]]default content[[/section]] or [[SECTION_sidebar]]. `)).
+			Child(hb.Text("Templates extending a parent template (see Settings) only need to define the sections they override. Pages can override sections too."))
+	}
+
 	fieldsContent := []form.FieldInterface{
 		form.NewField(form.FieldOptions{
-			Type: form.FORM_FIELD_TYPE_RAW,
-			Value: hb.Div().
-				Class(`alert alert-info`).
-				Child(hb.Text("Available variables: [[PageContent]], [[PageCanonicalUrl]], [[PageMetaDescription]], [[PageMetaKeywords]], [[PageMetaRobots]], [[PageTitle]]")).
-				ToHTML(),
+			Type:  form.FORM_FIELD_TYPE_RAW,
+			Value: variablesInfo.ToHTML(),
 		}),
 		form.NewField(form.FieldOptions{
 			Label: "Content (HTML)",
@@ -361,6 +375,50 @@ func (controller templateUpdateController) fieldsSettings(data templateUpdateCon
 		},
 	}
 
+	fieldParentID := &form.Field{
+		Label: "Parent Template",
+		Name:  "template_parent_id",
+		Type:  form.FORM_FIELD_TYPE_SELECT,
+		Value: data.formParentID,
+		Help:  "The template this template extends. The content of this template should then only define the sections it overrides.",
+		OptionsF: func() []form.FieldOption {
+			options := []form.FieldOption{
+				{
+					Value: "- no parent template -",
+					Key:   "",
+				},
+			}
+			for _, template := range data.templateList {
+				if template.ID() == data.templateID {
+					continue // a template cannot extend itself
+				}
+				options = append(options, form.FieldOption{
+					Value: template.Name() + " (" + template.Status() + ")",
+					Key:   template.ID(),
+				})
+			}
+			return options
+		},
+	}
+
+	fieldEditor := form.NewField(form.FieldOptions{
+		Label: "Template Engine",
+		Name:  "template_editor",
+		Type:  form.FORM_FIELD_TYPE_SELECT,
+		Value: data.formEditor,
+		Help:  "The engine used to render the template. The Go template engine supports loops, conditions and context-aware escaping.",
+		Options: []form.FieldOption{
+			{
+				Value: "CMS placeholders ([[PageTitle]])",
+				Key:   "",
+			},
+			{
+				Value: "Go html/template ({{ .Page.Title }})",
+				Key:   cmsstore.TEMPLATE_EDITOR_GOTEMPLATE,
+			},
+		},
+	})
+
 	fieldStatus := form.NewField(form.FieldOptions{
 		Label: "Status",
 		Name:  "template_status",
@@ -416,6 +474,8 @@ func (controller templateUpdateController) fieldsSettings(data templateUpdateCon
 		fieldStatus,
 		fieldTemplateName,
 		fieldSiteID,
+		fieldParentID,
+		fieldEditor,
 		fieldMemo,
 		fieldTemplateID,
 		fieldView,
@@ -428,6 +488,8 @@ func (controller templateUpdateController) saveTemplate(data templateUpdateContr
 	data.formContent = req.GetStringTrimmed(data.request, "template_content")
 	data.formMemo = req.GetStringTrimmed(data.request, "template_memo")
 	data.formName = req.GetStringTrimmed(data.request, "template_name")
+	data.formParentID = req.GetStringTrimmed(data.request, "template_parent_id")
+	data.formEditor = req.GetStringTrimmed(data.request, "template_editor")
 	data.formSiteID = req.GetStringTrimmed(data.request, "template_site_id")
 	data.formStatus = req.GetStringTrimmed(data.request, "template_status")
 	data.formTitle = req.GetStringTrimmed(data.request, "template_title")
@@ -437,11 +499,25 @@ func (controller templateUpdateController) saveTemplate(data templateUpdateContr
 			data.formErrorMessage = "Status is required"
 			return data, ""
 		}
+
+		if errorMessage := controller.validateParentID(data); errorMessage != "" {
+			data.formErrorMessage = errorMessage
+			return data, ""
+		}
 	}
 
 	if data.view == VIEW_SETTINGS {
+		// The parent ID is stored in the metas, not set if they are invalid
+		if _, err := data.template.Metas(); err != nil {
+			controller.ui.Logger().Error("At templateUpdateController > saveTemplate", "error", err.Error())
+			data.formErrorMessage = "System error. Saving template failed. " + err.Error()
+			return data, ""
+		}
+
 		data.template.SetMemo(data.formMemo)
 		data.template.SetName(data.formName)
+		data.template.SetParentID(data.formParentID)
+		data.template.SetEditor(data.formEditor)
 		data.template.SetSiteID(data.formSiteID)
 		data.template.SetStatus(data.formStatus)
 	}
@@ -475,6 +551,36 @@ func (controller templateUpdateController) saveTemplate(data templateUpdateContr
 	return data, ""
 }
 
+// validateParentID checks the selected parent template exists and that
+// extending it does not create an inheritance cycle
+func (controller templateUpdateController) validateParentID(data templateUpdateControllerData) string {
+	visited := map[string]bool{data.templateID: true}
+	parentID := data.formParentID
+
+	for parentID != "" {
+		if visited[parentID] {
+			return "Parent template is not valid. The template cannot extend itself or one of its descendants"
+		}
+
+		visited[parentID] = true
+
+		parent, err := controller.ui.Store().TemplateFindByID(data.request.Context(), parentID)
+
+		if err != nil {
+			controller.ui.Logger().Error("At templateUpdateController > validateParentID", "error", err.Error())
+			return "System error. Parent template could not be loaded"
+		}
+
+		if parent == nil {
+			return "Parent template not found"
+		}
+
+		parentID = parent.ParentID()
+	}
+
+	return ""
+}
+
 func (controller templateUpdateController) moveTemplateBlocks(request *http.Request, templateID string, siteID string) error {
 	blocks, err := controller.ui.Store().BlockList(request.Context(), cmsstore.BlockQuery().
 		SetPageID(templateID))
@@ -538,9 +644,21 @@ func (controller templateUpdateController) prepareDataAndValidate(r *http.Reques
 
 	data.siteList = siteList
 
+	templateList, err := controller.ui.Store().TemplateList(r.Context(), cmsstore.TemplateQuery().
+		SetOrderBy(cmsstore.COLUMN_NAME).
+		SetSortOrder(cmsstore.SORT_ORDER_ASC))
+
+	if err != nil {
+		return data, "Template list failed to be retrieved" + err.Error()
+	}
+
+	data.templateList = templateList
+
 	data.formContent = data.template.Content()
 	data.formName = data.template.Name()
 	data.formMemo = data.template.Memo()
+	data.formParentID = data.template.ParentID()
+	data.formEditor = data.template.Editor()
 	data.formSiteID = data.template.SiteID()
 	data.formStatus = data.template.Status()
 
@@ -558,14 +676,17 @@ type templateUpdateControllerData struct {
 	template   cmsstore.TemplateInterface
 	view       string
 
-	siteList []cmsstore.SiteInterface
+	siteList     []cmsstore.SiteInterface
+	templateList []cmsstore.TemplateInterface
 
 	formErrorMessage   string
 	formRedirectURL    string
 	formSuccessMessage string
 	formContent        string
+	formEditor         string
 	formName           string
 	formMemo           string
+	formParentID       string
 	formSiteID         string
 	formStatus         string
 	formTitle          string
@@ -359,6 +359,82 @@ func Test_TemplateUpdateController_Save_Settings_MissingStatus(t *testing.T) {
 	}
 }
 
+func Test_TemplateUpdateController_Save_Settings_ParentTemplate(t *testing.T) {
+	handler, store, err := initHandler()
+	if err != nil {
+		t.Fatalf("initHandler should succeed, got error: %v", err)
+	}
+
+	parent, err := testutils.SeedTemplate(store, testutils.SITE_01, testutils.TEMPLATE_01)
+	if err != nil {
+		t.Fatalf("Seeding template should succeed, got error: %v", err)
+	}
+
+	child, err := testutils.SeedTemplate(store, testutils.SITE_01, testutils.TEMPLATE_02)
+	if err != nil {
+		t.Fatalf("Seeding template should succeed, got error: %v", err)
+	}
+
+	postData := url.Values{}
+	postData.Set("template_name", child.Name())
+	postData.Set("template_site_id", testutils.SITE_01)
+	postData.Set("template_status", cmsstore.TEMPLATE_STATUS_ACTIVE)
+	postData.Set("template_parent_id", parent.ID())
+	postData.Set("view", VIEW_SETTINGS)
+
+	body, _, err := test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"template_id": {child.ID()},
+		},
+		PostValues: postData,
+	})
+
+	if err != nil {
+		t.Fatalf("CallStringEndpoint should succeed, got error: %v", err)
+	}
+
+	if !strings.Contains(body, `"icon":"success"`) {
+		t.Errorf("Expected success swal icon in body: %s", body)
+	}
+
+	updatedChild, err := store.TemplateFindByID(context.Background(), child.ID())
+	if err != nil {
+		t.Fatalf("Finding template should succeed, got error: %v", err)
+	}
+
+	if updatedChild.ParentID() != parent.ID() {
+		t.Errorf("Expected parent ID '%s', got '%s'", parent.ID(), updatedChild.ParentID())
+	}
+
+	// Making the parent extend its child must be rejected
+	postData.Set("template_name", parent.Name())
+	postData.Set("template_parent_id", child.ID())
+
+	body, _, err = test.CallStringEndpoint(http.MethodPost, handler, test.NewRequestOptions{
+		GetValues: url.Values{
+			"template_id": {parent.ID()},
+		},
+		PostValues: postData,
+	})
+
+	if err != nil {
+		t.Fatalf("CallStringEndpoint should succeed, got error: %v", err)
+	}
+
+	if !strings.Contains(body, `"icon":"error"`) {
+		t.Errorf("Expected error swal icon in body: %s", body)
+	}
+
+	notUpdatedParent, err := store.TemplateFindByID(context.Background(), parent.ID())
+	if err != nil {
+		t.Fatalf("Finding template should succeed, got error: %v", err)
+	}
+
+	if notUpdatedParent.ParentID() != "" {
+		t.Errorf("Expected parent template to have no parent, got '%s'", notUpdatedParent.ParentID())
+	}
+}
+
 func Test_TemplateUpdateController_Index_Success_SettingsView(t *testing.T) { // Renamed for clarity
 	handler, store, err := initHandler() // Use local initHandler
 	if err != nil {
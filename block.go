@@ -2,6 +2,8 @@ package cmsstore
 
 import (
 	"encoding/json"
+	"slices"
+	"strings"
 
 	"github.com/dracory/dataobject"
 	"github.com/dromara/carbon/v2"
@@ -120,6 +122,52 @@ func (o *block) SetHandle(handle string) BlockInterface {
 	return o
 }
 
+// IsLibrary returns whether the block is a reusable library block
+//
+// A library block can be embedded on any page as a linked instance,
+// with its library fields overridden by the attributes of the reference
+func (o *block) IsLibrary() bool {
+	return o.Meta(BLOCK_META_LIBRARY) == "yes"
+}
+
+// SetLibrary marks the block as a reusable library block (or not)
+func (o *block) SetLibrary(isLibrary bool) error {
+	if isLibrary {
+		return o.SetMeta(BLOCK_META_LIBRARY, "yes")
+	}
+
+	return o.SetMeta(BLOCK_META_LIBRARY, "no")
+}
+
+// LibraryFields returns the names of the metas, which the linked
+// instances of the library block can override
+func (o *block) LibraryFields() []string {
+	fields := []string{}
+
+	for _, field := range strings.Split(o.Meta(BLOCK_META_LIBRARY_FIELDS), ",") {
+		field = strings.TrimSpace(field)
+		if field != "" && !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// SetLibraryFields sets the names of the overridable metas
+func (o *block) SetLibraryFields(fields []string) error {
+	names := []string{}
+
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field != "" && !slices.Contains(names, field) {
+			names = append(names, field)
+		}
+	}
+
+	return o.SetMeta(BLOCK_META_LIBRARY_FIELDS, strings.Join(names, ","))
+}
+
 func (o *block) Memo() string {
 	return o.Get(COLUMN_MEMO)
 }
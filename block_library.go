@@ -0,0 +1,164 @@
+package cmsstore
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Block reference syntaxes, as processed by the frontend:
+// [[BLOCK_id]], <block id="..." /> and [[block id='...']]
+var blockReferenceShortcode = regexp.MustCompile(`\[\[BLOCK_([0-9A-Za-z_-]+)\]\]`)
+var blockReferenceAngleBrackets = regexp.MustCompile(`<block\s+([^>]+?)\s*/>`)
+var blockReferenceSquareBrackets = regexp.MustCompile(`\[\[block\s+([^\]]+?)\s*\]\]`)
+var blockReferenceAttribute = regexp.MustCompile(`([\w-]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s/>]+)))?`)
+
+// BlockUsage is a single reference to a block, as found in the content
+// of a page, a template or another block.
+type BlockUsage struct {
+	// EntityType is the type of the referencing entity,
+	// one of the BLOCK_USAGE_ENTITY_* constants
+	EntityType string
+
+	// EntityID is the ID of the referencing entity
+	EntityID string
+
+	// EntityName is the name of the referencing entity
+	EntityName string
+
+	// Overrides are the library fields overridden by the reference
+	Overrides map[string]string
+}
+
+// BlockLibraryInstance returns the linked instance of the library block
+// for the attributes of a block reference.
+//
+// Business Logic:
+//   - the instance is a copy of the block, the block itself is not modified
+//   - the attributes matching the library fields override the block metas
+//   - the remaining attributes are returned, to be passed to the block type
+//   - non-library blocks are returned as they are, with all attributes
+func BlockLibraryInstance(block BlockInterface, attrs map[string]string) (BlockInterface, map[string]string, error) {
+	if block == nil || !block.IsLibrary() {
+		return block, attrs, nil
+	}
+
+	fields := block.LibraryFields()
+	instance := NewBlockFromExistingData(maps.Clone(block.Data()))
+	overrides := map[string]string{}
+	remaining := map[string]string{}
+
+	for name, value := range attrs {
+		if slices.Contains(fields, name) {
+			overrides[name] = value
+		} else {
+			remaining[name] = value
+		}
+	}
+
+	if len(overrides) > 0 {
+		if err := instance.UpsertMetas(overrides); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return instance, remaining, nil
+}
+
+// BlockLibraryFieldValues returns the values of the library fields
+// of the block (or linked instance), keyed by the field name.
+//
+// The values are used to replace the [[field]] placeholders
+// in the rendered library block.
+func BlockLibraryFieldValues(block BlockInterface) map[string]string {
+	values := map[string]string{}
+
+	if block == nil || !block.IsLibrary() {
+		return values
+	}
+
+	for _, field := range block.LibraryFields() {
+		values[field] = block.Meta(field)
+	}
+
+	return values
+}
+
+// blockUsagesInContent returns the overrides of the references
+// to the block in the content (by ID or handle), in content order
+func blockUsagesInContent(block BlockInterface, content string) []map[string]string {
+	references := []map[string]string{}
+
+	if content == "" {
+		return references
+	}
+
+	type reference struct {
+		position int
+		attrs    map[string]string
+	}
+
+	found := []reference{}
+
+	for _, match := range blockReferenceShortcode.FindAllStringSubmatchIndex(content, -1) {
+		found = append(found, reference{
+			position: match[0],
+			attrs:    map[string]string{"id": content[match[2]:match[3]]},
+		})
+	}
+
+	for _, pattern := range []*regexp.Regexp{blockReferenceAngleBrackets, blockReferenceSquareBrackets} {
+		for _, match := range pattern.FindAllStringSubmatchIndex(content, -1) {
+			found = append(found, reference{
+				position: match[0],
+				attrs:    parseBlockReferenceAttributes(content[match[2]:match[3]]),
+			})
+		}
+	}
+
+	slices.SortFunc(found, func(a, b reference) int { return a.position - b.position })
+
+	for _, ref := range found {
+		id := ref.attrs["id"]
+
+		if id == "" || (id != block.ID() && (block.Handle() == "" || id != block.Handle())) {
+			continue
+		}
+
+		overrides := map[string]string{}
+		for _, field := range block.LibraryFields() {
+			if value, exists := ref.attrs[field]; exists {
+				overrides[field] = value
+			}
+		}
+
+		references = append(references, overrides)
+	}
+
+	return references
+}
+
+// parseBlockReferenceAttributes parses the attributes of a block reference,
+// i.e. id="cta" title='Hello' flag
+func parseBlockReferenceAttributes(s string) map[string]string {
+	attrs := map[string]string{}
+
+	for _, m := range blockReferenceAttribute.FindAllStringSubmatch(s, -1) {
+		key := strings.TrimSpace(m[1])
+		if key == "" {
+			continue
+		}
+
+		switch {
+		case m[2] != "":
+			attrs[key] = m[2]
+		case m[3] != "":
+			attrs[key] = m[3]
+		default:
+			attrs[key] = m[4]
+		}
+	}
+
+	return attrs
+}
@@ -0,0 +1,153 @@
+package cmsstore
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBlockLibraryFields(t *testing.T) {
+	block := NewBlock()
+
+	if block.IsLibrary() {
+		t.Error("Expected a new block not to be a library block")
+	}
+
+	if err := block.SetLibrary(true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := block.SetLibraryFields([]string{" title ", "url", "", "title"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !block.IsLibrary() {
+		t.Error("Expected the block to be a library block")
+	}
+
+	fields := block.LibraryFields()
+	if len(fields) != 2 || fields[0] != "title" || fields[1] != "url" {
+		t.Errorf("Expected fields [title url], got %v", fields)
+	}
+}
+
+func TestBlockLibraryInstance(t *testing.T) {
+	block := NewBlock()
+	block.SetLibrary(true)
+	block.SetLibraryFields([]string{"title"})
+	block.SetMeta("title", "Join us")
+
+	instance, remaining, err := BlockLibraryInstance(block, map[string]string{
+		"id":    block.ID(),
+		"title": "Sign up",
+		"class": "wide",
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if instance.Meta("title") != "Sign up" {
+		t.Errorf("Expected the instance title to be overridden, got %q", instance.Meta("title"))
+	}
+
+	if block.Meta("title") != "Join us" {
+		t.Errorf("Expected the library block to be unchanged, got %q", block.Meta("title"))
+	}
+
+	if _, exists := remaining["title"]; exists {
+		t.Error("Expected the overridden field not to be in the remaining attributes")
+	}
+
+	if remaining["class"] != "wide" || remaining["id"] != block.ID() {
+		t.Errorf("Expected the other attributes to remain, got %v", remaining)
+	}
+
+	values := BlockLibraryFieldValues(instance)
+	if len(values) != 1 || values["title"] != "Sign up" {
+		t.Errorf("Expected the field values of the instance, got %v", values)
+	}
+}
+
+func TestBlockLibraryInstance_NotLibrary(t *testing.T) {
+	block := NewBlock()
+	block.SetMeta("title", "Join us")
+
+	instance, remaining, err := BlockLibraryInstance(block, map[string]string{"title": "Sign up"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if instance != block || remaining["title"] != "Sign up" {
+		t.Error("Expected a non-library block to be returned as is, with all attributes")
+	}
+}
+
+func TestStoreBlockUsageList(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	block := NewBlock().SetSiteID("Site1").SetHandle("cta").SetName("CTA")
+	block.SetLibrary(true)
+	block.SetLibraryFields([]string{"title"})
+
+	if err := store.BlockCreate(ctx, block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	page := NewPage().
+		SetSiteID("Site1").
+		SetName("Home").
+		SetContent(`<block id="cta" title="Welcome" class="wide" /> [[BLOCK_` + block.ID() + `]]`)
+
+	if err := store.PageCreate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	template := NewTemplate().
+		SetSiteID("Site1").
+		SetName("Default").
+		SetContent(`[[block id='` + block.ID() + `']]`)
+
+	if err := store.TemplateCreate(ctx, template); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	other := NewBlock().
+		SetSiteID("Site2").
+		SetContent(`<block id="cta" />`)
+
+	if err := store.BlockCreate(ctx, other); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	usages, err := store.BlockUsageList(ctx, block)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(usages) != 3 {
+		t.Fatalf("Expected 3 usages, got %d: %v", len(usages), usages)
+	}
+
+	if usages[0].EntityType != BLOCK_USAGE_ENTITY_PAGE || usages[0].EntityID != page.ID() || usages[0].EntityName != "Home" {
+		t.Errorf("Expected the first usage to be the page, got %v", usages[0])
+	}
+
+	if len(usages[0].Overrides) != 1 || usages[0].Overrides["title"] != "Welcome" {
+		t.Errorf("Expected the title override only, got %v", usages[0].Overrides)
+	}
+
+	if usages[1].EntityType != BLOCK_USAGE_ENTITY_PAGE || len(usages[1].Overrides) != 0 {
+		t.Errorf("Expected the legacy reference without overrides, got %v", usages[1])
+	}
+
+	if usages[2].EntityType != BLOCK_USAGE_ENTITY_TEMPLATE || usages[2].EntityID != template.ID() {
+		t.Errorf("Expected the third usage to be the template, got %v", usages[2])
+	}
+}
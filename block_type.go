@@ -144,8 +144,11 @@ type BlockCustomVariable struct {
 
 // BlockTypeRegistry manages all registered block types.
 //
-// This is a global registry that stores block type definitions. Both the frontend
-// and admin systems use this registry to look up block types.
+// The registry stores block type definitions. Both the frontend and admin
+// systems use the registry of the store (see NewStoreOptions.BlockTypeRegistry)
+// to look up block types. Stores without an own registry share the default
+// global registry, used by the package-level functions (RegisterCustomBlockType,
+// GetBlockType, etc.).
 //
 // The registry is thread-safe and can be accessed concurrently.
 type BlockTypeRegistry struct {
@@ -154,9 +157,31 @@ type BlockTypeRegistry struct {
 	mu      sync.RWMutex
 }
 
-var globalBlockTypeRegistry = &BlockTypeRegistry{
-	types:   make(map[string]BlockType),
-	origins: make(map[string]string),
+var globalBlockTypeRegistry = NewBlockTypeRegistry()
+
+// NewBlockTypeRegistry creates a new, empty block type registry.
+//
+// Use it to give a store its own block types, i.e. in multi-tenant apps
+// where each store needs its own store-bound block type instances:
+//
+//	registry := cmsstore.NewBlockTypeRegistry()
+//	registry.RegisterWithOrigin(&GalleryBlockType{}, cmsstore.BLOCK_ORIGIN_CUSTOM)
+//
+//	store, err := cmsstore.NewStore(cmsstore.NewStoreOptions{
+//	    // ...
+//	    BlockTypeRegistry: registry,
+//	})
+func NewBlockTypeRegistry() *BlockTypeRegistry {
+	return &BlockTypeRegistry{
+		types:   make(map[string]BlockType),
+		origins: make(map[string]string),
+	}
+}
+
+// DefaultBlockTypeRegistry returns the default global registry, used by
+// the package-level functions and by stores without an own registry.
+func DefaultBlockTypeRegistry() *BlockTypeRegistry {
+	return globalBlockTypeRegistry
 }
 
 // RegisterSystemBlockType registers a system (built-in) block type.
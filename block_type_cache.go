@@ -0,0 +1,71 @@
+package cmsstore
+
+import "context"
+
+// BlockTypeWithCache is an optional BlockType extension for block types
+// whose rendered HTML can be cached, i.e. menus, navbars and entity lists,
+// expensive to render on every request.
+//
+// The frontend caches the rendered HTML of the block separately from the
+// page, per value of the vary keys of the cache policy. The cached HTML is
+// invalidated when the block, or the data it depends on, is changed.
+//
+// Example:
+//
+//	func (t *MenuBlockType) CachePolicy(ctx context.Context, block BlockInterface) *BlockCachePolicy {
+//	    return &BlockCachePolicy{
+//	        VaryByLanguage: true,
+//	        VaryByPath:     true, // the active item depends on the page
+//	        Dependencies:   []string{CHANGE_KIND_MENUS, CHANGE_KIND_PAGES},
+//	    }
+//	}
+type BlockTypeWithCache interface {
+	BlockType
+
+	// CachePolicy returns how the rendered block is cached,
+	// nil if the block must not be cached. It is called on every render of
+	// the block, with the context of the render, so must be inexpensive
+	CachePolicy(ctx context.Context, block BlockInterface) *BlockCachePolicy
+}
+
+// BlockCachePolicy describes how the rendered HTML of a block is cached.
+type BlockCachePolicy struct {
+	// ExpireSeconds is how long the rendered block is cached,
+	// the cache expiration of the frontend is used if 0. As the
+	// Dependencies only see the changes made through the store of this
+	// process, it is also how long the block may show stale data changed
+	// by other processes
+	ExpireSeconds int
+
+	// VaryByLanguage caches the block per language of the page
+	VaryByLanguage bool
+
+	// VaryByPath caches the block per path of the page,
+	// i.e. for the menus marking the active item
+	VaryByPath bool
+
+	// VaryByQuery caches the block per query string,
+	// i.e. for the links keeping the query parameters of the request
+	VaryByQuery bool
+
+	// VaryByQueryParams caches the block per value of the query parameters,
+	// i.e. "page" for a paginated list. The other query parameters are ignored
+	VaryByQueryParams []string
+
+	// Dependencies are the kinds of data the rendered block depends on
+	// (see CHANGE_KIND_*), their changes invalidate the cached block.
+	// Changes to the blocks always invalidate the cached block
+	Dependencies []string
+}
+
+// BlockTypeCachePolicy returns the cache policy of the block,
+// or nil if the block type does not implement BlockTypeWithCache.
+func BlockTypeCachePolicy(ctx context.Context, blockType BlockType, block BlockInterface) *BlockCachePolicy {
+	withCache, ok := blockType.(BlockTypeWithCache)
+
+	if !ok {
+		return nil
+	}
+
+	return withCache.CachePolicy(ctx, block)
+}
@@ -0,0 +1,37 @@
+package cmsstore
+
+import "slices"
+
+// BlockTypeContainer is an optional BlockType extension for block types
+// rendering their child blocks, i.e. sections, grid rows and tabs.
+//
+// The children of a block are the blocks with the block as parent,
+// rendered in sequence order. The admin block layout only allows nesting
+// blocks in the blocks of container types.
+type BlockTypeContainer interface {
+	BlockType
+
+	// AllowedChildTypes returns the block types allowed as children,
+	// nil allows any block type
+	AllowedChildTypes() []string
+}
+
+// BlockTypeIsContainer returns whether the block type renders child blocks.
+func BlockTypeIsContainer(blockType BlockType) bool {
+	_, ok := blockType.(BlockTypeContainer)
+	return ok
+}
+
+// BlockTypeAllowsChild returns whether a block of the child type
+// can be nested in a block of the (container) block type.
+func BlockTypeAllowsChild(blockType BlockType, childType string) bool {
+	container, ok := blockType.(BlockTypeContainer)
+
+	if !ok {
+		return false
+	}
+
+	allowed := container.AllowedChildTypes()
+
+	return allowed == nil || slices.Contains(allowed, childType)
+}
@@ -131,3 +131,59 @@ func TestBlockTypeRegistryGet(t *testing.T) {
 		t.Error("Expected nil for non-existent block type")
 	}
 }
+
+func TestNewBlockTypeRegistry_IsolatedFromDefault(t *testing.T) {
+	registry := NewBlockTypeRegistry()
+
+	if registry == DefaultBlockTypeRegistry() {
+		t.Fatal("Expected a new registry, got the default registry")
+	}
+
+	registry.RegisterWithOrigin(&testCustomBlock{}, BLOCK_ORIGIN_CUSTOM)
+
+	if registry.Get("test_custom") == nil {
+		t.Error("Expected test_custom to be registered in the new registry")
+	}
+
+	if len(NewBlockTypeRegistry().GetAll()) != 0 {
+		t.Error("Expected another new registry to be empty")
+	}
+}
+
+func TestStoreBlockTypeRegistry(t *testing.T) {
+	db := initDB(":memory:")
+	defer db.Close()
+
+	registry := NewBlockTypeRegistry()
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                db,
+		BlockTableName:    "block_table",
+		PageTableName:     "page_table",
+		SiteTableName:     "site_table",
+		TemplateTableName: "template_table",
+		BlockTypeRegistry: registry,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	if store.BlockTypeRegistry() != registry {
+		t.Error("Expected the store to use its own registry")
+	}
+
+	defaultStore, err := NewStore(NewStoreOptions{
+		DB:                db,
+		BlockTableName:    "block_table",
+		PageTableName:     "page_table",
+		SiteTableName:     "site_table",
+		TemplateTableName: "template_table",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	if defaultStore.BlockTypeRegistry() != DefaultBlockTypeRegistry() {
+		t.Error("Expected the store to default to the global registry")
+	}
+}
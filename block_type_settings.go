@@ -0,0 +1,394 @@
+package cmsstore
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// Block setting types
+const (
+	BLOCK_SETTING_TYPE_STRING = "string"
+	BLOCK_SETTING_TYPE_TEXT   = "text"
+	BLOCK_SETTING_TYPE_INT    = "int"
+	BLOCK_SETTING_TYPE_FLOAT  = "float"
+	BLOCK_SETTING_TYPE_BOOL   = "bool"
+	BLOCK_SETTING_TYPE_ENUM   = "enum"
+)
+
+// BlockTypeWithSettings is an optional BlockType extension declaring
+// a typed settings schema.
+//
+// The settings are stored as block metas, keyed by the setting name.
+// From the schema the admin form fields and their parsing, the validation,
+// the MCP tool schema and the REST validation are generated, so the block
+// type's GetAdminFields and SaveAdminFields only need to handle anything
+// not covered by the settings (they may return nil).
+//
+// Example:
+//
+//	func (t *GalleryBlockType) SettingsSchema() []BlockSettingDefinition {
+//	    return []BlockSettingDefinition{
+//	        {Name: "layout", Label: "Layout", Type: BLOCK_SETTING_TYPE_ENUM, Default: "grid", Options: []BlockSettingOption{
+//	            {Value: "grid", Label: "Grid"},
+//	            {Value: "masonry", Label: "Masonry"},
+//	        }},
+//	        {Name: "columns", Label: "Columns", Type: BLOCK_SETTING_TYPE_INT, Default: "3", MinValue: &one, MaxValue: &six},
+//	    }
+//	}
+//
+// In Render the values, with the defaults applied, are read with
+// BlockSettingsValues(block, t.SettingsSchema()).
+type BlockTypeWithSettings interface {
+	BlockType
+
+	// SettingsSchema returns the settings of the block type, in display order
+	SettingsSchema() []BlockSettingDefinition
+}
+
+// BlockSettingDefinition describes a single typed block setting.
+type BlockSettingDefinition struct {
+	// Name is the setting name, used as the meta key and the form field name
+	Name string
+
+	// Label is the human-readable label, the name is used if empty
+	Label string
+
+	// Type is the setting type, one of the BLOCK_SETTING_TYPE_* constants
+	Type string
+
+	// Default is the value used when the setting is not set
+	Default string
+
+	// Required indicates whether the setting must have a non-empty value
+	Required bool
+
+	// Options lists the valid values for the enum type
+	Options []BlockSettingOption
+
+	// Validation is a regular expression the (non-empty) value must match
+	Validation string
+
+	// MinValue is the minimum value (for int/float types)
+	MinValue *float64
+
+	// MaxValue is the maximum value (for int/float types)
+	MaxValue *float64
+
+	// Help is the help text shown in the admin form
+	Help string
+}
+
+// BlockSettingOption is a valid value of an enum setting.
+type BlockSettingOption struct {
+	Value string
+	Label string
+}
+
+// BlockTypeSettingsSchema returns the settings schema of the block type,
+// or nil if the block type does not implement BlockTypeWithSettings.
+func BlockTypeSettingsSchema(blockType BlockType) []BlockSettingDefinition {
+	withSettings, ok := blockType.(BlockTypeWithSettings)
+
+	if !ok {
+		return nil
+	}
+
+	return withSettings.SettingsSchema()
+}
+
+// BlockSettingsValues returns the setting values of the block,
+// falling back to the defaults for the settings not set.
+func BlockSettingsValues(block BlockInterface, schema []BlockSettingDefinition) map[string]string {
+	values := make(map[string]string, len(schema))
+
+	for _, setting := range schema {
+		value := block.Meta(setting.Name)
+
+		if value == "" {
+			value = setting.Default
+		}
+
+		values[setting.Name] = value
+	}
+
+	return values
+}
+
+// ValidateBlockSettings validates the values against the schema.
+//
+// Business Logic:
+//   - values for unknown settings are rejected
+//   - required settings must have a non-empty value
+//   - empty values of optional settings are always valid
+//   - non-empty values must be valid for the setting type, options,
+//     range and validation pattern
+func ValidateBlockSettings(schema []BlockSettingDefinition, values map[string]string) error {
+	for name := range values {
+		if !slices.ContainsFunc(schema, func(setting BlockSettingDefinition) bool { return setting.Name == name }) {
+			return errors.New("unknown setting: " + name)
+		}
+	}
+
+	for _, setting := range schema {
+		if err := validateBlockSetting(setting, values[setting.Name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateBlockSetting validates a single setting value
+func validateBlockSetting(setting BlockSettingDefinition, value string) error {
+	label := setting.label()
+
+	if value == "" {
+		if setting.Required {
+			return errors.New(label + " is required")
+		}
+
+		return nil
+	}
+
+	switch setting.Type {
+	case BLOCK_SETTING_TYPE_INT, BLOCK_SETTING_TYPE_FLOAT:
+		var number float64
+		var err error
+
+		if setting.Type == BLOCK_SETTING_TYPE_INT {
+			var integer int64
+			integer, err = strconv.ParseInt(value, 10, 64)
+			number = float64(integer)
+		} else {
+			number, err = strconv.ParseFloat(value, 64)
+		}
+
+		if err != nil {
+			return errors.New(label + " must be a number")
+		}
+
+		if setting.MinValue != nil && number < *setting.MinValue {
+			return errors.New(label + " must be at least " + strconv.FormatFloat(*setting.MinValue, 'f', -1, 64))
+		}
+
+		if setting.MaxValue != nil && number > *setting.MaxValue {
+			return errors.New(label + " must be at most " + strconv.FormatFloat(*setting.MaxValue, 'f', -1, 64))
+		}
+
+	case BLOCK_SETTING_TYPE_BOOL:
+		if value != "true" && value != "false" {
+			return errors.New(label + " must be true or false")
+		}
+
+	case BLOCK_SETTING_TYPE_ENUM:
+		if !slices.ContainsFunc(setting.Options, func(option BlockSettingOption) bool { return option.Value == value }) {
+			return errors.New(label + " has an invalid value: " + value)
+		}
+
+	case BLOCK_SETTING_TYPE_STRING, BLOCK_SETTING_TYPE_TEXT, "":
+		// any string
+
+	default:
+		return errors.New(label + " has an unsupported type: " + setting.Type)
+	}
+
+	if setting.Validation != "" {
+		pattern, err := regexp.Compile(setting.Validation)
+
+		if err != nil {
+			return errors.New(label + " has an invalid validation pattern: " + err.Error())
+		}
+
+		if !pattern.MatchString(value) {
+			return errors.New(label + " has an invalid format")
+		}
+	}
+
+	return nil
+}
+
+// ApplyBlockSettings validates the values and stores them as block metas.
+//
+// Only the given settings are updated (partial update), the validation
+// is done on the resulting values, so required settings already set
+// on the block do not need to be given again.
+func ApplyBlockSettings(block BlockInterface, schema []BlockSettingDefinition, values map[string]string) error {
+	merged := BlockSettingsValues(block, schema)
+
+	for name, value := range values {
+		merged[name] = value
+	}
+
+	if err := ValidateBlockSettings(schema, merged); err != nil {
+		return err
+	}
+
+	for _, setting := range schema {
+		value, exists := values[setting.Name]
+
+		if !exists {
+			continue
+		}
+
+		if err := block.SetMeta(setting.Name, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BlockSettingsFromRequest reads the setting values from the submitted
+// admin form, bool settings are read as "true" or "false".
+func BlockSettingsFromRequest(r *http.Request, schema []BlockSettingDefinition) map[string]string {
+	values := make(map[string]string, len(schema))
+
+	for _, setting := range schema {
+		value := r.FormValue(setting.Name)
+
+		switch setting.Type {
+		case BLOCK_SETTING_TYPE_BOOL:
+			value = strconv.FormatBool(slices.Contains([]string{"1", "on", "true", "yes"}, strings.ToLower(strings.TrimSpace(value))))
+		case BLOCK_SETTING_TYPE_TEXT:
+			// keep the text as entered
+		default:
+			value = strings.TrimSpace(value)
+		}
+
+		values[setting.Name] = value
+	}
+
+	return values
+}
+
+// BlockSettingsFromJSON converts the decoded JSON settings object
+// (i.e. from the REST API or an MCP tool call) to setting values.
+//
+// Numbers and booleans are accepted as such, as well as strings.
+func BlockSettingsFromJSON(settings map[string]any) (map[string]string, error) {
+	values := make(map[string]string, len(settings))
+
+	for name, raw := range settings {
+		switch value := raw.(type) {
+		case string:
+			values[name] = value
+		case bool:
+			values[name] = strconv.FormatBool(value)
+		case float64:
+			values[name] = strconv.FormatFloat(value, 'f', -1, 64)
+		case int:
+			values[name] = strconv.Itoa(value)
+		case int64:
+			values[name] = strconv.FormatInt(value, 10)
+		case json.Number:
+			values[name] = value.String()
+		case nil:
+			values[name] = ""
+		default:
+			return nil, errors.New("setting " + name + " must be a string, number or boolean")
+		}
+	}
+
+	return values, nil
+}
+
+// BlockSettingsJSONSchema returns the JSON schema of the settings,
+// i.e. for describing the settings argument of the MCP tools.
+func BlockSettingsJSONSchema(schema []BlockSettingDefinition) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+
+	for _, setting := range schema {
+		property := map[string]any{
+			"title": setting.label(),
+		}
+
+		switch setting.Type {
+		case BLOCK_SETTING_TYPE_INT:
+			property["type"] = "integer"
+		case BLOCK_SETTING_TYPE_FLOAT:
+			property["type"] = "number"
+		case BLOCK_SETTING_TYPE_BOOL:
+			property["type"] = "boolean"
+		default:
+			property["type"] = "string"
+		}
+
+		if setting.Default != "" {
+			property["default"] = setting.jsonDefault()
+		}
+
+		if setting.Help != "" {
+			property["description"] = setting.Help
+		}
+
+		if setting.Type == BLOCK_SETTING_TYPE_ENUM {
+			enum := make([]string, 0, len(setting.Options))
+			for _, option := range setting.Options {
+				enum = append(enum, option.Value)
+			}
+			property["enum"] = enum
+		}
+
+		if setting.MinValue != nil {
+			property["minimum"] = *setting.MinValue
+		}
+
+		if setting.MaxValue != nil {
+			property["maximum"] = *setting.MaxValue
+		}
+
+		if setting.Validation != "" {
+			property["pattern"] = setting.Validation
+		}
+
+		if setting.Required {
+			required = append(required, setting.Name)
+		}
+
+		properties[setting.Name] = property
+	}
+
+	jsonSchema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+
+	if len(required) > 0 {
+		jsonSchema["required"] = required
+	}
+
+	return jsonSchema
+}
+
+// jsonDefault returns the default of the setting, with the type
+// of its JSON schema property
+func (setting BlockSettingDefinition) jsonDefault() any {
+	switch setting.Type {
+	case BLOCK_SETTING_TYPE_INT:
+		return cast.ToInt(setting.Default)
+	case BLOCK_SETTING_TYPE_FLOAT:
+		return cast.ToFloat64(setting.Default)
+	case BLOCK_SETTING_TYPE_BOOL:
+		return cast.ToBool(setting.Default)
+	default:
+		return setting.Default
+	}
+}
+
+// label returns the label of the setting, or its name if no label is set
+func (setting BlockSettingDefinition) label() string {
+	if setting.Label == "" {
+		return setting.Name
+	}
+
+	return setting.Label
+}
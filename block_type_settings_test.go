@@ -0,0 +1,193 @@
+package cmsstore
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// settingsBlockType is a BlockType implementation declaring a settings schema
+type settingsBlockType struct {
+	mockBlockType
+}
+
+func (t *settingsBlockType) SettingsSchema() []BlockSettingDefinition {
+	one, twelve := 1.0, 12.0
+
+	return []BlockSettingDefinition{
+		{Name: "title", Label: "Title", Type: BLOCK_SETTING_TYPE_STRING, Required: true},
+		{Name: "columns", Label: "Columns", Type: BLOCK_SETTING_TYPE_INT, Default: "3", MinValue: &one, MaxValue: &twelve},
+		{Name: "dark", Label: "Dark", Type: BLOCK_SETTING_TYPE_BOOL, Default: "false"},
+		{Name: "layout", Label: "Layout", Type: BLOCK_SETTING_TYPE_ENUM, Default: "grid", Options: []BlockSettingOption{
+			{Value: "grid", Label: "Grid"},
+			{Value: "list", Label: "List"},
+		}},
+		{Name: "anchor", Label: "Anchor", Type: BLOCK_SETTING_TYPE_STRING, Validation: `^[a-z-]+$`},
+	}
+}
+
+func TestBlockTypeSettingsSchema(t *testing.T) {
+	if schema := BlockTypeSettingsSchema(&mockBlockType{}); schema != nil {
+		t.Errorf("expected nil schema, got %v", schema)
+	}
+
+	if schema := BlockTypeSettingsSchema(&settingsBlockType{}); len(schema) != 5 {
+		t.Errorf("expected 5 settings, got %d", len(schema))
+	}
+}
+
+func TestBlockSettingsValues_Defaults(t *testing.T) {
+	block := NewBlock()
+	if err := block.SetMeta("columns", "4"); err != nil {
+		t.Fatalf("failed to set meta: %v", err)
+	}
+
+	values := BlockSettingsValues(block, (&settingsBlockType{}).SettingsSchema())
+
+	if values["columns"] != "4" {
+		t.Errorf("expected columns %q, got %q", "4", values["columns"])
+	}
+	if values["layout"] != "grid" {
+		t.Errorf("expected default layout %q, got %q", "grid", values["layout"])
+	}
+	if values["title"] != "" {
+		t.Errorf("expected empty title, got %q", values["title"])
+	}
+}
+
+func TestValidateBlockSettings(t *testing.T) {
+	schema := (&settingsBlockType{}).SettingsSchema()
+
+	tests := []struct {
+		name        string
+		values      map[string]string
+		expectedErr string
+	}{
+		{"valid", map[string]string{"title": "Hello", "columns": "12", "dark": "true", "layout": "list", "anchor": "top"}, ""},
+		{"missing required", map[string]string{"columns": "2"}, "Title is required"},
+		{"not an integer", map[string]string{"title": "Hello", "columns": "2.5"}, "Columns must be a number"},
+		{"below minimum", map[string]string{"title": "Hello", "columns": "0"}, "Columns must be at least 1"},
+		{"above maximum", map[string]string{"title": "Hello", "columns": "13"}, "Columns must be at most 12"},
+		{"invalid bool", map[string]string{"title": "Hello", "dark": "yes"}, "Dark must be true or false"},
+		{"invalid option", map[string]string{"title": "Hello", "layout": "table"}, "Layout has an invalid value: table"},
+		{"invalid format", map[string]string{"title": "Hello", "anchor": "Top"}, "Anchor has an invalid format"},
+		{"unknown setting", map[string]string{"title": "Hello", "color": "red"}, "unknown setting: color"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBlockSettings(schema, tt.values)
+
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != tt.expectedErr {
+				t.Errorf("expected error %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyBlockSettings_PartialUpdate(t *testing.T) {
+	schema := (&settingsBlockType{}).SettingsSchema()
+	block := NewBlock()
+
+	if err := ApplyBlockSettings(block, schema, map[string]string{"columns": "2"}); err == nil {
+		t.Fatal("expected required error, got nil")
+	}
+	if block.Meta("columns") != "" {
+		t.Errorf("expected no meta to be set on error, got %q", block.Meta("columns"))
+	}
+
+	if err := ApplyBlockSettings(block, schema, map[string]string{"title": "Hello"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// The title is already set, so it is not required again
+	if err := ApplyBlockSettings(block, schema, map[string]string{"columns": "2"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if block.Meta("title") != "Hello" || block.Meta("columns") != "2" {
+		t.Errorf("expected title and columns to be set, got %q and %q", block.Meta("title"), block.Meta("columns"))
+	}
+	if block.Meta("layout") != "" {
+		t.Errorf("expected layout not to be set, got %q", block.Meta("layout"))
+	}
+}
+
+func TestBlockSettingsFromRequest(t *testing.T) {
+	form := url.Values{
+		"title":   {"  Hello  "},
+		"columns": {"4"},
+		"dark":    {"on"},
+	}
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	values := BlockSettingsFromRequest(r, (&settingsBlockType{}).SettingsSchema())
+
+	if values["title"] != "Hello" {
+		t.Errorf("expected trimmed title, got %q", values["title"])
+	}
+	if values["dark"] != "true" {
+		t.Errorf("expected dark %q, got %q", "true", values["dark"])
+	}
+	if values["layout"] != "" {
+		t.Errorf("expected empty layout, got %q", values["layout"])
+	}
+}
+
+func TestBlockSettingsFromJSON(t *testing.T) {
+	values, err := BlockSettingsFromJSON(map[string]any{"title": "Hello", "columns": float64(4), "dark": true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if values["columns"] != "4" || values["dark"] != "true" || values["title"] != "Hello" {
+		t.Errorf("unexpected values: %v", values)
+	}
+
+	if _, err := BlockSettingsFromJSON(map[string]any{"title": []any{"a"}}); err == nil {
+		t.Error("expected error for an array value")
+	}
+}
+
+func TestBlockSettingsJSONSchema(t *testing.T) {
+	jsonSchema := BlockSettingsJSONSchema((&settingsBlockType{}).SettingsSchema())
+
+	properties := jsonSchema["properties"].(map[string]any)
+
+	columns := properties["columns"].(map[string]any)
+	if columns["type"] != "integer" || columns["minimum"] != 1.0 || columns["maximum"] != 12.0 {
+		t.Errorf("unexpected columns schema: %v", columns)
+	}
+
+	if columns["default"] != 3 {
+		t.Errorf("expected columns default to be the integer 3, got %#v", columns["default"])
+	}
+
+	dark := properties["dark"].(map[string]any)
+	if dark["type"] != "boolean" || dark["default"] != false {
+		t.Errorf("expected dark default to be the boolean false, got %#v", dark["default"])
+	}
+
+	layout := properties["layout"].(map[string]any)
+	if layout["default"] != "grid" {
+		t.Errorf("expected layout default to be the string grid, got %#v", layout["default"])
+	}
+
+	if enum, ok := layout["enum"].([]string); !ok || len(enum) != 2 {
+		t.Errorf("expected layout enum, got %v", layout["enum"])
+	}
+
+	if required, ok := jsonSchema["required"].([]string); !ok || len(required) != 1 || required[0] != "title" {
+		t.Errorf("expected title to be required, got %v", jsonSchema["required"])
+	}
+}
@@ -0,0 +1,379 @@
+package cmsstore
+
+import (
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Block visibility device classes
+const (
+	BLOCK_VISIBILITY_DEVICE_DESKTOP = "desktop"
+	BLOCK_VISIBILITY_DEVICE_TABLET  = "tablet"
+	BLOCK_VISIBILITY_DEVICE_MOBILE  = "mobile"
+)
+
+// Block visibility logged in states
+const (
+	BLOCK_VISIBILITY_LOGGED_IN_YES = "yes"
+	BLOCK_VISIBILITY_LOGGED_IN_NO  = "no"
+)
+
+// BLOCK_VISIBILITY_DATE_FORMAT is the format of the visibility dates (UTC)
+const BLOCK_VISIBILITY_DATE_FORMAT = "2006-01-02 15:04:05"
+
+// blockVisibilityDateLayouts are the accepted layouts of the visibility
+// dates, i.e. as posted by a datetime-local input
+var blockVisibilityDateLayouts = []string{
+	BLOCK_VISIBILITY_DATE_FORMAT,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// BlockVisibility are the rules deciding whether an active block is displayed
+// to a visitor. The rules are stored as JSON in the BLOCK_META_VISIBILITY meta.
+//
+// Business Logic:
+//   - empty rules are not applied, a block without rules is always displayed
+//   - all the non-empty rules must match for the block to be displayed
+//   - the percentage rollout is sticky per visitor and block
+type BlockVisibility struct {
+	// DateFrom is the date (UTC) the block is displayed from
+	DateFrom string `json:"date_from,omitempty"`
+
+	// DateTo is the date (UTC) the block is displayed until
+	DateTo string `json:"date_to,omitempty"`
+
+	// Languages are the languages the block is displayed for
+	Languages []string `json:"languages,omitempty"`
+
+	// Devices are the device classes the block is displayed on,
+	// the BLOCK_VISIBILITY_DEVICE_* constants
+	Devices []string `json:"devices,omitempty"`
+
+	// QueryParam is the query parameter which must be present,
+	// as "name" or "name=value"
+	QueryParam string `json:"query_param,omitempty"`
+
+	// Cookie is the cookie which must be present,
+	// as "name" or "name=value"
+	Cookie string `json:"cookie,omitempty"`
+
+	// LoggedIn is the logged in state of the visitor the block is displayed to,
+	// one of the BLOCK_VISIBILITY_LOGGED_IN_* constants
+	LoggedIn string `json:"logged_in,omitempty"`
+
+	// Percentage is the percentage of the visitors the block is displayed to,
+	// 0 (or 100) for all the visitors
+	Percentage int `json:"percentage,omitempty"`
+}
+
+// BlockVisitor is the visitor the visibility rules are evaluated for
+type BlockVisitor struct {
+	// ID identifies the visitor, for the percentage rollout
+	ID string
+
+	// Language is the language of the rendered page
+	Language string
+
+	// LoggedIn is whether the visitor is logged in to the host application
+	LoggedIn bool
+
+	// LoggedInFunc, if set, replaces LoggedIn and is only called
+	// for the rules requiring the logged in state
+	LoggedInFunc func() bool
+
+	// Now is the time of the visit, the current time if zero
+	Now time.Time
+
+	// Request is the HTTP request of the visit, for the device class,
+	// the query parameters and the cookies
+	Request *http.Request
+}
+
+// IsEmpty returns true if no rule is set
+func (rules BlockVisibility) IsEmpty() bool {
+	return rules.DateFrom == "" &&
+		rules.DateTo == "" &&
+		len(rules.Languages) == 0 &&
+		len(rules.Devices) == 0 &&
+		rules.QueryParam == "" &&
+		rules.Cookie == "" &&
+		rules.LoggedIn == "" &&
+		(rules.Percentage <= 0 || rules.Percentage >= 100)
+}
+
+// Validate checks the rules are well formed
+func (rules BlockVisibility) Validate() error {
+	from, err := parseBlockVisibilityDate(rules.DateFrom)
+	if err != nil {
+		return errors.New("visibility date from is not a valid date: " + rules.DateFrom)
+	}
+
+	to, err := parseBlockVisibilityDateTo(rules.DateTo)
+	if err != nil {
+		return errors.New("visibility date to is not a valid date: " + rules.DateTo)
+	}
+
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return errors.New("visibility date to must be after date from")
+	}
+
+	for _, device := range rules.Devices {
+		if !slices.Contains(BlockVisibilityDevices(), device) {
+			return errors.New("visibility device is not supported: " + device)
+		}
+	}
+
+	if !slices.Contains([]string{"", BLOCK_VISIBILITY_LOGGED_IN_YES, BLOCK_VISIBILITY_LOGGED_IN_NO}, rules.LoggedIn) {
+		return errors.New("visibility logged in must be yes or no: " + rules.LoggedIn)
+	}
+
+	if rules.Percentage < 0 || rules.Percentage > 100 {
+		return errors.New("visibility percentage must be between 0 and 100")
+	}
+
+	return nil
+}
+
+// IsVisible returns true if the block is displayed to the visitor
+func (rules BlockVisibility) IsVisible(blockID string, visitor BlockVisitor) bool {
+	now := visitor.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	if from, err := parseBlockVisibilityDate(rules.DateFrom); err != nil || (!from.IsZero() && now.Before(from)) {
+		return false
+	}
+
+	// The date to is inclusive, to the second of its format
+	if to, err := parseBlockVisibilityDateTo(rules.DateTo); err != nil || (!to.IsZero() && now.Truncate(time.Second).After(to)) {
+		return false
+	}
+
+	if len(rules.Languages) > 0 && !slices.Contains(rules.Languages, visitor.Language) {
+		return false
+	}
+
+	if rules.LoggedIn != "" {
+		loggedIn := visitor.LoggedIn
+		if visitor.LoggedInFunc != nil {
+			loggedIn = visitor.LoggedInFunc()
+		}
+
+		if rules.LoggedIn == BLOCK_VISIBILITY_LOGGED_IN_YES && !loggedIn {
+			return false
+		}
+
+		if rules.LoggedIn == BLOCK_VISIBILITY_LOGGED_IN_NO && loggedIn {
+			return false
+		}
+	}
+
+	r := visitor.Request
+
+	if len(rules.Devices) > 0 {
+		userAgent := ""
+		if r != nil {
+			userAgent = r.UserAgent()
+		}
+
+		if !slices.Contains(rules.Devices, DeviceFromUserAgent(userAgent)) {
+			return false
+		}
+	}
+
+	if rules.QueryParam != "" {
+		if r == nil {
+			return false
+		}
+
+		name, value, hasValue := strings.Cut(rules.QueryParam, "=")
+		query := r.URL.Query()
+
+		if !query.Has(name) || (hasValue && query.Get(name) != value) {
+			return false
+		}
+	}
+
+	if rules.Cookie != "" {
+		if r == nil {
+			return false
+		}
+
+		name, value, hasValue := strings.Cut(rules.Cookie, "=")
+		cookie, err := r.Cookie(name)
+
+		if err != nil || (hasValue && cookie.Value != value) {
+			return false
+		}
+	}
+
+	if rules.Percentage > 0 && rules.Percentage < 100 {
+		return blockVisibilityBucket(blockID, visitor.ID) < rules.Percentage
+	}
+
+	return true
+}
+
+// BlockVisibilityRules returns the visibility rules of the block
+func BlockVisibilityRules(block BlockInterface) (BlockVisibility, error) {
+	rules := BlockVisibility{}
+
+	if block == nil {
+		return rules, nil
+	}
+
+	value := block.Meta(BLOCK_META_VISIBILITY)
+
+	if value == "" {
+		return rules, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return BlockVisibility{}, err
+	}
+
+	return rules, nil
+}
+
+// SetBlockVisibilityRules validates and stores the visibility rules of the block,
+// the dates normalized to BLOCK_VISIBILITY_DATE_FORMAT
+func SetBlockVisibilityRules(block BlockInterface, rules BlockVisibility) error {
+	if block == nil {
+		return errors.New("block is nil")
+	}
+
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	if rules.IsEmpty() {
+		metas, err := block.Metas()
+		if err != nil {
+			return err
+		}
+
+		if _, exists := metas[BLOCK_META_VISIBILITY]; !exists {
+			return nil
+		}
+
+		delete(metas, BLOCK_META_VISIBILITY)
+		return block.SetMetas(metas)
+	}
+
+	from, _ := parseBlockVisibilityDate(rules.DateFrom)
+	to, _ := parseBlockVisibilityDateTo(rules.DateTo)
+	rules.DateFrom = formatBlockVisibilityDate(from)
+	rules.DateTo = formatBlockVisibilityDate(to)
+
+	value, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+
+	return block.SetMeta(BLOCK_META_VISIBILITY, string(value))
+}
+
+// BlockIsVisible returns true if the block is displayed to the visitor,
+// blocks with invalid rules are not displayed
+func BlockIsVisible(block BlockInterface, visitor BlockVisitor) bool {
+	rules, err := BlockVisibilityRules(block)
+	if err != nil {
+		return false
+	}
+
+	return rules.IsVisible(block.ID(), visitor)
+}
+
+// BlockVisibilityDevices returns the supported device classes
+func BlockVisibilityDevices() []string {
+	return []string{
+		BLOCK_VISIBILITY_DEVICE_DESKTOP,
+		BLOCK_VISIBILITY_DEVICE_TABLET,
+		BLOCK_VISIBILITY_DEVICE_MOBILE,
+	}
+}
+
+// DeviceFromUserAgent returns the device class of the User-Agent,
+// one of the BLOCK_VISIBILITY_DEVICE_* constants
+//
+// Business Logic:
+//   - iPads, Android devices without "Mobile" and e-readers are tablets
+//   - iPhones, Android phones and "Mobi" browsers are mobiles
+//   - anything else (including empty and bots) is a desktop
+func DeviceFromUserAgent(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+
+	switch {
+	case strings.Contains(ua, "ipad"),
+		strings.Contains(ua, "tablet"),
+		strings.Contains(ua, "kindle"),
+		strings.Contains(ua, "silk/"),
+		strings.Contains(ua, "android") && !strings.Contains(ua, "mobile"):
+		return BLOCK_VISIBILITY_DEVICE_TABLET
+	case strings.Contains(ua, "mobi"),
+		strings.Contains(ua, "iphone"),
+		strings.Contains(ua, "ipod"),
+		strings.Contains(ua, "android"),
+		strings.Contains(ua, "windows phone"):
+		return BLOCK_VISIBILITY_DEVICE_MOBILE
+	}
+
+	return BLOCK_VISIBILITY_DEVICE_DESKTOP
+}
+
+// blockVisibilityBucket returns the rollout bucket (0-99) of the visitor
+// for the block, the same visitor always falling in the same bucket
+func blockVisibilityBucket(blockID, visitorID string) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(blockID + "|" + visitorID))
+	return int(hash.Sum32() % 100)
+}
+
+// parseBlockVisibilityDate parses a visibility date (UTC), an empty date is zero
+func parseBlockVisibilityDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	var err error
+	for _, layout := range blockVisibilityDateLayouts {
+		var date time.Time
+		if date, err = time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
+// parseBlockVisibilityDateTo parses the visibility date to (UTC), a date
+// without time is the end of the day, the block is displayed the whole day
+func parseBlockVisibilityDateTo(value string) (time.Time, error) {
+	date, err := parseBlockVisibilityDate(value)
+
+	if err == nil && len(strings.TrimSpace(value)) == len(time.DateOnly) {
+		date = date.Add(24*time.Hour - time.Second)
+	}
+
+	return date, err
+}
+
+// formatBlockVisibilityDate formats a visibility date, a zero date is empty
+func formatBlockVisibilityDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.UTC().Format(BLOCK_VISIBILITY_DATE_FORMAT)
+}
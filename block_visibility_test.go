@@ -0,0 +1,187 @@
+package cmsstore
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBlockVisibilityRules_RoundTrip(t *testing.T) {
+	block := NewBlock()
+
+	rules, err := BlockVisibilityRules(block)
+	if err != nil || !rules.IsEmpty() {
+		t.Fatalf("Expected no rules, got %+v (%v)", rules, err)
+	}
+
+	err = SetBlockVisibilityRules(block, BlockVisibility{
+		DateFrom:  "2026-01-01T09:30",
+		Languages: []string{"en", "fr"},
+		Devices:   []string{BLOCK_VISIBILITY_DEVICE_MOBILE},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	rules, err = BlockVisibilityRules(block)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if rules.DateFrom != "2026-01-01 09:30:00" {
+		t.Errorf("Expected the date to be normalized, got %q", rules.DateFrom)
+	}
+
+	if len(rules.Languages) != 2 || len(rules.Devices) != 1 {
+		t.Errorf("Unexpected rules %+v", rules)
+	}
+
+	if err := SetBlockVisibilityRules(block, BlockVisibility{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if block.Meta(BLOCK_META_VISIBILITY) != "" {
+		t.Errorf("Expected empty rules to clear the meta, got %q", block.Meta(BLOCK_META_VISIBILITY))
+	}
+}
+
+func TestBlockVisibilityRules_Validate(t *testing.T) {
+	invalid := []BlockVisibility{
+		{DateFrom: "tomorrow"},
+		{DateFrom: "2026-02-01", DateTo: "2026-01-01"},
+		{Devices: []string{"watch"}},
+		{LoggedIn: "maybe"},
+		{Percentage: 101},
+	}
+
+	for _, rules := range invalid {
+		if err := SetBlockVisibilityRules(NewBlock(), rules); err == nil {
+			t.Errorf("Expected an error for %+v", rules)
+		}
+	}
+}
+
+func TestBlockVisibility_IsVisible(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	r := httptest.NewRequest(http.MethodGet, "/page?preview=1&campaign=summer", nil)
+	r.Header.Set("User-Agent", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Mobile/15E148")
+	r.AddCookie(&http.Cookie{Name: "beta", Value: "on"})
+
+	visitor := BlockVisitor{ID: "visitor", Language: "en", Now: now, Request: r}
+
+	tests := []struct {
+		name     string
+		rules    BlockVisibility
+		loggedIn bool
+		expected bool
+	}{
+		{"no rules", BlockVisibility{}, false, true},
+		{"within dates", BlockVisibility{DateFrom: "2026-06-01", DateTo: "2026-07-01"}, false, true},
+		{"before date from", BlockVisibility{DateFrom: "2026-06-16"}, false, false},
+		{"after date to", BlockVisibility{DateTo: "2026-06-15 11:59:59"}, false, false},
+		{"language", BlockVisibility{Languages: []string{"en"}}, false, true},
+		{"other language", BlockVisibility{Languages: []string{"de"}}, false, false},
+		{"device", BlockVisibility{Devices: []string{BLOCK_VISIBILITY_DEVICE_MOBILE}}, false, true},
+		{"other device", BlockVisibility{Devices: []string{BLOCK_VISIBILITY_DEVICE_DESKTOP}}, false, false},
+		{"query param", BlockVisibility{QueryParam: "preview"}, false, true},
+		{"query param value", BlockVisibility{QueryParam: "campaign=summer"}, false, true},
+		{"query param other value", BlockVisibility{QueryParam: "campaign=winter"}, false, false},
+		{"missing query param", BlockVisibility{QueryParam: "debug"}, false, false},
+		{"cookie", BlockVisibility{Cookie: "beta=on"}, false, true},
+		{"missing cookie", BlockVisibility{Cookie: "session"}, false, false},
+		{"logged in", BlockVisibility{LoggedIn: BLOCK_VISIBILITY_LOGGED_IN_YES}, true, true},
+		{"not logged in", BlockVisibility{LoggedIn: BLOCK_VISIBILITY_LOGGED_IN_YES}, false, false},
+		{"logged out only", BlockVisibility{LoggedIn: BLOCK_VISIBILITY_LOGGED_IN_NO}, true, false},
+		{"all rules must match", BlockVisibility{Languages: []string{"en"}, Cookie: "session"}, false, false},
+	}
+
+	for _, test := range tests {
+		visitor.LoggedIn = test.loggedIn
+
+		if visible := test.rules.IsVisible("block", visitor); visible != test.expected {
+			t.Errorf("%s: expected visible %v, got %v", test.name, test.expected, visible)
+		}
+	}
+}
+
+func TestBlockVisibility_IsVisible_DateToWholeDay(t *testing.T) {
+	rules := BlockVisibility{DateFrom: "2026-06-15", DateTo: "2026-06-15"}
+
+	tests := []struct {
+		now      time.Time
+		expected bool
+	}{
+		{time.Date(2026, 6, 14, 23, 59, 59, 0, time.UTC), false},
+		{time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, 6, 15, 23, 59, 59, 999, time.UTC), true},
+		{time.Date(2026, 6, 16, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, test := range tests {
+		if visible := rules.IsVisible("block", BlockVisitor{Now: test.now}); visible != test.expected {
+			t.Errorf("%s: expected visible %v, got %v", test.now, test.expected, visible)
+		}
+	}
+
+	block := NewBlock()
+
+	if err := SetBlockVisibilityRules(block, rules); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	saved, err := BlockVisibilityRules(block)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if saved.DateTo != "2026-06-15 23:59:59" {
+		t.Errorf("Expected the date to be the end of the day, got %q", saved.DateTo)
+	}
+
+	if !saved.IsVisible("block", BlockVisitor{Now: time.Date(2026, 6, 15, 23, 59, 59, 500, time.UTC)}) {
+		t.Error("Expected the saved rules to display the block the whole day")
+	}
+}
+
+func TestBlockVisibility_Percentage(t *testing.T) {
+	rules := BlockVisibility{Percentage: 30}
+
+	visible := 0
+	for i := range 1000 {
+		visitor := BlockVisitor{ID: fmt.Sprintf("visitor_%d", i)}
+
+		if rules.IsVisible("block", visitor) {
+			visible++
+		}
+
+		if rules.IsVisible("block", visitor) != rules.IsVisible("block", visitor) {
+			t.Fatalf("Expected the rollout to be sticky for %s", visitor.ID)
+		}
+	}
+
+	if visible < 250 || visible > 350 {
+		t.Errorf("Expected about 30%% of the visitors, got %d of 1000", visible)
+	}
+}
+
+func TestDeviceFromUserAgent(t *testing.T) {
+	tests := map[string]string{
+		"": BLOCK_VISIBILITY_DEVICE_DESKTOP,
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/120.0 Safari/537.36":       BLOCK_VISIBILITY_DEVICE_DESKTOP,
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148":     BLOCK_VISIBILITY_DEVICE_MOBILE,
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 Chrome/120.0 Mobile Safari/537.36": BLOCK_VISIBILITY_DEVICE_MOBILE,
+		"Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148":              BLOCK_VISIBILITY_DEVICE_TABLET,
+		"Mozilla/5.0 (Linux; Android 13; SM-X710) AppleWebKit/537.36 Chrome/120.0 Safari/537.36":        BLOCK_VISIBILITY_DEVICE_TABLET,
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)":                      BLOCK_VISIBILITY_DEVICE_DESKTOP,
+	}
+
+	for userAgent, expected := range tests {
+		if device := DeviceFromUserAgent(userAgent); device != expected {
+			t.Errorf("Expected %s for %q, got %s", expected, userAgent, device)
+		}
+	}
+}
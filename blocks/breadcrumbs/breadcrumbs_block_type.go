@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/dracory/cmsstore"
 	"github.com/dracory/form"
@@ -12,6 +14,9 @@ import (
 // BreadcrumbsBlockType represents a breadcrumbs block for navigation
 type BreadcrumbsBlockType struct {
 	store cmsstore.StoreInterface
+
+	// visibilityRules remembers the menus with visibility rules, see CachePolicy
+	visibilityRules cmsstore.MenuVisibilityRulesCache
 }
 
 // NewBreadcrumbsBlockType creates a new breadcrumbs block type
@@ -87,6 +92,55 @@ func (t *BreadcrumbsBlockType) Render(ctx context.Context, block cmsstore.BlockI
 	// Generate breadcrumb items based on current page
 	breadcrumbs := t.generateBreadcrumbs(ctx, block, homeText, homeURL)
 
+	html, err := t.renderBreadcrumbs(ctx, block, breadcrumbs, style, renderingMode, cssClass, cssID, separator, options.Attributes)
+	if err != nil {
+		return "", err
+	}
+
+	if block.Meta(cmsstore.BLOCK_META_BREADCRUMBS_JSON_LD) != "true" {
+		return html, nil
+	}
+
+	// Emit the BreadcrumbList structured data alongside the HTML
+	jsonLdBreadcrumbs := make([]cmsstore.JsonLdBreadcrumb, 0, len(breadcrumbs))
+	for _, breadcrumb := range breadcrumbs {
+		jsonLdBreadcrumbs = append(jsonLdBreadcrumbs, cmsstore.JsonLdBreadcrumb{Name: breadcrumb.Name, URL: breadcrumb.URL})
+	}
+
+	script, err := cmsstore.JsonLdScript(cmsstore.JsonLdBreadcrumbList(ctx, jsonLdBreadcrumbs))
+	if err != nil {
+		return "", err
+	}
+
+	return html + script, nil
+}
+
+// renderBreadcrumbs renders the breadcrumbs with the renderer registered
+// for the rendering mode, if any, or the built-in renderers
+func (t *BreadcrumbsBlockType) renderBreadcrumbs(ctx context.Context, block cmsstore.BlockInterface, breadcrumbs []BreadcrumbItem, style, renderingMode, cssClass, cssID, separator string, attributes map[string]string) (string, error) {
+	if renderer := cmsstore.GetMenuRenderer(cmsstore.BLOCK_TYPE_BREADCRUMBS, renderingMode); renderer != nil {
+		items := make([]*cmsstore.MenuRenderItem, 0, len(breadcrumbs))
+		for _, breadcrumb := range breadcrumbs {
+			items = append(items, &cmsstore.MenuRenderItem{
+				Name:           breadcrumb.Name,
+				URL:            breadcrumb.URL,
+				Active:         breadcrumb.Active,
+				ActiveAncestor: !breadcrumb.Active,
+				Children:       []*cmsstore.MenuRenderItem{},
+			})
+		}
+
+		return renderer.Render(ctx, cmsstore.MenuRenderData{
+			BlockType:  cmsstore.BLOCK_TYPE_BREADCRUMBS,
+			Block:      block,
+			Style:      style,
+			CSSClass:   cssClass,
+			CSSID:      cssID,
+			Attributes: attributes,
+			Items:      items,
+		})
+	}
+
 	// Use the breadcrumbs renderer
 	return renderBreadcrumbsHTML(breadcrumbs, style, renderingMode, cssClass, cssID, separator)
 }
@@ -152,7 +206,7 @@ func (t *BreadcrumbsBlockType) GetAdminFields(block cmsstore.BlockInterface, r *
 			Type:  form.FORM_FIELD_TYPE_SELECT,
 			Value: block.Meta(cmsstore.BLOCK_META_BREADCRUMBS_RENDERING_MODE),
 			Help:  "Choose the rendering framework",
-			Options: []form.FieldOption{
+			Options: renderingModeOptions([]form.FieldOption{
 				{
 					Value: "Plain",
 					Key:   cmsstore.BLOCK_BREADCRUMBS_RENDERING_PLAIN,
@@ -161,7 +215,7 @@ func (t *BreadcrumbsBlockType) GetAdminFields(block cmsstore.BlockInterface, r *
 					Value: "Bootstrap 5",
 					Key:   cmsstore.BLOCK_BREADCRUMBS_RENDERING_BOOTSTRAP5,
 				},
-			},
+			}),
 		}),
 		form.NewField(form.FieldOptions{
 			Label: "Home Text",
@@ -184,6 +238,23 @@ func (t *BreadcrumbsBlockType) GetAdminFields(block cmsstore.BlockInterface, r *
 			Value: block.Meta(cmsstore.BLOCK_META_BREADCRUMBS_SEPARATOR),
 			Help:  "Separator between breadcrumbs (default: /)",
 		}),
+		form.NewField(form.FieldOptions{
+			Label: "Structured Data (JSON-LD)",
+			Name:  "breadcrumbs_json_ld",
+			Type:  form.FORM_FIELD_TYPE_SELECT,
+			Value: block.Meta(cmsstore.BLOCK_META_BREADCRUMBS_JSON_LD),
+			Help:  "Emit the BreadcrumbList for search engines. Not needed if the template has the [[PageJsonLd]] placeholder, which includes it",
+			Options: []form.FieldOption{
+				{
+					Value: "No",
+					Key:   "",
+				},
+				{
+					Value: "Yes",
+					Key:   "true",
+				},
+			},
+		}),
 		form.NewField(form.FieldOptions{
 			Label: "CSS ID",
 			Name:  "breadcrumbs_css_id",
@@ -203,11 +274,42 @@ func (t *BreadcrumbsBlockType) GetAdminFields(block cmsstore.BlockInterface, r *
 	return fieldsContent
 }
 
+// renderingModeOptions adds the rendering modes registered for breadcrumbs
+// blocks (see cmsstore.RegisterMenuRenderer) to the built-in rendering modes
+func renderingModeOptions(options []form.FieldOption) []form.FieldOption {
+	for _, renderer := range cmsstore.GetMenuRenderers(cmsstore.BLOCK_TYPE_BREADCRUMBS) {
+		if !slices.ContainsFunc(options, func(option form.FieldOption) bool { return option.Key == renderer.Mode() }) {
+			options = append(options, form.FieldOption{Value: renderer.Label(), Key: renderer.Mode()})
+		}
+	}
+
+	return options
+}
+
 // GetCustomVariables returns nil as breadcrumbs blocks do not set any custom variables.
 func (t *BreadcrumbsBlockType) GetCustomVariables() []cmsstore.BlockCustomVariable {
 	return nil
 }
 
+// CachePolicy caches the rendered breadcrumbs per page,
+// until the menus or pages are changed. The breadcrumbs are not cached
+// if the menu items of their menu have visibility rules.
+func (t *BreadcrumbsBlockType) CachePolicy(ctx context.Context, block cmsstore.BlockInterface) *cmsstore.BlockCachePolicy {
+	if menuID := block.Meta(cmsstore.BLOCK_META_MENU_ID); menuID != "" {
+		hasRules, err := t.visibilityRules.MenuHasVisibilityRules(ctx, t.store, menuID)
+
+		if err != nil || hasRules {
+			return nil
+		}
+	}
+
+	return &cmsstore.BlockCachePolicy{
+		VaryByLanguage: true,
+		VaryByPath:     true,
+		Dependencies:   []string{cmsstore.CHANGE_KIND_MENUS, cmsstore.CHANGE_KIND_PAGES, cmsstore.CHANGE_KIND_ENTITIES},
+	}
+}
+
 // SaveAdminFields processes form submission and updates the breadcrumbs block.
 func (t *BreadcrumbsBlockType) SaveAdminFields(r *http.Request, block cmsstore.BlockInterface) error {
 	r.ParseForm()
@@ -220,6 +322,7 @@ func (t *BreadcrumbsBlockType) SaveAdminFields(r *http.Request, block cmsstore.B
 	homeURL := r.FormValue("breadcrumbs_home_url")
 	cssClass := r.FormValue("breadcrumbs_css_class")
 	cssID := r.FormValue("breadcrumbs_css_id")
+	jsonLd := r.FormValue("breadcrumbs_json_ld")
 
 	block.SetMeta(cmsstore.BLOCK_META_MENU_ID, menuID)
 	block.SetMeta(cmsstore.BLOCK_META_BREADCRUMBS_STYLE, style)
@@ -229,6 +332,7 @@ func (t *BreadcrumbsBlockType) SaveAdminFields(r *http.Request, block cmsstore.B
 	block.SetMeta(cmsstore.BLOCK_META_BREADCRUMBS_HOME_URL, homeURL)
 	block.SetMeta(cmsstore.BLOCK_META_BREADCRUMBS_CSS_CLASS, cssClass)
 	block.SetMeta(cmsstore.BLOCK_META_BREADCRUMBS_CSS_ID, cssID)
+	block.SetMeta(cmsstore.BLOCK_META_BREADCRUMBS_JSON_LD, jsonLd)
 
 	return nil
 }
@@ -266,8 +370,8 @@ func (t *BreadcrumbsBlockType) generateBreadcrumbs(ctx context.Context, block cm
 	})
 
 	// Get current page from context
-	currentPage, found := getCurrentPageFromContext(ctx)
-	if !found {
+	currentPage := cmsstore.PageFromContext(ctx)
+	if currentPage == nil {
 		// If no current page found, return only home breadcrumb
 		return breadcrumbs
 	}
@@ -275,25 +379,15 @@ func (t *BreadcrumbsBlockType) generateBreadcrumbs(ctx context.Context, block cm
 	// Get menu ID from block configuration
 	menuID := block.Meta(cmsstore.BLOCK_META_MENU_ID)
 	if menuID == "" {
-		// If no menu configured, return home + current page
-		breadcrumbs = append(breadcrumbs, BreadcrumbItem{
-			Name:   currentPage.Name(),
-			URL:    "", // Current page has no URL
-			Active: true,
-		})
-		return breadcrumbs
+		// If no menu configured, return home + page ancestors + current page
+		return append(breadcrumbs, t.buildPagePath(ctx, currentPage)...)
 	}
 
 	// Build breadcrumb path from menu hierarchy
 	menuPath, err := t.buildMenuPath(ctx, menuID, currentPage.ID())
 	if err != nil || len(menuPath) == 0 {
-		// Fallback to home + current page if menu navigation fails
-		breadcrumbs = append(breadcrumbs, BreadcrumbItem{
-			Name:   currentPage.Name(),
-			URL:    "", // Current page has no URL
-			Active: true,
-		})
-		return breadcrumbs
+		// Fallback to the page hierarchy if menu navigation fails
+		return append(breadcrumbs, t.buildPagePath(ctx, currentPage)...)
 	}
 
 	// Add menu path items (excluding home which is already added)
@@ -309,15 +403,34 @@ func (t *BreadcrumbsBlockType) generateBreadcrumbs(ctx context.Context, block cm
 	return breadcrumbs
 }
 
-// getCurrentPageFromContext extracts the current page from the context
-func getCurrentPageFromContext(ctx context.Context) (cmsstore.PageInterface, bool) {
-	type contextKey string
-	const pageContextKey contextKey = "page"
+// buildPagePath builds the breadcrumb path from the page hierarchy,
+// the ancestors of the current page followed by the current page.
+// The ancestors added to the context by the frontend are used, if any.
+func (t *BreadcrumbsBlockType) buildPagePath(ctx context.Context, currentPage cmsstore.PageInterface) []BreadcrumbItem {
+	var path []BreadcrumbItem
+
+	ancestors := cmsstore.PageAncestorsFromContext(ctx)
+
+	if len(ancestors) == 0 && currentPage.ParentID() != "" && t.store != nil {
+		var err error
+		if ancestors, err = t.store.PageAncestors(ctx, currentPage.ID()); err != nil {
+			ancestors = []cmsstore.PageInterface{}
+		}
+	}
 
-	if page, ok := ctx.Value(pageContextKey).(cmsstore.PageInterface); ok {
-		return page, true
+	for _, ancestor := range ancestors {
+		path = append(path, BreadcrumbItem{
+			Name:   ancestor.Name(),
+			URL:    "/" + strings.TrimPrefix(ancestor.Alias(), "/"),
+			Active: false,
+		})
 	}
-	return nil, false
+
+	return append(path, BreadcrumbItem{
+		Name:   currentPage.Name(),
+		URL:    "", // Current page has no URL
+		Active: true,
+	})
 }
 
 // MenuPathItem represents an item in the menu path
@@ -329,9 +442,7 @@ type MenuPathItem struct {
 // buildMenuPath builds the breadcrumb path from menu hierarchy
 func (t *BreadcrumbsBlockType) buildMenuPath(ctx context.Context, menuID, currentPageID string) ([]MenuPathItem, error) {
 	// Get all menu items for the specified menu
-	menuItems, err := t.store.MenuItemList(ctx, cmsstore.MenuItemQuery().
-		SetMenuID(menuID).
-		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE))
+	menuItems, err := t.store.MenuItemsResolve(ctx, menuID)
 	if err != nil {
 		return nil, err
 	}
@@ -345,7 +456,7 @@ func (t *BreadcrumbsBlockType) buildMenuPath(ctx context.Context, menuID, curren
 	// Find the current page in the menu
 	var currentItem cmsstore.MenuItemInterface
 	for _, item := range menuItems {
-		if item.PageID() == currentPageID {
+		if item.PageID() != "" && item.PageID() == currentPageID {
 			currentItem = item
 			break
 		}
@@ -361,24 +472,34 @@ func (t *BreadcrumbsBlockType) buildMenuPath(ctx context.Context, menuID, curren
 
 	// Walk up to root
 	for current != nil {
-		// Get page details for this menu item
-		page, err := t.store.PageFindByID(ctx, current.PageID())
-		if err != nil {
-			return nil, err
+		// Get page details for this menu item, items without a page
+		// (i.e. URLs, generated entities) use their own name and URL
+		var page cmsstore.PageInterface
+		if current.PageID() != "" {
+			page, err = t.store.PageFindByID(ctx, current.PageID())
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		// Determine URL for this item
 		var url string
 		if current.URL() != "" {
 			url = current.URL()
-		} else {
+		} else if page != nil {
 			// Use page alias if no custom URL
 			url = "/" + page.Alias()
 		}
 
+		// Translated menu items keep their label (see MenuItemsResolve)
+		name := current.Name()
+		if page != nil && len(current.Labels()) == 0 {
+			name = page.Name()
+		}
+
 		// Add to beginning of path (reverse order)
 		path = append([]MenuPathItem{{
-			Name: page.Name(),
+			Name: name,
 			URL:  url,
 		}}, path...)
 
@@ -3,6 +3,9 @@ package breadcrumbs
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/dracory/cmsstore"
@@ -132,6 +135,184 @@ func TestBreadcrumbsBlockType_RenderPlain(t *testing.T) {
 	}
 }
 
+// TestBreadcrumbsBlockType_RenderPageHierarchy tests the breadcrumbs from
+// the page hierarchy, when no menu is configured
+func TestBreadcrumbsBlockType_RenderPageHierarchy(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	newPage := func(name, alias, parentID string) cmsstore.PageInterface {
+		page := cmsstore.NewPage().
+			SetSiteID(testutils.SITE_01).
+			SetName(name).
+			SetAlias(alias).
+			SetParentID(parentID)
+
+		if err := store.PageCreate(context.Background(), page); err != nil {
+			t.Fatalf("Failed to create page: %v", err)
+		}
+
+		return page
+	}
+
+	about := newPage("About Us", "/about", "")
+	team := newPage("Our Team", "/about/team", about.ID())
+	lead := newPage("Team Lead", "/about/team/lead", team.ID())
+
+	block := &TestBreadcrumbsBlock{
+		meta: map[string]string{
+			cmsstore.BLOCK_META_BREADCRUMBS_RENDERING_MODE: "plain",
+		},
+	}
+
+	ctx := cmsstore.PageToContext(context.Background(), lead)
+
+	result, err := NewBreadcrumbsBlockType(store).Render(ctx, block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	expected := []string{
+		`href="/about"`,
+		"About Us",
+		`href="/about/team"`,
+		"Our Team",
+		"Team Lead",
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(result, s) {
+			t.Errorf("Expected result to contain %q, got: %s", s, result)
+		}
+	}
+
+	if strings.Contains(result, `href="/about/team/lead"`) {
+		t.Errorf("Expected the current page not to be linked, got: %s", result)
+	}
+}
+
+// TestBreadcrumbsBlockType_RenderTranslatedMenu tests that the breadcrumbs
+// of a menu use the labels and URLs of the visitor's language
+func TestBreadcrumbsBlockType_RenderTranslatedMenu(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	ctx := context.Background()
+
+	about := cmsstore.NewPage().SetSiteID(testutils.SITE_01).SetName("About Us").SetAlias("/about")
+	team := cmsstore.NewPage().SetSiteID(testutils.SITE_01).SetName("Our Team").SetAlias("/about/team")
+
+	for _, page := range []cmsstore.PageInterface{about, team} {
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatalf("Failed to create page: %v", err)
+		}
+	}
+
+	menu := cmsstore.NewMenu().SetSiteID(testutils.SITE_01).SetName("Main")
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatalf("Failed to create menu: %v", err)
+	}
+
+	aboutItem := cmsstore.NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetName("About").
+		SetPageID(about.ID()).
+		SetLabel("fr", "À propos").
+		SetTranslatedURL("fr", "/fr/a-propos").
+		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+
+	teamItem := cmsstore.NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetParentID(aboutItem.ID()).
+		SetName("Team").
+		SetPageID(team.ID()).
+		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+
+	for _, item := range []cmsstore.MenuItemInterface{aboutItem, teamItem} {
+		if err := store.MenuItemCreate(ctx, item); err != nil {
+			t.Fatalf("Failed to create menu item: %v", err)
+		}
+	}
+
+	block := &TestBreadcrumbsBlock{
+		meta: map[string]string{
+			cmsstore.BLOCK_META_BREADCRUMBS_RENDERING_MODE: "plain",
+			cmsstore.BLOCK_META_MENU_ID:                    menu.ID(),
+		},
+	}
+
+	renderCtx := cmsstore.VisitorToContext(cmsstore.PageToContext(ctx, team), cmsstore.BlockVisitor{Language: "fr"})
+
+	result, err := NewBreadcrumbsBlockType(store).Render(renderCtx, block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	for _, s := range []string{`href="/fr/a-propos"`, "À propos", "Our Team"} {
+		if !strings.Contains(result, s) {
+			t.Errorf("Expected result to contain %q, got: %s", s, result)
+		}
+	}
+}
+
+// TestBreadcrumbsBlockType_CachePolicy tests that the breadcrumbs of menus
+// with visibility rules are not cached
+func TestBreadcrumbsBlockType_CachePolicy(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	ctx := context.Background()
+
+	menu := cmsstore.NewMenu().SetSiteID(testutils.SITE_01).SetName("Main")
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatalf("Failed to create menu: %v", err)
+	}
+
+	item := cmsstore.NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetName("Account").
+		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+
+	if err := store.MenuItemCreate(ctx, item); err != nil {
+		t.Fatalf("Failed to create menu item: %v", err)
+	}
+
+	blockType := NewBreadcrumbsBlockType(store)
+	block := &TestBreadcrumbsBlock{
+		meta: map[string]string{
+			cmsstore.BLOCK_META_MENU_ID: menu.ID(),
+		},
+	}
+
+	policy := blockType.CachePolicy(ctx, block)
+	if policy == nil {
+		t.Fatal("Expected the breadcrumbs to be cached")
+	}
+
+	// The items of the menu sources are generated from the entities
+	if !slices.Contains(policy.Dependencies, cmsstore.CHANGE_KIND_ENTITIES) {
+		t.Errorf("Expected the breadcrumbs to depend on the entities, got %v", policy.Dependencies)
+	}
+
+	if err := cmsstore.SetMenuItemVisibilityRules(item, cmsstore.BlockVisibility{LoggedIn: cmsstore.BLOCK_VISIBILITY_LOGGED_IN_YES}); err != nil {
+		t.Fatalf("Failed to set visibility rules: %v", err)
+	}
+
+	if err := store.MenuItemUpdate(ctx, item); err != nil {
+		t.Fatalf("Failed to update menu item: %v", err)
+	}
+
+	if policy := blockType.CachePolicy(ctx, block); policy != nil {
+		t.Errorf("Expected the breadcrumbs not to be cached, got %+v", policy)
+	}
+}
+
 // TestBreadcrumbsBlockType_Validate tests validation functionality
 func TestBreadcrumbsBlockType_Validate(t *testing.T) {
 	store, err := testutils.InitStore(":memory:")
@@ -294,6 +475,10 @@ func (b *TestBreadcrumbsBlock) Editor() string
 func (b *TestBreadcrumbsBlock) SetEditor(editor string) cmsstore.BlockInterface         { return b }
 func (b *TestBreadcrumbsBlock) Handle() string                                          { return "test-handle" }
 func (b *TestBreadcrumbsBlock) SetHandle(handle string) cmsstore.BlockInterface         { return b }
+func (b *TestBreadcrumbsBlock) IsLibrary() bool                                         { return false }
+func (b *TestBreadcrumbsBlock) SetLibrary(isLibrary bool) error                         { return nil }
+func (b *TestBreadcrumbsBlock) LibraryFields() []string                                 { return nil }
+func (b *TestBreadcrumbsBlock) SetLibraryFields(fields []string) error                  { return nil }
 func (b *TestBreadcrumbsBlock) Memo() string                                            { return "" }
 func (b *TestBreadcrumbsBlock) SetMemo(memo string) cmsstore.BlockInterface             { return b }
 func (b *TestBreadcrumbsBlock) Name() string                                            { return "Test Block" }
@@ -357,3 +542,130 @@ func TestBreadcrumbsBlockType_GetCustomVariables(t *testing.T) {
 		t.Errorf("expected nil custom variables for breadcrumbs block, got %v", vars)
 	}
 }
+
+type testBreadcrumbsRenderer struct{}
+
+func (r *testBreadcrumbsRenderer) Mode() string  { return "test_breadcrumbs" }
+func (r *testBreadcrumbsRenderer) Label() string { return "Test Breadcrumbs" }
+
+func (r *testBreadcrumbsRenderer) BlockTypes() []string {
+	return []string{cmsstore.BLOCK_TYPE_BREADCRUMBS}
+}
+
+func (r *testBreadcrumbsRenderer) Render(ctx context.Context, data cmsstore.MenuRenderData) (string, error) {
+	html := data.Style + ":"
+	for _, item := range data.Items {
+		html += item.Name + "=" + item.URL
+		if item.Active {
+			html += "(active)"
+		}
+		html += ";"
+	}
+	return html, nil
+}
+
+// TestBreadcrumbsBlockType_RenderRegisteredMode tests that the breadcrumbs
+// are rendered by the renderer registered for the rendering mode
+func TestBreadcrumbsBlockType_RenderRegisteredMode(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	cmsstore.RegisterMenuRenderer(&testBreadcrumbsRenderer{})
+	defer cmsstore.DefaultMenuRendererRegistry().Unregister("test_breadcrumbs")
+
+	about := cmsstore.NewPage().SetName("About Us").SetAlias("/about")
+	team := cmsstore.NewPage().SetName("Our Team").SetAlias("/about/team").SetParentID(about.ID())
+
+	ctx := cmsstore.PageToContext(context.Background(), team)
+	ctx = cmsstore.PageAncestorsToContext(ctx, []cmsstore.PageInterface{about})
+
+	block := &TestBreadcrumbsBlock{
+		meta: map[string]string{
+			cmsstore.BLOCK_META_BREADCRUMBS_RENDERING_MODE: "test_breadcrumbs",
+		},
+	}
+
+	breadcrumbsBlock := NewBreadcrumbsBlockType(store)
+
+	result, err := breadcrumbsBlock.Render(ctx, block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	expected := "default:Home=/;About Us=/about;Our Team=(active);"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	fields := breadcrumbsBlock.GetAdminFields(block, req).([]form.FieldInterface)
+
+	found := false
+	for _, field := range fields {
+		if field.GetName() != "breadcrumbs_rendering_mode" {
+			continue
+		}
+		for _, option := range field.GetOptions() {
+			found = found || (option.Key == "test_breadcrumbs" && option.Value == "Test Breadcrumbs")
+		}
+	}
+
+	if !found {
+		t.Error("Expected the registered rendering mode in the admin dropdown")
+	}
+}
+
+func TestBreadcrumbsBlockType_RenderJsonLd(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	about := cmsstore.NewPage().SetName("About Us").SetAlias("/about")
+	team := cmsstore.NewPage().SetName("Our Team").SetAlias("/about/team").SetParentID(about.ID())
+
+	req := httptest.NewRequest("GET", "https://example.com/about/team", nil)
+	ctx := cmsstore.RequestToContext(context.Background(), req)
+	ctx = cmsstore.PageToContext(ctx, team)
+	ctx = cmsstore.PageAncestorsToContext(ctx, []cmsstore.PageInterface{about})
+
+	block := &TestBreadcrumbsBlock{
+		meta: map[string]string{
+			cmsstore.BLOCK_META_BREADCRUMBS_RENDERING_MODE: "plain",
+		},
+	}
+
+	breadcrumbsBlock := NewBreadcrumbsBlockType(store)
+
+	result, err := breadcrumbsBlock.Render(ctx, block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	if strings.Contains(result, "application/ld+json") {
+		t.Errorf("Expected no JSON-LD unless enabled, got %q", result)
+	}
+
+	block.meta[cmsstore.BLOCK_META_BREADCRUMBS_JSON_LD] = "true"
+
+	result, err = breadcrumbsBlock.Render(ctx, block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	expected := []string{
+		`<script type="application/ld+json">`,
+		`"@type":"BreadcrumbList"`,
+		`"item":"https://example.com/about"`,
+		`"item":"https://example.com/about/team"`,
+		`"name":"Our Team","position":3`,
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(result, e) {
+			t.Errorf("Expected %s in the result, got %q", e, result)
+		}
+	}
+}
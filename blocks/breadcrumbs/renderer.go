@@ -94,6 +94,7 @@ func renderPlainBreadcrumbs(breadcrumbs []BreadcrumbItem, style, cssClass, cssID
 			// Active breadcrumb (current page)
 			active := hb.Span()
 			active.Class("breadcrumb-item active")
+			active.Attr("aria-current", "page")
 			active.Text(item.Name)
 			nav.AddChild(active)
 		} else {
@@ -0,0 +1,314 @@
+// Package entitylist provides the entity list block type, displaying
+// the custom entities of a type as a paginated list.
+//
+// Each entity is rendered through the item template of the block, in which
+// the placeholders [[attribute_name]] are replaced by the (HTML escaped)
+// values of the attributes of the entity. The entity fields are available
+// as [[id]], [[handle]], [[created_at]] and [[updated_at]].
+//
+// The current page and the sort order are read from the request
+// (the "page" and "sort" query parameters), so the visitors can
+// page through the list, and sort it by the fields allowed in the admin.
+package entitylist
+
+import (
+	"cmp"
+	"context"
+	"html"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/entitystore"
+	"github.com/samber/lo"
+)
+
+// Fields of the entities, available besides the attributes
+// for filtering, sorting and in the item template
+const (
+	fieldID        = "id"
+	fieldHandle    = "handle"
+	fieldCreatedAt = "created_at"
+	fieldUpdatedAt = "updated_at"
+)
+
+// entityFieldColumns maps the entity fields to their entity store columns
+var entityFieldColumns = map[string]string{
+	fieldID:        entitystore.COLUMN_ID,
+	fieldHandle:    entitystore.COLUMN_ENTITY_HANDLE,
+	fieldCreatedAt: entitystore.COLUMN_CREATED_AT,
+	fieldUpdatedAt: entitystore.COLUMN_UPDATED_AT,
+}
+
+// Filter operators
+const (
+	operatorEquals    = "="
+	operatorNotEquals = "!="
+	operatorContains  = "~"
+)
+
+// placeholderRegex matches the [[field]] placeholders of the item template
+var placeholderRegex = regexp.MustCompile(`\[\[\s*([A-Za-z0-9_\-]+)\s*\]\]`)
+
+// listItem is an entity of the list with the values of its fields
+type listItem struct {
+	entity entitystore.EntityInterface
+	values map[string]string
+}
+
+// listFilter is a filter of the list, i.e. category=news
+type listFilter struct {
+	field    string
+	operator string
+	value    string
+}
+
+// matches returns whether the value passes the filter
+func (f listFilter) matches(value string) bool {
+	switch f.operator {
+	case operatorNotEquals:
+		return value != f.value
+	case operatorContains:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(f.value))
+	}
+
+	return value == f.value
+}
+
+// listSort is the sort order of the list
+type listSort struct {
+	field string
+	desc  bool
+}
+
+// parseFilters parses the filters, one per line, as field=value,
+// field!=value or field~value (contains), the invalid lines are skipped
+func parseFilters(text string) []listFilter {
+	filters := []listFilter{}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		for _, operator := range []string{operatorNotEquals, operatorContains, operatorEquals} {
+			field, value, found := strings.Cut(line, operator)
+			field = strings.TrimSpace(field)
+
+			if !found || field == "" {
+				continue
+			}
+
+			filters = append(filters, listFilter{
+				field:    field,
+				operator: operator,
+				value:    strings.TrimSpace(value),
+			})
+
+			break
+		}
+	}
+
+	return filters
+}
+
+// parseSort parses the sort order, the field name for ascending
+// order, prefixed with "-" for descending order, i.e. -created_at.
+// Returns false if the field cannot be sorted by.
+func parseSort(text string, definition cmsstore.CustomEntityDefinition) (listSort, bool) {
+	text = strings.TrimSpace(text)
+	sort := listSort{
+		field: strings.TrimPrefix(text, "-"),
+		desc:  strings.HasPrefix(text, "-"),
+	}
+
+	if _, isEntityField := entityFieldColumns[sort.field]; isEntityField {
+		return sort, true
+	}
+
+	if slices.ContainsFunc(definition.Attributes, func(attribute cmsstore.CustomAttributeDefinition) bool {
+		return attribute.Name == sort.field
+	}) {
+		return sort, true
+	}
+
+	return listSort{}, false
+}
+
+// parseSortable parses the fields the visitors may sort by,
+// separated by commas
+func parseSortable(text string) []string {
+	fields := []string{}
+
+	for _, field := range strings.Split(text, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// queryItems returns the items on the page and the total number of items
+//
+// Business Logic:
+//   - without filters, sorted by an entity field, the page is
+//     queried from the entity store
+//   - otherwise, all the entities of the type are loaded with their
+//     attributes, and filtered, sorted and paged in memory
+func queryItems(ctx context.Context, entityStore *cmsstore.CustomEntityStore, entityType string, filters []listFilter, sort listSort, offset int, limit int) ([]listItem, int, error) {
+	sortOrder := lo.Ternary(sort.desc, cmsstore.SORT_ORDER_DESC, cmsstore.SORT_ORDER_ASC)
+
+	if column, isEntityField := entityFieldColumns[sort.field]; isEntityField && len(filters) == 0 {
+		count, err := entityStore.Count(ctx, entitystore.EntityQueryOptions{
+			EntityType: entityType,
+		})
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entities, err := entityStore.List(ctx, entitystore.EntityQueryOptions{
+			EntityType: entityType,
+			SortBy:     column,
+			SortOrder:  sortOrder,
+			Offset:     uint64(offset),
+			Limit:      uint64(limit),
+		})
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		items, err := loadItems(ctx, entityStore, entities)
+
+		return items, int(count), err
+	}
+
+	entities, err := entityStore.List(ctx, entitystore.EntityQueryOptions{
+		EntityType: entityType,
+		SortBy:     entitystore.COLUMN_CREATED_AT,
+		SortOrder:  sortOrder,
+	})
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, err := loadItems(ctx, entityStore, entities)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items = slices.DeleteFunc(items, func(item listItem) bool {
+		return !matchesFilters(item, filters)
+	})
+
+	// The equal values are kept in the order of creation of the sort order
+	slices.SortStableFunc(items, func(a, b listItem) int {
+		result := compareValues(a.values[sort.field], b.values[sort.field])
+		if sort.desc {
+			return -result
+		}
+		return result
+	})
+
+	total := len(items)
+	start := min(offset, total)
+	end := min(start+limit, total)
+
+	return items[start:end], total, nil
+}
+
+// loadItems loads the attributes of the entities, in bulk
+func loadItems(ctx context.Context, entityStore *cmsstore.CustomEntityStore, entities []entitystore.EntityInterface) ([]listItem, error) {
+	entityIDs := lo.Map(entities, func(entity entitystore.EntityInterface, _ int) string {
+		return entity.GetID()
+	})
+
+	attributeValues, err := entityStore.AttributeValues(ctx, entityIDs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]listItem, 0, len(entities))
+
+	for _, entity := range entities {
+		values := attributeValues[entity.GetID()]
+
+		// The entity fields take precedence over the attributes
+		values[fieldID] = entity.GetID()
+		values[fieldHandle] = entity.GetHandle()
+		values[fieldCreatedAt] = entity.GetCreatedAt()
+		values[fieldUpdatedAt] = entity.GetUpdatedAt()
+
+		items = append(items, listItem{entity: entity, values: values})
+	}
+
+	return items, nil
+}
+
+// matchesFilters returns whether the item passes all the filters
+func matchesFilters(item listItem, filters []listFilter) bool {
+	for _, filter := range filters {
+		if !filter.matches(item.values[filter.field]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareValues compares the values numerically, if both are numbers,
+// or as case insensitive text otherwise
+func compareValues(a, b string) int {
+	aNumber, aErr := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	bNumber, bErr := strconv.ParseFloat(strings.TrimSpace(b), 64)
+
+	if aErr == nil && bErr == nil {
+		return cmp.Compare(aNumber, bNumber)
+	}
+
+	return cmp.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// renderItem renders the item through the template, the placeholders
+// of fields the entity does not have are kept as they are
+func renderItem(template string, item listItem, definition cmsstore.CustomEntityDefinition) string {
+	return placeholderRegex.ReplaceAllStringFunc(template, func(placeholder string) string {
+		field := placeholderRegex.FindStringSubmatch(placeholder)[1]
+
+		value, exists := item.values[field]
+
+		if !exists && !slices.ContainsFunc(definition.Attributes, func(attribute cmsstore.CustomAttributeDefinition) bool {
+			return attribute.Name == field
+		}) {
+			return placeholder
+		}
+
+		// The values are entered by the users, they must not be parsed as [[...]]
+		return cmsstore.EscapeText(value)
+	})
+}
+
+// defaultItemTemplate returns the item template used, when the block
+// has none, showing the attributes of the entity type
+func defaultItemTemplate(definition cmsstore.CustomEntityDefinition) string {
+	template := ""
+
+	for _, attribute := range definition.Attributes {
+		template += `<div class="cms-entity-attribute" data-attribute="` + html.EscapeString(attribute.Name) + `">[[` + attribute.Name + `]]</div>`
+	}
+
+	if template == "" {
+		template = `<div class="cms-entity-attribute" data-attribute="id">[[id]]</div>`
+	}
+
+	return template
+}
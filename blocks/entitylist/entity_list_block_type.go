@@ -0,0 +1,374 @@
+package entitylist
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/form"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/spf13/cast"
+)
+
+// EntityListBlockType renders the custom entities of a type as a list,
+// each entity rendered through the item template of the block.
+type EntityListBlockType struct {
+	store cmsstore.StoreInterface
+}
+
+var _ cmsstore.BlockTypeWithSettings = (*EntityListBlockType)(nil)
+var _ cmsstore.BlockTypeWithCache = (*EntityListBlockType)(nil)
+
+// NewEntityListBlockType creates a new entity list block type
+func NewEntityListBlockType(store cmsstore.StoreInterface) *EntityListBlockType {
+	return &EntityListBlockType{store: store}
+}
+
+// TypeKey returns the unique identifier for entity list blocks.
+func (t *EntityListBlockType) TypeKey() string {
+	return cmsstore.BLOCK_TYPE_ENTITY_LIST
+}
+
+// TypeLabel returns the display name for entity list blocks.
+func (t *EntityListBlockType) TypeLabel() string {
+	return "Entity List"
+}
+
+// GetCustomVariables returns nil, entity list blocks set no custom variables.
+func (t *EntityListBlockType) GetCustomVariables() []cmsstore.BlockCustomVariable {
+	return nil
+}
+
+// CachePolicy caches the rendered list per value of the page and sort
+// query parameters of the list, the only ones it reads, until the
+// entities are changed.
+func (t *EntityListBlockType) CachePolicy(_ context.Context, block cmsstore.BlockInterface) *cmsstore.BlockCachePolicy {
+	prefix := cmsstore.BlockSettingsValues(block, t.SettingsSchema())[cmsstore.BLOCK_META_ENTITY_LIST_PARAM_PREFIX]
+
+	return &cmsstore.BlockCachePolicy{
+		VaryByQueryParams: []string{prefix + "page", prefix + "sort"},
+		Dependencies:      []string{cmsstore.CHANGE_KIND_ENTITIES},
+	}
+}
+
+// SettingsSchema returns the settings of the entity list block.
+func (t *EntityListBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	minPerPage, maxPerPage := 1.0, 100.0
+
+	return []cmsstore.BlockSettingDefinition{
+		{
+			Name:  cmsstore.BLOCK_META_ENTITY_LIST_ITEM_TEMPLATE,
+			Label: "Item Template",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_TEXT,
+			Help:  "The HTML of each item, with the placeholders [[attribute_name]], [[id]], [[handle]], [[created_at]] and [[updated_at]]. Shows all the attributes if not set",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_ENTITY_LIST_FILTERS,
+			Label: "Filters",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_TEXT,
+			Help:  "One filter per line, as attribute=value, attribute!=value or attribute~value (contains)",
+		},
+		{
+			Name:    cmsstore.BLOCK_META_ENTITY_LIST_SORT,
+			Label:   "Sort",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_STRING,
+			Default: "-" + fieldCreatedAt,
+			Help:    "The attribute (or id, handle, created_at, updated_at) to sort by, prefixed with - for descending order",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_ENTITY_LIST_SORTABLE,
+			Label: "Visitor Sortable Fields",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+			Help:  "The fields the visitors may sort by with the sort query parameter, separated by commas, i.e. title,price. The visitors cannot sort the list if empty",
+		},
+		{
+			Name:     cmsstore.BLOCK_META_ENTITY_LIST_PER_PAGE,
+			Label:    "Items Per Page",
+			Type:     cmsstore.BLOCK_SETTING_TYPE_INT,
+			Default:  "10",
+			MinValue: &minPerPage,
+			MaxValue: &maxPerPage,
+		},
+		{
+			Name:    cmsstore.BLOCK_META_ENTITY_LIST_PAGINATION,
+			Label:   "Show Pagination",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_BOOL,
+			Default: "true",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_ENTITY_LIST_PARAM_PREFIX,
+			Label: "Query Parameter Prefix",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+			Help:  "Prefix of the page and sort query parameters, i.e. products_ for products_page, needed for several lists on a page",
+		},
+		{
+			Name:    cmsstore.BLOCK_META_ENTITY_LIST_EMPTY_TEXT,
+			Label:   "Empty Text",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_STRING,
+			Default: "No items found.",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_ENTITY_LIST_CSS_CLASS,
+			Label: "CSS Class",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+		},
+	}
+}
+
+// Render renders the entities on the current page, with the pagination.
+// Supports the runtime attributes "class" and "per_page".
+//
+// Business Logic:
+//   - the page and sort order are read from the query parameters
+//     of the request, a sort field not in the visitor sortable fields
+//     (or invalid) is ignored
+//   - the filters are set in the admin only, not read from the request
+//   - a page past the last page shows the last page
+//   - an HTML comment is rendered, if custom entities are not enabled
+//     or the entity type is not registered
+func (t *EntityListBlockType) Render(ctx context.Context, block cmsstore.BlockInterface, opts ...cmsstore.RenderOption) (string, error) {
+	options := &cmsstore.RenderOptions{
+		Attributes: map[string]string{},
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	settings := cmsstore.BlockSettingsValues(block, t.SettingsSchema())
+
+	entityStore := t.entityStore()
+	if entityStore == nil {
+		return "<!-- Custom entities not enabled -->", nil
+	}
+
+	entityType := block.Meta(cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE)
+	definition, found := entityStore.GetEntityDefinition(entityType)
+	if !found {
+		return "<!-- Entity type not found -->", nil
+	}
+
+	perPage := cast.ToInt(settings[cmsstore.BLOCK_META_ENTITY_LIST_PER_PAGE])
+	if value := cast.ToInt(options.Attributes["per_page"]); value > 0 {
+		perPage = value
+	}
+	perPage = max(1, min(100, perPage))
+
+	prefix := settings[cmsstore.BLOCK_META_ENTITY_LIST_PARAM_PREFIX]
+	query := url.Values{}
+	if r := cmsstore.RequestFromContext(ctx); r != nil {
+		query = r.URL.Query()
+	}
+
+	sort, valid := parseSort(query.Get(prefix+"sort"), definition)
+	if valid && !slices.Contains(parseSortable(settings[cmsstore.BLOCK_META_ENTITY_LIST_SORTABLE]), sort.field) {
+		valid = false
+	}
+	if !valid {
+		sort, valid = parseSort(settings[cmsstore.BLOCK_META_ENTITY_LIST_SORT], definition)
+	}
+	if !valid {
+		sort = listSort{field: fieldCreatedAt, desc: true}
+	}
+
+	filters := parseFilters(settings[cmsstore.BLOCK_META_ENTITY_LIST_FILTERS])
+	page := max(1, cast.ToInt(query.Get(prefix+"page")))
+
+	items, total, err := queryItems(ctx, entityStore, entityType, filters, sort, (page-1)*perPage, perPage)
+	if err != nil {
+		return "", err
+	}
+
+	lastPage := max(1, (total+perPage-1)/perPage)
+	if page > lastPage {
+		page = lastPage
+		items, _, err = queryItems(ctx, entityStore, entityType, filters, sort, (page-1)*perPage, perPage)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	cssClass := options.Attributes["class"]
+	if cssClass == "" {
+		cssClass = settings[cmsstore.BLOCK_META_ENTITY_LIST_CSS_CLASS]
+	}
+
+	list := hb.Div().
+		Class("cms-entity-list").
+		ClassIf(cssClass != "", cssClass).
+		Data("entity-type", entityType)
+
+	if len(items) == 0 {
+		return list.
+			Child(hb.Paragraph().Class("cms-entity-list-empty").Text(settings[cmsstore.BLOCK_META_ENTITY_LIST_EMPTY_TEXT])).
+			ToHTML(), nil
+	}
+
+	template := settings[cmsstore.BLOCK_META_ENTITY_LIST_ITEM_TEMPLATE]
+	if strings.TrimSpace(template) == "" {
+		template = defaultItemTemplate(definition)
+	}
+
+	itemsDiv := hb.Div().Class("cms-entity-list-items")
+	for _, item := range items {
+		itemsDiv.Child(hb.Div().
+			Class("cms-entity-list-item").
+			Data("entity-id", item.entity.GetID()).
+			HTML(renderItem(template, item, definition)))
+	}
+
+	showPagination := settings[cmsstore.BLOCK_META_ENTITY_LIST_PAGINATION] == "true" && lastPage > 1
+
+	return list.
+		Child(itemsDiv).
+		ChildIf(showPagination, pagination(query, prefix, page, lastPage)).
+		ToHTML(), nil
+}
+
+// GetAdminFields returns the field for selecting the entity type,
+// the other fields are generated from the settings schema.
+func (t *EntityListBlockType) GetAdminFields(block cmsstore.BlockInterface, r *http.Request) interface{} {
+	entityStore := t.entityStore()
+
+	if entityStore == nil {
+		return []form.FieldInterface{
+			form.NewField(form.FieldOptions{
+				Label: "Entity List Not Available",
+				Type:  form.FORM_FIELD_TYPE_RAW,
+				Value: hb.Div().Class("alert alert-warning").Text("Custom entities are not enabled in this CMS installation.").ToHTML(),
+			}),
+		}
+	}
+
+	definitions := entityStore.GetAllDefinitions()
+	slices.SortFunc(definitions, func(a, b cmsstore.CustomEntityDefinition) int {
+		return strings.Compare(a.TypeLabel, b.TypeLabel)
+	})
+
+	options := []form.FieldOption{
+		{
+			Value: "- Select Entity Type -",
+			Key:   "",
+		},
+	}
+
+	for _, definition := range definitions {
+		options = append(options, form.FieldOption{
+			Value: definition.TypeLabel,
+			Key:   definition.Type,
+		})
+	}
+
+	fields := []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Label:    "Entity Type",
+			Name:     cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE,
+			Type:     form.FORM_FIELD_TYPE_SELECT,
+			Value:    block.Meta(cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE),
+			Required: true,
+			Help:     "Select the type of the entities to list",
+			Options:  options,
+		}),
+	}
+
+	// The placeholders of the selected entity type
+	if definition, found := entityStore.GetEntityDefinition(block.Meta(cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE)); found {
+		placeholders := []string{fieldID, fieldHandle, fieldCreatedAt, fieldUpdatedAt}
+		for _, attribute := range definition.Attributes {
+			placeholders = append(placeholders, attribute.Name)
+		}
+
+		placeholderList := hb.Div().Class("form-text")
+		placeholderList.Child(hb.Span().Text("Available placeholders: "))
+		for i, placeholder := range placeholders {
+			placeholderList.ChildIf(i > 0, hb.Span().Text(", "))
+			placeholderList.Child(hb.NewTag("code").Text("[[" + placeholder + "]]"))
+		}
+
+		fields = append(fields, form.NewField(form.FieldOptions{
+			Label: "Placeholders",
+			Type:  form.FORM_FIELD_TYPE_RAW,
+			Value: hb.Div().Class("mb-3").Child(placeholderList).ToHTML(),
+		}))
+	}
+
+	return fields
+}
+
+// SaveAdminFields saves the selected entity type.
+func (t *EntityListBlockType) SaveAdminFields(r *http.Request, block cmsstore.BlockInterface) error {
+	entityStore := t.entityStore()
+
+	if entityStore == nil {
+		return nil
+	}
+
+	entityType := req.GetStringTrimmed(r, cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE)
+
+	if entityType == "" {
+		return errors.New("entity type selection is required")
+	}
+
+	if _, found := entityStore.GetEntityDefinition(entityType); !found {
+		return errors.New("entity type not found: " + entityType)
+	}
+
+	return block.SetMeta(cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE, entityType)
+}
+
+// entityStore returns the custom entity store, nil if not enabled
+func (t *EntityListBlockType) entityStore() *cmsstore.CustomEntityStore {
+	if t.store == nil || !t.store.CustomEntitiesEnabled() {
+		return nil
+	}
+
+	return t.store.CustomEntityStore()
+}
+
+// pagination renders the (Bootstrap 5) pagination, the page links keep
+// the sort query parameter of the list. The other query parameters are
+// not kept, as the list is cached per value of its parameters only
+func pagination(query url.Values, prefix string, page int, lastPage int) hb.TagInterface {
+	pageURL := func(number int) string {
+		values := url.Values{}
+		if query.Has(prefix + "sort") {
+			values[prefix+"sort"] = query[prefix+"sort"]
+		}
+		values.Set(prefix+"page", strconv.Itoa(number))
+		return "?" + values.Encode()
+	}
+
+	pageItem := func(number int, label string, ariaLabel string, disabled bool, active bool) hb.TagInterface {
+		return hb.LI().
+			Class("page-item").
+			ClassIf(disabled, "disabled").
+			ClassIf(active, "active").
+			Child(hb.Hyperlink().
+				Class("page-link").
+				Href(pageURL(number)).
+				HTML(label).
+				AttrIf(ariaLabel != "", "aria-label", ariaLabel).
+				AttrIf(active, "aria-current", "page").
+				AttrIf(disabled, "aria-disabled", "true").
+				AttrIf(disabled, "tabindex", "-1"))
+	}
+
+	list := hb.UL().Class("pagination")
+	list.Child(pageItem(max(1, page-1), "&laquo;", "Previous", page <= 1, false))
+
+	for number := max(1, page-2); number <= min(lastPage, page+2); number++ {
+		list.Child(pageItem(number, strconv.Itoa(number), "", false, number == page))
+	}
+
+	list.Child(pageItem(min(lastPage, page+1), "&raquo;", "Next", page >= lastPage, false))
+
+	return hb.NewTag("nav").
+		Class("cms-entity-list-pagination").
+		Attr("aria-label", "Pagination").
+		Child(list)
+}
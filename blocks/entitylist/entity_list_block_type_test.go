@@ -0,0 +1,356 @@
+package entitylist
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/form"
+	_ "modernc.org/sqlite"
+)
+
+// initEntityStore creates a store with the "product" entity type
+// and the products seeded
+func initEntityStore(t *testing.T) cmsstore.StoreInterface {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:?parseTime=true")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store, err := cmsstore.NewStore(cmsstore.NewStoreOptions{
+		DB:                    db,
+		BlockTableName:        "block_table",
+		PageTableName:         "page_table",
+		SiteTableName:         "site_table",
+		TemplateTableName:     "template_table",
+		AutomigrateEnabled:    true,
+		CustomEntitiesEnabled: true,
+		CustomEntityDefinitions: []cmsstore.CustomEntityDefinition{
+			{
+				Type:      "product",
+				TypeLabel: "Product",
+				Attributes: []cmsstore.CustomAttributeDefinition{
+					{Name: "title", Type: "string", Label: "Title", Required: true},
+					{Name: "price", Type: "float", Label: "Price"},
+					{Name: "category", Type: "string", Label: "Category"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	products := []map[string]interface{}{
+		{"title": "Apple", "price": 3.5, "category": "fruit"},
+		{"title": "Banana", "price": 1.25, "category": "fruit"},
+		{"title": "Carrot", "price": 12.0, "category": "vegetable"},
+		{"title": "Dates <special>", "price": 7.0, "category": "fruit"},
+	}
+
+	for _, attrs := range products {
+		if _, err := store.CustomEntityStore().Create(context.Background(), "product", attrs, nil, nil); err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+	}
+
+	return store
+}
+
+func newEntityListBlock(t *testing.T, metas map[string]string) cmsstore.BlockInterface {
+	t.Helper()
+
+	block := cmsstore.NewBlock().SetType(cmsstore.BLOCK_TYPE_ENTITY_LIST)
+
+	if err := block.SetMetas(metas); err != nil {
+		t.Fatalf("Failed to set metas: %v", err)
+	}
+
+	return block
+}
+
+// requestContext returns a context with a request to the URL
+func requestContext(target string) context.Context {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	return cmsstore.RequestToContext(context.Background(), r)
+}
+
+func TestParseFilters(t *testing.T) {
+	filters := parseFilters("category = fruit\n\ntitle~an\nprice!=3.5\ninvalid\n=value")
+
+	if len(filters) != 3 {
+		t.Fatalf("Expected 3 filters, got %d: %v", len(filters), filters)
+	}
+
+	expected := []listFilter{
+		{field: "category", operator: operatorEquals, value: "fruit"},
+		{field: "title", operator: operatorContains, value: "an"},
+		{field: "price", operator: operatorNotEquals, value: "3.5"},
+	}
+
+	for i, filter := range expected {
+		if filters[i] != filter {
+			t.Errorf("Expected filter %d to be %v, got %v", i, filter, filters[i])
+		}
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	if compareValues("9", "12") >= 0 {
+		t.Error("Expected numbers to be compared numerically")
+	}
+
+	if compareValues("banana", "Apple") <= 0 {
+		t.Error("Expected text to be compared case insensitively")
+	}
+}
+
+func TestRenderItem_ValuesNotParsed(t *testing.T) {
+	definition := cmsstore.CustomEntityDefinition{
+		Attributes: []cmsstore.CustomAttributeDefinition{{Name: "title"}},
+	}
+
+	item := listItem{values: map[string]string{"title": "[[BLOCK_secret]] <b>"}}
+
+	html := renderItem(`<h3>[[title]]</h3>`, item, definition)
+
+	if html != `<h3>&#91;&#91;BLOCK_secret]] &lt;b&gt;</h3>` {
+		t.Errorf("Expected the value escaped, not to be parsed as a block, got %s", html)
+	}
+}
+
+func TestEntityListBlockType_Render(t *testing.T) {
+	store := initEntityStore(t)
+	blockType := NewEntityListBlockType(store)
+
+	block := newEntityListBlock(t, map[string]string{
+		cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE:   "product",
+		cmsstore.BLOCK_META_ENTITY_LIST_ITEM_TEMPLATE: `<h3>[[title]]</h3><span>[[price]]</span>[[unknown]]`,
+		cmsstore.BLOCK_META_ENTITY_LIST_FILTERS:       "category=fruit",
+		cmsstore.BLOCK_META_ENTITY_LIST_SORT:          "title",
+	})
+
+	html, err := blockType.Render(context.Background(), block)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	apple := strings.Index(html, "<h3>Apple</h3>")
+	banana := strings.Index(html, "<h3>Banana</h3>")
+	dates := strings.Index(html, "<h3>Dates &lt;special&gt;</h3>")
+
+	if apple < 0 || banana < 0 || dates < 0 {
+		t.Fatalf("Expected the fruits with escaped values, got %s", html)
+	}
+
+	if !(apple < banana && banana < dates) {
+		t.Errorf("Expected the items sorted by title, got %s", html)
+	}
+
+	if strings.Contains(html, "Carrot") {
+		t.Errorf("Expected the filtered out item not to be rendered, got %s", html)
+	}
+
+	if !strings.Contains(html, "[[unknown]]") {
+		t.Errorf("Expected the unknown placeholder to be kept, got %s", html)
+	}
+
+	if strings.Contains(html, "pagination") {
+		t.Errorf("Expected no pagination for a single page, got %s", html)
+	}
+}
+
+func TestEntityListBlockType_Render_SortAndPageFromRequest(t *testing.T) {
+	store := initEntityStore(t)
+	blockType := NewEntityListBlockType(store)
+
+	block := newEntityListBlock(t, map[string]string{
+		cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE:   "product",
+		cmsstore.BLOCK_META_ENTITY_LIST_ITEM_TEMPLATE: `[[title]]`,
+		cmsstore.BLOCK_META_ENTITY_LIST_SORT:          "title",
+		cmsstore.BLOCK_META_ENTITY_LIST_SORTABLE:      "title, price",
+		cmsstore.BLOCK_META_ENTITY_LIST_PER_PAGE:      "2",
+		cmsstore.BLOCK_META_ENTITY_LIST_PARAM_PREFIX:  "products_",
+	})
+
+	html, err := blockType.Render(requestContext("/shop?lang=en&products_sort=-price&products_page=2"), block)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	// Sorted by price descending: Carrot, Dates, Apple, Banana
+	if !strings.Contains(html, ">Apple</div>") || !strings.Contains(html, ">Banana</div>") {
+		t.Errorf("Expected the second page with Apple and Banana, got %s", html)
+	}
+
+	if strings.Contains(html, ">Carrot</div>") {
+		t.Errorf("Expected Carrot to be on the first page, got %s", html)
+	}
+
+	if !strings.Contains(html, `aria-current="page"`) {
+		t.Errorf("Expected the current page to be marked, got %s", html)
+	}
+
+	if !strings.Contains(html, `href="?products_page=1&amp;products_sort=-price"`) {
+		t.Errorf("Expected the page links to keep the sort of the list only, got %s", html)
+	}
+
+	// An invalid sort, a field not sortable by the visitors,
+	// and a page past the last page
+	for _, target := range []string{"/shop?products_sort=secret&products_page=99", "/shop?products_sort=-category&products_page=99"} {
+		html, err = blockType.Render(requestContext(target), block)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+
+		if !strings.Contains(html, ">Carrot</div>") || !strings.Contains(html, ">Dates &lt;special&gt;</div>") {
+			t.Errorf("%s: expected the last page sorted by title, got %s", target, html)
+		}
+	}
+}
+
+func TestEntityListBlockType_CachePolicy(t *testing.T) {
+	blockType := NewEntityListBlockType(initEntityStore(t))
+
+	block := newEntityListBlock(t, map[string]string{
+		cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE:  "product",
+		cmsstore.BLOCK_META_ENTITY_LIST_PARAM_PREFIX: "products_",
+	})
+
+	policy := blockType.CachePolicy(context.Background(), block)
+
+	if policy == nil || policy.VaryByQuery {
+		t.Fatalf("Expected the list to be cached per query parameter, got %+v", policy)
+	}
+
+	if strings.Join(policy.VaryByQueryParams, ",") != "products_page,products_sort" {
+		t.Errorf("Expected the page and sort parameters of the list, got %v", policy.VaryByQueryParams)
+	}
+}
+
+func TestEntityListBlockType_Render_EntityPaging(t *testing.T) {
+	store := initEntityStore(t)
+	blockType := NewEntityListBlockType(store)
+
+	block := newEntityListBlock(t, map[string]string{
+		cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE: "product",
+		cmsstore.BLOCK_META_ENTITY_LIST_SORT:        "id",
+	})
+
+	html, err := blockType.Render(context.Background(), block, cmsstore.WithAttributes(map[string]string{
+		"per_page": "3",
+		"class":    "products",
+	}))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if count := strings.Count(html, `class="cms-entity-list-item"`); count != 3 {
+		t.Errorf("Expected 3 items, got %d: %s", count, html)
+	}
+
+	if !strings.Contains(html, `data-attribute="title"`) {
+		t.Errorf("Expected the default template, got %s", html)
+	}
+
+	if !strings.Contains(html, "products") || !strings.Contains(html, "cms-entity-list-pagination") {
+		t.Errorf("Expected the CSS class and the pagination, got %s", html)
+	}
+}
+
+func TestEntityListBlockType_Render_NotFound(t *testing.T) {
+	store := initEntityStore(t)
+
+	block := newEntityListBlock(t, map[string]string{
+		cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE: "unknown",
+	})
+
+	html, err := NewEntityListBlockType(store).Render(context.Background(), block)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if html != "<!-- Entity type not found -->" {
+		t.Errorf("Expected entity type not found comment, got %s", html)
+	}
+
+	html, err = NewEntityListBlockType(nil).Render(context.Background(), block)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if html != "<!-- Custom entities not enabled -->" {
+		t.Errorf("Expected not enabled comment, got %s", html)
+	}
+}
+
+func TestEntityListBlockType_AdminFields(t *testing.T) {
+	store := initEntityStore(t)
+	blockType := NewEntityListBlockType(store)
+
+	block := newEntityListBlock(t, map[string]string{
+		cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE: "product",
+	})
+
+	fields, ok := blockType.GetAdminFields(block, httptest.NewRequest(http.MethodGet, "/", nil)).([]form.FieldInterface)
+	if !ok || len(fields) != 2 {
+		t.Fatalf("Expected the entity type and placeholders fields, got %v", fields)
+	}
+
+	if !strings.Contains(fields[1].GetValue(), "[[price]]") {
+		t.Errorf("Expected the attribute placeholders, got %s", fields[1].GetValue())
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(url.Values{
+		cmsstore.BLOCK_META_ENTITY_LIST_ENTITY_TYPE: {"unknown"},
+	}.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if err := blockType.SaveAdminFields(r, block); err == nil {
+		t.Error("Expected an error for an unknown entity type")
+	}
+}
+
+func TestQueryItems_FiltersAllEntities(t *testing.T) {
+	store := initEntityStore(t)
+	ctx := context.Background()
+
+	// The oldest entity, behind more entities than a page of queries
+	if _, err := store.CustomEntityStore().Create(ctx, "product", map[string]interface{}{"title": "Vintage", "category": "archive"}, nil, nil); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+
+	for range 600 {
+		if _, err := store.CustomEntityStore().Create(ctx, "product", map[string]interface{}{"title": "Extra", "category": "extra"}, nil, nil); err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+	}
+
+	filters := []listFilter{{field: "category", operator: operatorEquals, value: "archive"}}
+
+	items, total, err := queryItems(ctx, store.CustomEntityStore(), "product", filters, listSort{field: "title"}, 0, 10)
+	if err != nil {
+		t.Fatalf("queryItems failed: %v", err)
+	}
+
+	if total != 1 || len(items) != 1 || items[0].values["title"] != "Vintage" {
+		t.Fatalf("Expected the oldest product to match, got %d of %d", len(items), total)
+	}
+
+	_, total, err = queryItems(ctx, store.CustomEntityStore(), "product", nil, listSort{field: "title"}, 0, 10)
+	if err != nil {
+		t.Fatalf("queryItems failed: %v", err)
+	}
+
+	if total < 601 {
+		t.Errorf("Expected all the products counted, got %d", total)
+	}
+}
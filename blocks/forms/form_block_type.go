@@ -0,0 +1,531 @@
+package forms
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/form"
+	"github.com/dracory/hb"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+// Names of the inputs of the field editor in the admin,
+// each row of the editor posting one value of each
+const (
+	adminInputLabel       = "form_fields_label"
+	adminInputName        = "form_fields_name"
+	adminInputType        = "form_fields_type"
+	adminInputRequired    = "form_fields_required"
+	adminInputOptions     = "form_fields_options"
+	adminInputPlaceholder = "form_fields_placeholder"
+)
+
+// adminBlankRows is the number of blank rows of the field editor,
+// for adding new fields
+const adminBlankRows = 3
+
+// SubmissionHook is called after a submission is stored, i.e. to send
+// a notification. The errors are logged and do not fail the submission
+type SubmissionHook func(ctx context.Context, block cmsstore.BlockInterface, submission cmsstore.FormSubmissionInterface) error
+
+// FormBlockType renders a form with the fields configured in the admin,
+// and handles its submissions.
+type FormBlockType struct {
+	store  cmsstore.StoreInterface
+	secret []byte
+	hooks  []SubmissionHook
+	now    func() time.Time
+}
+
+var _ cmsstore.BlockTypeWithSettings = (*FormBlockType)(nil)
+var _ cmsstore.BlockTypeWithCache = (*FormBlockType)(nil)
+
+// Option configures the form block type
+type Option func(*FormBlockType)
+
+// WithSecret sets the secret signing the CSRF tokens. Must be the same
+// for all the instances of the application, so that a form rendered by
+// one instance can be submitted to another
+func WithSecret(secret []byte) Option {
+	return func(t *FormBlockType) {
+		if len(secret) > 0 {
+			t.secret = secret
+		}
+	}
+}
+
+// WithSubmissionHooks adds hooks called after a submission is stored
+func WithSubmissionHooks(hooks ...SubmissionHook) Option {
+	return func(t *FormBlockType) {
+		for _, hook := range hooks {
+			if hook != nil {
+				t.hooks = append(t.hooks, hook)
+			}
+		}
+	}
+}
+
+// NewFormBlockType creates a new form block type. Without a secret,
+// a random secret is generated, invalidating the rendered forms
+// when the application restarts
+func NewFormBlockType(store cmsstore.StoreInterface, opts ...Option) *FormBlockType {
+	t := &FormBlockType{
+		store: store,
+		now:   time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	if len(t.secret) == 0 {
+		t.secret = make([]byte, 32)
+		_, _ = rand.Read(t.secret)
+	}
+
+	return t
+}
+
+// TypeKey returns the unique identifier for form blocks.
+func (t *FormBlockType) TypeKey() string {
+	return cmsstore.BLOCK_TYPE_FORM
+}
+
+// TypeLabel returns the display name for form blocks.
+func (t *FormBlockType) TypeLabel() string {
+	return "Form"
+}
+
+// GetCustomVariables returns nil, form blocks set no custom variables.
+func (t *FormBlockType) GetCustomVariables() []cmsstore.BlockCustomVariable {
+	return nil
+}
+
+// CachePolicy returns nil, the CSRF token of the rendered form is
+// bound to the visitor, so the form is not cached.
+func (t *FormBlockType) CachePolicy(_ context.Context, _ cmsstore.BlockInterface) *cmsstore.BlockCachePolicy {
+	return nil
+}
+
+// SettingsSchema returns the settings of the form block.
+func (t *FormBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	minRateLimit, maxRateLimit := 0.0, 1000.0
+
+	return []cmsstore.BlockSettingDefinition{
+		{
+			Name:    cmsstore.BLOCK_META_FORM_SUBMIT_LABEL,
+			Label:   "Submit Button Label",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_STRING,
+			Default: "Submit",
+		},
+		{
+			Name:    cmsstore.BLOCK_META_FORM_SUCCESS_MESSAGE,
+			Label:   "Success Message",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_TEXT,
+			Default: "Thank you, your submission has been received.",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_FORM_REDIRECT_URL,
+			Label: "Redirect URL",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+			Help:  "The URL to redirect to after a submission, shows the success message on the page if not set",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_FORM_NOTIFY_EMAIL,
+			Label: "Notification Email",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+			Help:  "The email address notified of the submissions, if the application has configured an email notification hook",
+		},
+		{
+			Name:     cmsstore.BLOCK_META_FORM_RATE_LIMIT,
+			Label:    "Rate Limit",
+			Type:     cmsstore.BLOCK_SETTING_TYPE_INT,
+			Default:  "5",
+			MinValue: &minRateLimit,
+			MaxValue: &maxRateLimit,
+			Help:     "The maximum submissions per hour from an IP address, 0 for no limit",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_FORM_CSS_CLASS,
+			Label: "CSS Class",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+		},
+	}
+}
+
+// Render renders the form, or the success message after a submission.
+// Supports the runtime attribute "class".
+//
+// Business Logic:
+//   - the success message is shown, if the query parameter
+//     cms_form_success is the ID of the block
+//   - after an invalid submission of the block, the form shows
+//     the errors and keeps the submitted values
+//   - the CSRF token is bound to the visitor key (see WithVisitorKey)
+//   - an HTML comment is rendered, if forms are not enabled
+func (t *FormBlockType) Render(ctx context.Context, block cmsstore.BlockInterface, opts ...cmsstore.RenderOption) (string, error) {
+	options := &cmsstore.RenderOptions{
+		Attributes: map[string]string{},
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if t.store == nil || !t.store.FormsEnabled() {
+		return "<!-- Forms not enabled -->", nil
+	}
+
+	settings := cmsstore.BlockSettingsValues(block, t.SettingsSchema())
+
+	cssClass := options.Attributes["class"]
+	if cssClass == "" {
+		cssClass = settings[cmsstore.BLOCK_META_FORM_CSS_CLASS]
+	}
+
+	wrapper := hb.Div().
+		Class("cms-form").
+		ClassIf(cssClass != "", cssClass).
+		Data("form-id", block.ID())
+
+	if r := cmsstore.RequestFromContext(ctx); r != nil && r.URL.Query().Get(QUERY_SUCCESS) == block.ID() {
+		return wrapper.
+			Child(hb.Div().
+				Class("alert alert-success cms-form-success").
+				Attr("role", "status").
+				Text(settings[cmsstore.BLOCK_META_FORM_SUCCESS_MESSAGE])).
+			ToHTML(), nil
+	}
+
+	result := SubmitResultFromContext(ctx)
+	if result != nil && result.BlockID != block.ID() {
+		result = nil
+	}
+
+	values := map[string]string{}
+	errs := map[string]string{}
+	if result != nil {
+		values = result.Values
+		errs = result.Errors
+	}
+
+	htmlForm := hb.Form().
+		Method(http.MethodPost).
+		Attr("novalidate", "novalidate").
+		Child(hb.Input().Type(hb.TYPE_HIDDEN).Name(INPUT_FORM_ID).Value(block.ID())).
+		Child(hb.Input().Type(hb.TYPE_HIDDEN).Name(INPUT_TOKEN).Value(newToken(t.secret, block.ID(), visitorKeyFromContext(ctx), t.now()))).
+		Child(honeypot(block.ID()))
+
+	if message := errs[""]; message != "" {
+		htmlForm.Child(hb.Div().Class("alert alert-danger cms-form-error").Attr("role", "alert").Text(message))
+	}
+
+	for _, field := range Fields(block) {
+		htmlForm.Child(renderField(block.ID(), field, echoValue(values[field.Name]), errs[field.Name]))
+	}
+
+	htmlForm.Child(hb.Button().
+		Type(hb.TYPE_SUBMIT).
+		Class("btn btn-primary").
+		Text(settings[cmsstore.BLOCK_META_FORM_SUBMIT_LABEL]))
+
+	html := wrapper.Child(htmlForm).ToHTML()
+
+	return strings.ReplaceAll(html, echoedSquareBracket, cmsstore.ESCAPED_SQUARE_BRACKET), nil
+}
+
+// echoedSquareBracket stands for the "[" of the submitted values, echoed back
+// in the form. The values are escaped by hb, so the bracket is replaced by its
+// character reference once the form is rendered, and the values are not parsed
+// as [[...]] blocks, translations or placeholders.
+const echoedSquareBracket = "\uFDD0"
+
+// echoValue returns the submitted value to echo back in the form,
+// with the echoedSquareBracket for its square brackets
+func echoValue(value string) string {
+	value = strings.ReplaceAll(value, echoedSquareBracket, "")
+	return strings.ReplaceAll(value, "[", echoedSquareBracket)
+}
+
+// GetAdminFields returns the field editor of the form, the other
+// fields are generated from the settings schema.
+func (t *FormBlockType) GetAdminFields(block cmsstore.BlockInterface, r *http.Request) interface{} {
+	if t.store == nil || !t.store.FormsEnabled() {
+		return []form.FieldInterface{
+			form.NewField(form.FieldOptions{
+				Label: "Form Not Available",
+				Type:  form.FORM_FIELD_TYPE_RAW,
+				Value: hb.Div().Class("alert alert-warning").Text("Forms are not enabled in this CMS installation.").ToHTML(),
+			}),
+		}
+	}
+
+	fields := Fields(block)
+
+	table := hb.Table().Class("table table-sm align-middle").
+		Child(hb.Thead().Child(hb.TR().
+			Child(hb.TH().Text("Label")).
+			Child(hb.TH().Text("Name")).
+			Child(hb.TH().Text("Type")).
+			Child(hb.TH().Text("Required")).
+			Child(hb.TH().Text("Options")).
+			Child(hb.TH().Text("Placeholder"))))
+
+	tbody := hb.Tbody()
+	for _, field := range fields {
+		tbody.Child(adminFieldRow(field))
+	}
+	for i := 0; i < adminBlankRows; i++ {
+		tbody.Child(adminFieldRow(Field{Type: FIELD_TYPE_TEXT}))
+	}
+
+	help := hb.Div().Class("form-text").
+		Text("The name is derived from the label if empty. The options of the select and radio fields are separated by commas. Clear the label and the name of a row to remove the field.")
+
+	return []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Label: "Fields",
+			Type:  form.FORM_FIELD_TYPE_RAW,
+			Value: hb.Div().Class("mb-3 cms-form-fields-editor").
+				Child(hb.Label().Class("form-label").Text("Fields")).
+				Child(hb.Div().Class("table-responsive").Child(table.Child(tbody))).
+				Child(help).
+				ToHTML(),
+		}),
+	}
+}
+
+// SaveAdminFields saves the fields of the form from the field editor.
+//
+// Business Logic:
+//   - rows without a label and a name are skipped
+//   - the name is derived from the label if empty
+//   - the form must have at least one field, with unique names
+func (t *FormBlockType) SaveAdminFields(r *http.Request, block cmsstore.BlockInterface) error {
+	if t.store == nil || !t.store.FormsEnabled() {
+		return nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+
+	labels := r.PostForm[adminInputLabel]
+	names := r.PostForm[adminInputName]
+	types := r.PostForm[adminInputType]
+	required := r.PostForm[adminInputRequired]
+	options := r.PostForm[adminInputOptions]
+	placeholders := r.PostForm[adminInputPlaceholder]
+
+	at := func(values []string, i int) string {
+		if i < len(values) {
+			return strings.TrimSpace(values[i])
+		}
+		return ""
+	}
+
+	fields := []Field{}
+
+	for i := range labels {
+		label := at(labels, i)
+		name := at(names, i)
+
+		if label == "" && name == "" {
+			continue
+		}
+
+		if name == "" {
+			name = fieldName(label)
+		}
+
+		if label == "" {
+			label = name
+		}
+
+		field := Field{
+			Name:        name,
+			Label:       label,
+			Type:        at(types, i),
+			Required:    at(required, i) == "yes",
+			Placeholder: at(placeholders, i),
+		}
+
+		for _, option := range strings.Split(at(options, i), ",") {
+			if option = strings.TrimSpace(option); option != "" {
+				field.Options = append(field.Options, option)
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	if err := SetFields(block, fields); err != nil {
+		return errors.New("form fields: " + err.Error())
+	}
+
+	return nil
+}
+
+// rateLimit returns the maximum submissions per hour from an IP address
+func (t *FormBlockType) rateLimit(block cmsstore.BlockInterface) int {
+	settings := cmsstore.BlockSettingsValues(block, t.SettingsSchema())
+	return max(0, cast.ToInt(settings[cmsstore.BLOCK_META_FORM_RATE_LIMIT]))
+}
+
+// honeypot renders the honeypot input, moved off screen so that people
+// do not see it, while the bots fill it in
+func honeypot(blockID string) hb.TagInterface {
+	id := "cms-form-" + blockID + "-" + INPUT_HONEYPOT
+
+	return hb.Div().
+		Style("position:absolute;left:-10000px;top:auto;width:1px;height:1px;overflow:hidden;").
+		Attr("aria-hidden", "true").
+		Child(hb.Label().Attr("for", id).Text("Leave this field empty")).
+		Child(hb.Input().
+			Type(hb.TYPE_TEXT).
+			ID(id).
+			Name(INPUT_HONEYPOT).
+			Attr("tabindex", "-1").
+			Attr("autocomplete", "off"))
+}
+
+// renderField renders the field, with its value and error
+func renderField(blockID string, field Field, value string, errorMessage string) hb.TagInterface {
+	id := "cms-form-" + blockID + "-" + field.Name
+	invalid := errorMessage != ""
+
+	group := hb.Div().Class("mb-3 cms-form-field").Data("field", field.Name)
+
+	label := hb.Label().
+		Class(lo.Ternary(field.Type == FIELD_TYPE_CHECKBOX, "form-check-label", "form-label")).
+		Attr("for", id).
+		Text(field.Label).
+		ChildIf(field.Required, hb.Span().Class("text-danger").Attr("aria-hidden", "true").Text(" *"))
+
+	feedback := hb.Div().Class("invalid-feedback d-block").ID(id + "-error").Text(errorMessage)
+
+	switch field.Type {
+	case FIELD_TYPE_TEXTAREA:
+		group.Child(label).Child(hb.TextArea().
+			Class("form-control").
+			ClassIf(invalid, "is-invalid").
+			ID(id).
+			Name(field.Name).
+			Attr("rows", "5").
+			Attr("maxlength", strconv.Itoa(field.maxLength())).
+			AttrIf(field.Placeholder != "", "placeholder", field.Placeholder).
+			AttrIf(field.Required, "required", "required").
+			AttrIf(invalid, "aria-invalid", "true").
+			AttrIf(invalid, "aria-describedby", id+"-error").
+			Text(value))
+
+	case FIELD_TYPE_SELECT:
+		selectInput := hb.Select().
+			Class("form-select").
+			ClassIf(invalid, "is-invalid").
+			ID(id).
+			Name(field.Name).
+			AttrIf(field.Required, "required", "required").
+			AttrIf(invalid, "aria-invalid", "true").
+			AttrIf(invalid, "aria-describedby", id+"-error").
+			Child(hb.Option().Value("").Text(lo.Ternary(field.Placeholder != "", field.Placeholder, "- Select -")))
+
+		for _, option := range field.Options {
+			selectInput.Child(hb.Option().
+				Value(option).
+				Text(option).
+				AttrIf(option == value, "selected", "selected"))
+		}
+
+		group.Child(label).Child(selectInput)
+
+	case FIELD_TYPE_RADIO:
+		fieldset := hb.NewTag("fieldset").
+			AttrIf(invalid, "aria-invalid", "true").
+			AttrIf(invalid, "aria-describedby", id+"-error").
+			Child(hb.NewTag("legend").
+				Class("form-label fs-6").
+				Text(field.Label).
+				ChildIf(field.Required, hb.Span().Class("text-danger").Attr("aria-hidden", "true").Text(" *")))
+
+		for i, option := range field.Options {
+			optionID := id + "-" + strconv.Itoa(i)
+			fieldset.Child(hb.Div().Class("form-check").
+				Child(hb.Input().
+					Type(hb.TYPE_RADIO).
+					Class("form-check-input").
+					ClassIf(invalid, "is-invalid").
+					ID(optionID).
+					Name(field.Name).
+					Value(option).
+					AttrIf(option == value, "checked", "checked")).
+				Child(hb.Label().Class("form-check-label").Attr("for", optionID).Text(option)))
+		}
+
+		group.Child(fieldset)
+
+	case FIELD_TYPE_CHECKBOX:
+		group.Class("form-check").
+			Child(hb.Input().
+				Type(hb.TYPE_CHECKBOX).
+				Class("form-check-input").
+				ClassIf(invalid, "is-invalid").
+				ID(id).
+				Name(field.Name).
+				Value(checkboxValue).
+				AttrIf(value == checkboxValue, "checked", "checked").
+				AttrIf(field.Required, "required", "required").
+				AttrIf(invalid, "aria-invalid", "true").
+				AttrIf(invalid, "aria-describedby", id+"-error")).
+			Child(label)
+
+	default:
+		group.Child(label).Child(hb.Input().
+			Type(field.Type).
+			Class("form-control").
+			ClassIf(invalid, "is-invalid").
+			ID(id).
+			Name(field.Name).
+			Value(value).
+			AttrIf(field.Type != FIELD_TYPE_NUMBER && field.Type != FIELD_TYPE_DATE, "maxlength", strconv.Itoa(field.maxLength())).
+			AttrIf(field.Placeholder != "", "placeholder", field.Placeholder).
+			AttrIf(field.Required, "required", "required").
+			AttrIf(invalid, "aria-invalid", "true").
+			AttrIf(invalid, "aria-describedby", id+"-error"))
+	}
+
+	return group.ChildIf(invalid, feedback)
+}
+
+// adminFieldRow renders a row of the field editor
+func adminFieldRow(field Field) hb.TagInterface {
+	typeSelect := hb.Select().Class("form-select form-select-sm").Name(adminInputType)
+	for _, fieldType := range fieldTypes {
+		typeSelect.Child(hb.Option().
+			Value(fieldType.Value).
+			Text(fieldType.Label).
+			AttrIf(fieldType.Value == field.Type, "selected", "selected"))
+	}
+
+	requiredSelect := hb.Select().Class("form-select form-select-sm").Name(adminInputRequired).
+		Child(hb.Option().Value("no").Text("No")).
+		Child(hb.Option().Value("yes").Text("Yes").AttrIf(field.Required, "selected", "selected"))
+
+	input := func(name string, value string) hb.TagInterface {
+		return hb.Input().Type(hb.TYPE_TEXT).Class("form-control form-control-sm").Name(name).Value(value)
+	}
+
+	return hb.TR().
+		Child(hb.TD().Child(input(adminInputLabel, field.Label))).
+		Child(hb.TD().Child(input(adminInputName, field.Name))).
+		Child(hb.TD().Child(typeSelect)).
+		Child(hb.TD().Child(requiredSelect)).
+		Child(hb.TD().Child(input(adminInputOptions, strings.Join(field.Options, ", ")))).
+		Child(hb.TD().Child(input(adminInputPlaceholder, field.Placeholder)))
+}
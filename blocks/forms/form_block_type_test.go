@@ -0,0 +1,449 @@
+package forms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+	_ "modernc.org/sqlite"
+)
+
+var testSecret = []byte("test-secret")
+
+// testVisitorKey is the visitor key cookie of the test submissions
+const testVisitorKey = "test-visitor-key"
+
+// initFormBlock creates a form block type and a contact form block
+func initFormBlock(t *testing.T) (*FormBlockType, cmsstore.StoreInterface, cmsstore.BlockInterface) {
+	t.Helper()
+
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	blockType := NewFormBlockType(store, WithSecret(testSecret))
+
+	block := cmsstore.NewBlock().
+		SetID("FormBlock" + strings.ReplaceAll(t.Name(), "/", "")).
+		SetSiteID("FormSite").
+		SetType(cmsstore.BLOCK_TYPE_FORM)
+
+	err = SetFields(block, []Field{
+		{Name: "name", Label: "Name", Type: FIELD_TYPE_TEXT, Required: true},
+		{Name: "email", Label: "Email", Type: FIELD_TYPE_EMAIL, Required: true},
+		{Name: "topic", Label: "Topic", Type: FIELD_TYPE_SELECT, Options: []string{"Sales", "Support"}},
+		{Name: "message", Label: "Message", Type: FIELD_TYPE_TEXTAREA},
+	})
+	if err != nil {
+		t.Fatalf("Failed to set fields: %v", err)
+	}
+
+	return blockType, store, block
+}
+
+// postRequest returns a POST request submitting the values, with a token
+// of a form rendered the age ago for the visitor of testVisitorKey
+func postRequest(blockType *FormBlockType, block cmsstore.BlockInterface, values url.Values, age time.Duration) *http.Request {
+	values.Set(INPUT_FORM_ID, block.ID())
+	values.Set(INPUT_TOKEN, newToken(blockType.secret, block.ID(), testVisitorKey, blockType.now().Add(-age)))
+
+	r := httptest.NewRequest(http.MethodPost, "http://example.com/contact?ref=home", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Origin", "http://example.com")
+	r.AddCookie(&http.Cookie{Name: COOKIE_VISITOR_KEY, Value: testVisitorKey})
+	r.RemoteAddr = "192.0.2.1:1234"
+
+	return r
+}
+
+func validValues() url.Values {
+	return url.Values{
+		"name":    {"Jane"},
+		"email":   {"jane@example.com"},
+		"topic":   {"Support"},
+		"message": {"Hello"},
+	}
+}
+
+func countSubmissions(t *testing.T, store cmsstore.StoreInterface, blockID string) int64 {
+	t.Helper()
+
+	count, err := store.FormSubmissionCount(context.Background(), cmsstore.FormSubmissionQuery().SetBlockID(blockID))
+	if err != nil {
+		t.Fatalf("Failed to count submissions: %v", err)
+	}
+
+	return count
+}
+
+func TestFormBlockTypeRender(t *testing.T) {
+	blockType, _, block := initFormBlock(t)
+
+	html, err := blockType.Render(context.Background(), block)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	for _, expected := range []string{
+		`method="POST"`,
+		`name="` + INPUT_FORM_ID + `" type="hidden" value="` + block.ID() + `"`,
+		`name="` + INPUT_TOKEN + `"`,
+		`name="` + INPUT_HONEYPOT + `"`,
+		`id="cms-form-` + block.ID() + `-email"`,
+		`type="email"`,
+		`<textarea`,
+		`<option value="Support">Support</option>`,
+		`>Submit</button>`,
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected %q in %s", expected, html)
+		}
+	}
+}
+
+func TestFormBlockTypeRenderVisitorKey(t *testing.T) {
+	blockType, _, block := initFormBlock(t)
+
+	r := WithVisitorKey(httptest.NewRequest(http.MethodGet, "/contact", nil))
+
+	html, err := blockType.Render(cmsstore.RequestToContext(r.Context(), r), block)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	VisitorKeyCookieWrite(recorder, r)
+
+	cookies := recorder.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != COOKIE_VISITOR_KEY || cookies[0].Value == "" || !cookies[0].HttpOnly {
+		t.Fatalf("Expected the visitor key cookie, got %v", cookies)
+	}
+
+	_, token, _ := strings.Cut(html, `name="`+INPUT_TOKEN+`" type="hidden" value="`)
+	token, _, _ = strings.Cut(token, `"`)
+
+	if err := verifyToken(testSecret, block.ID(), cookies[0].Value, token, time.Now().Add(time.Minute)); err != nil {
+		t.Errorf("Expected the token to be bound to the visitor key, got %v", err)
+	}
+
+	// The key of the cookie is kept
+	r = httptest.NewRequest(http.MethodGet, "/contact", nil)
+	r.AddCookie(cookies[0])
+	r = WithVisitorKey(r)
+
+	if key := visitorKeyFromContext(r.Context()); key != cookies[0].Value {
+		t.Errorf("Expected the visitor key of the cookie, got %q", key)
+	}
+
+	// No cookie is set if no form is rendered
+	recorder = httptest.NewRecorder()
+	VisitorKeyCookieWrite(recorder, WithVisitorKey(httptest.NewRequest(http.MethodGet, "/", nil)))
+
+	if cookies := recorder.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("Expected no cookie, got %v", cookies)
+	}
+}
+
+func TestFormBlockTypeRenderSuccessAndErrors(t *testing.T) {
+	blockType, _, block := initFormBlock(t)
+
+	r := httptest.NewRequest(http.MethodGet, "/contact?"+QUERY_SUCCESS+"="+block.ID(), nil)
+	html, _ := blockType.Render(cmsstore.RequestToContext(context.Background(), r), block)
+
+	if !strings.Contains(html, "alert-success") || strings.Contains(html, "<form") {
+		t.Errorf("Expected the success message only, got %s", html)
+	}
+
+	ctx := SubmitResultToContext(context.Background(), &SubmitResult{
+		BlockID: block.ID(),
+		Errors:  map[string]string{"email": "Email must be a valid email address."},
+		Values:  map[string]string{"name": "<Jane>", "email": "jane"},
+	})
+	html, _ = blockType.Render(ctx, block)
+
+	for _, expected := range []string{"is-invalid", "Email must be a valid email address.", `value="&lt;Jane&gt;"`} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected %q in %s", expected, html)
+		}
+	}
+}
+
+func TestFormBlockTypeRenderNotEnabled(t *testing.T) {
+	blockType := NewFormBlockType(nil)
+
+	html, err := blockType.Render(context.Background(), cmsstore.NewBlock())
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if html != "<!-- Forms not enabled -->" {
+		t.Errorf("Expected not enabled comment, got %s", html)
+	}
+}
+
+func TestFormBlockTypeSubmitValid(t *testing.T) {
+	blockType, store, block := initFormBlock(t)
+
+	var hooked cmsstore.FormSubmissionInterface
+	WithSubmissionHooks(func(ctx context.Context, block cmsstore.BlockInterface, submission cmsstore.FormSubmissionInterface) error {
+		hooked = submission
+		return nil
+	})(blockType)
+
+	r := postRequest(blockType, block, validValues(), time.Minute)
+	result := blockType.Submit(context.Background(), block, r)
+
+	if !result.Success || len(result.Errors) > 0 {
+		t.Fatalf("Expected success, got %v", result.Errors)
+	}
+
+	if result.RedirectURL != "/contact?"+QUERY_SUCCESS+"="+block.ID()+"&ref=home" {
+		t.Errorf("Unexpected redirect URL: %s", result.RedirectURL)
+	}
+
+	if result.Submission == nil || hooked == nil || hooked.ID() != result.Submission.ID() {
+		t.Fatal("Expected the submission to be stored and the hook called")
+	}
+
+	if result.Submission.FormValue("email") != "jane@example.com" || result.Submission.IPAddress() != "192.0.2.1" {
+		t.Errorf("Unexpected submission: %v", result.Submission.Data())
+	}
+
+	if countSubmissions(t, store, block.ID()) != 1 {
+		t.Error("Expected 1 stored submission")
+	}
+}
+
+func TestFormBlockTypeSubmitInvalid(t *testing.T) {
+	blockType, store, block := initFormBlock(t)
+
+	values := validValues()
+	values.Set("name", "")
+	values.Set("email", "not-an-email")
+	values.Set("topic", "Other")
+
+	result := blockType.Submit(context.Background(), block, postRequest(blockType, block, values, time.Minute))
+
+	if result.Success {
+		t.Fatal("Expected the submission to fail")
+	}
+
+	for _, name := range []string{"name", "email", "topic"} {
+		if result.Errors[name] == "" {
+			t.Errorf("Expected an error for %s, got %v", name, result.Errors)
+		}
+	}
+
+	if result.Values["email"] != "not-an-email" {
+		t.Errorf("Expected the submitted values to be kept, got %v", result.Values)
+	}
+
+	if countSubmissions(t, store, block.ID()) != 0 {
+		t.Error("Expected no stored submission")
+	}
+}
+
+func TestFormBlockTypeSubmitSpam(t *testing.T) {
+	blockType, store, block := initFormBlock(t)
+
+	values := validValues()
+	values.Set(INPUT_HONEYPOT, "http://spam.example.com")
+
+	result := blockType.Submit(context.Background(), block, postRequest(blockType, block, values, time.Minute))
+	if !result.Success || result.Submission != nil {
+		t.Error("Expected the honeypot submission to be reported as accepted, without storing it")
+	}
+
+	result = blockType.Submit(context.Background(), block, postRequest(blockType, block, validValues(), time.Second))
+	if !result.Success || result.Submission != nil {
+		t.Error("Expected the too fast submission to be reported as accepted, without storing it")
+	}
+
+	if countSubmissions(t, store, block.ID()) != 0 {
+		t.Error("Expected no stored submission")
+	}
+}
+
+func TestFormBlockTypeSubmitForged(t *testing.T) {
+	blockType, _, block := initFormBlock(t)
+
+	r := postRequest(blockType, block, validValues(), time.Minute)
+	r.Header.Set("Origin", "http://evil.example.com")
+
+	if result := blockType.Submit(context.Background(), block, r); result.Success || result.Errors[""] == "" {
+		t.Error("Expected the cross origin submission to fail")
+	}
+
+	values := validValues()
+	values.Set(INPUT_FORM_ID, block.ID())
+	values.Set(INPUT_TOKEN, newToken([]byte("other-secret"), block.ID(), testVisitorKey, time.Now().Add(-time.Minute)))
+	r = httptest.NewRequest(http.MethodPost, "http://example.com/contact", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Origin", "http://example.com")
+	r.AddCookie(&http.Cookie{Name: COOKIE_VISITOR_KEY, Value: testVisitorKey})
+
+	if result := blockType.Submit(context.Background(), block, r); result.Success || result.Errors[""] == "" {
+		t.Error("Expected the submission with an invalid token to fail")
+	}
+
+	// The token of a form rendered for another visitor
+	r = postRequest(blockType, block, validValues(), time.Minute)
+	r.Header.Del("Cookie")
+	r.AddCookie(&http.Cookie{Name: COOKIE_VISITOR_KEY, Value: "other-visitor-key"})
+
+	if result := blockType.Submit(context.Background(), block, r); result.Success || result.Errors[""] == "" {
+		t.Error("Expected the submission with the visitor key of another visitor to fail")
+	}
+
+	r = postRequest(blockType, block, validValues(), time.Minute)
+	r.Header.Del("Cookie")
+
+	if result := blockType.Submit(context.Background(), block, r); result.Success || result.Errors[""] == "" {
+		t.Error("Expected the submission without the visitor key cookie to fail")
+	}
+
+	r = postRequest(blockType, block, validValues(), time.Minute)
+	r.Header.Del("Origin")
+
+	if result := blockType.Submit(context.Background(), block, r); result.Success || result.Errors[""] == "" {
+		t.Error("Expected the submission without origin and referer to fail")
+	}
+
+	r.Header.Set("Referer", "http://example.com/contact")
+
+	if result := blockType.Submit(context.Background(), block, r); !result.Success {
+		t.Errorf("Expected the submission with the referer of the host to succeed, got %v", result.Errors)
+	}
+
+	if result := blockType.Submit(context.Background(), block, postRequest(blockType, block, validValues(), 25*time.Hour)); result.Success || !strings.Contains(result.Errors[""], "expired") {
+		t.Errorf("Expected the expired token to fail, got %v", result.Errors)
+	}
+}
+
+func TestFormBlockTypeSubmitRateLimit(t *testing.T) {
+	blockType, store, block := initFormBlock(t)
+
+	if err := block.SetMeta(cmsstore.BLOCK_META_FORM_RATE_LIMIT, "2"); err != nil {
+		t.Fatalf("Failed to set meta: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if result := blockType.Submit(context.Background(), block, postRequest(blockType, block, validValues(), time.Minute)); !result.Success {
+			t.Fatalf("Expected submission %d to succeed, got %v", i+1, result.Errors)
+		}
+	}
+
+	result := blockType.Submit(context.Background(), block, postRequest(blockType, block, validValues(), time.Minute))
+	if result.Success || !strings.Contains(result.Errors[""], "Too many") {
+		t.Errorf("Expected the rate limit error, got %v", result.Errors)
+	}
+
+	if countSubmissions(t, store, block.ID()) != 2 {
+		t.Error("Expected 2 stored submissions")
+	}
+}
+
+func TestFormBlockTypeSaveAdminFields(t *testing.T) {
+	blockType, _, block := initFormBlock(t)
+
+	values := url.Values{
+		adminInputLabel:       {"Your Email", "Plan", ""},
+		adminInputName:        {"", "plan", ""},
+		adminInputType:        {FIELD_TYPE_EMAIL, FIELD_TYPE_RADIO, FIELD_TYPE_TEXT},
+		adminInputRequired:    {"yes", "no", "no"},
+		adminInputOptions:     {"", "Basic, Pro ,", ""},
+		adminInputPlaceholder: {"you@example.com", "", ""},
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if err := blockType.SaveAdminFields(r, block); err != nil {
+		t.Fatalf("SaveAdminFields failed: %v", err)
+	}
+
+	fields := Fields(block)
+	if len(fields) != 2 {
+		t.Fatalf("Expected 2 fields, got %v", fields)
+	}
+
+	if fields[0].Name != "your_email" || !fields[0].Required || fields[0].Placeholder != "you@example.com" {
+		t.Errorf("Unexpected first field: %+v", fields[0])
+	}
+
+	if fields[1].Type != FIELD_TYPE_RADIO || strings.Join(fields[1].Options, "|") != "Basic|Pro" {
+		t.Errorf("Unexpected second field: %+v", fields[1])
+	}
+
+	values[adminInputName] = []string{"plan", "plan", ""}
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if err := blockType.SaveAdminFields(r, block); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("Expected duplicate field name error, got %v", err)
+	}
+}
+
+func TestEmailNotificationHook(t *testing.T) {
+	_, _, block := initFormBlock(t)
+
+	submission := cmsstore.NewFormSubmission()
+	if err := submission.SetFormData(map[string]string{"name": "Jane", "email": "jane@example.com"}); err != nil {
+		t.Fatalf("Failed to set form data: %v", err)
+	}
+
+	sent := 0
+	var sentTo, sentBody string
+	hook := EmailNotificationHook(func(ctx context.Context, to string, subject string, body string) error {
+		sent++
+		sentTo, sentBody = to, body
+		return nil
+	})
+
+	if err := hook(context.Background(), block, submission); err != nil || sent != 0 {
+		t.Fatalf("Expected no email without notification email, got %d sent, error %v", sent, err)
+	}
+
+	if err := block.SetMeta(cmsstore.BLOCK_META_FORM_NOTIFY_EMAIL, "admin@example.com"); err != nil {
+		t.Fatalf("Failed to set meta: %v", err)
+	}
+
+	if err := hook(context.Background(), block, submission); err != nil || sent != 1 {
+		t.Fatalf("Expected the email to be sent, got %d sent, error %v", sent, err)
+	}
+
+	if sentTo != "admin@example.com" || !strings.Contains(sentBody, "Email: jane@example.com") {
+		t.Errorf("Unexpected email to %s: %s", sentTo, sentBody)
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	now := time.Now()
+	token := newToken(testSecret, "Block1", "Key1", now.Add(-time.Minute))
+
+	if err := verifyToken(testSecret, "Block1", "Key1", token, now); err != nil {
+		t.Errorf("Expected the token to be valid, got %v", err)
+	}
+
+	if err := verifyToken(testSecret, "Block2", "Key1", token, now); err != errTokenInvalid {
+		t.Errorf("Expected the token of another block to be invalid, got %v", err)
+	}
+
+	if err := verifyToken(testSecret, "Block1", "Key2", token, now); err != errTokenInvalid {
+		t.Errorf("Expected the token of another visitor to be invalid, got %v", err)
+	}
+
+	if err := verifyToken(testSecret, "Block1", "", token, now); err != errTokenInvalid {
+		t.Errorf("Expected the token without visitor key to be invalid, got %v", err)
+	}
+
+	if err := verifyToken(testSecret, "Block1", "Key1", "garbage", now); err != errTokenInvalid {
+		t.Errorf("Expected the garbage token to be invalid, got %v", err)
+	}
+}
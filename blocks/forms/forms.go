@@ -0,0 +1,383 @@
+// Package forms provides the form block type, rendering a form with the
+// fields configured in the admin and storing its submissions.
+//
+// The form is posted to the page it is displayed on. The frontend passes
+// the POST request to Submit, which checks the request for spam and forgery
+// (CSRF token bound to the visitor key cookie, origin, honeypot field,
+// minimum fill time, rate limit),
+// validates the values, stores the submission and calls the submission hooks.
+//
+// A valid submission redirects (Post/Redirect/Get) to the redirect URL of
+// the block, or back to the page showing the success message. An invalid
+// submission renders the page again, the form showing the errors and
+// keeping the submitted values.
+package forms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dracory/cmsstore"
+)
+
+// Names of the inputs added to each form
+const (
+	// INPUT_FORM_ID is the input holding the ID of the form block
+	INPUT_FORM_ID = "cms_form_id"
+
+	// INPUT_TOKEN is the input holding the CSRF token
+	INPUT_TOKEN = "cms_form_token"
+
+	// COOKIE_VISITOR_KEY is the cookie holding the random key of the
+	// visitor, the CSRF tokens are bound to (double-submit cookie)
+	COOKIE_VISITOR_KEY = "cms_form_key"
+
+	// INPUT_HONEYPOT is the hidden input, which only the bots fill in
+	INPUT_HONEYPOT = "cms_form_website"
+
+	// QUERY_SUCCESS is the query parameter, with the ID of the form block,
+	// showing the success message after a submission
+	QUERY_SUCCESS = "cms_form_success"
+)
+
+// Field types
+const (
+	FIELD_TYPE_TEXT     = "text"
+	FIELD_TYPE_EMAIL    = "email"
+	FIELD_TYPE_TEL      = "tel"
+	FIELD_TYPE_URL      = "url"
+	FIELD_TYPE_NUMBER   = "number"
+	FIELD_TYPE_DATE     = "date"
+	FIELD_TYPE_TEXTAREA = "textarea"
+	FIELD_TYPE_SELECT   = "select"
+	FIELD_TYPE_RADIO    = "radio"
+	FIELD_TYPE_CHECKBOX = "checkbox"
+)
+
+// fieldTypes are the field types, with their labels in the admin
+var fieldTypes = []struct {
+	Value string
+	Label string
+}{
+	{FIELD_TYPE_TEXT, "Text"},
+	{FIELD_TYPE_EMAIL, "Email"},
+	{FIELD_TYPE_TEL, "Phone"},
+	{FIELD_TYPE_URL, "URL"},
+	{FIELD_TYPE_NUMBER, "Number"},
+	{FIELD_TYPE_DATE, "Date"},
+	{FIELD_TYPE_TEXTAREA, "Text Area"},
+	{FIELD_TYPE_SELECT, "Select"},
+	{FIELD_TYPE_RADIO, "Radio Buttons"},
+	{FIELD_TYPE_CHECKBOX, "Checkbox"},
+}
+
+const (
+	// tokenMinAge is the minimum time to fill in the form,
+	// faster submissions are made by bots
+	tokenMinAge = 2 * time.Second
+
+	// tokenMaxAge is the time the form can be submitted after it is rendered
+	tokenMaxAge = 24 * time.Hour
+
+	// checkboxValue is the value of a checked checkbox
+	checkboxValue = "yes"
+)
+
+// fieldNameRegex matches the valid field names
+var fieldNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Field is a field of the form
+type Field struct {
+	Name        string   `json:"name"`
+	Label       string   `json:"label"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required,omitempty"`
+	Placeholder string   `json:"placeholder,omitempty"`
+	Options     []string `json:"options,omitempty"`
+	MaxLength   int      `json:"max_length,omitempty"`
+}
+
+// maxLength returns the maximum length of the value of the field
+func (f Field) maxLength() int {
+	if f.MaxLength > 0 {
+		return f.MaxLength
+	}
+
+	if f.Type == FIELD_TYPE_TEXTAREA {
+		return 5000
+	}
+
+	return 255
+}
+
+// Fields returns the fields of the form block
+func Fields(block cmsstore.BlockInterface) []Field {
+	fields := []Field{}
+
+	if block == nil || block.Meta(cmsstore.BLOCK_META_FORM_FIELDS) == "" {
+		return fields
+	}
+
+	if err := json.Unmarshal([]byte(block.Meta(cmsstore.BLOCK_META_FORM_FIELDS)), &fields); err != nil {
+		return []Field{}
+	}
+
+	return fields
+}
+
+// SetFields validates and sets the fields of the form block
+func SetFields(block cmsstore.BlockInterface, fields []Field) error {
+	if err := validateFields(fields); err != nil {
+		return err
+	}
+
+	fieldsJSON, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	return block.SetMeta(cmsstore.BLOCK_META_FORM_FIELDS, string(fieldsJSON))
+}
+
+// validateFields validates the field definitions
+func validateFields(fields []Field) error {
+	if len(fields) == 0 {
+		return errors.New("the form must have at least one field")
+	}
+
+	names := map[string]bool{}
+
+	for _, field := range fields {
+		if !fieldNameRegex.MatchString(field.Name) || strings.HasPrefix(field.Name, "cms_form_") {
+			return errors.New("invalid field name: " + field.Name + ", use lowercase letters, digits and underscores")
+		}
+
+		if names[field.Name] {
+			return errors.New("duplicate field name: " + field.Name)
+		}
+		names[field.Name] = true
+
+		if !isFieldType(field.Type) {
+			return errors.New("invalid type of the field " + field.Name + ": " + field.Type)
+		}
+
+		if (field.Type == FIELD_TYPE_SELECT || field.Type == FIELD_TYPE_RADIO) && len(field.Options) == 0 {
+			return errors.New("the field " + field.Name + " must have options")
+		}
+	}
+
+	return nil
+}
+
+// isFieldType returns whether the field type is supported
+func isFieldType(fieldType string) bool {
+	for _, t := range fieldTypes {
+		if t.Value == fieldType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldName returns a field name derived from the label, i.e. "Your Email" => "your_email"
+func fieldName(label string) string {
+	name := strings.Trim(regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(label), "_"), "_")
+
+	if name != "" && (name[0] < 'a' || name[0] > 'z') {
+		name = "field_" + name
+	}
+
+	return name
+}
+
+// == SUBMIT RESULT ==========================================================
+
+type submitResultContextKey struct{}
+
+// SubmitResult is the result of a form submission
+type SubmitResult struct {
+	// BlockID is the ID of the form block submitted
+	BlockID string
+
+	// Success is true if the submission was accepted. Spam submissions
+	// are reported as accepted, but are not stored
+	Success bool
+
+	// Errors are the validation errors, by field name,
+	// the errors of the form itself have an empty field name
+	Errors map[string]string
+
+	// Values are the submitted values, by field name
+	Values map[string]string
+
+	// RedirectURL is the URL to redirect to, after a successful submission
+	RedirectURL string
+
+	// Submission is the stored submission, nil if not stored
+	Submission cmsstore.FormSubmissionInterface
+
+	// HookErrors are the errors returned by the submission hooks,
+	// which do not fail the submission
+	HookErrors []error
+}
+
+// SubmitResultToContext adds the result of the form submission to the
+// context, for the form to render its errors and submitted values
+func SubmitResultToContext(ctx context.Context, result *SubmitResult) context.Context {
+	return context.WithValue(ctx, submitResultContextKey{}, result)
+}
+
+// SubmitResultFromContext returns the result of the form submission
+// of the request, nil if none
+func SubmitResultFromContext(ctx context.Context) *SubmitResult {
+	result, _ := ctx.Value(submitResultContextKey{}).(*SubmitResult)
+	return result
+}
+
+// == TOKEN ==================================================================
+
+var (
+	errTokenInvalid  = errors.New("invalid token")
+	errTokenExpired  = errors.New("expired token")
+	errTokenTooEarly = errors.New("token used too early")
+)
+
+// newToken returns the CSRF token of the form block, signing the form
+// block ID, the key of the visitor and the time the form is rendered
+func newToken(secret []byte, blockID string, visitorKey string, now time.Time) string {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	return timestamp + "." + tokenSignature(secret, blockID, visitorKey, timestamp)
+}
+
+// verifyToken verifies the CSRF token of the form block
+//
+// Business Logic:
+//   - the visitor key (see COOKIE_VISITOR_KEY) is required
+//   - the signature must match the form block, visitor key and time
+//   - tokens older than tokenMaxAge are expired
+//   - tokens younger than tokenMinAge are submitted by bots
+func verifyToken(secret []byte, blockID string, visitorKey string, token string, now time.Time) error {
+	if visitorKey == "" {
+		return errTokenInvalid
+	}
+
+	timestamp, signature, found := strings.Cut(token, ".")
+
+	if !found || !hmac.Equal([]byte(signature), []byte(tokenSignature(secret, blockID, visitorKey, timestamp))) {
+		return errTokenInvalid
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errTokenInvalid
+	}
+
+	age := now.Sub(time.Unix(seconds, 0))
+
+	if age > tokenMaxAge {
+		return errTokenExpired
+	}
+
+	if age < tokenMinAge {
+		return errTokenTooEarly
+	}
+
+	return nil
+}
+
+// tokenSignature returns the HMAC-SHA256 signature of the token
+func tokenSignature(secret []byte, blockID string, visitorKey string, timestamp string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(blockID + "|" + visitorKey + "|" + timestamp))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// == VISITOR KEY ============================================================
+
+type visitorKeyContextKey struct{}
+
+// visitorKey is the key of the visitor the rendered forms are bound to
+type visitorKey struct {
+	mu    sync.Mutex
+	value string
+
+	// used is true once a form is rendered with the key
+	used bool
+}
+
+// WithVisitorKey adds the key of the visitor, the CSRF tokens of the
+// rendered forms are bound to, to the context of the request. The key is
+// the value of the visitor key cookie, or a new random key if none.
+// Once the page is rendered, VisitorKeyCookieWrite stores it in the cookie.
+func WithVisitorKey(r *http.Request) *http.Request {
+	key := &visitorKey{}
+
+	if cookie, err := r.Cookie(COOKIE_VISITOR_KEY); err == nil && cookie.Value != "" {
+		key.value = cookie.Value
+	} else {
+		value := make([]byte, 32)
+		_, _ = rand.Read(value)
+		key.value = base64.RawURLEncoding.EncodeToString(value)
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), visitorKeyContextKey{}, key))
+}
+
+// VisitorKeyCookieWrite sets the visitor key cookie, if a form was
+// rendered with the key of the request (see WithVisitorKey)
+func VisitorKeyCookieWrite(w http.ResponseWriter, r *http.Request) {
+	key, ok := r.Context().Value(visitorKeyContextKey{}).(*visitorKey)
+	if !ok || w == nil {
+		return
+	}
+
+	key.mu.Lock()
+	defer key.mu.Unlock()
+
+	if !key.used {
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     COOKIE_VISITOR_KEY,
+		Value:    key.value,
+		Path:     "/",
+		MaxAge:   int(tokenMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// visitorKeyFromContext returns the key of the visitor the form is
+// rendered for: the key added by WithVisitorKey, or the visitor key
+// cookie of the request. Empty if none, the form cannot be submitted
+func visitorKeyFromContext(ctx context.Context) string {
+	if key, ok := ctx.Value(visitorKeyContextKey{}).(*visitorKey); ok {
+		key.mu.Lock()
+		defer key.mu.Unlock()
+
+		key.used = true
+		return key.value
+	}
+
+	if r := cmsstore.RequestFromContext(ctx); r != nil {
+		if cookie, err := r.Cookie(COOKIE_VISITOR_KEY); err == nil {
+			return cookie.Value
+		}
+	}
+
+	return ""
+}
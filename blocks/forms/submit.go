@@ -0,0 +1,278 @@
+package forms
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dromara/carbon/v2"
+)
+
+// Submit handles the submission of the form block, posted with the request.
+//
+// Business Logic:
+//   - the CSRF token must be valid for the block and the visitor key
+//     cookie, and the origin (or referer) of the request must be the
+//     host of the request
+//   - submissions filling the honeypot field, or faster than
+//     tokenMinAge, are spam, reported as accepted but not stored
+//   - the submissions from an IP address are limited per hour
+//   - the values are validated against the fields of the form
+//   - a valid submission is stored, then the submission hooks are called
+func (t *FormBlockType) Submit(ctx context.Context, block cmsstore.BlockInterface, r *http.Request) *SubmitResult {
+	result := &SubmitResult{
+		BlockID: block.ID(),
+		Errors:  map[string]string{},
+		Values:  map[string]string{},
+	}
+
+	if t.store == nil || !t.store.FormsEnabled() {
+		result.Errors[""] = "Forms are not enabled."
+		return result
+	}
+
+	if err := r.ParseForm(); err != nil {
+		result.Errors[""] = "The submission could not be read, please try again."
+		return result
+	}
+
+	fields := Fields(block)
+	for _, field := range fields {
+		result.Values[field.Name] = strings.TrimSpace(r.PostForm.Get(field.Name))
+	}
+
+	if !sameOrigin(r) {
+		result.Errors[""] = "The submission could not be verified, please try again."
+		return result
+	}
+
+	visitorKey := ""
+	if cookie, err := r.Cookie(COOKIE_VISITOR_KEY); err == nil {
+		visitorKey = cookie.Value
+	}
+
+	switch verifyToken(t.secret, block.ID(), visitorKey, r.PostForm.Get(INPUT_TOKEN), t.now()) {
+	case nil:
+	case errTokenTooEarly:
+		return t.spamResult(block, r, result)
+	case errTokenExpired:
+		result.Errors[""] = "The form has expired, please submit it again."
+		return result
+	default:
+		result.Errors[""] = "The submission could not be verified, please try again."
+		return result
+	}
+
+	if r.PostForm.Get(INPUT_HONEYPOT) != "" {
+		return t.spamResult(block, r, result)
+	}
+
+	ipAddress := clientIP(r)
+
+	if limit := t.rateLimit(block); limit > 0 {
+		count, err := t.store.FormSubmissionCount(ctx, cmsstore.FormSubmissionQuery().
+			SetBlockID(block.ID()).
+			SetIPAddress(ipAddress).
+			SetCreatedAtGte(carbon.Now(carbon.UTC).SubHour().ToDateTimeString(carbon.UTC)))
+
+		if err != nil {
+			result.Errors[""] = "The submission could not be saved, please try again later."
+			return result
+		}
+
+		if count >= int64(limit) {
+			result.Errors[""] = "Too many submissions, please try again later."
+			return result
+		}
+	}
+
+	for _, field := range fields {
+		if message := validateValue(field, result.Values[field.Name]); message != "" {
+			result.Errors[field.Name] = message
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		return result
+	}
+
+	submission := cmsstore.NewFormSubmission().
+		SetSiteID(block.SiteID()).
+		SetBlockID(block.ID()).
+		SetPageID(block.PageID()).
+		SetIPAddress(ipAddress).
+		SetUserAgent(truncate(r.UserAgent(), 255)).
+		SetSourceURL(truncate(r.URL.RequestURI(), 255))
+
+	if err := submission.SetFormData(result.Values); err != nil {
+		result.Errors[""] = "The submission could not be saved, please try again later."
+		return result
+	}
+
+	if err := t.store.FormSubmissionCreate(ctx, submission); err != nil {
+		result.Errors[""] = "The submission could not be saved, please try again later."
+		return result
+	}
+
+	result.Success = true
+	result.Submission = submission
+	result.RedirectURL = t.redirectURL(block, r)
+
+	for _, hook := range t.hooks {
+		if err := hook(ctx, block, submission); err != nil {
+			result.HookErrors = append(result.HookErrors, err)
+		}
+	}
+
+	return result
+}
+
+// spamResult reports the spam submission as accepted, without storing it,
+// so that the bots are not told to try differently
+func (t *FormBlockType) spamResult(block cmsstore.BlockInterface, r *http.Request, result *SubmitResult) *SubmitResult {
+	result.Success = true
+	result.Errors = map[string]string{}
+	result.RedirectURL = t.redirectURL(block, r)
+	return result
+}
+
+// redirectURL returns the URL to redirect to after a successful submission,
+// the redirect URL of the block, or the page with the success message
+func (t *FormBlockType) redirectURL(block cmsstore.BlockInterface, r *http.Request) string {
+	settings := cmsstore.BlockSettingsValues(block, t.SettingsSchema())
+
+	if redirectURL := strings.TrimSpace(settings[cmsstore.BLOCK_META_FORM_REDIRECT_URL]); redirectURL != "" {
+		return redirectURL
+	}
+
+	query := r.URL.Query()
+	query.Set(QUERY_SUCCESS, block.ID())
+
+	return r.URL.Path + "?" + query.Encode()
+}
+
+// validateValue validates the value of the field, returning the error message
+func validateValue(field Field, value string) string {
+	if value == "" {
+		if field.Required {
+			return field.Label + " is required."
+		}
+		return ""
+	}
+
+	if utf8.RuneCountInString(value) > field.maxLength() {
+		return field.Label + " must be at most " + strconv.Itoa(field.maxLength()) + " characters."
+	}
+
+	switch field.Type {
+	case FIELD_TYPE_EMAIL:
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value {
+			return field.Label + " must be a valid email address."
+		}
+	case FIELD_TYPE_URL:
+		u, err := url.ParseRequestURI(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return field.Label + " must be a valid URL."
+		}
+	case FIELD_TYPE_NUMBER:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return field.Label + " must be a number."
+		}
+	case FIELD_TYPE_DATE:
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return field.Label + " must be a valid date."
+		}
+	case FIELD_TYPE_SELECT, FIELD_TYPE_RADIO:
+		for _, option := range field.Options {
+			if option == value {
+				return ""
+			}
+		}
+		return field.Label + " must be one of the options."
+	case FIELD_TYPE_CHECKBOX:
+		if value != checkboxValue {
+			return field.Label + " is invalid."
+		}
+	}
+
+	return ""
+}
+
+// sameOrigin returns whether the request is posted from the same host,
+// checking the Origin header, or the Referer header if not sent.
+// Requests sending neither are rejected
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		origin = r.Header.Get("Referer")
+	}
+
+	if origin == "" {
+		return false
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// clientIP returns the IP address of the client of the request
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return truncate(r.RemoteAddr, 45)
+	}
+
+	return host
+}
+
+// truncate truncates the string to the maximum number of bytes,
+// without splitting a character
+func truncate(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+
+	for maxBytes > 0 && !utf8.RuneStart(s[maxBytes]) {
+		maxBytes--
+	}
+
+	return s[:maxBytes]
+}
+
+// EmailNotificationHook returns a submission hook sending the submission
+// to the notification email of the form block, using the send function
+// of the application. Form blocks without a notification email are skipped
+func EmailNotificationHook(send func(ctx context.Context, to string, subject string, body string) error) SubmissionHook {
+	return func(ctx context.Context, block cmsstore.BlockInterface, submission cmsstore.FormSubmissionInterface) error {
+		to := strings.TrimSpace(block.Meta(cmsstore.BLOCK_META_FORM_NOTIFY_EMAIL))
+		if to == "" || send == nil {
+			return nil
+		}
+
+		name := block.Name()
+		if name == "" {
+			name = block.ID()
+		}
+
+		body := strings.Builder{}
+		for _, field := range Fields(block) {
+			body.WriteString(field.Label + ": " + submission.FormValue(field.Name) + "\n")
+		}
+		body.WriteString("\nSubmitted from: " + submission.SourceURL() + "\n")
+		body.WriteString("IP address: " + submission.IPAddress() + "\n")
+
+		return send(ctx, to, "New submission of the form "+name, body.String())
+	}
+}
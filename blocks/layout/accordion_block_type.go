@@ -0,0 +1,113 @@
+package layout
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/hb"
+)
+
+// AccordionBlockType renders a (Bootstrap 5) accordion item per child
+// block, the item headers are the names of the child blocks.
+type AccordionBlockType struct {
+	containerBlockType
+}
+
+var _ cmsstore.BlockTypeContainer = (*AccordionBlockType)(nil)
+var _ cmsstore.BlockTypeWithSettings = (*AccordionBlockType)(nil)
+
+// NewAccordionBlockType creates a new accordion block type
+func NewAccordionBlockType(store cmsstore.StoreInterface) *AccordionBlockType {
+	return &AccordionBlockType{containerBlockType{store: store}}
+}
+
+// TypeKey returns the unique identifier for accordion blocks.
+func (t *AccordionBlockType) TypeKey() string {
+	return cmsstore.BLOCK_TYPE_ACCORDION
+}
+
+// TypeLabel returns the display name for accordion blocks.
+func (t *AccordionBlockType) TypeLabel() string {
+	return "Accordion"
+}
+
+// SettingsSchema returns the settings of the accordion block.
+func (t *AccordionBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	return []cmsstore.BlockSettingDefinition{
+		{
+			Name:    cmsstore.BLOCK_META_ACCORDION_OPEN,
+			Label:   "Initially Open",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_ENUM,
+			Default: "first",
+			Options: []cmsstore.BlockSettingOption{
+				{Value: "first", Label: "First Item"},
+				{Value: "none", Label: "None"},
+				{Value: "all", Label: "All Items"},
+			},
+		},
+		{
+			Name:    cmsstore.BLOCK_META_ACCORDION_MULTIPLE,
+			Label:   "Keep Items Open",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_BOOL,
+			Default: "false",
+			Help:    "Opening an item does not close the other items",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_ACCORDION_CSS_CLASS,
+			Label: "CSS Class",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+		},
+	}
+}
+
+// Render renders the accordion with an item per child block.
+// Supports the runtime attribute "class".
+func (t *AccordionBlockType) Render(ctx context.Context, block cmsstore.BlockInterface, opts ...cmsstore.RenderOption) (string, error) {
+	options := renderOptions(opts)
+	settings := cmsstore.BlockSettingsValues(block, t.SettingsSchema())
+
+	children, err := childBlocks(ctx, t.store, block)
+	if err != nil {
+		return "", err
+	}
+
+	cssClass := settingOrAttribute(options, "class", settings, cmsstore.BLOCK_META_ACCORDION_CSS_CLASS)
+	accordionID := "cms-accordion-" + block.ID()
+	openMode := settings[cmsstore.BLOCK_META_ACCORDION_OPEN]
+	keepOpen := settings[cmsstore.BLOCK_META_ACCORDION_MULTIPLE] == "true"
+
+	accordion := hb.Div().
+		Class("accordion").
+		ClassIf(cssClass != "", cssClass).
+		ID(accordionID)
+
+	for i, child := range children {
+		itemID := accordionID + "-" + strconv.Itoa(i)
+		open := openMode == "all" || (openMode == "first" && i == 0)
+
+		accordion.Child(hb.Div().
+			Class("accordion-item").
+			Child(hb.Heading2().
+				Class("accordion-header").
+				Child(hb.Button().
+					Class("accordion-button").
+					ClassIf(!open, "collapsed").
+					Type("button").
+					Data("bs-toggle", "collapse").
+					Data("bs-target", "#"+itemID).
+					Attr("aria-expanded", strconv.FormatBool(open)).
+					Attr("aria-controls", itemID).
+					Text(child.Name()))).
+			Child(hb.Div().
+				Class("accordion-collapse collapse").
+				ClassIf(open, "show").
+				ID(itemID).
+				AttrIf(!keepOpen, "data-bs-parent", "#"+accordionID).
+				Child(hb.Div().
+					Class("accordion-body").
+					HTML(childReference(child)))))
+	}
+
+	return accordion.ToHTML(), nil
+}
@@ -0,0 +1,121 @@
+package layout
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/hb"
+)
+
+// ColumnBlockType renders its child blocks in a Bootstrap grid column,
+// with a width per breakpoint.
+type ColumnBlockType struct {
+	containerBlockType
+}
+
+var _ cmsstore.BlockTypeContainer = (*ColumnBlockType)(nil)
+var _ cmsstore.BlockTypeWithSettings = (*ColumnBlockType)(nil)
+
+// NewColumnBlockType creates a new grid column block type
+func NewColumnBlockType(store cmsstore.StoreInterface) *ColumnBlockType {
+	return &ColumnBlockType{containerBlockType{store: store}}
+}
+
+// TypeKey returns the unique identifier for column blocks.
+func (t *ColumnBlockType) TypeKey() string {
+	return cmsstore.BLOCK_TYPE_COLUMN
+}
+
+// TypeLabel returns the display name for column blocks.
+func (t *ColumnBlockType) TypeLabel() string {
+	return "Grid Column"
+}
+
+// columnBreakpoints are the width settings, by Bootstrap breakpoint infix
+var columnBreakpoints = []struct {
+	infix   string
+	setting string
+	label   string
+}{
+	{"", cmsstore.BLOCK_META_COLUMN_WIDTH, "Width (All Screens)"},
+	{"sm", cmsstore.BLOCK_META_COLUMN_WIDTH_SM, "Width (Small Screens)"},
+	{"md", cmsstore.BLOCK_META_COLUMN_WIDTH_MD, "Width (Medium Screens)"},
+	{"lg", cmsstore.BLOCK_META_COLUMN_WIDTH_LG, "Width (Large Screens)"},
+	{"xl", cmsstore.BLOCK_META_COLUMN_WIDTH_XL, "Width (Extra Large Screens)"},
+}
+
+// SettingsSchema returns the settings of the column block.
+func (t *ColumnBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	widthOptions := []cmsstore.BlockSettingOption{
+		{Value: "auto", Label: "Auto (content width)"},
+	}
+
+	for width := 1; width <= 12; width++ {
+		widthOptions = append(widthOptions, cmsstore.BlockSettingOption{
+			Value: strconv.Itoa(width),
+			Label: strconv.Itoa(width) + "/12",
+		})
+	}
+
+	schema := []cmsstore.BlockSettingDefinition{}
+
+	for _, breakpoint := range columnBreakpoints {
+		schema = append(schema, cmsstore.BlockSettingDefinition{
+			Name:    breakpoint.setting,
+			Label:   breakpoint.label,
+			Type:    cmsstore.BLOCK_SETTING_TYPE_ENUM,
+			Options: widthOptions,
+			Help:    "Not set: inherited from the smaller screens (equal width on all screens)",
+		})
+	}
+
+	return append(schema, cmsstore.BlockSettingDefinition{
+		Name:  cmsstore.BLOCK_META_COLUMN_CSS_CLASS,
+		Label: "CSS Class",
+		Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+	})
+}
+
+// Render renders the column with its child blocks.
+// Supports the runtime attribute "class".
+func (t *ColumnBlockType) Render(ctx context.Context, block cmsstore.BlockInterface, opts ...cmsstore.RenderOption) (string, error) {
+	options := renderOptions(opts)
+	settings := cmsstore.BlockSettingsValues(block, t.SettingsSchema())
+
+	children, err := childBlocks(ctx, t.store, block)
+	if err != nil {
+		return "", err
+	}
+
+	column := hb.Div()
+
+	for _, breakpoint := range columnBreakpoints {
+		width := settings[breakpoint.setting]
+
+		if width == "" && breakpoint.infix != "" {
+			continue
+		}
+
+		class := "col"
+
+		if breakpoint.infix != "" {
+			class += "-" + breakpoint.infix
+		}
+
+		if width != "" {
+			class += "-" + width
+		}
+
+		column.Class(class)
+	}
+
+	cssClass := settingOrAttribute(options, "class", settings, cmsstore.BLOCK_META_COLUMN_CSS_CLASS)
+	column.ClassIf(cssClass != "", cssClass)
+
+	for _, child := range children {
+		column.HTML(childReference(child))
+	}
+
+	return column.ToHTML(), nil
+}
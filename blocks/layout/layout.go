@@ -0,0 +1,84 @@
+// Package layout provides the container block types, rendering their
+// child blocks (the blocks with the container as parent) in sequence order:
+//
+//   - section: a wrapper element, optionally with a Bootstrap container
+//   - row: a Bootstrap grid row, with column children
+//   - column: a Bootstrap grid column, with responsive widths
+//   - tabs: a tab per child block, titled by the child block name
+//   - accordion: an accordion item per child block, titled by the child block name
+//
+// The children are output as [[BLOCK_id]] references, so they are rendered
+// by the frontend like any other nested block (with its own block type,
+// cycle detection and depth limit).
+package layout
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dracory/cmsstore"
+)
+
+// childBlocks returns the published child blocks of the block, in sequence order
+func childBlocks(ctx context.Context, store cmsstore.StoreInterface, block cmsstore.BlockInterface) ([]cmsstore.BlockInterface, error) {
+	if store == nil || block == nil || block.ID() == "" {
+		return []cmsstore.BlockInterface{}, nil
+	}
+
+	return store.BlockList(ctx, cmsstore.BlockQuery().
+		SetParentID(block.ID()).
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE).
+		SetOrderBy(cmsstore.COLUMN_SEQUENCE).
+		SetSortOrder(cmsstore.SORT_ORDER_ASC))
+}
+
+// childReference returns the reference rendering the child block
+func childReference(child cmsstore.BlockInterface) string {
+	return "[[BLOCK_" + child.ID() + "]]"
+}
+
+// renderOptions parses the render options
+func renderOptions(opts []cmsstore.RenderOption) *cmsstore.RenderOptions {
+	options := &cmsstore.RenderOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// settingOrAttribute returns the runtime attribute, if given,
+// or the setting value otherwise
+func settingOrAttribute(options *cmsstore.RenderOptions, attribute string, settings map[string]string, setting string) string {
+	if value := options.Attributes[attribute]; value != "" {
+		return value
+	}
+
+	return settings[setting]
+}
+
+// containerBlockType holds the parts shared by the container block types.
+// The settings schema replaces the admin fields, so GetAdminFields and
+// SaveAdminFields have nothing to do.
+type containerBlockType struct {
+	store cmsstore.StoreInterface
+}
+
+// GetAdminFields returns nil, the settings are generated from the schema.
+func (t *containerBlockType) GetAdminFields(block cmsstore.BlockInterface, r *http.Request) interface{} {
+	return nil
+}
+
+// SaveAdminFields does nothing, the settings are saved from the schema.
+func (t *containerBlockType) SaveAdminFields(r *http.Request, block cmsstore.BlockInterface) error {
+	return nil
+}
+
+// GetCustomVariables returns nil, container blocks set no custom variables.
+func (t *containerBlockType) GetCustomVariables() []cmsstore.BlockCustomVariable {
+	return nil
+}
+
+// AllowedChildTypes returns nil, any block type can be nested.
+func (t *containerBlockType) AllowedChildTypes() []string {
+	return nil
+}
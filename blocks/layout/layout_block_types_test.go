@@ -0,0 +1,228 @@
+package layout
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+	_ "modernc.org/sqlite"
+)
+
+// seedLayoutBlock creates an active block of the type, with the parent and metas
+func seedLayoutBlock(t *testing.T, store cmsstore.StoreInterface, siteID, blockType, name, parentID string, sequence int, metas map[string]string) cmsstore.BlockInterface {
+	t.Helper()
+
+	block := cmsstore.NewBlock()
+	block.SetSiteID(siteID)
+	block.SetType(blockType)
+	block.SetName(name)
+	block.SetParentID(parentID)
+	block.SetSequenceInt(sequence)
+	block.SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if metas != nil {
+		if err := block.SetMetas(metas); err != nil {
+			t.Fatalf("Failed to set metas: %v", err)
+		}
+	}
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	return block
+}
+
+func initLayoutStore(t *testing.T) (cmsstore.StoreInterface, cmsstore.SiteInterface) {
+	t.Helper()
+
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site, err := testutils.SeedSite(store, "Test Site")
+	if err != nil {
+		t.Fatalf("Failed to seed site: %v", err)
+	}
+
+	return store, site
+}
+
+func TestLayoutBlockTypes_Containers(t *testing.T) {
+	blockTypes := []cmsstore.BlockType{
+		NewSectionBlockType(nil),
+		NewRowBlockType(nil),
+		NewColumnBlockType(nil),
+		NewTabsBlockType(nil),
+		NewAccordionBlockType(nil),
+	}
+
+	for _, blockType := range blockTypes {
+		if !cmsstore.BlockTypeIsContainer(blockType) {
+			t.Errorf("Expected %s to be a container", blockType.TypeKey())
+		}
+
+		if cmsstore.BlockTypeSettingsSchema(blockType) == nil {
+			t.Errorf("Expected %s to have a settings schema", blockType.TypeKey())
+		}
+	}
+
+	row := NewRowBlockType(nil)
+
+	if !cmsstore.BlockTypeAllowsChild(row, cmsstore.BLOCK_TYPE_COLUMN) {
+		t.Error("Expected row to allow column children")
+	}
+
+	if cmsstore.BlockTypeAllowsChild(row, cmsstore.BLOCK_TYPE_HTML) {
+		t.Error("Expected row to reject html children")
+	}
+
+	if !cmsstore.BlockTypeAllowsChild(NewSectionBlockType(nil), cmsstore.BLOCK_TYPE_HTML) {
+		t.Error("Expected section to allow html children")
+	}
+}
+
+func TestSectionBlockType_Render(t *testing.T) {
+	store, site := initLayoutStore(t)
+
+	section := seedLayoutBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_SECTION, "Hero", "", 0, map[string]string{
+		cmsstore.BLOCK_META_SECTION_CSS_CLASS: "hero",
+		cmsstore.BLOCK_META_SECTION_CSS_ID:    "hero",
+		cmsstore.BLOCK_META_SECTION_CONTAINER: "container",
+	})
+	second := seedLayoutBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_HTML, "Second", section.ID(), 1, nil)
+	first := seedLayoutBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_HTML, "First", section.ID(), 0, nil)
+
+	// Draft children are not rendered
+	draft := cmsstore.NewBlock()
+	draft.SetSiteID(site.ID())
+	draft.SetParentID(section.ID())
+	if err := store.BlockCreate(context.Background(), draft); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	html, err := NewSectionBlockType(store).Render(context.Background(), section)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	if !strings.HasPrefix(html, `<section class="cms-section hero" id="hero">`) {
+		t.Errorf("Expected a section element, got: %s", html)
+	}
+
+	if !strings.Contains(html, `<div class="container">`) {
+		t.Errorf("Expected a container, got: %s", html)
+	}
+
+	firstIndex := strings.Index(html, "[[BLOCK_"+first.ID()+"]]")
+	secondIndex := strings.Index(html, "[[BLOCK_"+second.ID()+"]]")
+
+	if firstIndex == -1 || secondIndex == -1 || firstIndex > secondIndex {
+		t.Errorf("Expected the children in sequence order, got: %s", html)
+	}
+
+	if strings.Contains(html, draft.ID()) {
+		t.Errorf("Expected the draft child not to be rendered, got: %s", html)
+	}
+}
+
+func TestRowAndColumnBlockTypes_Render(t *testing.T) {
+	store, site := initLayoutStore(t)
+
+	row := seedLayoutBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_ROW, "Row", "", 0, map[string]string{
+		cmsstore.BLOCK_META_ROW_GUTTER: "3",
+	})
+	column := seedLayoutBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_COLUMN, "Column", row.ID(), 0, map[string]string{
+		cmsstore.BLOCK_META_COLUMN_WIDTH:    "12",
+		cmsstore.BLOCK_META_COLUMN_WIDTH_MD: "6",
+	})
+	content := seedLayoutBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_HTML, "Content", column.ID(), 0, nil)
+
+	rowHtml, err := NewRowBlockType(store).Render(context.Background(), row)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	if rowHtml != `<div class="row g-3">[[BLOCK_`+column.ID()+`]]</div>` {
+		t.Errorf("Unexpected row html: %s", rowHtml)
+	}
+
+	columnHtml, err := NewColumnBlockType(store).Render(context.Background(), column, cmsstore.WithAttributes(map[string]string{"class": "text-center"}))
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	if columnHtml != `<div class="col-12 col-md-6 text-center">[[BLOCK_`+content.ID()+`]]</div>` {
+		t.Errorf("Unexpected column html: %s", columnHtml)
+	}
+}
+
+func TestTabsBlockType_Render(t *testing.T) {
+	store, site := initLayoutStore(t)
+
+	tabs := seedLayoutBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_TABS, "Tabs", "", 0, map[string]string{
+		cmsstore.BLOCK_META_TABS_STYLE: "pills",
+	})
+	seedLayoutBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_HTML, "Overview", tabs.ID(), 0, nil)
+	seedLayoutBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_HTML, "Pricing", tabs.ID(), 1, nil)
+
+	html, err := NewTabsBlockType(store).Render(context.Background(), tabs)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	expected := []string{
+		`class="nav nav-pills"`,
+		`>Overview</button>`,
+		`>Pricing</button>`,
+		`data-bs-target="#cms-tabs-` + tabs.ID() + `-1"`,
+		`class="tab-pane fade show active" id="cms-tabs-` + tabs.ID() + `-0"`,
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(html, s) {
+			t.Errorf("Expected html to contain %s, got: %s", s, html)
+		}
+	}
+}
+
+func TestAccordionBlockType_Render(t *testing.T) {
+	store, site := initLayoutStore(t)
+
+	accordion := seedLayoutBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_ACCORDION, "FAQ", "", 0, nil)
+	seedLayoutBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_HTML, "Question 1", accordion.ID(), 0, nil)
+	seedLayoutBlock(t, store, site.ID(), cmsstore.BLOCK_TYPE_HTML, "Question 2", accordion.ID(), 1, nil)
+
+	html, err := NewAccordionBlockType(store).Render(context.Background(), accordion)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	if strings.Count(html, `class="accordion-item"`) != 2 {
+		t.Errorf("Expected 2 accordion items, got: %s", html)
+	}
+
+	if strings.Count(html, `collapse show`) != 1 {
+		t.Errorf("Expected only the first item open, got: %s", html)
+	}
+
+	if !strings.Contains(html, `data-bs-parent="#cms-accordion-`+accordion.ID()+`"`) {
+		t.Errorf("Expected the items to close each other, got: %s", html)
+	}
+
+	if err := accordion.SetMeta(cmsstore.BLOCK_META_ACCORDION_MULTIPLE, "true"); err != nil {
+		t.Fatalf("Failed to set meta: %v", err)
+	}
+
+	html, err = NewAccordionBlockType(store).Render(context.Background(), accordion)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	if strings.Contains(html, "data-bs-parent") {
+		t.Errorf("Expected the items to stay open, got: %s", html)
+	}
+}
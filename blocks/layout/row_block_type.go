@@ -0,0 +1,87 @@
+package layout
+
+import (
+	"context"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/hb"
+)
+
+// RowBlockType renders its child column blocks in a Bootstrap grid row.
+type RowBlockType struct {
+	containerBlockType
+}
+
+var _ cmsstore.BlockTypeContainer = (*RowBlockType)(nil)
+var _ cmsstore.BlockTypeWithSettings = (*RowBlockType)(nil)
+
+// NewRowBlockType creates a new grid row block type
+func NewRowBlockType(store cmsstore.StoreInterface) *RowBlockType {
+	return &RowBlockType{containerBlockType{store: store}}
+}
+
+// TypeKey returns the unique identifier for row blocks.
+func (t *RowBlockType) TypeKey() string {
+	return cmsstore.BLOCK_TYPE_ROW
+}
+
+// TypeLabel returns the display name for row blocks.
+func (t *RowBlockType) TypeLabel() string {
+	return "Grid Row"
+}
+
+// AllowedChildTypes returns the column block type, the only child of rows.
+func (t *RowBlockType) AllowedChildTypes() []string {
+	return []string{cmsstore.BLOCK_TYPE_COLUMN}
+}
+
+// SettingsSchema returns the settings of the row block.
+func (t *RowBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	return []cmsstore.BlockSettingDefinition{
+		{
+			Name:  cmsstore.BLOCK_META_ROW_GUTTER,
+			Label: "Gutter",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_ENUM,
+			Options: []cmsstore.BlockSettingOption{
+				{Value: "0", Label: "None"},
+				{Value: "1", Label: "Extra Small"},
+				{Value: "2", Label: "Small"},
+				{Value: "3", Label: "Medium"},
+				{Value: "4", Label: "Large"},
+				{Value: "5", Label: "Extra Large"},
+			},
+			Help: "The spacing between the columns, the Bootstrap default if not set",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_ROW_CSS_CLASS,
+			Label: "CSS Class",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+		},
+	}
+}
+
+// Render renders the row with its child columns.
+// Supports the runtime attribute "class".
+func (t *RowBlockType) Render(ctx context.Context, block cmsstore.BlockInterface, opts ...cmsstore.RenderOption) (string, error) {
+	options := renderOptions(opts)
+	settings := cmsstore.BlockSettingsValues(block, t.SettingsSchema())
+
+	children, err := childBlocks(ctx, t.store, block)
+	if err != nil {
+		return "", err
+	}
+
+	cssClass := settingOrAttribute(options, "class", settings, cmsstore.BLOCK_META_ROW_CSS_CLASS)
+	gutter := settings[cmsstore.BLOCK_META_ROW_GUTTER]
+
+	row := hb.Div().
+		Class("row").
+		ClassIf(gutter != "", "g-"+gutter).
+		ClassIf(cssClass != "", cssClass)
+
+	for _, child := range children {
+		row.HTML(childReference(child))
+	}
+
+	return row.ToHTML(), nil
+}
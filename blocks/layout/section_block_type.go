@@ -0,0 +1,109 @@
+package layout
+
+import (
+	"context"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/hb"
+)
+
+// SectionBlockType renders its child blocks in a wrapper element,
+// i.e. a <section> with an optional Bootstrap container.
+type SectionBlockType struct {
+	containerBlockType
+}
+
+var _ cmsstore.BlockTypeContainer = (*SectionBlockType)(nil)
+var _ cmsstore.BlockTypeWithSettings = (*SectionBlockType)(nil)
+
+// NewSectionBlockType creates a new section block type
+func NewSectionBlockType(store cmsstore.StoreInterface) *SectionBlockType {
+	return &SectionBlockType{containerBlockType{store: store}}
+}
+
+// TypeKey returns the unique identifier for section blocks.
+func (t *SectionBlockType) TypeKey() string {
+	return cmsstore.BLOCK_TYPE_SECTION
+}
+
+// TypeLabel returns the display name for section blocks.
+func (t *SectionBlockType) TypeLabel() string {
+	return "Section"
+}
+
+// SettingsSchema returns the settings of the section block.
+func (t *SectionBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	return []cmsstore.BlockSettingDefinition{
+		{
+			Name:    cmsstore.BLOCK_META_SECTION_ELEMENT,
+			Label:   "Element",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_ENUM,
+			Default: "section",
+			Options: []cmsstore.BlockSettingOption{
+				{Value: "section", Label: "Section"},
+				{Value: "div", Label: "Div"},
+				{Value: "header", Label: "Header"},
+				{Value: "footer", Label: "Footer"},
+				{Value: "aside", Label: "Aside"},
+				{Value: "main", Label: "Main"},
+			},
+			Help: "The HTML element wrapping the child blocks",
+		},
+		{
+			Name:    cmsstore.BLOCK_META_SECTION_CONTAINER,
+			Label:   "Container",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_ENUM,
+			Default: "none",
+			Options: []cmsstore.BlockSettingOption{
+				{Value: "none", Label: "None (full width)"},
+				{Value: "container", Label: "Container"},
+				{Value: "container-fluid", Label: "Container Fluid"},
+			},
+			Help: "Wraps the child blocks in a Bootstrap container",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_SECTION_CSS_CLASS,
+			Label: "CSS Class",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+		},
+		{
+			Name:       cmsstore.BLOCK_META_SECTION_CSS_ID,
+			Label:      "CSS ID",
+			Type:       cmsstore.BLOCK_SETTING_TYPE_STRING,
+			Validation: `^[A-Za-z][\w-]*$`,
+		},
+	}
+}
+
+// Render renders the section with its child blocks.
+// Supports the runtime attribute "class".
+func (t *SectionBlockType) Render(ctx context.Context, block cmsstore.BlockInterface, opts ...cmsstore.RenderOption) (string, error) {
+	options := renderOptions(opts)
+	settings := cmsstore.BlockSettingsValues(block, t.SettingsSchema())
+
+	children, err := childBlocks(ctx, t.store, block)
+	if err != nil {
+		return "", err
+	}
+
+	content := hb.Wrap()
+	for _, child := range children {
+		content.HTML(childReference(child))
+	}
+
+	cssClass := settingOrAttribute(options, "class", settings, cmsstore.BLOCK_META_SECTION_CSS_CLASS)
+	cssID := settings[cmsstore.BLOCK_META_SECTION_CSS_ID]
+
+	section := hb.NewTag(settings[cmsstore.BLOCK_META_SECTION_ELEMENT]).
+		Class("cms-section").
+		ClassIf(cssClass != "", cssClass).
+		AttrIf(cssID != "", "id", cssID)
+
+	if container := settings[cmsstore.BLOCK_META_SECTION_CONTAINER]; container != "none" {
+		section.Child(hb.Div().Class(container).Child(content))
+	} else {
+		section.Child(content)
+	}
+
+	return section.ToHTML(), nil
+}
@@ -0,0 +1,113 @@
+package layout
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/hb"
+)
+
+// TabsBlockType renders a (Bootstrap 5) tab per child block,
+// the tab titles are the names of the child blocks.
+type TabsBlockType struct {
+	containerBlockType
+}
+
+var _ cmsstore.BlockTypeContainer = (*TabsBlockType)(nil)
+var _ cmsstore.BlockTypeWithSettings = (*TabsBlockType)(nil)
+
+// NewTabsBlockType creates a new tabs block type
+func NewTabsBlockType(store cmsstore.StoreInterface) *TabsBlockType {
+	return &TabsBlockType{containerBlockType{store: store}}
+}
+
+// TypeKey returns the unique identifier for tabs blocks.
+func (t *TabsBlockType) TypeKey() string {
+	return cmsstore.BLOCK_TYPE_TABS
+}
+
+// TypeLabel returns the display name for tabs blocks.
+func (t *TabsBlockType) TypeLabel() string {
+	return "Tabs"
+}
+
+// SettingsSchema returns the settings of the tabs block.
+func (t *TabsBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	return []cmsstore.BlockSettingDefinition{
+		{
+			Name:    cmsstore.BLOCK_META_TABS_STYLE,
+			Label:   "Style",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_ENUM,
+			Default: "tabs",
+			Options: []cmsstore.BlockSettingOption{
+				{Value: "tabs", Label: "Tabs"},
+				{Value: "pills", Label: "Pills"},
+				{Value: "underline", Label: "Underline"},
+			},
+		},
+		{
+			Name:  cmsstore.BLOCK_META_TABS_CSS_CLASS,
+			Label: "CSS Class",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+		},
+	}
+}
+
+// Render renders the tabs, the first tab is active.
+// Supports the runtime attribute "class".
+func (t *TabsBlockType) Render(ctx context.Context, block cmsstore.BlockInterface, opts ...cmsstore.RenderOption) (string, error) {
+	options := renderOptions(opts)
+	settings := cmsstore.BlockSettingsValues(block, t.SettingsSchema())
+
+	children, err := childBlocks(ctx, t.store, block)
+	if err != nil {
+		return "", err
+	}
+
+	cssClass := settingOrAttribute(options, "class", settings, cmsstore.BLOCK_META_TABS_CSS_CLASS)
+	tabsID := "cms-tabs-" + block.ID()
+
+	nav := hb.UL().
+		Class("nav nav-"+settings[cmsstore.BLOCK_META_TABS_STYLE]).
+		Attr("role", "tablist")
+
+	panes := hb.Div().Class("tab-content")
+
+	for i, child := range children {
+		paneID := tabsID + "-" + strconv.Itoa(i)
+		active := i == 0
+
+		nav.Child(hb.LI().
+			Class("nav-item").
+			Attr("role", "presentation").
+			Child(hb.Button().
+				Class("nav-link").
+				ClassIf(active, "active").
+				ID(paneID+"-tab").
+				Type("button").
+				Attr("role", "tab").
+				Data("bs-toggle", "tab").
+				Data("bs-target", "#"+paneID).
+				Attr("aria-controls", paneID).
+				Attr("aria-selected", strconv.FormatBool(active)).
+				Text(child.Name())))
+
+		panes.Child(hb.Div().
+			Class("tab-pane fade").
+			ClassIf(active, "show active").
+			ID(paneID).
+			Attr("role", "tabpanel").
+			Attr("aria-labelledby", paneID+"-tab").
+			Attr("tabindex", "0").
+			HTML(childReference(child)))
+	}
+
+	return hb.Div().
+		Class("cms-tabs").
+		ClassIf(cssClass != "", cssClass).
+		ID(tabsID).
+		Child(nav).
+		Child(panes).
+		ToHTML(), nil
+}
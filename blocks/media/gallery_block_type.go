@@ -0,0 +1,336 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/form"
+	"github.com/dracory/hb"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+// GalleryBlockType renders the selected images as a grid or a carousel.
+//
+// The images are shown in the selected order, the images not found
+// or not active are skipped.
+type GalleryBlockType struct {
+	mediaBlockType
+}
+
+var _ cmsstore.BlockTypeWithSettings = (*GalleryBlockType)(nil)
+
+// NewGalleryBlockType creates a new gallery block type
+func NewGalleryBlockType(store cmsstore.StoreInterface) *GalleryBlockType {
+	return &GalleryBlockType{mediaBlockType{store: store}}
+}
+
+// TypeKey returns the unique identifier for gallery blocks.
+func (t *GalleryBlockType) TypeKey() string {
+	return cmsstore.BLOCK_TYPE_GALLERY
+}
+
+// TypeLabel returns the display name for gallery blocks.
+func (t *GalleryBlockType) TypeLabel() string {
+	return "Gallery"
+}
+
+// SettingsSchema returns the settings of the gallery block.
+func (t *GalleryBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	minColumns, maxColumns := 1.0, 6.0
+	minInterval := 0.0
+
+	return []cmsstore.BlockSettingDefinition{
+		{
+			Name:    cmsstore.BLOCK_META_GALLERY_LAYOUT,
+			Label:   "Layout",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_ENUM,
+			Default: "grid",
+			Options: []cmsstore.BlockSettingOption{
+				{Value: "grid", Label: "Grid"},
+				{Value: "carousel", Label: "Carousel"},
+			},
+		},
+		{
+			Name:     cmsstore.BLOCK_META_GALLERY_COLUMNS,
+			Label:    "Columns",
+			Type:     cmsstore.BLOCK_SETTING_TYPE_INT,
+			Default:  "3",
+			MinValue: &minColumns,
+			MaxValue: &maxColumns,
+			Help:     "The number of columns of the grid on large screens",
+		},
+		{
+			Name:     cmsstore.BLOCK_META_GALLERY_INTERVAL,
+			Label:    "Carousel Interval (ms)",
+			Type:     cmsstore.BLOCK_SETTING_TYPE_INT,
+			Default:  "5000",
+			MinValue: &minInterval,
+			Help:     "The delay before the next slide, 0 to disable autoplay",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_GALLERY_SIZES,
+			Label: "Sizes",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+			Help:  "The display width of the images, derived from the layout and columns if not set",
+		},
+		{
+			Name:    cmsstore.BLOCK_META_GALLERY_LOADING,
+			Label:   "Loading",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_ENUM,
+			Default: loadingLazy,
+			Options: loadingOptions,
+			Help:    "Only the first slide of a carousel is loaded eagerly",
+		},
+		{
+			Name:    cmsstore.BLOCK_META_GALLERY_CAPTIONS,
+			Label:   "Show Captions",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_BOOL,
+			Default: "false",
+			Help:    "Shows the media titles as captions",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_GALLERY_CSS_CLASS,
+			Label: "CSS Class",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+		},
+	}
+}
+
+// Render renders the gallery, an HTML comment if it has no (active) images.
+// Supports the runtime attributes "class", "layout" and "loading".
+func (t *GalleryBlockType) Render(ctx context.Context, block cmsstore.BlockInterface, opts ...cmsstore.RenderOption) (string, error) {
+	options := renderOptions(opts)
+	settings := cmsstore.BlockSettingsValues(block, t.SettingsSchema())
+
+	images := []cmsstore.MediaInterface{}
+
+	for _, mediaID := range galleryMediaIDs(block) {
+		image, err := t.findMedia(ctx, mediaID)
+		if err != nil {
+			return "", err
+		}
+
+		if image != nil && image.IsImage() {
+			images = append(images, image)
+		}
+	}
+
+	if len(images) == 0 {
+		return "<!-- No images found -->", nil
+	}
+
+	cssClass := settingOrAttribute(options, "class", settings, cmsstore.BLOCK_META_GALLERY_CSS_CLASS)
+	loading := settingOrAttribute(options, "loading", settings, cmsstore.BLOCK_META_GALLERY_LOADING)
+	captions := settings[cmsstore.BLOCK_META_GALLERY_CAPTIONS] == "true"
+	columns := max(1, min(6, cast.ToInt(settings[cmsstore.BLOCK_META_GALLERY_COLUMNS])))
+
+	if settingOrAttribute(options, "layout", settings, cmsstore.BLOCK_META_GALLERY_LAYOUT) == "carousel" {
+		sizes := settings[cmsstore.BLOCK_META_GALLERY_SIZES]
+		if sizes == "" {
+			sizes = "100vw"
+		}
+
+		interval := max(0, cast.ToInt(settings[cmsstore.BLOCK_META_GALLERY_INTERVAL]))
+
+		return t.renderCarousel(block, images, cssClass, sizes, loading, interval, captions), nil
+	}
+
+	sizes := settings[cmsstore.BLOCK_META_GALLERY_SIZES]
+	if sizes == "" {
+		sizes = "(min-width: 768px) " + strconv.Itoa(100/columns) + "vw, 100vw"
+	}
+
+	grid := hb.Div().
+		Class("cms-gallery row g-3 row-cols-1").
+		ClassIf(columns > 1, "row-cols-sm-2").
+		Class("row-cols-md-"+strconv.Itoa(columns)).
+		ClassIf(cssClass != "", cssClass)
+
+	for _, image := range images {
+		caption := mediaCaption(image, lo.Ternary(captions, "title", "none"))
+
+		grid.Child(hb.Div().
+			Class("col").
+			Child(hb.NewTag("figure").
+				Class("figure").
+				Child(imageTag(image, mediaAlt(image), sizes, loading).Class("figure-img img-fluid")).
+				ChildIf(caption != "", hb.NewTag("figcaption").Class("figure-caption").Text(caption))))
+	}
+
+	return grid.ToHTML(), nil
+}
+
+// renderCarousel renders the images as a (Bootstrap 5) carousel
+func (t *GalleryBlockType) renderCarousel(block cmsstore.BlockInterface, images []cmsstore.MediaInterface, cssClass, sizes, loading string, interval int, captions bool) string {
+	carouselID := "cms-gallery-" + block.ID()
+
+	indicators := hb.Div().Class("carousel-indicators")
+	inner := hb.Div().Class("carousel-inner")
+
+	for i, image := range images {
+		active := i == 0
+		caption := mediaCaption(image, lo.Ternary(captions, "title", "none"))
+
+		// The slides not shown initially are always loaded lazily
+		slideLoading := loading
+		if !active {
+			slideLoading = loadingLazy
+		}
+
+		indicators.Child(hb.Button().
+			Type("button").
+			Data("bs-target", "#"+carouselID).
+			Data("bs-slide-to", strconv.Itoa(i)).
+			ClassIf(active, "active").
+			AttrIf(active, "aria-current", "true").
+			Attr("aria-label", "Slide "+strconv.Itoa(i+1)))
+
+		inner.Child(hb.Div().
+			Class("carousel-item").
+			ClassIf(active, "active").
+			Child(imageTag(image, mediaAlt(image), sizes, slideLoading).Class("d-block w-100")).
+			ChildIf(caption != "", hb.Div().
+				Class("carousel-caption d-none d-md-block").
+				Child(hb.Paragraph().Text(caption))))
+	}
+
+	control := func(direction, label string) hb.TagInterface {
+		return hb.Button().
+			Class("carousel-control-"+direction).
+			Type("button").
+			Data("bs-target", "#"+carouselID).
+			Data("bs-slide", direction).
+			Child(hb.Span().Class("carousel-control-"+direction+"-icon").Attr("aria-hidden", "true")).
+			Child(hb.Span().Class("visually-hidden").Text(label))
+	}
+
+	carousel := hb.Div().
+		Class("cms-gallery carousel slide").
+		ClassIf(cssClass != "", cssClass).
+		ID(carouselID)
+
+	if interval > 0 {
+		carousel.Data("bs-ride", "carousel").Data("bs-interval", strconv.Itoa(interval))
+	} else {
+		carousel.Data("bs-interval", "false")
+	}
+
+	return carousel.
+		ChildIf(len(images) > 1, indicators).
+		Child(inner).
+		ChildIf(len(images) > 1, control("prev", "Previous")).
+		ChildIf(len(images) > 1, control("next", "Next")).
+		ToHTML()
+}
+
+// GetAdminFields returns the field for selecting the images,
+// the other fields are generated from the settings schema.
+func (t *GalleryBlockType) GetAdminFields(block cmsstore.BlockInterface, r *http.Request) interface{} {
+	if t.store == nil || !t.store.MediaEnabled() {
+		return mediaNotEnabledFields()
+	}
+
+	selectedIDs := galleryMediaIDs(block)
+	images := t.mediaList(r, block.SiteID(), isImage)
+
+	// The selected images first, in their order
+	slices.SortStableFunc(images, func(a, b cmsstore.MediaInterface) int {
+		return galleryPosition(selectedIDs, a.ID()) - galleryPosition(selectedIDs, b.ID())
+	})
+
+	list := hb.Div().Class("list-group")
+
+	for _, image := range images {
+		list.Child(hb.Label().
+			Class("list-group-item d-flex align-items-center").
+			Child(hb.Input().
+				Type(hb.TYPE_CHECKBOX).
+				Class("form-check-input me-2").
+				Name(cmsstore.BLOCK_META_GALLERY_MEDIA_IDS).
+				Value(image.ID()).
+				AttrIf(slices.Contains(selectedIDs, image.ID()), "checked", "checked")).
+			Child(hb.Img(mediaURL(image)).
+				Alt("").
+				Attr("loading", loadingLazy).
+				Style("width:48px;height:48px;object-fit:cover;").
+				Class("rounded me-2")).
+			Child(hb.Span().Text(mediaLabel(image))))
+	}
+
+	if len(images) == 0 {
+		list.Child(hb.Div().Class("alert alert-info").Text("No images found for this site, upload images to the media library first."))
+	}
+
+	return []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Label: "Images",
+			Type:  form.FORM_FIELD_TYPE_RAW,
+			Value: hb.Div().
+				Class("mb-3").
+				Child(hb.Label().Class("form-label").Text("Images")).
+				Child(list).
+				Child(hb.Div().Class("form-text").Text("Select the images to display, the newly selected images are added at the end")).
+				ToHTML(),
+		}),
+	}
+}
+
+// SaveAdminFields saves the selected images, keeping the order
+// of the images already in the gallery.
+func (t *GalleryBlockType) SaveAdminFields(r *http.Request, block cmsstore.BlockInterface) error {
+	if t.store == nil || !t.store.MediaEnabled() {
+		return nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+
+	submittedIDs := []string{}
+	for _, mediaID := range r.Form[cmsstore.BLOCK_META_GALLERY_MEDIA_IDS] {
+		if mediaID = strings.TrimSpace(mediaID); mediaID != "" && !slices.Contains(submittedIDs, mediaID) {
+			submittedIDs = append(submittedIDs, mediaID)
+		}
+	}
+
+	if len(submittedIDs) == 0 {
+		return errors.New("at least one image must be selected")
+	}
+
+	previousIDs := galleryMediaIDs(block)
+
+	slices.SortStableFunc(submittedIDs, func(a, b string) int {
+		return galleryPosition(previousIDs, a) - galleryPosition(previousIDs, b)
+	})
+
+	return block.SetMeta(cmsstore.BLOCK_META_GALLERY_MEDIA_IDS, strings.Join(submittedIDs, ","))
+}
+
+// galleryMediaIDs returns the IDs of the images of the gallery, in order
+func galleryMediaIDs(block cmsstore.BlockInterface) []string {
+	ids := []string{}
+
+	for _, id := range strings.Split(block.Meta(cmsstore.BLOCK_META_GALLERY_MEDIA_IDS), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// galleryPosition returns the position of the media ID in the gallery,
+// the media not in the gallery are positioned at the end
+func galleryPosition(ids []string, mediaID string) int {
+	if index := slices.Index(ids, mediaID); index >= 0 {
+		return index
+	}
+
+	return len(ids)
+}
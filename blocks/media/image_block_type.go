@@ -0,0 +1,191 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/form"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+)
+
+// ImageBlockType renders a responsive image from the media store.
+//
+// With a mobile image set, a <picture> is rendered, showing the mobile
+// image on small screens (art direction).
+type ImageBlockType struct {
+	mediaBlockType
+}
+
+var _ cmsstore.BlockTypeWithSettings = (*ImageBlockType)(nil)
+
+// NewImageBlockType creates a new image block type
+func NewImageBlockType(store cmsstore.StoreInterface) *ImageBlockType {
+	return &ImageBlockType{mediaBlockType{store: store}}
+}
+
+// TypeKey returns the unique identifier for image blocks.
+func (t *ImageBlockType) TypeKey() string {
+	return cmsstore.BLOCK_TYPE_IMAGE
+}
+
+// TypeLabel returns the display name for image blocks.
+func (t *ImageBlockType) TypeLabel() string {
+	return "Image"
+}
+
+// SettingsSchema returns the settings of the image block.
+func (t *ImageBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	return []cmsstore.BlockSettingDefinition{
+		{
+			Name:  cmsstore.BLOCK_META_IMAGE_ALT,
+			Label: "Alt Text",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+			Help:  "Describes the image for screen readers, the media title (or description) if not set",
+		},
+		{
+			Name:    cmsstore.BLOCK_META_IMAGE_SIZES,
+			Label:   "Sizes",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_STRING,
+			Default: "100vw",
+			Help:    "The display width of the image, for choosing the image variant, i.e. (min-width: 992px) 50vw, 100vw",
+		},
+		{
+			Name:    cmsstore.BLOCK_META_IMAGE_LOADING,
+			Label:   "Loading",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_ENUM,
+			Default: loadingLazy,
+			Options: loadingOptions,
+		},
+		{
+			Name:    cmsstore.BLOCK_META_IMAGE_CAPTION,
+			Label:   "Caption",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_ENUM,
+			Default: "none",
+			Options: []cmsstore.BlockSettingOption{
+				{Value: "none", Label: "None"},
+				{Value: "title", Label: "Media Title"},
+				{Value: "description", Label: "Media Description"},
+			},
+		},
+		{
+			Name:  cmsstore.BLOCK_META_IMAGE_LINK_URL,
+			Label: "Link URL",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+			Help:  "Optional URL the image links to",
+		},
+		{
+			Name:  cmsstore.BLOCK_META_IMAGE_CSS_CLASS,
+			Label: "CSS Class",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+		},
+	}
+}
+
+// Render renders the image, an HTML comment if the image is not found or not active.
+// Supports the runtime attributes "alt", "class", "loading" and "sizes".
+func (t *ImageBlockType) Render(ctx context.Context, block cmsstore.BlockInterface, opts ...cmsstore.RenderOption) (string, error) {
+	options := renderOptions(opts)
+	settings := cmsstore.BlockSettingsValues(block, t.SettingsSchema())
+
+	image, err := t.findMedia(ctx, block.Meta(cmsstore.BLOCK_META_IMAGE_MEDIA_ID))
+	if err != nil {
+		return "", err
+	}
+
+	if image == nil || !image.IsImage() {
+		return "<!-- Image not found -->", nil
+	}
+
+	alt := settingOrAttribute(options, "alt", settings, cmsstore.BLOCK_META_IMAGE_ALT)
+	if alt == "" {
+		alt = mediaAlt(image)
+	}
+
+	cssClass := settingOrAttribute(options, "class", settings, cmsstore.BLOCK_META_IMAGE_CSS_CLASS)
+	sizes := settingOrAttribute(options, "sizes", settings, cmsstore.BLOCK_META_IMAGE_SIZES)
+	loading := settingOrAttribute(options, "loading", settings, cmsstore.BLOCK_META_IMAGE_LOADING)
+
+	var content hb.TagInterface = imageTag(image, alt, sizes, loading).
+		Class("img-fluid")
+
+	mobileImage, err := t.findMedia(ctx, block.Meta(cmsstore.BLOCK_META_IMAGE_MOBILE_MEDIA_ID))
+	if err != nil {
+		return "", err
+	}
+
+	if mobileImage != nil && mobileImage.IsImage() {
+		mobileSrcset := mediaSrcset(mobileImage)
+		if mobileSrcset == "" {
+			mobileSrcset = mediaURL(mobileImage)
+		}
+
+		content = hb.NewTag("picture").
+			Child(hb.NewTag("source").
+				Attr("media", "(max-width: 767.98px)").
+				Attr("srcset", mobileSrcset).
+				AttrIf(sizes != "", "sizes", sizes)).
+			Child(content)
+	}
+
+	if linkURL := settings[cmsstore.BLOCK_META_IMAGE_LINK_URL]; linkURL != "" {
+		content = hb.Hyperlink().Href(linkURL).Child(content)
+	}
+
+	caption := mediaCaption(image, settings[cmsstore.BLOCK_META_IMAGE_CAPTION])
+
+	return hb.NewTag("figure").
+		Class("cms-image").
+		ClassIf(cssClass != "", cssClass).
+		Child(content).
+		ChildIf(caption != "", hb.NewTag("figcaption").Class("figure-caption").Text(caption)).
+		ToHTML(), nil
+}
+
+// GetAdminFields returns the fields for selecting the images,
+// the other fields are generated from the settings schema.
+func (t *ImageBlockType) GetAdminFields(block cmsstore.BlockInterface, r *http.Request) interface{} {
+	if t.store == nil || !t.store.MediaEnabled() {
+		return mediaNotEnabledFields()
+	}
+
+	return []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Label:    "Image",
+			Name:     cmsstore.BLOCK_META_IMAGE_MEDIA_ID,
+			Type:     form.FORM_FIELD_TYPE_SELECT,
+			Value:    block.Meta(cmsstore.BLOCK_META_IMAGE_MEDIA_ID),
+			Required: true,
+			Help:     "Select the image to display in this block",
+			Options:  t.mediaOptions(r, block.SiteID(), "- Select Image -", isImage),
+		}),
+		form.NewField(form.FieldOptions{
+			Label:   "Mobile Image",
+			Name:    cmsstore.BLOCK_META_IMAGE_MOBILE_MEDIA_ID,
+			Type:    form.FORM_FIELD_TYPE_SELECT,
+			Value:   block.Meta(cmsstore.BLOCK_META_IMAGE_MOBILE_MEDIA_ID),
+			Help:    "Optional image shown on small screens instead, i.e. with a portrait crop",
+			Options: t.mediaOptions(r, block.SiteID(), "- Same Image -", isImage),
+		}),
+	}
+}
+
+// SaveAdminFields saves the selected images.
+func (t *ImageBlockType) SaveAdminFields(r *http.Request, block cmsstore.BlockInterface) error {
+	if t.store == nil || !t.store.MediaEnabled() {
+		return nil
+	}
+
+	mediaID := req.GetStringTrimmed(r, cmsstore.BLOCK_META_IMAGE_MEDIA_ID)
+
+	if mediaID == "" {
+		return errors.New("image selection is required")
+	}
+
+	return block.UpsertMetas(map[string]string{
+		cmsstore.BLOCK_META_IMAGE_MEDIA_ID:        mediaID,
+		cmsstore.BLOCK_META_IMAGE_MOBILE_MEDIA_ID: req.GetStringTrimmed(r, cmsstore.BLOCK_META_IMAGE_MOBILE_MEDIA_ID),
+	})
+}
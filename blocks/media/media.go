@@ -0,0 +1,280 @@
+// Package media provides the block types displaying the media
+// of the media store:
+//
+//   - image: a responsive image, with an optional mobile image and caption
+//   - gallery: a grid or carousel of images
+//   - video: an HTML5 video, with an optional poster image
+//
+// Images are rendered with a srcset built from the resized variants
+// of the media (the MEDIA_META_VARIANT_PREFIX metas), the alt text
+// defaults to the media title (or description).
+package media
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/form"
+	"github.com/dracory/hb"
+)
+
+// Loading options of the images
+const (
+	loadingLazy  = "lazy"
+	loadingEager = "eager"
+	loadingHigh  = "high"
+)
+
+// loadingOptions are the setting options for the image loading
+var loadingOptions = []cmsstore.BlockSettingOption{
+	{Value: loadingLazy, Label: "Lazy (when scrolled into view)"},
+	{Value: loadingEager, Label: "Eager"},
+	{Value: loadingHigh, Label: "Eager, high priority (above the fold)"},
+}
+
+// mediaBlockType holds the parts shared by the media block types
+type mediaBlockType struct {
+	store cmsstore.StoreInterface
+}
+
+// GetCustomVariables returns nil, media blocks set no custom variables.
+func (t *mediaBlockType) GetCustomVariables() []cmsstore.BlockCustomVariable {
+	return nil
+}
+
+// findMedia returns the media with the ID, nil if not found or not active
+func (t *mediaBlockType) findMedia(ctx context.Context, mediaID string) (cmsstore.MediaInterface, error) {
+	if mediaID == "" || t.store == nil || !t.store.MediaEnabled() {
+		return nil, nil
+	}
+
+	item, err := t.store.MediaFindByID(ctx, mediaID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if item == nil || !item.IsActive() {
+		return nil, nil
+	}
+
+	return item, nil
+}
+
+// mediaOptions returns the select options for the media of the site
+// accepted by the filter, i.e. the images
+func (t *mediaBlockType) mediaOptions(r *http.Request, siteID string, placeholder string, filter func(cmsstore.MediaInterface) bool) []form.FieldOption {
+	options := []form.FieldOption{
+		{
+			Value: placeholder,
+			Key:   "",
+		},
+	}
+
+	for _, item := range t.mediaList(r, siteID, filter) {
+		options = append(options, form.FieldOption{
+			Value: mediaLabel(item),
+			Key:   item.ID(),
+		})
+	}
+
+	return options
+}
+
+// mediaList returns the media of the site accepted by the filter
+func (t *mediaBlockType) mediaList(r *http.Request, siteID string, filter func(cmsstore.MediaInterface) bool) []cmsstore.MediaInterface {
+	if t.store == nil || !t.store.MediaEnabled() {
+		return []cmsstore.MediaInterface{}
+	}
+
+	list, err := t.store.MediaList(r.Context(), cmsstore.MediaQuery().
+		SetSiteID(siteID).
+		SetOrderBy(cmsstore.COLUMN_TITLE).
+		SetSortOrder(cmsstore.SORT_ORDER_ASC))
+
+	if err != nil {
+		return []cmsstore.MediaInterface{}
+	}
+
+	return slices.DeleteFunc(list, func(item cmsstore.MediaInterface) bool {
+		return !filter(item)
+	})
+}
+
+// mediaNotEnabledFields returns the admin fields shown,
+// when the media store is not enabled
+func mediaNotEnabledFields() []form.FieldInterface {
+	return []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Label: "Media Blocks Not Available",
+			Type:  form.FORM_FIELD_TYPE_RAW,
+			Value: hb.Div().Class("alert alert-warning").Text("Media functionality is not enabled in this CMS installation.").ToHTML(),
+		}),
+	}
+}
+
+// isImage is the media filter for images
+func isImage(item cmsstore.MediaInterface) bool {
+	return item.IsImage()
+}
+
+// isVideo is the media filter for videos
+func isVideo(item cmsstore.MediaInterface) bool {
+	return item.IsVideo()
+}
+
+// mediaLabel returns the label of the media in the admin
+func mediaLabel(item cmsstore.MediaInterface) string {
+	label := item.Title()
+
+	if label == "" {
+		label = item.ID()
+	}
+
+	if !item.IsActive() {
+		label += " (" + item.Status() + ")"
+	}
+
+	return label
+}
+
+// mediaURL returns the public URL of the media, external URLs
+// are used as they are, any other media is served by the CMS
+func mediaURL(item cmsstore.MediaInterface) string {
+	url := item.URL()
+
+	if strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://") {
+		return url
+	}
+
+	return item.ServeURL()
+}
+
+// mediaAlt returns the alt text of the media, its title,
+// or its description if it has no title
+func mediaAlt(item cmsstore.MediaInterface) string {
+	if title := strings.TrimSpace(item.Title()); title != "" {
+		return title
+	}
+
+	return strings.TrimSpace(item.Description())
+}
+
+// mediaCaption returns the caption of the media, by the caption setting
+func mediaCaption(item cmsstore.MediaInterface, caption string) string {
+	switch caption {
+	case "title":
+		return strings.TrimSpace(item.Title())
+	case "description":
+		return strings.TrimSpace(item.Description())
+	}
+
+	return ""
+}
+
+// mediaSrcset returns the srcset of the image, from its resized variants
+// and its own width (if known), empty if the image has no variants
+func mediaSrcset(item cmsstore.MediaInterface) string {
+	metas, err := item.Metas()
+
+	if err != nil {
+		return ""
+	}
+
+	candidates := map[int]string{}
+
+	for key, url := range metas {
+		width, err := strconv.Atoi(strings.TrimPrefix(key, cmsstore.MEDIA_META_VARIANT_PREFIX))
+
+		if !strings.HasPrefix(key, cmsstore.MEDIA_META_VARIANT_PREFIX) || err != nil || width <= 0 || url == "" {
+			continue
+		}
+
+		// Uploaded variants are stored as data URIs, served by the media handler
+		if strings.HasPrefix(url, "data:") {
+			url = item.ServeURL() + "?w=" + strconv.Itoa(width)
+		}
+
+		candidates[width] = url
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	if width, err := strconv.Atoi(metas[cmsstore.MEDIA_META_WIDTH]); err == nil && width > 0 {
+		if _, exists := candidates[width]; !exists {
+			candidates[width] = mediaURL(item)
+		}
+	}
+
+	widths := make([]int, 0, len(candidates))
+	for width := range candidates {
+		widths = append(widths, width)
+	}
+	slices.Sort(widths)
+
+	srcset := make([]string, 0, len(widths))
+	for _, width := range widths {
+		srcset = append(srcset, candidates[width]+" "+strconv.Itoa(width)+"w")
+	}
+
+	return strings.Join(srcset, ", ")
+}
+
+// imageTag returns the img tag of the image
+//
+// Business Logic:
+//   - the srcset and sizes are only set, if the image has variants
+//   - the width and height are set if known, to reserve the space
+//   - lazy images are loaded when scrolled into view, high priority
+//     images are loaded eagerly with a high fetch priority
+func imageTag(item cmsstore.MediaInterface, alt string, sizes string, loading string) *hb.Tag {
+	srcset := mediaSrcset(item)
+	width := item.Meta(cmsstore.MEDIA_META_WIDTH)
+	height := item.Meta(cmsstore.MEDIA_META_HEIGHT)
+
+	return hb.Img(mediaURL(item)).
+		AttrIf(srcset != "", "srcset", srcset).
+		AttrIf(srcset != "" && sizes != "", "sizes", sizes).
+		Alt(alt).
+		AttrIf(width != "" && height != "", "width", width).
+		AttrIf(width != "" && height != "", "height", height).
+		Attr("loading", loadingAttribute(loading)).
+		AttrIf(loading == loadingHigh, "fetchpriority", "high").
+		Attr("decoding", "async")
+}
+
+// loadingAttribute returns the value of the loading attribute
+func loadingAttribute(loading string) string {
+	if loading == loadingLazy || loading == "" {
+		return loadingLazy
+	}
+
+	return loadingEager
+}
+
+// renderOptions parses the render options
+func renderOptions(opts []cmsstore.RenderOption) *cmsstore.RenderOptions {
+	options := &cmsstore.RenderOptions{
+		Attributes: map[string]string{},
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// settingOrAttribute returns the runtime attribute, if given,
+// or the setting value otherwise
+func settingOrAttribute(options *cmsstore.RenderOptions, attribute string, settings map[string]string, setting string) string {
+	if value := options.Attributes[attribute]; value != "" {
+		return value
+	}
+
+	return settings[setting]
+}
@@ -0,0 +1,349 @@
+package media
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+	"github.com/dracory/form"
+	_ "modernc.org/sqlite"
+)
+
+func initMediaStore(t *testing.T) cmsstore.StoreInterface {
+	t.Helper()
+
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	return store
+}
+
+// seedMedia creates an active media record with the metas
+func seedMedia(t *testing.T, store cmsstore.StoreInterface, mediaType, url, title, description string, metas map[string]string) cmsstore.MediaInterface {
+	t.Helper()
+
+	item := cmsstore.NewMedia().
+		SetSiteID(testutils.SITE_01).
+		SetType(mediaType).
+		SetURL(url).
+		SetExtension(".jpg").
+		SetTitle(title).
+		SetDescription(description).
+		SetStatus(cmsstore.MEDIA_STATUS_ACTIVE)
+
+	if metas != nil {
+		if err := item.SetMetas(metas); err != nil {
+			t.Fatalf("Failed to set metas: %v", err)
+		}
+	}
+
+	if err := store.MediaCreate(context.Background(), item); err != nil {
+		t.Fatalf("Failed to create media: %v", err)
+	}
+
+	return item
+}
+
+func newMediaBlock(t *testing.T, blockType string, metas map[string]string) cmsstore.BlockInterface {
+	t.Helper()
+
+	block := cmsstore.NewBlock().
+		SetSiteID(testutils.SITE_01).
+		SetType(blockType)
+
+	if err := block.SetMetas(metas); err != nil {
+		t.Fatalf("Failed to set metas: %v", err)
+	}
+
+	return block
+}
+
+func TestMediaSrcset(t *testing.T) {
+	item := cmsstore.NewMedia().SetURL("https://cdn.example.com/hero.jpg")
+
+	if srcset := mediaSrcset(item); srcset != "" {
+		t.Errorf("Expected no srcset without variants, got %q", srcset)
+	}
+
+	err := item.SetMetas(map[string]string{
+		cmsstore.MEDIA_META_WIDTH:                  "1600",
+		cmsstore.MEDIA_META_VARIANT_PREFIX + "800": "https://cdn.example.com/hero-800.jpg",
+		cmsstore.MEDIA_META_VARIANT_PREFIX + "400": "https://cdn.example.com/hero-400.jpg",
+		cmsstore.MEDIA_META_VARIANT_PREFIX + "x":   "https://cdn.example.com/invalid.jpg",
+	})
+	if err != nil {
+		t.Fatalf("Failed to set metas: %v", err)
+	}
+
+	expected := "https://cdn.example.com/hero-400.jpg 400w, https://cdn.example.com/hero-800.jpg 800w, https://cdn.example.com/hero.jpg 1600w"
+	if srcset := mediaSrcset(item); srcset != expected {
+		t.Errorf("Expected srcset %q, got %q", expected, srcset)
+	}
+}
+
+func TestMediaAltAndURL(t *testing.T) {
+	item := cmsstore.NewMedia().
+		SetID("abc").
+		SetURL("/var/www/uploads/hero.jpg").
+		SetExtension("jpg").
+		SetDescription("A sunset over the sea")
+
+	if alt := mediaAlt(item); alt != "A sunset over the sea" {
+		t.Errorf("Expected the description as alt, got %q", alt)
+	}
+
+	item.SetTitle("Sunset")
+
+	if alt := mediaAlt(item); alt != "Sunset" {
+		t.Errorf("Expected the title as alt, got %q", alt)
+	}
+
+	if src := mediaURL(item); src != "/cms/media/abc.jpg" {
+		t.Errorf("Expected the serve URL, got %q", src)
+	}
+}
+
+func TestImageBlockType_Render(t *testing.T) {
+	store := initMediaStore(t)
+	ctx := context.Background()
+
+	image := seedMedia(t, store, "image/jpeg", "https://cdn.example.com/hero.jpg", "Hero & Co", "", map[string]string{
+		cmsstore.MEDIA_META_WIDTH:                  "1200",
+		cmsstore.MEDIA_META_HEIGHT:                 "600",
+		cmsstore.MEDIA_META_VARIANT_PREFIX + "600": "https://cdn.example.com/hero-600.jpg",
+	})
+	mobile := seedMedia(t, store, "image/jpeg", "https://cdn.example.com/hero-portrait.jpg", "Hero Portrait", "", nil)
+
+	block := newMediaBlock(t, cmsstore.BLOCK_TYPE_IMAGE, map[string]string{
+		cmsstore.BLOCK_META_IMAGE_MEDIA_ID:        image.ID(),
+		cmsstore.BLOCK_META_IMAGE_MOBILE_MEDIA_ID: mobile.ID(),
+		cmsstore.BLOCK_META_IMAGE_CAPTION:         "title",
+		cmsstore.BLOCK_META_IMAGE_LOADING:         loadingHigh,
+	})
+
+	html, err := NewImageBlockType(store).Render(ctx, block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	expected := []string{
+		`<figure class="cms-image"><picture><source media="(max-width: 767.98px)" sizes="100vw" srcset="https://cdn.example.com/hero-portrait.jpg" />`,
+		`srcset="https://cdn.example.com/hero-600.jpg 600w, https://cdn.example.com/hero.jpg 1200w"`,
+		`alt="Hero &amp; Co"`,
+		`width="1200"`,
+		`height="600"`,
+		`loading="eager"`,
+		`fetchpriority="high"`,
+		`<figcaption class="figure-caption">Hero &amp; Co</figcaption>`,
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(html, s) {
+			t.Errorf("Expected html to contain %s, got: %s", s, html)
+		}
+	}
+
+	// Runtime attributes override the settings
+	html, err = NewImageBlockType(store).Render(ctx, block, cmsstore.WithAttributes(map[string]string{
+		"alt":     "Custom",
+		"loading": loadingLazy,
+	}))
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	if !strings.Contains(html, `alt="Custom"`) || !strings.Contains(html, `loading="lazy"`) || strings.Contains(html, "fetchpriority") {
+		t.Errorf("Expected the runtime attributes to be applied, got: %s", html)
+	}
+}
+
+func TestImageBlockType_Render_InactiveImage(t *testing.T) {
+	store := initMediaStore(t)
+
+	image := seedMedia(t, store, "image/jpeg", "https://cdn.example.com/hero.jpg", "Hero", "", nil)
+	image.SetStatus(cmsstore.MEDIA_STATUS_INACTIVE)
+	if err := store.MediaUpdate(context.Background(), image); err != nil {
+		t.Fatalf("Failed to update media: %v", err)
+	}
+
+	block := newMediaBlock(t, cmsstore.BLOCK_TYPE_IMAGE, map[string]string{
+		cmsstore.BLOCK_META_IMAGE_MEDIA_ID: image.ID(),
+	})
+
+	html, err := NewImageBlockType(store).Render(context.Background(), block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	if html != "<!-- Image not found -->" {
+		t.Errorf("Expected the image not to be rendered, got: %s", html)
+	}
+}
+
+func TestGalleryBlockType_Render(t *testing.T) {
+	store := initMediaStore(t)
+	ctx := context.Background()
+
+	first := seedMedia(t, store, "image/jpeg", "https://cdn.example.com/1.jpg", "First", "", nil)
+	second := seedMedia(t, store, "image/jpeg", "https://cdn.example.com/2.jpg", "Second", "", nil)
+	video := seedMedia(t, store, "video/mp4", "https://cdn.example.com/1.mp4", "Video", "", nil)
+
+	block := newMediaBlock(t, cmsstore.BLOCK_TYPE_GALLERY, map[string]string{
+		cmsstore.BLOCK_META_GALLERY_MEDIA_IDS: second.ID() + "," + video.ID() + "," + first.ID() + ",missing",
+		cmsstore.BLOCK_META_GALLERY_COLUMNS:   "4",
+		cmsstore.BLOCK_META_GALLERY_CAPTIONS:  "true",
+	})
+
+	html, err := NewGalleryBlockType(store).Render(ctx, block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	if !strings.HasPrefix(html, `<div class="cms-gallery row g-3 row-cols-1 row-cols-sm-2 row-cols-md-4">`) {
+		t.Errorf("Expected a grid with 4 columns, got: %s", html)
+	}
+
+	if strings.Count(html, "<img") != 2 {
+		t.Errorf("Expected 2 images (the video and missing media skipped), got: %s", html)
+	}
+
+	if strings.Index(html, "2.jpg") > strings.Index(html, "1.jpg") {
+		t.Errorf("Expected the images in the gallery order, got: %s", html)
+	}
+
+	if !strings.Contains(html, `<figcaption class="figure-caption">Second</figcaption>`) {
+		t.Errorf("Expected the captions, got: %s", html)
+	}
+
+	html, err = NewGalleryBlockType(store).Render(ctx, block, cmsstore.WithAttributes(map[string]string{
+		"layout":  "carousel",
+		"loading": loadingEager,
+	}))
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	expected := []string{
+		`class="cms-gallery carousel slide"`,
+		`id="cms-gallery-` + block.ID() + `"`,
+		`data-bs-ride="carousel"`,
+		`class="carousel-control-next"`,
+		`class="carousel-item active"`,
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(html, s) {
+			t.Errorf("Expected html to contain %s, got: %s", s, html)
+		}
+	}
+
+	// Only the first slide is loaded eagerly
+	if strings.Count(html, `loading="eager"`) != 1 || strings.Count(html, `loading="lazy"`) != 1 {
+		t.Errorf("Expected only the first slide to be loaded eagerly, got: %s", html)
+	}
+}
+
+func TestGalleryBlockType_SaveAdminFields_KeepsOrder(t *testing.T) {
+	store := initMediaStore(t)
+
+	block := newMediaBlock(t, cmsstore.BLOCK_TYPE_GALLERY, map[string]string{
+		cmsstore.BLOCK_META_GALLERY_MEDIA_IDS: "c,a",
+	})
+
+	values := url.Values{cmsstore.BLOCK_META_GALLERY_MEDIA_IDS: {"a", "b", "c"}}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if err := NewGalleryBlockType(store).SaveAdminFields(r, block); err != nil {
+		t.Fatalf("SaveAdminFields returned error: %v", err)
+	}
+
+	if ids := block.Meta(cmsstore.BLOCK_META_GALLERY_MEDIA_IDS); ids != "c,a,b" {
+		t.Errorf("Expected the media IDs c,a,b, got %q", ids)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(""))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if err := NewGalleryBlockType(store).SaveAdminFields(r, block); err == nil {
+		t.Error("Expected an error without selected images")
+	}
+}
+
+func TestVideoBlockType_Render(t *testing.T) {
+	store := initMediaStore(t)
+
+	video := seedMedia(t, store, "video/mp4", "https://cdn.example.com/intro.mp4", "Intro", "", nil)
+	poster := seedMedia(t, store, "image/jpeg", "https://cdn.example.com/poster.jpg", "Poster", "", nil)
+
+	block := newMediaBlock(t, cmsstore.BLOCK_TYPE_VIDEO, map[string]string{
+		cmsstore.BLOCK_META_VIDEO_MEDIA_ID:        video.ID(),
+		cmsstore.BLOCK_META_VIDEO_POSTER_MEDIA_ID: poster.ID(),
+		cmsstore.BLOCK_META_VIDEO_AUTOPLAY:        "true",
+		cmsstore.BLOCK_META_VIDEO_PRELOAD:         "none",
+	})
+
+	html, err := NewVideoBlockType(store).Render(context.Background(), block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	expected := []string{
+		`controls="controls"`,
+		`autoplay="autoplay"`,
+		`muted="muted"`,
+		`preload="none"`,
+		`poster="https://cdn.example.com/poster.jpg"`,
+		`aria-label="Intro"`,
+		`<source src="https://cdn.example.com/intro.mp4" type="video/mp4" />`,
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(html, s) {
+			t.Errorf("Expected html to contain %s, got: %s", s, html)
+		}
+	}
+
+	// An image is not a video
+	block = newMediaBlock(t, cmsstore.BLOCK_TYPE_VIDEO, map[string]string{
+		cmsstore.BLOCK_META_VIDEO_MEDIA_ID: poster.ID(),
+	})
+
+	html, err = NewVideoBlockType(store).Render(context.Background(), block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	if html != "<!-- Video not found -->" {
+		t.Errorf("Expected the video not to be rendered, got: %s", html)
+	}
+}
+
+func TestImageBlockType_GetAdminFields(t *testing.T) {
+	store := initMediaStore(t)
+
+	image := seedMedia(t, store, "image/jpeg", "https://cdn.example.com/hero.jpg", "Hero", "", nil)
+	seedMedia(t, store, "video/mp4", "https://cdn.example.com/intro.mp4", "Intro", "", nil)
+
+	block := newMediaBlock(t, cmsstore.BLOCK_TYPE_IMAGE, map[string]string{})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	fields, ok := NewImageBlockType(store).GetAdminFields(block, r).([]form.FieldInterface)
+	if !ok || len(fields) != 2 {
+		t.Fatalf("Expected 2 form fields, got %#v", fields)
+	}
+
+	options := fields[0].GetOptions()
+
+	// The placeholder and the image, the video is not offered
+	if len(options) != 2 || options[1].Key != image.ID() || options[1].Value != "Hero" {
+		t.Errorf("Expected only the image as option, got %#v", options)
+	}
+}
@@ -0,0 +1,181 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/form"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+)
+
+// VideoBlockType renders an HTML5 video from the media store,
+// with an optional poster image.
+type VideoBlockType struct {
+	mediaBlockType
+}
+
+var _ cmsstore.BlockTypeWithSettings = (*VideoBlockType)(nil)
+
+// NewVideoBlockType creates a new video block type
+func NewVideoBlockType(store cmsstore.StoreInterface) *VideoBlockType {
+	return &VideoBlockType{mediaBlockType{store: store}}
+}
+
+// TypeKey returns the unique identifier for video blocks.
+func (t *VideoBlockType) TypeKey() string {
+	return cmsstore.BLOCK_TYPE_VIDEO
+}
+
+// TypeLabel returns the display name for video blocks.
+func (t *VideoBlockType) TypeLabel() string {
+	return "Video"
+}
+
+// SettingsSchema returns the settings of the video block.
+func (t *VideoBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	return []cmsstore.BlockSettingDefinition{
+		{
+			Name:    cmsstore.BLOCK_META_VIDEO_CONTROLS,
+			Label:   "Show Controls",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_BOOL,
+			Default: "true",
+		},
+		{
+			Name:    cmsstore.BLOCK_META_VIDEO_AUTOPLAY,
+			Label:   "Autoplay",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_BOOL,
+			Default: "false",
+			Help:    "Autoplaying videos are always muted, as required by the browsers",
+		},
+		{
+			Name:    cmsstore.BLOCK_META_VIDEO_MUTED,
+			Label:   "Muted",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_BOOL,
+			Default: "false",
+		},
+		{
+			Name:    cmsstore.BLOCK_META_VIDEO_LOOP,
+			Label:   "Loop",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_BOOL,
+			Default: "false",
+		},
+		{
+			Name:    cmsstore.BLOCK_META_VIDEO_PRELOAD,
+			Label:   "Preload",
+			Type:    cmsstore.BLOCK_SETTING_TYPE_ENUM,
+			Default: "metadata",
+			Options: []cmsstore.BlockSettingOption{
+				{Value: "none", Label: "None (lazy, loaded when played)"},
+				{Value: "metadata", Label: "Metadata"},
+				{Value: "auto", Label: "Auto"},
+			},
+		},
+		{
+			Name:  cmsstore.BLOCK_META_VIDEO_CSS_CLASS,
+			Label: "CSS Class",
+			Type:  cmsstore.BLOCK_SETTING_TYPE_STRING,
+		},
+	}
+}
+
+// Render renders the video, an HTML comment if the video is not found or not active.
+// Supports the runtime attributes "class" and "preload".
+func (t *VideoBlockType) Render(ctx context.Context, block cmsstore.BlockInterface, opts ...cmsstore.RenderOption) (string, error) {
+	options := renderOptions(opts)
+	settings := cmsstore.BlockSettingsValues(block, t.SettingsSchema())
+
+	video, err := t.findMedia(ctx, block.Meta(cmsstore.BLOCK_META_VIDEO_MEDIA_ID))
+	if err != nil {
+		return "", err
+	}
+
+	if video == nil || !video.IsVideo() {
+		return "<!-- Video not found -->", nil
+	}
+
+	poster, err := t.findMedia(ctx, block.Meta(cmsstore.BLOCK_META_VIDEO_POSTER_MEDIA_ID))
+	if err != nil {
+		return "", err
+	}
+
+	cssClass := settingOrAttribute(options, "class", settings, cmsstore.BLOCK_META_VIDEO_CSS_CLASS)
+	preload := settingOrAttribute(options, "preload", settings, cmsstore.BLOCK_META_VIDEO_PRELOAD)
+	autoplay := settings[cmsstore.BLOCK_META_VIDEO_AUTOPLAY] == "true"
+	muted := autoplay || settings[cmsstore.BLOCK_META_VIDEO_MUTED] == "true"
+	width := video.Meta(cmsstore.MEDIA_META_WIDTH)
+	height := video.Meta(cmsstore.MEDIA_META_HEIGHT)
+	label := mediaAlt(video)
+
+	posterURL := ""
+	if poster != nil && poster.IsImage() {
+		posterURL = mediaURL(poster)
+	}
+
+	return hb.NewTag("video").
+		Class("cms-video w-100").
+		ClassIf(cssClass != "", cssClass).
+		AttrIf(settings[cmsstore.BLOCK_META_VIDEO_CONTROLS] == "true", "controls", "controls").
+		AttrIf(autoplay, "autoplay", "autoplay").
+		AttrIf(muted, "muted", "muted").
+		AttrIf(settings[cmsstore.BLOCK_META_VIDEO_LOOP] == "true", "loop", "loop").
+		Attr("playsinline", "playsinline").
+		Attr("preload", preload).
+		AttrIf(posterURL != "", "poster", posterURL).
+		AttrIf(width != "" && height != "", "width", width).
+		AttrIf(width != "" && height != "", "height", height).
+		AttrIf(label != "", "aria-label", label).
+		Child(hb.NewTag("source").
+			Attr("src", mediaURL(video)).
+			Attr("type", video.Type())).
+		Text("Your browser does not support the video tag.").
+		ToHTML(), nil
+}
+
+// GetAdminFields returns the fields for selecting the video and the poster,
+// the other fields are generated from the settings schema.
+func (t *VideoBlockType) GetAdminFields(block cmsstore.BlockInterface, r *http.Request) interface{} {
+	if t.store == nil || !t.store.MediaEnabled() {
+		return mediaNotEnabledFields()
+	}
+
+	return []form.FieldInterface{
+		form.NewField(form.FieldOptions{
+			Label:    "Video",
+			Name:     cmsstore.BLOCK_META_VIDEO_MEDIA_ID,
+			Type:     form.FORM_FIELD_TYPE_SELECT,
+			Value:    block.Meta(cmsstore.BLOCK_META_VIDEO_MEDIA_ID),
+			Required: true,
+			Help:     "Select the video to display in this block",
+			Options:  t.mediaOptions(r, block.SiteID(), "- Select Video -", isVideo),
+		}),
+		form.NewField(form.FieldOptions{
+			Label:   "Poster Image",
+			Name:    cmsstore.BLOCK_META_VIDEO_POSTER_MEDIA_ID,
+			Type:    form.FORM_FIELD_TYPE_SELECT,
+			Value:   block.Meta(cmsstore.BLOCK_META_VIDEO_POSTER_MEDIA_ID),
+			Help:    "Optional image shown until the video is played",
+			Options: t.mediaOptions(r, block.SiteID(), "- No Poster -", isImage),
+		}),
+	}
+}
+
+// SaveAdminFields saves the selected video and poster.
+func (t *VideoBlockType) SaveAdminFields(r *http.Request, block cmsstore.BlockInterface) error {
+	if t.store == nil || !t.store.MediaEnabled() {
+		return nil
+	}
+
+	mediaID := req.GetStringTrimmed(r, cmsstore.BLOCK_META_VIDEO_MEDIA_ID)
+
+	if mediaID == "" {
+		return errors.New("video selection is required")
+	}
+
+	return block.UpsertMetas(map[string]string{
+		cmsstore.BLOCK_META_VIDEO_MEDIA_ID:        mediaID,
+		cmsstore.BLOCK_META_VIDEO_POSTER_MEDIA_ID: req.GetStringTrimmed(r, cmsstore.BLOCK_META_VIDEO_POSTER_MEDIA_ID),
+	})
+}
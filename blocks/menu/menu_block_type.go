@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 
 	"github.com/dracory/cmsstore"
 	"github.com/dracory/form"
@@ -20,6 +21,9 @@ type MenuBlockType struct {
 	logger interface {
 		Error(msg string, args ...interface{})
 	}
+
+	// visibilityRules remembers the menus with visibility rules, see CachePolicy
+	visibilityRules cmsstore.MenuVisibilityRulesCache
 }
 
 // NewMenuBlockType creates a new menu block type.
@@ -77,11 +81,7 @@ func (t *MenuBlockType) Render(ctx context.Context, block cmsstore.BlockInterfac
 		return "<!-- Menu not active -->", nil
 	}
 
-	menuItems, err := t.store.MenuItemList(ctx, cmsstore.MenuItemQuery().
-		SetMenuID(menuID).
-		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE).
-		SetOrderBy(cmsstore.COLUMN_SEQUENCE).
-		SetSortOrder("asc"))
+	menuItems, err := t.store.MenuItemsResolve(ctx, menuID)
 
 	if err != nil {
 		t.logger.Error("renderMenuBlock: Error listing menu items", "menuID", menuID, "error", err)
@@ -140,9 +140,24 @@ func (t *MenuBlockType) Render(ctx context.Context, block cmsstore.BlockInterfac
 		maxDepth = cast.ToInt(block.Meta(cmsstore.BLOCK_META_MENU_MAX_DEPTH))
 	}
 
+	// Use the renderer registered for the rendering mode, if any
+	if renderer := cmsstore.GetMenuRenderer(cmsstore.BLOCK_TYPE_MENU, renderingMode); renderer != nil {
+		return renderer.Render(ctx, cmsstore.MenuRenderData{
+			BlockType:  cmsstore.BLOCK_TYPE_MENU,
+			Block:      block,
+			Style:      style,
+			CSSClass:   cssClass,
+			CSSID:      cssID,
+			Attributes: options.Attributes,
+			Items: cmsstore.MenuRenderTree(ctx, menuItems, func(item cmsstore.MenuItemInterface) string {
+				return resolveMenuItemURL(ctx, t.store, item)
+			}),
+		})
+	}
+
 	// Use the menu renderer from frontend/blocks/menu package
 	// This delegates to the existing comprehensive menu rendering logic
-	return renderMenuHTML(ctx, t.store, menuItems, renderingMode, style, cssClass, cssID, startLevel, maxDepth)
+	return renderMenuHTML(ctx, t.store, menuItems, style, renderingMode, cssClass, cssID, startLevel, maxDepth)
 }
 
 // GetAdminFields returns form fields for editing menu block configuration.
@@ -223,7 +238,7 @@ func (t *MenuBlockType) GetAdminFields(block cmsstore.BlockInterface, r *http.Re
 			Type:  form.FORM_FIELD_TYPE_SELECT,
 			Value: block.Meta(cmsstore.BLOCK_META_MENU_RENDERING_MODE),
 			Help:  "Choose the rendering framework for the menu",
-			Options: []form.FieldOption{
+			Options: renderingModeOptions([]form.FieldOption{
 				{
 					Value: "Plain (default)",
 					Key:   cmsstore.BLOCK_MENU_RENDERING_PLAIN,
@@ -232,7 +247,7 @@ func (t *MenuBlockType) GetAdminFields(block cmsstore.BlockInterface, r *http.Re
 					Value: "Bootstrap 5",
 					Key:   cmsstore.BLOCK_MENU_RENDERING_BOOTSTRAP5,
 				},
-			},
+			}),
 		}),
 		form.NewField(form.FieldOptions{
 			Label: "CSS ID",
@@ -267,11 +282,42 @@ func (t *MenuBlockType) GetAdminFields(block cmsstore.BlockInterface, r *http.Re
 	return fieldsContent
 }
 
+// renderingModeOptions adds the rendering modes registered for menu blocks
+// (see cmsstore.RegisterMenuRenderer) to the built-in rendering modes
+func renderingModeOptions(options []form.FieldOption) []form.FieldOption {
+	for _, renderer := range cmsstore.GetMenuRenderers(cmsstore.BLOCK_TYPE_MENU) {
+		if !slices.ContainsFunc(options, func(option form.FieldOption) bool { return option.Key == renderer.Mode() }) {
+			options = append(options, form.FieldOption{Value: renderer.Label(), Key: renderer.Mode()})
+		}
+	}
+
+	return options
+}
+
 // GetCustomVariables returns nil as menu blocks do not set any custom variables.
 func (t *MenuBlockType) GetCustomVariables() []cmsstore.BlockCustomVariable {
 	return nil
 }
 
+// CachePolicy caches the rendered menu per page, as the active item
+// depends on the page, until the menus or pages are changed. The menu
+// is not cached if its menu items have visibility rules.
+func (t *MenuBlockType) CachePolicy(ctx context.Context, block cmsstore.BlockInterface) *cmsstore.BlockCachePolicy {
+	if menuID := block.Meta(cmsstore.BLOCK_META_MENU_ID); menuID != "" {
+		hasRules, err := t.visibilityRules.MenuHasVisibilityRules(ctx, t.store, menuID)
+
+		if err != nil || hasRules {
+			return nil
+		}
+	}
+
+	return &cmsstore.BlockCachePolicy{
+		VaryByLanguage: true,
+		VaryByPath:     true,
+		Dependencies:   []string{cmsstore.CHANGE_KIND_MENUS, cmsstore.CHANGE_KIND_PAGES, cmsstore.CHANGE_KIND_ENTITIES},
+	}
+}
+
 // SaveAdminFields processes form submission and updates the menu block.
 func (t *MenuBlockType) SaveAdminFields(r *http.Request, block cmsstore.BlockInterface) error {
 	menuID := req.GetStringTrimmed(r, "menu_id")
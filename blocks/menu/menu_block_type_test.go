@@ -1,8 +1,11 @@
 package menu
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	"github.com/dracory/cmsstore"
 	"github.com/dracory/cmsstore/testutils"
 	_ "modernc.org/sqlite"
 )
@@ -24,3 +27,139 @@ func TestMenuBlockType_GetCustomVariables(t *testing.T) {
 type testLogger struct{}
 
 func (l *testLogger) Error(msg string, args ...interface{}) {}
+
+func TestMenuBlockType_RenderMenuSources(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	ctx := context.Background()
+
+	parent, err := testutils.SeedPage(store, testutils.SITE_01, "PARENT")
+	if err != nil {
+		t.Fatalf("Failed to seed page: %v", err)
+	}
+
+	child := cmsstore.NewPage().
+		SetSiteID(testutils.SITE_01).
+		SetName("Generated Child").
+		SetAlias("/generated-child").
+		SetParentID(parent.ID()).
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE)
+	if err := store.PageCreate(ctx, child); err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	menu := cmsstore.NewMenu().SetSiteID(testutils.SITE_01).SetName("Main").SetStatus(cmsstore.MENU_STATUS_ACTIVE)
+	if err := cmsstore.SetMenuSources(menu, []cmsstore.MenuSource{
+		{Type: cmsstore.MENU_SOURCE_TYPE_PAGE_CHILDREN, PageID: parent.ID()},
+	}); err != nil {
+		t.Fatalf("Failed to set sources: %v", err)
+	}
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatalf("Failed to create menu: %v", err)
+	}
+
+	manual := cmsstore.NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetName("Manual Item").
+		SetURL("/manual").
+		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+	if err := store.MenuItemCreate(ctx, manual); err != nil {
+		t.Fatalf("Failed to create menu item: %v", err)
+	}
+
+	block := cmsstore.NewBlock().SetType(cmsstore.BLOCK_TYPE_MENU)
+	block.SetMeta(cmsstore.BLOCK_META_MENU_ID, menu.ID())
+
+	html, err := NewMenuBlockType(store, &testLogger{}).Render(ctx, block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	manualIndex := strings.Index(html, "Manual Item")
+	generatedIndex := strings.Index(html, "Generated Child")
+
+	if manualIndex < 0 || generatedIndex < 0 {
+		t.Fatalf("Expected the manual and generated items, got: %s", html)
+	}
+	if generatedIndex < manualIndex {
+		t.Errorf("Expected the generated item after the manual item, got: %s", html)
+	}
+	if !strings.Contains(html, "generated-child") {
+		t.Errorf("Expected the generated item to link the page alias, got: %s", html)
+	}
+}
+
+type testMenuRenderer struct{}
+
+func (r *testMenuRenderer) Mode() string         { return "test_menu" }
+func (r *testMenuRenderer) Label() string        { return "Test Menu" }
+func (r *testMenuRenderer) BlockTypes() []string { return nil }
+
+func (r *testMenuRenderer) Render(ctx context.Context, data cmsstore.MenuRenderData) (string, error) {
+	html := data.BlockType + ":" + data.Style + ":"
+	for _, item := range data.Items {
+		html += item.Name + "=" + item.URL + ";"
+	}
+	return html, nil
+}
+
+func TestMenuBlockType_RenderingModes(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	ctx := context.Background()
+
+	menu := cmsstore.NewMenu().SetSiteID(testutils.SITE_01).SetName("Main").SetStatus(cmsstore.MENU_STATUS_ACTIVE)
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatalf("Failed to create menu: %v", err)
+	}
+
+	item := cmsstore.NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetName("About").
+		SetURL("/about").
+		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+	if err := store.MenuItemCreate(ctx, item); err != nil {
+		t.Fatalf("Failed to create menu item: %v", err)
+	}
+
+	block := cmsstore.NewBlock().SetType(cmsstore.BLOCK_TYPE_MENU)
+	block.SetMeta(cmsstore.BLOCK_META_MENU_ID, menu.ID())
+	block.SetMeta(cmsstore.BLOCK_META_MENU_STYLE, cmsstore.BLOCK_MENU_STYLE_HORIZONTAL)
+
+	blockType := NewMenuBlockType(store, &testLogger{})
+
+	html, err := blockType.Render(ctx, block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if !strings.Contains(html, `menu-style-horizontal`) {
+		t.Errorf("Expected the plain menu with the block style, got: %s", html)
+	}
+
+	block.SetMeta(cmsstore.BLOCK_META_MENU_RENDERING_MODE, cmsstore.BLOCK_MENU_RENDERING_BOOTSTRAP5)
+
+	html, err = blockType.Render(ctx, block)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if !strings.Contains(html, `class="dropdown-item"`) {
+		t.Errorf("Expected the Bootstrap 5 dropdown, got: %s", html)
+	}
+
+	cmsstore.RegisterMenuRenderer(&testMenuRenderer{})
+	defer cmsstore.DefaultMenuRendererRegistry().Unregister("test_menu")
+
+	html, err = blockType.Render(ctx, block, cmsstore.WithAttributes(map[string]string{"mode": "test_menu"}))
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if html != "menu:horizontal:About=/about;" {
+		t.Errorf("Expected the registered renderer output, got: %s", html)
+	}
+}
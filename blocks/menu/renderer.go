@@ -3,6 +3,7 @@ package menu
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/dracory/cmsstore"
 	"github.com/dracory/hb"
@@ -26,7 +27,7 @@ import (
 func renderMenuHTML(ctx context.Context, store cmsstore.StoreInterface, menuItems []cmsstore.MenuItemInterface, style, renderingMode, cssClass, cssID string, startLevel, maxDepth int) (string, error) {
 	// Handle Bootstrap 5 dropdown separately as it has a different structure
 	if renderingMode == cmsstore.BLOCK_MENU_RENDERING_BOOTSTRAP5 {
-		return renderBootstrap5Dropdown(menuItems, cssClass, cssID)
+		return renderBootstrap5Dropdown(ctx, store, menuItems, cssClass, cssID)
 	}
 
 	// Build nav element using hb library for other styles
@@ -43,16 +44,29 @@ func renderMenuHTML(ctx context.Context, store cmsstore.StoreInterface, menuItem
 		nav.ID(cssID)
 	}
 
+	activeAncestorIDs := cmsstore.MenuItemsActiveAncestorIDs(ctx, menuItems)
+
 	// Add menu items
 	for _, item := range menuItems {
-		nav.AddChild(hb.A().Href(item.URL()).Text(item.Name()))
+		switch item.Type() {
+		case cmsstore.MENU_ITEM_TYPE_SEPARATOR:
+			nav.AddChild(hb.HR().Class("menu-separator").ClassIf(item.CSSClass() != "", item.CSSClass()))
+		case cmsstore.MENU_ITEM_TYPE_HEADING:
+			nav.AddChild(menuItemLabel(hb.Span().Class("menu-heading").ClassIf(item.CSSClass() != "", item.CSSClass()), item))
+		case cmsstore.MENU_ITEM_TYPE_MEGA_MENU:
+			nav.AddChild(megaMenuPanel(item).ClassIf(item.CSSClass() != "", item.CSSClass()))
+		default:
+			link := hb.A().Href(resolveMenuItemURL(ctx, store, item)).ClassIf(item.CSSClass() != "", item.CSSClass())
+			menuItemActiveAttributes(ctx, link, item, activeAncestorIDs)
+			nav.AddChild(menuItemLabel(menuItemLinkAttributes(link, item), item))
+		}
 	}
 
 	return nav.ToHTML(), nil
 }
 
 // renderBootstrap5Dropdown renders a Bootstrap 5 dropdown menu
-func renderBootstrap5Dropdown(menuItems []cmsstore.MenuItemInterface, cssClass, cssID string) (string, error) {
+func renderBootstrap5Dropdown(ctx context.Context, store cmsstore.StoreInterface, menuItems []cmsstore.MenuItemInterface, cssClass, cssID string) (string, error) {
 	// Build Bootstrap 5 dropdown structure
 	div := hb.Div()
 
@@ -79,13 +93,26 @@ func renderBootstrap5Dropdown(menuItems []cmsstore.MenuItemInterface, cssClass,
 	dropdownMenu := hb.Div()
 	dropdownMenu.Class("dropdown-menu")
 
+	activeAncestorIDs := cmsstore.MenuItemsActiveAncestorIDs(ctx, menuItems)
+
 	// Add menu items as dropdown items
 	for _, item := range menuItems {
-		dropdownItem := hb.A()
-		dropdownItem.Class("dropdown-item")
-		dropdownItem.Href(item.URL())
-		dropdownItem.Text(item.Name())
-		dropdownMenu.AddChild(dropdownItem)
+		switch item.Type() {
+		case cmsstore.MENU_ITEM_TYPE_SEPARATOR:
+			dropdownMenu.AddChild(hb.HR().Class("dropdown-divider").ClassIf(item.CSSClass() != "", item.CSSClass()))
+		case cmsstore.MENU_ITEM_TYPE_HEADING:
+			dropdownMenu.AddChild(menuItemLabel(hb.H6().Class("dropdown-header").ClassIf(item.CSSClass() != "", item.CSSClass()), item))
+		case cmsstore.MENU_ITEM_TYPE_MEGA_MENU:
+			dropdownMenu.AddChild(megaMenuPanel(item).ClassIf(item.CSSClass() != "", item.CSSClass()))
+		default:
+			dropdownItem := hb.A()
+			dropdownItem.Class("dropdown-item")
+			dropdownItem.ClassIf(item.CSSClass() != "", item.CSSClass())
+			dropdownItem.Href(resolveMenuItemURL(ctx, store, item))
+			menuItemActiveAttributes(ctx, dropdownItem, item, activeAncestorIDs)
+			menuItemLinkAttributes(dropdownItem, item)
+			dropdownMenu.AddChild(menuItemLabel(dropdownItem, item))
+		}
 	}
 
 	// Assemble the dropdown
@@ -94,3 +121,69 @@ func renderBootstrap5Dropdown(menuItems []cmsstore.MenuItemInterface, cssClass,
 
 	return div.ToHTML(), nil
 }
+
+// menuItemLabel adds the icon, if set, and the name of the menu item
+// to the tag
+func menuItemLabel(tag *hb.Tag, item cmsstore.MenuItemInterface) *hb.Tag {
+	if item.Icon() != "" {
+		tag.AddChild(hb.I().Class(item.Icon()).Attr("aria-hidden", "true"))
+		tag.Text(" ")
+	}
+
+	return tag.Text(item.Name())
+}
+
+// menuItemLinkAttributes adds the target and rel attributes of the menu
+// item to the link, the default "_self" target is omitted
+func menuItemLinkAttributes(link *hb.Tag, item cmsstore.MenuItemInterface) *hb.Tag {
+	if item.Target() != "" && item.Target() != "_self" {
+		link.Attr("target", item.Target())
+	}
+
+	if item.Rel() != "" {
+		link.Attr("rel", item.Rel())
+	}
+
+	return link
+}
+
+// menuItemActiveAttributes marks the link of the active menu item, with the
+// "active" class and aria-current, and of the active ancestor menu items,
+// with the "active-parent" class (see cmsstore.MenuItemIsActive)
+func menuItemActiveAttributes(ctx context.Context, link *hb.Tag, item cmsstore.MenuItemInterface, activeAncestorIDs map[string]bool) *hb.Tag {
+	if cmsstore.MenuItemIsActive(ctx, item) {
+		return link.Class("active").Attr("aria-current", "page")
+	}
+
+	return link.ClassIf(activeAncestorIDs[item.ID()], "active-parent")
+}
+
+// megaMenuPanel returns the panel of a mega menu item, displaying its
+// block, which the frontend renders as a nested block
+func megaMenuPanel(item cmsstore.MenuItemInterface) *hb.Tag {
+	panel := hb.Div().Class("mega-menu")
+
+	if item.BlockID() != "" {
+		panel.HTML("[[BLOCK_" + item.BlockID() + "]]")
+	}
+
+	return panel
+}
+
+// resolveMenuItemURL resolves the URL for a menu item,
+// the URL of the item or the alias of its page
+func resolveMenuItemURL(ctx context.Context, store cmsstore.StoreInterface, item cmsstore.MenuItemInterface) string {
+	if item.URL() != "" {
+		return item.URL()
+	}
+
+	if item.PageID() != "" {
+		page, err := store.PageFindByID(ctx, item.PageID())
+		if err != nil || page == nil {
+			return ""
+		}
+		return "/" + strings.TrimPrefix(page.Alias(), "/")
+	}
+
+	return ""
+}
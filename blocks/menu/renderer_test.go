@@ -85,12 +85,37 @@ func (m *TestMenuItem) Metas() (map[string]string, error) {
 	}
 	return m.data, nil
 }
-func (m *TestMenuItem) PageID() string   { return "" }
-func (m *TestMenuItem) ParentID() string { return "" }
-func (m *TestMenuItem) Sequence() string { return "1" }
-func (m *TestMenuItem) SequenceInt() int { return 1 }
-func (m *TestMenuItem) Status() string   { return "active" }
-func (m *TestMenuItem) Target() string   { return "_self" }
+func (m *TestMenuItem) PageID() string                                                   { return "" }
+func (m *TestMenuItem) ParentID() string                                                 { return "" }
+func (m *TestMenuItem) Sequence() string                                                 { return "1" }
+func (m *TestMenuItem) SequenceInt() int                                                 { return 1 }
+func (m *TestMenuItem) Status() string                                                   { return "active" }
+func (m *TestMenuItem) Target() string                                                   { return "_self" }
+func (m *TestMenuItem) Anchor() string                                                   { return m.Meta(cmsstore.MENU_ITEM_META_ANCHOR) }
+func (m *TestMenuItem) SetAnchor(anchor string) cmsstore.MenuItemInterface               { return m }
+func (m *TestMenuItem) BlockID() string                                                  { return m.Meta(cmsstore.MENU_ITEM_META_BLOCK_ID) }
+func (m *TestMenuItem) SetBlockID(blockID string) cmsstore.MenuItemInterface             { return m }
+func (m *TestMenuItem) CSSClass() string                                                 { return m.Meta(cmsstore.MENU_ITEM_META_CSS_CLASS) }
+func (m *TestMenuItem) SetCSSClass(cssClass string) cmsstore.MenuItemInterface           { return m }
+func (m *TestMenuItem) EntityID() string                                                 { return m.Meta(cmsstore.MENU_ITEM_META_ENTITY_ID) }
+func (m *TestMenuItem) SetEntityID(entityID string) cmsstore.MenuItemInterface           { return m }
+func (m *TestMenuItem) Icon() string                                                     { return m.Meta(cmsstore.MENU_ITEM_META_ICON) }
+func (m *TestMenuItem) SetIcon(icon string) cmsstore.MenuItemInterface                   { return m }
+func (m *TestMenuItem) Label(language string) string                                     { return m.name }
+func (m *TestMenuItem) Labels() map[string]string                                        { return map[string]string{} }
+func (m *TestMenuItem) SetLabel(language, label string) cmsstore.MenuItemInterface       { return m }
+func (m *TestMenuItem) TranslatedURL(language string) string                             { return m.url }
+func (m *TestMenuItem) TranslatedURLs() map[string]string                                { return map[string]string{} }
+func (m *TestMenuItem) SetTranslatedURL(language, url string) cmsstore.MenuItemInterface { return m }
+func (m *TestMenuItem) Rel() string                                                      { return m.Meta(cmsstore.MENU_ITEM_META_REL) }
+func (m *TestMenuItem) SetRel(rel string) cmsstore.MenuItemInterface                     { return m }
+func (m *TestMenuItem) SetType(menuItemType string) cmsstore.MenuItemInterface           { return m }
+func (m *TestMenuItem) Type() string {
+	if menuItemType := m.Meta(cmsstore.MENU_ITEM_META_TYPE); menuItemType != "" {
+		return menuItemType
+	}
+	return cmsstore.MENU_ITEM_TYPE_URL
+}
 
 // TestRenderMenuHTMLBasic tests the core rendering functionality
 func TestRenderMenuHTMLBasic(t *testing.T) {
@@ -238,3 +263,69 @@ func TestRenderMenuHTMLWithSpecialCharacters(t *testing.T) {
 		t.Errorf("renderMenuHTML() with special characters = %q, want %q", result, expected)
 	}
 }
+
+// TestRenderMenuHTMLMenuItemTypes tests the rendering of the typed menu items
+func TestRenderMenuHTMLMenuItemTypes(t *testing.T) {
+	ctx := context.Background()
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	menuItems := []cmsstore.MenuItemInterface{
+		cmsstore.NewMenuItem().SetName("Shop").SetType(cmsstore.MENU_ITEM_TYPE_HEADING),
+		cmsstore.NewMenuItem().SetName("Lamps").SetURL("/lamps").SetIcon("bi bi-lamp").SetRel("nofollow").SetTarget("_blank"),
+		cmsstore.NewMenuItem().SetName("-").SetType(cmsstore.MENU_ITEM_TYPE_SEPARATOR),
+		cmsstore.NewMenuItem().SetName("Explore").SetType(cmsstore.MENU_ITEM_TYPE_MEGA_MENU).SetBlockID("BLOCK_MEGA"),
+	}
+
+	got, err := renderMenuHTML(ctx, store, menuItems, "vertical", "", "", "", 0, 0)
+	if err != nil {
+		t.Fatalf("renderMenuHTML() error = %v", err)
+	}
+
+	want := `<nav class="menu menu-style-vertical">` +
+		`<span class="menu-heading">Shop</span>` +
+		`<a href="/lamps" rel="nofollow" target="_blank"><i aria-hidden="true" class="bi bi-lamp"></i> Lamps</a>` +
+		`<hr class="menu-separator" />` +
+		`<div class="mega-menu">[[BLOCK_BLOCK_MEGA]]</div>` +
+		`</nav>`
+
+	if got != want {
+		t.Errorf("renderMenuHTML() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderMenuHTMLActiveTrail(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	about := cmsstore.NewPage()
+	team := cmsstore.NewPage().SetParentID(about.ID())
+
+	ctx := cmsstore.PageToContext(context.Background(), team)
+	ctx = cmsstore.PageAncestorsToContext(ctx, []cmsstore.PageInterface{about})
+
+	menuItems := []cmsstore.MenuItemInterface{
+		cmsstore.NewMenuItem().SetName("About").SetURL("/about").SetPageID(about.ID()),
+		cmsstore.NewMenuItem().SetName("Team").SetURL("/about/team").SetPageID(team.ID()),
+		cmsstore.NewMenuItem().SetName("Contact").SetURL("/contact"),
+	}
+
+	got, err := renderMenuHTML(ctx, store, menuItems, "vertical", "", "", "", 0, 0)
+	if err != nil {
+		t.Fatalf("renderMenuHTML() error = %v", err)
+	}
+
+	want := `<nav class="menu menu-style-vertical">` +
+		`<a class="active-parent" href="/about">About</a>` +
+		`<a aria-current="page" class="active" href="/about/team">Team</a>` +
+		`<a href="/contact">Contact</a>` +
+		`</nav>`
+
+	if got != want {
+		t.Errorf("renderMenuHTML() = %q, want %q", got, want)
+	}
+}
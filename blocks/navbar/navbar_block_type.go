@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
 
 	"github.com/dracory/cmsstore"
 	"github.com/dracory/form"
@@ -13,6 +14,9 @@ import (
 // NavbarBlockType represents a navbar block for navigation
 type NavbarBlockType struct {
 	store cmsstore.StoreInterface
+
+	// visibilityRules remembers the menus with visibility rules, see CachePolicy
+	visibilityRules cmsstore.MenuVisibilityRulesCache
 }
 
 // NewNavbarBlockType creates a new navbar block type
@@ -47,11 +51,7 @@ func (t *NavbarBlockType) Render(ctx context.Context, block cmsstore.BlockInterf
 	}
 
 	// Get menu items
-	menuItems, err := t.store.MenuItemList(ctx, cmsstore.MenuItemQuery().
-		SetMenuID(menuID).
-		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE).
-		SetOrderBy(cmsstore.COLUMN_SEQUENCE).
-		SetSortOrder("asc"))
+	menuItems, err := t.store.MenuItemsResolve(ctx, menuID)
 	if err != nil {
 		return "", fmt.Errorf("failed to get menu items: %w", err)
 	}
@@ -106,6 +106,21 @@ func (t *NavbarBlockType) Render(ctx context.Context, block cmsstore.BlockInterf
 	fixed := block.Meta(cmsstore.BLOCK_META_NAVBAR_FIXED) == "true"
 	dark := block.Meta(cmsstore.BLOCK_META_NAVBAR_DARK) == "true"
 
+	// Use the renderer registered for the rendering mode, if any
+	if renderer := cmsstore.GetMenuRenderer(cmsstore.BLOCK_TYPE_NAVBAR, renderingMode); renderer != nil {
+		return renderer.Render(ctx, cmsstore.MenuRenderData{
+			BlockType:  cmsstore.BLOCK_TYPE_NAVBAR,
+			Block:      block,
+			Style:      style,
+			CSSClass:   cssClass,
+			CSSID:      cssID,
+			Attributes: options.Attributes,
+			Items: cmsstore.MenuRenderTree(ctx, menuItems, func(item cmsstore.MenuItemInterface) string {
+				return resolveMenuItemURL(ctx, t.store, item)
+			}),
+		})
+	}
+
 	// Use the navbar renderer with unique ID based on block ID
 	return renderNavbarHTML(ctx, t.store, block.ID(), menuItems, style, renderingMode, cssClass, cssID, brandText, brandURL, brandImageURL, brandImageWidth, brandImageHeight, brandImageAlt, fixed, dark, customCSS)
 }
@@ -172,7 +187,7 @@ func (t *NavbarBlockType) GetAdminFields(block cmsstore.BlockInterface, r *http.
 			Type:  form.FORM_FIELD_TYPE_SELECT,
 			Value: block.Meta(cmsstore.BLOCK_META_NAVBAR_RENDERING_MODE),
 			Help:  "Choose the rendering framework",
-			Options: []form.FieldOption{
+			Options: renderingModeOptions([]form.FieldOption{
 				{
 					Value: "Plain",
 					Key:   cmsstore.BLOCK_NAVBAR_RENDERING_PLAIN,
@@ -181,7 +196,7 @@ func (t *NavbarBlockType) GetAdminFields(block cmsstore.BlockInterface, r *http.
 					Value: "Bootstrap 5",
 					Key:   cmsstore.BLOCK_NAVBAR_RENDERING_BOOTSTRAP5,
 				},
-			},
+			}),
 		}),
 		form.NewField(form.FieldOptions{
 			Label: "Brand Text",
@@ -285,11 +300,42 @@ func (t *NavbarBlockType) GetAdminFields(block cmsstore.BlockInterface, r *http.
 	return fieldsContent
 }
 
+// renderingModeOptions adds the rendering modes registered for navbar
+// blocks (see cmsstore.RegisterMenuRenderer) to the built-in rendering modes
+func renderingModeOptions(options []form.FieldOption) []form.FieldOption {
+	for _, renderer := range cmsstore.GetMenuRenderers(cmsstore.BLOCK_TYPE_NAVBAR) {
+		if !slices.ContainsFunc(options, func(option form.FieldOption) bool { return option.Key == renderer.Mode() }) {
+			options = append(options, form.FieldOption{Value: renderer.Label(), Key: renderer.Mode()})
+		}
+	}
+
+	return options
+}
+
 // GetCustomVariables returns nil as navbar blocks do not set any custom variables.
 func (t *NavbarBlockType) GetCustomVariables() []cmsstore.BlockCustomVariable {
 	return nil
 }
 
+// CachePolicy caches the rendered navbar per page, as the active item
+// depends on the page, until the menus or pages are changed. The navbar
+// is not cached if its menu items have visibility rules.
+func (t *NavbarBlockType) CachePolicy(ctx context.Context, block cmsstore.BlockInterface) *cmsstore.BlockCachePolicy {
+	if menuID := block.Meta(cmsstore.BLOCK_META_MENU_ID); menuID != "" {
+		hasRules, err := t.visibilityRules.MenuHasVisibilityRules(ctx, t.store, menuID)
+
+		if err != nil || hasRules {
+			return nil
+		}
+	}
+
+	return &cmsstore.BlockCachePolicy{
+		VaryByLanguage: true,
+		VaryByPath:     true,
+		Dependencies:   []string{cmsstore.CHANGE_KIND_MENUS, cmsstore.CHANGE_KIND_PAGES, cmsstore.CHANGE_KIND_ENTITIES},
+	}
+}
+
 // SaveAdminFields processes form submission and updates the navbar block.
 func (t *NavbarBlockType) SaveAdminFields(r *http.Request, block cmsstore.BlockInterface) error {
 	r.ParseForm()
@@ -99,12 +99,42 @@ func (m *TestNavbarMenuItem) Metas() (map[string]string, error) {
 	}
 	return m.data, nil
 }
-func (m *TestNavbarMenuItem) PageID() string   { return "" }
-func (m *TestNavbarMenuItem) ParentID() string { return m.parentID }
-func (m *TestNavbarMenuItem) Sequence() string { return "1" }
-func (m *TestNavbarMenuItem) SequenceInt() int { return 1 }
-func (m *TestNavbarMenuItem) Status() string   { return "active" }
-func (m *TestNavbarMenuItem) Target() string   { return "_self" }
+func (m *TestNavbarMenuItem) PageID() string                                             { return "" }
+func (m *TestNavbarMenuItem) ParentID() string                                           { return m.parentID }
+func (m *TestNavbarMenuItem) Sequence() string                                           { return "1" }
+func (m *TestNavbarMenuItem) SequenceInt() int                                           { return 1 }
+func (m *TestNavbarMenuItem) Status() string                                             { return "active" }
+func (m *TestNavbarMenuItem) Target() string                                             { return "_self" }
+func (m *TestNavbarMenuItem) Anchor() string                                             { return m.Meta(cmsstore.MENU_ITEM_META_ANCHOR) }
+func (m *TestNavbarMenuItem) SetAnchor(anchor string) cmsstore.MenuItemInterface         { return m }
+func (m *TestNavbarMenuItem) BlockID() string                                            { return m.Meta(cmsstore.MENU_ITEM_META_BLOCK_ID) }
+func (m *TestNavbarMenuItem) SetBlockID(blockID string) cmsstore.MenuItemInterface       { return m }
+func (m *TestNavbarMenuItem) CSSClass() string                                           { return m.Meta(cmsstore.MENU_ITEM_META_CSS_CLASS) }
+func (m *TestNavbarMenuItem) SetCSSClass(cssClass string) cmsstore.MenuItemInterface     { return m }
+func (m *TestNavbarMenuItem) EntityID() string                                           { return m.Meta(cmsstore.MENU_ITEM_META_ENTITY_ID) }
+func (m *TestNavbarMenuItem) SetEntityID(entityID string) cmsstore.MenuItemInterface     { return m }
+func (m *TestNavbarMenuItem) Icon() string                                               { return m.Meta(cmsstore.MENU_ITEM_META_ICON) }
+func (m *TestNavbarMenuItem) SetIcon(icon string) cmsstore.MenuItemInterface             { return m }
+func (m *TestNavbarMenuItem) Label(language string) string                               { return m.Name() }
+func (m *TestNavbarMenuItem) Labels() map[string]string                                  { return map[string]string{} }
+func (m *TestNavbarMenuItem) SetLabel(language, label string) cmsstore.MenuItemInterface { return m }
+func (m *TestNavbarMenuItem) TranslatedURL(language string) string                       { return m.url }
+func (m *TestNavbarMenuItem) TranslatedURLs() map[string]string                          { return map[string]string{} }
+func (m *TestNavbarMenuItem) SetTranslatedURL(language, url string) cmsstore.MenuItemInterface {
+	return m
+}
+func (m *TestNavbarMenuItem) Rel() string                                            { return m.Meta(cmsstore.MENU_ITEM_META_REL) }
+func (m *TestNavbarMenuItem) SetRel(rel string) cmsstore.MenuItemInterface           { return m }
+func (m *TestNavbarMenuItem) SetType(menuItemType string) cmsstore.MenuItemInterface { return m }
+func (m *TestNavbarMenuItem) Type() string {
+	if menuItemType := m.Meta(cmsstore.MENU_ITEM_META_TYPE); menuItemType != "" {
+		return menuItemType
+	}
+	if m.PageID() != "" {
+		return cmsstore.MENU_ITEM_TYPE_PAGE
+	}
+	return cmsstore.MENU_ITEM_TYPE_URL
+}
 
 // TestNavbarBlock is a mock implementation of BlockInterface for testing
 type TestNavbarBlock struct {
@@ -135,6 +165,10 @@ func (b *TestNavbarBlock) Editor() string
 func (b *TestNavbarBlock) SetEditor(editor string) cmsstore.BlockInterface               { return b }
 func (b *TestNavbarBlock) Handle() string                                                { return "test-handle" }
 func (b *TestNavbarBlock) SetHandle(handle string) cmsstore.BlockInterface               { return b }
+func (b *TestNavbarBlock) IsLibrary() bool                                               { return false }
+func (b *TestNavbarBlock) SetLibrary(isLibrary bool) error                               { return nil }
+func (b *TestNavbarBlock) LibraryFields() []string                                       { return nil }
+func (b *TestNavbarBlock) SetLibraryFields(fields []string) error                        { return nil }
 func (b *TestNavbarBlock) Memo() string                                                  { return "" }
 func (b *TestNavbarBlock) SetMemo(memo string) cmsstore.BlockInterface                   { return b }
 func (b *TestNavbarBlock) Name() string                                                  { return "Test Block" }
@@ -889,3 +923,108 @@ func TestNavbarBlockType_GetCustomVariables(t *testing.T) {
 		t.Errorf("expected nil custom variables for navbar block, got %v", vars)
 	}
 }
+
+func TestNavbarBlockType_RenderMenuItemTypes(t *testing.T) {
+	ctx := context.Background()
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	products := cmsstore.NewMenuItem().SetID("products").SetName("Products").SetURL("#").SetSequenceInt(0)
+	heading := cmsstore.NewMenuItem().SetID("heading").SetName("Catalog").SetParentID("products").SetSequenceInt(0).
+		SetType(cmsstore.MENU_ITEM_TYPE_HEADING)
+	lamps := cmsstore.NewMenuItem().SetID("lamps").SetName("Lamps").SetURL("https://example.com/lamps").SetParentID("products").SetSequenceInt(1).
+		SetIcon("bi bi-lamp").
+		SetRel("nofollow").
+		SetCSSClass("featured")
+	separator := cmsstore.NewMenuItem().SetID("separator").SetName("-").SetParentID("products").SetSequenceInt(2).
+		SetType(cmsstore.MENU_ITEM_TYPE_SEPARATOR)
+	mega := cmsstore.NewMenuItem().SetID("mega").SetName("Explore").SetSequenceInt(1).
+		SetType(cmsstore.MENU_ITEM_TYPE_MEGA_MENU).
+		SetBlockID("BLOCK_MEGA")
+
+	menuItems := []cmsstore.MenuItemInterface{products, heading, lamps, separator, mega}
+
+	html, err := renderNavbarHTML(ctx, store, "block-1", menuItems, cmsstore.BLOCK_NAVBAR_STYLE_DEFAULT, cmsstore.BLOCK_NAVBAR_RENDERING_BOOTSTRAP5, "", "", "", "", "", "", "", "", false, false, "")
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	expected := []string{
+		`<h6 class="dropdown-header">Catalog</h6>`,
+		`<i aria-hidden="true" class="bi bi-lamp"></i> Lamps`,
+		`rel="nofollow"`,
+		`<li class="featured">`,
+		`<hr class="dropdown-divider"`,
+		`[[BLOCK_BLOCK_MEGA]]`,
+		`mega-menu`,
+	}
+
+	for _, want := range expected {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %q in: %s", want, html)
+		}
+	}
+
+	if strings.Index(html, "Catalog") > strings.Index(html, "Lamps") {
+		t.Errorf("Expected the children in sequence order, got: %s", html)
+	}
+
+	html, err = renderNavbarHTML(ctx, store, "block-1", menuItems, cmsstore.BLOCK_NAVBAR_STYLE_DEFAULT, cmsstore.BLOCK_NAVBAR_RENDERING_PLAIN, "", "", "", "", "", "", "", "", false, false, "")
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	for _, want := range []string{`class="navbar-heading"`, `role="separator"`, `[[BLOCK_BLOCK_MEGA]]`, `rel="nofollow"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %q in: %s", want, html)
+		}
+	}
+}
+
+func TestNavbarBlockType_RenderActiveTrail(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	team := cmsstore.NewPage()
+
+	// The request path differs from the menu item URL (i.e. language prefix)
+	req, _ := http.NewRequest("GET", "/fr/about/team", nil)
+	ctx := cmsstore.RequestToContext(context.Background(), req)
+	ctx = cmsstore.PageToContext(ctx, team)
+
+	about := cmsstore.NewMenuItem().SetID("about").SetName("About").SetURL("/about").SetSequenceInt(0)
+	teamItem := cmsstore.NewMenuItem().SetID("team").SetName("Team").SetURL("/about/team").SetParentID("about").SetPageID(team.ID())
+	contact := cmsstore.NewMenuItem().SetID("contact").SetName("Contact").SetURL("/contact").SetSequenceInt(1)
+
+	menuItems := []cmsstore.MenuItemInterface{about, teamItem, contact}
+
+	html, err := renderNavbarHTML(ctx, store, "block-1", menuItems, cmsstore.BLOCK_NAVBAR_STYLE_DEFAULT, cmsstore.BLOCK_NAVBAR_RENDERING_BOOTSTRAP5, "", "", "", "", "", "", "", "", false, false, "")
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	expected := []string{
+		`<a aria-current="page" class="dropdown-item active" href="/about/team">Team</a>`,
+		`class="nav-link dropdown-toggle active-parent"`,
+		`<a class="nav-link" href="/contact">Contact</a>`,
+	}
+
+	for _, want := range expected {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %q in: %s", want, html)
+		}
+	}
+
+	html, err = renderNavbarHTML(ctx, store, "block-1", menuItems, cmsstore.BLOCK_NAVBAR_STYLE_DEFAULT, cmsstore.BLOCK_NAVBAR_RENDERING_PLAIN, "", "", "", "", "", "", "", "", false, false, "")
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	if !strings.Contains(html, `<a aria-current="page" class="navbar-link active" href="/about/team">Team</a>`) {
+		t.Errorf("Expected the active item in: %s", html)
+	}
+}
@@ -1,8 +1,10 @@
 package navbar
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/dracory/cmsstore"
@@ -179,9 +181,11 @@ func renderBootstrap5Navbar(ctx context.Context, store cmsstore.StoreInterface,
 		}
 	}
 
+	activeAncestorIDs := cmsstore.MenuItemsActiveAncestorIDs(ctx, menuItems)
+
 	// Render top-level items with dropdown support
 	for _, item := range topLevelItems {
-		navItem := renderNavItemWithDropdown(ctx, store, item, menuItemMap, 0)
+		navItem := renderNavItemWithDropdown(ctx, store, item, menuItemMap, activeAncestorIDs, 0)
 		navbarMenu.AddChild(navItem)
 	}
 
@@ -193,10 +197,10 @@ func renderBootstrap5Navbar(ctx context.Context, store cmsstore.StoreInterface,
 }
 
 // renderNavItemWithDropdown renders a nav item with dropdown support for children
-func renderNavItemWithDropdown(ctx context.Context, store cmsstore.StoreInterface, item cmsstore.MenuItemInterface, menuItemMap map[string]cmsstore.MenuItemInterface, depth int) *hb.Tag {
+func renderNavItemWithDropdown(ctx context.Context, store cmsstore.StoreInterface, item cmsstore.MenuItemInterface, menuItemMap map[string]cmsstore.MenuItemInterface, activeAncestorIDs map[string]bool, depth int) *hb.Tag {
 	url := resolveMenuItemURL(ctx, store, item)
 
-	// Find children of this item
+	// Find children of this item, in their sequence order
 	var children []cmsstore.MenuItemInterface
 	for _, mi := range menuItemMap {
 		if mi.ParentID() == item.ID() {
@@ -204,13 +208,50 @@ func renderNavItemWithDropdown(ctx context.Context, store cmsstore.StoreInterfac
 		}
 	}
 
+	slices.SortStableFunc(children, func(a, b cmsstore.MenuItemInterface) int {
+		return cmp.Or(cmp.Compare(a.SequenceInt(), b.SequenceInt()), strings.Compare(a.ID(), b.ID()))
+	})
+
 	hasChildren := len(children) > 0
 
+	switch item.Type() {
+	case cmsstore.MENU_ITEM_TYPE_SEPARATOR:
+		return hb.Li().
+			Class("nav-item").
+			ClassIf(item.CSSClass() != "", item.CSSClass()).
+			Attr("role", "separator").
+			Child(hb.Div().Class("vr d-none d-lg-flex h-100 mx-2"))
+	case cmsstore.MENU_ITEM_TYPE_HEADING:
+		return hb.Li().
+			Class("nav-item").
+			ClassIf(item.CSSClass() != "", item.CSSClass()).
+			Child(menuItemLabel(hb.Span().Class("navbar-text"), item))
+	case cmsstore.MENU_ITEM_TYPE_MEGA_MENU:
+		navItem := hb.Li().
+			Class("nav-item").
+			Class("dropdown").
+			Class("mega-menu-item").
+			ClassIf(item.CSSClass() != "", item.CSSClass())
+
+		dropdownToggle := hb.A().
+			Class("nav-link").
+			Class("dropdown-toggle").
+			Href("#").
+			Attr("role", "button").
+			Attr("data-bs-toggle", "dropdown").
+			Attr("aria-expanded", "false")
+
+		navItem.AddChild(menuItemLabel(dropdownToggle, item))
+		navItem.AddChild(megaMenuPanel(item).Class("dropdown-menu"))
+		return navItem
+	}
+
 	if hasChildren {
 		// Render as dropdown
 		navItem := hb.Li()
 		navItem.Class("nav-item")
 		navItem.Class("dropdown")
+		navItem.ClassIf(item.CSSClass() != "", item.CSSClass())
 
 		// Dropdown toggle link
 		dropdownToggle := hb.A()
@@ -220,7 +261,8 @@ func renderNavItemWithDropdown(ctx context.Context, store cmsstore.StoreInterfac
 		dropdownToggle.Attr("role", "button")
 		dropdownToggle.Attr("data-bs-toggle", "dropdown")
 		dropdownToggle.Attr("aria-expanded", "false")
-		dropdownToggle.Text(item.Name())
+		menuItemActiveAttributes(ctx, dropdownToggle, item, activeAncestorIDs)
+		menuItemLabel(dropdownToggle, item)
 
 		// Add target attribute if set
 		if item.Target() != "" {
@@ -235,24 +277,7 @@ func renderNavItemWithDropdown(ctx context.Context, store cmsstore.StoreInterfac
 
 		// Add child items to dropdown
 		for _, child := range children {
-			childURL := resolveMenuItemURL(ctx, store, child)
-			dropdownItem := hb.Li()
-
-			childLink := hb.A()
-			childLink.Class("dropdown-item")
-			childLink.Text(child.Name())
-
-			if childURL != "" {
-				childLink.Href(childURL)
-				if child.Target() != "" {
-					childLink.Attr("target", child.Target())
-				}
-			} else {
-				childLink.Href("#")
-			}
-
-			dropdownItem.AddChild(childLink)
-			dropdownMenu.AddChild(dropdownItem)
+			dropdownMenu.AddChild(renderDropdownItem(ctx, store, child, activeAncestorIDs))
 		}
 
 		navItem.AddChild(dropdownMenu)
@@ -262,27 +287,54 @@ func renderNavItemWithDropdown(ctx context.Context, store cmsstore.StoreInterfac
 	// Render as simple nav item (no children)
 	navItem := hb.Li()
 	navItem.Class("nav-item")
+	navItem.ClassIf(item.CSSClass() != "", item.CSSClass())
 
 	if url != "" {
 		navLink := hb.A()
 		navLink.Class("nav-link")
 		navLink.Href(url)
-		navLink.Text(item.Name())
-
-		// Add target attribute if set
-		if item.Target() != "" {
-			navLink.Attr("target", item.Target())
-		}
-
-		navItem.AddChild(navLink)
+		menuItemActiveAttributes(ctx, navLink, item, activeAncestorIDs)
+		menuItemLinkAttributes(navLink, item)
+		navItem.AddChild(menuItemLabel(navLink, item))
 	} else {
 		// Render as plain text without link
-		navItem.Text(item.Name())
+		menuItemLabel(navItem, item)
 	}
 
 	return navItem
 }
 
+// renderDropdownItem renders a child item of a Bootstrap 5 dropdown
+func renderDropdownItem(ctx context.Context, store cmsstore.StoreInterface, child cmsstore.MenuItemInterface, activeAncestorIDs map[string]bool) *hb.Tag {
+	dropdownItem := hb.Li()
+	dropdownItem.ClassIf(child.CSSClass() != "", child.CSSClass())
+
+	switch child.Type() {
+	case cmsstore.MENU_ITEM_TYPE_SEPARATOR:
+		return dropdownItem.Child(hb.HR().Class("dropdown-divider"))
+	case cmsstore.MENU_ITEM_TYPE_HEADING:
+		return dropdownItem.Child(menuItemLabel(hb.H6().Class("dropdown-header"), child))
+	case cmsstore.MENU_ITEM_TYPE_MEGA_MENU:
+		return dropdownItem.Child(megaMenuPanel(child))
+	}
+
+	childURL := resolveMenuItemURL(ctx, store, child)
+
+	childLink := hb.A()
+	childLink.Class("dropdown-item")
+	menuItemLabel(childLink, child)
+
+	if childURL != "" {
+		childLink.Href(childURL)
+		menuItemActiveAttributes(ctx, childLink, child, activeAncestorIDs)
+		menuItemLinkAttributes(childLink, child)
+	} else {
+		childLink.Href("#")
+	}
+
+	return dropdownItem.Child(childLink)
+}
+
 // renderPlainNavbar renders a plain navbar without Bootstrap classes
 func renderPlainNavbar(ctx context.Context, store cmsstore.StoreInterface, blockID string, menuItems []cmsstore.MenuItemInterface, style, cssClass, cssID, brandText, brandURL, brandImageURL, brandImageWidth, brandImageHeight, brandImageAlt string, fixed, dark bool, customCSS string) (string, error) {
 	var result strings.Builder
@@ -400,28 +452,35 @@ func renderPlainNavbar(ctx context.Context, store cmsstore.StoreInterface, block
 		menu.Class("navbar-bottom")
 	}
 
+	activeAncestorIDs := cmsstore.MenuItemsActiveAncestorIDs(ctx, menuItems)
+
 	// Add menu items (flat list)
 	for _, item := range menuItems {
 		url := resolveMenuItemURL(ctx, store, item)
 
 		menuItem := hb.Li()
 		menuItem.Class("navbar-item")
-
-		if url != "" {
+		menuItem.ClassIf(item.CSSClass() != "", item.CSSClass())
+
+		switch {
+		case item.Type() == cmsstore.MENU_ITEM_TYPE_SEPARATOR:
+			menuItem.Class("navbar-separator")
+			menuItem.Attr("role", "separator")
+		case item.Type() == cmsstore.MENU_ITEM_TYPE_HEADING:
+			menuItem.AddChild(menuItemLabel(hb.Span().Class("navbar-heading"), item))
+		case item.Type() == cmsstore.MENU_ITEM_TYPE_MEGA_MENU:
+			menuItem.AddChild(menuItemLabel(hb.Span().Class("navbar-link"), item))
+			menuItem.AddChild(megaMenuPanel(item))
+		case url != "":
 			link := hb.A()
 			link.Class("navbar-link")
 			link.Href(url)
-			link.Text(item.Name())
-
-			// Add target attribute if set
-			if item.Target() != "" {
-				link.Attr("target", item.Target())
-			}
-
-			menuItem.AddChild(link)
-		} else {
+			menuItemActiveAttributes(ctx, link, item, activeAncestorIDs)
+			menuItemLinkAttributes(link, item)
+			menuItem.AddChild(menuItemLabel(link, item))
+		default:
 			// Render as plain text without link
-			menuItem.Text(item.Name())
+			menuItemLabel(menuItem, item)
 		}
 
 		menu.AddChild(menuItem)
@@ -432,6 +491,54 @@ func renderPlainNavbar(ctx context.Context, store cmsstore.StoreInterface, block
 	return result.String(), nil
 }
 
+// menuItemLabel adds the icon, if set, and the name of the menu item
+// to the tag
+func menuItemLabel(tag *hb.Tag, item cmsstore.MenuItemInterface) *hb.Tag {
+	if item.Icon() != "" {
+		tag.AddChild(hb.I().Class(item.Icon()).Attr("aria-hidden", "true"))
+		tag.Text(" ")
+	}
+
+	return tag.Text(item.Name())
+}
+
+// menuItemLinkAttributes adds the target and rel attributes of the menu
+// item to the link
+func menuItemLinkAttributes(link *hb.Tag, item cmsstore.MenuItemInterface) *hb.Tag {
+	if item.Target() != "" {
+		link.Attr("target", item.Target())
+	}
+
+	if item.Rel() != "" {
+		link.Attr("rel", item.Rel())
+	}
+
+	return link
+}
+
+// menuItemActiveAttributes marks the link of the active menu item, with the
+// "active" class and aria-current, and of the active ancestor menu items,
+// with the "active-parent" class (see cmsstore.MenuItemIsActive)
+func menuItemActiveAttributes(ctx context.Context, link *hb.Tag, item cmsstore.MenuItemInterface, activeAncestorIDs map[string]bool) *hb.Tag {
+	if cmsstore.MenuItemIsActive(ctx, item) {
+		return link.Class("active").Attr("aria-current", "page")
+	}
+
+	return link.ClassIf(activeAncestorIDs[item.ID()], "active-parent")
+}
+
+// megaMenuPanel returns the panel of a mega menu item, displaying its
+// block, which the frontend renders as a nested block
+func megaMenuPanel(item cmsstore.MenuItemInterface) *hb.Tag {
+	panel := hb.Div().Class("mega-menu")
+
+	if item.BlockID() != "" {
+		panel.HTML("[[BLOCK_" + item.BlockID() + "]]")
+	}
+
+	return panel
+}
+
 // resolveMenuItemURL resolves the URL for a menu item
 // If the item has a direct URL, it returns that.
 // Otherwise, if the item has a PageID, it looks up the page and returns its alias.
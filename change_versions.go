@@ -0,0 +1,42 @@
+package cmsstore
+
+import "sync"
+
+// changeVersions counts the changes made through the store, per kind
+// of data (see CHANGE_KIND_*), for the caches to detect changed data
+type changeVersions struct {
+	mu       sync.RWMutex
+	versions map[string]uint64
+}
+
+func newChangeVersions() *changeVersions {
+	return &changeVersions{
+		versions: map[string]uint64{},
+	}
+}
+
+// bump counts a change of the kinds of data
+func (changes *changeVersions) bump(kinds ...string) {
+	if changes == nil {
+		return
+	}
+
+	changes.mu.Lock()
+	defer changes.mu.Unlock()
+
+	for _, kind := range kinds {
+		changes.versions[kind]++
+	}
+}
+
+// version returns the number of changes of the kind of data
+func (changes *changeVersions) version(kind string) uint64 {
+	if changes == nil {
+		return 0
+	}
+
+	changes.mu.RLock()
+	defer changes.mu.RUnlock()
+
+	return changes.versions[kind]
+}
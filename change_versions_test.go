@@ -0,0 +1,65 @@
+package cmsstore
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStoreChangeVersion(t *testing.T) {
+	store, err := initStore(":memory:")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	block := NewBlock().SetSiteID("site1").SetType(BLOCK_TYPE_HTML)
+
+	if err := store.BlockCreate(ctx, block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if store.ChangeVersion(CHANGE_KIND_BLOCKS) != 1 {
+		t.Fatalf("Expected blocks version 1 after create, got %d", store.ChangeVersion(CHANGE_KIND_BLOCKS))
+	}
+
+	block.SetContent("updated")
+
+	if err := store.BlockUpdate(ctx, block); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.BlockDeleteByID(ctx, block.ID()); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if store.ChangeVersion(CHANGE_KIND_BLOCKS) != 3 {
+		t.Fatalf("Expected blocks version 3 after update and delete, got %d", store.ChangeVersion(CHANGE_KIND_BLOCKS))
+	}
+
+	if store.ChangeVersion(CHANGE_KIND_MENUS) != 0 || store.ChangeVersion(CHANGE_KIND_PAGES) != 0 {
+		t.Fatal("Expected the other kinds not to be changed")
+	}
+
+	menu := NewMenu().SetSiteID("site1")
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.MenuItemCreate(ctx, NewMenuItem().SetMenuID(menu.ID())); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if store.ChangeVersion(CHANGE_KIND_MENUS) != 2 {
+		t.Fatalf("Expected menus version 2 after menu and item create, got %d", store.ChangeVersion(CHANGE_KIND_MENUS))
+	}
+
+	if err := store.PageCreate(ctx, NewPage().SetSiteID("site1")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if store.ChangeVersion(CHANGE_KIND_PAGES) != 1 {
+		t.Fatalf("Expected pages version 1 after create, got %d", store.ChangeVersion(CHANGE_KIND_PAGES))
+	}
+}
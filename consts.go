@@ -6,6 +6,16 @@ const (
 	BLOCK_TYPE_MENU        = "menu"
 	BLOCK_TYPE_NAVBAR      = "navbar"
 	BLOCK_TYPE_BREADCRUMBS = "breadcrumbs"
+	BLOCK_TYPE_SECTION     = "section"
+	BLOCK_TYPE_ROW         = "row"
+	BLOCK_TYPE_COLUMN      = "column"
+	BLOCK_TYPE_TABS        = "tabs"
+	BLOCK_TYPE_ACCORDION   = "accordion"
+	BLOCK_TYPE_IMAGE       = "image"
+	BLOCK_TYPE_GALLERY     = "gallery"
+	BLOCK_TYPE_VIDEO       = "video"
+	BLOCK_TYPE_ENTITY_LIST = "entity_list"
+	BLOCK_TYPE_FORM        = "form"
 )
 
 // Block Meta Keys for Menu Type
@@ -72,6 +82,10 @@ const (
 	BLOCK_META_BREADCRUMBS_SEPARATOR      = "breadcrumbs_separator"
 	BLOCK_META_BREADCRUMBS_HOME_TEXT      = "breadcrumbs_home_text"
 	BLOCK_META_BREADCRUMBS_HOME_URL       = "breadcrumbs_home_url"
+
+	// BLOCK_META_BREADCRUMBS_JSON_LD is "true" to emit the BreadcrumbList
+	// JSON-LD alongside the HTML, see cmsstore.JsonLdBreadcrumbList
+	BLOCK_META_BREADCRUMBS_JSON_LD = "breadcrumbs_json_ld"
 )
 
 // Block Breadcrumbs Styles
@@ -87,6 +101,102 @@ const (
 	BLOCK_BREADCRUMBS_RENDERING_BOOTSTRAP5 = "bootstrap5"
 )
 
+// Block Meta Keys for Container Types
+const (
+	BLOCK_META_SECTION_ELEMENT     = "section_element"
+	BLOCK_META_SECTION_CONTAINER   = "section_container"
+	BLOCK_META_SECTION_CSS_CLASS   = "section_css_class"
+	BLOCK_META_SECTION_CSS_ID      = "section_css_id"
+	BLOCK_META_ROW_GUTTER          = "row_gutter"
+	BLOCK_META_ROW_CSS_CLASS       = "row_css_class"
+	BLOCK_META_COLUMN_WIDTH        = "column_width"
+	BLOCK_META_COLUMN_WIDTH_SM     = "column_width_sm"
+	BLOCK_META_COLUMN_WIDTH_MD     = "column_width_md"
+	BLOCK_META_COLUMN_WIDTH_LG     = "column_width_lg"
+	BLOCK_META_COLUMN_WIDTH_XL     = "column_width_xl"
+	BLOCK_META_COLUMN_CSS_CLASS    = "column_css_class"
+	BLOCK_META_TABS_STYLE          = "tabs_style"
+	BLOCK_META_TABS_CSS_CLASS      = "tabs_css_class"
+	BLOCK_META_ACCORDION_OPEN      = "accordion_open"
+	BLOCK_META_ACCORDION_MULTIPLE  = "accordion_multiple"
+	BLOCK_META_ACCORDION_CSS_CLASS = "accordion_css_class"
+)
+
+// Block Meta Keys for Media Types
+const (
+	BLOCK_META_IMAGE_MEDIA_ID        = "image_media_id"
+	BLOCK_META_IMAGE_MOBILE_MEDIA_ID = "image_mobile_media_id"
+	BLOCK_META_IMAGE_ALT             = "image_alt"
+	BLOCK_META_IMAGE_SIZES           = "image_sizes"
+	BLOCK_META_IMAGE_LOADING         = "image_loading"
+	BLOCK_META_IMAGE_CAPTION         = "image_caption"
+	BLOCK_META_IMAGE_LINK_URL        = "image_link_url"
+	BLOCK_META_IMAGE_CSS_CLASS       = "image_css_class"
+
+	BLOCK_META_GALLERY_MEDIA_IDS = "gallery_media_ids"
+	BLOCK_META_GALLERY_LAYOUT    = "gallery_layout"
+	BLOCK_META_GALLERY_COLUMNS   = "gallery_columns"
+	BLOCK_META_GALLERY_SIZES     = "gallery_sizes"
+	BLOCK_META_GALLERY_LOADING   = "gallery_loading"
+	BLOCK_META_GALLERY_CAPTIONS  = "gallery_captions"
+	BLOCK_META_GALLERY_INTERVAL  = "gallery_interval"
+	BLOCK_META_GALLERY_CSS_CLASS = "gallery_css_class"
+
+	BLOCK_META_VIDEO_MEDIA_ID        = "video_media_id"
+	BLOCK_META_VIDEO_POSTER_MEDIA_ID = "video_poster_media_id"
+	BLOCK_META_VIDEO_CONTROLS        = "video_controls"
+	BLOCK_META_VIDEO_AUTOPLAY        = "video_autoplay"
+	BLOCK_META_VIDEO_MUTED           = "video_muted"
+	BLOCK_META_VIDEO_LOOP            = "video_loop"
+	BLOCK_META_VIDEO_PRELOAD         = "video_preload"
+	BLOCK_META_VIDEO_CSS_CLASS       = "video_css_class"
+)
+
+// Block Meta Keys for Entity List Blocks
+const (
+	BLOCK_META_ENTITY_LIST_ENTITY_TYPE   = "entity_list_entity_type"
+	BLOCK_META_ENTITY_LIST_FILTERS       = "entity_list_filters"
+	BLOCK_META_ENTITY_LIST_SORT          = "entity_list_sort"
+	BLOCK_META_ENTITY_LIST_SORTABLE      = "entity_list_sortable"
+	BLOCK_META_ENTITY_LIST_PER_PAGE      = "entity_list_per_page"
+	BLOCK_META_ENTITY_LIST_ITEM_TEMPLATE = "entity_list_item_template"
+	BLOCK_META_ENTITY_LIST_EMPTY_TEXT    = "entity_list_empty_text"
+	BLOCK_META_ENTITY_LIST_PAGINATION    = "entity_list_pagination"
+	BLOCK_META_ENTITY_LIST_PARAM_PREFIX  = "entity_list_param_prefix"
+	BLOCK_META_ENTITY_LIST_CSS_CLASS     = "entity_list_css_class"
+)
+
+// Block Meta Keys for Form Blocks
+const (
+	BLOCK_META_FORM_FIELDS          = "form_fields"
+	BLOCK_META_FORM_SUBMIT_LABEL    = "form_submit_label"
+	BLOCK_META_FORM_SUCCESS_MESSAGE = "form_success_message"
+	BLOCK_META_FORM_REDIRECT_URL    = "form_redirect_url"
+	BLOCK_META_FORM_NOTIFY_EMAIL    = "form_notify_email"
+	BLOCK_META_FORM_RATE_LIMIT      = "form_rate_limit"
+	BLOCK_META_FORM_CSS_CLASS       = "form_css_class"
+)
+
+// Block Meta Keys for Library Blocks
+const (
+	BLOCK_META_LIBRARY        = "library"
+	BLOCK_META_LIBRARY_FIELDS = "library_fields"
+)
+
+// Block Meta Keys for the Visibility Rules (JSON), see BlockVisibility,
+// and the Experiment (JSON), see Experiment
+const (
+	BLOCK_META_VISIBILITY = "visibility"
+	BLOCK_META_EXPERIMENT = "experiment"
+)
+
+// Block Usage Entity Types
+const (
+	BLOCK_USAGE_ENTITY_BLOCK    = "block"
+	BLOCK_USAGE_ENTITY_PAGE     = "page"
+	BLOCK_USAGE_ENTITY_TEMPLATE = "template"
+)
+
 // Block Statuses
 const (
 	BLOCK_STATUS_DRAFT    = "draft"
@@ -104,6 +214,7 @@ const (
 // Column Names for Database Queries
 const (
 	COLUMN_ALIAS              = "alias"
+	COLUMN_BLOCK_ID           = "block_id"
 	COLUMN_CANONICAL_URL      = "canonical_url"
 	COLUMN_CONTENT            = "content"
 	COLUMN_CREATED_AT         = "created_at"
@@ -112,10 +223,12 @@ const (
 	COLUMN_EDITOR             = "editor"
 	COLUMN_FILE_EXTENSION     = "file_extension"
 	COLUMN_FILE_SIZE          = "file_size"
+	COLUMN_FORM_DATA          = "form_data"
 	COLUMN_ENTITY_ID          = "entity_id"
 	COLUMN_ENTITY_TYPE        = "entity_type"
 	COLUMN_ID                 = "id"
 	COLUMN_HANDLE             = "handle"
+	COLUMN_IP_ADDRESS         = "ip_address"
 	COLUMN_MEDIA_TYPE         = "media_type"
 	COLUMN_MEDIA_URL          = "media_url"
 	COLUMN_MEMO               = "memo"
@@ -132,6 +245,7 @@ const (
 	COLUMN_SEQUENCE           = "sequence"
 	COLUMN_SITE_ID            = "site_id"
 	COLUMN_SOFT_DELETED_AT    = "soft_deleted_at"
+	COLUMN_SOURCE_URL         = "source_url"
 	COLUMN_STATUS             = "status"
 	COLUMN_TARGET             = "target"
 	COLUMN_TYPE               = "type"
@@ -139,6 +253,7 @@ const (
 	COLUMN_TITLE              = "title"
 	COLUMN_UPDATED_AT         = "updated_at"
 	COLUMN_URL                = "url"
+	COLUMN_USER_AGENT         = "user_agent"
 )
 
 // VERSIONING_MAX_DATETIME is a far-future datetime used as the default soft-delete sentinel for versioning records.
@@ -153,6 +268,13 @@ const (
 	SORT_ORDER_DESC = "DESC"
 )
 
+// Form Submission Statuses
+const (
+	FORM_SUBMISSION_STATUS_NEW      = "new"
+	FORM_SUBMISSION_STATUS_READ     = "read"
+	FORM_SUBMISSION_STATUS_ARCHIVED = "archived"
+)
+
 // Media Statuses
 const (
 	MEDIA_STATUS_DRAFT    = "draft"
@@ -160,6 +282,18 @@ const (
 	MEDIA_STATUS_INACTIVE = "inactive"
 )
 
+// Media Meta Keys
+//
+// The width and height are the intrinsic dimensions of an image or video.
+// The resized variants of an image are stored with the variant prefix
+// and their width as key, i.e. "variant_480" => "/images/hero-480.webp",
+// and are used for the srcset of the responsive images.
+const (
+	MEDIA_META_WIDTH          = "width"
+	MEDIA_META_HEIGHT         = "height"
+	MEDIA_META_VARIANT_PREFIX = "variant_"
+)
+
 // Menu Statuses
 const (
 	MENU_STATUS_DRAFT    = "draft"
@@ -187,6 +321,14 @@ const (
 	PAGE_STATUS_INACTIVE = "inactive"
 )
 
+// Kinds of data counted by the change versions, see StoreInterface.ChangeVersion
+const (
+	CHANGE_KIND_BLOCKS   = "blocks"
+	CHANGE_KIND_ENTITIES = "entities"
+	CHANGE_KIND_MENUS    = "menus"
+	CHANGE_KIND_PAGES    = "pages"
+)
+
 // Page Editor Types
 const (
 	PAGE_EDITOR_BLOCKAREA   = "blockarea"
@@ -197,6 +339,16 @@ const (
 	PAGE_EDITOR_TEXTAREA    = "textarea"
 )
 
+// Page Meta Keys
+const (
+	// PAGE_META_ALIAS_AUTO is "yes" when the alias is derived from the
+	// alias of the parent page, see PageAliasIsAuto
+	PAGE_META_ALIAS_AUTO = "alias_auto"
+
+	// PAGE_META_EXPERIMENT is the experiment of the page (JSON), see Experiment
+	PAGE_META_EXPERIMENT = "experiment"
+)
+
 // Site Statuses
 const (
 	SITE_STATUS_DRAFT    = "draft"
@@ -204,6 +356,23 @@ const (
 	SITE_STATUS_INACTIVE = "inactive"
 )
 
+// Site Meta Keys
+const (
+	// SITE_META_PLACEHOLDER_ESCAPING is the escaping applied to placeholders
+	// without an explicit modifier, i.e. [[PageTitle]]. Sites without this
+	// meta (created before escaping was supported) default to raw output
+	SITE_META_PLACEHOLDER_ESCAPING = "placeholder_escaping"
+)
+
+// Placeholder Escaping Modes, used as modifiers, i.e. [[PageTitle|attr]]
+const (
+	PLACEHOLDER_ESCAPING_HTML = "html"
+	PLACEHOLDER_ESCAPING_ATTR = "attr"
+	PLACEHOLDER_ESCAPING_JS   = "js"
+	PLACEHOLDER_ESCAPING_URL  = "url"
+	PLACEHOLDER_ESCAPING_RAW  = "raw"
+)
+
 // Template Statuses
 const (
 	TEMPLATE_STATUS_DRAFT    = "draft"
@@ -211,6 +380,19 @@ const (
 	TEMPLATE_STATUS_INACTIVE = "inactive"
 )
 
+// Template Editor Types
+const (
+	// TEMPLATE_EDITOR_GOTEMPLATE renders the template with Go's html/template
+	// engine. Templates with any other editor value use [[Placeholder]] replacement
+	TEMPLATE_EDITOR_GOTEMPLATE = "gotemplate"
+)
+
+// Template Meta Keys
+const (
+	// TEMPLATE_META_PARENT_ID is the ID of the parent template this template extends
+	TEMPLATE_META_PARENT_ID = "parent_template_id"
+)
+
 // Translation Statuses
 const (
 	TRANSLATION_STATUS_DRAFT    = "draft"
@@ -260,4 +442,6 @@ const (
 	propertyKeyEntityID           = "entity_id"
 	propertyKeyEntityType         = "entity_type"
 	propertyKeyExtension          = "extension"
+	propertyKeyBlockID            = "block_id"
+	propertyKeyIPAddress          = "ip_address"
 )
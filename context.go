@@ -10,8 +10,12 @@ import (
 type contextKey string
 
 const (
-	httpRequestContextKey contextKey = "http_request"
-	varsContextKey        contextKey = "vars"
+	httpRequestContextKey   contextKey = "http_request"
+	pageContextKey          contextKey = "page"
+	pageAncestorsContextKey contextKey = "page_ancestors"
+	varsContextKey          contextKey = "vars"
+	renderStackContextKey   contextKey = "render_stack"
+	visitorContextKey       contextKey = "visitor"
 )
 
 // RequestFromContext retrieves the *http.Request from the context if it was
@@ -42,6 +46,41 @@ func RequestToContext(ctx context.Context, req *http.Request) context.Context {
 	return context.WithValue(ctx, httpRequestContextKey, req)
 }
 
+// PageFromContext retrieves the page being rendered from the context,
+// if it was previously added using PageToContext. Returns nil if the
+// content being rendered does not belong to a page.
+func PageFromContext(ctx context.Context) PageInterface {
+	if page, ok := ctx.Value(pageContextKey).(PageInterface); ok {
+		return page
+	}
+	return nil
+}
+
+// PageToContext adds the page being rendered to the context. This is called
+// internally by the frontend before rendering a page, so block types
+// (i.e. breadcrumbs) can access the current page via PageFromContext.
+func PageToContext(ctx context.Context, page PageInterface) context.Context {
+	return context.WithValue(ctx, pageContextKey, page)
+}
+
+// PageAncestorsFromContext retrieves the ancestors of the page being
+// rendered from the context, from the root page down to the direct parent,
+// if they were previously added using PageAncestorsToContext. Returns an
+// empty slice otherwise.
+func PageAncestorsFromContext(ctx context.Context) []PageInterface {
+	if ancestors, ok := ctx.Value(pageAncestorsContextKey).([]PageInterface); ok {
+		return ancestors
+	}
+	return []PageInterface{}
+}
+
+// PageAncestorsToContext adds the ancestors of the page being rendered to
+// the context. This is called internally by the frontend before rendering
+// a page, so block types (i.e. menus) can mark the active trail.
+func PageAncestorsToContext(ctx context.Context, ancestors []PageInterface) context.Context {
+	return context.WithValue(ctx, pageAncestorsContextKey, ancestors)
+}
+
 // VarsContext stores custom variables set by blocks during rendering.
 // Blocks can set arbitrary variables that will be replaced in the final content.
 //
@@ -123,3 +162,49 @@ func VarsFromContext(ctx context.Context) *VarsContext {
 	}
 	return nil
 }
+
+// WithRenderStackBlock returns a copy of the context with the block ID
+// pushed on the render stack. This is called internally by the frontend
+// before rendering a block, custom block types rendering nested content
+// must pass the received context on, so nested blocks are tracked.
+func WithRenderStackBlock(ctx context.Context, blockID string) context.Context {
+	parent := RenderStackFromContext(ctx)
+
+	stack := make([]string, len(parent), len(parent)+1)
+	copy(stack, parent)
+
+	return context.WithValue(ctx, renderStackContextKey, append(stack, blockID))
+}
+
+// RenderStackFromContext returns the IDs of the blocks being rendered,
+// from the outermost to the innermost. Returns an empty slice if no block
+// is being rendered.
+//
+// Example usage in a custom block:
+//
+//	if len(cmsstore.RenderStackFromContext(ctx)) > 1 {
+//		// rendered inside another block
+//	}
+func RenderStackFromContext(ctx context.Context) []string {
+	if stack, ok := ctx.Value(renderStackContextKey).([]string); ok {
+		return stack
+	}
+	return []string{}
+}
+
+// VisitorToContext adds the visitor of the page being rendered to the
+// context. This is called internally by the frontend before rendering a
+// page, so block types (i.e. menus) can evaluate the visibility rules.
+func VisitorToContext(ctx context.Context, visitor BlockVisitor) context.Context {
+	return context.WithValue(ctx, visitorContextKey, visitor)
+}
+
+// VisitorFromContext retrieves the visitor of the page being rendered from
+// the context, if it was previously added using VisitorToContext. Returns
+// an anonymous visitor of the request in the context, if any, otherwise.
+func VisitorFromContext(ctx context.Context) BlockVisitor {
+	if visitor, ok := ctx.Value(visitorContextKey).(BlockVisitor); ok {
+		return visitor
+	}
+	return BlockVisitor{Request: RequestFromContext(ctx)}
+}
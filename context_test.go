@@ -218,3 +218,42 @@ func TestVarsContext_VariableNamingConventions(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderStack(t *testing.T) {
+	ctx := context.Background()
+
+	if len(RenderStackFromContext(ctx)) != 0 {
+		t.Fatal("Expected empty render stack")
+	}
+
+	ctxA := WithRenderStackBlock(ctx, "a")
+	ctxB := WithRenderStackBlock(ctxA, "b")
+	ctxC := WithRenderStackBlock(ctxA, "c")
+
+	if stack := RenderStackFromContext(ctxB); len(stack) != 2 || stack[0] != "a" || stack[1] != "b" {
+		t.Errorf("Expected [a b], got %v", stack)
+	}
+
+	// Siblings must not share the underlying array
+	if stack := RenderStackFromContext(ctxC); len(stack) != 2 || stack[1] != "c" {
+		t.Errorf("Expected [a c], got %v", stack)
+	}
+
+	if stack := RenderStackFromContext(ctxA); len(stack) != 1 || stack[0] != "a" {
+		t.Errorf("Expected parent stack to be unchanged, got %v", stack)
+	}
+}
+
+func TestPageContext(t *testing.T) {
+	ctx := context.Background()
+
+	if PageFromContext(ctx) != nil {
+		t.Fatal("Expected no page")
+	}
+
+	page := NewPage().SetName("About")
+
+	if found := PageFromContext(PageToContext(ctx, page)); found == nil || found.ID() != page.ID() {
+		t.Errorf("Expected page %s, got %v", page.ID(), found)
+	}
+}
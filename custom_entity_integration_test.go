@@ -360,3 +360,64 @@ func TestCustomEntityWithRelationships(t *testing.T) {
 		t.Errorf("expected relationship type 'belongs_to', got %q", rels[0].GetRelationshipType())
 	}
 }
+
+func TestCustomEntityAttributeValues(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:?parseTime=true")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		BlockTableName:     "cms_block",
+		PageTableName:      "cms_page",
+		SiteTableName:      "cms_site",
+		TemplateTableName:  "cms_template",
+		AutomigrateEnabled: true,
+
+		CustomEntitiesEnabled: true,
+		CustomEntityDefinitions: []CustomEntityDefinition{
+			{
+				Type:      "product",
+				TypeLabel: "Product",
+				Attributes: []CustomAttributeDefinition{
+					{Name: "title", Type: "string", Label: "Title"},
+					{Name: "stock", Type: "int", Label: "Stock"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	ctx := context.Background()
+	customStore := store.CustomEntityStore()
+
+	entityIDs := []string{}
+	for _, title := range []string{"Laptop", "Phone"} {
+		entityID, err := customStore.Create(ctx, "product", map[string]interface{}{"title": title, "stock": 3}, nil, nil)
+		if err != nil {
+			t.Fatalf("failed to create product: %v", err)
+		}
+		entityIDs = append(entityIDs, entityID)
+	}
+
+	values, err := customStore.AttributeValues(ctx, append(entityIDs, "missing"))
+	if err != nil {
+		t.Fatalf("failed to load attribute values: %v", err)
+	}
+
+	if values[entityIDs[0]]["title"] != "Laptop" || values[entityIDs[1]]["title"] != "Phone" {
+		t.Errorf("expected the titles of the products, got %v", values)
+	}
+
+	if values[entityIDs[1]]["stock"] != "3" {
+		t.Errorf("expected the stock of the product, got %q", values[entityIDs[1]]["stock"])
+	}
+
+	if values["missing"] == nil || len(values["missing"]) != 0 {
+		t.Errorf("expected no attributes for a missing entity, got %v", values["missing"])
+	}
+}
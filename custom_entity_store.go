@@ -6,14 +6,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/dracory/entitystore"
+	"github.com/dracory/neat"
 )
 
 // CustomEntityStore wraps entitystore to provide CMS-specific custom entity functionality.
 type CustomEntityStore struct {
 	inner       entitystore.StoreInterface
+	neatDB      *neat.Database
 	definitions map[string]CustomEntityDefinition // Entity type -> definition mapping
+
+	// changeVersions counts the changes, set by the CMS store
+	changeVersions *changeVersions
 }
 
 // NewCustomEntityStore creates a new custom entity store wrapper.
@@ -81,8 +87,14 @@ func NewCustomEntityStore(db *sql.DB, options CustomEntityStoreOptions) (*Custom
 		return nil, fmt.Errorf("failed to create entitystore: %w", err)
 	}
 
+	neatDB, err := neat.NewFromSQLDB(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database: %w", err)
+	}
+
 	return &CustomEntityStore{
 		inner:       inner,
+		neatDB:      neatDB,
 		definitions: make(map[string]CustomEntityDefinition),
 	}, nil
 }
@@ -198,6 +210,8 @@ func (s *CustomEntityStore) Create(
 		}
 	}
 
+	s.changeVersions.bump(CHANGE_KIND_ENTITIES)
+
 	return entity.ID(), nil
 }
 
@@ -231,12 +245,19 @@ func (s *CustomEntityStore) Update(ctx context.Context, entity entitystore.Entit
 		}
 	}
 
+	s.changeVersions.bump(CHANGE_KIND_ENTITIES)
+
 	return s.inner.EntityUpdate(ctx, entity)
 }
 
 // Delete soft-deletes a custom entity.
 func (s *CustomEntityStore) Delete(ctx context.Context, entityID string) error {
 	_, err := s.inner.EntityTrash(ctx, entityID)
+
+	if err == nil {
+		s.changeVersions.bump(CHANGE_KIND_ENTITIES)
+	}
+
 	return err
 }
 
@@ -259,6 +280,50 @@ func (s *CustomEntityStore) GetTaxonomyAssignments(ctx context.Context, entityID
 	})
 }
 
+// attributeValuesChunkSize is the number of entities, which attributes
+// are loaded by a single query
+const attributeValuesChunkSize = 500
+
+// AttributeValues returns the attribute values of the entities, by entity ID
+// and attribute key. The attributes are loaded in bulk, instead of one query
+// per entity.
+func (s *CustomEntityStore) AttributeValues(ctx context.Context, entityIDs []string) (map[string]map[string]string, error) {
+	values := make(map[string]map[string]string, len(entityIDs))
+
+	for _, entityID := range entityIDs {
+		values[entityID] = map[string]string{}
+	}
+
+	for chunk := range slices.Chunk(entityIDs, attributeValuesChunkSize) {
+		ids := make([]any, len(chunk))
+		for i, id := range chunk {
+			ids[i] = id
+		}
+
+		var rows []struct {
+			EntityID       string `db:"entity_id"`
+			AttributeKey   string `db:"attribute_key"`
+			AttributeValue string `db:"attribute_value"`
+		}
+
+		err := s.neatDB.Query().
+			Table(s.inner.GetAttributeTableName()).
+			Select(entitystore.COLUMN_ENTITY_ID, entitystore.COLUMN_ATTRIBUTE_KEY, entitystore.COLUMN_ATTRIBUTE_VALUE).
+			WhereIn(entitystore.COLUMN_ENTITY_ID, ids).
+			Get(&rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			values[row.EntityID][row.AttributeKey] = row.AttributeValue
+		}
+	}
+
+	return values, nil
+}
+
 // Inner returns the underlying entitystore for advanced operations.
 func (s *CustomEntityStore) Inner() entitystore.StoreInterface {
 	return s.inner
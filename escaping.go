@@ -0,0 +1,20 @@
+package cmsstore
+
+import (
+	"html"
+	"strings"
+)
+
+// ESCAPED_SQUARE_BRACKET is the character reference of "[", displayed
+// as is by the browsers but not parsed as a [[...]] directive
+const ESCAPED_SQUARE_BRACKET = "&#91;"
+
+// EscapeText escapes text from the visitors, i.e. the submitted form values
+// or the custom entity attributes, for the HTML output of a block.
+//
+// Besides the HTML escaping, the square brackets are escaped, as the block
+// output is parsed again by the frontend. The text is thus not rendered as
+// [[...]] blocks, translations or placeholders.
+func EscapeText(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "[", ESCAPED_SQUARE_BRACKET)
+}
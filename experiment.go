@@ -0,0 +1,260 @@
+package cmsstore
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"slices"
+
+	"github.com/dromara/carbon/v2"
+)
+
+// Experiment Statuses
+const (
+	EXPERIMENT_STATUS_DRAFT   = "draft"
+	EXPERIMENT_STATUS_RUNNING = "running"
+	EXPERIMENT_STATUS_STOPPED = "stopped"
+)
+
+// EXPERIMENT_COOKIE_PREFIX prefixes the cookie storing the variant
+// assigned to the visitor, followed by the ID of the page or block
+const EXPERIMENT_COOKIE_PREFIX = "cms_experiment_"
+
+// EXPERIMENT_VARIABLE_PREFIX prefixes the custom variable exposing
+// the assigned variant, followed by the experiment key
+const EXPERIMENT_VARIABLE_PREFIX = "experiment_"
+
+// experimentKeyRegex is the format of the experiment and variant keys,
+// safe for the variable names, the cookie values and the analytics
+var experimentKeyRegex = regexp.MustCompile(`^[a-z0-9_]{1,40}$`)
+
+// Experiment is an A/B test of a page or a block, stored as JSON
+// in its PAGE_META_EXPERIMENT (BLOCK_META_EXPERIMENT) meta.
+//
+// Business Logic:
+//   - the variants are displayed only while the experiment is running
+//   - each visitor is assigned a variant, by weight, kept in a cookie
+//   - a variant without content ID displays the original page or block
+//   - the assigned variant is exposed as the [[experiment_<key>]] variable
+type Experiment struct {
+	// Key identifies the experiment, i.e. in the analytics
+	Key string `json:"key"`
+
+	// Status is one of the EXPERIMENT_STATUS_* constants
+	Status string `json:"status"`
+
+	// StartedAt is the date (UTC) the experiment was last started
+	StartedAt string `json:"started_at,omitempty"`
+
+	// StoppedAt is the date (UTC) the experiment was last stopped
+	StoppedAt string `json:"stopped_at,omitempty"`
+
+	// Variants are the variants the visitors are assigned to
+	Variants []ExperimentVariant `json:"variants"`
+}
+
+// ExperimentVariant is a variant of the experiment
+type ExperimentVariant struct {
+	// Key identifies the variant, i.e. "a", "b"
+	Key string `json:"key"`
+
+	// ContentID is the ID of the page (or block) displayed for the variant,
+	// empty for the original page (or block)
+	ContentID string `json:"content_id,omitempty"`
+
+	// Weight is the share of the visitors assigned to the variant,
+	// relative to the weights of the other variants
+	Weight int `json:"weight"`
+}
+
+// Validate checks the experiment is well formed
+func (experiment Experiment) Validate() error {
+	if !experimentKeyRegex.MatchString(experiment.Key) {
+		return errors.New("experiment key must be 1 to 40 lowercase letters, digits or underscores")
+	}
+
+	if !slices.Contains([]string{EXPERIMENT_STATUS_DRAFT, EXPERIMENT_STATUS_RUNNING, EXPERIMENT_STATUS_STOPPED}, experiment.Status) {
+		return errors.New("experiment status is not supported: " + experiment.Status)
+	}
+
+	if len(experiment.Variants) < 2 {
+		return errors.New("experiment must have at least 2 variants")
+	}
+
+	keys := []string{}
+	for _, variant := range experiment.Variants {
+		if !experimentKeyRegex.MatchString(variant.Key) {
+			return errors.New("variant key must be 1 to 40 lowercase letters, digits or underscores: " + variant.Key)
+		}
+
+		if slices.Contains(keys, variant.Key) {
+			return errors.New("variant key must be unique: " + variant.Key)
+		}
+
+		if variant.Weight < 0 {
+			return errors.New("variant weight cannot be negative: " + variant.Key)
+		}
+
+		keys = append(keys, variant.Key)
+	}
+
+	if experiment.TotalWeight() == 0 {
+		return errors.New("experiment must have a variant with a weight")
+	}
+
+	return nil
+}
+
+// IsRunning returns true if the variants are displayed
+func (experiment Experiment) IsRunning() bool {
+	return experiment.Status == EXPERIMENT_STATUS_RUNNING
+}
+
+// Start starts (or restarts) the experiment
+func (experiment *Experiment) Start() {
+	experiment.Status = EXPERIMENT_STATUS_RUNNING
+	experiment.StartedAt = carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)
+	experiment.StoppedAt = ""
+}
+
+// Stop stops the experiment, the original is displayed to all the visitors
+func (experiment *Experiment) Stop() {
+	experiment.Status = EXPERIMENT_STATUS_STOPPED
+	experiment.StoppedAt = carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)
+}
+
+// TotalWeight returns the sum of the weights of the variants
+func (experiment Experiment) TotalWeight() int {
+	total := 0
+	for _, variant := range experiment.Variants {
+		total += max(variant.Weight, 0)
+	}
+	return total
+}
+
+// Variant returns the variant with the key
+func (experiment Experiment) Variant(key string) (ExperimentVariant, bool) {
+	for _, variant := range experiment.Variants {
+		if variant.Key == key {
+			return variant, true
+		}
+	}
+
+	return ExperimentVariant{}, false
+}
+
+// ChooseVariant returns the variant for the number, between 0 and
+// the total weight (excluded), each variant covering a range of
+// the size of its weight
+func (experiment Experiment) ChooseVariant(n int) ExperimentVariant {
+	for _, variant := range experiment.Variants {
+		if variant.Weight <= 0 {
+			continue
+		}
+
+		if n < variant.Weight {
+			return variant
+		}
+
+		n -= variant.Weight
+	}
+
+	return experiment.Variants[len(experiment.Variants)-1]
+}
+
+// VariableName returns the name of the custom variable exposing the variant
+func (experiment Experiment) VariableName() string {
+	return EXPERIMENT_VARIABLE_PREFIX + experiment.Key
+}
+
+// ExperimentCookieName returns the name of the cookie storing the variant
+// of the experiment of the page or block
+func ExperimentCookieName(ownerID string) string {
+	return EXPERIMENT_COOKIE_PREFIX + ownerID
+}
+
+// BlockExperiment returns the experiment of the block, nil if none
+func BlockExperiment(block BlockInterface) (*Experiment, error) {
+	if block == nil {
+		return nil, nil
+	}
+
+	return experimentFromMeta(block.Meta(BLOCK_META_EXPERIMENT))
+}
+
+// SetBlockExperiment validates and stores the experiment of the block,
+// a nil experiment removes it
+func SetBlockExperiment(block BlockInterface, experiment *Experiment) error {
+	if block == nil {
+		return errors.New("block is nil")
+	}
+
+	return experimentToMetas(block, BLOCK_META_EXPERIMENT, experiment)
+}
+
+// PageExperiment returns the experiment of the page, nil if none
+func PageExperiment(page PageInterface) (*Experiment, error) {
+	if page == nil {
+		return nil, nil
+	}
+
+	return experimentFromMeta(page.Meta(PAGE_META_EXPERIMENT))
+}
+
+// SetPageExperiment validates and stores the experiment of the page,
+// a nil experiment removes it
+func SetPageExperiment(page PageInterface, experiment *Experiment) error {
+	if page == nil {
+		return errors.New("page is nil")
+	}
+
+	return experimentToMetas(page, PAGE_META_EXPERIMENT, experiment)
+}
+
+// experimentFromMeta unmarshals the experiment meta, nil if empty
+func experimentFromMeta(value string) (*Experiment, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	experiment := Experiment{}
+
+	if err := json.Unmarshal([]byte(value), &experiment); err != nil {
+		return nil, err
+	}
+
+	return &experiment, nil
+}
+
+// experimentToMetas stores the experiment in the metas of the page or block
+func experimentToMetas(entity interface {
+	Metas() (map[string]string, error)
+	SetMetas(metas map[string]string) error
+}, key string, experiment *Experiment) error {
+	metas, err := entity.Metas()
+	if err != nil {
+		return err
+	}
+
+	if metas == nil {
+		metas = map[string]string{}
+	}
+
+	if experiment == nil {
+		delete(metas, key)
+		return entity.SetMetas(metas)
+	}
+
+	if err := experiment.Validate(); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(experiment)
+	if err != nil {
+		return err
+	}
+
+	metas[key] = string(value)
+
+	return entity.SetMetas(metas)
+}
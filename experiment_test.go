@@ -0,0 +1,119 @@
+package cmsstore
+
+import (
+	"testing"
+)
+
+func newTestExperiment() *Experiment {
+	return &Experiment{
+		Key:    "hero_test",
+		Status: EXPERIMENT_STATUS_DRAFT,
+		Variants: []ExperimentVariant{
+			{Key: "a", Weight: 70},
+			{Key: "b", ContentID: "variant_b", Weight: 30},
+		},
+	}
+}
+
+func TestExperiment_Validate(t *testing.T) {
+	if err := newTestExperiment().Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	invalid := map[string]func(experiment *Experiment){
+		"key":             func(experiment *Experiment) { experiment.Key = "Hero Test" },
+		"status":          func(experiment *Experiment) { experiment.Status = "paused" },
+		"single variant":  func(experiment *Experiment) { experiment.Variants = experiment.Variants[:1] },
+		"duplicate key":   func(experiment *Experiment) { experiment.Variants[1].Key = "a" },
+		"negative weight": func(experiment *Experiment) { experiment.Variants[1].Weight = -1 },
+		"no weight": func(experiment *Experiment) {
+			experiment.Variants[0].Weight = 0
+			experiment.Variants[1].Weight = 0
+		},
+	}
+
+	for name, modify := range invalid {
+		experiment := newTestExperiment()
+		modify(experiment)
+
+		if err := experiment.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestExperiment_ChooseVariant(t *testing.T) {
+	experiment := newTestExperiment()
+	experiment.Variants = append(experiment.Variants, ExperimentVariant{Key: "c", Weight: 0})
+
+	if total := experiment.TotalWeight(); total != 100 {
+		t.Fatalf("Expected total weight 100, got %d", total)
+	}
+
+	tests := map[int]string{0: "a", 69: "a", 70: "b", 99: "b"}
+
+	for n, expected := range tests {
+		if variant := experiment.ChooseVariant(n); variant.Key != expected {
+			t.Errorf("Expected variant %s for %d, got %s", expected, n, variant.Key)
+		}
+	}
+}
+
+func TestExperiment_StartStop(t *testing.T) {
+	experiment := newTestExperiment()
+
+	experiment.Start()
+	if !experiment.IsRunning() || experiment.StartedAt == "" {
+		t.Errorf("Expected the experiment to be running, got %+v", experiment)
+	}
+
+	experiment.Stop()
+	if experiment.IsRunning() || experiment.StoppedAt == "" {
+		t.Errorf("Expected the experiment to be stopped, got %+v", experiment)
+	}
+}
+
+func TestBlockExperiment_RoundTrip(t *testing.T) {
+	block := NewBlock()
+
+	experiment, err := BlockExperiment(block)
+	if err != nil || experiment != nil {
+		t.Fatalf("Expected no experiment, got %+v (%v)", experiment, err)
+	}
+
+	if err := SetBlockExperiment(block, newTestExperiment()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	experiment, err = BlockExperiment(block)
+	if err != nil || experiment == nil {
+		t.Fatalf("Expected the experiment, got %v", err)
+	}
+
+	if variant, found := experiment.Variant("b"); !found || variant.ContentID != "variant_b" {
+		t.Errorf("Expected variant b, got %+v", variant)
+	}
+
+	if err := SetBlockExperiment(block, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if block.Meta(BLOCK_META_EXPERIMENT) != "" {
+		t.Errorf("Expected the experiment to be removed")
+	}
+}
+
+func TestPageExperiment_InvalidNotStored(t *testing.T) {
+	page := NewPage()
+
+	experiment := newTestExperiment()
+	experiment.Key = ""
+
+	if err := SetPageExperiment(page, experiment); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	if page.Meta(PAGE_META_EXPERIMENT) != "" {
+		t.Errorf("Expected the invalid experiment not to be stored")
+	}
+}
@@ -0,0 +1,277 @@
+package cmsstore
+
+import (
+	"encoding/json"
+
+	"github.com/dracory/dataobject"
+	"github.com/dromara/carbon/v2"
+)
+
+type formSubmissionImplementation struct {
+	dataobject.DataObject
+}
+
+var _ FormSubmissionInterface = (*formSubmissionImplementation)(nil)
+
+func NewFormSubmission() FormSubmissionInterface {
+	o := &formSubmissionImplementation{}
+	o.SetID(GenerateShortID())
+	o.SetSiteID("")
+	o.SetBlockID("")
+	o.SetPageID("")
+	o.SetStatus(FORM_SUBMISSION_STATUS_NEW)
+	o.SetFormData(map[string]string{})
+	o.SetIPAddress("")
+	o.SetUserAgent("")
+	o.SetSourceURL("")
+	o.SetMemo("")
+	o.SetMetas(map[string]string{})
+	o.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	o.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	o.SetSoftDeletedAt(MAX_DATETIME)
+	return o
+}
+
+func NewFormSubmissionFromExistingData(data map[string]string) *formSubmissionImplementation {
+	o := &formSubmissionImplementation{}
+	o.Hydrate(data)
+	return o
+}
+
+func (o *formSubmissionImplementation) IsNew() bool {
+	return o.Status() == FORM_SUBMISSION_STATUS_NEW
+}
+
+func (o *formSubmissionImplementation) IsRead() bool {
+	return o.Status() == FORM_SUBMISSION_STATUS_READ
+}
+
+func (o *formSubmissionImplementation) IsArchived() bool {
+	return o.Status() == FORM_SUBMISSION_STATUS_ARCHIVED
+}
+
+func (o *formSubmissionImplementation) IsSoftDeleted() bool {
+	return o.SoftDeletedAtCarbon().Compare("<", carbon.Now(carbon.UTC))
+}
+
+func (o *formSubmissionImplementation) ID() string {
+	return o.Get(COLUMN_ID)
+}
+
+func (o *formSubmissionImplementation) SetID(id string) FormSubmissionInterface {
+	o.Set(COLUMN_ID, id)
+	return o
+}
+
+func (o *formSubmissionImplementation) SiteID() string {
+	return o.Get(COLUMN_SITE_ID)
+}
+
+func (o *formSubmissionImplementation) SetSiteID(siteID string) FormSubmissionInterface {
+	o.Set(COLUMN_SITE_ID, siteID)
+	return o
+}
+
+func (o *formSubmissionImplementation) BlockID() string {
+	return o.Get(COLUMN_BLOCK_ID)
+}
+
+func (o *formSubmissionImplementation) SetBlockID(blockID string) FormSubmissionInterface {
+	o.Set(COLUMN_BLOCK_ID, blockID)
+	return o
+}
+
+func (o *formSubmissionImplementation) PageID() string {
+	return o.Get(COLUMN_PAGE_ID)
+}
+
+func (o *formSubmissionImplementation) SetPageID(pageID string) FormSubmissionInterface {
+	o.Set(COLUMN_PAGE_ID, pageID)
+	return o
+}
+
+func (o *formSubmissionImplementation) Status() string {
+	return o.Get(COLUMN_STATUS)
+}
+
+func (o *formSubmissionImplementation) SetStatus(status string) FormSubmissionInterface {
+	o.Set(COLUMN_STATUS, status)
+	return o
+}
+
+// FormData returns the submitted values, by field name
+func (o *formSubmissionImplementation) FormData() (map[string]string, error) {
+	formDataStr := o.Get(COLUMN_FORM_DATA)
+
+	if formDataStr == "" {
+		formDataStr = "{}"
+	}
+
+	formData := map[string]string{}
+	errJson := json.Unmarshal([]byte(formDataStr), &formData)
+	if errJson != nil {
+		return map[string]string{}, errJson
+	}
+
+	return formData, nil
+}
+
+// FormValue returns the submitted value of the field
+func (o *formSubmissionImplementation) FormValue(name string) string {
+	formData, err := o.FormData()
+
+	if err != nil {
+		return ""
+	}
+
+	return formData[name]
+}
+
+func (o *formSubmissionImplementation) SetFormData(formData map[string]string) error {
+	if formData == nil {
+		formData = map[string]string{}
+	}
+
+	mapString, err := json.Marshal(formData)
+	if err != nil {
+		return err
+	}
+
+	o.Set(COLUMN_FORM_DATA, string(mapString))
+
+	return nil
+}
+
+func (o *formSubmissionImplementation) IPAddress() string {
+	return o.Get(COLUMN_IP_ADDRESS)
+}
+
+func (o *formSubmissionImplementation) SetIPAddress(ipAddress string) FormSubmissionInterface {
+	o.Set(COLUMN_IP_ADDRESS, ipAddress)
+	return o
+}
+
+func (o *formSubmissionImplementation) UserAgent() string {
+	return o.Get(COLUMN_USER_AGENT)
+}
+
+func (o *formSubmissionImplementation) SetUserAgent(userAgent string) FormSubmissionInterface {
+	o.Set(COLUMN_USER_AGENT, userAgent)
+	return o
+}
+
+func (o *formSubmissionImplementation) SourceURL() string {
+	return o.Get(COLUMN_SOURCE_URL)
+}
+
+func (o *formSubmissionImplementation) SetSourceURL(sourceURL string) FormSubmissionInterface {
+	o.Set(COLUMN_SOURCE_URL, sourceURL)
+	return o
+}
+
+func (o *formSubmissionImplementation) Memo() string {
+	return o.Get(COLUMN_MEMO)
+}
+
+func (o *formSubmissionImplementation) SetMemo(memo string) FormSubmissionInterface {
+	o.Set(COLUMN_MEMO, memo)
+	return o
+}
+
+func (o *formSubmissionImplementation) Metas() (map[string]string, error) {
+	metasStr := o.Get(COLUMN_METAS)
+
+	if metasStr == "" {
+		metasStr = "{}"
+	}
+
+	metasJson := map[string]string{}
+	errJson := json.Unmarshal([]byte(metasStr), &metasJson)
+	if errJson != nil {
+		return map[string]string{}, errJson
+	}
+
+	return metasJson, nil
+}
+
+func (o *formSubmissionImplementation) Meta(name string) string {
+	metas, err := o.Metas()
+
+	if err != nil {
+		return ""
+	}
+
+	if value, exists := metas[name]; exists {
+		return value
+	}
+
+	return ""
+}
+
+func (o *formSubmissionImplementation) SetMeta(name string, value string) error {
+	return o.UpsertMetas(map[string]string{name: value})
+}
+
+func (o *formSubmissionImplementation) SetMetas(metas map[string]string) error {
+	mapString, err := json.Marshal(metas)
+	if err != nil {
+		return err
+	}
+
+	o.Set(COLUMN_METAS, string(mapString))
+
+	return nil
+}
+
+func (o *formSubmissionImplementation) UpsertMetas(metas map[string]string) error {
+	currentMetas, err := o.Metas()
+
+	if err != nil {
+		return err
+	}
+
+	for k, v := range metas {
+		currentMetas[k] = v
+	}
+
+	return o.SetMetas(currentMetas)
+}
+
+func (o *formSubmissionImplementation) CreatedAt() string {
+	return o.Get(COLUMN_CREATED_AT)
+}
+
+func (o *formSubmissionImplementation) SetCreatedAt(createdAt string) FormSubmissionInterface {
+	o.Set(COLUMN_CREATED_AT, createdAt)
+	return o
+}
+
+func (o *formSubmissionImplementation) CreatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(o.CreatedAt())
+}
+
+func (o *formSubmissionImplementation) UpdatedAt() string {
+	return o.Get(COLUMN_UPDATED_AT)
+}
+
+func (o *formSubmissionImplementation) SetUpdatedAt(updatedAt string) FormSubmissionInterface {
+	o.Set(COLUMN_UPDATED_AT, updatedAt)
+	return o
+}
+
+func (o *formSubmissionImplementation) UpdatedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(o.UpdatedAt())
+}
+
+func (o *formSubmissionImplementation) SoftDeletedAt() string {
+	return o.Get(COLUMN_SOFT_DELETED_AT)
+}
+
+func (o *formSubmissionImplementation) SetSoftDeletedAt(softDeletedAt string) FormSubmissionInterface {
+	o.Set(COLUMN_SOFT_DELETED_AT, softDeletedAt)
+	return o
+}
+
+func (o *formSubmissionImplementation) SoftDeletedAtCarbon() *carbon.Carbon {
+	return carbon.Parse(o.SoftDeletedAt())
+}
@@ -0,0 +1,272 @@
+package cmsstore
+
+import "errors"
+
+func FormSubmissionQuery() FormSubmissionQueryInterface {
+	return &formSubmissionQuery{
+		parameters: make(map[string]any),
+	}
+}
+
+type formSubmissionQuery struct {
+	parameters map[string]any
+}
+
+var _ FormSubmissionQueryInterface = (*formSubmissionQuery)(nil)
+
+func (p *formSubmissionQuery) Validate() error {
+	if p.parameters == nil {
+		return errors.New("form submission query: parameters cannot be nil")
+	}
+
+	if p.HasID() && p.ID() == "" {
+		return errors.New("form submission query: id cannot be empty")
+	}
+
+	if p.HasIDIn() && len(p.IDIn()) < 1 {
+		return errors.New("form submission query: id_in cannot be empty array")
+	}
+
+	if p.HasBlockID() && p.BlockID() == "" {
+		return errors.New("form submission query: block_id cannot be empty")
+	}
+
+	if p.HasIPAddress() && p.IPAddress() == "" {
+		return errors.New("form submission query: ip_address cannot be empty")
+	}
+
+	if p.HasCreatedAtGte() && p.CreatedAtGte() == "" {
+		return errors.New("form submission query: created_at_gte cannot be empty")
+	}
+
+	if p.HasLimit() && p.Limit() < 0 {
+		return errors.New("form submission query: limit cannot be negative")
+	}
+
+	if p.HasOffset() && p.Offset() < 0 {
+		return errors.New("form submission query: offset cannot be negative")
+	}
+
+	if p.HasOrderBy() && p.OrderBy() == "" {
+		return errors.New("form submission query: order_by cannot be empty")
+	}
+
+	if p.HasStatus() && p.Status() == "" {
+		return errors.New("form submission query: status cannot be empty")
+	}
+
+	if p.HasStatusIn() && len(p.StatusIn()) < 1 {
+		return errors.New("form submission query: status_in cannot be empty array")
+	}
+
+	return nil
+}
+
+func (p *formSubmissionQuery) HasColumns() bool {
+	return p.hasParameter(propertyKeyColumns)
+}
+
+func (p *formSubmissionQuery) Columns() []string {
+	if p.parameters[propertyKeyColumns] == nil {
+		return []string{}
+	}
+	return p.parameters[propertyKeyColumns].([]string)
+}
+
+func (p *formSubmissionQuery) SetColumns(columns []string) FormSubmissionQueryInterface {
+	p.parameters[propertyKeyColumns] = columns
+	return p
+}
+
+func (p *formSubmissionQuery) HasID() bool {
+	return p.hasParameter(propertyKeyId)
+}
+
+func (p *formSubmissionQuery) ID() string {
+	return p.parameters[propertyKeyId].(string)
+}
+
+func (p *formSubmissionQuery) SetID(id string) FormSubmissionQueryInterface {
+	p.parameters[propertyKeyId] = id
+	return p
+}
+
+func (p *formSubmissionQuery) HasIDIn() bool {
+	return p.hasParameter(propertyKeyIdIn)
+}
+
+func (p *formSubmissionQuery) IDIn() []string {
+	return p.parameters[propertyKeyIdIn].([]string)
+}
+
+func (p *formSubmissionQuery) SetIDIn(idIn []string) FormSubmissionQueryInterface {
+	p.parameters[propertyKeyIdIn] = idIn
+	return p
+}
+
+func (p *formSubmissionQuery) HasSiteID() bool {
+	return p.hasParameter(propertyKeySiteID)
+}
+
+func (p *formSubmissionQuery) SiteID() string {
+	return p.parameters[propertyKeySiteID].(string)
+}
+
+func (p *formSubmissionQuery) SetSiteID(siteID string) FormSubmissionQueryInterface {
+	p.parameters[propertyKeySiteID] = siteID
+	return p
+}
+
+func (p *formSubmissionQuery) HasBlockID() bool {
+	return p.hasParameter(propertyKeyBlockID)
+}
+
+func (p *formSubmissionQuery) BlockID() string {
+	return p.parameters[propertyKeyBlockID].(string)
+}
+
+func (p *formSubmissionQuery) SetBlockID(blockID string) FormSubmissionQueryInterface {
+	p.parameters[propertyKeyBlockID] = blockID
+	return p
+}
+
+func (p *formSubmissionQuery) HasStatus() bool {
+	return p.hasParameter(propertyKeyStatus)
+}
+
+func (p *formSubmissionQuery) Status() string {
+	return p.parameters[propertyKeyStatus].(string)
+}
+
+func (p *formSubmissionQuery) SetStatus(status string) FormSubmissionQueryInterface {
+	p.parameters[propertyKeyStatus] = status
+	return p
+}
+
+func (p *formSubmissionQuery) HasStatusIn() bool {
+	return p.hasParameter(propertyKeyStatusIn)
+}
+
+func (p *formSubmissionQuery) StatusIn() []string {
+	return p.parameters[propertyKeyStatusIn].([]string)
+}
+
+func (p *formSubmissionQuery) SetStatusIn(statusIn []string) FormSubmissionQueryInterface {
+	p.parameters[propertyKeyStatusIn] = statusIn
+	return p
+}
+
+func (p *formSubmissionQuery) HasIPAddress() bool {
+	return p.hasParameter(propertyKeyIPAddress)
+}
+
+func (p *formSubmissionQuery) IPAddress() string {
+	return p.parameters[propertyKeyIPAddress].(string)
+}
+
+func (p *formSubmissionQuery) SetIPAddress(ipAddress string) FormSubmissionQueryInterface {
+	p.parameters[propertyKeyIPAddress] = ipAddress
+	return p
+}
+
+func (p *formSubmissionQuery) HasCreatedAtGte() bool {
+	return p.hasParameter(propertyKeyCreatedAtGte)
+}
+
+func (p *formSubmissionQuery) CreatedAtGte() string {
+	return p.parameters[propertyKeyCreatedAtGte].(string)
+}
+
+func (p *formSubmissionQuery) SetCreatedAtGte(createdAtGte string) FormSubmissionQueryInterface {
+	p.parameters[propertyKeyCreatedAtGte] = createdAtGte
+	return p
+}
+
+func (p *formSubmissionQuery) HasCountOnly() bool {
+	return p.hasParameter(propertyKeyCountOnly)
+}
+
+func (p *formSubmissionQuery) IsCountOnly() bool {
+	if !p.HasCountOnly() {
+		return false
+	}
+	return p.parameters[propertyKeyCountOnly].(bool)
+}
+
+func (p *formSubmissionQuery) SetCountOnly(isCountOnly bool) FormSubmissionQueryInterface {
+	p.parameters[propertyKeyCountOnly] = isCountOnly
+	return p
+}
+
+func (p *formSubmissionQuery) HasLimit() bool {
+	return p.hasParameter(propertyKeyLimit)
+}
+
+func (p *formSubmissionQuery) Limit() int {
+	return p.parameters[propertyKeyLimit].(int)
+}
+
+func (p *formSubmissionQuery) SetLimit(limit int) FormSubmissionQueryInterface {
+	p.parameters[propertyKeyLimit] = limit
+	return p
+}
+
+func (p *formSubmissionQuery) HasOffset() bool {
+	return p.hasParameter(propertyKeyOffset)
+}
+
+func (p *formSubmissionQuery) Offset() int {
+	return p.parameters[propertyKeyOffset].(int)
+}
+
+func (p *formSubmissionQuery) SetOffset(offset int) FormSubmissionQueryInterface {
+	p.parameters[propertyKeyOffset] = offset
+	return p
+}
+
+func (p *formSubmissionQuery) HasSortOrder() bool {
+	return p.hasParameter(propertyKeySortOrder)
+}
+
+func (p *formSubmissionQuery) SortOrder() string {
+	return p.parameters[propertyKeySortOrder].(string)
+}
+
+func (p *formSubmissionQuery) SetSortOrder(sortOrder string) FormSubmissionQueryInterface {
+	p.parameters[propertyKeySortOrder] = sortOrder
+	return p
+}
+
+func (p *formSubmissionQuery) HasOrderBy() bool {
+	return p.hasParameter(propertyKeyOrderBy)
+}
+
+func (p *formSubmissionQuery) OrderBy() string {
+	return p.parameters[propertyKeyOrderBy].(string)
+}
+
+func (p *formSubmissionQuery) SetOrderBy(orderBy string) FormSubmissionQueryInterface {
+	p.parameters[propertyKeyOrderBy] = orderBy
+	return p
+}
+
+func (p *formSubmissionQuery) HasSoftDeletedIncluded() bool {
+	return p.hasParameter(propertyKeySoftDeleteIncluded)
+}
+
+func (p *formSubmissionQuery) SoftDeletedIncluded() bool {
+	if !p.HasSoftDeletedIncluded() {
+		return false
+	}
+	return p.parameters[propertyKeySoftDeleteIncluded].(bool)
+}
+
+func (p *formSubmissionQuery) SetSoftDeletedIncluded(softDeletedIncluded bool) FormSubmissionQueryInterface {
+	p.parameters[propertyKeySoftDeleteIncluded] = softDeletedIncluded
+	return p
+}
+
+func (p *formSubmissionQuery) hasParameter(name string) bool {
+	_, ok := p.parameters[name]
+	return ok
+}
@@ -0,0 +1,65 @@
+package cmsstore
+
+type FormSubmissionQueryInterface interface {
+	Validate() error
+
+	Columns() []string
+	HasColumns() bool
+	SetColumns(columns []string) FormSubmissionQueryInterface
+
+	HasID() bool
+	ID() string
+	SetID(id string) FormSubmissionQueryInterface
+
+	HasIDIn() bool
+	IDIn() []string
+	SetIDIn(idIn []string) FormSubmissionQueryInterface
+
+	HasSiteID() bool
+	SiteID() string
+	SetSiteID(siteID string) FormSubmissionQueryInterface
+
+	HasBlockID() bool
+	BlockID() string
+	SetBlockID(blockID string) FormSubmissionQueryInterface
+
+	HasStatus() bool
+	Status() string
+	SetStatus(status string) FormSubmissionQueryInterface
+
+	HasStatusIn() bool
+	StatusIn() []string
+	SetStatusIn(statusIn []string) FormSubmissionQueryInterface
+
+	HasIPAddress() bool
+	IPAddress() string
+	SetIPAddress(ipAddress string) FormSubmissionQueryInterface
+
+	HasCreatedAtGte() bool
+	CreatedAtGte() string
+	SetCreatedAtGte(createdAtGte string) FormSubmissionQueryInterface
+
+	HasCountOnly() bool
+	IsCountOnly() bool
+	SetCountOnly(countOnly bool) FormSubmissionQueryInterface
+
+	HasLimit() bool
+	Limit() int
+	SetLimit(limit int) FormSubmissionQueryInterface
+
+	HasOffset() bool
+	Offset() int
+	SetOffset(offset int) FormSubmissionQueryInterface
+
+	HasSortOrder() bool
+	SortOrder() string
+	SetSortOrder(sortOrder string) FormSubmissionQueryInterface
+
+	HasOrderBy() bool
+	OrderBy() string
+	SetOrderBy(orderBy string) FormSubmissionQueryInterface
+
+	HasSoftDeletedIncluded() bool
+	SoftDeletedIncluded() bool
+	SetSoftDeletedIncluded(softDeleteIncluded bool) FormSubmissionQueryInterface
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/blocks/forms"
 	"github.com/dracory/ui"
 )
 
@@ -27,16 +28,57 @@ type Config struct {
 	// Required for frontend operation.
 	Store cmsstore.StoreInterface
 
+	// BlockTypeRegistry is the registry the block types are looked up in.
+	// Defaults to the registry of the store.
+	BlockTypeRegistry *cmsstore.BlockTypeRegistry
+
 	// CacheEnabled enables in-memory caching of rendered content.
 	CacheEnabled bool
 
 	// CacheExpireSeconds sets the TTL for cached items.
 	// Defaults to 600 seconds (10 minutes) if not set or <= 0.
+	//
+	// The cached blocks are invalidated by the changes made through the
+	// store of this process only (see cmsstore.StoreInterface.ChangeVersion).
+	// The changes made by other instances of the application, or directly
+	// in the database, are seen once the cached items expire, so this TTL
+	// is how long they may be stale.
 	CacheExpireSeconds int
 
 	// PageNotFoundHandler is called when a page is not found.
 	// If it returns handled=true, the frontend will use the result and skip the default 404 response.
 	PageNotFoundHandler func(w http.ResponseWriter, r *http.Request, alias string) (handled bool, result string)
+
+	// BlockMaxDepth sets the maximum number of nested blocks, i.e. a block
+	// referencing a block referencing a block. Deeper blocks are not rendered.
+	// Defaults to 10 if not set or <= 0.
+	BlockMaxDepth int
+
+	// FormSecret signs the CSRF tokens of the form blocks. Must be the same
+	// for all the instances of the application. If empty, a random secret
+	// is generated, invalidating the rendered forms on restart.
+	FormSecret string
+
+	// FormSubmissionHooks are called after a form submission is stored,
+	// i.e. to send a notification (see forms.EmailNotificationHook).
+	FormSubmissionHooks []forms.SubmissionHook
+
+	// IsLoggedIn tells whether the visitor is logged in to the application,
+	// for the logged in visibility rule of the blocks. If nil, the visitors
+	// are considered not logged in.
+	IsLoggedIn func(r *http.Request) bool
+
+	// Debug renders an inline HTML comment in place of the blocks which
+	// cannot be rendered, i.e. blocks referencing each other (cycle).
+	// Should not be enabled in production.
+	Debug bool
+
+	// LegacyContentRendering renders content with the previous pipeline,
+	// running a separate pass per directive kind, instead of the single-pass
+	// parser. Placeholders in block output are then resolved inconsistently.
+	//
+	// Deprecated: kept for migration only, will be removed.
+	LegacyContentRendering bool
 }
 
 // New creates a new Frontend instance with the provided configuration.
@@ -67,18 +109,33 @@ type Config struct {
 // Returns:
 //   - FrontendInterface: a configured frontend instance ready to handle requests
 func New(config Config) FrontendInterface {
+	if config.BlockMaxDepth <= 0 {
+		config.BlockMaxDepth = blockMaxDepthDefault
+	}
+
+	if config.BlockTypeRegistry == nil && config.Store != nil {
+		config.BlockTypeRegistry = config.Store.BlockTypeRegistry()
+	}
+
 	if config.CacheEnabled && config.CacheExpireSeconds <= 0 {
 		config.CacheExpireSeconds = 10 * 60 // 10 minutes
 	}
 
 	f := frontend{
-		blockEditorRenderer: config.BlockEditorRenderer,
-		logger:              config.Logger,
-		shortcodes:          config.Shortcodes,
-		store:               config.Store,
-		cacheEnabled:        config.CacheEnabled,
-		cacheExpireSeconds:  config.CacheExpireSeconds,
-		pageNotFoundHandler: config.PageNotFoundHandler,
+		blockEditorRenderer:    config.BlockEditorRenderer,
+		logger:                 config.Logger,
+		shortcodes:             config.Shortcodes,
+		store:                  config.Store,
+		blockTypes:             config.BlockTypeRegistry,
+		cacheEnabled:           config.CacheEnabled,
+		cacheExpireSeconds:     config.CacheExpireSeconds,
+		pageNotFoundHandler:    config.PageNotFoundHandler,
+		legacyContentRendering: config.LegacyContentRendering,
+		blockMaxDepth:          config.BlockMaxDepth,
+		debug:                  config.Debug,
+		formSecret:             []byte(config.FormSecret),
+		formSubmissionHooks:    config.FormSubmissionHooks,
+		isLoggedIn:             config.IsLoggedIn,
 	}
 	f.blockRenderers = initBlockRenderers(&f, config.Store)
 
@@ -3,11 +3,10 @@ package frontend
 import (
 	"context"
 	"html"
+	"maps"
 	"net/http"
 	"regexp"
 	"strings"
-
-	"github.com/dracory/cmsstore"
 )
 
 // Package-level compiled regex for performance
@@ -23,7 +22,8 @@ var attributePattern = regexp.MustCompile(`([\w-]+)(?:\s*=\s*(?:"([^"]*)"|'([^']
 // Supports two syntaxes:
 // 1. <block id="..." attr="value" /> - Primary syntax
 // 2. [[block id='...' attr='value']] - Alternative for HTML attribute contexts
-// This is called after legacy [[BLOCK_id]] processing.
+// This is called after legacy [[BLOCK_id]] processing. The blocks are
+// pushed on the render stack, see renderBlockLegacy.
 func (frontend *frontend) applyBlockAttributeSyntax(ctx context.Context, req *http.Request, content string) (string, error) {
 	// Find all <block ... /> tags (angle bracket syntax)
 	angleMatches := blockAttributeAngleBrackets.FindAllStringSubmatch(content, -1)
@@ -50,87 +50,105 @@ func (frontend *frontend) applyBlockAttributeSyntax(ctx context.Context, req *ht
 
 	// Process each match
 	for _, m := range allMatches {
-		fullTag := m.fullTag
-		attrString := m.attrs
-
 		// Parse attributes
-		attrs := parseAttributes(attrString)
-
-		// Get block ID (required)
-		blockID := attrs["id"]
-		if blockID == "" {
-			frontend.logger.Warn("Block attribute syntax: missing id attribute", "tag", fullTag)
-			content = strings.Replace(content, fullTag, "<!-- Block reference missing id -->", 1)
-			continue
-		}
+		attrs := parseAttributes(m.attrs)
+
+		// Render the block within the render stack
+		html, err := frontend.renderBlockLegacy(ctx, attrs["id"], func(ctx context.Context) (string, error) {
+			return frontend.renderBlockTag(ctx, m.fullTag, attrs), nil
+		})
 
-		// Fetch block from database
-		block, err := frontend.store.BlockFindByID(req.Context(), blockID)
 		if err != nil {
-			frontend.logger.Error("Block attribute syntax: error fetching block", "id", blockID, "error", err)
-			content = strings.Replace(content, fullTag, "<!-- Block error: "+blockID+" -->", 1)
-			continue
+			return content, err
 		}
 
-		if block == nil {
-			frontend.logger.Warn("Block attribute syntax: block not found", "id", blockID)
-			content = strings.Replace(content, fullTag, "<!-- Block not found: "+blockID+" -->", 1)
-			continue
-		}
+		// Replace the tag with rendered content
+		content = strings.Replace(content, m.fullTag, html, 1)
+	}
 
-		// Security: Check if block is active
-		if !block.IsActive() {
-			frontend.logger.Warn("Block attribute syntax: inactive block", "id", blockID)
-			content = strings.Replace(content, fullTag, "<!-- Block inactive: "+blockID+" -->", 1)
-			continue
-		}
+	return content, nil
+}
 
-		// Get block type from stored value
-		blockTypeKey := block.Type()
-
-		// Get block type (from global registry)
-		blockType := cmsstore.GetBlockType(blockTypeKey)
-
-		// Extract wrap attribute before filtering (it's handled here, not passed to renderer)
-		wrapElement := attrs["wrap"]
-
-		// Remove system attrs and sanitize before passing to renderer
-		runtimeAttrs := filterAndSanitizeAttrs(attrs) // remove "id", "wrap", sanitize values
-
-		// Render with attributes
-		var htmlOutput string
-		// Use the passed context which already has VarsContext and request
-		if blockType != nil {
-			// Render with attributes (or without if empty)
-			// Block types validate attributes internally if needed
-			if len(runtimeAttrs) > 0 {
-				htmlOutput, err = blockType.Render(ctx, block, cmsstore.WithAttributes(runtimeAttrs))
-			} else {
-				htmlOutput, err = blockType.Render(ctx, block)
-			}
-		} else {
-			// Fallback to local renderer registry
-			renderer := frontend.blockRenderers.GetRenderer(blockTypeKey)
-			htmlOutput, err = renderer.Render(ctx, block)
-		}
+// renderBlockTag renders a single block reference with attributes,
+// i.e. <block id="..." attr="value" />
+//
+// Business Logic:
+//   - the id attribute is required
+//   - library blocks can be referenced by ID or handle, the attributes
+//     matching their library fields override the block metas
+//   - missing, inactive and failing blocks render an HTML comment
+//   - the wrap attribute wraps the output in the given element
+//   - the other attributes are sanitized and passed to the block type
+//   - the block is rendered by renderBlockContent, with the visibility
+//     rules, the experiment variant and the cache of the block
+//
+// Parameters:
+// - ctx: the context, with the request and the vars context
+// - fullTag: the tag as found in the content, used for logging
+// - attrs: the parsed tag attributes
+//
+// Returns:
+// - html: the rendered block
+func (frontend *frontend) renderBlockTag(ctx context.Context, fullTag string, attrs map[string]string) string {
+	// Get block ID (required)
+	blockID := attrs["id"]
+	if blockID == "" {
+		frontend.logger.Warn("Block attribute syntax: missing id attribute", "tag", fullTag)
+		return "<!-- Block reference missing id -->"
+	}
+
+	// Fetch block from database
+	block, err := frontend.store.BlockFindByID(ctx, blockID)
+	if err != nil {
+		frontend.logger.Error("Block attribute syntax: error fetching block", "id", blockID, "error", err)
+		return "<!-- Block error: " + blockID + " -->"
+	}
 
+	// Library blocks can also be referenced by their handle
+	if block == nil {
+		block, err = frontend.findLibraryBlockByHandle(ctx, blockID)
 		if err != nil {
-			frontend.logger.Error("Block attribute syntax: render error", "id", blockID, "error", err)
-			htmlOutput = "<!-- Block render error: " + blockID + " -->"
+			frontend.logger.Error("Block attribute syntax: error fetching block", "id", blockID, "error", err)
+			return "<!-- Block error: " + blockID + " -->"
 		}
+	}
 
-		// Apply wrap element if specified
-		if wrapElement != "" {
-			// Sanitize the wrap element name (only allow alphanumeric and hyphens)
-			wrapElement = html.EscapeString(wrapElement)
-			htmlOutput = "<" + wrapElement + ">" + htmlOutput + "</" + wrapElement + ">"
-		}
+	if block == nil {
+		frontend.logger.Warn("Block attribute syntax: block not found", "id", blockID)
+		return "<!-- Block not found: " + blockID + " -->"
+	}
 
-		// Replace the tag with rendered content
-		content = strings.Replace(content, fullTag, htmlOutput, 1)
+	// Security: Check if block is active
+	if !block.IsActive() {
+		frontend.logger.Warn("Block attribute syntax: inactive block", "id", blockID)
+		return "<!-- Block inactive: " + blockID + " -->"
 	}
 
-	return content, nil
+	// Extract wrap attribute before filtering (it's handled here, not passed to renderer)
+	wrapElement := attrs["wrap"]
+
+	// The system attrs are handled here, not passed to the renderer
+	refAttrs := maps.Clone(attrs)
+	delete(refAttrs, "id")
+	delete(refAttrs, "wrap")
+
+	// The visibility rules, the experiment, the library fields and the
+	// cache are applied as for the [[BLOCK_id]] references
+	htmlOutput, err := frontend.renderBlockContent(ctx, block, refAttrs)
+
+	if err != nil {
+		frontend.logger.Error("Block attribute syntax: render error", "id", blockID, "error", err)
+		htmlOutput = "<!-- Block render error: " + blockID + " -->"
+	}
+
+	// Apply wrap element if specified
+	if wrapElement != "" {
+		// Sanitize the wrap element name (only allow alphanumeric and hyphens)
+		wrapElement = html.EscapeString(wrapElement)
+		htmlOutput = "<" + wrapElement + ">" + htmlOutput + "</" + wrapElement + ">"
+	}
+
+	return htmlOutput
 }
 
 // parseAttributes parses "key=value key2='value2' key3=unquoted" into map
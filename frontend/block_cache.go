@@ -0,0 +1,125 @@
+package frontend
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dracory/cmsstore"
+	"github.com/spf13/cast"
+)
+
+// fetchBlock returns the block specified by the ID, nil if not found
+//
+// Business Logic:
+// - the block is cached, until a block is changed through the store
+func (frontend *frontend) fetchBlock(ctx context.Context, blockID string) (cmsstore.BlockInterface, error) {
+	key := "block_" + blockID + "_v" + strconv.FormatUint(frontend.store.ChangeVersion(cmsstore.CHANGE_KIND_BLOCKS), 10)
+
+	if frontend.CacheHas(key) {
+		block := frontend.CacheGet(key)
+
+		if block == nil {
+			return nil, nil
+		}
+
+		return block.(cmsstore.BlockInterface), nil
+	}
+
+	block, err := frontend.store.BlockFindByID(ctx, blockID)
+
+	if err != nil {
+		frontend.CacheSet(key, nil, 10) // 10 seconds only, error
+		return nil, err
+	}
+
+	if block == nil {
+		frontend.CacheSet(key, nil, frontend.cacheExpireSeconds)
+		return nil, nil
+	}
+
+	frontend.CacheSet(key, block, frontend.cacheExpireSeconds)
+
+	return block, nil
+}
+
+// blockContentCacheKey returns the cache key of the rendered block
+//
+// Business Logic:
+//   - blocks of types with a cache policy (see cmsstore.BlockTypeWithCache)
+//     are cached per value of the vary keys of the policy, until the block
+//     or the data it depends on is changed through the store
+//   - the other blocks are cached per query string, until a block is changed
+//   - the rendered block is not cached if the policy of the block type is nil
+//
+// Returns:
+// - key: the cache key
+// - expireSeconds: how long the rendered block is cached
+// - cacheable: false if the rendered block must not be cached
+func (frontend *frontend) blockContentCacheKey(ctx context.Context, block cmsstore.BlockInterface) (key string, expireSeconds int, cacheable bool) {
+	r := cmsstore.RequestFromContext(ctx)
+	blocksVersion := strconv.FormatUint(frontend.store.ChangeVersion(cmsstore.CHANGE_KIND_BLOCKS), 10)
+
+	var blockType cmsstore.BlockType
+	if frontend.blockRenderers != nil {
+		blockType = frontend.blockRenderers.blockType(block.Type())
+	} else {
+		blockType = frontend.blockTypeRegistry().Get(block.Type())
+	}
+	_, withCache := blockType.(cmsstore.BlockTypeWithCache)
+
+	if !withCache {
+		key = "block_content_" + block.ID() + "_v" + blocksVersion
+
+		// Include query string in cache key so blocks that depend on query
+		// parameters (e.g. blog_post_list with ?page=N) are cached separately.
+		if r != nil && r.URL.RawQuery != "" {
+			key += "_q_" + r.URL.RawQuery
+		}
+
+		return key, frontend.cacheExpireSeconds, true
+	}
+
+	policy := cmsstore.BlockTypeCachePolicy(ctx, blockType, block)
+
+	if policy == nil {
+		return "", 0, false
+	}
+
+	parts := []string{"block_render_" + block.ID(), "v" + blocksVersion}
+
+	for _, dependency := range policy.Dependencies {
+		parts = append(parts, dependency+strconv.FormatUint(frontend.store.ChangeVersion(dependency), 10))
+	}
+
+	if policy.VaryByLanguage {
+		parts = append(parts, "l_"+cast.ToString(ctx.Value(LanguageKey{})))
+	}
+
+	if policy.VaryByPath && r != nil {
+		parts = append(parts, "p_"+r.URL.Path)
+	}
+
+	if policy.VaryByQuery && r != nil {
+		parts = append(parts, "q_"+r.URL.RawQuery)
+	} else if len(policy.VaryByQueryParams) > 0 && r != nil {
+		query := r.URL.Query()
+		values := url.Values{}
+
+		for _, name := range policy.VaryByQueryParams {
+			if query.Has(name) {
+				values[name] = query[name]
+			}
+		}
+
+		parts = append(parts, "q_"+values.Encode())
+	}
+
+	expireSeconds = policy.ExpireSeconds
+	if expireSeconds <= 0 {
+		expireSeconds = frontend.cacheExpireSeconds
+	}
+
+	return strings.Join(parts, "_"), expireSeconds, true
+}
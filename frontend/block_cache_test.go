@@ -0,0 +1,211 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+// countingBlockType renders the number of times it was rendered,
+// to tell cached renders apart
+type countingBlockType struct {
+	renders int
+	policy  *cmsstore.BlockCachePolicy
+}
+
+var _ cmsstore.BlockTypeWithCache = (*countingBlockType)(nil)
+
+func (t *countingBlockType) TypeKey() string   { return "cache_counting" }
+func (t *countingBlockType) TypeLabel() string { return "Counting" }
+func (t *countingBlockType) Render(_ context.Context, _ cmsstore.BlockInterface, _ ...cmsstore.RenderOption) (string, error) {
+	t.renders++
+	return "render " + strconv.Itoa(t.renders), nil
+}
+func (t *countingBlockType) GetAdminFields(_ cmsstore.BlockInterface, _ *http.Request) interface{} {
+	return nil
+}
+func (t *countingBlockType) SaveAdminFields(_ *http.Request, _ cmsstore.BlockInterface) error {
+	return nil
+}
+func (t *countingBlockType) GetCustomVariables() []cmsstore.BlockCustomVariable { return nil }
+func (t *countingBlockType) CachePolicy(_ context.Context, _ cmsstore.BlockInterface) *cmsstore.BlockCachePolicy {
+	return t.policy
+}
+
+func initBlockCacheTest(t *testing.T, policy *cmsstore.BlockCachePolicy) (*frontend, cmsstore.BlockInterface, func(target string) string) {
+	t.Helper()
+
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	block := cmsstore.NewBlock().
+		SetSiteID(testutils.SITE_01).
+		SetType("cache_counting").
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	registry := cmsstore.NewBlockTypeRegistry()
+	registry.RegisterWithOrigin(&countingBlockType{policy: policy}, cmsstore.BLOCK_ORIGIN_CUSTOM)
+
+	f := New(Config{
+		Store:              store,
+		Logger:             slog.New(slog.NewTextHandler(io.Discard, nil)),
+		BlockTypeRegistry:  registry,
+		CacheEnabled:       true,
+		CacheExpireSeconds: 60,
+	}).(*frontend)
+
+	render := func(target string) string {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		ctx := context.WithValue(cmsstore.RequestToContext(r.Context(), r), LanguageKey{}, "en")
+
+		content, err := f.fetchBlockContent(ctx, block.ID())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		return content
+	}
+
+	return f, block, render
+}
+
+func TestBlockCache_VaryKeys(t *testing.T) {
+	_, _, render := initBlockCacheTest(t, &cmsstore.BlockCachePolicy{
+		VaryByPath:        true,
+		VaryByQueryParams: []string{"page"},
+	})
+
+	steps := []struct {
+		target   string
+		expected string
+	}{
+		{"/about", "render 1"},
+		{"/about", "render 1"},
+		{"/about?utm_source=mail", "render 1"}, // other query parameters are ignored
+		{"/contact", "render 2"},
+		{"/about?page=2", "render 3"},
+		{"/about?page=2&utm_source=mail", "render 3"},
+	}
+
+	for _, step := range steps {
+		if content := render(step.target); content != step.expected {
+			t.Errorf("%s: expected %q, got %q", step.target, step.expected, content)
+		}
+	}
+}
+
+func TestBlockCache_InvalidatedByChanges(t *testing.T) {
+	f, block, render := initBlockCacheTest(t, &cmsstore.BlockCachePolicy{
+		Dependencies: []string{cmsstore.CHANGE_KIND_MENUS},
+	})
+
+	if content := render("/"); content != "render 1" {
+		t.Fatalf("Expected %q, got %q", "render 1", content)
+	}
+
+	// Changes the pages are not depended on keep the cached block
+	if err := f.store.PageCreate(context.Background(), cmsstore.NewPage().SetSiteID(testutils.SITE_01)); err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	if content := render("/"); content != "render 1" {
+		t.Fatalf("Expected the cached %q, got %q", "render 1", content)
+	}
+
+	// The dependencies invalidate the cached block
+	if err := f.store.MenuCreate(context.Background(), cmsstore.NewMenu().SetSiteID(testutils.SITE_01)); err != nil {
+		t.Fatalf("Failed to create menu: %v", err)
+	}
+
+	if content := render("/"); content != "render 2" {
+		t.Fatalf("Expected %q after the menu change, got %q", "render 2", content)
+	}
+
+	// The block itself invalidates the cached block
+	block.SetName("Changed")
+
+	if err := f.store.BlockUpdate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to update block: %v", err)
+	}
+
+	if content := render("/"); content != "render 3" {
+		t.Fatalf("Expected %q after the block change, got %q", "render 3", content)
+	}
+}
+
+func TestBlockCache_NilPolicyNotCached(t *testing.T) {
+	_, _, render := initBlockCacheTest(t, nil)
+
+	if content := render("/"); content != "render 1" {
+		t.Fatalf("Expected %q, got %q", "render 1", content)
+	}
+
+	if content := render("/"); content != "render 2" {
+		t.Fatalf("Expected the block not to be cached, got %q", content)
+	}
+}
+
+func TestBlockCache_AttributeSyntaxVariesByAttributes(t *testing.T) {
+	f, block, _ := initBlockCacheTest(t, &cmsstore.BlockCachePolicy{})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx := context.WithValue(cmsstore.RequestToContext(r.Context(), r), LanguageKey{}, "en")
+
+	steps := []struct {
+		content  string
+		expected string
+	}{
+		{`<block id="` + block.ID() + `" style="list" />`, "render 1"},
+		{`<block id="` + block.ID() + `" style="list" />`, "render 1"},
+		{`<block id="` + block.ID() + `" style="list" wrap="div" />`, "<div>render 1</div>"}, // wrap is not passed to the block
+		{`<block id="` + block.ID() + `" style="grid" />`, "render 2"},
+		{`<block id="` + block.ID() + `" style="grid" />`, "render 2"},
+	}
+
+	for _, step := range steps {
+		content, err := f.applyBlockAttributeSyntax(ctx, r, step.content)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if content != step.expected {
+			t.Errorf("%s: expected %q, got %q", step.content, step.expected, content)
+		}
+	}
+}
+
+func TestBlockCache_Expires(t *testing.T) {
+	_, _, render := initBlockCacheTest(t, &cmsstore.BlockCachePolicy{
+		ExpireSeconds: 1,
+	})
+
+	if content := render("/"); content != "render 1" {
+		t.Fatalf("Expected %q, got %q", "render 1", content)
+	}
+
+	if content := render("/"); content != "render 1" {
+		t.Fatalf("Expected the cached %q, got %q", "render 1", content)
+	}
+
+	// The changes made by other processes are not counted, the cached
+	// block is rendered again once expired
+	time.Sleep(1100 * time.Millisecond)
+
+	if content := render("/"); content != "render 2" {
+		t.Fatalf("Expected %q after the expiration, got %q", "render 2", content)
+	}
+}
@@ -0,0 +1,38 @@
+package frontend
+
+import (
+	"context"
+
+	"github.com/dracory/cmsstore"
+)
+
+// renderLibraryFields replaces the [[field]] placeholders of the library
+// fields in the rendered library block with the values of the instance
+//
+// Business Logic:
+//   - non-library blocks are returned untouched
+//   - the values are HTML escaped, unless a modifier is given
+//     (i.e. [[title|raw]])
+func renderLibraryFields(block cmsstore.BlockInterface, content string) string {
+	if block == nil || !block.IsLibrary() {
+		return content
+	}
+
+	return replacePlaceholders(content, cmsstore.BlockLibraryFieldValues(block), cmsstore.PLACEHOLDER_ESCAPING_HTML)
+}
+
+// findLibraryBlockByHandle finds the library block with the given handle,
+// blocks which are not library blocks are not returned
+func (frontend *frontend) findLibraryBlockByHandle(ctx context.Context, handle string) (cmsstore.BlockInterface, error) {
+	block, err := frontend.store.BlockFindByHandle(ctx, handle)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if block == nil || !block.IsLibrary() {
+		return nil, nil
+	}
+
+	return block, nil
+}
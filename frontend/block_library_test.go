@@ -0,0 +1,133 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+func TestLibraryBlock_Overrides(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	block := cmsstore.NewBlock().
+		SetSiteID(testutils.SITE_01).
+		SetHandle("cta").
+		SetType(cmsstore.BLOCK_TYPE_HTML).
+		SetContent(`<div class="cta"><h2>[[title]]</h2><a href="[[url|attr]]">Go</a></div>`).
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := block.SetLibrary(true); err != nil {
+		t.Fatalf("Failed to set library: %v", err)
+	}
+
+	if err := block.SetLibraryFields([]string{"title", "url"}); err != nil {
+		t.Fatalf("Failed to set library fields: %v", err)
+	}
+
+	if err := block.UpsertMetas(map[string]string{"title": "Join us", "url": "/join"}); err != nil {
+		t.Fatalf("Failed to set metas: %v", err)
+	}
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).(*frontend)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "legacy syntax uses the library values",
+			content:  `[[BLOCK_` + block.ID() + `]]`,
+			expected: `<div class="cta"><h2>Join us</h2><a href="/join">Go</a></div>`,
+		},
+		{
+			name:     "attribute overrides a library field",
+			content:  `<block id="` + block.ID() + `" title="Sign up today" />`,
+			expected: `<div class="cta"><h2>Sign up today</h2><a href="/join">Go</a></div>`,
+		},
+		{
+			name:     "referenced by handle",
+			content:  `<block id="cta" url="/pricing" />`,
+			expected: `<div class="cta"><h2>Join us</h2><a href="/pricing">Go</a></div>`,
+		},
+		{
+			name:     "overrides are escaped",
+			content:  `<block id="cta" title="Tom & Jerry's" />`,
+			expected: `<div class="cta"><h2>Tom &amp; Jerry&#39;s</h2><a href="/join">Go</a></div>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			html, err := f.renderContentToHtml(req, tt.content, TemplateRenderHtmlByIDOptions{})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if html != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, html)
+			}
+		})
+	}
+
+	stored, err := store.BlockFindByID(context.Background(), block.ID())
+	if err != nil {
+		t.Fatalf("Failed to find block: %v", err)
+	}
+
+	if stored.Meta("title") != "Join us" {
+		t.Errorf("Expected the library block to be unchanged, got title %q", stored.Meta("title"))
+	}
+}
+
+func TestLibraryBlock_HandleOnlyForLibraryBlocks(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	block := cmsstore.NewBlock().
+		SetSiteID(testutils.SITE_01).
+		SetHandle("footer").
+		SetType(cmsstore.BLOCK_TYPE_HTML).
+		SetContent(`<footer></footer>`).
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).(*frontend)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	html, err := f.renderContentToHtml(req, `<block id="footer" />`, TemplateRenderHtmlByIDOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if html != `<!-- Block not found: footer -->` {
+		t.Errorf("Expected the block not to be found by handle, got %q", html)
+	}
+}
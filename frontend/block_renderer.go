@@ -2,12 +2,18 @@ package frontend
 
 import (
 	"context"
+	"log/slog"
 	"sync"
 
 	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/blocks/breadcrumbs"
+	"github.com/dracory/cmsstore/blocks/entitylist"
+	"github.com/dracory/cmsstore/blocks/forms"
+	"github.com/dracory/cmsstore/blocks/layout"
+	"github.com/dracory/cmsstore/blocks/media"
+	menublock "github.com/dracory/cmsstore/blocks/menu"
 	"github.com/dracory/cmsstore/blocks/navbar"
 	"github.com/dracory/cmsstore/frontend/blocks/html"
-	"github.com/dracory/cmsstore/frontend/blocks/menu"
 )
 
 // BlockRenderer interface defines how different block types are rendered.
@@ -49,7 +55,8 @@ type BlockRenderer interface {
 //
 // Built-in block types:
 //   - cmsstore.BLOCK_TYPE_HTML: Renders raw HTML content
-//   - cmsstore.BLOCK_TYPE_MENU: Renders navigation menus
+//   - the menu, navbar, breadcrumbs, layout, media, entity list and form
+//     block types, bound to the store of the frontend
 //
 // Custom block types can be registered after frontend initialization:
 //
@@ -57,6 +64,15 @@ type BlockRenderer interface {
 type BlockRendererRegistry struct {
 	renderers map[string]BlockRenderer
 	mu        sync.RWMutex
+
+	// blockTypes is the BlockType registry checked first,
+	// the default global registry if nil
+	blockTypes *cmsstore.BlockTypeRegistry
+
+	// instanceBlockTypes are the built-in block types bound to the store
+	// of the frontend (and the form block type to its secret and hooks),
+	// used instead of the system block types of the BlockType registry
+	instanceBlockTypes map[string]cmsstore.BlockType
 }
 
 // NewBlockRendererRegistry creates a new registry for block renderers.
@@ -103,20 +119,22 @@ func (r *BlockRendererRegistry) GetRenderer(blockType string) BlockRenderer {
 // RenderBlock renders a block using the appropriate renderer.
 //
 // Lookup priority:
-//  1. Global BlockType registry (cmsstore.GetBlockType)
+//  1. BlockType registry (the store's, or the default global registry)
 //  2. Local BlockRenderer registry (this registry)
 //  3. Fallback to NoOpRenderer
-func (r *BlockRendererRegistry) RenderBlock(ctx context.Context, block cmsstore.BlockInterface) (string, error) {
+//
+// The options (i.e. the runtime attributes) are passed to the block
+// types, the local renderers do not support them.
+func (r *BlockRendererRegistry) RenderBlock(ctx context.Context, block cmsstore.BlockInterface, opts ...cmsstore.RenderOption) (string, error) {
 	if block == nil {
 		return "<!-- Block is nil -->", nil
 	}
 
 	blockType := block.Type()
 
-	// First, check global BlockType registry
-	globalBlockType := cmsstore.GetBlockType(blockType)
-	if globalBlockType != nil {
-		return globalBlockType.Render(ctx, block)
+	// First, check the BlockType registry
+	if registeredBlockType := r.blockType(blockType); registeredBlockType != nil {
+		return registeredBlockType.Render(ctx, block, opts...)
 	}
 
 	// Fall back to local renderer registry
@@ -124,6 +142,26 @@ func (r *BlockRendererRegistry) RenderBlock(ctx context.Context, block cmsstore.
 	return renderer.Render(ctx, block)
 }
 
+// blockType returns the block type registered for the type key, nil if
+// none. The block types bound to the frontend are used instead of the
+// system block types, the custom block types of the app are kept
+func (r *BlockRendererRegistry) blockType(typeKey string) cmsstore.BlockType {
+	blockTypes := r.blockTypes
+	if blockTypes == nil {
+		blockTypes = cmsstore.DefaultBlockTypeRegistry()
+	}
+
+	registered := blockTypes.Get(typeKey)
+
+	if instance, ok := r.instanceBlockTypes[typeKey]; ok {
+		if registered == nil || blockTypes.GetOrigin(typeKey) == cmsstore.BLOCK_ORIGIN_SYSTEM {
+			return instance
+		}
+	}
+
+	return registered
+}
+
 // NoOpRenderer is a fallback renderer that returns empty content
 type NoOpRenderer struct{}
 
@@ -135,15 +173,52 @@ func (r *NoOpRenderer) Render(ctx context.Context, block cmsstore.BlockInterface
 // initBlockRenderers initializes and registers all block renderers
 func initBlockRenderers(f *frontend, store cmsstore.StoreInterface) *BlockRendererRegistry {
 	registry := NewBlockRendererRegistry()
+	registry.blockTypes = f.blockTypeRegistry()
 
 	// Register HTML renderer (default)
 	registry.Register(cmsstore.BLOCK_TYPE_HTML, html.NewHTMLRenderer())
 
-	// Register Menu renderer
-	registry.Register(cmsstore.BLOCK_TYPE_MENU, menu.NewBlockRenderer(f))
+	logger := f.logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	// The built-in block types are bound to the store of this frontend and
+	// kept on the frontend, not shared through the registry with the other
+	// frontends. The form block type signs the forms with the secret of
+	// this frontend and calls its hooks.
+	instanceBlockTypes := []cmsstore.BlockType{
+		menublock.NewMenuBlockType(store, logger),
+		navbar.NewNavbarBlockType(store),
+		breadcrumbs.NewBreadcrumbsBlockType(store),
+		layout.NewSectionBlockType(store),
+		layout.NewRowBlockType(store),
+		layout.NewColumnBlockType(store),
+		layout.NewTabsBlockType(store),
+		layout.NewAccordionBlockType(store),
+		media.NewImageBlockType(store),
+		media.NewGalleryBlockType(store),
+		media.NewVideoBlockType(store),
+		entitylist.NewEntityListBlockType(store),
+		forms.NewFormBlockType(store, forms.WithSecret(f.formSecret), forms.WithSubmissionHooks(f.formSubmissionHooks...)),
+	}
+
+	registry.instanceBlockTypes = make(map[string]cmsstore.BlockType, len(instanceBlockTypes))
+
+	for _, blockType := range instanceBlockTypes {
+		registry.instanceBlockTypes[blockType.TypeKey()] = blockType
+	}
 
-	// Register Navbar block type globally so it's available for frontend rendering
-	cmsstore.RegisterSystemBlockType(navbar.NewNavbarBlockType(store))
+	// The admin, the REST API and the MCP server find the block types in
+	// the registry of the store. The default global registry is shared by
+	// the stores, so is left to the app and the admin.
+	if registry.blockTypes != cmsstore.DefaultBlockTypeRegistry() {
+		for _, blockType := range instanceBlockTypes {
+			if registry.blockTypes.Get(blockType.TypeKey()) == nil {
+				registry.blockTypes.RegisterWithOrigin(blockType, cmsstore.BLOCK_ORIGIN_SYSTEM)
+			}
+		}
+	}
 
 	return registry
 }
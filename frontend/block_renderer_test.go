@@ -7,6 +7,8 @@ import (
 	_ "modernc.org/sqlite"
 
 	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/blocks/breadcrumbs"
+	menublock "github.com/dracory/cmsstore/blocks/menu"
 	"github.com/dracory/cmsstore/testutils"
 )
 
@@ -303,10 +305,13 @@ func TestInitBlockRenderers(t *testing.T) {
 		t.Error("HTML renderer should be registered")
 	}
 
-	// Verify Menu renderer is registered
-	menuRenderer := registry.GetRenderer(cmsstore.BLOCK_TYPE_MENU)
-	if menuRenderer == nil {
-		t.Error("Menu renderer should be registered")
+	// Verify the menu and breadcrumbs block types are bound to the frontend
+	if _, ok := registry.blockType(cmsstore.BLOCK_TYPE_MENU).(*menublock.MenuBlockType); !ok {
+		t.Error("Menu block type should be registered")
+	}
+
+	if _, ok := registry.blockType(cmsstore.BLOCK_TYPE_BREADCRUMBS).(*breadcrumbs.BreadcrumbsBlockType); !ok {
+		t.Error("Breadcrumbs block type should be registered")
 	}
 }
 
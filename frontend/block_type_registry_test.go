@@ -0,0 +1,150 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+// greetingBlockType renders a fixed greeting, to tell registries apart
+type greetingBlockType struct {
+	greeting string
+}
+
+func (t *greetingBlockType) TypeKey() string   { return "registry_greeting" }
+func (t *greetingBlockType) TypeLabel() string { return "Greeting" }
+func (t *greetingBlockType) Render(_ context.Context, _ cmsstore.BlockInterface, _ ...cmsstore.RenderOption) (string, error) {
+	return t.greeting, nil
+}
+func (t *greetingBlockType) GetAdminFields(_ cmsstore.BlockInterface, _ *http.Request) interface{} {
+	return nil
+}
+func (t *greetingBlockType) SaveAdminFields(_ *http.Request, _ cmsstore.BlockInterface) error {
+	return nil
+}
+func (t *greetingBlockType) GetCustomVariables() []cmsstore.BlockCustomVariable { return nil }
+
+func TestBlockTypeRegistry_PerFrontend(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	block := cmsstore.NewBlock().
+		SetSiteID(testutils.SITE_01).
+		SetType("registry_greeting").
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	render := func(greeting string) string {
+		registry := cmsstore.NewBlockTypeRegistry()
+		registry.RegisterWithOrigin(&greetingBlockType{greeting: greeting}, cmsstore.BLOCK_ORIGIN_CUSTOM)
+
+		f := New(Config{
+			Store:             store,
+			Logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+			BlockTypeRegistry: registry,
+		}).(*frontend)
+
+		req := httptest.NewRequest("GET", "/", nil)
+
+		html, err := f.renderContentToHtml(req, "[[BLOCK_"+block.ID()+"]]", TemplateRenderHtmlByIDOptions{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if registry.Get(cmsstore.BLOCK_TYPE_NAVBAR) == nil {
+			t.Error("Expected the navbar block type to be registered in the frontend registry")
+		}
+
+		return html
+	}
+
+	if html := render("Hello"); html != "Hello" {
+		t.Errorf("Expected %q, got %q", "Hello", html)
+	}
+
+	if html := render("Hallo"); html != "Hallo" {
+		t.Errorf("Expected %q, got %q", "Hallo", html)
+	}
+
+	if cmsstore.DefaultBlockTypeRegistry().Get("registry_greeting") != nil {
+		t.Error("Expected the default registry to be left untouched")
+	}
+}
+
+func TestBlockTypeRegistry_DefaultRegistryNotRebound(t *testing.T) {
+	storeA, err := testutils.InitStore(filepath.Join(t.TempDir(), "a.db"))
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	storeB, err := testutils.InitStore(filepath.Join(t.TempDir(), "b.db"))
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	ctx := context.Background()
+
+	menu := cmsstore.NewMenu().
+		SetName("Main").
+		SetSiteID(testutils.SITE_01).
+		SetStatus(cmsstore.MENU_STATUS_ACTIVE)
+
+	if err := storeA.MenuCreate(ctx, menu); err != nil {
+		t.Fatalf("Failed to create menu: %v", err)
+	}
+
+	menuItem := cmsstore.NewMenuItem().
+		SetName("Store A Home").
+		SetMenuID(menu.ID()).
+		SetURL("/home").
+		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+
+	if err := storeA.MenuItemCreate(ctx, menuItem); err != nil {
+		t.Fatalf("Failed to create menu item: %v", err)
+	}
+
+	block := cmsstore.NewBlock().
+		SetSiteID(testutils.SITE_01).
+		SetType(cmsstore.BLOCK_TYPE_NAVBAR).
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := block.SetMeta(cmsstore.BLOCK_META_MENU_ID, menu.ID()); err != nil {
+		t.Fatalf("Failed to set meta: %v", err)
+	}
+
+	if err := storeA.BlockCreate(ctx, block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	defaultNavbar := cmsstore.DefaultBlockTypeRegistry().Get(cmsstore.BLOCK_TYPE_NAVBAR)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	frontendA := New(Config{Store: storeA, Logger: logger}).(*frontend)
+	New(Config{Store: storeB, Logger: logger})
+
+	if cmsstore.DefaultBlockTypeRegistry().Get(cmsstore.BLOCK_TYPE_NAVBAR) != defaultNavbar {
+		t.Error("Expected the default registry to be left untouched")
+	}
+
+	html, err := frontendA.renderContentToHtml(httptest.NewRequest("GET", "/", nil), "[[BLOCK_"+block.ID()+"]]", TemplateRenderHtmlByIDOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.Contains(html, "Store A Home") {
+		t.Errorf("Expected the navbar rendered from the store of the frontend, got: %s", html)
+	}
+}
@@ -0,0 +1,80 @@
+package frontend
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/dracory/cmsstore"
+	"github.com/spf13/cast"
+)
+
+// VISITOR_COOKIE is the cookie identifying the visitor, if set by the
+// application, for the percentage rollout of the blocks
+const VISITOR_COOKIE = "cms_visitor_id"
+
+// blockIsVisible returns true if the active block is displayed to the visitor
+//
+// Business Logic:
+//   - blocks without visibility rules are always displayed
+//   - blocks with invalid rules are not displayed, the error is logged
+//   - the logged in callback is only called for the rules requiring it
+//
+// Returns:
+//   - visible: true if the block is displayed
+//   - hasRules: true if the block has visibility rules, its content must not be cached
+func (frontend *frontend) blockIsVisible(ctx context.Context, block cmsstore.BlockInterface) (visible bool, hasRules bool) {
+	rules, err := cmsstore.BlockVisibilityRules(block)
+
+	if err != nil {
+		frontend.logger.Error("blockIsVisible: Invalid visibility rules", "blockID", block.ID(), "error", err)
+		return false, true
+	}
+
+	if rules.IsEmpty() {
+		return true, false
+	}
+
+	visitor := frontend.visitor(cmsstore.RequestFromContext(ctx), cast.ToString(ctx.Value(LanguageKey{})))
+
+	return rules.IsVisible(block.ID(), visitor), true
+}
+
+// visitor returns the visitor of the request, the visibility rules of the
+// blocks and the menu items are evaluated for. The logged in callback is
+// called at most once, and only for the rules requiring it.
+func (frontend *frontend) visitor(r *http.Request, language string) cmsstore.BlockVisitor {
+	visitor := cmsstore.BlockVisitor{
+		ID:       visitorID(r),
+		Language: language,
+		Request:  r,
+	}
+
+	if frontend.isLoggedIn != nil && r != nil {
+		visitor.LoggedInFunc = sync.OnceValue(func() bool {
+			return frontend.isLoggedIn(r)
+		})
+	}
+
+	return visitor
+}
+
+// visitorID returns the ID of the visitor, from the visitor cookie if set,
+// or else from the IP address and the User-Agent
+func visitorID(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+
+	if cookie, err := r.Cookie(VISITOR_COOKIE); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return ip + "|" + r.UserAgent()
+}
@@ -0,0 +1,151 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+// initVisibilityPage creates a page displaying a block with the visibility rules
+func initVisibilityPage(t *testing.T, rules cmsstore.BlockVisibility, isLoggedIn func(r *http.Request) bool) (*frontend, cmsstore.SiteInterface) {
+	t.Helper()
+
+	return initVisibilityPageWithReference(t, rules, isLoggedIn, func(blockID string) string {
+		return "[[BLOCK_" + blockID + "]]"
+	})
+}
+
+// initVisibilityPageWithReference creates a page displaying a block with the
+// visibility rules, the block referenced as returned by reference
+func initVisibilityPageWithReference(t *testing.T, rules cmsstore.BlockVisibility, isLoggedIn func(r *http.Request) bool, reference func(blockID string) string) (*frontend, cmsstore.SiteInterface) {
+	t.Helper()
+
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site := cmsstore.NewSite().
+		SetName("Visibility Site").
+		SetStatus(cmsstore.SITE_STATUS_ACTIVE)
+
+	if err := store.SiteCreate(context.Background(), site); err != nil {
+		t.Fatalf("Failed to create site: %v", err)
+	}
+
+	block := cmsstore.NewBlock().
+		SetSiteID(site.ID()).
+		SetType(cmsstore.BLOCK_TYPE_HTML).
+		SetContent("<p>Promotion</p>").
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := cmsstore.SetBlockVisibilityRules(block, rules); err != nil {
+		t.Fatalf("Failed to set visibility rules: %v", err)
+	}
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	page := cmsstore.NewPage().
+		SetSiteID(site.ID()).
+		SetAlias("home").
+		SetContent("<h1>Home</h1>" + reference(block.ID())).
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE)
+
+	if err := store.PageCreate(context.Background(), page); err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	f := New(Config{
+		Store:              store,
+		Logger:             slog.New(slog.NewTextHandler(io.Discard, nil)),
+		CacheEnabled:       true,
+		CacheExpireSeconds: 60,
+		IsLoggedIn:         isLoggedIn,
+	}).(*frontend)
+
+	return f, site
+}
+
+func TestBlockVisibility_LanguageAndQueryParam(t *testing.T) {
+	f, site := initVisibilityPage(t, cmsstore.BlockVisibility{
+		Languages:  []string{"fr"},
+		QueryParam: "promo",
+	}, nil)
+
+	tests := []struct {
+		url      string
+		language string
+		expected bool
+	}{
+		{"/home?promo=1", "fr", true},
+		{"/home", "fr", false},
+		{"/home?promo=1", "en", false},
+		{"/home?promo=1", "fr", true},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, test.url, nil)
+		html := f.PageRenderHtmlBySiteAndAlias(httptest.NewRecorder(), r, site.ID(), "home", test.language)
+
+		if !strings.Contains(html, "<h1>Home</h1>") {
+			t.Fatalf("Expected the page to be rendered, got %s", html)
+		}
+
+		if visible := strings.Contains(html, "Promotion"); visible != test.expected {
+			t.Errorf("%s (%s): expected visible %v, got %s", test.url, test.language, test.expected, html)
+		}
+	}
+}
+
+func TestBlockVisibility_LoggedInNotCached(t *testing.T) {
+	f, site := initVisibilityPage(t, cmsstore.BlockVisibility{
+		LoggedIn: cmsstore.BLOCK_VISIBILITY_LOGGED_IN_YES,
+	}, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") != ""
+	})
+
+	loggedIn := httptest.NewRequest(http.MethodGet, "/home", nil)
+	loggedIn.Header.Set("Authorization", "Bearer token")
+
+	html := f.PageRenderHtmlBySiteAndAlias(httptest.NewRecorder(), loggedIn, site.ID(), "home", "en")
+	if !strings.Contains(html, "Promotion") {
+		t.Errorf("Expected the block to be displayed to the logged in visitor, got %s", html)
+	}
+
+	html = f.PageRenderHtmlBySiteAndAlias(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/home", nil), site.ID(), "home", "en")
+	if strings.Contains(html, "Promotion") {
+		t.Errorf("Expected the block not to be displayed (nor cached) for the anonymous visitor, got %s", html)
+	}
+}
+
+func TestBlockVisibility_AttributeSyntax(t *testing.T) {
+	f, site := initVisibilityPageWithReference(t, cmsstore.BlockVisibility{
+		LoggedIn: cmsstore.BLOCK_VISIBILITY_LOGGED_IN_YES,
+	}, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") != ""
+	}, func(blockID string) string {
+		return `<block id="` + blockID + `" wrap="aside" />`
+	})
+
+	loggedIn := httptest.NewRequest(http.MethodGet, "/home", nil)
+	loggedIn.Header.Set("Authorization", "Bearer token")
+
+	html := f.PageRenderHtmlBySiteAndAlias(httptest.NewRecorder(), loggedIn, site.ID(), "home", "en")
+	if !strings.Contains(html, "<aside><p>Promotion</p></aside>") {
+		t.Errorf("Expected the block to be displayed to the logged in visitor, got %s", html)
+	}
+
+	html = f.PageRenderHtmlBySiteAndAlias(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/home", nil), site.ID(), "home", "en")
+	if strings.Contains(html, "Promotion") {
+		t.Errorf("Expected the block not to be displayed for the anonymous visitor, got %s", html)
+	}
+}
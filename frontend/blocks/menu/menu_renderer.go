@@ -2,6 +2,7 @@ package menu
 
 import (
 	"context"
+	htmlpkg "html"
 	"strings"
 
 	"github.com/dracory/cmsstore"
@@ -49,32 +50,11 @@ func (r *MenuRenderer) RenderMenuHTML(ctx context.Context, menuItems []cmsstore.
 	}
 }
 
-// getCurrentPath retrieves the current request path from context
-func getCurrentPath(ctx context.Context) string {
-	if req := cmsstore.RequestFromContext(ctx); req != nil {
-		return req.URL.Path
-	}
-	return ""
-}
-
-// isActiveItem checks if the menu item URL matches the current path
-func isActiveItem(itemURL, currentPath string) bool {
-	if itemURL == "" || currentPath == "" {
-		return false
-	}
-	// Normalize paths for comparison
-	itemURL = strings.TrimSuffix(itemURL, "/")
-	currentPath = strings.TrimSuffix(currentPath, "/")
-	return itemURL == currentPath
-}
-
-// hasActiveChild checks if any child in the tree is active
-func hasActiveChild(node *menuTreeNode, currentPath string) bool {
-	if isActiveItem(node.Item.URL(), currentPath) {
-		return true
-	}
+// hasActiveDescendant checks if any descendant in the tree is active,
+// see cmsstore.MenuItemIsActive
+func hasActiveDescendant(ctx context.Context, node *menuTreeNode) bool {
 	for _, child := range node.Children {
-		if hasActiveChild(child, currentPath) {
+		if cmsstore.MenuItemIsActive(ctx, child.Item) || hasActiveDescendant(ctx, child) {
 			return true
 		}
 	}
@@ -216,34 +196,64 @@ func (r *MenuRenderer) renderMenuBreadcrumb(ctx context.Context, tree []*menuTre
 
 // renderMenuItemHTML renders a single menu item with its children
 func (r *MenuRenderer) renderMenuItemHTML(ctx context.Context, node *menuTreeNode, renderChildren bool) string {
-	currentPath := getCurrentPath(ctx)
 	itemURL := r.resolveMenuItemURL(ctx, node.Item)
 	target := node.Item.Target()
 
-	// Determine if this item is active
-	isActive := isActiveItem(itemURL, currentPath)
-	hasActiveDescendant := hasActiveChild(node, currentPath)
+	// Determine if this item, or one of its descendants, is active
+	isActive := cmsstore.MenuItemIsActive(ctx, node.Item)
+	isActiveAncestor := !isActive && (cmsstore.MenuItemIsActiveAncestor(ctx, node.Item) || hasActiveDescendant(ctx, node))
 
-	html := `<li`
+	classes := []string{}
 	if isActive {
-		html += ` class="active"`
-	} else if hasActiveDescendant && !renderChildren {
-		// For horizontal/dropdown menus, highlight parent if child is active
-		html += ` class="active-parent"`
+		classes = append(classes, "active")
+	} else if isActiveAncestor {
+		classes = append(classes, "active-parent")
+	}
+	if node.Item.CSSClass() != "" {
+		classes = append(classes, node.Item.CSSClass())
+	}
+
+	html := `<li`
+	if len(classes) > 0 {
+		html += ` class="` + htmlpkg.EscapeString(strings.Join(classes, " ")) + `"`
+	}
+	if node.Item.Type() == cmsstore.MENU_ITEM_TYPE_SEPARATOR {
+		html += ` role="separator"`
 	}
 	html += `>`
 
-	if itemURL != "" {
+	label := node.Item.Name()
+	if node.Item.Icon() != "" {
+		label = `<i class="` + htmlpkg.EscapeString(node.Item.Icon()) + `" aria-hidden="true"></i> ` + label
+	}
+
+	switch {
+	case node.Item.Type() == cmsstore.MENU_ITEM_TYPE_SEPARATOR:
+		html += `<hr>`
+	case node.Item.Type() == cmsstore.MENU_ITEM_TYPE_HEADING:
+		html += `<span class="menu-heading">` + label + `</span>`
+	case node.Item.Type() == cmsstore.MENU_ITEM_TYPE_MEGA_MENU:
+		html += `<span class="menu-mega-toggle">` + label + `</span>`
+		html += `<div class="mega-menu">`
+		if node.Item.BlockID() != "" {
+			// The frontend renders the nested block placeholder
+			html += `[[BLOCK_` + htmlpkg.EscapeString(node.Item.BlockID()) + `]]`
+		}
+		html += `</div>`
+	case itemURL != "":
 		html += `<a href="` + itemURL + `"`
 		if target != "" {
 			html += ` target="` + target + `"`
 		}
+		if node.Item.Rel() != "" {
+			html += ` rel="` + htmlpkg.EscapeString(node.Item.Rel()) + `"`
+		}
 		if isActive {
-			html += ` class="active"`
+			html += ` class="active" aria-current="page"`
 		}
-		html += `>` + node.Item.Name() + `</a>`
-	} else {
-		html += node.Item.Name()
+		html += `>` + label + `</a>`
+	default:
+		html += label
 	}
 
 	if renderChildren && len(node.Children) > 0 {
@@ -10,60 +10,13 @@ import (
 	"github.com/dromara/carbon/v2"
 )
 
-// TestGetCurrentPath verifies that getCurrentPath extracts the path from request context
-func TestGetCurrentPath(t *testing.T) {
-	// Test with request in context
-	req, _ := http.NewRequest("GET", "/about?q=test", nil)
-	ctx := cmsstore.RequestToContext(context.Background(), req)
-
-	path := getCurrentPath(ctx)
-	if path != "/about" {
-		t.Errorf("Expected path '/about', got: %s", path)
-	}
-
-	// Test without request in context
-	ctx2 := context.Background()
-	path2 := getCurrentPath(ctx2)
-	if path2 != "" {
-		t.Errorf("Expected empty path when no request, got: %s", path2)
-	}
-}
-
-// TestIsActiveItem verifies the active item detection logic
-func TestIsActiveItem(t *testing.T) {
-	tests := []struct {
-		name        string
-		itemURL     string
-		currentPath string
-		expected    bool
-	}{
-		{"exact match", "/about", "/about", true},
-		{"trailing slash on item", "/about/", "/about", true},
-		{"trailing slash on current", "/about", "/about/", true},
-		{"different paths", "/about", "/contact", false},
-		{"root match", "/", "/", true},
-		{"empty item URL", "", "/about", false},
-		{"empty current path", "/about", "", false},
-		{"both empty", "", "", false},
-		{"partial match", "/about", "/about-us", false},
-		{"subpath", "/about", "/about/team", false},
-	}
+// TestHasActiveDescendant verifies the recursive active descendant detection
+func TestHasActiveDescendant(t *testing.T) {
+	page := cmsstore.NewPage()
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			result := isActiveItem(test.itemURL, test.currentPath)
-			if result != test.expected {
-				t.Errorf("isActiveItem(%q, %q) = %v, want %v", test.itemURL, test.currentPath, result, test.expected)
-			}
-		})
-	}
-}
-
-// TestHasActiveChild verifies the recursive active child detection
-func TestHasActiveChild(t *testing.T) {
 	// Create a simple tree: Parent -> Child -> Grandchild
 	grandchild := &menuTreeNode{
-		Item: &mockMenuItem{id: "3", url: "/about/team"},
+		Item: &mockMenuItem{id: "3", url: "/about/team", pageID: page.ID()},
 	}
 	child := &menuTreeNode{
 		Item:     &mockMenuItem{id: "2", url: "/about"},
@@ -74,24 +27,22 @@ func TestHasActiveChild(t *testing.T) {
 		Children: []*menuTreeNode{child},
 	}
 
-	// Test: grandchild URL matches
-	if !hasActiveChild(parent, "/about/team") {
+	ctx := cmsstore.PageToContext(context.Background(), page)
+
+	if !hasActiveDescendant(ctx, parent) {
 		t.Error("Expected parent to have active grandchild")
 	}
 
-	// Test: child URL matches
-	if !hasActiveChild(parent, "/about") {
-		t.Error("Expected parent to have active child")
+	if !hasActiveDescendant(ctx, child) {
+		t.Error("Expected child to have active child")
 	}
 
-	// Test: parent URL matches
-	if !hasActiveChild(parent, "/") {
-		t.Error("Expected parent to be active")
+	if hasActiveDescendant(ctx, grandchild) {
+		t.Error("Expected the active item to have no active descendant")
 	}
 
-	// Test: no match
-	if hasActiveChild(parent, "/contact") {
-		t.Error("Expected no active items for /contact")
+	if hasActiveDescendant(context.Background(), parent) {
+		t.Error("Expected no active items without page")
 	}
 }
 
@@ -115,7 +66,7 @@ func TestRenderMenuItemHTML_ActiveState(t *testing.T) {
 	}
 
 	// Check a has active class
-	if !strings.Contains(html, `<a href="/about" class="active">About</a>`) {
+	if !strings.Contains(html, `<a href="/about" class="active" aria-current="page">About</a>`) {
 		t.Errorf("Expected a to have 'active' class. HTML: %s", html)
 	}
 }
@@ -171,6 +122,40 @@ func TestRenderMenuItemHTML_ActiveParent(t *testing.T) {
 	}
 }
 
+// TestRenderMenuItemHTML_ActivePage verifies the items are active by page ID,
+// whatever the request path, and the items of the ancestor pages are marked
+func TestRenderMenuItemHTML_ActivePage(t *testing.T) {
+	renderer := &MenuRenderer{}
+
+	about := cmsstore.NewPage()
+	team := cmsstore.NewPage().SetParentID(about.ID())
+
+	aboutNode := &menuTreeNode{
+		Item: &mockMenuItem{id: "1", name: "About", url: "/about", pageID: about.ID()},
+	}
+	teamNode := &menuTreeNode{
+		Item: &mockMenuItem{id: "2", name: "Team", url: "/about/team", pageID: team.ID()},
+	}
+
+	// The request path has a language prefix and a query string
+	req, _ := http.NewRequest("GET", "/fr/about/team?tab=1", nil)
+	ctx := cmsstore.RequestToContext(context.Background(), req)
+	ctx = cmsstore.PageToContext(ctx, team)
+	ctx = cmsstore.PageAncestorsToContext(ctx, []cmsstore.PageInterface{about})
+
+	html := renderer.renderMenuItemHTML(ctx, teamNode, false)
+
+	if !strings.Contains(html, `<a href="/about/team" class="active" aria-current="page">Team</a>`) {
+		t.Errorf("Expected the page item to be active. HTML: %s", html)
+	}
+
+	html = renderer.renderMenuItemHTML(ctx, aboutNode, true)
+
+	if !strings.Contains(html, `<li class="active-parent">`) || strings.Contains(html, `aria-current`) {
+		t.Errorf("Expected the ancestor page item to be an active parent. HTML: %s", html)
+	}
+}
+
 // mockMenuItem is a test implementation of MenuItemInterface
 type mockMenuItem struct {
 	id        string
@@ -190,11 +175,39 @@ type mockMenuItem struct {
 }
 
 // Core methods used by renderer
-func (m *mockMenuItem) ID() string       { return m.id }
-func (m *mockMenuItem) Name() string     { return m.name }
-func (m *mockMenuItem) URL() string      { return m.url }
-func (m *mockMenuItem) ParentID() string { return m.parentID }
-func (m *mockMenuItem) Target() string   { return m.target }
+func (m *mockMenuItem) ID() string                                                       { return m.id }
+func (m *mockMenuItem) Name() string                                                     { return m.name }
+func (m *mockMenuItem) URL() string                                                      { return m.url }
+func (m *mockMenuItem) ParentID() string                                                 { return m.parentID }
+func (m *mockMenuItem) Target() string                                                   { return m.target }
+func (m *mockMenuItem) Anchor() string                                                   { return m.Meta(cmsstore.MENU_ITEM_META_ANCHOR) }
+func (m *mockMenuItem) SetAnchor(anchor string) cmsstore.MenuItemInterface               { return m }
+func (m *mockMenuItem) BlockID() string                                                  { return m.Meta(cmsstore.MENU_ITEM_META_BLOCK_ID) }
+func (m *mockMenuItem) SetBlockID(blockID string) cmsstore.MenuItemInterface             { return m }
+func (m *mockMenuItem) CSSClass() string                                                 { return m.Meta(cmsstore.MENU_ITEM_META_CSS_CLASS) }
+func (m *mockMenuItem) SetCSSClass(cssClass string) cmsstore.MenuItemInterface           { return m }
+func (m *mockMenuItem) EntityID() string                                                 { return m.Meta(cmsstore.MENU_ITEM_META_ENTITY_ID) }
+func (m *mockMenuItem) SetEntityID(entityID string) cmsstore.MenuItemInterface           { return m }
+func (m *mockMenuItem) Icon() string                                                     { return m.Meta(cmsstore.MENU_ITEM_META_ICON) }
+func (m *mockMenuItem) SetIcon(icon string) cmsstore.MenuItemInterface                   { return m }
+func (m *mockMenuItem) Label(language string) string                                     { return m.Name() }
+func (m *mockMenuItem) Labels() map[string]string                                        { return map[string]string{} }
+func (m *mockMenuItem) SetLabel(language, label string) cmsstore.MenuItemInterface       { return m }
+func (m *mockMenuItem) TranslatedURL(language string) string                             { return m.url }
+func (m *mockMenuItem) TranslatedURLs() map[string]string                                { return map[string]string{} }
+func (m *mockMenuItem) SetTranslatedURL(language, url string) cmsstore.MenuItemInterface { return m }
+func (m *mockMenuItem) Rel() string                                                      { return m.Meta(cmsstore.MENU_ITEM_META_REL) }
+func (m *mockMenuItem) SetRel(rel string) cmsstore.MenuItemInterface                     { return m }
+func (m *mockMenuItem) SetType(menuItemType string) cmsstore.MenuItemInterface           { return m }
+func (m *mockMenuItem) Type() string {
+	if menuItemType := m.Meta(cmsstore.MENU_ITEM_META_TYPE); menuItemType != "" {
+		return menuItemType
+	}
+	if m.PageID() != "" {
+		return cmsstore.MENU_ITEM_TYPE_PAGE
+	}
+	return cmsstore.MENU_ITEM_TYPE_URL
+}
 
 // Setters
 func (m *mockMenuItem) SetID(id string) cmsstore.MenuItemInterface     { m.id = id; return m }
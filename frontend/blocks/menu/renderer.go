@@ -16,7 +16,7 @@ type BlockRenderer struct {
 // FrontendStore interface for store operations needed by menu renderer
 type FrontendStore interface {
 	MenuFindByID(ctx context.Context, id string) (cmsstore.MenuInterface, error)
-	MenuItemList(ctx context.Context, query cmsstore.MenuItemQueryInterface) ([]cmsstore.MenuItemInterface, error)
+	MenuItemsResolve(ctx context.Context, menuID string) ([]cmsstore.MenuItemInterface, error)
 	MenusEnabled() bool
 	PageFindByID(ctx context.Context, id string) (cmsstore.PageInterface, error)
 	Logger() *slog.Logger
@@ -63,11 +63,7 @@ func (r *BlockRenderer) Render(ctx context.Context, block cmsstore.BlockInterfac
 		return "<!-- Menu not active -->", nil
 	}
 
-	menuItems, err := r.store.MenuItemList(ctx, cmsstore.MenuItemQuery().
-		SetMenuID(menuID).
-		SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE).
-		SetOrderBy(cmsstore.COLUMN_SEQUENCE).
-		SetSortOrder("asc"))
+	menuItems, err := r.store.MenuItemsResolve(ctx, menuID)
 
 	if err != nil {
 		r.store.Logger().Error("renderMenuBlock: Error listing menu items", "menuID", menuID, "error", err)
@@ -0,0 +1,336 @@
+package frontend
+
+import (
+	"regexp"
+	"strings"
+)
+
+// contentNodeKind is the kind of a node in parsed CMS markup
+type contentNodeKind int
+
+const (
+	// contentNodeText is plain text (HTML), written as is
+	contentNodeText contentNodeKind = iota
+
+	// contentNodePlaceholder is a [[Name]] or [[Name|modifier]] placeholder,
+	// resolved from the standard placeholders and the custom variables
+	contentNodePlaceholder
+
+	// contentNodeBlock is a [[BLOCK_id]] reference
+	contentNodeBlock
+
+	// contentNodeBlockTag is a <block id="..." /> or [[block id='...']] reference
+	contentNodeBlockTag
+
+	// contentNodePageURL is a [[PAGE_URL_id]] reference
+	contentNodePageURL
+
+	// contentNodeTranslation is a [[TRANSLATION_id]] reference
+	contentNodeTranslation
+
+	// contentNodeTranslationTag is a <translation id="..." /> or
+	// [[translation id='...']] reference
+	contentNodeTranslationTag
+
+	// contentNodeShortcode is a <alias attr="value">content</alias> shortcode
+	contentNodeShortcode
+)
+
+// contentNode is a node in parsed CMS markup
+type contentNode struct {
+	kind contentNodeKind
+
+	// raw is the source text of the node, written as is when the node
+	// cannot be resolved
+	raw string
+
+	// name is the placeholder name, the referenced ID or the shortcode alias
+	name string
+
+	// modifier is the placeholder escaping modifier, i.e. attr in [[PageTitle|attr]]
+	modifier string
+
+	// attrs are the attributes of tags and shortcodes
+	attrs map[string]string
+
+	// children is the parsed content of a shortcode
+	children []*contentNode
+}
+
+// Package-level compiled regex for performance
+// Matches the ID of [[BLOCK_id]], [[PAGE_URL_id]] and [[TRANSLATION_id]]
+var contentReferenceID = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Matches the placeholder escaping modifier, i.e. attr in [[PageTitle|attr]]
+var contentModifier = regexp.MustCompile(`^[a-z]+$`)
+
+// Matches the shortcode arguments, same as the shortcode package
+var shortcodeArgsPattern = regexp.MustCompile(`\s*([^=]+)="([^"]+)"`)
+
+// parseContent parses the CMS markup in the content into a list of nodes,
+// in a single left to right scan
+//
+// Supported syntax:
+//   - [[BLOCK_id]], [[PAGE_URL_id]], [[TRANSLATION_id]]
+//   - <block id="..." />, [[block id='...']]
+//   - <translation id="..." />, [[translation id='...']]
+//   - [[Name]], [[ Name ]], [[Name|modifier]]
+//   - <alias attr="value">content</alias> for the given shortcode aliases,
+//     the shortcode content is parsed as children, same alias shortcodes
+//     may be nested
+//
+// Anything else, including malformed directives, is kept as text.
+//
+// Parameters:
+// - content: the content to parse
+// - shortcodeAliases: the aliases of the registered shortcodes
+//
+// Returns:
+// - nodes: the parsed nodes
+func parseContent(content string, shortcodeAliases []string) []*contentNode {
+	nodes := []*contentNode{}
+
+	var text strings.Builder
+
+	flushText := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, &contentNode{kind: contentNodeText, raw: text.String()})
+			text.Reset()
+		}
+	}
+
+	pos := 0
+
+	for pos < len(content) {
+		next := strings.IndexAny(content[pos:], "[<")
+
+		if next < 0 {
+			text.WriteString(content[pos:])
+			break
+		}
+
+		text.WriteString(content[pos : pos+next])
+		pos += next
+
+		var node *contentNode
+		end := 0
+
+		if content[pos] == '[' {
+			node, end = parseContentBrackets(content, pos)
+		} else {
+			node, end = parseContentTag(content, pos, shortcodeAliases)
+		}
+
+		if node == nil {
+			text.WriteByte(content[pos])
+			pos++
+			continue
+		}
+
+		if node.kind == contentNodeShortcode {
+			inner := node.raw[strings.Index(node.raw, ">")+1 : len(node.raw)-len("</"+node.name+">")]
+			node.children = parseContent(inner, shortcodeAliases)
+		}
+
+		flushText()
+		nodes = append(nodes, node)
+		pos = end
+	}
+
+	flushText()
+
+	return nodes
+}
+
+// parseContentBrackets parses the [[...]] directive starting at pos
+//
+// Returns the node and the position after it, or nil if there is no
+// valid directive at pos.
+func parseContentBrackets(content string, pos int) (*contentNode, int) {
+	if !strings.HasPrefix(content[pos:], "[[") {
+		return nil, 0
+	}
+
+	closing := strings.Index(content[pos+2:], "]]")
+
+	if closing < 0 {
+		return nil, 0
+	}
+
+	end := pos + 2 + closing + 2
+	raw := content[pos:end]
+	inner := content[pos+2 : end-2]
+
+	// i.e. "[[[[Name]]", the directive starts at a later bracket
+	if strings.ContainsAny(inner, "[]") {
+		return nil, 0
+	}
+
+	trimmed := strings.TrimSpace(inner)
+
+	if trimmed == "" {
+		return nil, 0
+	}
+
+	if attrString, ok := strings.CutPrefix(inner, "block"); ok && startsWithSpace(attrString) {
+		return &contentNode{kind: contentNodeBlockTag, raw: raw, attrs: parseAttributes(strings.TrimSpace(attrString))}, end
+	}
+
+	if attrString, ok := strings.CutPrefix(inner, "translation"); ok && startsWithSpace(attrString) {
+		return &contentNode{kind: contentNodeTranslationTag, raw: raw, attrs: parseAttributes(strings.TrimSpace(attrString))}, end
+	}
+
+	references := []struct {
+		prefix string
+		kind   contentNodeKind
+	}{
+		{"BLOCK_", contentNodeBlock},
+		{"PAGE_URL_", contentNodePageURL},
+		{"TRANSLATION_", contentNodeTranslation},
+	}
+
+	for _, reference := range references {
+		if id, ok := strings.CutPrefix(trimmed, reference.prefix); ok {
+			if !contentReferenceID.MatchString(id) {
+				return nil, 0
+			}
+
+			return &contentNode{kind: reference.kind, raw: raw, name: id}, end
+		}
+	}
+
+	name, modifier, hasModifier := strings.Cut(trimmed, "|")
+	name = strings.TrimSpace(name)
+	modifier = strings.TrimSpace(modifier)
+
+	if name == "" || (hasModifier && !contentModifier.MatchString(modifier)) {
+		return nil, 0
+	}
+
+	return &contentNode{kind: contentNodePlaceholder, raw: raw, name: name, modifier: modifier}, end
+}
+
+// parseContentTag parses the <block />, <translation /> or shortcode tag
+// starting at pos
+//
+// Returns the node and the position after it, or nil if there is no
+// valid tag at pos.
+func parseContentTag(content string, pos int, shortcodeAliases []string) (*contentNode, int) {
+	rest := content[pos+1:]
+
+	for _, tag := range []struct {
+		name string
+		kind contentNodeKind
+	}{
+		{"block", contentNodeBlockTag},
+		{"translation", contentNodeTranslationTag},
+	} {
+		attrString, ok := strings.CutPrefix(rest, tag.name)
+
+		if !ok || !startsWithSpace(attrString) {
+			continue
+		}
+
+		closing := strings.Index(attrString, ">")
+
+		if closing < 0 || !strings.HasSuffix(attrString[:closing], "/") {
+			return nil, 0
+		}
+
+		end := pos + 1 + len(tag.name) + closing + 1
+		attrString = strings.TrimSuffix(attrString[:closing], "/")
+
+		return &contentNode{kind: tag.kind, raw: content[pos:end], attrs: parseAttributes(strings.TrimSpace(attrString))}, end
+	}
+
+	for _, alias := range shortcodeAliases {
+		if node, end := parseContentShortcode(content, pos, alias); node != nil {
+			return node, end
+		}
+	}
+
+	return nil, 0
+}
+
+// parseContentShortcode parses the <alias ...>...</alias> shortcode
+// starting at pos, nested shortcodes with the same alias are matched
+// with their own closing tags
+func parseContentShortcode(content string, pos int, alias string) (*contentNode, int) {
+	if alias == "" {
+		return nil, 0
+	}
+
+	openTagEnd, ok := shortcodeOpenTagEnd(content, pos, alias)
+
+	if !ok {
+		return nil, 0
+	}
+
+	closeTag := "</" + alias + ">"
+	depth := 1
+	cursor := openTagEnd
+
+	for depth > 0 {
+		next := strings.Index(content[cursor:], "<")
+
+		if next < 0 {
+			return nil, 0 // not closed
+		}
+
+		cursor += next
+
+		if strings.HasPrefix(content[cursor:], closeTag) {
+			depth--
+			cursor += len(closeTag)
+			continue
+		}
+
+		if end, ok := shortcodeOpenTagEnd(content, cursor, alias); ok {
+			depth++
+			cursor = end
+			continue
+		}
+
+		cursor++
+	}
+
+	attrs := map[string]string{}
+
+	for _, match := range shortcodeArgsPattern.FindAllStringSubmatch(content[pos+1+len(alias):openTagEnd-1], -1) {
+		attrs[match[1]] = match[2]
+	}
+
+	return &contentNode{
+		kind:  contentNodeShortcode,
+		raw:   content[pos:cursor],
+		name:  alias,
+		attrs: attrs,
+	}, cursor
+}
+
+// shortcodeOpenTagEnd returns the position after the <alias ...> opening
+// tag starting at pos, and false if there is no such tag
+func shortcodeOpenTagEnd(content string, pos int, alias string) (int, bool) {
+	attrString, ok := strings.CutPrefix(content[pos:], "<"+alias)
+
+	if !ok {
+		return 0, false
+	}
+
+	closing := strings.Index(attrString, ">")
+
+	if closing < 0 {
+		return 0, false
+	}
+
+	if closing > 0 && !startsWithSpace(attrString) {
+		return 0, false // i.e. <bolder> for alias bold
+	}
+
+	return pos + 1 + len(alias) + closing + 1, true
+}
+
+// startsWithSpace returns true if the string starts with a whitespace
+func startsWithSpace(s string) bool {
+	return s != "" && strings.ContainsRune(" \t\r\n", rune(s[0]))
+}
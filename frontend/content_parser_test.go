@@ -0,0 +1,131 @@
+package frontend
+
+import (
+	"testing"
+)
+
+func TestParseContent_Directives(t *testing.T) {
+	content := `<p>[[PageTitle]] [[ PageTitle|attr ]]</p>[[BLOCK_b1]][[ BLOCK_b2 ]]` +
+		`<a href="[[PAGE_URL_p1]]">[[TRANSLATION_hello]]</a>` +
+		`<block id="b3" wrap="div" />[[block id='b4']]` +
+		`<translation id="t1" name="John" />[[translation id='t2']]`
+
+	nodes := parseContent(content, nil)
+
+	expected := []struct {
+		kind     contentNodeKind
+		name     string
+		modifier string
+		attrID   string
+	}{
+		{contentNodeText, "", "", ""},
+		{contentNodePlaceholder, "PageTitle", "", ""},
+		{contentNodeText, "", "", ""},
+		{contentNodePlaceholder, "PageTitle", "attr", ""},
+		{contentNodeText, "", "", ""},
+		{contentNodeBlock, "b1", "", ""},
+		{contentNodeBlock, "b2", "", ""},
+		{contentNodeText, "", "", ""},
+		{contentNodePageURL, "p1", "", ""},
+		{contentNodeText, "", "", ""},
+		{contentNodeTranslation, "hello", "", ""},
+		{contentNodeText, "", "", ""},
+		{contentNodeBlockTag, "", "", "b3"},
+		{contentNodeBlockTag, "", "", "b4"},
+		{contentNodeTranslationTag, "", "", "t1"},
+		{contentNodeTranslationTag, "", "", "t2"},
+	}
+
+	if len(nodes) != len(expected) {
+		t.Fatalf("Expected %d nodes, got %d", len(expected), len(nodes))
+	}
+
+	for i, e := range expected {
+		node := nodes[i]
+
+		if node.kind != e.kind || node.name != e.name || node.modifier != e.modifier || node.attrs["id"] != e.attrID {
+			t.Errorf("Node %d: expected %+v, got kind=%d name=%q modifier=%q attrs=%v", i, e, node.kind, node.name, node.modifier, node.attrs)
+		}
+	}
+
+	if nodes[12].attrs["wrap"] != "div" {
+		t.Errorf("Expected wrap attribute, got %v", nodes[12].attrs)
+	}
+}
+
+func TestParseContent_MalformedKeptAsText(t *testing.T) {
+	contents := []string{
+		`[[`,
+		`[[]]`,
+		`[[ unclosed`,
+		`[[BLOCK_bad id]]`,
+		`[[PageTitle|Bad]]`,
+		`<block id="x">`,
+		`<blocks id="x" />`,
+		`<div>[plain] <b>bold</b></div>`,
+	}
+
+	for _, content := range contents {
+		nodes := parseContent(content, nil)
+
+		if len(nodes) != 1 || nodes[0].kind != contentNodeText || nodes[0].raw != content {
+			t.Errorf("Expected %q to be kept as a single text node, got %d nodes", content, len(nodes))
+		}
+	}
+}
+
+func TestParseContent_NestedBrackets(t *testing.T) {
+	nodes := parseContent(`[[[[PageTitle]]]]`, nil)
+
+	if len(nodes) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d", len(nodes))
+	}
+
+	if nodes[0].raw != "[[" || nodes[1].kind != contentNodePlaceholder || nodes[2].raw != "]]" {
+		t.Errorf("Unexpected nodes: %q %q %q", nodes[0].raw, nodes[1].raw, nodes[2].raw)
+	}
+}
+
+func TestParseContent_Shortcodes(t *testing.T) {
+	content := `<box color="red">A <box>[[PageTitle]]</box> B</box><boxed>x</boxed><bold>y</bold>`
+
+	nodes := parseContent(content, []string{"box", "bold"})
+
+	if len(nodes) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d", len(nodes))
+	}
+
+	outer := nodes[0]
+
+	if outer.kind != contentNodeShortcode || outer.name != "box" || outer.attrs["color"] != "red" {
+		t.Fatalf("Unexpected outer shortcode: %+v", outer)
+	}
+
+	if outer.raw != `<box color="red">A <box>[[PageTitle]]</box> B</box>` {
+		t.Errorf("Expected the outer shortcode to end at its own closing tag, got %q", outer.raw)
+	}
+
+	if len(outer.children) != 3 || outer.children[1].kind != contentNodeShortcode {
+		t.Fatalf("Expected nested shortcode as second child, got %d children", len(outer.children))
+	}
+
+	if inner := outer.children[1]; len(inner.children) != 1 || inner.children[0].kind != contentNodePlaceholder {
+		t.Errorf("Expected placeholder in the nested shortcode")
+	}
+
+	if nodes[1].kind != contentNodeText || nodes[1].raw != `<boxed>x</boxed>` {
+		t.Errorf("Expected <boxed> to be text, got %q", nodes[1].raw)
+	}
+
+	if nodes[2].kind != contentNodeShortcode || nodes[2].name != "bold" {
+		t.Errorf("Expected bold shortcode, got %+v", nodes[2])
+	}
+}
+
+func TestParseContent_UnclosedShortcode(t *testing.T) {
+	nodes := parseContent(`<box>never closed`, []string{"box"})
+
+	if len(nodes) != 1 || nodes[0].kind != contentNodeText {
+		t.Errorf("Expected unclosed shortcode to be kept as text")
+	}
+}
@@ -0,0 +1,409 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/dracory/cmsstore"
+	"github.com/samber/lo"
+)
+
+// contentRenderMaxDepth is the maximum nesting of rendered output (blocks
+// and shortcodes outputting CMS markup) which is parsed for further
+// directives. Deeper output is inserted as is.
+const contentRenderMaxDepth = 10
+
+// contentRenderer resolves parsed CMS markup (see parseContent) to HTML
+//
+// Rendering is done in two walks over the same parsed tree:
+// 1. the blocks are rendered in document order (template first, then
+// page content), so blocks can set custom variables before any
+// placeholder is resolved
+// 2. the tree is written out, resolving the placeholders, page URLs,
+// translations and shortcodes
+//
+// The output of blocks and shortcodes is parsed and resolved the same way,
+// up to contentRenderMaxDepth levels deep. Placeholder values are never
+// parsed, they are escaped and inserted as is.
+type contentRenderer struct {
+	frontend *frontend
+
+	// request is the HTTP request, with the render context
+	request *http.Request
+
+	// language is the language code used for translations
+	language string
+
+	// defaultEscaping is the escaping of placeholders without a modifier
+	defaultEscaping string
+
+	// siteID is the ID of the site being rendered, for [[PageJsonLd]]
+	// and [[PageOpenGraph]]
+	siteID string
+
+	// placeholders are the standard placeholders, without PageContent
+	placeholders map[string]string
+
+	// pageContent is the parsed page content, inserted by [[PageContent]]
+	pageContent []*contentNode
+
+	shortcodeAliases []string
+	shortcodes       map[string]cmsstore.ShortcodeInterface
+
+	// rendered holds the rendered blocks, by node
+	rendered map[*contentNode]renderedBlock
+}
+
+// renderedBlock is a block rendered by the content renderer
+type renderedBlock struct {
+	// ctx is the context the block was rendered with, its output
+	// is resolved with the same context
+	ctx context.Context
+
+	// output is the parsed output of the block
+	output []*contentNode
+}
+
+// newContentRenderer creates a content renderer for a single rendering
+//
+// Parameters:
+// - r: the HTTP request, its context must hold the request and the vars context
+// - options: the options for the rendering
+// - defaultEscaping: the escaping of placeholders without a modifier
+func (frontend *frontend) newContentRenderer(r *http.Request, options TemplateRenderHtmlByIDOptions, defaultEscaping string) *contentRenderer {
+	renderer := &contentRenderer{
+		frontend:        frontend,
+		request:         r,
+		language:        lo.If(options.Language == "", "en").Else(options.Language),
+		defaultEscaping: defaultEscaping,
+		siteID:          options.SiteID,
+		placeholders: map[string]string{
+			"PageCanonicalUrl":    options.PageCanonicalURL,
+			"PageMetaDescription": options.PageMetaDescription,
+			"PageMetaKeywords":    options.PageMetaKeywords,
+			"PageRobots":          options.PageMetaRobots,
+			"PageTitle":           options.PageTitle,
+		},
+		shortcodes: map[string]cmsstore.ShortcodeInterface{},
+		rendered:   map[*contentNode]renderedBlock{},
+	}
+
+	shortcodes := []cmsstore.ShortcodeInterface{}
+	shortcodes = append(shortcodes, frontend.store.Shortcodes()...)
+	shortcodes = append(shortcodes, frontend.shortcodes...)
+
+	for _, shortcode := range shortcodes {
+		if _, exists := renderer.shortcodes[shortcode.Alias()]; exists {
+			continue // the first registered shortcode wins
+		}
+
+		renderer.shortcodes[shortcode.Alias()] = shortcode
+		renderer.shortcodeAliases = append(renderer.shortcodeAliases, shortcode.Alias())
+	}
+
+	renderer.pageContent = parseContent(options.PageContent, renderer.shortcodeAliases)
+
+	return renderer
+}
+
+// render renders the content to HTML
+func (renderer *contentRenderer) render(content string) (string, error) {
+	ctx := renderer.request.Context()
+	nodes := parseContent(content, renderer.shortcodeAliases)
+
+	// Render blocks FIRST so they can set custom variables
+	// This allows variables to "bubble up" from blocks to the page/template level
+	if err := renderer.renderBlocks(ctx, nodes, 0); err != nil {
+		return "", err
+	}
+
+	if err := renderer.renderBlocks(ctx, renderer.pageContent, 0); err != nil {
+		return "", err
+	}
+
+	// Resolve any custom variables within the standard placeholder values,
+	// these are escaped as a whole when inserted
+	if vars := cmsstore.VarsFromContext(ctx); vars != nil {
+		for key, value := range renderer.placeholders {
+			renderer.placeholders[key] = replacePlaceholders(value, vars.All(), cmsstore.PLACEHOLDER_ESCAPING_RAW)
+		}
+	}
+
+	var sb strings.Builder
+
+	if err := renderer.write(ctx, &sb, nodes, 0, false); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// renderBlocks renders the blocks in the nodes, and in their output,
+// in document order
+func (renderer *contentRenderer) renderBlocks(ctx context.Context, nodes []*contentNode, depth int) error {
+	for _, node := range nodes {
+		switch node.kind {
+		case contentNodeBlock, contentNodeBlockTag:
+			if _, err := renderer.renderBlock(ctx, node, depth); err != nil {
+				return err
+			}
+		case contentNodeShortcode:
+			if err := renderer.renderBlocks(ctx, node.children, depth); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// renderBlock renders the block referenced by the node, once
+//
+// The block is pushed on the render stack of the context (see enterBlock),
+// a block found in a cycle or beyond the maximum depth is not rendered.
+func (renderer *contentRenderer) renderBlock(ctx context.Context, node *contentNode, depth int) (renderedBlock, error) {
+	if block, exists := renderer.rendered[node]; exists {
+		return block, nil
+	}
+
+	blockID := lo.If(node.kind == contentNodeBlockTag, node.attrs["id"]).Else(node.name)
+
+	if blockID != "" {
+		blockCtx, err := renderer.frontend.enterBlock(ctx, blockID)
+
+		if stackErr, ok := err.(*blockRenderStackError); ok {
+			block := renderedBlock{
+				ctx:    ctx,
+				output: []*contentNode{{kind: contentNodeText, raw: renderer.frontend.blockRenderStackErrorHtml(stackErr)}},
+			}
+
+			renderer.rendered[node] = block
+
+			return block, nil
+		}
+
+		ctx = blockCtx
+	}
+
+	html := ""
+
+	if node.kind == contentNodeBlockTag {
+		html = renderer.frontend.renderBlockTag(ctx, node.raw, node.attrs)
+	} else {
+		var err error
+		html, err = renderer.frontend.fetchBlockContent(ctx, node.name)
+
+		if err != nil {
+			return renderedBlock{}, err
+		}
+	}
+
+	block := renderedBlock{
+		ctx:    ctx,
+		output: renderer.parseOutput(html, depth+1),
+	}
+
+	renderer.rendered[node] = block
+
+	return block, renderer.renderBlocks(ctx, block.output, depth+1)
+}
+
+// parseOutput parses the output of a block or a shortcode, unless
+// the maximum depth is reached
+func (renderer *contentRenderer) parseOutput(html string, depth int) []*contentNode {
+	if depth < contentRenderMaxDepth {
+		return parseContent(html, renderer.shortcodeAliases)
+	}
+
+	if renderer.frontend.logger != nil {
+		renderer.frontend.logger.Warn("Content rendering: maximum depth reached, output inserted as is", "depth", depth)
+	}
+
+	return []*contentNode{{kind: contentNodeText, raw: html}}
+}
+
+// write writes the resolved nodes to the builder
+//
+// Parameters:
+// - ctx: the context, with the render stack of the nodes
+// - sb: the builder to write to
+// - nodes: the nodes to write
+// - depth: the nesting depth of the nodes
+// - inPageContent: whether the nodes are (part of) the page content, where
+// [[PageContent]] is not resolved
+func (renderer *contentRenderer) write(ctx context.Context, sb *strings.Builder, nodes []*contentNode, depth int, inPageContent bool) error {
+	for _, node := range nodes {
+		switch node.kind {
+		case contentNodePlaceholder:
+			value, err := renderer.placeholder(node, depth, inPageContent)
+
+			if err != nil {
+				return err
+			}
+
+			sb.WriteString(value)
+
+		case contentNodeBlock, contentNodeBlockTag:
+			block, err := renderer.renderBlock(ctx, node, depth)
+
+			if err != nil {
+				return err
+			}
+
+			if err := renderer.write(block.ctx, sb, block.output, depth+1, inPageContent); err != nil {
+				return err
+			}
+
+		case contentNodePageURL:
+			pageURL, err := renderer.frontend.fetchPageURL(ctx, node.name)
+
+			if err != nil {
+				return err
+			}
+
+			sb.WriteString(pageURL)
+
+		case contentNodeTranslation:
+			text, err := renderer.frontend.fetchTranslationText(ctx, node.name, renderer.language)
+
+			if err != nil {
+				return err
+			}
+
+			sb.WriteString(text)
+
+		case contentNodeTranslationTag:
+			sb.WriteString(renderer.frontend.renderTranslationTag(ctx, node.raw, node.attrs, renderer.language))
+
+		case contentNodeShortcode:
+			if err := renderer.writeShortcode(ctx, sb, node, depth, inPageContent); err != nil {
+				return err
+			}
+
+		default:
+			sb.WriteString(node.raw)
+		}
+	}
+
+	return nil
+}
+
+// placeholder returns the escaped value of the placeholder
+//
+// Business Logic:
+//   - [[PageContent]] is the rendered page content, inserted raw unless
+//     a modifier is given, and left untouched within the page content
+//   - [[PageJsonLd]] and [[PageOpenGraph]] are the JSON-LD script and the
+//     social meta tags of the page, inserted raw unless a modifier is
+//     given, built only when used
+//   - the standard placeholders take precedence over the custom variables
+//   - placeholders with unknown names or modifiers are left untouched
+func (renderer *contentRenderer) placeholder(node *contentNode, depth int, inPageContent bool) (string, error) {
+	if node.name == "PageContent" {
+		if inPageContent {
+			return node.raw, nil
+		}
+
+		var sb strings.Builder
+
+		// The page content blocks were rendered with the request context
+		if err := renderer.write(renderer.request.Context(), &sb, renderer.pageContent, depth, true); err != nil {
+			return "", err
+		}
+
+		if node.modifier == "" {
+			return sb.String(), nil
+		}
+
+		return renderer.escape(node, sb.String()), nil
+	}
+
+	if node.name == "PageJsonLd" || node.name == "PageOpenGraph" {
+		var html string
+
+		if node.name == "PageJsonLd" {
+			html = renderer.frontend.pageJsonLd(renderer.request.Context(), renderer.siteID)
+		} else {
+			html = renderer.frontend.pageOpenGraph(renderer.request.Context(), renderer.siteID)
+		}
+
+		if node.modifier == "" {
+			return html, nil
+		}
+
+		return renderer.escape(node, html), nil
+	}
+
+	value, exists := renderer.placeholders[node.name]
+
+	if !exists {
+		if vars := cmsstore.VarsFromContext(renderer.request.Context()); vars != nil {
+			value, exists = vars.Get(node.name)
+		}
+	}
+
+	if !exists {
+		return node.raw, nil
+	}
+
+	return renderer.escape(node, value), nil
+}
+
+// escape escapes the value with the modifier of the placeholder, or the
+// default escaping, the placeholder is left untouched if the modifier is
+// not supported
+func (renderer *contentRenderer) escape(node *contentNode, value string) string {
+	escaped, supported := escapePlaceholderValue(value, lo.If(node.modifier == "", renderer.defaultEscaping).Else(node.modifier))
+
+	if !supported {
+		return node.raw
+	}
+
+	return escaped
+}
+
+// writeShortcode renders the shortcode content, passes it to the shortcode
+// and writes the parsed output
+func (renderer *contentRenderer) writeShortcode(ctx context.Context, sb *strings.Builder, node *contentNode, depth int, inPageContent bool) error {
+	var content strings.Builder
+
+	if err := renderer.write(ctx, &content, node.children, depth, inPageContent); err != nil {
+		return err
+	}
+
+	output := renderer.shortcodes[node.name].Render(renderer.request, content.String(), node.attrs)
+
+	nodes := renderer.parseOutput(output, depth+1)
+
+	if err := renderer.renderBlocks(ctx, nodes, depth+1); err != nil {
+		return err
+	}
+
+	return renderer.write(ctx, sb, nodes, depth+1, inPageContent)
+}
+
+// fetchTranslationText returns the text of the translation for the language
+//
+// Business Logic:
+//   - if the translation is not found an empty string is returned
+//   - if the language has no text an empty string is returned
+func (frontend *frontend) fetchTranslationText(ctx context.Context, translationID string, language string) (string, error) {
+	translation, err := frontend.store.TranslationFindByHandleOrID(ctx, translationID, language)
+
+	if err != nil {
+		return "", err
+	}
+
+	if translation == nil {
+		return "", nil
+	}
+
+	translationMap, err := translation.Content()
+
+	if err != nil {
+		return "", err
+	}
+
+	return lo.ValueOr(translationMap, language, ""), nil
+}
@@ -0,0 +1,287 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+// setupContentRendererTest creates a store and a frontend for content renderer tests
+func setupContentRendererTest(t testing.TB, config Config) (*frontend, cmsstore.StoreInterface) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	config.Store = store
+	config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return New(config).(*frontend), store
+}
+
+// createContentBlock is a helper creating an active HTML block
+func createContentBlock(t testing.TB, store cmsstore.StoreInterface, content string) cmsstore.BlockInterface {
+	block := cmsstore.NewBlock().
+		SetSiteID(testutils.SITE_01).
+		SetType(cmsstore.BLOCK_TYPE_HTML).
+		SetContent(content).
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	return block
+}
+
+func TestRenderContentToHtml_DirectivesInBlockOutput(t *testing.T) {
+	f, store := setupContentRendererTest(t, Config{})
+
+	page := cmsstore.NewPage().
+		SetSiteID(testutils.SITE_01).
+		SetAlias("about").
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE)
+
+	if err := store.PageCreate(context.Background(), page); err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	block := createContentBlock(t, store, `<h2>[[PageTitle]]</h2><a href="[[PAGE_URL_`+page.ID()+`]]">About</a>`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `[[BLOCK_`+block.ID()+`]]|<block id="`+block.ID()+`" />|[[PageContent]]`, TemplateRenderHtmlByIDOptions{
+		PageTitle:   "Title",
+		PageContent: `[[BLOCK_` + block.ID() + `]]`,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	blockHtml := `<h2>Title</h2><a href="/about">About</a>`
+	expected := blockHtml + "|" + blockHtml + "|" + blockHtml
+
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+}
+
+func TestRenderContentToHtml_NestedBlocks(t *testing.T) {
+	f, store := setupContentRendererTest(t, Config{})
+
+	inner := createContentBlock(t, store, `<em>inner</em>`)
+	outer := createContentBlock(t, store, `<div>[[BLOCK_`+inner.ID()+`]]</div>`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `[[BLOCK_`+outer.ID()+`]]`, TemplateRenderHtmlByIDOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if html != `<div><em>inner</em></div>` {
+		t.Errorf("Expected nested block to be rendered, got %q", html)
+	}
+}
+
+func TestRenderContentToHtml_MaxDepth(t *testing.T) {
+	// A shortcode outputting itself is parsed again, up to the maximum depth
+	echo := &mockShortcode{
+		alias: "echo",
+		render: func(r *http.Request, s string, attrs map[string]string) string {
+			return "x<echo></echo>"
+		},
+	}
+
+	f, _ := setupContentRendererTest(t, Config{
+		Shortcodes: []cmsstore.ShortcodeInterface{echo},
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `<echo></echo>`, TemplateRenderHtmlByIDOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := strings.Repeat("x", contentRenderMaxDepth) + "<echo></echo>"
+
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+}
+
+func TestRenderContentToHtml_Shortcodes(t *testing.T) {
+	box := &mockShortcode{
+		alias: "box",
+		render: func(r *http.Request, s string, attrs map[string]string) string {
+			return `<div class="` + attrs["class"] + `">` + s + `[[PageTitle]]</div>`
+		},
+	}
+
+	f, store := setupContentRendererTest(t, Config{
+		Shortcodes: []cmsstore.ShortcodeInterface{box},
+	})
+
+	block := createContentBlock(t, store, `<b>block</b>`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `<box class="outer">[[BLOCK_`+block.ID()+`]]<box class="inner">!</box></box>`, TemplateRenderHtmlByIDOptions{
+		PageTitle: "T",
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `<div class="outer"><b>block</b><div class="inner">!T</div>T</div>`
+
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+}
+
+func TestRenderContentToHtml_VariablesBeforeBlock(t *testing.T) {
+	f, store := setupContentRendererTest(t, Config{})
+
+	cmsstore.RegisterCustomBlockType(&testVariableSettingBlockType{})
+
+	block := cmsstore.NewBlock().
+		SetSiteID(testutils.SITE_01).
+		SetType("test_variable_setter").
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `<title>[[blog_title]]</title>[[PageContent]]`, TemplateRenderHtmlByIDOptions{
+		PageContent: `<block id="` + block.ID() + `" />`,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.HasPrefix(html, `<title>My Blog Post Title</title>`) {
+		t.Errorf("Expected variable set later in the page to be resolved, got %q", html)
+	}
+}
+
+func TestRenderContentToHtml_PlaceholderValuesNotParsed(t *testing.T) {
+	f, store := setupContentRendererTest(t, Config{})
+
+	block := createContentBlock(t, store, `<b>block</b>`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `[[PageTitle|raw]]`, TemplateRenderHtmlByIDOptions{
+		PageTitle: `[[BLOCK_` + block.ID() + `]]`,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if html != `[[BLOCK_`+block.ID()+`]]` {
+		t.Errorf("Expected the placeholder value to be inserted as is, got %q", html)
+	}
+}
+
+func TestRenderContentToHtml_Legacy(t *testing.T) {
+	f, store := setupContentRendererTest(t, Config{
+		LegacyContentRendering: true,
+	})
+
+	block := createContentBlock(t, store, `<b>block</b>`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `<title>[[PageTitle]]</title>[[BLOCK_`+block.ID()+`]][[PageContent]]`, TemplateRenderHtmlByIDOptions{
+		PageTitle:   "Title",
+		PageContent: "<p>Body</p>",
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `<title>Title</title><b>block</b><p>Body</p>`
+
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+}
+
+// benchmarkContentRendering renders a large page, with the given pipeline
+func benchmarkContentRendering(b *testing.B, legacy bool) {
+	f, store := setupContentRendererTest(b, Config{
+		CacheEnabled:           true,
+		LegacyContentRendering: legacy,
+	})
+
+	translation := cmsstore.NewTranslation().
+		SetSiteID(testutils.SITE_01).
+		SetHandle("greeting").
+		SetStatus(cmsstore.TRANSLATION_STATUS_ACTIVE)
+
+	if err := translation.SetContent(map[string]string{"en": "Hello"}); err != nil {
+		b.Fatalf("Failed to set translation content: %v", err)
+	}
+
+	if err := store.TranslationCreate(context.Background(), translation); err != nil {
+		b.Fatalf("Failed to create translation: %v", err)
+	}
+
+	var template, pageContent strings.Builder
+
+	template.WriteString(`<html><head><title>[[PageTitle]]</title><meta name="description" content="[[PageMetaDescription]]"></head><body>`)
+
+	for i := 0; i < 50; i++ {
+		block := createContentBlock(b, store, `<section><h2>Section `+strconv.Itoa(i)+` [[PageTitle]]</h2>[[TRANSLATION_greeting]]</section>`)
+
+		template.WriteString(`<nav>[[BLOCK_` + block.ID() + `]]</nav>`)
+		pageContent.WriteString(`<article><block id="` + block.ID() + `" />` + strings.Repeat("<p>Lorem ipsum dolor sit amet.</p>", 20) + `</article>`)
+	}
+
+	template.WriteString(`<main>[[PageContent]]</main><footer><translation id="greeting" /></footer></body></html>`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	options := TemplateRenderHtmlByIDOptions{
+		PageTitle:           "Benchmark",
+		PageMetaDescription: "Benchmark page",
+		PageContent:         pageContent.String(),
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := f.renderContentToHtml(req, template.String(), options); err != nil {
+			b.Fatalf("Failed to render: %v", err)
+		}
+	}
+}
+
+// BenchmarkRenderContentToHtml_SinglePass benchmarks the single-pass pipeline
+func BenchmarkRenderContentToHtml_SinglePass(b *testing.B) {
+	benchmarkContentRendering(b, false)
+}
+
+// BenchmarkRenderContentToHtml_Legacy benchmarks the previous multi-pass pipeline
+func BenchmarkRenderContentToHtml_Legacy(b *testing.B) {
+	benchmarkContentRendering(b, true)
+}
@@ -0,0 +1,220 @@
+package frontend
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dracory/cmsstore"
+)
+
+// experimentCookieMaxAge is how long the visitor keeps the assigned variant
+const experimentCookieMaxAge = 90 * 24 * time.Hour
+
+const experimentAssignmentsContextKey contextKey = "experiment_assignments"
+
+// experimentAssignments collects the variants assigned while rendering
+// the page, to be stored in cookies once the page is rendered
+type experimentAssignments struct {
+	mu sync.Mutex
+
+	// variants are the assigned variant keys, keyed by the cookie name
+	variants map[string]string
+}
+
+// withExperimentAssignments adds the collector of the assigned variants
+// to the context of the request
+func withExperimentAssignments(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), experimentAssignmentsContextKey, &experimentAssignments{
+		variants: map[string]string{},
+	}))
+}
+
+// experimentCookiesWrite sets the cookies of the variants assigned
+// while rendering the page
+func experimentCookiesWrite(w http.ResponseWriter, r *http.Request) {
+	assignments, ok := r.Context().Value(experimentAssignmentsContextKey).(*experimentAssignments)
+	if !ok || w == nil {
+		return
+	}
+
+	assignments.mu.Lock()
+	defer assignments.mu.Unlock()
+
+	for name, variantKey := range assignments.variants {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    variantKey,
+			Path:     "/",
+			MaxAge:   int(experimentCookieMaxAge.Seconds()),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+}
+
+// experimentVariant returns the variant of the running experiment
+// of the page or block, assigned to the visitor
+//
+// Business Logic:
+//   - the variant in the experiment cookie is kept, if still defined
+//   - otherwise a variant is chosen by weight, and stored in the cookie
+//   - the same variant is kept for the whole page (i.e. a block displayed twice)
+func experimentVariant(ctx context.Context, ownerID string, experiment *cmsstore.Experiment) cmsstore.ExperimentVariant {
+	cookieName := cmsstore.ExperimentCookieName(ownerID)
+	assignments, _ := ctx.Value(experimentAssignmentsContextKey).(*experimentAssignments)
+
+	if assignments != nil {
+		assignments.mu.Lock()
+		defer assignments.mu.Unlock()
+	}
+
+	variantKey := ""
+
+	if assignments != nil {
+		variantKey = assignments.variants[cookieName]
+	}
+
+	if r := cmsstore.RequestFromContext(ctx); variantKey == "" && r != nil {
+		if cookie, err := r.Cookie(cookieName); err == nil {
+			variantKey = cookie.Value
+		}
+	}
+
+	variant, found := experiment.Variant(variantKey)
+
+	if !found || variant.Weight <= 0 {
+		variant = experiment.ChooseVariant(rand.IntN(experiment.TotalWeight()))
+
+		if assignments != nil {
+			assignments.variants[cookieName] = variant.Key
+		}
+	}
+
+	return variant
+}
+
+// experimentVariableSet sets the key of the displayed variant as the
+// [[experiment_<key>]] custom variable, the control if the variant is
+// not displayed, i.e. to send the displayed variant to the analytics
+func experimentVariableSet(ctx context.Context, ownerID string, experiment *cmsstore.Experiment, variant cmsstore.ExperimentVariant, displayed bool) {
+	vars := cmsstore.VarsFromContext(ctx)
+	if vars == nil {
+		return
+	}
+
+	if !displayed {
+		variant = cmsstore.ExperimentVariant{}
+
+		for _, control := range experiment.Variants {
+			if control.ContentID == "" || control.ContentID == ownerID {
+				variant = control
+				break
+			}
+		}
+	}
+
+	vars.Set(experiment.VariableName(), variant.Key)
+}
+
+// pageExperimentVariant returns the page to render for the visitor,
+// the variant page if the page runs an experiment, or else the page itself
+//
+// Business Logic:
+//   - the variant pages are usually drafts, displayed only through the experiment
+//   - the inactive or deleted variant pages, or of another site, are not
+//     displayed, the original page (the control) is displayed instead
+func (frontend *frontend) pageExperimentVariant(ctx context.Context, page cmsstore.PageInterface) cmsstore.PageInterface {
+	experiment, err := cmsstore.PageExperiment(page)
+
+	if err != nil {
+		frontend.logger.Error("pageExperimentVariant: Invalid experiment", "pageID", page.ID(), "error", err)
+		return page
+	}
+
+	if experiment == nil || !experiment.IsRunning() || experiment.TotalWeight() == 0 {
+		return page
+	}
+
+	variant := experimentVariant(ctx, page.ID(), experiment)
+
+	if variant.ContentID == "" || variant.ContentID == page.ID() {
+		experimentVariableSet(ctx, page.ID(), experiment, variant, true)
+		return page
+	}
+
+	variantPage, err := frontend.store.PageFindByID(ctx, variant.ContentID)
+
+	if err != nil || variantPage == nil {
+		frontend.logger.Error("pageExperimentVariant: Variant page not found", "pageID", page.ID(), "variant", variant.Key, "error", err)
+		experimentVariableSet(ctx, page.ID(), experiment, variant, false)
+		return page
+	}
+
+	if variantPage.IsInactive() || variantPage.IsSoftDeleted() || variantPage.SiteID() != page.SiteID() {
+		frontend.logger.Warn("pageExperimentVariant: Variant page not displayable", "pageID", page.ID(), "variant", variant.Key, "variantPageID", variantPage.ID())
+		experimentVariableSet(ctx, page.ID(), experiment, variant, false)
+		return page
+	}
+
+	experimentVariableSet(ctx, page.ID(), experiment, variant, true)
+
+	return variantPage
+}
+
+// blockExperimentVariant returns the block to render for the visitor,
+// the variant block if the block runs an experiment, or else the block itself
+//
+// Business Logic:
+//   - the variant blocks are usually drafts, displayed only through the experiment
+//   - the inactive or deleted variant blocks, of another site, or hidden
+//     to the visitor by their visibility rules, are not displayed, the
+//     original block (the control) is displayed instead
+//
+// Returns:
+//   - block: the block to render
+//   - running: true if the block runs an experiment, its content must not be cached
+func (frontend *frontend) blockExperimentVariant(ctx context.Context, block cmsstore.BlockInterface) (cmsstore.BlockInterface, bool) {
+	experiment, err := cmsstore.BlockExperiment(block)
+
+	if err != nil {
+		frontend.logger.Error("blockExperimentVariant: Invalid experiment", "blockID", block.ID(), "error", err)
+		return block, false
+	}
+
+	if experiment == nil || !experiment.IsRunning() || experiment.TotalWeight() == 0 {
+		return block, false
+	}
+
+	variant := experimentVariant(ctx, block.ID(), experiment)
+
+	if variant.ContentID == "" || variant.ContentID == block.ID() {
+		experimentVariableSet(ctx, block.ID(), experiment, variant, true)
+		return block, true
+	}
+
+	variantBlock, err := frontend.store.BlockFindByID(ctx, variant.ContentID)
+
+	if err != nil || variantBlock == nil {
+		frontend.logger.Error("blockExperimentVariant: Variant block not found", "blockID", block.ID(), "variant", variant.Key, "error", err)
+		experimentVariableSet(ctx, block.ID(), experiment, variant, false)
+		return block, true
+	}
+
+	if variantBlock.IsInactive() || variantBlock.IsSoftDeleted() || variantBlock.SiteID() != block.SiteID() {
+		frontend.logger.Warn("blockExperimentVariant: Variant block not displayable", "blockID", block.ID(), "variant", variant.Key, "variantBlockID", variantBlock.ID())
+		experimentVariableSet(ctx, block.ID(), experiment, variant, false)
+		return block, true
+	}
+
+	if visible, _ := frontend.blockIsVisible(ctx, variantBlock); !visible {
+		experimentVariableSet(ctx, block.ID(), experiment, variant, false)
+		return block, true
+	}
+
+	experimentVariableSet(ctx, block.ID(), experiment, variant, true)
+
+	return variantBlock, true
+}
@@ -0,0 +1,272 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+// initExperimentSite creates a site with a page, both running an experiment:
+// the page against a variant page, and a block against a variant block
+func initExperimentSite(t *testing.T) (*frontend, cmsstore.SiteInterface, cmsstore.PageInterface, cmsstore.BlockInterface) {
+	t.Helper()
+
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site := cmsstore.NewSite().
+		SetName("Experiment Site").
+		SetStatus(cmsstore.SITE_STATUS_ACTIVE)
+
+	if err := store.SiteCreate(context.Background(), site); err != nil {
+		t.Fatalf("Failed to create site: %v", err)
+	}
+
+	variantBlock := cmsstore.NewBlock().
+		SetSiteID(site.ID()).
+		SetType(cmsstore.BLOCK_TYPE_HTML).
+		SetContent("<button>Buy now</button>").
+		SetStatus(cmsstore.BLOCK_STATUS_DRAFT)
+
+	if err := store.BlockCreate(context.Background(), variantBlock); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	block := cmsstore.NewBlock().
+		SetSiteID(site.ID()).
+		SetType(cmsstore.BLOCK_TYPE_HTML).
+		SetContent("<button>Add to cart</button>").
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := cmsstore.SetBlockExperiment(block, &cmsstore.Experiment{
+		Key:    "cta",
+		Status: cmsstore.EXPERIMENT_STATUS_RUNNING,
+		Variants: []cmsstore.ExperimentVariant{
+			{Key: "control", Weight: 50},
+			{Key: "buy", ContentID: variantBlock.ID(), Weight: 50},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to set block experiment: %v", err)
+	}
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	variantPage := cmsstore.NewPage().
+		SetSiteID(site.ID()).
+		SetAlias("home-b").
+		SetContent("<h1>Welcome B</h1>[[BLOCK_" + block.ID() + "]]<i>[[experiment_home]] [[experiment_cta]]</i>").
+		SetStatus(cmsstore.PAGE_STATUS_DRAFT)
+
+	if err := store.PageCreate(context.Background(), variantPage); err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	page := cmsstore.NewPage().
+		SetSiteID(site.ID()).
+		SetAlias("home").
+		SetContent("<h1>Welcome A</h1>[[BLOCK_" + block.ID() + "]]<i>[[experiment_home]] [[experiment_cta]]</i>").
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE)
+
+	if err := cmsstore.SetPageExperiment(page, &cmsstore.Experiment{
+		Key:    "home",
+		Status: cmsstore.EXPERIMENT_STATUS_RUNNING,
+		Variants: []cmsstore.ExperimentVariant{
+			{Key: "a", Weight: 50},
+			{Key: "b", ContentID: variantPage.ID(), Weight: 50},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to set page experiment: %v", err)
+	}
+
+	if err := store.PageCreate(context.Background(), page); err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	f := New(Config{
+		Store:              store,
+		Logger:             slog.New(slog.NewTextHandler(io.Discard, nil)),
+		CacheEnabled:       true,
+		CacheExpireSeconds: 60,
+	}).(*frontend)
+
+	return f, site, page, block
+}
+
+func TestExperiment_StickyVariantFromCookie(t *testing.T) {
+	f, site, page, block := initExperimentSite(t)
+
+	for range 5 {
+		r := httptest.NewRequest(http.MethodGet, "/home", nil)
+		r.AddCookie(&http.Cookie{Name: cmsstore.ExperimentCookieName(page.ID()), Value: "b"})
+		r.AddCookie(&http.Cookie{Name: cmsstore.ExperimentCookieName(block.ID()), Value: "buy"})
+
+		recorder := httptest.NewRecorder()
+		html := f.PageRenderHtmlBySiteAndAlias(recorder, r, site.ID(), "home", "en")
+
+		for _, expected := range []string{"Welcome B", "Buy now", "<i>b buy</i>"} {
+			if !strings.Contains(html, expected) {
+				t.Fatalf("Expected %q in %s", expected, html)
+			}
+		}
+
+		if cookies := recorder.Result().Cookies(); len(cookies) != 0 {
+			t.Errorf("Expected the assigned variants to be kept, got cookies %v", cookies)
+		}
+	}
+}
+
+func TestExperiment_AssignsVariantCookies(t *testing.T) {
+	f, site, page, block := initExperimentSite(t)
+
+	recorder := httptest.NewRecorder()
+	html := f.PageRenderHtmlBySiteAndAlias(recorder, httptest.NewRequest(http.MethodGet, "/home", nil), site.ID(), "home", "en")
+
+	cookies := map[string]string{}
+	for _, cookie := range recorder.Result().Cookies() {
+		cookies[cookie.Name] = cookie.Value
+	}
+
+	pageVariant := cookies[cmsstore.ExperimentCookieName(page.ID())]
+	blockVariant := cookies[cmsstore.ExperimentCookieName(block.ID())]
+
+	if pageVariant == "" || blockVariant == "" {
+		t.Fatalf("Expected the variant cookies, got %v", cookies)
+	}
+
+	if !strings.Contains(html, "<i>"+pageVariant+" "+blockVariant+"</i>") {
+		t.Errorf("Expected the assigned variants as variables, got %s", html)
+	}
+
+	if (pageVariant == "b") != strings.Contains(html, "Welcome B") {
+		t.Errorf("Expected the page of variant %s, got %s", pageVariant, html)
+	}
+
+	if (blockVariant == "buy") != strings.Contains(html, "Buy now") {
+		t.Errorf("Expected the block of variant %s, got %s", blockVariant, html)
+	}
+}
+
+func TestExperiment_StoppedShowsOriginal(t *testing.T) {
+	f, site, page, _ := initExperimentSite(t)
+
+	experiment, _ := cmsstore.PageExperiment(page)
+	experiment.Stop()
+
+	if err := cmsstore.SetPageExperiment(page, experiment); err != nil {
+		t.Fatalf("Failed to stop experiment: %v", err)
+	}
+
+	if err := f.store.PageUpdate(context.Background(), page); err != nil {
+		t.Fatalf("Failed to update page: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/home", nil)
+	r.AddCookie(&http.Cookie{Name: cmsstore.ExperimentCookieName(page.ID()), Value: "b"})
+
+	recorder := httptest.NewRecorder()
+	html := f.PageRenderHtmlBySiteAndAlias(recorder, r, site.ID(), "home", "en")
+
+	if !strings.Contains(html, "Welcome A") {
+		t.Errorf("Expected the original page, got %s", html)
+	}
+
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.Name == cmsstore.ExperimentCookieName(page.ID()) {
+			t.Errorf("Expected no variant to be assigned for a stopped experiment")
+		}
+	}
+}
+
+func TestExperiment_UndisplayableVariantShowsControl(t *testing.T) {
+	f, site, page, block := initExperimentSite(t)
+	ctx := context.Background()
+
+	pageExperiment, _ := cmsstore.PageExperiment(page)
+	variantPage, err := f.store.PageFindByID(ctx, pageExperiment.Variants[1].ContentID)
+	if err != nil || variantPage == nil {
+		t.Fatalf("Failed to find the variant page: %v", err)
+	}
+
+	variantPage.SetStatus(cmsstore.PAGE_STATUS_INACTIVE)
+
+	if err := f.store.PageUpdate(ctx, variantPage); err != nil {
+		t.Fatalf("Failed to update page: %v", err)
+	}
+
+	blockExperiment, _ := cmsstore.BlockExperiment(block)
+	variantBlock, err := f.store.BlockFindByID(ctx, blockExperiment.Variants[1].ContentID)
+	if err != nil || variantBlock == nil {
+		t.Fatalf("Failed to find the variant block: %v", err)
+	}
+
+	if err := cmsstore.SetBlockVisibilityRules(variantBlock, cmsstore.BlockVisibility{Languages: []string{"de"}}); err != nil {
+		t.Fatalf("Failed to set visibility rules: %v", err)
+	}
+
+	if err := f.store.BlockUpdate(ctx, variantBlock); err != nil {
+		t.Fatalf("Failed to update block: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/home", nil)
+	r.AddCookie(&http.Cookie{Name: cmsstore.ExperimentCookieName(page.ID()), Value: "b"})
+	r.AddCookie(&http.Cookie{Name: cmsstore.ExperimentCookieName(block.ID()), Value: "buy"})
+
+	html := f.PageRenderHtmlBySiteAndAlias(httptest.NewRecorder(), r, site.ID(), "home", "en")
+
+	for _, expected := range []string{"Welcome A", "Add to cart", "<i>a control</i>"} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected %q in %s", expected, html)
+		}
+	}
+
+	for _, unexpected := range []string{"Welcome B", "Buy now"} {
+		if strings.Contains(html, unexpected) {
+			t.Errorf("Expected no %q in %s", unexpected, html)
+		}
+	}
+}
+
+func TestExperiment_AttributeSyntaxVariant(t *testing.T) {
+	f, site, _, block := initExperimentSite(t)
+
+	page := cmsstore.NewPage().
+		SetSiteID(site.ID()).
+		SetAlias("shop").
+		SetContent(`<h1>Shop</h1><block id="` + block.ID() + `" wrap="div" />`).
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE)
+
+	if err := f.store.PageCreate(context.Background(), page); err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	tests := []struct {
+		variant  string
+		expected string
+	}{
+		{"buy", "<div><button>Buy now</button></div>"},
+		{"control", "<div><button>Add to cart</button></div>"},
+		{"buy", "<div><button>Buy now</button></div>"},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/shop", nil)
+		r.AddCookie(&http.Cookie{Name: cmsstore.ExperimentCookieName(block.ID()), Value: test.variant})
+
+		html := f.PageRenderHtmlBySiteAndAlias(httptest.NewRecorder(), r, site.ID(), "shop", "en")
+
+		if !strings.Contains(html, test.expected) {
+			t.Errorf("Variant %s: expected %q in %s", test.variant, test.expected, html)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package frontend
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/blocks/forms"
+)
+
+// formSubmitter is implemented by the block types handling form submissions
+type formSubmitter interface {
+	Submit(ctx context.Context, block cmsstore.BlockInterface, r *http.Request) *forms.SubmitResult
+}
+
+// formSubmissionHandle handles the form block submission posted to the page
+//
+// Business Logic:
+//   - only POST requests with the cms_form_id input are handled
+//   - the block must be an active form block of the site
+//   - a successful submission redirects (303 See Other), so that
+//     reloading the page does not submit the form again
+//   - an invalid submission is added to the request context,
+//     for the form to show the errors when the page is rendered
+//
+// Returns:
+//   - r: the request, with the result of an invalid submission in its context
+//   - redirected: true if the response was redirected
+func (frontend *frontend) formSubmissionHandle(w http.ResponseWriter, r *http.Request, siteID string) (*http.Request, bool) {
+	if r.Method != http.MethodPost || frontend.store == nil || !frontend.store.FormsEnabled() {
+		return r, false
+	}
+
+	blockID := r.PostFormValue(forms.INPUT_FORM_ID)
+	if blockID == "" {
+		return r, false
+	}
+
+	block, err := frontend.store.BlockFindByID(r.Context(), blockID)
+	if err != nil {
+		frontend.logger.Error("formSubmissionHandle: Error finding block", "blockID", blockID, "error", err)
+		return r, false
+	}
+
+	if block == nil || !block.IsActive() || block.Type() != cmsstore.BLOCK_TYPE_FORM || block.SiteID() != siteID {
+		return r, false
+	}
+
+	submitter, ok := frontend.blockRenderers.blockType(cmsstore.BLOCK_TYPE_FORM).(formSubmitter)
+	if !ok {
+		return r, false
+	}
+
+	ctx := cmsstore.RequestToContext(r.Context(), r)
+	result := submitter.Submit(ctx, block, r)
+
+	for _, hookErr := range result.HookErrors {
+		frontend.logger.Error("formSubmissionHandle: Submission hook error", "blockID", blockID, "error", hookErr)
+	}
+
+	if result.Success {
+		http.Redirect(w, r, result.RedirectURL, http.StatusSeeOther)
+		return r, true
+	}
+
+	return r.WithContext(forms.SubmitResultToContext(r.Context(), result)), false
+}
@@ -0,0 +1,262 @@
+package frontend
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/blocks/forms"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+const testFormSecret = "test-form-secret"
+
+// testVisitorKey is the visitor key cookie of the test submissions
+const testVisitorKey = "test-visitor-key"
+
+// formToken returns a CSRF token of the form block, rendered a minute ago
+// for the visitor of testVisitorKey
+func formToken(blockID string) string {
+	timestamp := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(testFormSecret))
+	mac.Write([]byte(blockID + "|" + testVisitorKey + "|" + timestamp))
+	return timestamp + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// initFormPage creates a page displaying a form block
+func initFormPage(t *testing.T) (*frontend, cmsstore.StoreInterface, cmsstore.SiteInterface, cmsstore.BlockInterface) {
+	t.Helper()
+
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site := cmsstore.NewSite().
+		SetName("Form Site").
+		SetStatus(cmsstore.SITE_STATUS_ACTIVE)
+
+	if err := store.SiteCreate(context.Background(), site); err != nil {
+		t.Fatalf("Failed to create site: %v", err)
+	}
+
+	block := cmsstore.NewBlock().
+		SetSiteID(site.ID()).
+		SetType(cmsstore.BLOCK_TYPE_FORM).
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := forms.SetFields(block, []forms.Field{
+		{Name: "email", Label: "Email", Type: forms.FIELD_TYPE_EMAIL, Required: true},
+	}); err != nil {
+		t.Fatalf("Failed to set fields: %v", err)
+	}
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	page := cmsstore.NewPage().
+		SetSiteID(site.ID()).
+		SetAlias("contact").
+		SetContent("<h1>Contact</h1>[[BLOCK_" + block.ID() + "]]").
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE)
+
+	if err := store.PageCreate(context.Background(), page); err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	f := New(Config{
+		Store:              store,
+		Logger:             slog.New(slog.NewTextHandler(io.Discard, nil)),
+		FormSecret:         testFormSecret,
+		CacheEnabled:       true,
+		CacheExpireSeconds: 60,
+	}).(*frontend)
+
+	return f, store, site, block
+}
+
+func postForm(blockID string, email string) *http.Request {
+	values := url.Values{
+		forms.INPUT_FORM_ID: {blockID},
+		forms.INPUT_TOKEN:   {formToken(blockID)},
+		"email":             {email},
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/contact", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Origin", "http://example.com")
+	r.AddCookie(&http.Cookie{Name: forms.COOKIE_VISITOR_KEY, Value: testVisitorKey})
+
+	return r
+}
+
+func TestFormSubmission_ValidRedirects(t *testing.T) {
+	f, store, site, block := initFormPage(t)
+
+	recorder := httptest.NewRecorder()
+	html := f.PageRenderHtmlBySiteAndAlias(recorder, postForm(block.ID(), "jane@example.com"), site.ID(), "contact", "en")
+
+	if html != "" || recorder.Code != http.StatusSeeOther {
+		t.Fatalf("Expected a redirect, got %d: %s", recorder.Code, html)
+	}
+
+	location := recorder.Header().Get("Location")
+	if location != "/contact?"+forms.QUERY_SUCCESS+"="+block.ID() {
+		t.Errorf("Unexpected redirect location: %s", location)
+	}
+
+	count, err := store.FormSubmissionCount(context.Background(), cmsstore.FormSubmissionQuery().SetBlockID(block.ID()))
+	if err != nil || count != 1 {
+		t.Fatalf("Expected 1 stored submission, got %d (%v)", count, err)
+	}
+
+	html = f.PageRenderHtmlBySiteAndAlias(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, location, nil), site.ID(), "contact", "en")
+	if !strings.Contains(html, "alert-success") {
+		t.Errorf("Expected the success message, got %s", html)
+	}
+}
+
+func TestFormSubmission_InvalidRendersErrors(t *testing.T) {
+	f, store, site, block := initFormPage(t)
+
+	// Cache the block rendered for a GET request
+	html := f.PageRenderHtmlBySiteAndAlias(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/contact", nil), site.ID(), "contact", "en")
+	if !strings.Contains(html, "<form") {
+		t.Fatalf("Expected the form, got %s", html)
+	}
+
+	recorder := httptest.NewRecorder()
+	html = f.PageRenderHtmlBySiteAndAlias(recorder, postForm(block.ID(), "not-an-email"), site.ID(), "contact", "en")
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected the page to be rendered, got %d", recorder.Code)
+	}
+
+	if !strings.Contains(html, "must be a valid email address") || !strings.Contains(html, `value="not-an-email"`) {
+		t.Errorf("Expected the errors and submitted values, got %s", html)
+	}
+
+	count, err := store.FormSubmissionCount(context.Background(), cmsstore.FormSubmissionQuery().SetBlockID(block.ID()))
+	if err != nil || count != 0 {
+		t.Fatalf("Expected no stored submission, got %d (%v)", count, err)
+	}
+
+	// The errors must not be cached for the next visitors
+	html = f.PageRenderHtmlBySiteAndAlias(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/contact", nil), site.ID(), "contact", "en")
+	if strings.Contains(html, "not-an-email") {
+		t.Errorf("Expected the submission not to be cached, got %s", html)
+	}
+}
+
+func TestFormSubmission_SecretAndHooksPerFrontend(t *testing.T) {
+	f, store, site, block := initFormPage(t)
+
+	hookCalls := map[string]int{}
+	hook := func(name string) forms.SubmissionHook {
+		return func(_ context.Context, _ cmsstore.BlockInterface, _ cmsstore.FormSubmissionInterface) error {
+			hookCalls[name]++
+			return nil
+		}
+	}
+
+	f.formSubmissionHooks = []forms.SubmissionHook{hook("first")}
+	f.blockRenderers = initBlockRenderers(f, store)
+
+	// A second frontend of the same store, with its own secret and hooks
+	New(Config{
+		Store:               store,
+		Logger:              slog.New(slog.NewTextHandler(io.Discard, nil)),
+		FormSecret:          "other-form-secret",
+		FormSubmissionHooks: []forms.SubmissionHook{hook("second")},
+	})
+
+	recorder := httptest.NewRecorder()
+	html := f.PageRenderHtmlBySiteAndAlias(recorder, postForm(block.ID(), "jane@example.com"), site.ID(), "contact", "en")
+
+	if recorder.Code != http.StatusSeeOther {
+		t.Fatalf("Expected the token signed with the secret of the frontend to be accepted, got %d: %s", recorder.Code, html)
+	}
+
+	if hookCalls["first"] != 1 || hookCalls["second"] != 0 {
+		t.Errorf("Expected the hooks of the frontend only, got %v", hookCalls)
+	}
+}
+
+func TestFormSubmission_TokenBoundToVisitor(t *testing.T) {
+	f, _, site, _ := initFormPage(t)
+
+	tokens := map[string]bool{}
+
+	for range 2 {
+		recorder := httptest.NewRecorder()
+		html := f.PageRenderHtmlBySiteAndAlias(recorder, httptest.NewRequest(http.MethodGet, "/contact", nil), site.ID(), "contact", "en")
+
+		found := false
+		for _, cookie := range recorder.Result().Cookies() {
+			found = found || (cookie.Name == forms.COOKIE_VISITOR_KEY && cookie.Value != "")
+		}
+
+		if !found {
+			t.Fatalf("Expected the visitor key cookie to be set")
+		}
+
+		_, token, _ := strings.Cut(html, `name="`+forms.INPUT_TOKEN+`" type="hidden" value="`)
+		token, _, _ = strings.Cut(token, `"`)
+		tokens[token] = true
+	}
+
+	// The forms are not cached, each visitor has its own token
+	if len(tokens) != 2 {
+		t.Errorf("Expected a token per visitor, got %v", tokens)
+	}
+}
+
+func TestFormSubmission_ValuesNotRenderedAsBlocks(t *testing.T) {
+	f, store, site, block := initFormPage(t)
+
+	// A block which is not on the page
+	hidden := cmsstore.NewBlock().
+		SetSiteID(site.ID()).
+		SetType(cmsstore.BLOCK_TYPE_HTML).
+		SetContent("TOP-SECRET").
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := store.BlockCreate(context.Background(), hidden); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	submitted := []string{
+		"[[BLOCK_" + hidden.ID() + "]]",
+		"[[block id='" + hidden.ID() + "']]",
+		"[[TRANSLATION_" + hidden.ID() + "]]",
+	}
+
+	for _, value := range submitted {
+		recorder := httptest.NewRecorder()
+		html := f.PageRenderHtmlBySiteAndAlias(recorder, postForm(block.ID(), value), site.ID(), "contact", "en")
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected the page to be rendered, got %d", recorder.Code)
+		}
+
+		if strings.Contains(html, "TOP-SECRET") {
+			t.Fatalf("Expected the submitted %s not to render the block, got %s", value, html)
+		}
+
+		if !strings.Contains(html, `value="&#91;&#91;`) {
+			t.Errorf("Expected the submitted %s to be echoed back escaped, got %s", value, html)
+		}
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"log/slog"
 	"maps"
 	"net/http"
+	"net/url"
 	"regexp"
 	"sort"
 	"strings"
 
 	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/blocks/forms"
 	"github.com/dracory/cmsstore/frontend/blocks/menu"
 	"github.com/dracory/hb"
 	"github.com/dracory/shortcode"
@@ -21,15 +23,22 @@ import (
 )
 
 type frontend struct {
-	blockEditorRenderer func(blocks []ui.BlockInterface) string
-	logger              *slog.Logger
-	shortcodes          []cmsstore.ShortcodeInterface
-	store               cmsstore.StoreInterface
-	cacheEnabled        bool
-	cacheExpireSeconds  int
-	cache               *ttlcache.Cache[string, any]
-	blockRenderers      *BlockRendererRegistry
-	pageNotFoundHandler func(w http.ResponseWriter, r *http.Request, alias string) (handled bool, result string)
+	blockEditorRenderer    func(blocks []ui.BlockInterface) string
+	logger                 *slog.Logger
+	shortcodes             []cmsstore.ShortcodeInterface
+	store                  cmsstore.StoreInterface
+	blockTypes             *cmsstore.BlockTypeRegistry
+	cacheEnabled           bool
+	cacheExpireSeconds     int
+	cache                  *ttlcache.Cache[string, any]
+	blockRenderers         *BlockRendererRegistry
+	legacyContentRendering bool
+	blockMaxDepth          int
+	debug                  bool
+	pageNotFoundHandler    func(w http.ResponseWriter, r *http.Request, alias string) (handled bool, result string)
+	formSecret             []byte
+	formSubmissionHooks    []forms.SubmissionHook
+	isLoggedIn             func(r *http.Request) bool
 }
 
 // Implement menu.FrontendStore interface
@@ -41,6 +50,10 @@ func (f *frontend) MenuItemList(ctx context.Context, query cmsstore.MenuItemQuer
 	return f.store.MenuItemList(ctx, query)
 }
 
+func (f *frontend) MenuItemsResolve(ctx context.Context, menuID string) ([]cmsstore.MenuItemInterface, error) {
+	return f.store.MenuItemsResolve(ctx, menuID)
+}
+
 func (f *frontend) MenusEnabled() bool {
 	return f.store.MenusEnabled()
 }
@@ -58,6 +71,15 @@ func (f *frontend) Logger() *slog.Logger {
 	return f.logger
 }
 
+// blockTypeRegistry returns the registry the block types are looked up in,
+// the default global registry if none is set
+func (f *frontend) blockTypeRegistry() *cmsstore.BlockTypeRegistry {
+	if f.blockTypes == nil {
+		return cmsstore.DefaultBlockTypeRegistry()
+	}
+	return f.blockTypes
+}
+
 // BlockRegistry returns the block renderer registry, allowing external packages
 // to register custom block types.
 //
@@ -81,11 +103,6 @@ var _ FrontendInterface = (*frontend)(nil)
 
 type contextKey string
 
-const (
-	// Define a custom context key for the page
-	pageContextKey contextKey = "page"
-)
-
 // Handler is the main handler for the CMS frontend.
 //
 // It handles the routing of the request to the appropriate page.
@@ -153,9 +170,8 @@ func (frontend *frontend) StringHandler(w http.ResponseWriter, r *http.Request)
 //
 // Business Logic:
 // - if the block find returns an error error is returned
-// - if the block is not active an empty string is returned
-// - dispatches to type-specific renderer based on block type
-// - the rendered block content is returned
+// - if the block is not found an empty string is returned
+// - the block is rendered by renderBlockContent
 //
 // Parameters:
 // - blockID: the ID of the block
@@ -167,17 +183,68 @@ func (frontend *frontend) fetchBlockContent(ctx context.Context, blockID string)
 		return "", nil
 	}
 
-	key := "block_content_" + blockID
+	block, err := frontend.fetchBlock(ctx, blockID)
+
+	if err != nil {
+		return "", err
+	}
+
+	if block == nil {
+		return "", nil
+	}
+
+	return frontend.renderBlockContent(ctx, block, map[string]string{})
+}
 
-	// Include query string in cache key so blocks that depend on query
-	// parameters (e.g. blog_post_list with ?page=N) are cached separately.
-	if r := cmsstore.RequestFromContext(ctx); r != nil {
-		if r.URL.RawQuery != "" {
-			key += "_q_" + r.URL.RawQuery
+// renderBlockContent renders the block, the same way for the [[BLOCK_id]]
+// and the <block id="..." /> references
+//
+// Business Logic:
+// - if the block is not active an empty string is returned
+// - if the visibility rules do not match the visitor an empty string is returned
+// - blocks running an experiment render the variant of the visitor
+// - library blocks render their linked instance, the attributes matching
+// their library fields override the block metas
+// - dispatches to type-specific renderer based on block type, with the
+// remaining attributes (sanitized) as runtime attributes
+// - the rendered block is cached, see blockContentCacheKey
+// - the cache is bypassed for POST requests (form submissions)
+// - blocks with visibility rules or running an experiment are not cached
+// - blocks referenced with attributes are cached per attribute values
+//
+// Parameters:
+// - block: the referenced block
+// - attrs: the attributes of the reference, without id and wrap
+//
+// Returns:
+// - content: the content of the block
+func (frontend *frontend) renderBlockContent(ctx context.Context, block cmsstore.BlockInterface, attrs map[string]string) (string, error) {
+	key, expireSeconds, useCache := frontend.blockContentCacheKey(ctx, block)
+
+	// The attributes change the rendered block, so are part of the key
+	if useCache && len(attrs) > 0 {
+		values := url.Values{}
+
+		for name, value := range attrs {
+			values.Set(name, value)
 		}
+
+		key += "_a_" + values.Encode()
 	}
 
-	if frontend.CacheHas(key) {
+	// Blocks rendered for a form submission (POST) show its errors and
+	// submitted values, so are neither read from nor written to the cache.
+	if r := cmsstore.RequestFromContext(ctx); r != nil && r.Method == http.MethodPost {
+		useCache = false
+	}
+
+	cacheSet := func(value any, expireSeconds int) {
+		if useCache {
+			frontend.CacheSet(key, value, expireSeconds)
+		}
+	}
+
+	if useCache && frontend.CacheHas(key) {
 		blockContent := frontend.CacheGet(key)
 
 		if blockContent == nil {
@@ -187,37 +254,54 @@ func (frontend *frontend) fetchBlockContent(ctx context.Context, blockID string)
 		return blockContent.(string), nil
 	}
 
-	block, err := frontend.store.BlockFindByID(ctx, blockID)
+	content := ""
+	visible := block.IsActive()
 
-	if err != nil {
-		frontend.CacheSet(key, "", 10) // 10 seconds only, error
-		return "", err
+	if visible {
+		var hasRules bool
+		visible, hasRules = frontend.blockIsVisible(ctx, block)
+
+		// The visibility depends on the visitor, so the content is not cached
+		useCache = useCache && !hasRules
 	}
 
-	if block == nil {
-		frontend.CacheSet(key, "", frontend.cacheExpireSeconds)
-		return "", nil
+	if visible {
+		var hasExperiment bool
+		block, hasExperiment = frontend.blockExperimentVariant(ctx, block)
+
+		// The variant depends on the visitor, so the content is not cached
+		useCache = useCache && !hasExperiment
 	}
 
-	content := ""
+	if visible {
+		instance, runtimeAttrs, err := cmsstore.BlockLibraryInstance(block, attrs)
+
+		if err == nil {
+			content, err = frontend.renderBlockByType(ctx, instance, filterAndSanitizeAttrs(runtimeAttrs))
+		}
 
-	if block.IsActive() {
-		content, err = frontend.renderBlockByType(ctx, block)
 		if err != nil {
-			frontend.logger.Error("fetchBlockContent: Error rendering block", "blockID", blockID, "type", block.Type(), "error", err)
-			frontend.CacheSet(key, "", 10) // 10 seconds only, error
+			frontend.logger.Error("renderBlockContent: Error rendering block", "blockID", block.ID(), "type", block.Type(), "error", err)
+			cacheSet("", 10) // 10 seconds only, error
 			return "", err
 		}
+
+		content = renderLibraryFields(instance, content)
 	}
 
-	frontend.CacheSet(key, content, frontend.cacheExpireSeconds)
+	cacheSet(content, expireSeconds)
 
 	return content, nil
 }
 
-// renderBlockByType dispatches to the appropriate renderer based on block type
-func (frontend *frontend) renderBlockByType(ctx context.Context, block cmsstore.BlockInterface) (string, error) {
-	return frontend.blockRenderers.RenderBlock(ctx, block)
+// renderBlockByType dispatches to the appropriate renderer based on block
+// type, the attributes (if any) are passed as runtime attributes
+func (frontend *frontend) renderBlockByType(ctx context.Context, block cmsstore.BlockInterface, attrs map[string]string) (string, error) {
+	if len(attrs) == 0 {
+		return frontend.blockRenderers.RenderBlock(ctx, block)
+	}
+
+	return frontend.blockRenderers.RenderBlock(ctx, block, cmsstore.WithAttributes(attrs))
 }
 
 // fetchPageAliasMapBySite fetches the page alias map for a given site ID
@@ -461,11 +545,43 @@ func (frontend *frontend) PageRenderHtmlBySiteAndAlias(w http.ResponseWriter, r
 		return hb.NewDiv().Text("Page with alias '").Text(alias).Text("' not found").ToHTML()
 	}
 
-	// Get the page or template content
-	pageOrTemplateContent := frontend.pageOrTemplateContent(r, page)
+	// Add page, its ancestors (for the active menu trail) and language to the context
+	r = r.WithContext(cmsstore.PageToContext(r.Context(), page))
+	if page.ParentID() != "" {
+		ancestors, err := frontend.store.PageAncestors(r.Context(), page.ID())
+		if err != nil {
+			frontend.logger.Warn("PageRenderHtmlBySiteAndAlias: Page ancestors not found", "pageID", page.ID(), "error", err)
+		} else {
+			r = r.WithContext(cmsstore.PageAncestorsToContext(r.Context(), ancestors))
+		}
+	}
+	r = r.WithContext(context.WithValue(r.Context(), LanguageKey{}, lo.If(language == "", "en").Else(language)))
+	r = r.WithContext(cmsstore.VisitorToContext(r.Context(), frontend.visitor(r, lo.If(language == "", "en").Else(language))))
+
+	// Handle the form submission posted to the page, if any
+	r, redirected := frontend.formSubmissionHandle(w, r, siteID)
+	if redirected {
+		return ""
+	}
+
+	// Add the custom variables, so the page and the blocks share them,
+	// the collector of the experiment variants assigned to the visitor,
+	// and the visitor key the CSRF tokens of the forms are bound to
+	if cmsstore.VarsFromContext(r.Context()) == nil {
+		r = r.WithContext(cmsstore.WithVarsContext(r.Context()))
+	}
+	r = withExperimentAssignments(r)
+	r = forms.WithVisitorKey(r)
+
+	// Render the variant of the page assigned to the visitor, if running an experiment
+	page = frontend.pageExperimentVariant(cmsstore.RequestToContext(r.Context(), r), page)
+
+	// Get the page or template content, and the editor selecting the engine
+	pageOrTemplateContent, editor := frontend.pageOrTemplateContentAndEditor(r, page)
 
 	// Render the content to HTML
-	html, err := frontend.renderContentToHtml(r, pageOrTemplateContent, TemplateRenderHtmlByIDOptions{
+	html, err := frontend.renderTemplateContentToHtml(r, pageOrTemplateContent, editor, TemplateRenderHtmlByIDOptions{
+		SiteID:              siteID,
 		Language:            language,
 		PageContent:         page.Content(),
 		PageCanonicalURL:    page.CanonicalUrl(),
@@ -480,8 +596,10 @@ func (frontend *frontend) PageRenderHtmlBySiteAndAlias(w http.ResponseWriter, r
 		return hb.NewDiv().Text("Error occurred").ToHTML()
 	}
 
-	// Add page to the context
-	r = r.WithContext(context.WithValue(r.Context(), pageContextKey, page))
+	// Keep the experiment variants assigned to the visitor,
+	// and the visitor key of the rendered forms
+	experimentCookiesWrite(w, r)
+	forms.VisitorKeyCookieWrite(w, r)
 
 	// Apply middleware transformations to the rendered HTML before returning the final result.
 	return frontend.applyMiddlewares(w, r, html, page.MiddlewaresBefore(), page.MiddlewaresAfter())
@@ -495,7 +613,8 @@ func (frontend *frontend) PageRenderHtmlBySiteAndAlias(w http.ResponseWriter, r
 // 3. If the page has no template, return the page content as is.
 // 4. Fetch the template associated with the page.
 // 5. If the template is not found, return the page content as is.
-// 6. Return the template content.
+// 6. Resolve the sections inherited from the parent templates.
+// 7. Return the template content.
 //
 // Parameters:
 // - r: the HTTP request
@@ -504,6 +623,16 @@ func (frontend *frontend) PageRenderHtmlBySiteAndAlias(w http.ResponseWriter, r
 // Returns:
 // - pageContent: the content of the page or the template
 func (frontend *frontend) pageOrTemplateContent(r *http.Request, page cmsstore.PageInterface) (pageContent string) {
+	pageContent, _ = frontend.pageOrTemplateContentAndEditor(r, page)
+	return pageContent
+}
+
+// pageOrTemplateContentAndEditor returns the content of the page or the
+// template associated with the page, and the editor of the template
+//
+// The editor selects the rendering engine (see renderTemplateContentToHtml),
+// it is empty if the page content is returned.
+func (frontend *frontend) pageOrTemplateContentAndEditor(r *http.Request, page cmsstore.PageInterface) (pageContent string, editor string) {
 	pageContent = page.Content()
 
 	// If the page uses the block editor, convert its JSON content to HTML.
@@ -513,7 +642,7 @@ func (frontend *frontend) pageOrTemplateContent(r *http.Request, page cmsstore.P
 
 	// If the page has no template, return the page content as is.
 	if page.TemplateID() == "" {
-		return pageContent
+		return pageContent, ""
 	}
 
 	// Fetch the template associated with the page.
@@ -521,15 +650,23 @@ func (frontend *frontend) pageOrTemplateContent(r *http.Request, page cmsstore.P
 
 	if err != nil {
 		frontend.logger.Error("PageRenderHtmlBySiteAndAlias: Template load error", "templateID", page.TemplateID(), "error", err)
-		return "error loading template"
+		return "error loading template", ""
 	}
 
 	// If the template is not found, return the page content as is.
 	if template == nil {
-		return pageContent
+		return pageContent, ""
+	}
+
+	// Resolve the sections inherited from the parent templates, if any.
+	templateContent, err := frontend.templateResolveInheritance(r.Context(), template)
+
+	if err != nil {
+		frontend.logger.Error("PageRenderHtmlBySiteAndAlias: Template inheritance error", "templateID", page.TemplateID(), "error", err)
+		return "error loading template", ""
 	}
 
-	return template.Content()
+	return templateContent, template.Editor()
 }
 
 func (frontend *frontend) convertBlockJsonToHtml(blocksJson string) string {
@@ -553,12 +690,17 @@ func (frontend *frontend) convertBlockJsonToHtml(blocksJson string) string {
 // renderContentToHtml renders the content to HTML
 //
 // This is done in the following steps (sequence is important):
-// 1. replaces placeholders with values
-// 2. renders the blocks
-// 3. renders the shortcodes
-// 3. renders the translations
+// 0. fills the template sections, the page content may override them
+// 1. parses the content and the page content in a single pass (see parseContent)
+// 2. renders the blocks, so they can set custom variables
+// 3. resolves the placeholders, escaped with the modifier or the site
+// default escaping, i.e. [[PageTitle|attr]], the page URLs, the shortcodes
+// and the translations, in document order (see contentRenderer)
 // 4. returns the HTML
 //
+// If Config.LegacyContentRendering is set, the previous multi-pass
+// pipeline is used instead (see renderContentToHtmlLegacy).
+//
 // Parameters:
 // - r: the HTTP request
 // - content: the content to render
@@ -572,11 +714,80 @@ func (frontend *frontend) renderContentToHtml(
 	content string,
 	options TemplateRenderHtmlByIDOptions,
 ) (html string, err error) {
+	if frontend.legacyContentRendering {
+		return frontend.renderContentToHtmlLegacy(r, content, options)
+	}
+
+	// Fill the template sections. Sections defined in the page content
+	// override the template ones, the rest of the page becomes [[PageContent]]
+	pageSections, pageContent := extractTemplateSections(options.PageContent)
+	content = applyTemplateSections(content, pageSections, false)
+	options.PageContent = pageContent
+
+	// Add request to context so blocks can access it (e.g., for query parameters)
+	ctx := cmsstore.RequestToContext(r.Context(), r)
+
+	// Add vars context so blocks can set custom variables. An existing vars
+	// context is reused, so nested renderings share the same variables
+	if cmsstore.VarsFromContext(ctx) == nil {
+		ctx = cmsstore.WithVarsContext(ctx)
+	}
+
+	// Placeholders without a modifier are escaped with the site default
+	defaultEscaping, err := frontend.fetchSitePlaceholderEscaping(ctx, options.SiteID)
+
+	if err != nil {
+		return "", err
+	}
+
+	return frontend.newContentRenderer(r.WithContext(ctx), options, defaultEscaping).render(content)
+}
+
+// renderContentToHtmlLegacy renders the content to HTML with a pass per
+// directive kind
+//
+// Used when Config.LegacyContentRendering is set, and by the benchmarks
+// comparing both pipelines.
+//
+// This is done in the following steps (sequence is important):
+// 1. fills the template sections, the page content may override them
+// 2. renders the blocks, and the blocks they reference, in the template and
+// then in the page content, so the variables they set bubble up
+// (see renderBlockLegacy)
+// 3. replaces placeholders with values, escaped with the modifier or the site
+// default escaping, i.e. [[PageTitle|attr]] (see replacePlaceholders)
+// 4. renders the page URLs
+// 5. renders the shortcodes
+// 6. renders the translations
+// 7. returns the HTML
+//
+// Parameters:
+// - r: the HTTP request
+// - content: the content to render
+// - options: the options for the rendering
+//
+// Returns:
+// - html: the rendered HTML
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) renderContentToHtmlLegacy(
+	r *http.Request,
+	content string,
+	options TemplateRenderHtmlByIDOptions,
+) (html string, err error) {
+	// Fill the template sections. Sections defined in the page content
+	// override the template ones, the rest of the page becomes [[PageContent]]
+	pageSections, pageContent := extractTemplateSections(options.PageContent)
+	content = applyTemplateSections(content, pageSections, false)
+	options.PageContent = pageContent
+
 	// Add request to context so blocks can access it (e.g., for query parameters)
 	ctx := cmsstore.RequestToContext(r.Context(), r)
 
-	// Add vars context so blocks can set custom variables
-	ctx = cmsstore.WithVarsContext(ctx)
+	// Add vars context so blocks can set custom variables. An existing vars
+	// context is reused, so nested renderings share the same variables
+	if cmsstore.VarsFromContext(ctx) == nil {
+		ctx = cmsstore.WithVarsContext(ctx)
+	}
 
 	// Render blocks FIRST so they can set custom variables
 	// This allows variables to "bubble up" from blocks to the page/template level
@@ -628,26 +839,48 @@ func (frontend *frontend) renderContentToHtml(
 		"PageTitle":           options.PageTitle,
 	}
 
-	// Resolve any custom variables within the standard placeholder values
+	// Placeholders without a modifier are escaped with the site default
+	defaultEscaping, err := frontend.fetchSitePlaceholderEscaping(r.Context(), options.SiteID)
+
+	if err != nil {
+		return "", err
+	}
+
+	// Resolve any custom variables within the standard placeholder values.
+	// The page content is HTML, so the variables (and the standard values)
+	// in it are escaped like in the template. The other standard values are
+	// escaped as a whole when they are replaced below
+	pageContentReplacements := maps.Clone(allReplacements)
+	maps.Copy(pageContentReplacements, replacementsKeywords)
+	delete(pageContentReplacements, "PageContent")
+
 	for key, value := range replacementsKeywords {
-		resolvedValue := value
+		if key == "PageContent" {
+			allReplacements[key] = replacePlaceholders(value, pageContentReplacements, defaultEscaping)
+			continue
+		}
+
 		if customVars != nil {
-			for cKey, cValue := range customVars.All() {
-				resolvedValue = strings.ReplaceAll(resolvedValue, "[["+cKey+"]]", cValue)
-				resolvedValue = strings.ReplaceAll(resolvedValue, "[[ "+cKey+" ]]", cValue)
-			}
+			value = replacePlaceholders(value, customVars.All(), cmsstore.PLACEHOLDER_ESCAPING_RAW)
 		}
-		allReplacements[key] = resolvedValue
+
+		allReplacements[key] = value
 	}
 
-	// Perform all replacements in a single pass
-	for key, value := range allReplacements {
-		content = strings.ReplaceAll(content, "[["+key+"]]", value)
-		content = strings.ReplaceAll(content, "[[ "+key+" ]]", value)
+	// The JSON-LD script and the social meta tags of the page are built
+	// only when used
+	if strings.Contains(content, "[[PageJsonLd") {
+		allReplacements["PageJsonLd"] = frontend.pageJsonLd(ctx, options.SiteID)
 	}
 
-	// Block attribute syntax already applied earlier (lines 579 and 596)
-	// to ensure variables from blocks bubble up before placeholder replacement
+	if strings.Contains(content, "[[PageOpenGraph") {
+		allReplacements["PageOpenGraph"] = frontend.pageOpenGraph(ctx, options.SiteID)
+	}
+
+	// Perform all replacements in a single pass. The page content, the
+	// JSON-LD and the social meta tags are inserted raw, unless a modifier
+	// is given, i.e. [[PageContent|html]]
+	content = replacePlaceholders(content, allReplacements, defaultEscaping, "PageContent", "PageJsonLd", "PageOpenGraph")
 
 	content, err = frontend.contentRenderPageURLs(r.Context(), content)
 
@@ -821,16 +1054,31 @@ func (frontend *frontend) contentRenderPageURLByID(ctx context.Context, content
 		return content, nil
 	}
 
+	pagePath, err := frontend.fetchPageURL(ctx, pageID)
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ReplaceAll(content, "[[PAGE_URL_"+pageID+"]]", pagePath), nil
+}
+
+// fetchPageURL returns the URL path of the page specified by the ID
+//
+// Business Logic:
+// - if the page is not found an empty string is returned
+// - results are cached in memory, to not fetch the same data multiple times
+func (frontend *frontend) fetchPageURL(ctx context.Context, pageID string) (string, error) {
 	key := "page_url_" + pageID
 
 	if frontend.CacheHas(key) {
 		pageURL := frontend.CacheGet(key)
 
 		if pageURL == nil {
-			return content, nil
+			return "", nil
 		}
 
-		return strings.ReplaceAll(content, "[[PAGE_URL_"+pageID+"]]", pageURL.(string)), nil
+		return pageURL.(string), nil
 	}
 
 	page, err := frontend.store.PageFindByID(ctx, pageID)
@@ -842,14 +1090,13 @@ func (frontend *frontend) contentRenderPageURLByID(ctx context.Context, content
 
 	if page == nil {
 		frontend.CacheSet(key, "", frontend.cacheExpireSeconds)
-		// Replace with empty string if page not found
-		return strings.ReplaceAll(content, "[[PAGE_URL_"+pageID+"]]", ""), nil
+		return "", nil
 	}
 
 	pagePath := "/" + strings.TrimPrefix(page.Alias(), "/")
 	frontend.CacheSet(key, pagePath, frontend.cacheExpireSeconds)
 
-	return strings.ReplaceAll(content, "[[PAGE_URL_"+pageID+"]]", pagePath), nil
+	return pagePath, nil
 }
 
 // contentRenderTranslations renders the translations in a string
@@ -890,7 +1137,9 @@ func (frontend *frontend) contentRenderBlockByID(ctx context.Context, content st
 		return content, nil
 	}
 
-	blockContent, err := frontend.fetchBlockContent(ctx, blockID)
+	blockContent, err := frontend.renderBlockLegacy(ctx, blockID, func(ctx context.Context) (string, error) {
+		return frontend.fetchBlockContent(ctx, blockID)
+	})
 
 	if err != nil {
 		return content, err
@@ -963,6 +1212,14 @@ func (frontend *frontend) ContentRenderTranslationByHandleOrId(ctx context.Conte
 }
 
 // TemplateRenderHtmlByID builds the HTML of a template based on its ID
+//
+// If the template extends a parent template, the sections are resolved
+// through the whole inheritance chain. An error is returned if the chain
+// contains a cycle or a parent template is missing.
+//
+// Templates with editor cmsstore.TEMPLATE_EDITOR_GOTEMPLATE are rendered
+// with Go's html/template engine (see GoTemplateData), all other templates
+// use [[Placeholder]] replacement.
 func (frontend *frontend) TemplateRenderHtmlByID(
 	r *http.Request,
 	templateID string,
@@ -986,9 +1243,17 @@ func (frontend *frontend) TemplateRenderHtmlByID(
 		return "", errors.New("template " + templateID + " is not active")
 	}
 
-	content := template.Content()
+	content, err := frontend.templateResolveInheritance(r.Context(), template)
+
+	if err != nil {
+		return "", err
+	}
+
+	if options.SiteID == "" {
+		options.SiteID = template.SiteID()
+	}
 
-	html, err := frontend.renderContentToHtml(r, content, options)
+	html, err := frontend.renderTemplateContentToHtml(r, content, template.Editor(), options)
 
 	if err != nil {
 		return "", err
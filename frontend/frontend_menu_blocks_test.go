@@ -2,8 +2,11 @@ package frontend
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/dracory/cmsstore"
@@ -131,6 +134,85 @@ func TestRenderMenuBlock(t *testing.T) {
 	}
 }
 
+func TestRenderMenuBlock_CachedPerPath(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	menu := cmsstore.NewMenu().
+		SetName("Cached Menu").
+		SetSiteID(testutils.SITE_01).
+		SetStatus(cmsstore.MENU_STATUS_ACTIVE)
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, path := range []string{"/one", "/two"} {
+		menuItem := cmsstore.NewMenuItem().
+			SetName("Item " + path).
+			SetMenuID(menu.ID()).
+			SetURL(path).
+			SetSequenceInt(i + 1).
+			SetStatus(cmsstore.MENU_ITEM_STATUS_ACTIVE)
+
+		if err := store.MenuItemCreate(ctx, menuItem); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	block := cmsstore.NewBlock().
+		SetSiteID(testutils.SITE_01).
+		SetType(cmsstore.BLOCK_TYPE_MENU).
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := block.SetMeta(cmsstore.BLOCK_META_MENU_ID, menu.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.BlockCreate(ctx, block); err != nil {
+		t.Fatal(err)
+	}
+
+	fe := New(Config{
+		Store:              store,
+		Logger:             slog.New(slog.NewTextHandler(io.Discard, nil)),
+		CacheEnabled:       true,
+		CacheExpireSeconds: 60,
+	}).(*frontend)
+
+	render := func(target string) string {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+
+		content, err := fe.fetchBlockContent(cmsstore.RequestToContext(r.Context(), r), block.ID())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return content
+	}
+
+	for _, path := range []string{"/one", "/two", "/one"} {
+		content := render(path)
+
+		if !strings.Contains(content, `href="`+path+`"`) || !strings.Contains(content, `aria-current="page"`) {
+			t.Fatalf("Expected the %s item to be active, got: %s", path, content)
+		}
+
+		// The link marked as the current page
+		index := strings.Index(content, `aria-current="page"`)
+		active := content[strings.LastIndex(content[:index], "<a"):]
+		active = active[:strings.Index(active, ">")]
+
+		if !strings.Contains(active, `href="`+path+`"`) {
+			t.Errorf("Expected the %s item to be active, got: %s", path, content)
+		}
+	}
+}
+
 func TestRenderHTMLBlock(t *testing.T) {
 	store, err := testutils.InitStore(":memory:")
 	if err != nil {
@@ -199,7 +281,7 @@ func TestBlockTypeDispatcher(t *testing.T) {
 	htmlBlock.SetType(cmsstore.BLOCK_TYPE_HTML)
 	htmlBlock.SetContent("<p>HTML Content</p>")
 
-	content, err := f.renderBlockByType(ctx, htmlBlock)
+	content, err := f.renderBlockByType(ctx, htmlBlock, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -213,7 +295,7 @@ func TestBlockTypeDispatcher(t *testing.T) {
 	emptyBlock.SetType("")
 	emptyBlock.SetContent("<p>Default Content</p>")
 
-	content, err = f.renderBlockByType(ctx, emptyBlock)
+	content, err = f.renderBlockByType(ctx, emptyBlock, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,50 @@
+package frontend
+
+import (
+	"context"
+
+	"github.com/dracory/cmsstore"
+)
+
+// pageJsonLd returns the JSON-LD script of the page being rendered, as
+// inserted by the [[PageJsonLd]] placeholder (see cmsstore.PageJsonLd)
+//
+// Business Logic:
+//   - empty when rendering without a page (i.e. a template preview)
+//   - the errors are logged, and an empty script returned, so invalid
+//     structured data does not break the page
+func (frontend *frontend) pageJsonLd(ctx context.Context, siteID string) string {
+	page := cmsstore.PageFromContext(ctx)
+
+	if page == nil {
+		return ""
+	}
+
+	var site cmsstore.SiteInterface
+
+	if siteID != "" {
+		var err error
+		site, err = frontend.store.SiteFindByID(ctx, siteID)
+
+		if err != nil {
+			frontend.logJsonLdError(page, err)
+			return ""
+		}
+	}
+
+	script, err := cmsstore.PageJsonLd(ctx, site, page, cmsstore.PageAncestorsFromContext(ctx))
+
+	if err != nil {
+		frontend.logJsonLdError(page, err)
+		return ""
+	}
+
+	return script
+}
+
+// logJsonLdError logs the error of the JSON-LD of the page
+func (frontend *frontend) logJsonLdError(page cmsstore.PageInterface, err error) {
+	if frontend.logger != nil {
+		frontend.logger.Warn("PageJsonLd: structured data not rendered", "pageID", page.ID(), "error", err)
+	}
+}
@@ -0,0 +1,85 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+func TestTemplateRenderHtmlByID_PageJsonLd(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site := cmsstore.NewSite().
+		SetName("Json Site").
+		SetStatus(cmsstore.SITE_STATUS_ACTIVE)
+
+	if err := store.SiteCreate(context.Background(), site); err != nil {
+		t.Fatalf("Failed to create site: %v", err)
+	}
+
+	template := cmsstore.NewTemplate().
+		SetSiteID(site.ID()).
+		SetContent(`<head>[[PageJsonLd]]</head><p>[[PageJsonLd|html]]</p>`).
+		SetStatus(cmsstore.TEMPLATE_STATUS_ACTIVE)
+
+	if err := store.TemplateCreate(context.Background(), template); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	page := cmsstore.NewPage().
+		SetSiteID(site.ID()).
+		SetName("About").
+		SetAlias("/about")
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	req := httptest.NewRequest("GET", "http://example.com/about", nil)
+	req = req.WithContext(cmsstore.PageToContext(req.Context(), page))
+
+	html, err := f.TemplateRenderHtmlByID(req, template.ID(), TemplateRenderHtmlByIDOptions{
+		SiteID: site.ID(),
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.Contains(html, `<head><script type="application/ld+json">`) {
+		t.Errorf("Expected the raw JSON-LD script, got %q", html)
+	}
+
+	if !strings.Contains(html, `<p>&lt;script type=&#34;application/ld+json&#34;&gt;`) {
+		t.Errorf("Expected the escaped JSON-LD script with the html modifier, got %q", html)
+	}
+
+	for _, expected := range []string{`"@type":"Organization"`, `"name":"Json Site"`, `"@type":"WebPage"`, `"url":"http://example.com/about"`} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected %s in the JSON-LD, got %q", expected, html)
+		}
+	}
+
+	// Without a page, i.e. a template preview, nothing is rendered
+	html, err = f.TemplateRenderHtmlByID(httptest.NewRequest("GET", "/", nil), template.ID(), TemplateRenderHtmlByIDOptions{
+		SiteID: site.ID(),
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if html != `<head></head><p></p>` {
+		t.Errorf("Expected an empty JSON-LD, got %q", html)
+	}
+}
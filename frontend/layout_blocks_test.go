@@ -0,0 +1,59 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+func TestLayoutBlocks_RenderNestedChildren(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	newBlock := func(blockType, parentID string, sequence int, content string) cmsstore.BlockInterface {
+		block := cmsstore.NewBlock().
+			SetSiteID(testutils.SITE_01).
+			SetType(blockType).
+			SetParentID(parentID).
+			SetSequenceInt(sequence).
+			SetContent(content).
+			SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+		if err := store.BlockCreate(context.Background(), block); err != nil {
+			t.Fatalf("Failed to create block: %v", err)
+		}
+
+		return block
+	}
+
+	row := newBlock(cmsstore.BLOCK_TYPE_ROW, "", 0, "")
+	right := newBlock(cmsstore.BLOCK_TYPE_COLUMN, row.ID(), 1, "")
+	left := newBlock(cmsstore.BLOCK_TYPE_COLUMN, row.ID(), 0, "")
+	newBlock(cmsstore.BLOCK_TYPE_HTML, left.ID(), 0, "<p>Left</p>")
+	newBlock(cmsstore.BLOCK_TYPE_HTML, right.ID(), 0, "<p>Right</p>")
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}).(*frontend)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	html, err := f.renderContentToHtml(req, "[[BLOCK_"+row.ID()+"]]", TemplateRenderHtmlByIDOptions{})
+	if err != nil {
+		t.Fatalf("Failed to render: %v", err)
+	}
+
+	expected := `<div class="row"><div class="col"><p>Left</p></div><div class="col"><p>Right</p></div></div>`
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"encoding/base64"
 	"errors"
 	"net/http"
+	"strconv"
 	"strings"
+
+	"github.com/dracory/cmsstore"
 )
 
 // MediaHandler serves CMS media files via /cms/media/{mediaId}.{ext}
@@ -41,6 +44,18 @@ func (frontend *frontend) MediaHandler(w http.ResponseWriter, r *http.Request) s
 
 	url := media.URL()
 
+	// Resized variant of an uploaded image, i.e. /cms/media/<id>.jpg?w=480
+	if width := r.URL.Query().Get("w"); width != "" {
+		variant := media.Meta(cmsstore.MEDIA_META_VARIANT_PREFIX + width)
+
+		if !strings.HasPrefix(variant, "data:") {
+			w.WriteHeader(http.StatusNotFound)
+			return "Media not found"
+		}
+
+		return frontend.serveDataURI(w, r, variant, media)
+	}
+
 	// Data URI — decode base64 and serve directly
 	if strings.HasPrefix(url, "data:") {
 		return frontend.serveDataURI(w, r, url, media)
@@ -73,10 +88,15 @@ func (frontend *frontend) serveDataURI(w http.ResponseWriter, r *http.Request, d
 		contentType = mimeTypeFromExtension(media.Extension())
 	}
 
+	etag := media.ID()
+	if width := r.URL.Query().Get("w"); width != "" {
+		etag += "-" + width
+	}
+
 	w.Header().Set("Content-Type", contentType)
-	w.Header().Set("Content-Length", media.Size())
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
 	w.Header().Set("Cache-Control", "public, max-age=31536000")
-	w.Header().Set("ETag", media.ID())
+	w.Header().Set("ETag", etag)
 
 	if _, err := w.Write(content); err != nil {
 		return "Failed to write media content: " + err.Error()
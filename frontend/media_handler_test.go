@@ -3,6 +3,7 @@ package frontend
 import (
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	_ "modernc.org/sqlite"
@@ -134,6 +135,70 @@ func TestMediaHandler_DataURI(t *testing.T) {
 	}
 }
 
+func TestMediaHandler_Variant(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	variant := "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+
+	media := cmsstore.NewMedia().
+		SetID("variant123").
+		SetExtension(".png").
+		SetType("image/png").
+		SetURL("data:image/png;base64,AAAA").
+		SetStatus(cmsstore.MEDIA_STATUS_ACTIVE)
+
+	if err := media.SetMetas(map[string]string{
+		cmsstore.MEDIA_META_VARIANT_PREFIX + "480": "data:image/png;base64," + variant,
+		cmsstore.MEDIA_META_VARIANT_PREFIX + "960": "https://cdn.example.com/variant-960.png",
+	}); err != nil {
+		t.Fatalf("Failed to set metas: %v", err)
+	}
+
+	if err := store.MediaCreate(nil, media); err != nil {
+		t.Fatalf("Failed to create media: %v", err)
+	}
+
+	f := New(Config{Store: store})
+
+	req := httptest.NewRequest("GET", "/cms/media/variant123.png?w=480", nil)
+	recorder := httptest.NewRecorder()
+
+	f.Handler(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	expected, _ := decodeDataURL("data:image/png;base64," + variant)
+
+	if recorder.Body.String() != string(expected) {
+		t.Error("expected the variant content")
+	}
+
+	if recorder.Header().Get("Content-Length") != strconv.Itoa(len(expected)) {
+		t.Errorf("expected Content-Length %d, got %s", len(expected), recorder.Header().Get("Content-Length"))
+	}
+
+	if recorder.Header().Get("ETag") != "variant123-480" {
+		t.Errorf("expected ETag variant123-480, got %s", recorder.Header().Get("ETag"))
+	}
+
+	// Only the uploaded variants are served, the others are linked directly
+	for _, width := range []string{"960", "123"} {
+		req := httptest.NewRequest("GET", "/cms/media/variant123.png?w="+width, nil)
+		recorder := httptest.NewRecorder()
+
+		f.Handler(recorder, req)
+
+		if recorder.Code != http.StatusNotFound {
+			t.Errorf("expected status %d for width %s, got %d", http.StatusNotFound, width, recorder.Code)
+		}
+	}
+}
+
 func TestMediaHandler_NotFound(t *testing.T) {
 	store, err := testutils.InitStore(":memory:")
 	if err != nil {
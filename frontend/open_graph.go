@@ -0,0 +1,60 @@
+package frontend
+
+import (
+	"context"
+
+	"github.com/dracory/cmsstore"
+)
+
+// pageOpenGraph returns the Open Graph and Twitter card meta tags of the
+// page being rendered, as inserted by the [[PageOpenGraph]] placeholder
+// (see cmsstore.PageOpenGraph)
+//
+// Business Logic:
+//   - empty when rendering without a page (i.e. a template preview)
+//   - the image is looked up only when the media is enabled
+//   - the errors are logged, and the available tags returned, so a
+//     missing site or image does not break the page
+func (frontend *frontend) pageOpenGraph(ctx context.Context, siteID string) string {
+	page := cmsstore.PageFromContext(ctx)
+
+	if page == nil {
+		return ""
+	}
+
+	var site cmsstore.SiteInterface
+	var image cmsstore.MediaInterface
+	var err error
+
+	if siteID != "" {
+		site, err = frontend.store.SiteFindByID(ctx, siteID)
+
+		if err != nil {
+			frontend.logOpenGraphError(page, err)
+		}
+	}
+
+	if mediaID := page.OpenGraphImageMediaID(); mediaID != "" && frontend.store.MediaEnabled() {
+		image, err = frontend.store.MediaFindByID(ctx, mediaID)
+
+		if err != nil {
+			frontend.logOpenGraphError(page, err)
+		}
+	}
+
+	tags, err := cmsstore.PageOpenGraph(ctx, site, page, image)
+
+	if err != nil {
+		frontend.logOpenGraphError(page, err)
+		return ""
+	}
+
+	return tags
+}
+
+// logOpenGraphError logs the error of the Open Graph tags of the page
+func (frontend *frontend) logOpenGraphError(page cmsstore.PageInterface, err error) {
+	if frontend.logger != nil {
+		frontend.logger.Warn("PageOpenGraph: social tags incomplete", "pageID", page.ID(), "error", err)
+	}
+}
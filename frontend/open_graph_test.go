@@ -0,0 +1,96 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+func TestTemplateRenderHtmlByID_PageOpenGraph(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site := cmsstore.NewSite().
+		SetName("Social Site").
+		SetStatus(cmsstore.SITE_STATUS_ACTIVE)
+
+	if err := store.SiteCreate(context.Background(), site); err != nil {
+		t.Fatalf("Failed to create site: %v", err)
+	}
+
+	image := cmsstore.NewMedia().
+		SetType("image/jpeg").
+		SetExtension("jpg").
+		SetURL("https://cdn.example.com/cover.jpg").
+		SetStatus(cmsstore.MEDIA_STATUS_ACTIVE)
+
+	if err := store.MediaCreate(context.Background(), image); err != nil {
+		t.Fatalf("Failed to create media: %v", err)
+	}
+
+	template := cmsstore.NewTemplate().
+		SetSiteID(site.ID()).
+		SetContent(`<head>[[PageOpenGraph]]</head>`).
+		SetStatus(cmsstore.TEMPLATE_STATUS_ACTIVE)
+
+	if err := store.TemplateCreate(context.Background(), template); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	page := cmsstore.NewPage().
+		SetSiteID(site.ID()).
+		SetName("About").
+		SetTitle(`About "us"`).
+		SetAlias("/about").
+		SetOpenGraphImageMediaID(image.ID())
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	req := httptest.NewRequest("GET", "http://example.com/about", nil)
+	req = req.WithContext(cmsstore.PageToContext(req.Context(), page))
+
+	html, err := f.TemplateRenderHtmlByID(req, template.ID(), TemplateRenderHtmlByIDOptions{
+		SiteID: site.ID(),
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		`<head><meta property="og:title" content="About &#34;us&#34;">`,
+		`<meta property="og:image" content="http://example.com/cms/media/` + image.ID() + `.jpg">`,
+		`<meta property="og:site_name" content="Social Site">`,
+		`<meta name="twitter:card" content="summary_large_image">`,
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(html, e) {
+			t.Errorf("Expected %s, got %q", e, html)
+		}
+	}
+
+	// Without a page, i.e. a template preview, nothing is rendered
+	html, err = f.TemplateRenderHtmlByID(httptest.NewRequest("GET", "/", nil), template.ID(), TemplateRenderHtmlByIDOptions{
+		SiteID: site.ID(),
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if html != `<head></head>` {
+		t.Errorf("Expected no social tags, got %q", html)
+	}
+}
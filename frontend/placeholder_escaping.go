@@ -0,0 +1,128 @@
+package frontend
+
+import (
+	"context"
+	"html"
+	"html/template"
+	"net/url"
+	"regexp"
+
+	"github.com/dracory/cmsstore"
+)
+
+// Package-level compiled regex for performance
+// Matches: [[key]], [[ key ]] and [[key|modifier]]
+var placeholderPattern = regexp.MustCompile(`\[\[\s*([^\[\]|]+?)\s*(?:\|\s*([a-z]+)\s*)?\]\]`)
+
+// escapePlaceholderValue escapes the value for the given escaping mode
+//
+// Supported modes:
+//   - html: escapes the value for HTML text
+//   - attr: escapes the value for a quoted HTML attribute
+//   - js: escapes the value for a JavaScript string
+//   - url: escapes the value for a URL query parameter
+//   - raw: inserts the value as is
+//
+// Returns false if the mode is not supported.
+func escapePlaceholderValue(value string, mode string) (string, bool) {
+	switch mode {
+	case cmsstore.PLACEHOLDER_ESCAPING_HTML, cmsstore.PLACEHOLDER_ESCAPING_ATTR:
+		return html.EscapeString(value), true
+	case cmsstore.PLACEHOLDER_ESCAPING_JS:
+		return template.JSEscapeString(value), true
+	case cmsstore.PLACEHOLDER_ESCAPING_URL:
+		return url.QueryEscape(value), true
+	case cmsstore.PLACEHOLDER_ESCAPING_RAW:
+		return value, true
+	}
+
+	return "", false
+}
+
+// replacePlaceholders replaces the placeholders in the content in a single pass
+//
+// Business Logic:
+//   - [[key]] is replaced by the value escaped with defaultEscaping
+//   - [[key|modifier]] is replaced by the value escaped with the modifier
+//   - keys listed in rawKeys are not escaped unless a modifier is given
+//     (i.e. PageContent, which is HTML)
+//   - placeholders with unknown keys or modifiers are left untouched
+//
+// Parameters:
+// - content: the content to process
+// - replacements: the placeholder values, by key
+// - defaultEscaping: the escaping used when no modifier is given
+// - rawKeys: the keys inserted raw by default
+//
+// Returns:
+// - content: the content with the placeholders replaced
+func replacePlaceholders(content string, replacements map[string]string, defaultEscaping string, rawKeys ...string) string {
+	if len(replacements) == 0 {
+		return content
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(content, func(placeholder string) string {
+		match := placeholderPattern.FindStringSubmatch(placeholder)
+		key, modifier := match[1], match[2]
+
+		value, exists := replacements[key]
+
+		if !exists {
+			return placeholder
+		}
+
+		if modifier == "" {
+			modifier = defaultEscaping
+
+			for _, rawKey := range rawKeys {
+				if key == rawKey {
+					modifier = cmsstore.PLACEHOLDER_ESCAPING_RAW
+				}
+			}
+		}
+
+		escaped, supported := escapePlaceholderValue(value, modifier)
+
+		if !supported {
+			return placeholder
+		}
+
+		return escaped
+	})
+}
+
+// fetchSitePlaceholderEscaping returns the default placeholder escaping of the site
+//
+// Business Logic:
+//   - if the site ID is empty, raw escaping is returned
+//   - if the site has no escaping meta (legacy site), raw escaping is returned
+//   - results are cached in memory, to not fetch the same data multiple times
+func (frontend *frontend) fetchSitePlaceholderEscaping(ctx context.Context, siteID string) (string, error) {
+	if siteID == "" {
+		return cmsstore.PLACEHOLDER_ESCAPING_RAW, nil
+	}
+
+	cacheKey := "site_placeholder_escaping:" + siteID
+
+	if frontend.CacheHas(cacheKey) {
+		if escaping, ok := frontend.CacheGet(cacheKey).(string); ok {
+			return escaping, nil
+		}
+	}
+
+	site, err := frontend.store.SiteFindByID(ctx, siteID)
+
+	if err != nil {
+		return "", err
+	}
+
+	escaping := cmsstore.PLACEHOLDER_ESCAPING_RAW
+
+	if site != nil && site.Meta(cmsstore.SITE_META_PLACEHOLDER_ESCAPING) != "" {
+		escaping = site.Meta(cmsstore.SITE_META_PLACEHOLDER_ESCAPING)
+	}
+
+	frontend.CacheSet(cacheKey, escaping, frontend.cacheExpireSeconds)
+
+	return escaping, nil
+}
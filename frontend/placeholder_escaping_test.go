@@ -0,0 +1,162 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+func TestReplacePlaceholders_Modifiers(t *testing.T) {
+	replacements := map[string]string{
+		"PageTitle":   `Tom & "Jerry" <b>`,
+		"PageContent": `<p>Body</p>`,
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"default", `[[PageTitle]]`, `Tom &amp; &#34;Jerry&#34; &lt;b&gt;`},
+		{"spaces", `[[ PageTitle ]]`, `Tom &amp; &#34;Jerry&#34; &lt;b&gt;`},
+		{"attr", `<a title="[[PageTitle|attr]]">`, `<a title="Tom &amp; &#34;Jerry&#34; &lt;b&gt;">`},
+		{"js", `var t = '[[PageTitle|js]]';`, `var t = 'Tom \u0026 \"Jerry\" \u003Cb\u003E';`},
+		{"url", `/search?q=[[PageTitle|url]]`, `/search?q=Tom+%26+%22Jerry%22+%3Cb%3E`},
+		{"raw", `[[PageTitle|raw]]`, `Tom & "Jerry" <b>`},
+		{"raw key", `[[PageContent]]`, `<p>Body</p>`},
+		{"raw key with modifier", `[[PageContent|html]]`, `&lt;p&gt;Body&lt;/p&gt;`},
+		{"unknown key", `[[Unknown]]`, `[[Unknown]]`},
+		{"unknown modifier", `[[PageTitle|bold]]`, `[[PageTitle|bold]]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := replacePlaceholders(tt.content, replacements, cmsstore.PLACEHOLDER_ESCAPING_HTML, "PageContent")
+
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestReplacePlaceholders_SinglePass(t *testing.T) {
+	// Values containing placeholders must not be replaced again
+	result := replacePlaceholders(`[[A]]`, map[string]string{
+		"A": "[[B]]",
+		"B": "b",
+	}, cmsstore.PLACEHOLDER_ESCAPING_RAW)
+
+	if result != "[[B]]" {
+		t.Errorf("Expected %q, got %q", "[[B]]", result)
+	}
+}
+
+func TestTemplateRenderHtmlByID_PlaceholderEscaping(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site := cmsstore.NewSite().
+		SetName("Escaping Site").
+		SetStatus(cmsstore.SITE_STATUS_ACTIVE)
+
+	if err := store.SiteCreate(context.Background(), site); err != nil {
+		t.Fatalf("Failed to create site: %v", err)
+	}
+
+	template := cmsstore.NewTemplate().
+		SetSiteID(site.ID()).
+		SetContent(`<title>[[PageTitle]]</title><meta content="[[PageMetaDescription|attr]]"><main>[[PageContent]]</main>`).
+		SetStatus(cmsstore.TEMPLATE_STATUS_ACTIVE)
+
+	if err := store.TemplateCreate(context.Background(), template); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.TemplateRenderHtmlByID(req, template.ID(), TemplateRenderHtmlByIDOptions{
+		SiteID:              site.ID(),
+		PageTitle:           `<script>alert(1)</script>`,
+		PageMetaDescription: `Say "hi"`,
+		PageContent:         `<p>[[PageTitle]]</p>`,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `<title>&lt;script&gt;alert(1)&lt;/script&gt;</title>` +
+		`<meta content="Say &#34;hi&#34;">` +
+		`<main><p>&lt;script&gt;alert(1)&lt;/script&gt;</p></main>`
+
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+}
+
+func TestTemplateRenderHtmlByID_PlaceholderEscaping_LegacySite(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site := cmsstore.NewSite().
+		SetName("Legacy Site").
+		SetStatus(cmsstore.SITE_STATUS_ACTIVE)
+
+	if err := store.SiteCreate(context.Background(), site); err != nil {
+		t.Fatalf("Failed to create site: %v", err)
+	}
+
+	// Sites created before escaping was introduced have no escaping meta
+	if err := site.SetMetas(map[string]string{}); err != nil {
+		t.Fatalf("Failed to clear metas: %v", err)
+	}
+
+	if err := store.SiteUpdate(context.Background(), site); err != nil {
+		t.Fatalf("Failed to update site: %v", err)
+	}
+
+	template := cmsstore.NewTemplate().
+		SetSiteID(site.ID()).
+		SetContent(`<title>[[PageTitle]]</title><a href="?q=[[PageTitle|url]]">`).
+		SetStatus(cmsstore.TEMPLATE_STATUS_ACTIVE)
+
+	if err := store.TemplateCreate(context.Background(), template); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.TemplateRenderHtmlByID(req, template.ID(), TemplateRenderHtmlByIDOptions{
+		PageTitle: `<b>Bold</b>`,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `<title><b>Bold</b></title><a href="?q=%3Cb%3EBold%3C%2Fb%3E">`
+
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+}
@@ -0,0 +1,118 @@
+package frontend
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dracory/cmsstore"
+	"github.com/samber/lo"
+)
+
+// blockMaxDepthDefault is the default maximum number of nested blocks
+const blockMaxDepthDefault = 10
+
+// blockRenderStackError is returned when a block cannot be rendered
+// because of its position in the render stack
+type blockRenderStackError struct {
+	// reason is the reason, i.e. "cycle detected"
+	reason string
+
+	// path is the render stack, including the rejected block
+	path []string
+}
+
+func (e *blockRenderStackError) Error() string {
+	return "block " + e.reason + ": " + strings.Join(e.path, " -> ")
+}
+
+// enterBlock pushes the block on the render stack of the context
+//
+// Business Logic:
+//   - fails if the block is already being rendered (cycle), i.e. a block
+//     referencing itself, or two blocks referencing each other
+//   - fails if the render stack is already at the maximum depth
+//
+// Parameters:
+// - ctx: the context, with the current render stack
+// - blockID: the ID of the block about to be rendered
+//
+// Returns:
+// - ctx: the context to render the block (and its output) with
+// - err: a *blockRenderStackError, if any, or nil otherwise
+func (frontend *frontend) enterBlock(ctx context.Context, blockID string) (context.Context, error) {
+	stack := cmsstore.RenderStackFromContext(ctx)
+	path := append(slices.Clone(stack), blockID)
+
+	if slices.Contains(stack, blockID) {
+		return ctx, &blockRenderStackError{reason: "cycle detected", path: path}
+	}
+
+	maxDepth := lo.If(frontend.blockMaxDepth > 0, frontend.blockMaxDepth).Else(blockMaxDepthDefault)
+
+	if len(stack) >= maxDepth {
+		return ctx, &blockRenderStackError{reason: "max depth of " + strconv.Itoa(maxDepth) + " exceeded", path: path}
+	}
+
+	return cmsstore.WithRenderStackBlock(ctx, blockID), nil
+}
+
+// blockRenderStackErrorHtml logs the render stack error, and returns the
+// HTML replacing the rejected block: an inline comment in debug mode, or
+// an empty string otherwise
+func (frontend *frontend) blockRenderStackErrorHtml(err *blockRenderStackError) string {
+	if frontend.logger != nil {
+		frontend.logger.Error("Block render: "+err.reason, "path", strings.Join(err.path, " -> "))
+	}
+
+	if !frontend.debug {
+		return ""
+	}
+
+	// "--" is not allowed in HTML comments
+	return "<!-- " + strings.ReplaceAll(err.Error(), "--", "- -") + " -->"
+}
+
+// renderBlockLegacy renders the block referenced in the content of the
+// legacy pipeline (see renderContentToHtmlLegacy), within the render stack
+//
+// Business Logic:
+//   - the block is pushed on the render stack (see enterBlock), a block
+//     found in a cycle or beyond the maximum depth is not rendered
+//   - the blocks referenced in the rendered block are rendered within it,
+//     so the render stack detects their cycles
+//
+// Parameters:
+// - ctx: the context, with the current render stack
+// - blockID: the ID of the referenced block
+// - render: renders the block with the context of the block
+//
+// Returns:
+// - html: the rendered block
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) renderBlockLegacy(ctx context.Context, blockID string, render func(ctx context.Context) (string, error)) (string, error) {
+	if blockID == "" {
+		return render(ctx)
+	}
+
+	blockCtx, err := frontend.enterBlock(ctx, blockID)
+
+	if stackErr, ok := err.(*blockRenderStackError); ok {
+		return frontend.blockRenderStackErrorHtml(stackErr), nil
+	}
+
+	html, err := render(blockCtx)
+
+	if err != nil {
+		return html, err
+	}
+
+	html, err = frontend.contentRenderBlocks(blockCtx, html)
+
+	if err != nil {
+		return html, err
+	}
+
+	return frontend.applyBlockAttributeSyntax(blockCtx, cmsstore.RequestFromContext(blockCtx), html)
+}
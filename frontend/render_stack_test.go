@@ -0,0 +1,193 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+)
+
+// setBlockContent is a helper updating the content of a block
+func setBlockContent(t *testing.T, store cmsstore.StoreInterface, block cmsstore.BlockInterface, content string) {
+	block.SetContent(content)
+
+	if err := store.BlockUpdate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to update block: %v", err)
+	}
+}
+
+func TestRenderContentToHtml_BlockSelfReference(t *testing.T) {
+	f, store := setupContentRendererTest(t, Config{})
+
+	block := createContentBlock(t, store, "")
+	setBlockContent(t, store, block, "x[[BLOCK_"+block.ID()+"]]")
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `[[BLOCK_`+block.ID()+`]]`, TemplateRenderHtmlByIDOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if html != "x" {
+		t.Errorf("Expected the cycle to be cut without output, got %q", html)
+	}
+}
+
+func TestRenderContentToHtml_BlockCycle_Debug(t *testing.T) {
+	var logs bytes.Buffer
+
+	f, store := setupContentRendererTest(t, Config{
+		Debug: true,
+	})
+
+	f.logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+	blockA := createContentBlock(t, store, "")
+	blockB := createContentBlock(t, store, "")
+
+	setBlockContent(t, store, blockA, `A<block id="`+blockB.ID()+`" />`)
+	setBlockContent(t, store, blockB, `B[[BLOCK_`+blockA.ID()+`]]`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `[[BLOCK_`+blockA.ID()+`]]`, TemplateRenderHtmlByIDOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	path := blockA.ID() + " -> " + blockB.ID() + " -> " + blockA.ID()
+	expected := "AB<!-- block cycle detected: " + path + " -->"
+
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+
+	if !strings.Contains(logs.String(), "cycle detected") || !strings.Contains(logs.String(), path) {
+		t.Errorf("Expected the cycle path to be logged, got %q", logs.String())
+	}
+}
+
+func TestRenderContentToHtml_BlockMaxDepth(t *testing.T) {
+	f, store := setupContentRendererTest(t, Config{
+		BlockMaxDepth: 2,
+		Debug:         true,
+	})
+
+	block3 := createContentBlock(t, store, "3")
+	block2 := createContentBlock(t, store, "2[[BLOCK_"+block3.ID()+"]]")
+	block1 := createContentBlock(t, store, "1[[BLOCK_"+block2.ID()+"]]")
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `[[BLOCK_`+block1.ID()+`]]`, TemplateRenderHtmlByIDOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "12<!-- block max depth of 2 exceeded: " + block1.ID() + " -> " + block2.ID() + " -> " + block3.ID() + " -->"
+
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+}
+
+func TestRenderContentToHtml_BlockRepeatedNotCycle(t *testing.T) {
+	f, store := setupContentRendererTest(t, Config{})
+
+	inner := createContentBlock(t, store, "i")
+	outer := createContentBlock(t, store, "[[BLOCK_"+inner.ID()+"]][[BLOCK_"+inner.ID()+"]]")
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `[[BLOCK_`+outer.ID()+`]][[BLOCK_`+inner.ID()+`]]`, TemplateRenderHtmlByIDOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if html != "iii" {
+		t.Errorf("Expected sibling blocks to render, got %q", html)
+	}
+}
+
+func TestRenderContentToHtmlLegacy_BlockCycle(t *testing.T) {
+	f, store := setupContentRendererTest(t, Config{
+		LegacyContentRendering: true,
+		Debug:                  true,
+	})
+
+	blockA := createContentBlock(t, store, "")
+	blockB := createContentBlock(t, store, "")
+
+	setBlockContent(t, store, blockA, `A<block id="`+blockB.ID()+`" />`)
+	setBlockContent(t, store, blockB, `B[[BLOCK_`+blockA.ID()+`]]`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `[[BLOCK_`+blockA.ID()+`]]`, TemplateRenderHtmlByIDOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "AB<!-- block cycle detected: " + blockA.ID() + " -> " + blockB.ID() + " -> " + blockA.ID() + " -->"
+
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+}
+
+func TestRenderContentToHtmlLegacy_BlockMaxDepth(t *testing.T) {
+	f, store := setupContentRendererTest(t, Config{
+		LegacyContentRendering: true,
+		BlockMaxDepth:          2,
+	})
+
+	block3 := createContentBlock(t, store, "3")
+	block2 := createContentBlock(t, store, `2<block id="`+block3.ID()+`" />`)
+	block1 := createContentBlock(t, store, "1[[BLOCK_"+block2.ID()+"]]")
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.renderContentToHtml(req, `[[BLOCK_`+block1.ID()+`]]`, TemplateRenderHtmlByIDOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if html != "12" {
+		t.Errorf("Expected the blocks beyond the maximum depth not to render, got %q", html)
+	}
+}
+
+func TestEnterBlock_RenderStackInContext(t *testing.T) {
+	f, _ := setupContentRendererTest(t, Config{})
+
+	ctx, err := f.enterBlock(context.Background(), "a")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx, err = f.enterBlock(ctx, "b")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if stack := cmsstore.RenderStackFromContext(ctx); strings.Join(stack, ",") != "a,b" {
+		t.Errorf("Expected render stack [a b], got %v", stack)
+	}
+
+	if _, err := f.enterBlock(ctx, "a"); err == nil {
+		t.Error("Expected a cycle error")
+	}
+}
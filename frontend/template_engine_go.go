@@ -0,0 +1,328 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/http"
+
+	"github.com/dracory/cmsstore"
+	"github.com/samber/lo"
+)
+
+// GoTemplateData is the data available to templates rendered with the Go
+// template engine (templates with editor cmsstore.TEMPLATE_EDITOR_GOTEMPLATE).
+//
+// Example template:
+//
+//	<title>{{ .Page.Title }}</title>
+//	<meta name="description" content="{{ .Page.MetaDescription }}">
+//	{{ if .Site }}<h1>{{ .Site.Name }}</h1>{{ end }}
+//	<main>{{ .Page.Content }}</main>
+//	{{ if .Vars.blog_title }}<h2>{{ .Vars.blog_title }}</h2>{{ end }}
+//
+// All values are escaped by html/template according to their context,
+// except Page.Content and the output of blockContent, pageJsonLd and
+// pageOpenGraph, which are trusted HTML.
+type GoTemplateData struct {
+	// Page holds the page fields
+	Page GoTemplatePage
+
+	// Site holds the fields of the site being rendered, nil if unknown
+	Site *GoTemplateSite
+
+	// Vars holds the custom variables set by the blocks in the page content
+	Vars map[string]string
+
+	// Language is the language code used for rendering
+	Language string
+}
+
+// GoTemplatePage holds the page fields available to Go templates
+type GoTemplatePage struct {
+	// ID is the page ID, empty when rendering a template without a page
+	ID string
+
+	// Alias is the page alias, empty when rendering a template without a page
+	Alias string
+
+	// Content is the rendered page content (blocks, shortcodes and
+	// translations already applied)
+	Content template.HTML
+
+	CanonicalURL    string
+	MetaDescription string
+	MetaKeywords    string
+	MetaRobots      string
+	Title           string
+
+	// Metas are the page metas, empty when rendering a template without a page
+	Metas map[string]string
+}
+
+// GoTemplateSite holds the site fields available to Go templates,
+// a read-only copy so templates cannot call the site setters
+type GoTemplateSite struct {
+	ID          string
+	Handle      string
+	Name        string
+	DomainNames []string
+}
+
+// renderTemplateContentToHtml renders the content using the engine
+// selected by the template editor
+//
+// Parameters:
+// - r: the HTTP request
+// - content: the template content to render
+// - editor: the editor of the template, selects the engine
+// - options: the options for the rendering
+//
+// Returns:
+// - html: the rendered HTML
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) renderTemplateContentToHtml(
+	r *http.Request,
+	content string,
+	editor string,
+	options TemplateRenderHtmlByIDOptions,
+) (html string, err error) {
+	if editor == cmsstore.TEMPLATE_EDITOR_GOTEMPLATE {
+		return frontend.renderGoTemplateToHtml(r, content, options)
+	}
+
+	return frontend.renderContentToHtml(r, content, options)
+}
+
+// renderGoTemplateToHtml renders the content with Go's html/template engine
+//
+// This is done in the following steps (sequence is important):
+// 1. fills the template sections, the page content may override them
+// 2. renders the page content with the standard pipeline, so blocks can
+// set custom variables before the template is executed
+// 3. executes the template with GoTemplateData as data and the helper
+// functions below
+//
+// Helper functions:
+//   - blockContent "BLOCK_ID" - the rendered block (trusted HTML)
+//   - translation "HANDLE_OR_ID" - the translation for the current language
+//   - pageJsonLd - the JSON-LD script of the page (trusted HTML),
+//     see cmsstore.PageJsonLd
+//   - pageOpenGraph - the Open Graph and Twitter card meta tags of the
+//     page (trusted HTML), see cmsstore.PageOpenGraph
+//   - pageUrl "PAGE_ID" - the URL of the page
+//   - media "MEDIA_ID" - the URL the media is served from
+//   - variable "NAME" - the current value of a custom variable, including
+//     variables set by blocks rendered earlier in the template
+//
+// Note! "block" is a reserved word in Go templates, hence blockContent
+//
+// Parameters:
+// - r: the HTTP request
+// - content: the template content to render
+// - options: the options for the rendering
+//
+// Returns:
+// - html: the rendered HTML
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) renderGoTemplateToHtml(
+	r *http.Request,
+	content string,
+	options TemplateRenderHtmlByIDOptions,
+) (html string, err error) {
+	pageSections, pageContent := extractTemplateSections(options.PageContent)
+	content = applyTemplateSections(content, pageSections, false)
+	options.PageContent = pageContent
+
+	// Share the request and the custom variables between the page content,
+	// the blocks and the template
+	ctx := cmsstore.RequestToContext(r.Context(), r)
+	if cmsstore.VarsFromContext(ctx) == nil {
+		ctx = cmsstore.WithVarsContext(ctx)
+	}
+	r = r.WithContext(ctx)
+
+	language := lo.If(options.Language == "", "en").Else(options.Language)
+
+	pageHtml := ""
+
+	if options.PageContent != "" {
+		pageHtml, err = frontend.renderContentToHtml(r, "[[PageContent]]", options)
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	site, err := frontend.goTemplateSite(ctx, options.SiteID)
+
+	if err != nil {
+		return "", err
+	}
+
+	data := GoTemplateData{
+		Page: GoTemplatePage{
+			Content:         template.HTML(pageHtml),
+			CanonicalURL:    options.PageCanonicalURL,
+			MetaDescription: options.PageMetaDescription,
+			MetaKeywords:    options.PageMetaKeywords,
+			MetaRobots:      options.PageMetaRobots,
+			Title:           options.PageTitle,
+			Metas:           map[string]string{},
+		},
+		Site:     site,
+		Vars:     cmsstore.VarsFromContext(ctx).All(),
+		Language: language,
+	}
+
+	if page := cmsstore.PageFromContext(ctx); page != nil {
+		data.Page.ID = page.ID()
+		data.Page.Alias = page.Alias()
+
+		if metas, err := page.Metas(); err == nil {
+			data.Page.Metas = metas
+		}
+	}
+
+	tmpl, err := template.New("cms").
+		Funcs(frontend.goTemplateFuncs(r, options, language)).
+		Parse(content)
+
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// goTemplateSite returns the fields of the site with the given ID,
+// or nil if the ID is empty or the site is not found
+func (frontend *frontend) goTemplateSite(ctx context.Context, siteID string) (*GoTemplateSite, error) {
+	if siteID == "" {
+		return nil, nil
+	}
+
+	site, err := frontend.store.SiteFindByID(ctx, siteID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if site == nil {
+		return nil, nil
+	}
+
+	domainNames, err := site.DomainNames()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &GoTemplateSite{
+		ID:          site.ID(),
+		Handle:      site.Handle(),
+		Name:        site.Name(),
+		DomainNames: domainNames,
+	}, nil
+}
+
+// goTemplateFuncs returns the helper functions available to Go templates
+func (frontend *frontend) goTemplateFuncs(r *http.Request, options TemplateRenderHtmlByIDOptions, language string) template.FuncMap {
+	ctx := r.Context()
+
+	// Blocks are rendered without page content, only their own content
+	blockOptions := options
+	blockOptions.PageContent = ""
+
+	return template.FuncMap{
+		"blockContent": func(blockID string) (template.HTML, error) {
+			if blockID == "" {
+				return "", nil
+			}
+
+			html, err := frontend.renderContentToHtml(r, "[[BLOCK_"+blockID+"]]", blockOptions)
+
+			if err != nil {
+				return "", err
+			}
+
+			return template.HTML(html), nil
+		},
+
+		"translation": func(handleOrID string) (string, error) {
+			if handleOrID == "" {
+				return "", nil
+			}
+
+			translation, err := frontend.store.TranslationFindByHandleOrID(ctx, handleOrID, language)
+
+			if err != nil {
+				return "", err
+			}
+
+			if translation == nil {
+				return "", nil
+			}
+
+			translationMap, err := translation.Content()
+
+			if err != nil {
+				return "", err
+			}
+
+			return lo.ValueOr(translationMap, language, ""), nil
+		},
+
+		"pageJsonLd": func() template.HTML {
+			return template.HTML(frontend.pageJsonLd(ctx, options.SiteID))
+		},
+
+		"pageOpenGraph": func() template.HTML {
+			return template.HTML(frontend.pageOpenGraph(ctx, options.SiteID))
+		},
+
+		"pageUrl": func(pageID string) (string, error) {
+			if pageID == "" {
+				return "", nil
+			}
+
+			return frontend.contentRenderPageURLByID(ctx, "[[PAGE_URL_"+pageID+"]]", pageID)
+		},
+
+		"media": func(mediaID string) (string, error) {
+			if mediaID == "" || !frontend.store.MediaEnabled() {
+				return "", nil
+			}
+
+			media, err := frontend.store.MediaFindByID(ctx, mediaID)
+
+			if err != nil {
+				return "", err
+			}
+
+			if media == nil || !media.IsActive() {
+				return "", nil
+			}
+
+			return media.ServeURL(), nil
+		},
+
+		"variable": func(name string) string {
+			vars := cmsstore.VarsFromContext(ctx)
+
+			if vars == nil {
+				return ""
+			}
+
+			value, _ := vars.Get(name)
+
+			return value
+		},
+	}
+}
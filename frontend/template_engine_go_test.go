@@ -0,0 +1,305 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+// setupGoTemplateTest creates a store, a site and a frontend for Go template tests
+func setupGoTemplateTest(t *testing.T) (*frontend, cmsstore.StoreInterface, cmsstore.SiteInterface) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site := cmsstore.NewSite().
+		SetName("Go Site").
+		SetStatus(cmsstore.SITE_STATUS_ACTIVE)
+
+	err = store.SiteCreate(context.Background(), site)
+	if err != nil {
+		t.Fatalf("Failed to create site: %v", err)
+	}
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	return f.(*frontend), store, site
+}
+
+// createGoTemplate is a helper creating an active Go template
+func createGoTemplate(t *testing.T, store cmsstore.StoreInterface, siteID, content string) cmsstore.TemplateInterface {
+	template := cmsstore.NewTemplate().
+		SetSiteID(siteID).
+		SetName("Go Template").
+		SetEditor(cmsstore.TEMPLATE_EDITOR_GOTEMPLATE).
+		SetContent(content).
+		SetStatus(cmsstore.TEMPLATE_STATUS_ACTIVE)
+
+	err := store.TemplateCreate(context.Background(), template)
+	if err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	return template
+}
+
+func TestGoTemplate_EscapesValues(t *testing.T) {
+	f, store, site := setupGoTemplateTest(t)
+
+	template := createGoTemplate(t, store, site.ID(),
+		`<meta name="description" content="{{ .Page.MetaDescription }}"><title>{{ .Page.Title }}</title><main>{{ .Page.Content }}</main>`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.TemplateRenderHtmlByID(req, template.ID(), TemplateRenderHtmlByIDOptions{
+		PageTitle:           `Tom & "Jerry"`,
+		PageMetaDescription: `Say "hi" <script>`,
+		PageContent:         `<p>Trusted</p>`,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.Contains(html, `content="Say &#34;hi&#34; &lt;script&gt;"`) {
+		t.Errorf("Expected escaped meta description, got %q", html)
+	}
+
+	if !strings.Contains(html, `<title>Tom &amp; &#34;Jerry&#34;</title>`) {
+		t.Errorf("Expected escaped title, got %q", html)
+	}
+
+	if !strings.Contains(html, `<main><p>Trusted</p></main>`) {
+		t.Errorf("Expected page content to be rendered as HTML, got %q", html)
+	}
+}
+
+func TestGoTemplate_SiteAndConditionals(t *testing.T) {
+	f, store, site := setupGoTemplateTest(t)
+
+	template := createGoTemplate(t, store, site.ID(),
+		`{{ if .Site }}<h1>{{ .Site.Name }}</h1>{{ end }}{{ if .Page.Title }}T{{ else }}NoTitle{{ end }}|{{ .Language }}`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.TemplateRenderHtmlByID(req, template.ID(), TemplateRenderHtmlByIDOptions{
+		Language: "de",
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `<h1>Go Site</h1>NoTitle|de`
+
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+}
+
+func TestGoTemplate_SiteIsReadOnly(t *testing.T) {
+	f, store, site := setupGoTemplateTest(t)
+
+	template := createGoTemplate(t, store, site.ID(), `{{ .Site.ID }}|{{ .Site.Name }}`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.TemplateRenderHtmlByID(req, template.ID(), TemplateRenderHtmlByIDOptions{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if expected := site.ID() + "|Go Site"; html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+
+	// The site setters are not available to the templates
+	template = createGoTemplate(t, store, site.ID(), `{{ .Site.SetName "Changed" }}`)
+
+	if _, err := f.TemplateRenderHtmlByID(req, template.ID(), TemplateRenderHtmlByIDOptions{}); err == nil {
+		t.Error("Expected an error, when a template calls a site setter")
+	}
+
+	found, err := store.SiteFindByID(context.Background(), site.ID())
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if found.Name() != "Go Site" {
+		t.Errorf("Expected the site name to be unchanged, got %q", found.Name())
+	}
+}
+
+func TestGoTemplate_HelperFunctions(t *testing.T) {
+	f, store, site := setupGoTemplateTest(t)
+
+	block := cmsstore.NewBlock().
+		SetSiteID(site.ID()).
+		SetType(cmsstore.BLOCK_TYPE_HTML).
+		SetContent("<b>Block</b>").
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	page := cmsstore.NewPage().
+		SetSiteID(site.ID()).
+		SetAlias("/about").
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE)
+
+	if err := store.PageCreate(context.Background(), page); err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	translation := cmsstore.NewTranslation().
+		SetSiteID(site.ID()).
+		SetHandle("greeting").
+		SetStatus(cmsstore.TRANSLATION_STATUS_ACTIVE)
+
+	if err := translation.SetContent(map[string]string{"en": "Hello", "de": "Hallo"}); err != nil {
+		t.Fatalf("Failed to set translation content: %v", err)
+	}
+
+	if err := store.TranslationCreate(context.Background(), translation); err != nil {
+		t.Fatalf("Failed to create translation: %v", err)
+	}
+
+	template := createGoTemplate(t, store, site.ID(),
+		`{{ blockContent "`+block.ID()+`" }}|{{ pageUrl "`+page.ID()+`" }}|{{ translation "greeting" }}|{{ media "" }}`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.TemplateRenderHtmlByID(req, template.ID(), TemplateRenderHtmlByIDOptions{
+		Language: "de",
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `<b>Block</b>|/about|Hallo|`
+
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+}
+
+func TestGoTemplate_LoopsOverCustomVariables(t *testing.T) {
+	f, store, site := setupGoTemplateTest(t)
+
+	cmsstore.RegisterCustomBlockType(&testVariableSettingBlockType{})
+
+	block := cmsstore.NewBlock().
+		SetSiteID(site.ID()).
+		SetType("test_variable_setter").
+		SetStatus(cmsstore.BLOCK_STATUS_ACTIVE)
+
+	if err := store.BlockCreate(context.Background(), block); err != nil {
+		t.Fatalf("Failed to create block: %v", err)
+	}
+
+	template := createGoTemplate(t, store, site.ID(),
+		`<h2>{{ .Vars.blog_title }}</h2>{{ range $key, $value := .Vars }}[{{ $key }}]{{ end }}`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.TemplateRenderHtmlByID(req, template.ID(), TemplateRenderHtmlByIDOptions{
+		PageContent: "[[BLOCK_" + block.ID() + "]]",
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.Contains(html, `<h2>My Blog Post Title</h2>`) {
+		t.Errorf("Expected blog title set by the block, got %q", html)
+	}
+
+	if !strings.Contains(html, `[blog_date][blog_slug][blog_summary][blog_title]`) {
+		t.Errorf("Expected sorted variable keys, got %q", html)
+	}
+}
+
+func TestGoTemplate_ParseError(t *testing.T) {
+	f, store, site := setupGoTemplateTest(t)
+
+	template := createGoTemplate(t, store, site.ID(), `{{ if .Page.Title }}`)
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	_, err := f.TemplateRenderHtmlByID(req, template.ID(), TemplateRenderHtmlByIDOptions{})
+
+	if err == nil {
+		t.Fatal("Expected a template parse error")
+	}
+}
+
+func TestGoTemplate_PlaceholderTemplatesUnchanged(t *testing.T) {
+	f, store, site := setupGoTemplateTest(t)
+
+	template := cmsstore.NewTemplate().
+		SetSiteID(site.ID()).
+		SetContent(`{{ .Page.Title }} [[PageTitle]]`).
+		SetStatus(cmsstore.TEMPLATE_STATUS_ACTIVE)
+
+	if err := store.TemplateCreate(context.Background(), template); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	html, err := f.TemplateRenderHtmlByID(req, template.ID(), TemplateRenderHtmlByIDOptions{
+		PageTitle: "Title",
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if html != `{{ .Page.Title }} Title` {
+		t.Errorf("Expected placeholder rendering only, got %q", html)
+	}
+}
+
+func TestPageRenderHtmlBySiteAndAlias_GoTemplate(t *testing.T) {
+	f, store, site := setupGoTemplateTest(t)
+
+	template := createGoTemplate(t, store, site.ID(),
+		`<title>{{ .Page.Title }}</title>{{ .Page.ID }}|{{ .Page.Alias }}|{{ .Page.Content }}`)
+
+	page := cmsstore.NewPage().
+		SetSiteID(site.ID()).
+		SetAlias("go-page").
+		SetTitle("Go <Page>").
+		SetContent("<p>Body</p>").
+		SetTemplateID(template.ID()).
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE)
+
+	if err := store.PageCreate(context.Background(), page); err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	recorder := httptest.NewRecorder()
+
+	html := f.PageRenderHtmlBySiteAndAlias(recorder, req, site.ID(), "go-page", "en")
+
+	expected := `<title>Go &lt;Page&gt;</title>` + page.ID() + `|go-page|<p>Body</p>`
+
+	if html != expected {
+		t.Errorf("Expected %q, got %q", expected, html)
+	}
+}
@@ -0,0 +1,281 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dracory/cmsstore"
+)
+
+// templateInheritanceMaxDepth is the maximum number of templates allowed
+// in a single inheritance chain (the template itself included)
+const templateInheritanceMaxDepth = 10
+
+// Package-level compiled regex for performance
+// Matches: [[section name="..."]] ... [[/section]] and [[SECTION_name]]
+var templateSectionOpen = regexp.MustCompile(`\[\[\s*section\s+([^\]]+?)\s*\]\]`)
+var templateSectionClose = regexp.MustCompile(`\[\[\s*/section\s*\]\]`)
+var templateSectionSlot = regexp.MustCompile(`\[\[\s*SECTION_([a-zA-Z0-9_-]+)\s*\]\]`)
+
+// templateSection describes a top level section found in a content
+type templateSection struct {
+	name      string
+	start     int // start of the opening tag
+	end       int // end of the closing tag
+	bodyStart int // end of the opening tag
+	bodyEnd   int // start of the closing tag
+}
+
+// templateResolveInheritance returns the content of the template with
+// the sections of all its ancestors resolved.
+//
+// Business Logic:
+//   - walks the parent chain from the template up to the root (layout) template
+//   - fails if a template is found twice in the chain (cycle)
+//   - fails if the chain is longer than templateInheritanceMaxDepth
+//   - fails if a parent template cannot be found
+//   - starts from the root template content and applies the sections
+//     defined by each descendant, the closest descendant wins
+//   - section markers are kept, so pages can still fill or override them
+//
+// Parameters:
+// - ctx: the context
+// - template: the template to resolve
+//
+// Returns:
+// - content: the resolved content
+// - err: the error, if any, or nil otherwise
+func (frontend *frontend) templateResolveInheritance(ctx context.Context, template cmsstore.TemplateInterface) (string, error) {
+	if template == nil {
+		return "", errors.New("template is nil")
+	}
+
+	chain := []cmsstore.TemplateInterface{template}
+	path := []string{template.ID()}
+	visited := map[string]bool{template.ID(): true}
+
+	current := template
+
+	for current.ParentID() != "" {
+		parentID := current.ParentID()
+
+		if visited[parentID] {
+			path = append(path, parentID)
+			return "", errors.New("template inheritance cycle detected: " + strings.Join(path, " -> "))
+		}
+
+		if len(chain) >= templateInheritanceMaxDepth {
+			return "", errors.New("template inheritance too deep: " + strings.Join(path, " -> "))
+		}
+
+		parent, err := frontend.store.TemplateFindByID(ctx, parentID)
+
+		if err != nil {
+			return "", err
+		}
+
+		if parent == nil {
+			return "", errors.New("parent template " + parentID + " not found")
+		}
+
+		chain = append(chain, parent)
+		path = append(path, parentID)
+		visited[parentID] = true
+		current = parent
+	}
+
+	// Start with the root template, and apply descendants from the top down
+	content := chain[len(chain)-1].Content()
+
+	for i := len(chain) - 2; i >= 0; i-- {
+		sections, _ := extractTemplateSections(chain[i].Content())
+		content = applyTemplateSections(content, sections, true)
+	}
+
+	return content, nil
+}
+
+// findTemplateSections returns the top level sections in the content
+//
+// Nested sections are supported, only the outermost sections are returned.
+// Unbalanced opening or closing tags are ignored.
+func findTemplateSections(content string) []templateSection {
+	if !strings.Contains(content, "[[") {
+		return nil
+	}
+
+	type token struct {
+		start int
+		end   int
+		name  string
+		open  bool
+	}
+
+	tokens := []token{}
+
+	for _, m := range templateSectionOpen.FindAllStringSubmatchIndex(content, -1) {
+		attrs := parseAttributes(content[m[2]:m[3]])
+		tokens = append(tokens, token{start: m[0], end: m[1], name: attrs["name"], open: true})
+	}
+
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	for _, m := range templateSectionClose.FindAllStringIndex(content, -1) {
+		tokens = append(tokens, token{start: m[0], end: m[1]})
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].start < tokens[j].start
+	})
+
+	sections := []templateSection{}
+	stack := []token{}
+
+	for _, t := range tokens {
+		if t.open {
+			stack = append(stack, t)
+			continue
+		}
+
+		if len(stack) == 0 {
+			continue // stray closing tag
+		}
+
+		open := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if len(stack) > 0 {
+			continue // nested section, handled when its parent is processed
+		}
+
+		if open.name == "" {
+			continue // section without a name
+		}
+
+		sections = append(sections, templateSection{
+			name:      open.name,
+			start:     open.start,
+			end:       t.end,
+			bodyStart: open.end,
+			bodyEnd:   t.start,
+		})
+	}
+
+	return sections
+}
+
+// extractTemplateSections returns the top level sections defined in the
+// content, and the content with the section definitions removed
+//
+// If a section is defined more than once, the last definition wins.
+func extractTemplateSections(content string) (sections map[string]string, remaining string) {
+	sections = map[string]string{}
+
+	found := findTemplateSections(content)
+
+	if len(found) == 0 {
+		return sections, content
+	}
+
+	var sb strings.Builder
+	last := 0
+
+	for _, section := range found {
+		sb.WriteString(content[last:section.start])
+		sections[section.name] = content[section.bodyStart:section.bodyEnd]
+		last = section.end
+	}
+
+	sb.WriteString(content[last:])
+
+	return sections, strings.TrimSpace(sb.String())
+}
+
+// applyTemplateSections fills the sections in the content
+//
+// Business Logic:
+//   - a [[section name="x"]]default[[/section]] is replaced by the
+//     provided value for "x", or by its default content otherwise
+//   - a [[SECTION_x]] slot is replaced by the provided value for "x",
+//     or by an empty string otherwise
+//   - if keepMarkers is true the section markers are kept, so the
+//     sections can be overridden again later (i.e. by a page)
+//
+// Parameters:
+// - content: the content containing the sections
+// - sections: the section values, by section name
+// - keepMarkers: whether to keep the section markers in the result
+//
+// Returns:
+// - content: the content with the sections filled
+func applyTemplateSections(content string, sections map[string]string, keepMarkers bool) string {
+	found := findTemplateSections(content)
+
+	var sb strings.Builder
+	last := 0
+
+	for _, section := range found {
+		sb.WriteString(applyTemplateSectionSlots(content[last:section.start], sections, keepMarkers))
+
+		body, overridden := sections[section.name]
+
+		if !overridden {
+			body = applyTemplateSections(content[section.bodyStart:section.bodyEnd], sections, keepMarkers)
+		} else if !keepMarkers {
+			// Strip any markers left in the value, without re-applying the
+			// same section to itself
+			body = applyTemplateSections(body, withoutSection(sections, section.name), keepMarkers)
+		}
+
+		if keepMarkers {
+			body = `[[section name="` + section.name + `"]]` + body + `[[/section]]`
+		}
+
+		sb.WriteString(body)
+		last = section.end
+	}
+
+	sb.WriteString(applyTemplateSectionSlots(content[last:], sections, keepMarkers))
+
+	return sb.String()
+}
+
+// applyTemplateSectionSlots replaces the [[SECTION_x]] slots in the content
+func applyTemplateSectionSlots(content string, sections map[string]string, keepMarkers bool) string {
+	if !strings.Contains(content, "SECTION_") {
+		return content
+	}
+
+	return templateSectionSlot.ReplaceAllStringFunc(content, func(slot string) string {
+		name := templateSectionSlot.FindStringSubmatch(slot)[1]
+		value, exists := sections[name]
+
+		if !keepMarkers {
+			return value
+		}
+
+		if !exists {
+			return slot // keep the slot, it may be filled later
+		}
+
+		return `[[section name="` + name + `"]]` + value + `[[/section]]`
+	})
+}
+
+// withoutSection returns a copy of the sections without the named section
+func withoutSection(sections map[string]string, name string) map[string]string {
+	result := make(map[string]string, len(sections))
+
+	for key, value := range sections {
+		if key != name {
+			result[key] = value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,304 @@
+package frontend
+
+import (
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+	"github.com/dracory/cmsstore/testutils"
+)
+
+func TestExtractTemplateSections(t *testing.T) {
+	content := `Intro [[section name="head"]]<title>Child</title>[[/section]] Body [[section name='footer']]Footer[[/section]]`
+
+	sections, remaining := extractTemplateSections(content)
+
+	if len(sections) != 2 {
+		t.Fatalf("Expected 2 sections, got %d", len(sections))
+	}
+
+	if sections["head"] != "<title>Child</title>" {
+		t.Errorf("Unexpected head section: %q", sections["head"])
+	}
+
+	if sections["footer"] != "Footer" {
+		t.Errorf("Unexpected footer section: %q", sections["footer"])
+	}
+
+	if remaining != "Intro  Body" {
+		t.Errorf("Unexpected remaining content: %q", remaining)
+	}
+}
+
+func TestExtractTemplateSections_NoSections(t *testing.T) {
+	sections, remaining := extractTemplateSections("<p>Plain content</p>")
+
+	if len(sections) != 0 {
+		t.Errorf("Expected no sections, got %d", len(sections))
+	}
+
+	if remaining != "<p>Plain content</p>" {
+		t.Errorf("Expected content to be unchanged, got %q", remaining)
+	}
+}
+
+func TestExtractTemplateSections_Nested(t *testing.T) {
+	content := `[[section name="main"]]A [[section name="inner"]]B[[/section]] C[[/section]]`
+
+	sections, _ := extractTemplateSections(content)
+
+	if len(sections) != 1 {
+		t.Fatalf("Expected 1 top level section, got %d", len(sections))
+	}
+
+	if sections["main"] != `A [[section name="inner"]]B[[/section]] C` {
+		t.Errorf("Unexpected main section: %q", sections["main"])
+	}
+}
+
+func TestApplyTemplateSections_Defaults(t *testing.T) {
+	content := `<head>[[section name="head"]]<title>Default</title>[[/section]]</head><aside>[[SECTION_sidebar]]</aside>`
+
+	result := applyTemplateSections(content, map[string]string{}, false)
+
+	expected := `<head><title>Default</title></head><aside></aside>`
+
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestApplyTemplateSections_Overrides(t *testing.T) {
+	content := `<head>[[section name="head"]]<title>Default</title>[[/section]]</head><aside>[[SECTION_sidebar]]</aside>`
+
+	result := applyTemplateSections(content, map[string]string{
+		"head":    "<title>Override</title>",
+		"sidebar": "Links",
+	}, false)
+
+	expected := `<head><title>Override</title></head><aside>Links</aside>`
+
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestApplyTemplateSections_KeepMarkers(t *testing.T) {
+	content := `[[section name="head"]]Default[[/section]]|[[SECTION_sidebar]]|[[SECTION_footer]]`
+
+	result := applyTemplateSections(content, map[string]string{
+		"sidebar": "Links",
+	}, true)
+
+	expected := `[[section name="head"]]Default[[/section]]|[[section name="sidebar"]]Links[[/section]]|[[SECTION_footer]]`
+
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+// createInheritanceTemplate is a helper creating an active template
+func createInheritanceTemplate(t *testing.T, store cmsstore.StoreInterface, siteID, content, parentID string) cmsstore.TemplateInterface {
+	template := cmsstore.NewTemplate().
+		SetSiteID(siteID).
+		SetName("Template").
+		SetContent(content).
+		SetStatus(cmsstore.TEMPLATE_STATUS_ACTIVE).
+		SetParentID(parentID)
+
+	err := store.TemplateCreate(context.Background(), template)
+	if err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+
+	return template
+}
+
+func TestTemplateRenderHtmlByID_Inheritance(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	layout := createInheritanceTemplate(t, store, testutils.SITE_01,
+		`<html><head>[[section name="head"]]<title>[[PageTitle]]</title>[[/section]]</head>`+
+			`<body>[[section name="body"]][[PageContent]][[/section]]<footer>[[section name="footer"]]Layout footer[[/section]]</footer></body></html>`,
+		"")
+
+	twoColumns := createInheritanceTemplate(t, store, testutils.SITE_01,
+		`[[section name="body"]]<main>[[PageContent]]</main><aside>[[SECTION_sidebar]]</aside>[[/section]]`,
+		layout.ID())
+
+	blog := createInheritanceTemplate(t, store, testutils.SITE_01,
+		`[[section name="sidebar"]]Blog sidebar[[/section]][[section name="footer"]]Blog footer[[/section]]`,
+		twoColumns.ID())
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.Default(),
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	result, err := f.TemplateRenderHtmlByID(req, blog.ID(), TemplateRenderHtmlByIDOptions{
+		PageTitle:   "My Post",
+		PageContent: "<p>Post</p>",
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `<html><head><title>My Post</title></head>` +
+		`<body><main><p>Post</p></main><aside>Blog sidebar</aside><footer>Blog footer</footer></body></html>`
+
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestTemplateRenderHtmlByID_PageOverridesSections(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	layout := createInheritanceTemplate(t, store, testutils.SITE_01,
+		`<aside>[[section name="sidebar"]]Default sidebar[[/section]]</aside><main>[[PageContent]]</main>`,
+		"")
+
+	child := createInheritanceTemplate(t, store, testutils.SITE_01,
+		`[[section name="sidebar"]]Child sidebar[[/section]]`,
+		layout.ID())
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.Default(),
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	result, err := f.TemplateRenderHtmlByID(req, child.ID(), TemplateRenderHtmlByIDOptions{
+		PageContent: `[[section name="sidebar"]]Page sidebar[[/section]]<p>Page</p>`,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `<aside>Page sidebar</aside><main><p>Page</p></main>`
+
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestTemplateRenderHtmlByID_InheritanceCycle(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	templateA := createInheritanceTemplate(t, store, testutils.SITE_01, `A`, "")
+	templateB := createInheritanceTemplate(t, store, testutils.SITE_01, `B`, templateA.ID())
+
+	templateA.SetParentID(templateB.ID())
+
+	err = store.TemplateUpdate(context.Background(), templateA)
+	if err != nil {
+		t.Fatalf("Failed to update template: %v", err)
+	}
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.Default(),
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	_, err = f.TemplateRenderHtmlByID(req, templateA.ID(), TemplateRenderHtmlByIDOptions{})
+
+	if err == nil {
+		t.Fatal("Expected an inheritance cycle error")
+	}
+
+	if !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("Expected cycle error, got %v", err)
+	}
+}
+
+func TestTemplateRenderHtmlByID_ParentNotFound(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	child := createInheritanceTemplate(t, store, testutils.SITE_01, `[[section name="body"]]B[[/section]]`, "missing-parent")
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.Default(),
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+
+	_, err = f.TemplateRenderHtmlByID(req, child.ID(), TemplateRenderHtmlByIDOptions{})
+
+	if err == nil {
+		t.Fatal("Expected a parent not found error")
+	}
+}
+
+func TestPageRenderHtmlBySiteAndAlias_TemplateInheritance(t *testing.T) {
+	store, err := testutils.InitStore(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to init store: %v", err)
+	}
+
+	site := cmsstore.NewSite().
+		SetName("Test Site").
+		SetStatus(cmsstore.SITE_STATUS_ACTIVE)
+
+	err = store.SiteCreate(context.Background(), site)
+	if err != nil {
+		t.Fatalf("Failed to create site: %v", err)
+	}
+
+	layout := createInheritanceTemplate(t, store, site.ID(),
+		`<nav>[[SECTION_nav]]</nav><div class="layout">[[PageContent]]</div>`, "")
+
+	child := createInheritanceTemplate(t, store, site.ID(),
+		`[[section name="nav"]]Child nav[[/section]]`, layout.ID())
+
+	page := cmsstore.NewPage().
+		SetSiteID(site.ID()).
+		SetName("Test Page").
+		SetAlias("test-page").
+		SetContent("<h1>Page Content</h1>").
+		SetTemplateID(child.ID()).
+		SetStatus(cmsstore.PAGE_STATUS_ACTIVE)
+
+	err = store.PageCreate(context.Background(), page)
+	if err != nil {
+		t.Fatalf("Failed to create page: %v", err)
+	}
+
+	f := New(Config{
+		Store:  store,
+		Logger: slog.Default(),
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	recorder := httptest.NewRecorder()
+
+	result := f.(*frontend).PageRenderHtmlBySiteAndAlias(recorder, req, site.ID(), "test-page", "en")
+
+	expected := `<nav>Child nav</nav><div class="layout"><h1>Page Content</h1></div>`
+
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
@@ -1,6 +1,7 @@
 package frontend
 
 import (
+	"context"
 	"html"
 	"net/http"
 	"regexp"
@@ -45,73 +46,87 @@ func (frontend *frontend) applyTranslationAttributeSyntax(req *http.Request, con
 
 	// Process each match
 	for _, m := range allMatches {
-		fullTag := m.fullTag
-		attrString := m.attrs
-
 		// Parse attributes
-		attrs := parseAttributes(attrString)
-
-		// Get translation ID (required)
-		translationID := attrs["id"]
-		if translationID == "" {
-			frontend.logger.Warn("Translation attribute syntax: missing id attribute", "tag", fullTag)
-			content = strings.Replace(content, fullTag, "<!-- Translation reference missing id -->", 1)
-			continue
-		}
+		attrs := parseAttributes(m.attrs)
 
-		// Get fallback language (optional)
-		fallbackLang := attrs["fallback"]
+		// Replace the tag with translated text
+		content = strings.Replace(content, m.fullTag, frontend.renderTranslationTag(req.Context(), m.fullTag, attrs, language), 1)
+	}
 
-		// Fetch translation from database
-		translation, err := frontend.store.TranslationFindByHandleOrID(req.Context(), translationID, language)
-		if err != nil {
-			frontend.logger.Error("Translation attribute syntax: error fetching translation", "id", translationID, "error", err)
-			content = strings.Replace(content, fullTag, "<!-- Translation error: "+translationID+" -->", 1)
-			continue
-		}
+	return content, nil
+}
 
-		if translation == nil {
-			frontend.logger.Warn("Translation attribute syntax: translation not found", "id", translationID)
-			content = strings.Replace(content, fullTag, "<!-- Translation not found: "+translationID+" -->", 1)
-			continue
-		}
+// renderTranslationTag renders a single translation reference with
+// attributes, i.e. <translation id="..." fallback="en" name="value" />
+//
+// Business Logic:
+//   - the id attribute is required
+//   - missing and failing translations render an HTML comment
+//   - the fallback language is used if the language has no text
+//   - {{key}} in the text is replaced by the escaped attribute value
+//
+// Parameters:
+// - ctx: the context
+// - fullTag: the tag as found in the content, used for logging
+// - attrs: the parsed tag attributes
+// - language: the language to translate to
+//
+// Returns:
+// - text: the translated text
+func (frontend *frontend) renderTranslationTag(ctx context.Context, fullTag string, attrs map[string]string, language string) string {
+	// Get translation ID (required)
+	translationID := attrs["id"]
+	if translationID == "" {
+		frontend.logger.Warn("Translation attribute syntax: missing id attribute", "tag", fullTag)
+		return "<!-- Translation reference missing id -->"
+	}
 
-		// Get translation content map
-		translationMap, err := translation.Content()
-		if err != nil {
-			frontend.logger.Error("Translation attribute syntax: error parsing translation content", "id", translationID, "error", err)
-			content = strings.Replace(content, fullTag, "<!-- Translation parse error: "+translationID+" -->", 1)
-			continue
-		}
+	// Get fallback language (optional)
+	fallbackLang := attrs["fallback"]
 
-		// Get text for current language
-		text := lo.ValueOr(translationMap, language, "")
+	// Fetch translation from database
+	translation, err := frontend.store.TranslationFindByHandleOrID(ctx, translationID, language)
+	if err != nil {
+		frontend.logger.Error("Translation attribute syntax: error fetching translation", "id", translationID, "error", err)
+		return "<!-- Translation error: " + translationID + " -->"
+	}
 
-		// Fallback handling
-		if text == "" && fallbackLang != "" {
-			text = lo.ValueOr(translationMap, fallbackLang, "")
-		}
+	if translation == nil {
+		frontend.logger.Warn("Translation attribute syntax: translation not found", "id", translationID)
+		return "<!-- Translation not found: " + translationID + " -->"
+	}
 
-		// If still empty, use empty string
-		if text == "" {
-			frontend.logger.Warn("Translation attribute syntax: no translation found for language", "id", translationID, "language", language, "fallback", fallbackLang)
-			text = ""
-		}
+	// Get translation content map
+	translationMap, err := translation.Content()
+	if err != nil {
+		frontend.logger.Error("Translation attribute syntax: error parsing translation content", "id", translationID, "error", err)
+		return "<!-- Translation parse error: " + translationID + " -->"
+	}
 
-		// Variable interpolation - replace {{key}} with attribute values
-		// Remove system attributes (id, fallback) before interpolation
-		for key, value := range attrs {
-			if key != "id" && key != "fallback" {
-				// Security: HTML escape interpolation values to prevent XSS
-				escapedValue := html.EscapeString(value)
-				placeholder := "{{" + key + "}}"
-				text = strings.ReplaceAll(text, placeholder, escapedValue)
-			}
-		}
+	// Get text for current language
+	text := lo.ValueOr(translationMap, language, "")
 
-		// Replace the tag with translated text
-		content = strings.Replace(content, fullTag, text, 1)
+	// Fallback handling
+	if text == "" && fallbackLang != "" {
+		text = lo.ValueOr(translationMap, fallbackLang, "")
 	}
 
-	return content, nil
+	// If still empty, use empty string
+	if text == "" {
+		frontend.logger.Warn("Translation attribute syntax: no translation found for language", "id", translationID, "language", language, "fallback", fallbackLang)
+		text = ""
+	}
+
+	// Variable interpolation - replace {{key}} with attribute values
+	// Remove system attributes (id, fallback) before interpolation
+	for key, value := range attrs {
+		if key != "id" && key != "fallback" {
+			// Security: HTML escape interpolation values to prevent XSS
+			escapedValue := html.EscapeString(value)
+			placeholder := "{{" + key + "}}"
+			text = strings.ReplaceAll(text, placeholder, escapedValue)
+		}
+	}
+
+	return text
 }
@@ -21,4 +21,8 @@ type TemplateRenderHtmlByIDOptions struct {
 	PageMetaRobots      string
 	PageTitle           string
 	Language            string
+
+	// SiteID is the ID of the site being rendered. Used by the Go template
+	// engine to expose the site to templates. Defaults to the template site
+	SiteID string
 }
@@ -28,6 +28,11 @@ type BlockInterface interface {
 	Handle() string
 	SetHandle(handle string) BlockInterface
 
+	IsLibrary() bool
+	SetLibrary(isLibrary bool) error
+	LibraryFields() []string
+	SetLibraryFields(fields []string) error
+
 	Memo() string
 	SetMemo(memo string) BlockInterface
 
@@ -150,16 +155,35 @@ type MenuItemInterface interface {
 
 	// Setters and Getters
 
+	Anchor() string
+	SetAnchor(anchor string) MenuItemInterface
+
+	BlockID() string
+	SetBlockID(blockID string) MenuItemInterface
+
 	CreatedAt() string
 	SetCreatedAt(createdAt string) MenuItemInterface
 	CreatedAtCarbon() *carbon.Carbon
 
+	CSSClass() string
+	SetCSSClass(cssClass string) MenuItemInterface
+
+	EntityID() string
+	SetEntityID(entityID string) MenuItemInterface
+
 	Handle() string
 	SetHandle(handle string) MenuItemInterface
 
+	Icon() string
+	SetIcon(icon string) MenuItemInterface
+
 	ID() string
 	SetID(id string) MenuItemInterface
 
+	Label(language string) string
+	Labels() map[string]string
+	SetLabel(language string, label string) MenuItemInterface
+
 	Memo() string
 	SetMemo(memo string) MenuItemInterface
 
@@ -181,6 +205,9 @@ type MenuItemInterface interface {
 	ParentID() string
 	SetParentID(parentID string) MenuItemInterface
 
+	Rel() string
+	SetRel(rel string) MenuItemInterface
+
 	Sequence() string
 	SequenceInt() int
 	SetSequence(sequence string) MenuItemInterface
@@ -196,6 +223,13 @@ type MenuItemInterface interface {
 	Target() string
 	SetTarget(target string) MenuItemInterface
 
+	TranslatedURL(language string) string
+	TranslatedURLs() map[string]string
+	SetTranslatedURL(language string, url string) MenuItemInterface
+
+	Type() string
+	SetType(menuItemType string) MenuItemInterface
+
 	UpdatedAt() string
 	SetUpdatedAt(updatedAt string) MenuItemInterface
 	UpdatedAtCarbon() *carbon.Carbon
@@ -268,6 +302,26 @@ type PageInterface interface {
 	Name() string
 	SetName(name string) PageInterface
 
+	OpenGraphDescription() string
+	SetOpenGraphDescription(description string) PageInterface
+
+	OpenGraphImageMediaID() string
+	SetOpenGraphImageMediaID(mediaID string) PageInterface
+
+	OpenGraphTitle() string
+	SetOpenGraphTitle(title string) PageInterface
+
+	OpenGraphType() string
+	SetOpenGraphType(ogType string) PageInterface
+
+	ParentID() string
+	SetParentID(parentID string) PageInterface
+
+	Sequence() string
+	SequenceInt() int
+	SetSequence(sequence string) PageInterface
+	SetSequenceInt(sequence int) PageInterface
+
 	SiteID() string
 	SetSiteID(siteID string) PageInterface
 
@@ -284,6 +338,9 @@ type PageInterface interface {
 	TemplateID() string
 	SetTemplateID(templateID string) PageInterface
 
+	TwitterCard() string
+	SetTwitterCard(card string) PageInterface
+
 	UpdatedAt() string
 	SetUpdatedAt(updatedAt string) PageInterface
 	UpdatedAtCarbon() *carbon.Carbon
@@ -425,6 +482,70 @@ type MediaInterface interface {
 	ServeURL() string
 }
 
+type FormSubmissionInterface interface {
+	Data() map[string]string
+	DataChanged() map[string]string
+	MarkAsNotDirty(...string)
+
+	ID() string
+	SetID(id string) FormSubmissionInterface
+
+	SiteID() string
+	SetSiteID(siteID string) FormSubmissionInterface
+
+	// BlockID is the ID of the form block submitted
+	BlockID() string
+	SetBlockID(blockID string) FormSubmissionInterface
+
+	// PageID is the ID of the page the form was submitted on
+	PageID() string
+	SetPageID(pageID string) FormSubmissionInterface
+
+	Status() string
+	SetStatus(status string) FormSubmissionInterface
+
+	// FormData are the submitted values, by field name
+	FormData() (map[string]string, error)
+	FormValue(name string) string
+	SetFormData(formData map[string]string) error
+
+	IPAddress() string
+	SetIPAddress(ipAddress string) FormSubmissionInterface
+
+	UserAgent() string
+	SetUserAgent(userAgent string) FormSubmissionInterface
+
+	// SourceURL is the URL of the page the form was submitted on
+	SourceURL() string
+	SetSourceURL(sourceURL string) FormSubmissionInterface
+
+	Memo() string
+	SetMemo(memo string) FormSubmissionInterface
+
+	Meta(key string) string
+	SetMeta(key, value string) error
+	Metas() (map[string]string, error)
+	SetMetas(metas map[string]string) error
+	UpsertMetas(metas map[string]string) error
+
+	CreatedAt() string
+	SetCreatedAt(createdAt string) FormSubmissionInterface
+	CreatedAtCarbon() *carbon.Carbon
+
+	UpdatedAt() string
+	SetUpdatedAt(updatedAt string) FormSubmissionInterface
+	UpdatedAtCarbon() *carbon.Carbon
+
+	SoftDeletedAt() string
+	SetSoftDeletedAt(softDeletedAt string) FormSubmissionInterface
+	SoftDeletedAtCarbon() *carbon.Carbon
+
+	IsNew() bool
+	IsRead() bool
+	IsArchived() bool
+	IsSoftDeleted() bool
+}
+
 type StoreInterface interface {
 	// AutoMigrate runs the auto-migration process for the cms store
 	// Deprecated: Use MigrateUp and MigrateDown instead
@@ -445,9 +566,11 @@ type StoreInterface interface {
 	BlockFindByHandle(ctx context.Context, blockHandle string) (BlockInterface, error)
 	BlockFindByID(ctx context.Context, blockID string) (BlockInterface, error)
 	BlockList(ctx context.Context, query BlockQueryInterface) ([]BlockInterface, error)
+	BlockMove(ctx context.Context, blockID string, parentID string, position int) error
 	BlockSoftDelete(ctx context.Context, block BlockInterface) error
 	BlockSoftDeleteByID(ctx context.Context, id string) error
 	BlockUpdate(ctx context.Context, block BlockInterface) error
+	BlockUsageList(ctx context.Context, block BlockInterface) ([]BlockUsage, error)
 
 	MenusEnabled() bool
 
@@ -460,6 +583,7 @@ type StoreInterface interface {
 	MenuList(ctx context.Context, query MenuQueryInterface) ([]MenuInterface, error)
 	MenuSoftDelete(ctx context.Context, menu MenuInterface) error
 	MenuSoftDeleteByID(ctx context.Context, id string) error
+	MenuTree(ctx context.Context, menuID string) ([]*MenuTreeNode, error)
 	MenuUpdate(ctx context.Context, menu MenuInterface) error
 
 	MenuItemCreate(ctx context.Context, menuItem MenuItemInterface) error
@@ -468,6 +592,8 @@ type StoreInterface interface {
 	MenuItemDeleteByID(ctx context.Context, id string) error
 	MenuItemFindByID(ctx context.Context, menuItemID string) (MenuItemInterface, error)
 	MenuItemList(ctx context.Context, query MenuItemQueryInterface) ([]MenuItemInterface, error)
+	MenuItemMove(ctx context.Context, menuItemID string, parentID string, position int) error
+	MenuItemsResolve(ctx context.Context, menuID string) ([]MenuItemInterface, error)
 	MenuItemSoftDelete(ctx context.Context, menuItem MenuItemInterface) error
 	MenuItemSoftDeleteByID(ctx context.Context, id string) error
 	MenuItemUpdate(ctx context.Context, menuItem MenuItemInterface) error
@@ -479,6 +605,12 @@ type StoreInterface interface {
 	PageFindByHandle(ctx context.Context, pageHandle string) (PageInterface, error)
 	PageFindByID(ctx context.Context, pageID string) (PageInterface, error)
 	PageList(ctx context.Context, query PageQueryInterface) ([]PageInterface, error)
+	PageAliasesRefresh(ctx context.Context, pageID string) error
+	PageAncestors(ctx context.Context, pageID string) ([]PageInterface, error)
+	PageChildren(ctx context.Context, pageID string) ([]PageInterface, error)
+	PageMove(ctx context.Context, pageID string, parentID string, position int) error
+	PageSeoAudit(ctx context.Context, page PageInterface, html string) ([]SeoAuditIssue, error)
+	PageTree(ctx context.Context, siteID string) ([]*PageTreeNode, error)
 	PageSoftDelete(ctx context.Context, page PageInterface) error
 	PageSoftDeleteByID(ctx context.Context, id string) error
 	PageUpdate(ctx context.Context, page PageInterface) error
@@ -491,6 +623,7 @@ type StoreInterface interface {
 	SiteFindByHandle(ctx context.Context, siteHandle string) (SiteInterface, error)
 	SiteFindByID(ctx context.Context, siteID string) (SiteInterface, error)
 	SiteList(ctx context.Context, query SiteQueryInterface) ([]SiteInterface, error)
+	SiteSeoAudit(ctx context.Context, siteID string) ([]SeoAuditReport, error)
 	SiteSoftDelete(ctx context.Context, site SiteInterface) error
 	SiteSoftDeleteByID(ctx context.Context, id string) error
 	SiteUpdate(ctx context.Context, site SiteInterface) error
@@ -534,6 +667,15 @@ type StoreInterface interface {
 	VersioningSoftDeleteByID(ctx context.Context, id string) error
 	VersioningUpdate(ctx context.Context, versioning VersioningInterface) error
 
+	// BlockTypeRegistry returns the registry of the block types used by the store
+	BlockTypeRegistry() *BlockTypeRegistry
+
+	// ChangeVersion returns the number of changes made through the store
+	// to the kind of data (see CHANGE_KIND_*), increasing on each change.
+	// Used by the caches to detect changed data. Changes made by other
+	// processes, or directly in the database, are not counted.
+	ChangeVersion(kind string) uint64
+
 	Shortcodes() []ShortcodeInterface
 	AddShortcode(shortcode ShortcodeInterface)
 	AddShortcodes(shortcodes []ShortcodeInterface)
@@ -561,6 +703,18 @@ type StoreInterface interface {
 	MediaSoftDelete(ctx context.Context, media MediaInterface) error
 	MediaSoftDeleteByID(ctx context.Context, id string) error
 	MediaUpdate(ctx context.Context, media MediaInterface) error
+
+	// Form Submissions
+	FormsEnabled() bool
+	FormSubmissionCount(ctx context.Context, options FormSubmissionQueryInterface) (int64, error)
+	FormSubmissionCreate(ctx context.Context, submission FormSubmissionInterface) error
+	FormSubmissionDelete(ctx context.Context, submission FormSubmissionInterface) error
+	FormSubmissionDeleteByID(ctx context.Context, id string) error
+	FormSubmissionFindByID(ctx context.Context, id string) (FormSubmissionInterface, error)
+	FormSubmissionList(ctx context.Context, query FormSubmissionQueryInterface) ([]FormSubmissionInterface, error)
+	FormSubmissionSoftDelete(ctx context.Context, submission FormSubmissionInterface) error
+	FormSubmissionSoftDeleteByID(ctx context.Context, id string) error
+	FormSubmissionUpdate(ctx context.Context, submission FormSubmissionInterface) error
 }
 
 type TemplateInterface interface {
@@ -596,6 +750,12 @@ type TemplateInterface interface {
 	Name() string
 	SetName(name string) TemplateInterface
 
+	// ParentID returns the ID of the parent template this template extends,
+	// or an empty string if the template is a root (layout) template.
+	// It is stored in the metas, so is not set if the metas are invalid
+	ParentID() string
+	SetParentID(parentID string) TemplateInterface
+
 	SiteID() string
 	SetSiteID(siteID string) TemplateInterface
 
@@ -202,6 +202,15 @@ func (m *MCP) toolBlockUpsert(ctx context.Context, args map[string]any) (string,
 	if v, ok := argInt(args, "sequence"); ok {
 		block.SetSequenceInt(int(v))
 	}
+	if raw, exists := args["settings"]; exists && raw != nil {
+		settings, ok := raw.(map[string]any)
+		if !ok {
+			return "", errors.New("settings must be an object")
+		}
+		if err := applyBlockSettings(m.store.BlockTypeRegistry(), block, settings); err != nil {
+			return "", err
+		}
+	}
 
 	// Save block
 	if strings.TrimSpace(id) != "" {
@@ -245,6 +254,26 @@ func (m *MCP) toolBlockUpsert(ctx context.Context, args map[string]any) (string,
 	return string(respBytes), nil
 }
 
+// applyBlockSettings validates the settings against the settings schema
+// of the block type, and stores them in the block metas
+func applyBlockSettings(blockTypes *cmsstore.BlockTypeRegistry, block cmsstore.BlockInterface, settings map[string]any) error {
+	if len(settings) == 0 {
+		return nil
+	}
+
+	schema := cmsstore.BlockTypeSettingsSchema(blockTypes.Get(block.Type()))
+	if len(schema) == 0 {
+		return errors.New("block type has no settings schema: " + block.Type())
+	}
+
+	values, err := cmsstore.BlockSettingsFromJSON(settings)
+	if err != nil {
+		return err
+	}
+
+	return cmsstore.ApplyBlockSettings(block, schema, values)
+}
+
 func (m *MCP) toolBlockDelete(ctx context.Context, args map[string]any) (string, error) {
 	id := argString(args, "id")
 	if strings.TrimSpace(id) == "" {
@@ -1454,3 +1454,123 @@ func TestBlockList_WithPageID(t *testing.T) {
 		}
 	})
 }
+
+// settingsTestBlockType is a block type declaring a settings schema
+type settingsTestBlockType struct{}
+
+func (t *settingsTestBlockType) TypeKey() string   { return "mcp_settings_test" }
+func (t *settingsTestBlockType) TypeLabel() string { return "Settings Test" }
+func (t *settingsTestBlockType) Render(_ context.Context, _ cmsstore.BlockInterface, _ ...cmsstore.RenderOption) (string, error) {
+	return "", nil
+}
+func (t *settingsTestBlockType) GetAdminFields(_ cmsstore.BlockInterface, _ *http.Request) interface{} {
+	return nil
+}
+func (t *settingsTestBlockType) SaveAdminFields(_ *http.Request, _ cmsstore.BlockInterface) error {
+	return nil
+}
+func (t *settingsTestBlockType) GetCustomVariables() []cmsstore.BlockCustomVariable { return nil }
+func (t *settingsTestBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	return []cmsstore.BlockSettingDefinition{
+		{Name: "columns", Label: "Columns", Type: cmsstore.BLOCK_SETTING_TYPE_INT, Default: "3"},
+		{Name: "dark", Label: "Dark", Type: cmsstore.BLOCK_SETTING_TYPE_BOOL},
+	}
+}
+
+func callBlockUpsert(t *testing.T, serverURL string, arguments map[string]any) map[string]any {
+	t.Helper()
+
+	upsertBody, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "upsert",
+		"method":  "call_tool",
+		"params": map[string]any{
+			"tool_name": "block_upsert",
+			"arguments": arguments,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal payload: %v", err)
+	}
+
+	upsertResp, err := http.Post(serverURL, "application/json", bytes.NewBuffer(upsertBody))
+	if err != nil {
+		t.Fatalf("Failed to post request: %v", err)
+	}
+	defer upsertResp.Body.Close()
+
+	var response map[string]any
+	if err := json.NewDecoder(upsertResp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	return response
+}
+
+func TestBlockUpsert_Settings(t *testing.T) {
+	server, store, cleanup := initMCPServerWithStore(t)
+	defer cleanup()
+
+	cmsstore.RegisterCustomBlockType(&settingsTestBlockType{})
+
+	response := callBlockUpsert(t, server.URL, map[string]any{
+		"type":     "mcp_settings_test",
+		"settings": map[string]any{"columns": 4, "dark": true},
+	})
+
+	result, ok := response["result"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected response to have result, got %v", response)
+	}
+
+	text := result["content"].([]any)[0].(map[string]any)["text"].(string)
+
+	var blockData map[string]any
+	if err := json.Unmarshal([]byte(text), &blockData); err != nil {
+		t.Fatalf("Failed to unmarshal block data: %v", err)
+	}
+
+	block, err := store.BlockFindByID(context.Background(), cast.ToString(blockData["id"]))
+	if err != nil || block == nil {
+		t.Fatalf("Failed to find block: %v", err)
+	}
+	if block.Meta("columns") != "4" || block.Meta("dark") != "true" {
+		t.Errorf("Expected settings to be saved, got columns=%q dark=%q", block.Meta("columns"), block.Meta("dark"))
+	}
+}
+
+func TestBlockUpsert_Settings_ValidationError(t *testing.T) {
+	server, _, cleanup := initMCPServerWithStore(t)
+	defer cleanup()
+
+	cmsstore.RegisterCustomBlockType(&settingsTestBlockType{})
+
+	tests := []struct {
+		name        string
+		blockType   string
+		settings    any
+		expectedErr string
+	}{
+		{"invalid value", "mcp_settings_test", map[string]any{"columns": "many"}, "Columns must be a number"},
+		{"unknown setting", "mcp_settings_test", map[string]any{"color": "red"}, "unknown setting: color"},
+		{"not an object", "mcp_settings_test", "columns=4", "settings must be an object"},
+		{"no schema", "text", map[string]any{"columns": 4}, "block type has no settings schema: text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := callBlockUpsert(t, server.URL, map[string]any{
+				"type":     tt.blockType,
+				"settings": tt.settings,
+			})
+
+			errorObj, ok := response["error"].(map[string]any)
+			if !ok {
+				t.Fatalf("Expected error in response, got %v", response)
+			}
+			if errorObj["message"] != tt.expectedErr {
+				t.Errorf("Expected error message '%s', got '%s'", tt.expectedErr, errorObj["message"])
+			}
+		})
+	}
+}
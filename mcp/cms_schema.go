@@ -3,6 +3,8 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+
+	"github.com/dracory/cmsstore"
 )
 
 func (m *MCP) toolCmsSchema(_ context.Context, _ map[string]any) (string, error) {
@@ -160,6 +162,7 @@ func (m *MCP) toolCmsSchema(_ context.Context, _ map[string]any) (string, error)
 				{"name": "editor", "type": "string"},
 				{"name": "memo", "type": "string"},
 				{"name": "sequence", "type": "integer"},
+				{"name": "settings", "type": "object", "description": "Typed settings, validated against the settings schema of the block type (see block_types)"},
 			},
 			"returns": map[string]any{
 				"block": "block",
@@ -209,6 +212,24 @@ func (m *MCP) toolCmsSchema(_ context.Context, _ map[string]any) (string, error)
 			"arguments": []map[string]any{{"name": "id", "type": "string", "required": true}},
 			"returns":   map[string]any{"deleted": "boolean"},
 		},
+		"menu_item_move": map[string]any{
+			"arguments": []map[string]any{
+				{"name": "id", "type": "string", "required": true},
+				{"name": "parent_id", "type": "string"},
+				{"name": "position", "type": "integer"},
+			},
+			"returns": map[string]any{
+				"menu_id": "string",
+				"items":   "array[menu_item] (nested, with children)",
+			},
+		},
+		"menu_tree": map[string]any{
+			"arguments": []map[string]any{{"name": "menu_id", "type": "string", "required": true}},
+			"returns": map[string]any{
+				"menu_id": "string",
+				"items":   "array[menu_item] (nested, with children)",
+			},
+		},
 		"template_list": map[string]any{
 			"arguments": []map[string]any{
 				{"name": "limit", "type": "integer"},
@@ -391,9 +412,22 @@ func (m *MCP) toolCmsSchema(_ context.Context, _ map[string]any) (string, error)
 		},
 	}
 
+	// Block types, with the settings schema of the types declaring one
+	blockTypes := map[string]any{}
+	for typeKey, blockType := range m.store.BlockTypeRegistry().GetAll() {
+		blockTypeSchema := map[string]any{
+			"label": blockType.TypeLabel(),
+		}
+		if schema := cmsstore.BlockTypeSettingsSchema(blockType); len(schema) > 0 {
+			blockTypeSchema["settings"] = cmsstore.BlockSettingsJSONSchema(schema)
+		}
+		blockTypes[typeKey] = blockTypeSchema
+	}
+
 	respBytes, err := json.Marshal(map[string]any{
-		"entities": entities,
-		"tools":    tools,
+		"entities":    entities,
+		"tools":       tools,
+		"block_types": blockTypes,
 	})
 	if err != nil {
 		return "", err
@@ -175,6 +175,10 @@ func (m *MCP) handleToolsList(w http.ResponseWriter, _ context.Context, id any)
 					"editor":   map[string]any{"type": "string"},
 					"memo":     map[string]any{"type": "string"},
 					"sequence": map[string]any{"type": "integer"},
+					"settings": map[string]any{
+						"type":        "object",
+						"description": "Typed settings, validated against the settings schema of the block type (see cms_schema block_types)",
+					},
 				},
 			},
 		},
@@ -295,6 +299,28 @@ func (m *MCP) handleToolsList(w http.ResponseWriter, _ context.Context, id any)
 				},
 			},
 		},
+		{
+			"name":        "menu_item_move",
+			"description": "Move a CMS menu item under a parent menu item (empty for the top level), at a position among its siblings. The siblings are renumbered.",
+			"inputSchema": map[string]any{
+				"type":     "object",
+				"required": []string{"id"},
+				"properties": map[string]any{
+					"id":        map[string]any{"type": "string"},
+					"parent_id": map[string]any{"type": "string"},
+					"position":  map[string]any{"type": "integer"},
+				},
+			},
+		},
+		{
+			"name":        "menu_tree",
+			"description": "Get the menu items of a CMS menu as a tree",
+			"inputSchema": map[string]any{
+				"type":       "object",
+				"required":   []string{"menu_id"},
+				"properties": map[string]any{"menu_id": map[string]any{"type": "string"}},
+			},
+		},
 		// END: MENU TOOLS
 		// START: PAGE TOOLS
 		{
@@ -612,6 +638,10 @@ func (m *MCP) dispatchTool(ctx context.Context, toolName string, args map[string
 		return m.toolMenuItemUpsert(ctx, args)
 	case "menu_item_delete":
 		return m.toolMenuItemDelete(ctx, args)
+	case "menu_item_move":
+		return m.toolMenuItemMove(ctx, args)
+	case "menu_tree":
+		return m.toolMenuTree(ctx, args)
 	case "page_list":
 		return m.toolPageList(ctx, args)
 	case "page_upsert":
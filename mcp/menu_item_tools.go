@@ -269,3 +269,93 @@ func (m *MCP) toolMenuItemDelete(ctx context.Context, args map[string]any) (stri
 	}
 	return string(respBytes), nil
 }
+
+func (m *MCP) toolMenuTree(ctx context.Context, args map[string]any) (string, error) {
+	menuID := argString(args, "menu_id")
+	if strings.TrimSpace(menuID) == "" {
+		return "", errors.New("missing required parameter: menu_id")
+	}
+
+	menu, err := m.store.MenuFindByID(ctx, menuID)
+	if err != nil {
+		return "", err
+	}
+	if menu == nil {
+		return "", errors.New("menu not found")
+	}
+
+	return m.menuTreeResponse(ctx, menu.ID())
+}
+
+func (m *MCP) toolMenuItemMove(ctx context.Context, args map[string]any) (string, error) {
+	id := argString(args, "id")
+	if strings.TrimSpace(id) == "" {
+		return "", errors.New("missing required parameter: id")
+	}
+
+	menuItem, err := m.store.MenuItemFindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if menuItem == nil {
+		return "", errors.New("menu item not found")
+	}
+
+	parentID := argString(args, "parent_id")
+	if strings.TrimSpace(parentID) != "" {
+		parent, err := m.store.MenuItemFindByID(ctx, parentID)
+		if err != nil {
+			return "", err
+		}
+		if parent == nil {
+			return "", errors.New("parent menu item not found")
+		}
+		parentID = parent.ID()
+	}
+
+	position, _ := argInt(args, "position")
+
+	if err := m.store.MenuItemMove(ctx, menuItem.ID(), parentID, int(position)); err != nil {
+		return "", err
+	}
+
+	return m.menuTreeResponse(ctx, menuItem.MenuID())
+}
+
+// menuTreeResponse returns the menu item tree of the menu as JSON
+func (m *MCP) menuTreeResponse(ctx context.Context, menuID string) (string, error) {
+	tree, err := m.store.MenuTree(ctx, menuID)
+	if err != nil {
+		return "", err
+	}
+
+	respBytes, err := json.Marshal(map[string]any{
+		"menu_id": menuID,
+		"items":   menuTreeItems(tree),
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(respBytes), nil
+}
+
+// menuTreeItems converts the menu tree nodes to nested menu items
+func menuTreeItems(nodes []*cmsstore.MenuTreeNode) []map[string]any {
+	items := make([]map[string]any, 0, len(nodes))
+	for _, node := range nodes {
+		menuItem := node.MenuItem
+		items = append(items, map[string]any{
+			"id":        menuItem.ID(),
+			"name":      menuItem.Name(),
+			"type":      menuItem.Type(),
+			"url":       menuItem.URL(),
+			"target":    menuItem.Target(),
+			"status":    menuItem.Status(),
+			"page_id":   menuItem.PageID(),
+			"parent_id": menuItem.ParentID(),
+			"sequence":  menuItem.Sequence(),
+			"children":  menuTreeItems(node.Children),
+		})
+	}
+	return items
+}
@@ -1744,3 +1744,108 @@ func TestMenuItemList_WithOrdering(t *testing.T) {
 		}
 	})
 }
+
+func TestMenuTreeAndMenuItemMove(t *testing.T) {
+	server, store, cleanup := initMCPServerWithStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	menu := cmsstore.NewMenu()
+	menu.SetName("Main Menu")
+	menu.SetSiteID("Site_01")
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatalf("Failed to create menu: %v", err)
+	}
+
+	newItem := func(name, parentID string, sequence int) cmsstore.MenuItemInterface {
+		menuItem := cmsstore.NewMenuItem()
+		menuItem.SetName(name)
+		menuItem.SetMenuID(menu.ID())
+		menuItem.SetParentID(parentID)
+		menuItem.SetSequenceInt(sequence)
+		if err := store.MenuItemCreate(ctx, menuItem); err != nil {
+			t.Fatalf("Failed to create menu item: %v", err)
+		}
+		return menuItem
+	}
+
+	home := newItem("Home", "", 0)
+	about := newItem("About", "", 1)
+	newItem("Team", about.ID(), 0)
+
+	callTool := func(toolName string, arguments map[string]any) []byte {
+		body, err := json.Marshal(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      toolName,
+			"method":  "call_tool",
+			"params": map[string]any{
+				"tool_name": toolName,
+				"arguments": arguments,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to marshal payload: %v", err)
+		}
+
+		resp, err := http.Post(server.URL, "application/json", bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatalf("Failed to post request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		respBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		return respBytes
+	}
+
+	treeItems := func(respBytes []byte) []any {
+		var tree map[string]any
+		if err := json.Unmarshal([]byte(rpcResultText(t, respBytes)), &tree); err != nil {
+			t.Fatalf("Failed to unmarshal tree: %v", err)
+		}
+		items, ok := tree["items"].([]any)
+		if !ok {
+			t.Fatalf("Expected items array, got %T", tree["items"])
+		}
+		return items
+	}
+
+	items := treeItems(callTool("menu_tree", map[string]any{"menu_id": menu.ID()}))
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 top level items, got %d", len(items))
+	}
+	if children := items[1].(map[string]any)["children"].([]any); len(children) != 1 {
+		t.Errorf("Expected About to have 1 child, got %d", len(children))
+	}
+
+	items = treeItems(callTool("menu_item_move", map[string]any{
+		"id":        cmsstore.ShortenID(home.ID()),
+		"parent_id": cmsstore.ShortenID(about.ID()),
+		"position":  0,
+	}))
+
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 top level item, got %d", len(items))
+	}
+
+	children := items[0].(map[string]any)["children"].([]any)
+	if len(children) != 2 || children[0].(map[string]any)["name"] != "Home" {
+		t.Errorf("Expected Home to be the first child of About, got %v", children)
+	}
+
+	var response map[string]any
+	if err := json.Unmarshal(callTool("menu_item_move", map[string]any{
+		"id":        about.ID(),
+		"parent_id": home.ID(),
+	}), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if _, hasError := response["error"]; !hasError {
+		t.Error("Expected an error moving a menu item into its descendant")
+	}
+}
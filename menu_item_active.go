@@ -0,0 +1,88 @@
+package cmsstore
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// MenuItemIsActive returns true if the menu item links to the page being
+// rendered (see PageFromContext).
+//
+// The menu items with a page are compared by page ID, so the pattern
+// aliases, the query strings and the language prefixes do not matter.
+// The menu items without a page (i.e. URLs) are compared to the request
+// path instead.
+func MenuItemIsActive(ctx context.Context, menuItem MenuItemInterface) bool {
+	if menuItem == nil {
+		return false
+	}
+
+	if menuItem.PageID() != "" {
+		page := PageFromContext(ctx)
+		return page != nil && page.ID() == menuItem.PageID()
+	}
+
+	req := RequestFromContext(ctx)
+	if req == nil || req.URL == nil || menuItem.URL() == "" {
+		return false
+	}
+
+	itemURL, err := url.Parse(menuItem.URL())
+	if err != nil || itemURL.Path == "" || (itemURL.Host != "" && itemURL.Host != req.Host) {
+		return false
+	}
+
+	return strings.TrimSuffix(itemURL.Path, "/") == strings.TrimSuffix(req.URL.Path, "/")
+}
+
+// MenuItemIsActiveAncestor returns true if the menu item links to one of
+// the ancestors of the page being rendered (see PageAncestorsFromContext).
+//
+// The renderers also mark the menu items, whose descendant menu items are
+// active, as active ancestors.
+func MenuItemIsActiveAncestor(ctx context.Context, menuItem MenuItemInterface) bool {
+	if menuItem == nil || menuItem.PageID() == "" {
+		return false
+	}
+
+	for _, ancestor := range PageAncestorsFromContext(ctx) {
+		if ancestor.ID() == menuItem.PageID() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MenuItemsActiveAncestorIDs returns the IDs of the menu items which are
+// active ancestors: the parent menu items, up to the top level, of the
+// active menu items, and the menu items linking to the ancestors of the
+// page being rendered (see MenuItemIsActiveAncestor).
+func MenuItemsActiveAncestorIDs(ctx context.Context, menuItems []MenuItemInterface) map[string]bool {
+	ancestorIDs := map[string]bool{}
+
+	itemsByID := map[string]MenuItemInterface{}
+	for _, menuItem := range menuItems {
+		itemsByID[menuItem.ID()] = menuItem
+	}
+
+	for _, menuItem := range menuItems {
+		if MenuItemIsActiveAncestor(ctx, menuItem) {
+			ancestorIDs[menuItem.ID()] = true
+		}
+
+		if !MenuItemIsActive(ctx, menuItem) {
+			continue
+		}
+
+		// The visited items guard against cycles in corrupted data
+		visited := map[string]bool{menuItem.ID(): true}
+		for parent := itemsByID[menuItem.ParentID()]; parent != nil && !visited[parent.ID()]; parent = itemsByID[parent.ParentID()] {
+			visited[parent.ID()] = true
+			ancestorIDs[parent.ID()] = true
+		}
+	}
+
+	return ancestorIDs
+}
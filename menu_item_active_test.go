@@ -0,0 +1,100 @@
+package cmsstore
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestMenuItemIsActive_URL(t *testing.T) {
+	tests := []struct {
+		name        string
+		itemURL     string
+		currentPath string
+		expected    bool
+	}{
+		{"exact match", "/about", "/about", true},
+		{"trailing slash on item", "/about/", "/about", true},
+		{"trailing slash on current", "/about", "/about/", true},
+		{"query string", "/about?tab=1", "/about?tab=2", true},
+		{"different paths", "/about", "/contact", false},
+		{"root match", "/", "/", true},
+		{"empty item URL", "", "/about", false},
+		{"anchor", "#top", "/about", false},
+		{"other host", "https://example.org/about", "/about", false},
+		{"partial match", "/about", "/about-us", false},
+		{"subpath", "/about", "/about/team", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", test.currentPath, nil)
+			ctx := RequestToContext(context.Background(), req)
+
+			result := MenuItemIsActive(ctx, NewMenuItem().SetURL(test.itemURL))
+			if result != test.expected {
+				t.Errorf("MenuItemIsActive(%q, %q) = %v, want %v", test.itemURL, test.currentPath, result, test.expected)
+			}
+		})
+	}
+
+	if MenuItemIsActive(context.Background(), NewMenuItem().SetURL("/about")) {
+		t.Error("expected no active menu item without request")
+	}
+}
+
+func TestMenuItemIsActive_Page(t *testing.T) {
+	about := NewPage()
+	team := NewPage().SetParentID(about.ID())
+
+	// The page items are compared by page ID, not by URL
+	req, _ := http.NewRequest("GET", "/fr/team?tab=1", nil)
+	ctx := RequestToContext(context.Background(), req)
+	ctx = PageToContext(ctx, team)
+	ctx = PageAncestorsToContext(ctx, []PageInterface{about})
+
+	teamItem := NewMenuItem().SetPageID(team.ID()).SetURL("/team")
+	aboutItem := NewMenuItem().SetPageID(about.ID()).SetURL("/fr/team")
+
+	if !MenuItemIsActive(ctx, teamItem) {
+		t.Error("expected the item of the page to be active")
+	}
+	if MenuItemIsActive(ctx, aboutItem) {
+		t.Error("expected the item of another page not to be active, whatever its URL")
+	}
+	if !MenuItemIsActiveAncestor(ctx, aboutItem) {
+		t.Error("expected the item of the parent page to be an active ancestor")
+	}
+	if MenuItemIsActiveAncestor(ctx, teamItem) {
+		t.Error("expected the item of the page not to be an active ancestor")
+	}
+	if MenuItemIsActiveAncestor(context.Background(), aboutItem) {
+		t.Error("expected no active ancestor without page ancestors")
+	}
+}
+
+func TestMenuItemsActiveAncestorIDs(t *testing.T) {
+	about := NewPage()
+	team := NewPage().SetParentID(about.ID())
+
+	ctx := PageToContext(context.Background(), team)
+	ctx = PageAncestorsToContext(ctx, []PageInterface{about})
+
+	company := NewMenuItem().SetURL("/company")
+	people := NewMenuItem().SetParentID(company.ID()).SetURL("/people")
+	teamItem := NewMenuItem().SetParentID(people.ID()).SetPageID(team.ID())
+	aboutItem := NewMenuItem().SetPageID(about.ID())
+	contact := NewMenuItem().SetURL("/contact")
+
+	ancestorIDs := MenuItemsActiveAncestorIDs(ctx, []MenuItemInterface{company, people, teamItem, aboutItem, contact})
+
+	for _, item := range []MenuItemInterface{company, people, aboutItem} {
+		if !ancestorIDs[item.ID()] {
+			t.Errorf("expected %q to be an active ancestor", item.URL())
+		}
+	}
+
+	if ancestorIDs[teamItem.ID()] || ancestorIDs[contact.ID()] {
+		t.Errorf("expected only the ancestors to be active ancestors, got %v", ancestorIDs)
+	}
+}
@@ -0,0 +1,408 @@
+package cmsstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Menu Item Types
+const (
+	// MENU_ITEM_TYPE_PAGE links to a page (PageID)
+	MENU_ITEM_TYPE_PAGE = "page"
+
+	// MENU_ITEM_TYPE_URL links to an internal or external URL
+	MENU_ITEM_TYPE_URL = "url"
+
+	// MENU_ITEM_TYPE_ANCHOR links to an anchor, on the page (PageID)
+	// if set, or else on the current page
+	MENU_ITEM_TYPE_ANCHOR = "anchor"
+
+	// MENU_ITEM_TYPE_ENTITY links to a custom entity (EntityID), the URL
+	// is a pattern with the entity placeholders, i.e. "/products/[[handle]]"
+	MENU_ITEM_TYPE_ENTITY = "entity"
+
+	// MENU_ITEM_TYPE_SEPARATOR is a divider between the items
+	MENU_ITEM_TYPE_SEPARATOR = "separator"
+
+	// MENU_ITEM_TYPE_HEADING is a title grouping the items, not a link
+	MENU_ITEM_TYPE_HEADING = "heading"
+
+	// MENU_ITEM_TYPE_MEGA_MENU is a panel displaying a block (BlockID)
+	MENU_ITEM_TYPE_MEGA_MENU = "mega_menu"
+)
+
+// Menu Item Meta Keys
+const (
+	// MENU_ITEM_META_ANCHOR is the anchor of MENU_ITEM_TYPE_ANCHOR, without "#"
+	MENU_ITEM_META_ANCHOR = "anchor"
+
+	// MENU_ITEM_META_BLOCK_ID is the block of MENU_ITEM_TYPE_MEGA_MENU
+	MENU_ITEM_META_BLOCK_ID = "block_id"
+
+	// MENU_ITEM_META_CSS_CLASS is the CSS class of the menu item
+	MENU_ITEM_META_CSS_CLASS = "css_class"
+
+	// MENU_ITEM_META_ENTITY_ID is the custom entity of MENU_ITEM_TYPE_ENTITY
+	MENU_ITEM_META_ENTITY_ID = "entity_id"
+
+	// MENU_ITEM_META_ICON is the icon CSS class of the menu item,
+	// i.e. "bi bi-house"
+	MENU_ITEM_META_ICON = "icon"
+
+	// MENU_ITEM_META_LABEL_PREFIX prefixes the language of the
+	// per-language labels, i.e. "label_fr"
+	MENU_ITEM_META_LABEL_PREFIX = "label_"
+
+	// MENU_ITEM_META_REL is the rel attribute of the link,
+	// i.e. "nofollow noopener"
+	MENU_ITEM_META_REL = "rel"
+
+	// MENU_ITEM_META_TYPE is the type of the menu item, MENU_ITEM_TYPE_*
+	MENU_ITEM_META_TYPE = "type"
+
+	// MENU_ITEM_META_URL_PREFIX prefixes the language of the
+	// per-language URLs, i.e. "url_fr"
+	MENU_ITEM_META_URL_PREFIX = "url_"
+
+	// MENU_ITEM_META_VISIBILITY are the visibility rules (JSON),
+	// see MenuItemVisibilityRules
+	MENU_ITEM_META_VISIBILITY = "visibility"
+)
+
+// MenuItemTypes returns the supported menu item types
+func MenuItemTypes() []string {
+	return []string{
+		MENU_ITEM_TYPE_PAGE,
+		MENU_ITEM_TYPE_URL,
+		MENU_ITEM_TYPE_ANCHOR,
+		MENU_ITEM_TYPE_ENTITY,
+		MENU_ITEM_TYPE_SEPARATOR,
+		MENU_ITEM_TYPE_HEADING,
+		MENU_ITEM_TYPE_MEGA_MENU,
+	}
+}
+
+// Anchor returns the anchor of the menu item, without "#"
+func (o *menuItemImplementation) Anchor() string {
+	return o.Meta(MENU_ITEM_META_ANCHOR)
+}
+
+// SetAnchor sets the anchor of the menu item, a leading "#" is removed
+func (o *menuItemImplementation) SetAnchor(anchor string) MenuItemInterface {
+	o.setMetaOrDelete(MENU_ITEM_META_ANCHOR, strings.TrimPrefix(anchor, "#"))
+	return o
+}
+
+// BlockID returns the block displayed by a mega menu item
+func (o *menuItemImplementation) BlockID() string {
+	return o.Meta(MENU_ITEM_META_BLOCK_ID)
+}
+
+// SetBlockID sets the block displayed by a mega menu item
+func (o *menuItemImplementation) SetBlockID(blockID string) MenuItemInterface {
+	o.setMetaOrDelete(MENU_ITEM_META_BLOCK_ID, blockID)
+	return o
+}
+
+// CSSClass returns the CSS class of the menu item
+func (o *menuItemImplementation) CSSClass() string {
+	return o.Meta(MENU_ITEM_META_CSS_CLASS)
+}
+
+// SetCSSClass sets the CSS class of the menu item
+func (o *menuItemImplementation) SetCSSClass(cssClass string) MenuItemInterface {
+	o.setMetaOrDelete(MENU_ITEM_META_CSS_CLASS, cssClass)
+	return o
+}
+
+// EntityID returns the custom entity linked by an entity menu item
+func (o *menuItemImplementation) EntityID() string {
+	return o.Meta(MENU_ITEM_META_ENTITY_ID)
+}
+
+// SetEntityID sets the custom entity linked by an entity menu item
+func (o *menuItemImplementation) SetEntityID(entityID string) MenuItemInterface {
+	o.setMetaOrDelete(MENU_ITEM_META_ENTITY_ID, entityID)
+	return o
+}
+
+// Icon returns the icon CSS class of the menu item
+func (o *menuItemImplementation) Icon() string {
+	return o.Meta(MENU_ITEM_META_ICON)
+}
+
+// SetIcon sets the icon CSS class of the menu item
+func (o *menuItemImplementation) SetIcon(icon string) MenuItemInterface {
+	o.setMetaOrDelete(MENU_ITEM_META_ICON, icon)
+	return o
+}
+
+// Label returns the label of the menu item in the language,
+// the name if the language has no label
+func (o *menuItemImplementation) Label(language string) string {
+	if language != "" {
+		if label := o.Meta(MENU_ITEM_META_LABEL_PREFIX + language); label != "" {
+			return label
+		}
+	}
+
+	return o.Name()
+}
+
+// Labels returns the per-language labels of the menu item,
+// keyed by language
+func (o *menuItemImplementation) Labels() map[string]string {
+	return o.metasByLanguage(MENU_ITEM_META_LABEL_PREFIX)
+}
+
+// SetLabel sets the label of the menu item in the language,
+// an empty label removes it
+func (o *menuItemImplementation) SetLabel(language string, label string) MenuItemInterface {
+	if language == "" {
+		return o
+	}
+
+	o.setMetaOrDelete(MENU_ITEM_META_LABEL_PREFIX+language, label)
+	return o
+}
+
+// Rel returns the rel attribute of the menu item link
+func (o *menuItemImplementation) Rel() string {
+	return o.Meta(MENU_ITEM_META_REL)
+}
+
+// SetRel sets the rel attribute of the menu item link
+func (o *menuItemImplementation) SetRel(rel string) MenuItemInterface {
+	o.setMetaOrDelete(MENU_ITEM_META_REL, rel)
+	return o
+}
+
+// Type returns the type of the menu item, one of the MENU_ITEM_TYPE_*
+// constants. Menu items without a type (i.e. created before the types
+// were introduced) are pages if they have a page, or else URLs.
+func (o *menuItemImplementation) Type() string {
+	if menuItemType := o.Meta(MENU_ITEM_META_TYPE); menuItemType != "" {
+		return menuItemType
+	}
+
+	if o.PageID() != "" {
+		return MENU_ITEM_TYPE_PAGE
+	}
+
+	return MENU_ITEM_TYPE_URL
+}
+
+// SetType sets the type of the menu item, one of the MENU_ITEM_TYPE_*
+// constants
+func (o *menuItemImplementation) SetType(menuItemType string) MenuItemInterface {
+	o.setMetaOrDelete(MENU_ITEM_META_TYPE, menuItemType)
+	return o
+}
+
+// TranslatedURL returns the URL of the menu item in the language,
+// the URL if the language has no URL
+func (o *menuItemImplementation) TranslatedURL(language string) string {
+	if language != "" {
+		if url := o.Meta(MENU_ITEM_META_URL_PREFIX + language); url != "" {
+			return url
+		}
+	}
+
+	return o.URL()
+}
+
+// TranslatedURLs returns the per-language URLs of the menu item,
+// keyed by language
+func (o *menuItemImplementation) TranslatedURLs() map[string]string {
+	return o.metasByLanguage(MENU_ITEM_META_URL_PREFIX)
+}
+
+// SetTranslatedURL sets the URL of the menu item in the language,
+// an empty URL removes it
+func (o *menuItemImplementation) SetTranslatedURL(language string, url string) MenuItemInterface {
+	if language == "" {
+		return o
+	}
+
+	o.setMetaOrDelete(MENU_ITEM_META_URL_PREFIX+language, url)
+	return o
+}
+
+// metasByLanguage returns the non-empty metas with the prefix,
+// keyed by the language following the prefix
+func (o *menuItemImplementation) metasByLanguage(prefix string) map[string]string {
+	values := map[string]string{}
+
+	metas, err := o.Metas()
+	if err != nil {
+		return values
+	}
+
+	for key, value := range metas {
+		if language, found := strings.CutPrefix(key, prefix); found && language != "" && value != "" {
+			values[language] = value
+		}
+	}
+
+	return values
+}
+
+// setMetaOrDelete sets the meta, or removes it if the value is empty
+func (o *menuItemImplementation) setMetaOrDelete(key string, value string) {
+	metas, err := o.Metas()
+
+	if err != nil || metas == nil {
+		metas = map[string]string{}
+	}
+
+	if value == "" {
+		if _, exists := metas[key]; !exists {
+			return
+		}
+
+		delete(metas, key)
+	} else {
+		metas[key] = value
+	}
+
+	// The metas are a map of strings, which always marshals
+	_ = o.SetMetas(metas)
+}
+
+// MenuItemVisibilityRules returns the visibility rules of the menu item,
+// which are the same as the block visibility rules (see BlockVisibility)
+func MenuItemVisibilityRules(menuItem MenuItemInterface) (BlockVisibility, error) {
+	rules := BlockVisibility{}
+
+	if menuItem == nil {
+		return rules, nil
+	}
+
+	value := menuItem.Meta(MENU_ITEM_META_VISIBILITY)
+
+	if value == "" {
+		return rules, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return BlockVisibility{}, err
+	}
+
+	return rules, nil
+}
+
+// SetMenuItemVisibilityRules validates and stores the visibility rules of
+// the menu item, the dates normalized to BLOCK_VISIBILITY_DATE_FORMAT
+func SetMenuItemVisibilityRules(menuItem MenuItemInterface, rules BlockVisibility) error {
+	if menuItem == nil {
+		return errors.New("menu item is nil")
+	}
+
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	metas, err := menuItem.Metas()
+	if err != nil {
+		return err
+	}
+
+	if metas == nil {
+		metas = map[string]string{}
+	}
+
+	if rules.IsEmpty() {
+		if _, exists := metas[MENU_ITEM_META_VISIBILITY]; !exists {
+			return nil
+		}
+
+		delete(metas, MENU_ITEM_META_VISIBILITY)
+		return menuItem.SetMetas(metas)
+	}
+
+	from, _ := parseBlockVisibilityDate(rules.DateFrom)
+	to, _ := parseBlockVisibilityDate(rules.DateTo)
+	rules.DateFrom = formatBlockVisibilityDate(from)
+	rules.DateTo = formatBlockVisibilityDate(to)
+
+	value, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+
+	metas[MENU_ITEM_META_VISIBILITY] = string(value)
+
+	return menuItem.SetMetas(metas)
+}
+
+// MenuItemIsVisible returns true if the menu item is displayed to the
+// visitor, menu items with invalid rules are not displayed
+func MenuItemIsVisible(menuItem MenuItemInterface, visitor BlockVisitor) bool {
+	rules, err := MenuItemVisibilityRules(menuItem)
+	if err != nil {
+		return false
+	}
+
+	return rules.IsVisible(menuItem.ID(), visitor)
+}
+
+// MenuItemsHaveVisibilityRules returns true if any of the menu items has
+// visibility rules, the menus rendering them differ per visitor and must
+// not be cached
+func MenuItemsHaveVisibilityRules(menuItems []MenuItemInterface) bool {
+	for _, menuItem := range menuItems {
+		if menuItem.Meta(MENU_ITEM_META_VISIBILITY) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MenuVisibilityRulesCache remembers which menus have menu items with
+// visibility rules, until the menus are changed (see CHANGE_KIND_MENUS).
+// The cache policies of the menu block types use it to not list the menu
+// items on every cache lookup. The zero value is ready to use.
+type MenuVisibilityRulesCache struct {
+	mu      sync.Mutex
+	version uint64
+	menus   map[string]bool
+}
+
+// MenuHasVisibilityRules returns true if any of the menu items of the menu
+// has visibility rules, see MenuItemsHaveVisibilityRules
+func (cache *MenuVisibilityRulesCache) MenuHasVisibilityRules(ctx context.Context, store StoreInterface, menuID string) (bool, error) {
+	version := store.ChangeVersion(CHANGE_KIND_MENUS)
+
+	cache.mu.Lock()
+	if cache.menus == nil || cache.version != version {
+		cache.menus = map[string]bool{}
+		cache.version = version
+	}
+
+	hasRules, found := cache.menus[menuID]
+	cache.mu.Unlock()
+
+	if found {
+		return hasRules, nil
+	}
+
+	menuItems, err := store.MenuItemList(ctx, MenuItemQuery().SetMenuID(menuID))
+
+	if err != nil {
+		return false, err
+	}
+
+	hasRules = MenuItemsHaveVisibilityRules(menuItems)
+
+	cache.mu.Lock()
+	if cache.version == version {
+		cache.menus[menuID] = hasRules
+	}
+	cache.mu.Unlock()
+
+	return hasRules, nil
+}
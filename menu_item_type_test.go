@@ -0,0 +1,167 @@
+package cmsstore
+
+import (
+	"testing"
+)
+
+func TestMenuItemTypeDefaults(t *testing.T) {
+	menuItem := NewMenuItem()
+
+	if menuItem.Type() != MENU_ITEM_TYPE_URL {
+		t.Errorf("expected type %q, got %q", MENU_ITEM_TYPE_URL, menuItem.Type())
+	}
+
+	menuItem.SetPageID("PAGE_01")
+
+	if menuItem.Type() != MENU_ITEM_TYPE_PAGE {
+		t.Errorf("expected type %q, got %q", MENU_ITEM_TYPE_PAGE, menuItem.Type())
+	}
+
+	menuItem.SetType(MENU_ITEM_TYPE_HEADING)
+
+	if menuItem.Type() != MENU_ITEM_TYPE_HEADING {
+		t.Errorf("expected type %q, got %q", MENU_ITEM_TYPE_HEADING, menuItem.Type())
+	}
+
+	menuItem.SetType("")
+
+	if menuItem.Meta(MENU_ITEM_META_TYPE) != "" {
+		t.Error("expected the type meta to be removed")
+	}
+}
+
+func TestMenuItemAttributes(t *testing.T) {
+	menuItem := NewMenuItem().
+		SetName("Products").
+		SetIcon("bi bi-box").
+		SetCSSClass("highlight").
+		SetRel("nofollow").
+		SetAnchor("#pricing").
+		SetEntityID("ENTITY_01").
+		SetBlockID("BLOCK_01").
+		SetLabel("fr", "Produits").
+		SetLabel("de", "Produkte")
+
+	if menuItem.Icon() != "bi bi-box" {
+		t.Errorf("expected icon %q, got %q", "bi bi-box", menuItem.Icon())
+	}
+	if menuItem.CSSClass() != "highlight" {
+		t.Errorf("expected CSS class %q, got %q", "highlight", menuItem.CSSClass())
+	}
+	if menuItem.Rel() != "nofollow" {
+		t.Errorf("expected rel %q, got %q", "nofollow", menuItem.Rel())
+	}
+	if menuItem.Anchor() != "pricing" {
+		t.Errorf("expected anchor %q, got %q", "pricing", menuItem.Anchor())
+	}
+	if menuItem.EntityID() != "ENTITY_01" {
+		t.Errorf("expected entity ID %q, got %q", "ENTITY_01", menuItem.EntityID())
+	}
+	if menuItem.BlockID() != "BLOCK_01" {
+		t.Errorf("expected block ID %q, got %q", "BLOCK_01", menuItem.BlockID())
+	}
+	if menuItem.Label("fr") != "Produits" {
+		t.Errorf("expected label %q, got %q", "Produits", menuItem.Label("fr"))
+	}
+	if menuItem.Label("es") != "Products" {
+		t.Errorf("expected the name for a language without label, got %q", menuItem.Label("es"))
+	}
+	if len(menuItem.Labels()) != 2 {
+		t.Errorf("expected 2 labels, got %v", menuItem.Labels())
+	}
+
+	menuItem.SetURL("/products").SetTranslatedURL("fr", "/fr/produits")
+
+	if menuItem.TranslatedURL("fr") != "/fr/produits" {
+		t.Errorf("expected URL %q, got %q", "/fr/produits", menuItem.TranslatedURL("fr"))
+	}
+	if menuItem.TranslatedURL("es") != "/products" {
+		t.Errorf("expected the URL for a language without URL, got %q", menuItem.TranslatedURL("es"))
+	}
+	if len(menuItem.TranslatedURLs()) != 1 {
+		t.Errorf("expected 1 URL, got %v", menuItem.TranslatedURLs())
+	}
+
+	menuItem.SetLabel("de", "").SetIcon("")
+
+	if len(menuItem.Labels()) != 1 {
+		t.Errorf("expected 1 label, got %v", menuItem.Labels())
+	}
+	if menuItem.Icon() != "" {
+		t.Errorf("expected the icon to be removed, got %q", menuItem.Icon())
+	}
+}
+
+func TestMenuItemVisibilityRules(t *testing.T) {
+	menuItem := NewMenuItem()
+
+	err := SetMenuItemVisibilityRules(menuItem, BlockVisibility{LoggedIn: "maybe"})
+
+	if err == nil {
+		t.Fatal("expected an error for invalid rules")
+	}
+
+	err = SetMenuItemVisibilityRules(menuItem, BlockVisibility{
+		Languages: []string{"fr"},
+		DateFrom:  "2020-01-01T10:00",
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	rules, err := MenuItemVisibilityRules(menuItem)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if rules.DateFrom != "2020-01-01 10:00:00" {
+		t.Errorf("expected the normalized date, got %q", rules.DateFrom)
+	}
+
+	if !MenuItemIsVisible(menuItem, BlockVisitor{Language: "fr"}) {
+		t.Error("expected the menu item to be visible in French")
+	}
+	if MenuItemIsVisible(menuItem, BlockVisitor{Language: "en"}) {
+		t.Error("expected the menu item to be hidden in English")
+	}
+	if !MenuItemsHaveVisibilityRules([]MenuItemInterface{NewMenuItem(), menuItem}) {
+		t.Error("expected the menu items to have visibility rules")
+	}
+
+	if err := SetMenuItemVisibilityRules(menuItem, BlockVisibility{}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if menuItem.Meta(MENU_ITEM_META_VISIBILITY) != "" {
+		t.Error("expected the visibility rules to be removed")
+	}
+}
+
+func TestMenuItemVisibilityLoggedInFunc(t *testing.T) {
+	menuItem := NewMenuItem()
+
+	if err := SetMenuItemVisibilityRules(menuItem, BlockVisibility{LoggedIn: BLOCK_VISIBILITY_LOGGED_IN_YES}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	calls := 0
+	visitor := BlockVisitor{LoggedInFunc: func() bool {
+		calls++
+		return true
+	}}
+
+	if !MenuItemIsVisible(menuItem, visitor) {
+		t.Error("expected the menu item to be visible to a logged in visitor")
+	}
+	if calls != 1 {
+		t.Errorf("expected the logged in callback to be called once, got %d", calls)
+	}
+
+	calls = 0
+	if !MenuItemIsVisible(NewMenuItem(), visitor) {
+		t.Error("expected a menu item without rules to be visible")
+	}
+	if calls != 0 {
+		t.Errorf("expected the logged in callback not to be called, got %d", calls)
+	}
+}
@@ -0,0 +1,237 @@
+package cmsstore
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// MenuRenderer renders the menu, navbar and breadcrumbs blocks in an
+// additional rendering mode (i.e. Tailwind, Bulma, Go templates), next to
+// the built-in plain and Bootstrap 5 modes.
+//
+// The registered rendering modes are listed in the rendering mode dropdown
+// of the blocks they support, and take precedence over the built-in modes
+// with the same key.
+//
+// Example:
+//
+//	type TailwindMenuRenderer struct{}
+//
+//	func (r *TailwindMenuRenderer) Mode() string { return "tailwind" }
+//
+//	func (r *TailwindMenuRenderer) Label() string { return "Tailwind" }
+//
+//	func (r *TailwindMenuRenderer) BlockTypes() []string {
+//	    return []string{cmsstore.BLOCK_TYPE_MENU, cmsstore.BLOCK_TYPE_NAVBAR}
+//	}
+//
+//	func (r *TailwindMenuRenderer) Render(ctx context.Context, data cmsstore.MenuRenderData) (string, error) {
+//	    // Render data.Items, i.e. with a Go template
+//	}
+//
+// To register:
+//
+//	cmsstore.RegisterMenuRenderer(&TailwindMenuRenderer{})
+type MenuRenderer interface {
+	// Mode returns the unique key of the rendering mode, stored in the
+	// rendering mode meta of the blocks. Example: "tailwind"
+	Mode() string
+
+	// Label returns the human-readable name of the rendering mode,
+	// displayed in the admin dropdowns. Example: "Tailwind"
+	Label() string
+
+	// BlockTypes returns the block types the rendering mode supports
+	// (BLOCK_TYPE_MENU, BLOCK_TYPE_NAVBAR, BLOCK_TYPE_BREADCRUMBS),
+	// all of them if empty
+	BlockTypes() []string
+
+	// Render renders the menu tree of the block
+	Render(ctx context.Context, data MenuRenderData) (string, error)
+}
+
+// MenuRenderData is the data of the block rendered by a MenuRenderer
+type MenuRenderData struct {
+	// BlockType is the type of the rendered block, i.e. BLOCK_TYPE_NAVBAR
+	BlockType string
+
+	// Block is the rendered block, for the settings specific to the
+	// block type (i.e. the navbar brand)
+	Block BlockInterface
+
+	// Style is the style of the block, i.e. BLOCK_MENU_STYLE_VERTICAL
+	Style string
+
+	// CSSClass is the CSS class of the block
+	CSSClass string
+
+	// CSSID is the CSS ID of the block
+	CSSID string
+
+	// Attributes are the runtime attributes of the block reference,
+	// i.e. [[block id='menu_main' depth='2']]
+	Attributes map[string]string
+
+	// Items is the menu tree. For the breadcrumbs, it is the trail from
+	// the home page to the current page, without children.
+	Items []*MenuRenderItem
+}
+
+// MenuRenderItem is an item of the menu tree rendered by a MenuRenderer
+type MenuRenderItem struct {
+	// MenuItem is the resolved menu item, for its type, icon, target,
+	// etc. Nil for the breadcrumbs.
+	MenuItem MenuItemInterface
+
+	// Name is the (translated) name of the item
+	Name string
+
+	// URL is the URL of the item, the alias of the page if the menu
+	// item has no URL
+	URL string
+
+	// Active is true for the item of the page being rendered,
+	// see MenuItemIsActive
+	Active bool
+
+	// ActiveAncestor is true for the items above the active item,
+	// see MenuItemsActiveAncestorIDs
+	ActiveAncestor bool
+
+	// Children are the child items, in sequence order
+	Children []*MenuRenderItem
+}
+
+// MenuRenderTree returns the menu items, as resolved by MenuItemsResolve,
+// as a tree with their active trail. The items whose parent is not in the
+// list are returned at the top level.
+//
+// The itemURL function returns the URL of a menu item, i.e. the alias of
+// its page if it has no URL.
+func MenuRenderTree(ctx context.Context, menuItems []MenuItemInterface, itemURL func(MenuItemInterface) string) []*MenuRenderItem {
+	activeAncestorIDs := MenuItemsActiveAncestorIDs(ctx, menuItems)
+	parentIDs := menuItemParentIDs(menuItems)
+
+	nodes := map[string]*MenuRenderItem{}
+	for _, menuItem := range menuItems {
+		nodes[menuItem.ID()] = &MenuRenderItem{
+			MenuItem:       menuItem,
+			Name:           menuItem.Name(),
+			URL:            itemURL(menuItem),
+			Active:         MenuItemIsActive(ctx, menuItem),
+			ActiveAncestor: activeAncestorIDs[menuItem.ID()],
+			Children:       []*MenuRenderItem{},
+		}
+	}
+
+	roots := []*MenuRenderItem{}
+	for _, menuItem := range menuItems {
+		node := nodes[menuItem.ID()]
+		parentID, found := parentIDs[menuItem.ID()]
+
+		if !found {
+			roots = append(roots, node)
+			continue
+		}
+
+		nodes[parentID].Children = append(nodes[parentID].Children, node)
+	}
+
+	return roots
+}
+
+// MenuRendererRegistry manages the registered menu rendering modes.
+//
+// The registry is thread-safe and can be accessed concurrently.
+type MenuRendererRegistry struct {
+	renderers map[string]MenuRenderer
+	mu        sync.RWMutex
+}
+
+var globalMenuRendererRegistry = NewMenuRendererRegistry()
+
+// NewMenuRendererRegistry creates a new, empty menu renderer registry.
+func NewMenuRendererRegistry() *MenuRendererRegistry {
+	return &MenuRendererRegistry{
+		renderers: make(map[string]MenuRenderer),
+	}
+}
+
+// DefaultMenuRendererRegistry returns the default global registry, used
+// by the menu, navbar and breadcrumbs blocks.
+func DefaultMenuRendererRegistry() *MenuRendererRegistry {
+	return globalMenuRendererRegistry
+}
+
+// RegisterMenuRenderer registers a rendering mode in the default registry.
+func RegisterMenuRenderer(renderer MenuRenderer) {
+	globalMenuRendererRegistry.Register(renderer)
+}
+
+// GetMenuRenderer returns the renderer of the rendering mode for the block
+// type from the default registry, nil if none is registered.
+func GetMenuRenderer(blockType, mode string) MenuRenderer {
+	return globalMenuRendererRegistry.Get(blockType, mode)
+}
+
+// GetMenuRenderers returns the renderers supporting the block type from
+// the default registry, sorted by label.
+func GetMenuRenderers(blockType string) []MenuRenderer {
+	return globalMenuRendererRegistry.GetAll(blockType)
+}
+
+// Register registers a rendering mode, replacing the renderer registered
+// with the same mode, if any.
+func (r *MenuRendererRegistry) Register(renderer MenuRenderer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.renderers[renderer.Mode()] = renderer
+}
+
+// Unregister removes a rendering mode.
+func (r *MenuRendererRegistry) Unregister(mode string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.renderers, mode)
+}
+
+// Get returns the renderer of the rendering mode, if it supports the
+// block type, nil otherwise.
+func (r *MenuRendererRegistry) Get(blockType, mode string) MenuRenderer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	renderer, found := r.renderers[mode]
+	if !found || !menuRendererSupports(renderer, blockType) {
+		return nil
+	}
+
+	return renderer
+}
+
+// GetAll returns the renderers supporting the block type, sorted by label.
+func (r *MenuRendererRegistry) GetAll(blockType string) []MenuRenderer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	renderers := []MenuRenderer{}
+	for _, renderer := range r.renderers {
+		if menuRendererSupports(renderer, blockType) {
+			renderers = append(renderers, renderer)
+		}
+	}
+
+	sort.Slice(renderers, func(i, j int) bool {
+		return renderers[i].Label() < renderers[j].Label()
+	})
+
+	return renderers
+}
+
+// menuRendererSupports returns true if the renderer supports the block type
+func menuRendererSupports(renderer MenuRenderer, blockType string) bool {
+	blockTypes := renderer.BlockTypes()
+	return len(blockTypes) == 0 || slices.Contains(blockTypes, blockType)
+}
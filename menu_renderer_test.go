@@ -0,0 +1,96 @@
+package cmsstore
+
+import (
+	"context"
+	"testing"
+)
+
+type testMenuRenderer struct {
+	mode       string
+	label      string
+	blockTypes []string
+}
+
+func (r *testMenuRenderer) Mode() string         { return r.mode }
+func (r *testMenuRenderer) Label() string        { return r.label }
+func (r *testMenuRenderer) BlockTypes() []string { return r.blockTypes }
+
+func (r *testMenuRenderer) Render(ctx context.Context, data MenuRenderData) (string, error) {
+	return r.mode, nil
+}
+
+func TestMenuRendererRegistry(t *testing.T) {
+	registry := NewMenuRendererRegistry()
+
+	registry.Register(&testMenuRenderer{mode: "tailwind", label: "Tailwind"})
+	registry.Register(&testMenuRenderer{mode: "bulma", label: "Bulma", blockTypes: []string{BLOCK_TYPE_NAVBAR}})
+
+	if registry.Get(BLOCK_TYPE_MENU, "tailwind") == nil {
+		t.Error("expected the renderer without block types to support menus")
+	}
+	if registry.Get(BLOCK_TYPE_MENU, "bulma") != nil {
+		t.Error("expected the navbar renderer not to support menus")
+	}
+	if registry.Get(BLOCK_TYPE_NAVBAR, "bulma") == nil {
+		t.Error("expected the navbar renderer to support navbars")
+	}
+	if registry.Get(BLOCK_TYPE_NAVBAR, "missing") != nil {
+		t.Error("expected no renderer for an unregistered mode")
+	}
+
+	renderers := registry.GetAll(BLOCK_TYPE_NAVBAR)
+	if len(renderers) != 2 || renderers[0].Mode() != "bulma" || renderers[1].Mode() != "tailwind" {
+		t.Errorf("expected the navbar renderers sorted by label, got %v", renderers)
+	}
+
+	if len(registry.GetAll(BLOCK_TYPE_BREADCRUMBS)) != 1 {
+		t.Errorf("expected 1 breadcrumbs renderer, got %v", registry.GetAll(BLOCK_TYPE_BREADCRUMBS))
+	}
+
+	registry.Unregister("tailwind")
+
+	if registry.Get(BLOCK_TYPE_MENU, "tailwind") != nil {
+		t.Error("expected the renderer to be unregistered")
+	}
+}
+
+func TestMenuRenderTree(t *testing.T) {
+	about := NewPage().SetAlias("/about")
+	team := NewPage().SetParentID(about.ID()).SetAlias("/about/team")
+
+	ctx := PageToContext(context.Background(), team)
+	ctx = PageAncestorsToContext(ctx, []PageInterface{about})
+
+	company := NewMenuItem().SetName("Company").SetURL("/company")
+	teamItem := NewMenuItem().SetName("Team").SetParentID(company.ID()).SetPageID(team.ID())
+	orphan := NewMenuItem().SetName("Orphan").SetParentID("MISSING").SetURL("/orphan")
+	contact := NewMenuItem().SetName("Contact").SetURL("/contact")
+
+	tree := MenuRenderTree(ctx, []MenuItemInterface{company, teamItem, orphan, contact}, func(item MenuItemInterface) string {
+		if item.PageID() == team.ID() {
+			return team.Alias()
+		}
+		return item.URL()
+	})
+
+	if len(tree) != 3 {
+		t.Fatalf("expected 3 top level items, got %d", len(tree))
+	}
+
+	if tree[0].Name != "Company" || !tree[0].ActiveAncestor || tree[0].Active {
+		t.Errorf("expected Company to be an active ancestor, got %+v", tree[0])
+	}
+
+	if len(tree[0].Children) != 1 {
+		t.Fatalf("expected 1 child item, got %d", len(tree[0].Children))
+	}
+
+	child := tree[0].Children[0]
+	if child.URL != "/about/team" || !child.Active || child.MenuItem != teamItem {
+		t.Errorf("expected the active page item, got %+v", child)
+	}
+
+	if tree[1].Name != "Orphan" || tree[2].Name != "Contact" || tree[2].Active || tree[2].ActiveAncestor {
+		t.Errorf("expected the orphan at the top level and Contact inactive, got %+v %+v", tree[1], tree[2])
+	}
+}
@@ -0,0 +1,201 @@
+package cmsstore
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+)
+
+// Menu Source Types
+const (
+	// MENU_SOURCE_TYPE_PAGE_CHILDREN generates the child pages of a page
+	MENU_SOURCE_TYPE_PAGE_CHILDREN = "page_children"
+
+	// MENU_SOURCE_TYPE_PAGE_QUERY generates the pages matching a query
+	MENU_SOURCE_TYPE_PAGE_QUERY = "page_query"
+
+	// MENU_SOURCE_TYPE_ENTITIES generates the custom entities of a type
+	MENU_SOURCE_TYPE_ENTITIES = "entities"
+
+	// MENU_SOURCE_TYPE_TAXONOMY generates the terms of a taxonomy
+	MENU_SOURCE_TYPE_TAXONOMY = "taxonomy"
+)
+
+// Menu Meta Keys
+const (
+	// MENU_META_SOURCES are the sources of the menu (JSON), see MenuSource
+	MENU_META_SOURCES = "sources"
+)
+
+// Menu Item Meta Keys
+const (
+	// MENU_ITEM_META_SOURCE is the index of the source, which generated
+	// the menu item, see MenuItemIsGenerated
+	MENU_ITEM_META_SOURCE = "source"
+)
+
+// MenuSource populates a menu dynamically, the generated menu items are
+// merged with the manual menu items when the menu is rendered (see
+// StoreInterface.MenuItemsResolve), and are not stored.
+//
+// Business Logic:
+//   - the generated items are added after the manual items of the same
+//     parent, in the order of the sources
+//   - only active pages are generated
+//   - the URL pattern of the entities and taxonomy terms supports the
+//     placeholders [[id]], [[handle]] (entities), [[slug]] (terms)
+//     and [[<attribute>]] (entities), the values are URL escaped
+type MenuSource struct {
+	// Type is one of the MENU_SOURCE_TYPE_* constants
+	Type string `json:"type"`
+
+	// ParentItemID is the manual menu item the generated items are
+	// nested under, the top level if empty
+	ParentItemID string `json:"parent_item_id,omitempty"`
+
+	// PageID is the parent page of MENU_SOURCE_TYPE_PAGE_CHILDREN
+	PageID string `json:"page_id,omitempty"`
+
+	// AliasLike filters the pages of MENU_SOURCE_TYPE_PAGE_QUERY,
+	// i.e. "/blog/%"
+	AliasLike string `json:"alias_like,omitempty"`
+
+	// TemplateID filters the pages of MENU_SOURCE_TYPE_PAGE_QUERY
+	TemplateID string `json:"template_id,omitempty"`
+
+	// EntityType is the custom entity type of MENU_SOURCE_TYPE_ENTITIES
+	EntityType string `json:"entity_type,omitempty"`
+
+	// LabelAttribute is the attribute of the entities used as
+	// the menu item name, the handle if empty
+	LabelAttribute string `json:"label_attribute,omitempty"`
+
+	// TaxonomyID is the taxonomy of MENU_SOURCE_TYPE_TAXONOMY
+	TaxonomyID string `json:"taxonomy_id,omitempty"`
+
+	// URLPattern is the URL of the generated entities and taxonomy
+	// terms, i.e. "/products/[[handle]]"
+	URLPattern string `json:"url_pattern,omitempty"`
+
+	// Depth limits the levels of the generated child pages and
+	// taxonomy terms, 0 for unlimited
+	Depth int `json:"depth,omitempty"`
+
+	// Limit limits the number of the generated items per level,
+	// 0 for unlimited
+	Limit int `json:"limit,omitempty"`
+}
+
+// MenuSourceTypes returns the supported source types
+func MenuSourceTypes() []string {
+	return []string{
+		MENU_SOURCE_TYPE_PAGE_CHILDREN,
+		MENU_SOURCE_TYPE_PAGE_QUERY,
+		MENU_SOURCE_TYPE_ENTITIES,
+		MENU_SOURCE_TYPE_TAXONOMY,
+	}
+}
+
+// Validate checks the source has the settings required by its type
+func (source MenuSource) Validate() error {
+	if !slices.Contains(MenuSourceTypes(), source.Type) {
+		return errors.New("menu source type is not supported: " + source.Type)
+	}
+
+	if source.Depth < 0 {
+		return errors.New("menu source depth cannot be negative")
+	}
+
+	if source.Limit < 0 {
+		return errors.New("menu source limit cannot be negative")
+	}
+
+	switch source.Type {
+	case MENU_SOURCE_TYPE_PAGE_CHILDREN:
+		if source.PageID == "" {
+			return errors.New("menu source page is required")
+		}
+	case MENU_SOURCE_TYPE_ENTITIES:
+		if source.EntityType == "" {
+			return errors.New("menu source entity type is required")
+		}
+		if source.URLPattern == "" {
+			return errors.New("menu source URL pattern is required")
+		}
+	case MENU_SOURCE_TYPE_TAXONOMY:
+		if source.TaxonomyID == "" {
+			return errors.New("menu source taxonomy is required")
+		}
+		if source.URLPattern == "" {
+			return errors.New("menu source URL pattern is required")
+		}
+	}
+
+	return nil
+}
+
+// MenuSources returns the sources of the menu, empty if none
+func MenuSources(menu MenuInterface) ([]MenuSource, error) {
+	if menu == nil {
+		return []MenuSource{}, nil
+	}
+
+	value := menu.Meta(MENU_META_SOURCES)
+	if value == "" {
+		return []MenuSource{}, nil
+	}
+
+	sources := []MenuSource{}
+	if err := json.Unmarshal([]byte(value), &sources); err != nil {
+		return []MenuSource{}, err
+	}
+
+	return sources, nil
+}
+
+// SetMenuSources validates and stores the sources of the menu,
+// empty sources remove them
+func SetMenuSources(menu MenuInterface, sources []MenuSource) error {
+	if menu == nil {
+		return errors.New("menu is nil")
+	}
+
+	metas, err := menu.Metas()
+	if err != nil {
+		return err
+	}
+
+	if metas == nil {
+		metas = map[string]string{}
+	}
+
+	if len(sources) == 0 {
+		delete(metas, MENU_META_SOURCES)
+		return menu.SetMetas(metas)
+	}
+
+	for _, source := range sources {
+		if err := source.Validate(); err != nil {
+			return err
+		}
+	}
+
+	value, err := json.Marshal(sources)
+	if err != nil {
+		return err
+	}
+
+	metas[MENU_META_SOURCES] = string(value)
+
+	return menu.SetMetas(metas)
+}
+
+// MenuItemIsGenerated returns whether the menu item was generated by
+// a menu source, rather than stored
+func MenuItemIsGenerated(menuItem MenuItemInterface) bool {
+	if menuItem == nil {
+		return false
+	}
+
+	return menuItem.Meta(MENU_ITEM_META_SOURCE) != ""
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/dracory/dataobject"
 	"github.com/dromara/carbon/v2"
+	"github.com/spf13/cast"
 )
 
 // == TYPE ===================================================================
@@ -39,6 +40,8 @@ func NewPage() PageInterface {
 	o.SetMiddlewaresAfter([]string{})
 	o.SetMiddlewaresBefore([]string{})
 	o.SetName("")
+	o.SetParentID("")
+	o.SetSequenceInt(0)
 	o.SetStatus(PAGE_STATUS_DRAFT)
 	o.SetTemplateID("")
 	o.SetTitle("")
@@ -289,6 +292,12 @@ func (o *pageImplementation) UpsertMetas(metas map[string]string) error {
 	return o.SetMetas(currentMetas)
 }
 
+// setMetaValue sets the meta backing a field of the page, the metas
+// are left unchanged if they cannot be decoded.
+func (o *pageImplementation) setMetaValue(name string, value string) {
+	_ = o.UpsertMetas(map[string]string{name: value})
+}
+
 // MiddlewaresBefore returns the middlewares that run before the page.
 func (o *pageImplementation) MiddlewaresBefore() []string {
 	s := o.Get(COLUMN_MIDDLEWARES_BEFORE)
@@ -334,6 +343,88 @@ func (o *pageImplementation) SetName(name string) PageInterface {
 	return o
 }
 
+// OpenGraphDescription returns the Open Graph description of the page,
+// see PageOpenGraph for the fallback.
+func (o *pageImplementation) OpenGraphDescription() string {
+	return o.Meta(PAGE_META_OG_DESCRIPTION)
+}
+
+// SetOpenGraphDescription sets the Open Graph description of the page.
+func (o *pageImplementation) SetOpenGraphDescription(description string) PageInterface {
+	o.setMetaValue(PAGE_META_OG_DESCRIPTION, description)
+	return o
+}
+
+// OpenGraphImageMediaID returns the ID of the media of the Open Graph
+// image of the page.
+func (o *pageImplementation) OpenGraphImageMediaID() string {
+	return o.Meta(PAGE_META_OG_IMAGE_MEDIA_ID)
+}
+
+// SetOpenGraphImageMediaID sets the ID of the media of the Open Graph
+// image of the page.
+func (o *pageImplementation) SetOpenGraphImageMediaID(mediaID string) PageInterface {
+	o.setMetaValue(PAGE_META_OG_IMAGE_MEDIA_ID, mediaID)
+	return o
+}
+
+// OpenGraphTitle returns the Open Graph title of the page,
+// see PageOpenGraph for the fallback.
+func (o *pageImplementation) OpenGraphTitle() string {
+	return o.Meta(PAGE_META_OG_TITLE)
+}
+
+// SetOpenGraphTitle sets the Open Graph title of the page.
+func (o *pageImplementation) SetOpenGraphTitle(title string) PageInterface {
+	o.setMetaValue(PAGE_META_OG_TITLE, title)
+	return o
+}
+
+// OpenGraphType returns the Open Graph type of the page, one of the
+// PAGE_OG_TYPE_* constants.
+func (o *pageImplementation) OpenGraphType() string {
+	return o.Meta(PAGE_META_OG_TYPE)
+}
+
+// SetOpenGraphType sets the Open Graph type of the page.
+func (o *pageImplementation) SetOpenGraphType(ogType string) PageInterface {
+	o.setMetaValue(PAGE_META_OG_TYPE, ogType)
+	return o
+}
+
+// ParentID returns the ID of the parent page, empty for a top level page.
+func (o *pageImplementation) ParentID() string {
+	return o.Get(COLUMN_PARENT_ID)
+}
+
+// SetParentID sets the ID of the parent page.
+func (o *pageImplementation) SetParentID(parentID string) PageInterface {
+	o.Set(COLUMN_PARENT_ID, parentID)
+	return o
+}
+
+// Sequence returns the position of the page among its siblings.
+func (o *pageImplementation) Sequence() string {
+	return o.Get(COLUMN_SEQUENCE)
+}
+
+// SetSequence sets the position of the page among its siblings.
+func (o *pageImplementation) SetSequence(sequence string) PageInterface {
+	o.Set(COLUMN_SEQUENCE, sequence)
+	return o
+}
+
+// SequenceInt returns the position of the page among its siblings, as an int.
+func (o *pageImplementation) SequenceInt() int {
+	return cast.ToInt(o.Sequence())
+}
+
+// SetSequenceInt sets the position of the page among its siblings, as an int.
+func (o *pageImplementation) SetSequenceInt(sequence int) PageInterface {
+	o.Set(COLUMN_SEQUENCE, cast.ToString(sequence))
+	return o
+}
+
 // SiteID returns the site ID of the page.
 func (o *pageImplementation) SiteID() string {
 	return o.Get(COLUMN_SITE_ID)
@@ -394,6 +485,18 @@ func (o *pageImplementation) SetTemplateID(templateID string) PageInterface {
 	return o
 }
 
+// TwitterCard returns the Twitter card type of the page, one of the
+// PAGE_TWITTER_CARD_* constants.
+func (o *pageImplementation) TwitterCard() string {
+	return o.Meta(PAGE_META_TWITTER_CARD)
+}
+
+// SetTwitterCard sets the Twitter card type of the page.
+func (o *pageImplementation) SetTwitterCard(card string) PageInterface {
+	o.setMetaValue(PAGE_META_TWITTER_CARD, card)
+	return o
+}
+
 // UpdatedAt returns the update timestamp of the page.
 func (o *pageImplementation) UpdatedAt() string {
 	return o.Get(COLUMN_UPDATED_AT)
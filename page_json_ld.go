@@ -0,0 +1,409 @@
+package cmsstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Page Schema Types, the schema.org type of the page, see PageJsonLd
+const (
+	PAGE_SCHEMA_TYPE_ARTICLE  = "Article"
+	PAGE_SCHEMA_TYPE_FAQ_PAGE = "FAQPage"
+	PAGE_SCHEMA_TYPE_PRODUCT  = "Product"
+	PAGE_SCHEMA_TYPE_WEB_PAGE = "WebPage"
+)
+
+// Page Schema Meta Keys
+const (
+	// PAGE_META_SCHEMA_TYPE is the schema.org type of the page, one of
+	// the PAGE_SCHEMA_TYPE_* constants, PAGE_SCHEMA_TYPE_WEB_PAGE if empty
+	PAGE_META_SCHEMA_TYPE = "schema_type"
+
+	// PAGE_META_SCHEMA_IMAGE is the image URL of the article or product
+	PAGE_META_SCHEMA_IMAGE = "schema_image"
+
+	// PAGE_META_SCHEMA_AUTHOR is the author name of the article
+	PAGE_META_SCHEMA_AUTHOR = "schema_author"
+
+	// PAGE_META_SCHEMA_SKU is the SKU of the product
+	PAGE_META_SCHEMA_SKU = "schema_sku"
+
+	// PAGE_META_SCHEMA_BRAND is the brand name of the product
+	PAGE_META_SCHEMA_BRAND = "schema_brand"
+
+	// PAGE_META_SCHEMA_PRICE is the price of the product, i.e. "19.99"
+	PAGE_META_SCHEMA_PRICE = "schema_price"
+
+	// PAGE_META_SCHEMA_PRICE_CURRENCY is the ISO 4217 currency of the
+	// price of the product, i.e. "USD"
+	PAGE_META_SCHEMA_PRICE_CURRENCY = "schema_price_currency"
+
+	// PAGE_META_SCHEMA_FAQ are the questions of the FAQ page (JSON),
+	// see PageSchemaFAQ
+	PAGE_META_SCHEMA_FAQ = "schema_faq"
+)
+
+// Site Schema Meta Keys
+const (
+	// SITE_META_ORGANIZATION_NAME is the public name of the organization
+	// of the site, the site name if empty
+	SITE_META_ORGANIZATION_NAME = "organization_name"
+
+	// SITE_META_ORGANIZATION_LOGO is the logo URL of the organization
+	SITE_META_ORGANIZATION_LOGO = "organization_logo"
+
+	// SITE_META_ORGANIZATION_SAME_AS are the profile URLs of the
+	// organization (i.e. social networks), one per line
+	SITE_META_ORGANIZATION_SAME_AS = "organization_same_as"
+)
+
+// PageSchemaTypes returns the supported schema types of the pages
+func PageSchemaTypes() []string {
+	return []string{
+		PAGE_SCHEMA_TYPE_WEB_PAGE,
+		PAGE_SCHEMA_TYPE_ARTICLE,
+		PAGE_SCHEMA_TYPE_PRODUCT,
+		PAGE_SCHEMA_TYPE_FAQ_PAGE,
+	}
+}
+
+// PageSchemaQuestion is a question of a FAQ page, see PageSchemaFAQ
+type PageSchemaQuestion struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// PageSchemaFAQ returns the questions of the FAQ page, empty if none
+func PageSchemaFAQ(page PageInterface) ([]PageSchemaQuestion, error) {
+	if page == nil {
+		return []PageSchemaQuestion{}, nil
+	}
+
+	faqJSON := page.Meta(PAGE_META_SCHEMA_FAQ)
+	if faqJSON == "" {
+		return []PageSchemaQuestion{}, nil
+	}
+
+	questions := []PageSchemaQuestion{}
+	if err := json.Unmarshal([]byte(faqJSON), &questions); err != nil {
+		return []PageSchemaQuestion{}, err
+	}
+
+	return questions, nil
+}
+
+// SetPageSchemaFAQ stores the questions of the FAQ page, the questions
+// without a question or an answer are skipped
+func SetPageSchemaFAQ(page PageInterface, questions []PageSchemaQuestion) error {
+	if page == nil {
+		return errors.New("page is nil")
+	}
+
+	questions = slices.DeleteFunc(slices.Clone(questions), func(question PageSchemaQuestion) bool {
+		return strings.TrimSpace(question.Question) == "" || strings.TrimSpace(question.Answer) == ""
+	})
+
+	if len(questions) == 0 {
+		return page.SetMeta(PAGE_META_SCHEMA_FAQ, "")
+	}
+
+	faqJSON, err := json.Marshal(questions)
+	if err != nil {
+		return err
+	}
+
+	return page.SetMeta(PAGE_META_SCHEMA_FAQ, string(faqJSON))
+}
+
+// JsonLdBreadcrumb is an item of a BreadcrumbList, see JsonLdBreadcrumbList
+type JsonLdBreadcrumb struct {
+	Name string
+	URL  string
+}
+
+// JsonLdBreadcrumbList returns the schema.org BreadcrumbList of the
+// breadcrumbs, the relative URLs are made absolute with the request
+// of the context. The breadcrumbs without URL (i.e. the current page)
+// link to the requested URL.
+func JsonLdBreadcrumbList(ctx context.Context, breadcrumbs []JsonLdBreadcrumb) map[string]any {
+	items := []map[string]any{}
+
+	for i, breadcrumb := range breadcrumbs {
+		itemURL := breadcrumb.URL
+		if itemURL == "" {
+			if req := RequestFromContext(ctx); req != nil && req.URL != nil {
+				itemURL = req.URL.Path
+			}
+		}
+
+		item := map[string]any{
+			"@type":    "ListItem",
+			"position": i + 1,
+			"name":     breadcrumb.Name,
+		}
+
+		if itemURL != "" {
+			item["item"] = jsonLdAbsoluteURL(ctx, itemURL)
+		}
+
+		items = append(items, item)
+	}
+
+	return map[string]any{
+		"@context":        "https://schema.org",
+		"@type":           "BreadcrumbList",
+		"itemListElement": items,
+	}
+}
+
+// JsonLdScript returns the data as a JSON-LD script tag. The HTML
+// characters are escaped, so the data cannot close the script.
+func JsonLdScript(data any) (string, error) {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	return `<script type="application/ld+json">` + string(dataJSON) + `</script>`, nil
+}
+
+// PageJsonLd returns the JSON-LD script of the page, as inserted by
+// the [[PageJsonLd]] placeholder. It holds:
+//   - the Organization and the WebSite of the site, if not nil
+//   - the BreadcrumbList of the page ancestors, if any
+//   - the page, with its schema type (see PAGE_META_SCHEMA_TYPE),
+//     filled from the page fields and the PAGE_META_SCHEMA_* metas
+//
+// The relative URLs are made absolute with the request of the context.
+func PageJsonLd(ctx context.Context, site SiteInterface, page PageInterface, ancestors []PageInterface) (string, error) {
+	if page == nil {
+		return "", errors.New("page is nil")
+	}
+
+	graph := []map[string]any{}
+
+	if site != nil {
+		graph = append(graph, jsonLdOrganization(ctx, site), jsonLdWebSite(ctx, site))
+	}
+
+	if len(ancestors) > 0 {
+		breadcrumbs := []JsonLdBreadcrumb{}
+		for _, ancestor := range ancestors {
+			breadcrumbs = append(breadcrumbs, JsonLdBreadcrumb{Name: ancestor.Name(), URL: jsonLdPageURL(ancestor)})
+		}
+		breadcrumbs = append(breadcrumbs, JsonLdBreadcrumb{Name: page.Name(), URL: jsonLdPageURL(page)})
+
+		breadcrumbList := JsonLdBreadcrumbList(ctx, breadcrumbs)
+		delete(breadcrumbList, "@context")
+		graph = append(graph, breadcrumbList)
+	}
+
+	pageData, err := jsonLdPage(ctx, page)
+	if err != nil {
+		return "", err
+	}
+
+	graph = append(graph, pageData)
+
+	return JsonLdScript(map[string]any{
+		"@context": "https://schema.org",
+		"@graph":   graph,
+	})
+}
+
+// jsonLdOrganization returns the schema.org Organization of the site
+func jsonLdOrganization(ctx context.Context, site SiteInterface) map[string]any {
+	organization := map[string]any{
+		"@type": "Organization",
+		"name":  jsonLdSiteName(site),
+	}
+
+	if siteURL := jsonLdSiteURL(ctx, site); siteURL != "" {
+		organization["url"] = siteURL
+	}
+
+	if logo := site.Meta(SITE_META_ORGANIZATION_LOGO); logo != "" {
+		organization["logo"] = jsonLdAbsoluteURL(ctx, logo)
+	}
+
+	sameAs := []string{}
+	for _, line := range strings.Split(site.Meta(SITE_META_ORGANIZATION_SAME_AS), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			sameAs = append(sameAs, line)
+		}
+	}
+
+	if len(sameAs) > 0 {
+		organization["sameAs"] = sameAs
+	}
+
+	return organization
+}
+
+// jsonLdWebSite returns the schema.org WebSite of the site
+func jsonLdWebSite(ctx context.Context, site SiteInterface) map[string]any {
+	webSite := map[string]any{
+		"@type": "WebSite",
+		"name":  jsonLdSiteName(site),
+	}
+
+	if siteURL := jsonLdSiteURL(ctx, site); siteURL != "" {
+		webSite["url"] = siteURL
+	}
+
+	return webSite
+}
+
+// jsonLdPage returns the page with its schema type
+func jsonLdPage(ctx context.Context, page PageInterface) (map[string]any, error) {
+	schemaType := page.Meta(PAGE_META_SCHEMA_TYPE)
+	if !slices.Contains(PageSchemaTypes(), schemaType) {
+		schemaType = PAGE_SCHEMA_TYPE_WEB_PAGE
+	}
+
+	title := page.Title()
+	if title == "" {
+		title = page.Name()
+	}
+
+	pageURL := page.CanonicalUrl()
+	if pageURL == "" {
+		pageURL = jsonLdPageURL(page)
+	}
+
+	data := map[string]any{
+		"@type": schemaType,
+		"url":   jsonLdAbsoluteURL(ctx, pageURL),
+	}
+
+	jsonLdSet(data, "description", page.MetaDescription())
+
+	switch schemaType {
+	case PAGE_SCHEMA_TYPE_ARTICLE:
+		data["headline"] = title
+		jsonLdSetImage(ctx, data, page)
+
+		if author := page.Meta(PAGE_META_SCHEMA_AUTHOR); author != "" {
+			data["author"] = map[string]any{"@type": "Person", "name": author}
+		}
+
+		if createdAt := page.CreatedAtCarbon(); createdAt.IsValid() {
+			data["datePublished"] = createdAt.ToIso8601String()
+		}
+
+		if updatedAt := page.UpdatedAtCarbon(); updatedAt.IsValid() {
+			data["dateModified"] = updatedAt.ToIso8601String()
+		}
+
+	case PAGE_SCHEMA_TYPE_PRODUCT:
+		data["name"] = title
+		jsonLdSetImage(ctx, data, page)
+		jsonLdSet(data, "sku", page.Meta(PAGE_META_SCHEMA_SKU))
+
+		if brand := page.Meta(PAGE_META_SCHEMA_BRAND); brand != "" {
+			data["brand"] = map[string]any{"@type": "Brand", "name": brand}
+		}
+
+		if price := page.Meta(PAGE_META_SCHEMA_PRICE); price != "" {
+			offer := map[string]any{"@type": "Offer", "price": price, "url": data["url"]}
+			jsonLdSet(offer, "priceCurrency", page.Meta(PAGE_META_SCHEMA_PRICE_CURRENCY))
+			data["offers"] = offer
+		}
+
+	case PAGE_SCHEMA_TYPE_FAQ_PAGE:
+		data["name"] = title
+
+		questions, err := PageSchemaFAQ(page)
+		if err != nil {
+			return nil, err
+		}
+
+		mainEntity := []map[string]any{}
+		for _, question := range questions {
+			mainEntity = append(mainEntity, map[string]any{
+				"@type": "Question",
+				"name":  question.Question,
+				"acceptedAnswer": map[string]any{
+					"@type": "Answer",
+					"text":  question.Answer,
+				},
+			})
+		}
+
+		data["mainEntity"] = mainEntity
+
+	default:
+		data["name"] = title
+	}
+
+	return data, nil
+}
+
+// jsonLdSet sets the value, if not empty
+func jsonLdSet(data map[string]any, key string, value string) {
+	if value != "" {
+		data[key] = value
+	}
+}
+
+// jsonLdSetImage sets the image of the page, if any
+func jsonLdSetImage(ctx context.Context, data map[string]any, page PageInterface) {
+	if image := page.Meta(PAGE_META_SCHEMA_IMAGE); image != "" {
+		data["image"] = jsonLdAbsoluteURL(ctx, image)
+	}
+}
+
+// jsonLdSiteName returns the public name of the site
+func jsonLdSiteName(site SiteInterface) string {
+	if name := site.Meta(SITE_META_ORGANIZATION_NAME); name != "" {
+		return name
+	}
+
+	return site.Name()
+}
+
+// jsonLdSiteURL returns the URL of the site, the root of the request,
+// or of the first domain name of the site without a request
+func jsonLdSiteURL(ctx context.Context, site SiteInterface) string {
+	if req := RequestFromContext(ctx); req != nil && req.Host != "" {
+		return jsonLdAbsoluteURL(ctx, "/")
+	}
+
+	domainNames, err := site.DomainNames()
+	if err != nil || len(domainNames) == 0 {
+		return ""
+	}
+
+	return "https://" + strings.TrimSuffix(domainNames[0], "/") + "/"
+}
+
+// jsonLdPageURL returns the URL path of the page
+func jsonLdPageURL(page PageInterface) string {
+	return "/" + strings.TrimPrefix(page.Alias(), "/")
+}
+
+// jsonLdAbsoluteURL makes the relative URL absolute, with the scheme and
+// the host of the request of the context. The URL is returned as is if
+// absolute, invalid or without a request.
+func jsonLdAbsoluteURL(ctx context.Context, rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.IsAbs() {
+		return rawURL
+	}
+
+	req := RequestFromContext(ctx)
+	if req == nil || req.Host == "" {
+		return rawURL
+	}
+
+	scheme := "http"
+	if req.TLS != nil || req.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return (&url.URL{Scheme: scheme, Host: req.Host, Path: "/"}).ResolveReference(parsed).String()
+}
@@ -0,0 +1,232 @@
+package cmsstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// jsonLdTestGraph decodes the @graph of the JSON-LD script
+func jsonLdTestGraph(t *testing.T, script string) []map[string]any {
+	t.Helper()
+
+	if !strings.HasPrefix(script, `<script type="application/ld+json">`) || !strings.HasSuffix(script, `</script>`) {
+		t.Fatalf("expected a JSON-LD script, got %s", script)
+	}
+
+	dataJSON := strings.TrimSuffix(strings.TrimPrefix(script, `<script type="application/ld+json">`), `</script>`)
+
+	data := map[string]any{}
+	if err := json.Unmarshal([]byte(dataJSON), &data); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+
+	graph := []map[string]any{}
+	for _, node := range data["@graph"].([]any) {
+		graph = append(graph, node.(map[string]any))
+	}
+
+	return graph
+}
+
+func TestPageSchemaFAQ(t *testing.T) {
+	page := NewPage()
+
+	err := SetPageSchemaFAQ(page, []PageSchemaQuestion{
+		{Question: "What is it?", Answer: "A CMS"},
+		{Question: "Incomplete?", Answer: " "},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	questions, err := PageSchemaFAQ(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(questions) != 1 || questions[0].Question != "What is it?" || questions[0].Answer != "A CMS" {
+		t.Fatalf("expected the complete question only, got %v", questions)
+	}
+
+	if err := SetPageSchemaFAQ(page, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if page.Meta(PAGE_META_SCHEMA_FAQ) != "" {
+		t.Fatalf("expected the FAQ meta to be cleared, got %q", page.Meta(PAGE_META_SCHEMA_FAQ))
+	}
+}
+
+func TestJsonLdBreadcrumbList(t *testing.T) {
+	req := httptest.NewRequest("GET", "https://example.com/about/team", nil)
+	ctx := RequestToContext(context.Background(), req)
+
+	list := JsonLdBreadcrumbList(ctx, []JsonLdBreadcrumb{
+		{Name: "Home", URL: "/"},
+		{Name: "Team <b>", URL: ""},
+	})
+
+	items := list["itemListElement"].([]map[string]any)
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	if items[0]["item"] != "https://example.com/" || items[0]["position"] != 1 {
+		t.Errorf("expected the absolute home URL, got %v", items[0])
+	}
+
+	if items[1]["item"] != "https://example.com/about/team" {
+		t.Errorf("expected the requested URL for the current page, got %v", items[1])
+	}
+
+	script, err := JsonLdScript(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(script, "<b>") {
+		t.Errorf("expected the HTML characters to be escaped, got %s", script)
+	}
+}
+
+func TestPageJsonLd(t *testing.T) {
+	req := httptest.NewRequest("GET", "http://example.com/products/widget", nil)
+	ctx := RequestToContext(context.Background(), req)
+
+	site := NewSite().SetName("Example")
+	if err := site.SetMeta(SITE_META_ORGANIZATION_NAME, "Example Inc."); err != nil {
+		t.Fatal(err)
+	}
+	if err := site.SetMeta(SITE_META_ORGANIZATION_LOGO, "/media/logo.png"); err != nil {
+		t.Fatal(err)
+	}
+	if err := site.SetMeta(SITE_META_ORGANIZATION_SAME_AS, "https://x.com/example\n\nhttps://github.com/example"); err != nil {
+		t.Fatal(err)
+	}
+
+	products := NewPage().SetName("Products").SetAlias("/products")
+	page := NewPage().SetName("Widget").SetTitle("The Widget").SetAlias("/products/widget").SetMetaDescription("A widget")
+
+	if err := page.SetMeta(PAGE_META_SCHEMA_TYPE, PAGE_SCHEMA_TYPE_PRODUCT); err != nil {
+		t.Fatal(err)
+	}
+	if err := page.SetMeta(PAGE_META_SCHEMA_SKU, "W-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := page.SetMeta(PAGE_META_SCHEMA_PRICE, "9.99"); err != nil {
+		t.Fatal(err)
+	}
+	if err := page.SetMeta(PAGE_META_SCHEMA_PRICE_CURRENCY, "EUR"); err != nil {
+		t.Fatal(err)
+	}
+
+	script, err := PageJsonLd(ctx, site, page, []PageInterface{products})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	graph := jsonLdTestGraph(t, script)
+	if len(graph) != 4 {
+		t.Fatalf("expected Organization, WebSite, BreadcrumbList and Product, got %v", graph)
+	}
+
+	organization := graph[0]
+	if organization["@type"] != "Organization" || organization["name"] != "Example Inc." || organization["logo"] != "http://example.com/media/logo.png" {
+		t.Errorf("unexpected organization %v", organization)
+	}
+	if sameAs := organization["sameAs"].([]any); len(sameAs) != 2 {
+		t.Errorf("expected 2 profiles, got %v", sameAs)
+	}
+
+	if graph[1]["@type"] != "WebSite" || graph[1]["url"] != "http://example.com/" {
+		t.Errorf("unexpected website %v", graph[1])
+	}
+
+	breadcrumbs := graph[2]["itemListElement"].([]any)
+	if graph[2]["@type"] != "BreadcrumbList" || len(breadcrumbs) != 2 {
+		t.Errorf("unexpected breadcrumbs %v", graph[2])
+	}
+
+	product := graph[3]
+	if product["@type"] != "Product" || product["name"] != "The Widget" || product["sku"] != "W-1" || product["description"] != "A widget" {
+		t.Errorf("unexpected product %v", product)
+	}
+
+	offer := product["offers"].(map[string]any)
+	if offer["price"] != "9.99" || offer["priceCurrency"] != "EUR" || offer["url"] != "http://example.com/products/widget" {
+		t.Errorf("unexpected offer %v", offer)
+	}
+}
+
+func TestPageJsonLdArticleAndFAQ(t *testing.T) {
+	ctx := context.Background()
+
+	article := NewPage().SetName("News").SetCanonicalUrl("https://example.com/news")
+	if err := article.SetMeta(PAGE_META_SCHEMA_TYPE, PAGE_SCHEMA_TYPE_ARTICLE); err != nil {
+		t.Fatal(err)
+	}
+	if err := article.SetMeta(PAGE_META_SCHEMA_AUTHOR, "Jane Doe"); err != nil {
+		t.Fatal(err)
+	}
+
+	script, err := PageJsonLd(ctx, nil, article, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	graph := jsonLdTestGraph(t, script)
+	if len(graph) != 1 {
+		t.Fatalf("expected the article only, got %v", graph)
+	}
+
+	if graph[0]["@type"] != "Article" || graph[0]["headline"] != "News" || graph[0]["url"] != "https://example.com/news" {
+		t.Errorf("unexpected article %v", graph[0])
+	}
+	if author := graph[0]["author"].(map[string]any); author["name"] != "Jane Doe" {
+		t.Errorf("unexpected author %v", author)
+	}
+	if graph[0]["datePublished"] == nil {
+		t.Errorf("expected the publication date, got %v", graph[0])
+	}
+
+	faq := NewPage().SetName("FAQ").SetAlias("faq")
+	if err := faq.SetMeta(PAGE_META_SCHEMA_TYPE, PAGE_SCHEMA_TYPE_FAQ_PAGE); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetPageSchemaFAQ(faq, []PageSchemaQuestion{{Question: "Why?", Answer: "Because"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	script, err = PageJsonLd(ctx, nil, faq, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	graph = jsonLdTestGraph(t, script)
+	mainEntity := graph[0]["mainEntity"].([]any)
+	if graph[0]["@type"] != "FAQPage" || graph[0]["url"] != "/faq" || len(mainEntity) != 1 {
+		t.Fatalf("unexpected FAQ page %v", graph[0])
+	}
+
+	answer := mainEntity[0].(map[string]any)["acceptedAnswer"].(map[string]any)
+	if answer["text"] != "Because" {
+		t.Errorf("unexpected answer %v", answer)
+	}
+
+	unknown := NewPage().SetName("Unknown")
+	if err := unknown.SetMeta(PAGE_META_SCHEMA_TYPE, "Movie"); err != nil {
+		t.Fatal(err)
+	}
+
+	script, err = PageJsonLd(ctx, nil, unknown, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if graph = jsonLdTestGraph(t, script); graph[0]["@type"] != "WebPage" {
+		t.Errorf("expected the WebPage fallback, got %v", graph[0])
+	}
+}
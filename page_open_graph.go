@@ -0,0 +1,170 @@
+package cmsstore
+
+import (
+	"context"
+	"errors"
+	"html"
+	"slices"
+	"strings"
+)
+
+// Page Open Graph Types, the og:type of the page, see PageOpenGraph
+const (
+	PAGE_OG_TYPE_ARTICLE = "article"
+	PAGE_OG_TYPE_PRODUCT = "product"
+	PAGE_OG_TYPE_WEBSITE = "website"
+)
+
+// Page Twitter Cards, the twitter:card of the page, see PageOpenGraph
+const (
+	PAGE_TWITTER_CARD_SUMMARY             = "summary"
+	PAGE_TWITTER_CARD_SUMMARY_LARGE_IMAGE = "summary_large_image"
+)
+
+// Page Open Graph Meta Keys, see PageInterface.OpenGraphTitle, etc.
+const (
+	// PAGE_META_OG_TITLE is the og:title of the page, the page title if empty
+	PAGE_META_OG_TITLE = "og_title"
+
+	// PAGE_META_OG_DESCRIPTION is the og:description of the page, the
+	// meta description if empty
+	PAGE_META_OG_DESCRIPTION = "og_description"
+
+	// PAGE_META_OG_IMAGE_MEDIA_ID is the ID of the media of the og:image
+	PAGE_META_OG_IMAGE_MEDIA_ID = "og_image_media_id"
+
+	// PAGE_META_OG_TYPE is the og:type of the page, one of the
+	// PAGE_OG_TYPE_* constants, derived from the schema type if empty
+	PAGE_META_OG_TYPE = "og_type"
+
+	// PAGE_META_TWITTER_CARD is the twitter:card of the page, one of the
+	// PAGE_TWITTER_CARD_* constants, derived from the image if empty
+	PAGE_META_TWITTER_CARD = "twitter_card"
+)
+
+// PageOpenGraphTypes returns the supported Open Graph types of the pages
+func PageOpenGraphTypes() []string {
+	return []string{
+		PAGE_OG_TYPE_ARTICLE,
+		PAGE_OG_TYPE_PRODUCT,
+		PAGE_OG_TYPE_WEBSITE,
+	}
+}
+
+// PageTwitterCards returns the supported Twitter cards of the pages
+func PageTwitterCards() []string {
+	return []string{
+		PAGE_TWITTER_CARD_SUMMARY,
+		PAGE_TWITTER_CARD_SUMMARY_LARGE_IMAGE,
+	}
+}
+
+// PageOpenGraph returns the Open Graph and Twitter card meta tags of the
+// page, as inserted by the [[PageOpenGraph]] placeholder. The fields
+// fall back to:
+//   - og:title to the page title, then the page name
+//   - og:description to the meta description
+//   - og:image to the schema image (see PAGE_META_SCHEMA_IMAGE), if the
+//     image media is nil, inactive or not an image
+//   - og:type to the schema type (article, product), then website
+//   - twitter:card to a large image card, if there is an image
+//
+// The site, if not nil, sets og:site_name. The relative URLs are made
+// absolute with the request of the context.
+func PageOpenGraph(ctx context.Context, site SiteInterface, page PageInterface, image MediaInterface) (string, error) {
+	if page == nil {
+		return "", errors.New("page is nil")
+	}
+
+	title := openGraphFirst(page.OpenGraphTitle(), page.Title(), page.Name())
+	description := openGraphFirst(page.OpenGraphDescription(), page.MetaDescription())
+
+	pageURL := page.CanonicalUrl()
+	if pageURL == "" {
+		pageURL = jsonLdPageURL(page)
+	}
+
+	imageURL := ""
+	imageAlt := ""
+	if image != nil && image.IsActive() && image.IsImage() {
+		imageURL = jsonLdAbsoluteURL(ctx, image.ServeURL())
+		imageAlt = openGraphFirst(image.Description(), image.Title())
+	} else if schemaImage := page.Meta(PAGE_META_SCHEMA_IMAGE); schemaImage != "" {
+		imageURL = jsonLdAbsoluteURL(ctx, schemaImage)
+	}
+
+	card := page.TwitterCard()
+	if !slices.Contains(PageTwitterCards(), card) {
+		card = PAGE_TWITTER_CARD_SUMMARY
+		if imageURL != "" {
+			card = PAGE_TWITTER_CARD_SUMMARY_LARGE_IMAGE
+		}
+	}
+
+	tags := [][2]string{
+		{"og:title", title},
+		{"og:description", description},
+		{"og:type", openGraphType(page)},
+		{"og:url", jsonLdAbsoluteURL(ctx, pageURL)},
+		{"og:image", imageURL},
+		{"og:image:alt", imageAlt},
+	}
+
+	if site != nil {
+		tags = append(tags, [2]string{"og:site_name", jsonLdSiteName(site)})
+	}
+
+	tags = append(tags,
+		[2]string{"twitter:card", card},
+		[2]string{"twitter:title", title},
+		[2]string{"twitter:description", description},
+		[2]string{"twitter:image", imageURL},
+		[2]string{"twitter:image:alt", imageAlt},
+	)
+
+	var sb strings.Builder
+
+	for _, tag := range tags {
+		if tag[1] == "" {
+			continue
+		}
+
+		// Open Graph uses the property attribute, Twitter cards the name
+		attribute := "property"
+		if strings.HasPrefix(tag[0], "twitter:") {
+			attribute = "name"
+		}
+
+		sb.WriteString(`<meta ` + attribute + `="` + tag[0] + `" content="` + html.EscapeString(tag[1]) + `">` + "\n")
+	}
+
+	return sb.String(), nil
+}
+
+// openGraphType returns the Open Graph type of the page, derived from the
+// schema type of the page if not set
+func openGraphType(page PageInterface) string {
+	if ogType := page.OpenGraphType(); slices.Contains(PageOpenGraphTypes(), ogType) {
+		return ogType
+	}
+
+	switch page.Meta(PAGE_META_SCHEMA_TYPE) {
+	case PAGE_SCHEMA_TYPE_ARTICLE:
+		return PAGE_OG_TYPE_ARTICLE
+	case PAGE_SCHEMA_TYPE_PRODUCT:
+		return PAGE_OG_TYPE_PRODUCT
+	}
+
+	return PAGE_OG_TYPE_WEBSITE
+}
+
+// openGraphFirst returns the first non-empty value, trimmed
+func openGraphFirst(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
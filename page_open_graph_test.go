@@ -0,0 +1,113 @@
+package cmsstore
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPageOpenGraphFields(t *testing.T) {
+	page := NewPage().
+		SetOpenGraphTitle("Title").
+		SetOpenGraphDescription("Description").
+		SetOpenGraphImageMediaID("MEDIA").
+		SetOpenGraphType(PAGE_OG_TYPE_ARTICLE).
+		SetTwitterCard(PAGE_TWITTER_CARD_SUMMARY)
+
+	if page.OpenGraphTitle() != "Title" || page.Meta(PAGE_META_OG_TITLE) != "Title" {
+		t.Errorf("expected the title in the metas, got %q", page.OpenGraphTitle())
+	}
+	if page.OpenGraphDescription() != "Description" || page.OpenGraphImageMediaID() != "MEDIA" {
+		t.Errorf("unexpected description or image, got %q %q", page.OpenGraphDescription(), page.OpenGraphImageMediaID())
+	}
+	if page.OpenGraphType() != PAGE_OG_TYPE_ARTICLE || page.TwitterCard() != PAGE_TWITTER_CARD_SUMMARY {
+		t.Errorf("unexpected type or card, got %q %q", page.OpenGraphType(), page.TwitterCard())
+	}
+}
+
+func TestPageOpenGraph(t *testing.T) {
+	req := httptest.NewRequest("GET", "https://example.com/widget", nil)
+	ctx := RequestToContext(context.Background(), req)
+
+	site := NewSite().SetName("Example")
+
+	image := NewMedia().
+		SetTitle(`Widget "photo"`).
+		SetType("image/png").
+		SetExtension("png").
+		SetStatus(MEDIA_STATUS_ACTIVE)
+
+	page := NewPage().
+		SetName("Widget").
+		SetTitle("The Widget").
+		SetAlias("/widget").
+		SetMetaDescription("A <great> widget").
+		SetOpenGraphTitle("Share the Widget")
+
+	if err := page.SetMeta(PAGE_META_SCHEMA_TYPE, PAGE_SCHEMA_TYPE_PRODUCT); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := PageOpenGraph(ctx, site, page, image)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`<meta property="og:title" content="Share the Widget">`,
+		`<meta property="og:description" content="A &lt;great&gt; widget">`,
+		`<meta property="og:type" content="product">`,
+		`<meta property="og:url" content="https://example.com/widget">`,
+		`<meta property="og:image" content="https://example.com/cms/media/` + image.ID() + `.png">`,
+		`<meta property="og:image:alt" content="Widget &#34;photo&#34;">`,
+		`<meta property="og:site_name" content="Example">`,
+		`<meta name="twitter:card" content="summary_large_image">`,
+		`<meta name="twitter:title" content="Share the Widget">`,
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(tags, e) {
+			t.Errorf("expected %s, got %s", e, tags)
+		}
+	}
+}
+
+func TestPageOpenGraphFallbacks(t *testing.T) {
+	page := NewPage().SetName("About")
+
+	tags, err := PageOpenGraph(context.Background(), nil, page, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<meta property="og:title" content="About">` + "\n" +
+		`<meta property="og:type" content="website">` + "\n" +
+		`<meta property="og:url" content="/">` + "\n" +
+		`<meta name="twitter:card" content="summary">` + "\n" +
+		`<meta name="twitter:title" content="About">` + "\n"
+
+	if tags != expected {
+		t.Errorf("expected %q, got %q", expected, tags)
+	}
+
+	// A media that is not an image is ignored, the schema image is used
+	video := NewMedia().SetType("video/mp4").SetStatus(MEDIA_STATUS_ACTIVE)
+	if err := page.SetMeta(PAGE_META_SCHEMA_IMAGE, "https://cdn.example.com/about.jpg"); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err = PageOpenGraph(context.Background(), nil, page.SetTwitterCard("invalid"), video)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(tags, `<meta property="og:image" content="https://cdn.example.com/about.jpg">`) ||
+		!strings.Contains(tags, `<meta name="twitter:card" content="summary_large_image">`) {
+		t.Errorf("expected the schema image with a large image card, got %s", tags)
+	}
+
+	if _, err := PageOpenGraph(context.Background(), nil, nil, nil); err == nil {
+		t.Error("expected an error for a nil page")
+	}
+}
@@ -302,6 +302,22 @@ func (p *pageQuery) SetOrderBy(orderBy string) PageQueryInterface {
 	return p
 }
 
+// HasParentID checks if the ParentID parameter is set.
+func (p *pageQuery) HasParentID() bool {
+	return p.hasParameter(propertyKeyParentID)
+}
+
+// ParentID returns the value of the ParentID parameter.
+func (p *pageQuery) ParentID() string {
+	return p.parameters[propertyKeyParentID].(string)
+}
+
+// SetParentID sets the value of the ParentID parameter.
+func (p *pageQuery) SetParentID(parentID string) PageQueryInterface {
+	p.parameters[propertyKeyParentID] = parentID
+	return p
+}
+
 // HasSiteID checks if the SiteID parameter is set.
 func (p *pageQuery) HasSiteID() bool {
 	return p.hasParameter(propertyKeySiteID)
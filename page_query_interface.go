@@ -96,6 +96,13 @@ type PageQueryInterface interface {
 	// SetOrderBy sets the order-by clause.
 	SetOrderBy(orderBy string) PageQueryInterface
 
+	// HasParentID checks if a parent ID is set.
+	HasParentID() bool
+	// ParentID returns the parent ID if set, empty for the top level pages.
+	ParentID() string
+	// SetParentID sets the parent ID.
+	SetParentID(parentID string) PageQueryInterface
+
 	// HasSiteID checks if a site ID is set.
 	HasSiteID() bool
 	// SiteID returns the site ID if set.
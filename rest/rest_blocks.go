@@ -2,6 +2,7 @@ package rest
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -110,6 +111,17 @@ func (api *RestAPI) handleBlockCreate(w http.ResponseWriter, r *http.Request) {
 		block.SetSequenceInt(0) // Default to 0 if not provided
 	}
 
+	// Set type - optional field
+	if blockType, ok := blockData["type"].(string); ok && blockType != "" {
+		block.SetType(blockType)
+	}
+
+	// Set settings - optional field, validated against the block type settings schema
+	if err := api.applyBlockSettings(block, blockData["settings"]); err != nil {
+		http.Error(w, fmt.Sprintf(`{"success":false,"error":"Invalid settings: %v"}`, err), http.StatusBadRequest)
+		return
+	}
+
 	// Save the block
 	if err := api.store.BlockCreate(r.Context(), block); err != nil {
 		http.Error(w, fmt.Sprintf(`{"success":false,"error":"Failed to save block: %v"}`, err), http.StatusInternalServerError)
@@ -253,6 +265,10 @@ func (api *RestAPI) handleBlockUpdate(w http.ResponseWriter, r *http.Request, bl
 	if content, ok := updates["content"].(string); ok {
 		block.SetContent(content)
 	}
+	if err := api.applyBlockSettings(block, updates["settings"]); err != nil {
+		http.Error(w, fmt.Sprintf(`{"success":false,"error":"Invalid settings: %v"}`, err), http.StatusBadRequest)
+		return
+	}
 
 	// Save the updated block
 	if err := api.store.BlockUpdate(r.Context(), block); err != nil {
@@ -279,6 +295,35 @@ func (api *RestAPI) handleBlockUpdate(w http.ResponseWriter, r *http.Request, bl
 	w.Write(jsonResponse)
 }
 
+// applyBlockSettings validates the settings from the request body against
+// the settings schema of the block type, and stores them in the block metas
+func (api *RestAPI) applyBlockSettings(block cmsstore.BlockInterface, raw interface{}) error {
+	if raw == nil {
+		return nil
+	}
+
+	settings, ok := raw.(map[string]interface{})
+	if !ok {
+		return errors.New("settings must be an object")
+	}
+
+	if len(settings) == 0 {
+		return nil
+	}
+
+	schema := cmsstore.BlockTypeSettingsSchema(api.store.BlockTypeRegistry().Get(block.Type()))
+	if len(schema) == 0 {
+		return errors.New("block type has no settings schema: " + block.Type())
+	}
+
+	values, err := cmsstore.BlockSettingsFromJSON(settings)
+	if err != nil {
+		return err
+	}
+
+	return cmsstore.ApplyBlockSettings(block, schema, values)
+}
+
 // handleBlockDelete handles HTTP requests to delete a block
 func (api *RestAPI) handleBlockDelete(w http.ResponseWriter, r *http.Request, blockID string) {
 	// Delete the block
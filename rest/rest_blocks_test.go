@@ -223,6 +223,105 @@ func TestBlockUpdate(t *testing.T) {
 	}
 }
 
+// settingsTestBlockType is a block type declaring a settings schema
+type settingsTestBlockType struct{}
+
+func (t *settingsTestBlockType) TypeKey() string   { return "rest_settings_test" }
+func (t *settingsTestBlockType) TypeLabel() string { return "Settings Test" }
+func (t *settingsTestBlockType) Render(_ context.Context, _ cmsstore.BlockInterface, _ ...cmsstore.RenderOption) (string, error) {
+	return "", nil
+}
+func (t *settingsTestBlockType) GetAdminFields(_ cmsstore.BlockInterface, _ *http.Request) interface{} {
+	return nil
+}
+func (t *settingsTestBlockType) SaveAdminFields(_ *http.Request, _ cmsstore.BlockInterface) error {
+	return nil
+}
+func (t *settingsTestBlockType) GetCustomVariables() []cmsstore.BlockCustomVariable { return nil }
+func (t *settingsTestBlockType) SettingsSchema() []cmsstore.BlockSettingDefinition {
+	return []cmsstore.BlockSettingDefinition{
+		{Name: "layout", Label: "Layout", Type: cmsstore.BLOCK_SETTING_TYPE_ENUM, Options: []cmsstore.BlockSettingOption{
+			{Value: "grid", Label: "Grid"},
+			{Value: "list", Label: "List"},
+		}},
+	}
+}
+
+// TestBlockCreateAndUpdate_Settings tests the settings validation of the
+// POST /api/blocks and PUT /api/blocks/{id} endpoints
+func TestBlockCreateAndUpdate_Settings(t *testing.T) {
+	serverURL, store, testSite, _, _, cleanup := setupBlockTest(t)
+	defer cleanup()
+
+	cmsstore.RegisterCustomBlockType(&settingsTestBlockType{})
+
+	createData, err := json.Marshal(map[string]interface{}{
+		"name":     "Settings Block",
+		"type":     "rest_settings_test",
+		"site_id":  testSite.ID(),
+		"settings": map[string]interface{}{"layout": "grid"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal JSON: %v", err)
+	}
+
+	resp, err := http.Post(serverURL+"/api/blocks", "application/json", bytes.NewBuffer(createData))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status OK, got %v", resp.Status)
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	blockID, _ := result["id"].(string)
+
+	block, err := store.BlockFindByID(context.Background(), blockID)
+	if err != nil || block == nil {
+		t.Fatalf("Failed to find block: %v", err)
+	}
+	if block.Type() != "rest_settings_test" || block.Meta("layout") != "grid" {
+		t.Errorf("Expected type and layout to be saved, got %q and %q", block.Type(), block.Meta("layout"))
+	}
+
+	updateData, err := json.Marshal(map[string]interface{}{
+		"settings": map[string]interface{}{"layout": "table"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal JSON: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, serverURL+"/api/blocks/"+blockID, bytes.NewBuffer(updateData))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	updateResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer updateResp.Body.Close()
+
+	if updateResp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status Bad Request, got %v", updateResp.Status)
+	}
+
+	block, err = store.BlockFindByID(context.Background(), blockID)
+	if err != nil || block == nil {
+		t.Fatalf("Failed to find block: %v", err)
+	}
+	if block.Meta("layout") != "grid" {
+		t.Errorf("Expected layout to be unchanged, got %q", block.Meta("layout"))
+	}
+}
+
 // TestBlockDelete tests the DELETE /api/blocks/{id} endpoint (delete block)
 func TestBlockDelete(t *testing.T) {
 	serverURL, store, _, _, testBlock, cleanup := setupBlockTest(t)
@@ -11,6 +11,12 @@ import (
 
 // handleMenusEndpoint handles HTTP requests for the /api/menus endpoint
 func (api *RestAPI) handleMenusEndpoint(w http.ResponseWriter, r *http.Request, pathParts []string) {
+	// The menu item tree, /api/menus/{id}/tree
+	if len(pathParts) > 1 && pathParts[0] != "" && pathParts[1] == "tree" {
+		api.handleMenuTreeEndpoint(w, r, pathParts[0])
+		return
+	}
+
 	switch r.Method {
 	case http.MethodPost:
 		// Create a new menu
@@ -0,0 +1,134 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dracory/cmsstore"
+	"github.com/spf13/cast"
+)
+
+// handleMenuTreeEndpoint handles HTTP requests for the /api/menus/{id}/tree endpoint
+func (api *RestAPI) handleMenuTreeEndpoint(w http.ResponseWriter, r *http.Request, menuID string) {
+	switch r.Method {
+	case http.MethodGet:
+		// Get the menu item tree
+		api.handleMenuTreeGet(w, r, menuID)
+	case http.MethodPost:
+		// Move a menu item within the tree
+		api.handleMenuTreeMove(w, r, menuID)
+	default:
+		http.Error(w, `{"success":false,"error":"Method not allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
+
+// handleMenuTreeGet handles HTTP requests to get the menu item tree of a menu
+func (api *RestAPI) handleMenuTreeGet(w http.ResponseWriter, r *http.Request, menuID string) {
+	menu, err := api.store.MenuFindByID(r.Context(), menuID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"success":false,"error":"Failed to find menu: %v"}`, err), http.StatusInternalServerError)
+		return
+	}
+
+	if menu == nil {
+		http.Error(w, `{"success":false,"error":"Menu not found"}`, http.StatusNotFound)
+		return
+	}
+
+	api.writeMenuTree(w, r, menuID)
+}
+
+// handleMenuTreeMove handles HTTP requests to move a menu item under a
+// parent item (empty for the top level), at a position among its siblings
+func (api *RestAPI) handleMenuTreeMove(w http.ResponseWriter, r *http.Request, menuID string) {
+	// Read the request body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"success":false,"error":"Failed to read request body: %v"}`, err), http.StatusBadRequest)
+		return
+	}
+
+	// Parse the request body
+	var moveData map[string]interface{}
+	if err := json.Unmarshal(body, &moveData); err != nil {
+		http.Error(w, fmt.Sprintf(`{"success":false,"error":"Failed to parse request body: %v"}`, err), http.StatusBadRequest)
+		return
+	}
+
+	// Validate required fields
+	itemID, ok := moveData["item_id"].(string)
+	if !ok || itemID == "" {
+		http.Error(w, `{"success":false,"error":"Item ID is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	parentID, _ := moveData["parent_id"].(string)
+	position := cast.ToInt(moveData["position"])
+
+	menuItem, err := api.store.MenuItemFindByID(r.Context(), itemID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"success":false,"error":"Failed to find menu item: %v"}`, err), http.StatusInternalServerError)
+		return
+	}
+
+	if menuItem == nil || menuItem.MenuID() != menuID {
+		http.Error(w, `{"success":false,"error":"Menu item not found"}`, http.StatusNotFound)
+		return
+	}
+
+	if err := api.store.MenuItemMove(r.Context(), itemID, parentID, position); err != nil {
+		http.Error(w, fmt.Sprintf(`{"success":false,"error":"Failed to move menu item: %v"}`, err), http.StatusBadRequest)
+		return
+	}
+
+	api.writeMenuTree(w, r, menuID)
+}
+
+// writeMenuTree writes the menu item tree of the menu as the response
+func (api *RestAPI) writeMenuTree(w http.ResponseWriter, r *http.Request, menuID string) {
+	tree, err := api.store.MenuTree(r.Context(), menuID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"success":false,"error":"Failed to get menu tree: %v"}`, err), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"menu_id": menuID,
+		"items":   menuTreeToList(tree),
+	}
+
+	jsonResponse, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"success":false,"error":"Failed to create response: %v"}`, err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResponse)
+}
+
+// menuTreeToList converts the menu tree nodes to the response format
+func menuTreeToList(nodes []*cmsstore.MenuTreeNode) []map[string]interface{} {
+	list := make([]map[string]interface{}, 0, len(nodes))
+
+	for _, node := range nodes {
+		item := node.MenuItem
+		list = append(list, map[string]interface{}{
+			"id":        item.ID(),
+			"name":      item.Name(),
+			"type":      item.Type(),
+			"parent_id": item.ParentID(),
+			"page_id":   item.PageID(),
+			"url":       item.URL(),
+			"target":    item.Target(),
+			"sequence":  item.SequenceInt(),
+			"status":    item.Status(),
+			"children":  menuTreeToList(node.Children),
+		})
+	}
+
+	return list
+}
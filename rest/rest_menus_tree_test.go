@@ -0,0 +1,151 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/dracory/cmsstore"
+)
+
+func TestMenuTreeEndpoints(t *testing.T) {
+	serverURL, store, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	testMenu := cmsstore.NewMenu()
+	testMenu.SetName("Test Menu")
+	testMenu.SetSiteID("Site_01")
+	testMenu.SetStatus(cmsstore.MENU_STATUS_ACTIVE)
+	if err := store.MenuCreate(ctx, testMenu); err != nil {
+		t.Fatalf("Failed to create test menu: %v", err)
+	}
+
+	newItem := func(name, parentID string, sequence int) cmsstore.MenuItemInterface {
+		item := cmsstore.NewMenuItem().
+			SetMenuID(testMenu.ID()).
+			SetName(name).
+			SetParentID(parentID).
+			SetSequenceInt(sequence)
+		if err := store.MenuItemCreate(ctx, item); err != nil {
+			t.Fatalf("Failed to create test menu item: %v", err)
+		}
+		return item
+	}
+
+	home := newItem("Home", "", 0)
+	about := newItem("About", "", 1)
+	newItem("Team", about.ID(), 0)
+
+	decodeItems := func(t *testing.T, resp *http.Response) []interface{} {
+		t.Helper()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status OK, got %v", resp.Status)
+		}
+
+		var result map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if success, ok := result["success"].(bool); !ok || !success {
+			t.Fatalf("Expected success to be true, got %v", result["success"])
+		}
+
+		items, ok := result["items"].([]interface{})
+		if !ok {
+			t.Fatalf("Expected items to be an array, got %T", result["items"])
+		}
+
+		return items
+	}
+
+	t.Run("Get Menu Tree", func(t *testing.T) {
+		resp, err := http.Get(serverURL + "/api/menus/" + testMenu.ID() + "/tree")
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		items := decodeItems(t, resp)
+
+		if len(items) != 2 {
+			t.Fatalf("Expected 2 top level items, got %d", len(items))
+		}
+
+		second := items[1].(map[string]interface{})
+		if second["name"] != "About" {
+			t.Errorf("Expected the second item to be About, got %v", second["name"])
+		}
+
+		children := second["children"].([]interface{})
+		if len(children) != 1 || children[0].(map[string]interface{})["name"] != "Team" {
+			t.Errorf("Expected About to have the Team child, got %v", children)
+		}
+	})
+
+	t.Run("Move Menu Item", func(t *testing.T) {
+		jsonData, err := json.Marshal(map[string]interface{}{
+			"item_id":   home.ID(),
+			"parent_id": about.ID(),
+			"position":  1,
+		})
+		if err != nil {
+			t.Fatalf("Failed to marshal JSON: %v", err)
+		}
+
+		resp, err := http.Post(serverURL+"/api/menus/"+testMenu.ID()+"/tree", "application/json", bytes.NewBuffer(jsonData))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		items := decodeItems(t, resp)
+
+		if len(items) != 1 {
+			t.Fatalf("Expected 1 top level item, got %d", len(items))
+		}
+
+		children := items[0].(map[string]interface{})["children"].([]interface{})
+		if len(children) != 2 || children[1].(map[string]interface{})["name"] != "Home" {
+			t.Errorf("Expected Home to be the second child of About, got %v", children)
+		}
+	})
+
+	t.Run("Move Menu Item Into Descendant", func(t *testing.T) {
+		jsonData, _ := json.Marshal(map[string]interface{}{
+			"item_id":   about.ID(),
+			"parent_id": home.ID(),
+		})
+
+		resp, err := http.Post(serverURL+"/api/menus/"+testMenu.ID()+"/tree", "application/json", bytes.NewBuffer(jsonData))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status Bad Request, got %v", resp.Status)
+		}
+	})
+
+	t.Run("Move Menu Item Of Another Menu", func(t *testing.T) {
+		jsonData, _ := json.Marshal(map[string]interface{}{
+			"item_id": home.ID(),
+		})
+
+		resp, err := http.Post(serverURL+"/api/menus/OTHER_MENU/tree", "application/json", bytes.NewBuffer(jsonData))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status Not Found, got %v", resp.Status)
+		}
+	})
+}
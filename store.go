@@ -7,6 +7,7 @@ import (
 
 	"github.com/dracory/database"
 	"github.com/dracory/neat"
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
 	contractsschema "github.com/dracory/neat/contracts/database/schema"
 )
 
@@ -47,12 +48,22 @@ type storeImplementation struct {
 	mediaEnabled   bool
 	mediaTableName string
 
+	// Form Submissions
+	formsEnabled            bool
+	formSubmissionTableName string
+
 	// Shortcodes
 	shortcodes  []ShortcodeInterface
 	middlewares []MiddlewareInterface
 
+	// Block types
+	blockTypeRegistry *BlockTypeRegistry
+
 	// Pending versioning operations to execute after transaction commit
 	pendingVersioningOps []pendingVersioningOp
+
+	// Changes made through the store, for the caches
+	changeVersions *changeVersions
 }
 
 type pendingVersioningOp struct {
@@ -92,6 +103,8 @@ func (store *storeImplementation) MigrateUp(ctx context.Context, tx ...*sql.Tx)
 			table.String(COLUMN_STATUS, 40)
 			table.String(COLUMN_ALIAS, 255)
 			table.String(COLUMN_NAME, 255)
+			table.String(COLUMN_PARENT_ID, 40)
+			table.Integer(COLUMN_SEQUENCE)
 			table.String(COLUMN_TITLE, 255)
 			table.Text(COLUMN_CONTENT)
 			table.String(COLUMN_EDITOR, 40)
@@ -114,6 +127,25 @@ func (store *storeImplementation) MigrateUp(ctx context.Context, tx ...*sql.Tx)
 		}
 	}
 
+	// Add page hierarchy columns to page tables created before they existed
+	if !store.neatDB.Schema().HasColumn(store.pageTableName, COLUMN_PARENT_ID) {
+		err := store.neatDB.Schema().Table(store.pageTableName, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_PARENT_ID, 40).Default("")
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !store.neatDB.Schema().HasColumn(store.pageTableName, COLUMN_SEQUENCE) {
+		err := store.neatDB.Schema().Table(store.pageTableName, func(table contractsschema.Blueprint) {
+			table.Integer(COLUMN_SEQUENCE).Default(0)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create block table
 	if !store.neatDB.Schema().HasTable(store.blockTableName) {
 		err := store.neatDB.Schema().Create(store.blockTableName, func(table contractsschema.Blueprint) {
@@ -289,6 +321,31 @@ func (store *storeImplementation) MigrateUp(ctx context.Context, tx ...*sql.Tx)
 		}
 	}
 
+	if store.formsEnabled {
+		if !store.neatDB.Schema().HasTable(store.formSubmissionTableName) {
+			err := store.neatDB.Schema().Create(store.formSubmissionTableName, func(table contractsschema.Blueprint) {
+				table.String(COLUMN_ID, 40)
+				table.Primary(COLUMN_ID)
+				table.String(COLUMN_SITE_ID, 40)
+				table.String(COLUMN_BLOCK_ID, 40)
+				table.String(COLUMN_PAGE_ID, 40)
+				table.String(COLUMN_STATUS, 40)
+				table.LongText(COLUMN_FORM_DATA)
+				table.String(COLUMN_IP_ADDRESS, 45)
+				table.String(COLUMN_USER_AGENT, 255)
+				table.String(COLUMN_SOURCE_URL, 255)
+				table.Text(COLUMN_METAS)
+				table.Text(COLUMN_MEMO)
+				table.DateTime(COLUMN_CREATED_AT)
+				table.DateTime(COLUMN_UPDATED_AT)
+				table.DateTime(COLUMN_SOFT_DELETED_AT)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -360,6 +417,15 @@ func (store *storeImplementation) MigrateDown(ctx context.Context, tx ...*sql.Tx
 		}
 	}
 
+	if store.formsEnabled {
+		if store.neatDB.Schema().HasTable(store.formSubmissionTableName) {
+			err := store.neatDB.Schema().Drop(store.formSubmissionTableName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -392,11 +458,30 @@ func (store *storeImplementation) MediaEnabled() bool {
 	return store.mediaEnabled
 }
 
+// FormsEnabled checks if the storage of the form submissions is enabled.
+func (store *storeImplementation) FormsEnabled() bool {
+	return store.formsEnabled
+}
+
 // CustomEntityStore returns the custom entity store.
 func (store *storeImplementation) CustomEntityStore() *CustomEntityStore {
 	return store.customEntityStore
 }
 
+// ChangeVersion returns the number of changes made through the store
+// to the kind of data (see CHANGE_KIND_*).
+func (store *storeImplementation) ChangeVersion(kind string) uint64 {
+	return store.changeVersions.version(kind)
+}
+
+// BlockTypeRegistry returns the registry of the block types used by the store.
+func (store *storeImplementation) BlockTypeRegistry() *BlockTypeRegistry {
+	if store.blockTypeRegistry == nil {
+		return DefaultBlockTypeRegistry()
+	}
+	return store.blockTypeRegistry
+}
+
 // Shortcodes returns the list of shortcodes.
 func (store *storeImplementation) Shortcodes() []ShortcodeInterface {
 	return store.shortcodes
@@ -446,6 +531,16 @@ func (store *storeImplementation) toQuerableContext(ctx context.Context) databas
 	return database.Context(ctx, store.db)
 }
 
+// queryClone returns a copy of the query, as the query builder is changed
+// in place, i.e. to run several queries in the transaction of the caller
+func queryClone(q contractsorm.Query) contractsorm.Query {
+	if cloner, ok := q.(interface{ Clone() contractsorm.Query }); ok {
+		return cloner.Clone()
+	}
+
+	return q
+}
+
 func (store *storeImplementation) withTransaction(ctx context.Context, fn func(txCtx context.Context) error) error {
 	// Execute the operation directly without creating an internal raw sql.Tx.
 	//
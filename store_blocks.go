@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
 
 	contractsorm "github.com/dracory/neat/contracts/database/orm"
 	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
 )
 
 // BlockCount returns the count of blocks matching the provided query options.
@@ -21,7 +23,7 @@ func (store *storeImplementation) BlockCount(ctx context.Context, options BlockQ
 		options.SetCountOnly(true)
 	}
 
-	q, _, err := store.blockSelectQuery(options)
+	q, _, err := store.blockSelectQuery(store.neatDB.Query(), options)
 
 	if err != nil {
 		return -1, err
@@ -60,6 +62,8 @@ func (store *storeImplementation) BlockCreate(ctx context.Context, block BlockIn
 
 		block.MarkAsNotDirty() // Mark the block as not dirty after successful insertion
 
+		store.changeVersions.bump(CHANGE_KIND_BLOCKS)
+
 		return store.versioningTrackEntity(txCtx, VERSIONING_TYPE_BLOCK, block.ID(), block)
 	})
 }
@@ -93,6 +97,10 @@ func (store *storeImplementation) BlockDeleteByID(ctx context.Context, id string
 
 	_, err := store.neatDB.Query().Table(store.blockTableName).Where("id = ?", id).Delete()
 
+	if err == nil {
+		store.changeVersions.bump(CHANGE_KIND_BLOCKS)
+	}
+
 	return err // Return the error if the query execution failed
 }
 
@@ -129,11 +137,17 @@ func (store *storeImplementation) BlockFindByID(ctx context.Context, id string)
 		return nil, errors.New("block id is empty") // Return an error if the block ID is empty
 	}
 
+	return store.blockFindByID(store.neatDB.Query(), id)
+}
+
+// blockFindByID finds a block by its ID (or short ID) with the given
+// query, i.e. the transaction of the caller
+func (store *storeImplementation) blockFindByID(q contractsorm.Query, id string) (BlockInterface, error) {
 	// Normalize ID to lowercase for consistent lookups
 	id = NormalizeID(id)
 
 	// Try direct lookup first (handles both 9-char and 32-char IDs)
-	list, err := store.BlockList(ctx, BlockQuery().SetID(id).SetLimit(1)) // Get the list of blocks matching the ID
+	list, err := store.blockList(q, BlockQuery().SetID(id).SetLimit(1)) // Get the list of blocks matching the ID
 
 	if err != nil {
 		return nil, err // Return the error if the query execution failed
@@ -147,7 +161,7 @@ func (store *storeImplementation) BlockFindByID(ctx context.Context, id string)
 	if IsShortID(id) {
 		unshortenedID := UnshortenID(id)
 		if unshortenedID != id {
-			list, err = store.BlockList(ctx, BlockQuery().SetID(unshortenedID).SetLimit(1))
+			list, err = store.blockList(q, BlockQuery().SetID(unshortenedID).SetLimit(1))
 			if err != nil {
 				return nil, err
 			}
@@ -169,7 +183,13 @@ func (store *storeImplementation) BlockList(ctx context.Context, query BlockQuer
 		return []BlockInterface{}, nil
 	}
 
-	q, _, err := store.blockSelectQuery(query)
+	return store.blockList(store.neatDB.Query(), query)
+}
+
+// blockList lists the blocks with the given query,
+// i.e. the transaction of the caller
+func (store *storeImplementation) blockList(base contractsorm.Query, query BlockQueryInterface) ([]BlockInterface, error) {
+	q, _, err := store.blockSelectQuery(base, query)
 
 	if err != nil {
 		return []BlockInterface{}, err
@@ -228,6 +248,251 @@ func (store *storeImplementation) BlockList(ctx context.Context, query BlockQuer
 	return list, nil
 }
 
+// BlockMove moves the block under the new parent block (empty for
+// a top level block), at the given zero-based position.
+//
+// Business Logic:
+//   - the parent block must exist and belong to the same site
+//   - a block cannot be moved into itself or its descendants
+//   - the position is clamped to the range of the new siblings
+//   - the sequences of the new (and old) siblings are renumbered from 0
+func (store *storeImplementation) BlockMove(ctx context.Context, blockID string, parentID string, position int) error {
+	if store.neatDB == nil {
+		return errors.New("blockstore: database is nil")
+	}
+
+	if blockID == "" {
+		return errors.New("block id is empty")
+	}
+
+	return store.withTransaction(ctx, func(txCtx context.Context) error {
+		var changed []BlockInterface
+
+		// The blocks are read and renumbered in the transaction of the
+		// updates, so concurrent moves do not renumber stale siblings
+		err := store.neatDB.Transaction(func(tx contractsorm.Query) error {
+			var err error
+			changed, err = store.blockMoveTx(tx, blockID, parentID, position)
+			return err
+		})
+
+		if err != nil || len(changed) == 0 {
+			return err
+		}
+
+		store.changeVersions.bump(CHANGE_KIND_BLOCKS)
+
+		for _, block := range changed {
+			block.MarkAsNotDirty()
+
+			if err := store.versioningTrackEntity(txCtx, VERSIONING_TYPE_BLOCK, block.ID(), block); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// blockMoveTx moves the block in the transaction (see BlockMove),
+// returns the moved block and the siblings with a changed sequence
+func (store *storeImplementation) blockMoveTx(tx contractsorm.Query, blockID string, parentID string, position int) ([]BlockInterface, error) {
+	block, err := store.blockFindByID(tx, blockID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if block == nil {
+		return nil, errors.New("block not found")
+	}
+
+	if parentID != "" {
+		parent, err := store.blockFindByID(tx, parentID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if parent == nil {
+			return nil, errors.New("parent block not found")
+		}
+
+		if parent.SiteID() != block.SiteID() {
+			return nil, errors.New("parent block belongs to another site")
+		}
+
+		// Walk up the ancestors of the new parent, to prevent cycles
+		visited := map[string]bool{}
+		for ancestor := parent; ancestor != nil && !visited[ancestor.ID()]; {
+			if ancestor.ID() == block.ID() {
+				return nil, errors.New("block cannot be moved into itself or its descendants")
+			}
+
+			visited[ancestor.ID()] = true
+
+			if ancestor.ParentID() == "" {
+				break
+			}
+
+			ancestor, err = store.blockFindByID(tx, ancestor.ParentID())
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		parentID = parent.ID()
+	}
+
+	oldParentID := block.ParentID()
+
+	siblings, err := store.blockSiblings(tx, block.SiteID(), parentID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	siblings = slices.DeleteFunc(siblings, func(sibling BlockInterface) bool {
+		return sibling.ID() == block.ID()
+	})
+
+	position = max(0, min(position, len(siblings)))
+	siblings = slices.Insert(siblings, position, BlockInterface(block))
+
+	if block.ParentID() != parentID {
+		block.SetParentID(parentID)
+	}
+
+	blockResequence(siblings)
+
+	blocks := siblings
+
+	if oldParentID != parentID {
+		oldSiblings, err := store.blockSiblings(tx, block.SiteID(), oldParentID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		oldSiblings = slices.DeleteFunc(oldSiblings, func(sibling BlockInterface) bool {
+			return sibling.ID() == block.ID()
+		})
+
+		blockResequence(oldSiblings)
+
+		blocks = append(blocks, oldSiblings...)
+	}
+
+	changed := lo.Filter(blocks, func(block BlockInterface, _ int) bool {
+		return len(block.DataChanged()) > 0
+	})
+
+	updatedAt := carbon.Now(carbon.UTC).ToDateTimeString()
+
+	// Only the parent and the sequence are changed by a move
+	sqlStr := "UPDATE " + store.blockTableName + " SET " +
+		COLUMN_PARENT_ID + " = ?, " +
+		COLUMN_SEQUENCE + " = ?, " +
+		COLUMN_UPDATED_AT + " = ? WHERE " +
+		COLUMN_ID + " = ?"
+
+	for _, block := range changed {
+		block.SetUpdatedAt(updatedAt)
+
+		if _, err := tx.Exec(sqlStr, block.ParentID(), block.Sequence(), block.UpdatedAt(), block.ID()); err != nil {
+			return nil, err
+		}
+	}
+
+	return changed, nil
+}
+
+// blockSiblings returns the blocks of the site with the given parent,
+// in sequence order
+func (store *storeImplementation) blockSiblings(tx contractsorm.Query, siteID string, parentID string) ([]BlockInterface, error) {
+	return store.blockList(tx, BlockQuery().
+		SetSiteID(siteID).
+		SetParentID(parentID).
+		SetOrderBy(COLUMN_SEQUENCE).
+		SetSortOrder(SORT_ORDER_ASC))
+}
+
+// blockResequence numbers the blocks in their order, from 0,
+// only the blocks with a different sequence are changed
+func blockResequence(blocks []BlockInterface) {
+	for sequence, block := range blocks {
+		if block.Sequence() != strconv.Itoa(sequence) {
+			block.SetSequenceInt(sequence)
+		}
+	}
+}
+
+// BlockUsageList returns where the block is used, i.e. for the admin
+// "where used" view of the library blocks.
+//
+// The content of the pages, templates and other blocks of the block's
+// site is scanned for references to the block, by its ID or handle.
+// Each reference is a separate usage, with its own overrides.
+func (store *storeImplementation) BlockUsageList(ctx context.Context, block BlockInterface) ([]BlockUsage, error) {
+	if store.neatDB == nil {
+		return []BlockUsage{}, errors.New("blockstore: database is nil")
+	}
+
+	if block == nil {
+		return []BlockUsage{}, errors.New("block is nil")
+	}
+
+	usages := []BlockUsage{}
+
+	addUsages := func(entityType, entityID, entityName, content string) {
+		for _, overrides := range blockUsagesInContent(block, content) {
+			usages = append(usages, BlockUsage{
+				EntityType: entityType,
+				EntityID:   entityID,
+				EntityName: entityName,
+				Overrides:  overrides,
+			})
+		}
+	}
+
+	pages, err := store.PageList(ctx, PageQuery().SetSiteID(block.SiteID()).SetOrderBy(COLUMN_NAME).SetSortOrder(SORT_ORDER_ASC))
+
+	if err != nil {
+		return []BlockUsage{}, err
+	}
+
+	for _, page := range pages {
+		addUsages(BLOCK_USAGE_ENTITY_PAGE, page.ID(), page.Name(), page.Content())
+	}
+
+	templates, err := store.TemplateList(ctx, TemplateQuery().SetSiteID(block.SiteID()).SetOrderBy(COLUMN_NAME).SetSortOrder(SORT_ORDER_ASC))
+
+	if err != nil {
+		return []BlockUsage{}, err
+	}
+
+	for _, template := range templates {
+		addUsages(BLOCK_USAGE_ENTITY_TEMPLATE, template.ID(), template.Name(), template.Content())
+	}
+
+	blocks, err := store.BlockList(ctx, BlockQuery().SetSiteID(block.SiteID()).SetOrderBy(COLUMN_NAME).SetSortOrder(SORT_ORDER_ASC))
+
+	if err != nil {
+		return []BlockUsage{}, err
+	}
+
+	for _, other := range blocks {
+		if other.ID() == block.ID() {
+			continue
+		}
+
+		addUsages(BLOCK_USAGE_ENTITY_BLOCK, other.ID(), other.Name(), other.Content())
+	}
+
+	return usages, nil
+}
+
 func (store *storeImplementation) BlockSoftDelete(ctx context.Context, block BlockInterface) error {
 	if store.neatDB == nil {
 		return errors.New("blockstore: database is nil")
@@ -291,11 +556,13 @@ func (store *storeImplementation) BlockUpdate(ctx context.Context, block BlockIn
 
 		block.MarkAsNotDirty()
 
+		store.changeVersions.bump(CHANGE_KIND_BLOCKS)
+
 		return store.versioningTrackEntity(txCtx, VERSIONING_TYPE_BLOCK, block.ID(), block)
 	})
 }
 
-func (store *storeImplementation) blockSelectQuery(options BlockQueryInterface) (query contractsorm.Query, columns []any, err error) {
+func (store *storeImplementation) blockSelectQuery(base contractsorm.Query, options BlockQueryInterface) (query contractsorm.Query, columns []any, err error) {
 	if options == nil {
 		return nil, []any{}, errors.New("block query: cannot be nil")
 	}
@@ -304,7 +571,8 @@ func (store *storeImplementation) blockSelectQuery(options BlockQueryInterface)
 		return nil, []any{}, err
 	}
 
-	q := store.neatDB.Query().Table(store.blockTableName)
+	// Cloned, as the query (i.e. a transaction) is reused by the caller
+	q := queryClone(base).Table(store.blockTableName)
 
 	if options.HasCreatedAtGte() && options.HasCreatedAtLte() {
 		q = q.Where(COLUMN_CREATED_AT+" >= ? AND "+COLUMN_CREATED_AT+" <= ?", options.CreatedAtGte(), options.CreatedAtLte())
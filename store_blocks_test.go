@@ -2,10 +2,13 @@ package cmsstore
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dracory/neat"
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
 )
 
 func TestStoreBlockCreate(t *testing.T) {
@@ -632,3 +635,234 @@ func TestStoreBlockUpdate(t *testing.T) {
 		t.Fatal("Metas do not match")
 	}
 }
+
+func TestStoreBlockMove(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	newBlock := func(name, parentID string, sequence int) BlockInterface {
+		block := NewBlock().
+			SetSiteID("SiteBlockMove").
+			SetName(name).
+			SetParentID(parentID).
+			SetSequenceInt(sequence)
+
+		if err := store.BlockCreate(ctx, block); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		return block
+	}
+
+	names := func(parentID string) string {
+		blocks, err := store.BlockList(ctx, BlockQuery().
+			SetSiteID("SiteBlockMove").
+			SetParentID(parentID).
+			SetOrderBy(COLUMN_SEQUENCE).
+			SetSortOrder(SORT_ORDER_ASC))
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		result := []string{}
+		for i, block := range blocks {
+			if block.SequenceInt() != i {
+				t.Fatalf("Sequence of %s MUST be %d, found: %d", block.Name(), i, block.SequenceInt())
+			}
+			result = append(result, block.Name())
+		}
+
+		return strings.Join(result, ",")
+	}
+
+	section := newBlock("section", "", 0)
+	a := newBlock("a", section.ID(), 0)
+	b := newBlock("b", section.ID(), 1)
+	c := newBlock("c", section.ID(), 2)
+	footer := newBlock("footer", "", 1)
+
+	// Reorder within the same parent
+	if err := store.BlockMove(ctx, c.ID(), section.ID(), 0); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := names(section.ID()); got != "c,a,b" {
+		t.Fatal("Children MUST be c,a,b, found:", got)
+	}
+
+	// Move into another parent, the old siblings are renumbered
+	if err := store.BlockMove(ctx, a.ID(), footer.ID(), 5); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := names(section.ID()); got != "c,b" {
+		t.Fatal("Children MUST be c,b, found:", got)
+	}
+
+	if got := names(footer.ID()); got != "a" {
+		t.Fatal("Children MUST be a, found:", got)
+	}
+
+	// Move to the top level
+	if err := store.BlockMove(ctx, b.ID(), "", 1); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := names(""); got != "section,b,footer" {
+		t.Fatal("Top level blocks MUST be section,b,footer, found:", got)
+	}
+
+	// Cycles are rejected
+	err = store.BlockMove(ctx, section.ID(), c.ID(), 0)
+
+	if err == nil {
+		t.Fatal("Moving a block into its descendant MUST fail")
+	}
+
+	if err := store.BlockMove(ctx, section.ID(), section.ID(), 0); err == nil {
+		t.Fatal("Moving a block into itself MUST fail")
+	}
+
+	if err := store.BlockMove(ctx, "missing", "", 0); err == nil {
+		t.Fatal("Moving a missing block MUST fail")
+	}
+}
+
+func TestStoreBlockMove_ReadsInTransaction(t *testing.T) {
+	db := initDB(":memory:")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		BlockTableName:     "block_table_move_tx",
+		PageTableName:      "page_table_move_tx",
+		SiteTableName:      "site_table_move_tx",
+		TemplateTableName:  "template_table_move_tx",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	blocks := []BlockInterface{}
+	for i, name := range []string{"a", "b", "c"} {
+		block := NewBlock().
+			SetSiteID("SiteBlockMoveTx").
+			SetName(name).
+			SetSequenceInt(i)
+
+		if err := store.BlockCreate(ctx, block); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	// The database has a single connection, held by the transaction, the
+	// move would not complete if the blocks were read outside of it
+	done := make(chan error, 1)
+	go func() {
+		done <- store.(*storeImplementation).neatDB.Transaction(func(tx contractsorm.Query) error {
+			changed, err := store.(*storeImplementation).blockMoveTx(tx, blocks[2].ID(), "", 0)
+
+			if err != nil {
+				return err
+			}
+
+			if len(changed) != 3 {
+				t.Errorf("Changed blocks MUST be 3, found: %d", len(changed))
+			}
+
+			return errors.New("rollback")
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || err.Error() != "rollback" {
+			t.Fatal("unexpected error:", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Move MUST read the blocks in the transaction")
+	}
+
+	for i, block := range blocks {
+		found, err := store.BlockFindByID(ctx, block.ID())
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if found.SequenceInt() != i {
+			t.Fatalf("Sequence of %s MUST be rolled back to %d, found: %d", found.Name(), i, found.SequenceInt())
+		}
+	}
+}
+
+func TestStoreBlockMove_Rollback(t *testing.T) {
+	db := initDB(":memory:")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		BlockTableName:     "block_table_move_rollback",
+		PageTableName:      "page_table_move_rollback",
+		SiteTableName:      "site_table_move_rollback",
+		TemplateTableName:  "template_table_move_rollback",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	blocks := []BlockInterface{}
+	for i, name := range []string{"a", "b", "c"} {
+		block := NewBlock().
+			SetSiteID("SiteBlockMoveRollback").
+			SetName(name).
+			SetSequenceInt(i)
+
+		if err := store.BlockCreate(ctx, block); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	// Fail the update of the last renumbered block, after the others
+	_, err = db.Exec(`CREATE TRIGGER block_move_rollback BEFORE UPDATE ON block_table_move_rollback
+		WHEN NEW.id = '` + blocks[1].ID() + `'
+		BEGIN SELECT RAISE(ABORT, 'update rejected'); END`)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	defer db.Exec(`DROP TRIGGER block_move_rollback`)
+
+	if err := store.BlockMove(ctx, blocks[2].ID(), "", 0); err == nil {
+		t.Fatal("Move MUST fail, when an update fails")
+	}
+
+	for i, block := range blocks {
+		found, err := store.BlockFindByID(ctx, block.ID())
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if found.SequenceInt() != i {
+			t.Fatalf("Sequence of %s MUST be rolled back to %d, found: %d", found.Name(), i, found.SequenceInt())
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package cmsstore
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/dromara/carbon/v2"
+)
+
+func (store *storeImplementation) FormSubmissionCount(ctx context.Context, options FormSubmissionQueryInterface) (int64, error) {
+	if store.neatDB == nil {
+		return -1, errors.New("cms store: database is nil")
+	}
+
+	if !store.formsEnabled {
+		return -1, errors.New("cms store: forms are not enabled")
+	}
+
+	if options != nil && !options.IsCountOnly() {
+		options.SetCountOnly(true)
+	}
+
+	q, _, err := store.formSubmissionSelectQuery(options)
+
+	if err != nil {
+		return -1, err
+	}
+
+	var count int64
+	err = q.Table(store.formSubmissionTableName).Count(&count)
+	return count, err
+}
+
+func (store *storeImplementation) FormSubmissionCreate(ctx context.Context, submission FormSubmissionInterface) error {
+	if store.neatDB == nil {
+		return errors.New("cms store: database is nil")
+	}
+
+	if !store.formsEnabled {
+		return errors.New("cms store: forms are not enabled")
+	}
+
+	if submission == nil {
+		return errors.New("form submission is nil")
+	}
+
+	submission.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	submission.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+
+	data := submission.Data()
+
+	if store.debugEnabled {
+		log.Println("FormSubmissionCreate:", data)
+	}
+
+	err := store.neatDB.Query().Table(store.formSubmissionTableName).Create(data)
+
+	if err != nil {
+		return err
+	}
+
+	submission.MarkAsNotDirty()
+
+	return nil
+}
+
+func (store *storeImplementation) FormSubmissionDelete(ctx context.Context, submission FormSubmissionInterface) error {
+	if submission == nil {
+		return errors.New("form submission is nil")
+	}
+
+	return store.FormSubmissionDeleteByID(ctx, submission.ID())
+}
+
+func (store *storeImplementation) FormSubmissionDeleteByID(ctx context.Context, id string) error {
+	if store.neatDB == nil {
+		return errors.New("cms store: database is nil")
+	}
+
+	if !store.formsEnabled {
+		return errors.New("cms store: forms are not enabled")
+	}
+
+	if id == "" {
+		return errors.New("form submission id is empty")
+	}
+
+	if store.debugEnabled {
+		log.Println("FormSubmissionDeleteByID:", id)
+	}
+
+	_, err := store.neatDB.Query().Table(store.formSubmissionTableName).Where("id = ?", id).Delete()
+
+	return err
+}
+
+func (store *storeImplementation) FormSubmissionFindByID(ctx context.Context, id string) (FormSubmissionInterface, error) {
+	if id == "" {
+		return nil, errors.New("form submission id is empty")
+	}
+
+	list, err := store.FormSubmissionList(ctx, FormSubmissionQuery().SetID(id).SetLimit(1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) > 0 {
+		return list[0], nil
+	}
+
+	return nil, nil
+}
+
+func (store *storeImplementation) FormSubmissionList(ctx context.Context, query FormSubmissionQueryInterface) ([]FormSubmissionInterface, error) {
+	if store.neatDB == nil {
+		return []FormSubmissionInterface{}, errors.New("cms store: database is nil")
+	}
+
+	if !store.formsEnabled {
+		return []FormSubmissionInterface{}, errors.New("cms store: forms are not enabled")
+	}
+
+	q, _, err := store.formSubmissionSelectQuery(query)
+
+	if err != nil {
+		return []FormSubmissionInterface{}, err
+	}
+
+	type formSubmissionRow struct {
+		ID            string `db:"id"`
+		SiteID        string `db:"site_id"`
+		BlockID       string `db:"block_id"`
+		PageID        string `db:"page_id"`
+		Status        string `db:"status"`
+		FormData      string `db:"form_data"`
+		IPAddress     string `db:"ip_address"`
+		UserAgent     string `db:"user_agent"`
+		SourceURL     string `db:"source_url"`
+		Metas         string `db:"metas"`
+		Memo          string `db:"memo"`
+		CreatedAt     string `db:"created_at"`
+		UpdatedAt     string `db:"updated_at"`
+		SoftDeletedAt string `db:"soft_deleted_at"`
+	}
+
+	var rows []formSubmissionRow
+	if err := q.Table(store.formSubmissionTableName).Get(&rows); err != nil {
+		return []FormSubmissionInterface{}, err
+	}
+
+	list := make([]FormSubmissionInterface, 0, len(rows))
+	for _, r := range rows {
+		modelMap := map[string]string{
+			"id":              r.ID,
+			"site_id":         r.SiteID,
+			"block_id":        r.BlockID,
+			"page_id":         r.PageID,
+			"status":          r.Status,
+			"form_data":       r.FormData,
+			"ip_address":      r.IPAddress,
+			"user_agent":      r.UserAgent,
+			"source_url":      r.SourceURL,
+			"metas":           r.Metas,
+			"memo":            r.Memo,
+			"created_at":      r.CreatedAt,
+			"updated_at":      r.UpdatedAt,
+			"soft_deleted_at": r.SoftDeletedAt,
+		}
+		list = append(list, NewFormSubmissionFromExistingData(modelMap))
+	}
+
+	return list, nil
+}
+
+func (store *storeImplementation) FormSubmissionSoftDelete(ctx context.Context, submission FormSubmissionInterface) error {
+	if submission == nil {
+		return errors.New("form submission is nil")
+	}
+
+	submission.SetSoftDeletedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+
+	return store.FormSubmissionUpdate(ctx, submission)
+}
+
+func (store *storeImplementation) FormSubmissionSoftDeleteByID(ctx context.Context, id string) error {
+	submission, err := store.FormSubmissionFindByID(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	if submission == nil {
+		return errors.New("form submission not found")
+	}
+
+	return store.FormSubmissionSoftDelete(ctx, submission)
+}
+
+func (store *storeImplementation) FormSubmissionUpdate(ctx context.Context, submission FormSubmissionInterface) error {
+	if store.neatDB == nil {
+		return errors.New("cms store: database is nil")
+	}
+
+	if !store.formsEnabled {
+		return errors.New("cms store: forms are not enabled")
+	}
+
+	if submission == nil {
+		return errors.New("form submission is nil")
+	}
+
+	submission.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString())
+
+	dataChanged := submission.DataChanged()
+
+	delete(dataChanged, COLUMN_ID)
+
+	if len(dataChanged) < 1 {
+		return nil
+	}
+
+	if store.debugEnabled {
+		log.Println("FormSubmissionUpdate:", dataChanged)
+	}
+
+	_, err := store.neatDB.Query().Table(store.formSubmissionTableName).Where("id = ?", submission.ID()).Update(dataChanged)
+	if err != nil {
+		return err
+	}
+
+	submission.MarkAsNotDirty()
+
+	return nil
+}
+
+func (store *storeImplementation) formSubmissionSelectQuery(options FormSubmissionQueryInterface) (query contractsorm.Query, selectColumns []any, err error) {
+	if options == nil {
+		return nil, []any{}, errors.New("form submission options cannot be nil")
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, []any{}, err
+	}
+
+	q := store.neatDB.Query().Table(store.formSubmissionTableName)
+
+	if options.HasID() {
+		q = q.Where(COLUMN_ID+" = ?", options.ID())
+	}
+
+	if options.HasIDIn() {
+		idIn := options.IDIn()
+		if len(idIn) > 0 {
+			placeholders := make([]string, len(idIn))
+			args := make([]any, len(idIn))
+			for i, v := range idIn {
+				placeholders[i] = "?"
+				args[i] = v
+			}
+			q = q.Where(COLUMN_ID+" IN ("+strings.Join(placeholders, ", ")+")", args...)
+		}
+	}
+
+	if options.HasSiteID() {
+		q = q.Where(COLUMN_SITE_ID+" = ?", options.SiteID())
+	}
+
+	if options.HasBlockID() {
+		q = q.Where(COLUMN_BLOCK_ID+" = ?", options.BlockID())
+	}
+
+	if options.HasStatus() {
+		q = q.Where(COLUMN_STATUS+" = ?", options.Status())
+	}
+
+	if options.HasStatusIn() {
+		statusIn := options.StatusIn()
+		if len(statusIn) > 0 {
+			placeholders := make([]string, len(statusIn))
+			args := make([]any, len(statusIn))
+			for i, v := range statusIn {
+				placeholders[i] = "?"
+				args[i] = v
+			}
+			q = q.Where(COLUMN_STATUS+" IN ("+strings.Join(placeholders, ", ")+")", args...)
+		}
+	}
+
+	if options.HasIPAddress() {
+		q = q.Where(COLUMN_IP_ADDRESS+" = ?", options.IPAddress())
+	}
+
+	if options.HasCreatedAtGte() {
+		q = q.Where(COLUMN_CREATED_AT+" >= ?", options.CreatedAtGte())
+	}
+
+	if !options.IsCountOnly() {
+		if options.HasLimit() {
+			q = q.Limit(options.Limit())
+		}
+
+		if options.HasOffset() {
+			q = q.Offset(options.Offset())
+		}
+	}
+
+	sortOrder := SORT_ORDER_DESC
+	if options.HasSortOrder() {
+		sortOrder = options.SortOrder()
+	}
+
+	if !options.IsCountOnly() && options.HasOrderBy() {
+		if strings.EqualFold(sortOrder, SORT_ORDER_ASC) {
+			q = q.OrderBy(options.OrderBy(), "ASC")
+		} else {
+			q = q.OrderBy(options.OrderBy(), "DESC")
+		}
+	}
+
+	columns := []any{}
+
+	for _, column := range options.Columns() {
+		columns = append(columns, column)
+	}
+
+	if options.SoftDeletedIncluded() {
+		return q, columns, nil
+	}
+
+	q = q.Where(COLUMN_SOFT_DELETED_AT+" > ?", carbon.Now(carbon.UTC).ToDateTimeString())
+
+	return q, columns, nil
+}
@@ -0,0 +1,196 @@
+package cmsstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dromara/carbon/v2"
+)
+
+func initFormsStore(t *testing.T) StoreInterface {
+	t.Helper()
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                      initDB(":memory:"),
+		BlockTableName:          "block_table",
+		PageTableName:           "page_table",
+		SiteTableName:           "site_table",
+		TemplateTableName:       "template_table",
+		FormsEnabled:            true,
+		FormSubmissionTableName: "form_submission_table",
+		AutomigrateEnabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return store
+}
+
+func TestStoreFormSubmissionCreateAndFind(t *testing.T) {
+	store := initFormsStore(t)
+	ctx := context.Background()
+
+	submission := NewFormSubmission().
+		SetSiteID("SiteFormCreate").
+		SetBlockID("FormCreateBlock").
+		SetIPAddress("192.0.2.1").
+		SetSourceURL("/contact")
+
+	if err := submission.SetFormData(map[string]string{"email": "jane@example.com", "message": "Hello"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := store.FormSubmissionCreate(ctx, submission); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found, err := store.FormSubmissionFindByID(ctx, submission.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if found == nil {
+		t.Fatal("expected form submission to be found")
+	}
+
+	if !found.IsNew() {
+		t.Errorf("expected status new, got %q", found.Status())
+	}
+
+	if found.FormValue("email") != "jane@example.com" || found.FormValue("message") != "Hello" {
+		t.Errorf("unexpected form data: %v", found.Data()[COLUMN_FORM_DATA])
+	}
+
+	if found.BlockID() != "FormCreateBlock" || found.IPAddress() != "192.0.2.1" || found.SourceURL() != "/contact" {
+		t.Errorf("unexpected form submission: %v", found.Data())
+	}
+}
+
+func TestStoreFormSubmissionListAndCount(t *testing.T) {
+	store := initFormsStore(t)
+	ctx := context.Background()
+
+	for _, ip := range []string{"192.0.2.10", "192.0.2.10", "192.0.2.11"} {
+		if err := store.FormSubmissionCreate(ctx, NewFormSubmission().
+			SetSiteID("SiteFormList").
+			SetBlockID("FormListBlock").
+			SetIPAddress(ip)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	list, err := store.FormSubmissionList(ctx, FormSubmissionQuery().
+		SetBlockID("FormListBlock").
+		SetOrderBy(COLUMN_CREATED_AT).
+		SetSortOrder(SORT_ORDER_DESC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(list) != 3 {
+		t.Errorf("expected 3 form submissions, got %d", len(list))
+	}
+
+	count, err := store.FormSubmissionCount(ctx, FormSubmissionQuery().
+		SetBlockID("FormListBlock").
+		SetIPAddress("192.0.2.10").
+		SetCreatedAtGte(carbon.Now(carbon.UTC).SubHour().ToDateTimeString(carbon.UTC)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("expected 2 form submissions from the IP address, got %d", count)
+	}
+}
+
+func TestStoreFormSubmissionUpdateAndSoftDelete(t *testing.T) {
+	store := initFormsStore(t)
+	ctx := context.Background()
+
+	submission := NewFormSubmission().SetSiteID("SiteFormUpdate").SetBlockID("FormUpdateBlock")
+
+	if err := store.FormSubmissionCreate(ctx, submission); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	submission.SetStatus(FORM_SUBMISSION_STATUS_READ)
+
+	if err := store.FormSubmissionUpdate(ctx, submission); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found, err := store.FormSubmissionFindByID(ctx, submission.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if found == nil || !found.IsRead() {
+		t.Fatalf("expected the form submission to be read, got %v", found)
+	}
+
+	if err := store.FormSubmissionSoftDeleteByID(ctx, submission.ID()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found, err = store.FormSubmissionFindByID(ctx, submission.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if found != nil {
+		t.Error("expected the soft deleted form submission not to be found")
+	}
+
+	list, err := store.FormSubmissionList(ctx, FormSubmissionQuery().
+		SetID(submission.ID()).
+		SetSoftDeletedIncluded(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(list) != 1 || !list[0].IsSoftDeleted() {
+		t.Errorf("expected the soft deleted form submission, got %v", list)
+	}
+
+	if err := store.FormSubmissionDeleteByID(ctx, submission.ID()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStoreFormSubmissionNotEnabled(t *testing.T) {
+	store, err := NewStore(NewStoreOptions{
+		DB:                 initDB(":memory:"),
+		BlockTableName:     "block_table",
+		PageTableName:      "page_table",
+		SiteTableName:      "site_table",
+		TemplateTableName:  "template_table",
+		AutomigrateEnabled: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if store.FormsEnabled() {
+		t.Error("expected forms not to be enabled")
+	}
+
+	err = store.FormSubmissionCreate(context.Background(), NewFormSubmission())
+	if err == nil || !strings.Contains(err.Error(), "not enabled") {
+		t.Errorf("expected forms not enabled error, got %v", err)
+	}
+
+	_, err = NewStore(NewStoreOptions{
+		DB:                initDB(":memory:"),
+		BlockTableName:    "block_table",
+		PageTableName:     "page_table",
+		SiteTableName:     "site_table",
+		TemplateTableName: "template_table",
+		FormsEnabled:      true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "FormSubmissionTableName is required") {
+		t.Errorf("expected table name required error, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package cmsstore
+
+import (
+	"context"
+	"strings"
+)
+
+// menuItemsPrepare prepares the resolved menu items for the visitor in
+// the context (see MenuItemsResolve)
+//
+// Business Logic:
+//   - the items hidden by their visibility rules are removed,
+//     with their descendants
+//   - the names and the URLs are replaced by the labels and the URLs
+//     of the visitor's language, or else of the default language
+//   - the anchor items link to the anchor, on their page if set
+//   - the entity items link to their URL pattern, with the entity
+//     values; the items of a missing entity are removed
+func (store *storeImplementation) menuItemsPrepare(ctx context.Context, items []MenuItemInterface) ([]MenuItemInterface, error) {
+	visitor := VisitorFromContext(ctx)
+	languages := []string{visitor.Language, store.TranslationLanguageDefault()}
+
+	itemsByID := map[string]MenuItemInterface{}
+	hidden := map[string]bool{}
+
+	for _, item := range items {
+		itemsByID[item.ID()] = item
+
+		if !MenuItemIsVisible(item, visitor) {
+			hidden[item.ID()] = true
+		}
+	}
+
+	// isHidden returns true if the item, or one of its ancestors, is hidden
+	isHidden := func(item MenuItemInterface) bool {
+		visited := map[string]bool{}
+
+		for item != nil && !visited[item.ID()] {
+			if hidden[item.ID()] {
+				return true
+			}
+
+			visited[item.ID()] = true
+			item = itemsByID[item.ParentID()]
+		}
+
+		return false
+	}
+
+	prepared := []MenuItemInterface{}
+
+	for _, item := range items {
+		if isHidden(item) {
+			continue
+		}
+
+		if label := menuItemTranslation(item, MENU_ITEM_META_LABEL_PREFIX, languages); label != "" {
+			item.SetName(label)
+		}
+
+		if url := menuItemTranslation(item, MENU_ITEM_META_URL_PREFIX, languages); url != "" {
+			item.SetURL(url)
+		}
+
+		switch item.Type() {
+		case MENU_ITEM_TYPE_ANCHOR:
+			itemURL, err := store.menuItemAnchorURL(ctx, item)
+
+			if err != nil {
+				return []MenuItemInterface{}, err
+			}
+
+			item.SetURL(itemURL)
+		case MENU_ITEM_TYPE_ENTITY:
+			itemURL, found, err := store.menuItemEntityURL(ctx, item)
+
+			if err != nil {
+				return []MenuItemInterface{}, err
+			}
+
+			if !found {
+				continue
+			}
+
+			item.SetURL(itemURL)
+		}
+
+		prepared = append(prepared, item)
+	}
+
+	return prepared, nil
+}
+
+// menuItemTranslation returns the per-language meta (i.e. the label)
+// of the first of the languages the menu item has it for, or empty
+func menuItemTranslation(item MenuItemInterface, prefix string, languages []string) string {
+	for _, language := range languages {
+		if language == "" {
+			continue
+		}
+
+		if value := item.Meta(prefix + language); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// menuItemAnchorURL returns the URL of the anchor item, the anchor on
+// the item's page if set, or else on the current page
+func (store *storeImplementation) menuItemAnchorURL(ctx context.Context, item MenuItemInterface) (string, error) {
+	anchor := "#" + item.Anchor()
+
+	if item.PageID() == "" {
+		return anchor, nil
+	}
+
+	page, err := store.PageFindByID(ctx, item.PageID())
+
+	if err != nil {
+		return "", err
+	}
+
+	if page == nil {
+		return anchor, nil
+	}
+
+	return "/" + strings.TrimPrefix(page.Alias(), "/") + anchor, nil
+}
+
+// menuItemEntityURL returns the URL of the entity item, its URL pattern
+// with the entity values, i.e. "/products/[[handle]]". Returns false if
+// the custom entities are not enabled, or the entity does not exist.
+func (store *storeImplementation) menuItemEntityURL(ctx context.Context, item MenuItemInterface) (string, bool, error) {
+	entityStore := store.CustomEntityStore()
+
+	if entityStore == nil || item.EntityID() == "" {
+		return "", false, nil
+	}
+
+	entity, err := entityStore.FindByID(ctx, item.EntityID())
+
+	if err != nil {
+		return "", false, err
+	}
+
+	if entity == nil {
+		return "", false, nil
+	}
+
+	values, err := customEntityValues(ctx, entityStore, entity)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	return menuSourceURL(item.URL(), values), true, nil
+}
@@ -0,0 +1,341 @@
+package cmsstore
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestStoreMenuItemsResolve_VisibilityAndLabels(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	page := NewPage().
+		SetSiteID("SiteMenuTypes").
+		SetName("Pricing").
+		SetAlias("/pricing").
+		SetStatus(PAGE_STATUS_ACTIVE)
+
+	if err := store.PageCreate(ctx, page); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	menu := NewMenu().SetSiteID("SiteMenuTypes").SetName("Main").SetStatus(MENU_STATUS_ACTIVE)
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	newItem := func(name, parentID string, sequence int) MenuItemInterface {
+		return NewMenuItem().
+			SetMenuID(menu.ID()).
+			SetName(name).
+			SetParentID(parentID).
+			SetSequenceInt(sequence).
+			SetStatus(MENU_ITEM_STATUS_ACTIVE)
+	}
+
+	home := newItem("Home", "", 0).SetURL("/").SetLabel("fr", "Accueil")
+
+	members := newItem("Members", "", 1)
+	if err := SetMenuItemVisibilityRules(members, BlockVisibility{LoggedIn: BLOCK_VISIBILITY_LOGGED_IN_YES}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	account := newItem("Account", members.ID(), 0).SetURL("/account")
+
+	plans := newItem("Plans", "", 2).
+		SetType(MENU_ITEM_TYPE_ANCHOR).
+		SetPageID(page.ID()).
+		SetAnchor("plans")
+
+	top := newItem("Top", "", 3).
+		SetType(MENU_ITEM_TYPE_ANCHOR).
+		SetAnchor("top")
+
+	for _, item := range []MenuItemInterface{home, members, account, plans, top} {
+		if err := store.MenuItemCreate(ctx, item); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	items, err := store.MenuItemsResolve(VisitorToContext(ctx, BlockVisitor{Language: "fr"}), menu.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := renderMenuItems(items); got != "Accueil,Plans,Top" {
+		t.Errorf("expected %q, got %q", "Accueil,Plans,Top", got)
+	}
+
+	urls := map[string]string{}
+	for _, item := range items {
+		urls[item.ID()] = item.URL()
+	}
+
+	if urls[plans.ID()] != "/pricing#plans" {
+		t.Errorf("expected the anchor on the page, got %q", urls[plans.ID()])
+	}
+	if urls[top.ID()] != "#top" {
+		t.Errorf("expected the anchor on the current page, got %q", urls[top.ID()])
+	}
+
+	items, err = store.MenuItemsResolve(VisitorToContext(ctx, BlockVisitor{LoggedIn: true}), menu.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := renderMenuItems(items); got != "Home,Members(Account),Plans,Top" {
+		t.Errorf("expected %q, got %q", "Home,Members(Account),Plans,Top", got)
+	}
+}
+
+func TestStoreMenuItemsResolve_EntityItems(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:?parseTime=true")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	defer db.Close()
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                    db,
+		BlockTableName:        "block_table",
+		PageTableName:         "page_table",
+		SiteTableName:         "site_table",
+		TemplateTableName:     "template_table",
+		MenusEnabled:          true,
+		MenuTableName:         "menu_table",
+		MenuItemTableName:     "menu_item_table",
+		AutomigrateEnabled:    true,
+		CustomEntitiesEnabled: true,
+		CustomEntityDefinitions: []CustomEntityDefinition{
+			{
+				Type:      "product",
+				TypeLabel: "Product",
+				Attributes: []CustomAttributeDefinition{
+					{Name: "title", Type: "string", Label: "Title", Required: true},
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	entityID, err := store.CustomEntityStore().Create(ctx, "product", map[string]interface{}{"title": "Desk & Chair"}, nil, nil)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	menu := NewMenu().SetSiteID("SiteMenuTypes").SetName("Main").SetStatus(MENU_STATUS_ACTIVE)
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	product := NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetName("Featured").
+		SetURL("/products/[[title]]").
+		SetType(MENU_ITEM_TYPE_ENTITY).
+		SetEntityID(entityID).
+		SetStatus(MENU_ITEM_STATUS_ACTIVE)
+
+	missing := NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetName("Missing").
+		SetSequenceInt(1).
+		SetURL("/products/[[title]]").
+		SetType(MENU_ITEM_TYPE_ENTITY).
+		SetEntityID("MISSING").
+		SetStatus(MENU_ITEM_STATUS_ACTIVE)
+
+	for _, item := range []MenuItemInterface{product, missing} {
+		if err := store.MenuItemCreate(ctx, item); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	items, err := store.MenuItemsResolve(ctx, menu.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("expected the item of the missing entity to be removed, got %q", renderMenuItems(items))
+	}
+
+	if items[0].URL() != "/products/Desk%20&%20Chair" {
+		t.Errorf("expected the entity URL, got %q", items[0].URL())
+	}
+}
+
+func TestStoreMenuItemsResolve_Translations(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:?parseTime=true")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	defer db.Close()
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                         db,
+		BlockTableName:             "block_table",
+		PageTableName:              "page_table",
+		SiteTableName:              "site_table",
+		TemplateTableName:          "template_table",
+		MenusEnabled:               true,
+		MenuTableName:              "menu_table",
+		MenuItemTableName:          "menu_item_table",
+		AutomigrateEnabled:         true,
+		TranslationLanguageDefault: "en",
+		TranslationLanguages:       map[string]string{"en": "English", "fr": "French", "de": "German"},
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	menu := NewMenu().SetSiteID("SiteMenuTranslations").SetName("Main").SetStatus(MENU_STATUS_ACTIVE)
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	contact := NewMenuItem().
+		SetMenuID(menu.ID()).
+		SetName("contact").
+		SetURL("/contact").
+		SetLabel("en", "Contact Us").
+		SetLabel("fr", "Contactez-nous").
+		SetTranslatedURL("fr", "/fr/contact").
+		SetStatus(MENU_ITEM_STATUS_ACTIVE)
+
+	if err := store.MenuItemCreate(ctx, contact); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	tests := []struct {
+		language string
+		name     string
+		url      string
+	}{
+		{language: "fr", name: "Contactez-nous", url: "/fr/contact"},
+		{language: "de", name: "Contact Us", url: "/contact"},
+		{language: "", name: "Contact Us", url: "/contact"},
+	}
+
+	for _, test := range tests {
+		items, err := store.MenuItemsResolve(VisitorToContext(ctx, BlockVisitor{Language: test.language}), menu.ID())
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if len(items) != 1 {
+			t.Fatalf("expected 1 item, got %d", len(items))
+		}
+
+		if items[0].Name() != test.name {
+			t.Errorf("language %q: expected name %q, got %q", test.language, test.name, items[0].Name())
+		}
+
+		if items[0].URL() != test.url {
+			t.Errorf("language %q: expected URL %q, got %q", test.language, test.url, items[0].URL())
+		}
+	}
+}
+
+// menuItemListCounter counts the menu item listings of the store
+type menuItemListCounter struct {
+	StoreInterface
+	calls int
+}
+
+func (store *menuItemListCounter) MenuItemList(ctx context.Context, query MenuItemQueryInterface) ([]MenuItemInterface, error) {
+	store.calls++
+	return store.StoreInterface.MenuItemList(ctx, query)
+}
+
+func TestMenuVisibilityRulesCache(t *testing.T) {
+	db, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	store := &menuItemListCounter{StoreInterface: db}
+	ctx := context.Background()
+
+	menu := NewMenu().SetSiteID("SiteMenuRulesCache").SetName("Main").SetStatus(MENU_STATUS_ACTIVE)
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	item := NewMenuItem().SetMenuID(menu.ID()).SetName("Members").SetStatus(MENU_ITEM_STATUS_ACTIVE)
+
+	if err := store.MenuItemCreate(ctx, item); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	cache := MenuVisibilityRulesCache{}
+
+	for range 3 {
+		hasRules, err := cache.MenuHasVisibilityRules(ctx, store, menu.ID())
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if hasRules {
+			t.Fatal("expected no visibility rules")
+		}
+	}
+
+	if store.calls != 1 {
+		t.Errorf("expected the menu items to be listed once, got %d", store.calls)
+	}
+
+	// The changes of the menus are seen
+	if err := SetMenuItemVisibilityRules(item, BlockVisibility{LoggedIn: BLOCK_VISIBILITY_LOGGED_IN_YES}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.MenuItemUpdate(ctx, item); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	hasRules, err := cache.MenuHasVisibilityRules(ctx, store, menu.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if !hasRules {
+		t.Error("expected the visibility rules after the menu item change")
+	}
+
+	if store.calls != 2 {
+		t.Errorf("expected the menu items to be listed again, got %d", store.calls)
+	}
+}
@@ -27,7 +27,7 @@ func (store *storeImplementation) MenuItemCount(ctx context.Context, options Men
 	}
 
 	// Generate the select query based on the options
-	q, _, err := store.menuItemSelectQuery(options)
+	q, _, err := store.menuItemSelectQuery(store.neatDB.Query(), options)
 
 	if err != nil {
 		return -1, err
@@ -81,6 +81,8 @@ func (store *storeImplementation) MenuItemCreate(ctx context.Context, menuItem M
 		// Mark the menu item as not dirty
 		menuItem.MarkAsNotDirty()
 
+		store.changeVersions.bump(CHANGE_KIND_MENUS)
+
 		return store.versioningTrackEntity(txCtx, VERSIONING_TYPE_MENU_ITEM, menuItem.ID(), menuItem)
 	})
 }
@@ -124,6 +126,10 @@ func (store *storeImplementation) MenuItemDeleteByID(ctx context.Context, id str
 
 	_, err := store.neatDB.Query().Table(store.menuItemTableName).Where("id = ?", id).Delete()
 
+	if err == nil {
+		store.changeVersions.bump(CHANGE_KIND_MENUS)
+	}
+
 	return err
 }
 
@@ -139,11 +145,21 @@ func (store *storeImplementation) MenuItemFindByID(ctx context.Context, id strin
 		return nil, errors.New("menuItem id is empty")
 	}
 
+	if store.neatDB == nil {
+		return nil, errors.New("menuItemstore: database is nil")
+	}
+
+	return store.menuItemFindByID(store.neatDB.Query(), id)
+}
+
+// menuItemFindByID finds a menu item by its ID (or short ID) with the
+// given query, i.e. the transaction of the caller
+func (store *storeImplementation) menuItemFindByID(q contractsorm.Query, id string) (MenuItemInterface, error) {
 	// Normalize ID to lowercase for consistent lookups
 	id = NormalizeID(id)
 
 	// Try direct lookup first (handles both 9-char and 32-char IDs)
-	list, err := store.MenuItemList(ctx, MenuItemQuery().SetID(id).SetLimit(1))
+	list, err := store.menuItemList(q, MenuItemQuery().SetID(id).SetLimit(1))
 
 	if err != nil {
 		return nil, err
@@ -158,7 +174,7 @@ func (store *storeImplementation) MenuItemFindByID(ctx context.Context, id strin
 	if IsShortID(id) {
 		unshortenedID := UnshortenID(id)
 		if unshortenedID != id {
-			list, err = store.MenuItemList(ctx, MenuItemQuery().SetID(unshortenedID).SetLimit(1))
+			list, err = store.menuItemList(q, MenuItemQuery().SetID(unshortenedID).SetLimit(1))
 			if err != nil {
 				return nil, err
 			}
@@ -182,8 +198,14 @@ func (store *storeImplementation) MenuItemList(ctx context.Context, query MenuIt
 		return []MenuItemInterface{}, errors.New("menuItemstore: database is nil")
 	}
 
+	return store.menuItemList(store.neatDB.Query(), query)
+}
+
+// menuItemList lists the menu items with the given query,
+// i.e. the transaction of the caller
+func (store *storeImplementation) menuItemList(base contractsorm.Query, query MenuItemQueryInterface) ([]MenuItemInterface, error) {
 	// Generate the select query based on the options
-	q, _, err := store.menuItemSelectQuery(query)
+	q, _, err := store.menuItemSelectQuery(base, query)
 
 	if err != nil {
 		return []MenuItemInterface{}, err
@@ -321,12 +343,14 @@ func (store *storeImplementation) MenuItemUpdate(ctx context.Context, menuItem M
 		// Mark the menu item as not dirty
 		menuItem.MarkAsNotDirty()
 
+		store.changeVersions.bump(CHANGE_KIND_MENUS)
+
 		return store.versioningTrackEntity(txCtx, VERSIONING_TYPE_MENU_ITEM, menuItem.ID(), menuItem)
 	})
 }
 
 // menuItemSelectQuery generates a select query based on the provided query options.
-func (store *storeImplementation) menuItemSelectQuery(options MenuItemQueryInterface) (query contractsorm.Query, columns []any, err error) {
+func (store *storeImplementation) menuItemSelectQuery(base contractsorm.Query, options MenuItemQueryInterface) (query contractsorm.Query, columns []any, err error) {
 	// Validate the query options
 	if options == nil {
 		return nil, nil, errors.New("menuItem query cannot be nil")
@@ -336,8 +360,9 @@ func (store *storeImplementation) menuItemSelectQuery(options MenuItemQueryInter
 		return nil, nil, err
 	}
 
-	// Start building the select query
-	q := store.neatDB.Query().Table(store.menuItemTableName)
+	// Start building the select query, on a copy as the query
+	// (i.e. a transaction) is reused by the caller
+	q := queryClone(base).Table(store.menuItemTableName)
 
 	// Apply filters based on the query options
 	if options.HasCreatedAtGte() && options.HasCreatedAtLte() {
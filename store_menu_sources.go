@@ -0,0 +1,451 @@
+package cmsstore
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dracory/entitystore"
+	"github.com/samber/lo"
+)
+
+// MenuItemsResolve returns the active menu items of the menu, with the
+// items generated by its sources (see MenuSource), in sequence order.
+// This is the list the menu renderers display. If the menu does not
+// exist, only its stored items are returned.
+//
+// The generated items are not stored, their IDs are derived from the
+// source index and the generated page (entity, term) ID, so they are
+// stable between the requests.
+//
+// The items are prepared for the visitor in the context (see
+// VisitorFromContext): the items hidden by their visibility rules are
+// removed with their descendants, the names are replaced by the labels
+// of the visitor's language, and the URLs of the anchor and entity items
+// are resolved.
+func (store *storeImplementation) MenuItemsResolve(ctx context.Context, menuID string) ([]MenuItemInterface, error) {
+	items, err := store.menuItemsWithSources(ctx, menuID)
+
+	if err != nil {
+		return []MenuItemInterface{}, err
+	}
+
+	return store.menuItemsPrepare(ctx, items)
+}
+
+// menuItemsWithSources returns the active stored menu items of the menu,
+// with the items generated by its sources, in sequence order
+func (store *storeImplementation) menuItemsWithSources(ctx context.Context, menuID string) ([]MenuItemInterface, error) {
+	if store.neatDB == nil {
+		return []MenuItemInterface{}, errors.New("menustore: database is nil")
+	}
+
+	if menuID == "" {
+		return []MenuItemInterface{}, errors.New("menu id is empty")
+	}
+
+	items, err := store.MenuItemList(ctx, MenuItemQuery().
+		SetMenuID(menuID).
+		SetStatus(MENU_ITEM_STATUS_ACTIVE).
+		SetOrderBy(COLUMN_SEQUENCE).
+		SetSortOrder(SORT_ORDER_ASC))
+
+	if err != nil {
+		return []MenuItemInterface{}, err
+	}
+
+	menu, err := store.MenuFindByID(ctx, menuID)
+
+	if err != nil {
+		return []MenuItemInterface{}, err
+	}
+
+	// Without the menu there are no sources, the stored items are returned
+	if menu == nil {
+		return items, nil
+	}
+
+	sources, err := MenuSources(menu)
+
+	if err != nil {
+		return []MenuItemInterface{}, err
+	}
+
+	if len(sources) == 0 {
+		return items, nil
+	}
+
+	// The next sequence of the children of each parent,
+	// so the generated items follow the manual items
+	nextSequence := map[string]int{}
+	itemIDs := map[string]bool{}
+	for _, item := range items {
+		itemIDs[item.ID()] = true
+		nextSequence[item.ParentID()] = max(nextSequence[item.ParentID()], item.SequenceInt()+1)
+	}
+
+	for index, source := range sources {
+		if err := source.Validate(); err != nil {
+			continue // an invalid source is skipped, it generates nothing
+		}
+
+		generator := menuSourceGenerator{
+			store:  store,
+			menu:   menu,
+			source: source,
+			prefix: "src" + strconv.Itoa(index) + "_",
+			index:  index,
+		}
+
+		nodes, err := generator.nodes(ctx)
+
+		if err != nil {
+			return []MenuItemInterface{}, err
+		}
+
+		parentID := lo.Ternary(itemIDs[source.ParentItemID], source.ParentItemID, "")
+
+		for _, node := range nodes {
+			node.item.SetParentID(parentID)
+			node.item.SetSequenceInt(nextSequence[parentID])
+			nextSequence[parentID]++
+			items = append(items, node.flatten()...)
+		}
+	}
+
+	slices.SortStableFunc(items, func(a, b MenuItemInterface) int {
+		return cmp.Compare(a.SequenceInt(), b.SequenceInt())
+	})
+
+	return items, nil
+}
+
+// menuSourceNode is a generated menu item, with its generated children
+type menuSourceNode struct {
+	item     MenuItemInterface
+	children []*menuSourceNode
+}
+
+// flatten returns the item and its descendants, the children
+// are nested under the item and numbered in their order
+func (node *menuSourceNode) flatten() []MenuItemInterface {
+	items := []MenuItemInterface{node.item}
+
+	for sequence, child := range node.children {
+		child.item.SetParentID(node.item.ID())
+		child.item.SetSequenceInt(sequence)
+		items = append(items, child.flatten()...)
+	}
+
+	return items
+}
+
+// menuSourceGenerator generates the menu items of a source
+type menuSourceGenerator struct {
+	store  *storeImplementation
+	menu   MenuInterface
+	source MenuSource
+	prefix string
+	index  int
+}
+
+// nodes returns the top level generated items, with their children
+func (generator menuSourceGenerator) nodes(ctx context.Context) ([]*menuSourceNode, error) {
+	switch generator.source.Type {
+	case MENU_SOURCE_TYPE_PAGE_CHILDREN:
+		parent, err := generator.store.PageFindByID(ctx, generator.source.PageID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if parent == nil {
+			return []*menuSourceNode{}, nil
+		}
+
+		return generator.pageChildren(ctx, parent, 1, map[string]bool{parent.ID(): true})
+	case MENU_SOURCE_TYPE_PAGE_QUERY:
+		return generator.pageQuery(ctx)
+	case MENU_SOURCE_TYPE_ENTITIES:
+		return generator.entities(ctx)
+	case MENU_SOURCE_TYPE_TAXONOMY:
+		return generator.taxonomyTerms(ctx)
+	}
+
+	return []*menuSourceNode{}, nil
+}
+
+// pageChildren generates the active child pages of the parent,
+// down to the depth of the source
+func (generator menuSourceGenerator) pageChildren(ctx context.Context, parent PageInterface, level int, visited map[string]bool) ([]*menuSourceNode, error) {
+	pages, err := generator.store.PageList(ctx, PageQuery().
+		SetSiteID(parent.SiteID()).
+		SetParentID(parent.ID()).
+		SetStatus(PAGE_STATUS_ACTIVE).
+		SetOrderBy(COLUMN_SEQUENCE).
+		SetSortOrder(SORT_ORDER_ASC))
+
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []*menuSourceNode{}
+
+	for _, page := range pages[:generator.limitCount(len(pages))] {
+		if visited[page.ID()] {
+			continue
+		}
+
+		visited[page.ID()] = true
+
+		node := &menuSourceNode{item: generator.pageItem(page)}
+
+		if generator.source.Depth == 0 || level < generator.source.Depth {
+			node.children, err = generator.pageChildren(ctx, page, level+1, visited)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
+
+// pageQuery generates the active pages of the menu's site,
+// matching the alias and template of the source
+func (generator menuSourceGenerator) pageQuery(ctx context.Context) ([]*menuSourceNode, error) {
+	query := PageQuery().
+		SetSiteID(generator.menu.SiteID()).
+		SetStatus(PAGE_STATUS_ACTIVE).
+		SetOrderBy(COLUMN_NAME).
+		SetSortOrder(SORT_ORDER_ASC)
+
+	if generator.source.AliasLike != "" {
+		query.SetAliasLike(generator.source.AliasLike)
+	}
+
+	if generator.source.TemplateID != "" {
+		query.SetTemplateID(generator.source.TemplateID)
+	}
+
+	if generator.source.Limit > 0 {
+		query.SetLimit(generator.source.Limit)
+	}
+
+	pages, err := generator.store.PageList(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []*menuSourceNode{}
+	for _, page := range pages {
+		nodes = append(nodes, &menuSourceNode{item: generator.pageItem(page)})
+	}
+
+	return nodes, nil
+}
+
+// entities generates the custom entities of the type of the source,
+// ordered by their label. Nothing is generated if custom entities are
+// not enabled, or the entity type is not registered.
+func (generator menuSourceGenerator) entities(ctx context.Context) ([]*menuSourceNode, error) {
+	entityStore := generator.store.CustomEntityStore()
+
+	if entityStore == nil {
+		return []*menuSourceNode{}, nil
+	}
+
+	if _, found := entityStore.GetEntityDefinition(generator.source.EntityType); !found {
+		return []*menuSourceNode{}, nil
+	}
+
+	entities, err := entityStore.List(ctx, entitystore.EntityQueryOptions{
+		EntityType: generator.source.EntityType,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	type entityValues struct {
+		id     string
+		label  string
+		values map[string]string
+	}
+
+	list := []entityValues{}
+
+	for _, entity := range entities {
+		values, err := customEntityValues(ctx, entityStore, entity)
+
+		if err != nil {
+			return nil, err
+		}
+
+		label := values[generator.source.LabelAttribute]
+		if generator.source.LabelAttribute == "" || label == "" {
+			label = lo.Ternary(entity.GetHandle() != "", entity.GetHandle(), entity.GetID())
+		}
+
+		list = append(list, entityValues{id: entity.GetID(), label: label, values: values})
+	}
+
+	slices.SortStableFunc(list, func(a, b entityValues) int {
+		return strings.Compare(strings.ToLower(a.label), strings.ToLower(b.label))
+	})
+
+	nodes := []*menuSourceNode{}
+	for _, entity := range list[:generator.limitCount(len(list))] {
+		nodes = append(nodes, &menuSourceNode{
+			item: generator.item(entity.id, entity.label, "", menuSourceURL(generator.source.URLPattern, entity.values)),
+		})
+	}
+
+	return nodes, nil
+}
+
+// taxonomyTerms generates the terms of the taxonomy of the source,
+// nested by their parent term, in their sort order
+func (generator menuSourceGenerator) taxonomyTerms(ctx context.Context) ([]*menuSourceNode, error) {
+	entityStore := generator.store.CustomEntityStore()
+
+	if entityStore == nil {
+		return []*menuSourceNode{}, nil
+	}
+
+	terms, err := entityStore.Inner().TaxonomyTermList(ctx, entitystore.TaxonomyTermQueryOptions{
+		TaxonomyID: generator.source.TaxonomyID,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(terms, func(a, b entitystore.TaxonomyTermInterface) int {
+		return cmp.Or(
+			cmp.Compare(a.GetSortOrder(), b.GetSortOrder()),
+			strings.Compare(strings.ToLower(a.GetName()), strings.ToLower(b.GetName())),
+		)
+	})
+
+	termIDs := map[string]bool{}
+	for _, term := range terms {
+		termIDs[term.GetID()] = true
+	}
+
+	children := map[string][]entitystore.TaxonomyTermInterface{}
+	for _, term := range terms {
+		// Terms with a missing parent are generated at the top level
+		parentID := lo.Ternary(termIDs[term.GetParentID()] && term.GetParentID() != term.GetID(), term.GetParentID(), "")
+		children[parentID] = append(children[parentID], term)
+	}
+
+	var build func(parentID string, level int, visited map[string]bool) []*menuSourceNode
+	build = func(parentID string, level int, visited map[string]bool) []*menuSourceNode {
+		nodes := []*menuSourceNode{}
+
+		for _, term := range children[parentID][:generator.limitCount(len(children[parentID]))] {
+			if visited[term.GetID()] {
+				continue
+			}
+
+			visited[term.GetID()] = true
+
+			node := &menuSourceNode{
+				item: generator.item(term.GetID(), term.GetName(), "", menuSourceURL(generator.source.URLPattern, map[string]string{
+					"id":   term.GetID(),
+					"name": term.GetName(),
+					"slug": term.GetSlug(),
+				})),
+			}
+
+			if generator.source.Depth == 0 || level < generator.source.Depth {
+				node.children = build(term.GetID(), level+1, visited)
+			}
+
+			nodes = append(nodes, node)
+		}
+
+		return nodes
+	}
+
+	return build("", 1, map[string]bool{}), nil
+}
+
+// limitCount returns the number of the elements allowed by the source limit
+func (generator menuSourceGenerator) limitCount(count int) int {
+	if generator.source.Limit > 0 {
+		return min(count, generator.source.Limit)
+	}
+
+	return count
+}
+
+// pageItem returns the generated menu item of the page, linked to
+// the page, so it is resolved like a manual page item
+func (generator menuSourceGenerator) pageItem(page PageInterface) MenuItemInterface {
+	name := page.Name()
+	if name == "" {
+		name = page.Title()
+	}
+
+	return generator.item(page.ID(), name, page.ID(), "")
+}
+
+// item returns a generated, active menu item of the menu
+func (generator menuSourceGenerator) item(id string, name string, pageID string, itemURL string) MenuItemInterface {
+	item := NewMenuItem().
+		SetID(generator.prefix + id).
+		SetMenuID(generator.menu.ID()).
+		SetName(name).
+		SetPageID(pageID).
+		SetURL(itemURL).
+		SetStatus(MENU_ITEM_STATUS_ACTIVE)
+
+	_ = item.SetMeta(MENU_ITEM_META_SOURCE, strconv.Itoa(generator.index))
+
+	return item
+}
+
+// customEntityValues returns the attribute values of the entity,
+// with its ID and handle, for the URL patterns
+func customEntityValues(ctx context.Context, entityStore *CustomEntityStore, entity entitystore.EntityInterface) (map[string]string, error) {
+	attributes, err := entityStore.Inner().AttributeList(ctx, entitystore.AttributeQueryOptions{
+		EntityID: entity.GetID(),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	for _, attribute := range attributes {
+		values[attribute.GetKey()] = attribute.GetValue()
+	}
+
+	// The entity fields take precedence over the attributes
+	values["id"] = entity.GetID()
+	values["handle"] = entity.GetHandle()
+
+	return values, nil
+}
+
+// menuSourceURL replaces the [[name]] placeholders of the URL pattern
+// with the URL escaped values
+func menuSourceURL(pattern string, values map[string]string) string {
+	result := pattern
+
+	for name, value := range values {
+		result = strings.ReplaceAll(result, "[["+name+"]]", url.PathEscape(value))
+	}
+
+	return result
+}
@@ -0,0 +1,310 @@
+package cmsstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/dracory/entitystore"
+	_ "modernc.org/sqlite"
+)
+
+// renderMenuItems returns the names of the items as a tree,
+// i.e. "home,about(team,history)"
+func renderMenuItems(items []MenuItemInterface) string {
+	var render func(parentID string) string
+	render = func(parentID string) string {
+		result := []string{}
+		for _, item := range items {
+			if item.ParentID() != parentID {
+				continue
+			}
+			name := item.Name()
+			if children := render(item.ID()); children != "" {
+				name += "(" + children + ")"
+			}
+			result = append(result, name)
+		}
+		return strings.Join(result, ",")
+	}
+
+	return render("")
+}
+
+func TestStoreMenuItemsResolve_Pages(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	newPage := func(name, alias, parentID string, sequence int, status string) PageInterface {
+		page := NewPage().
+			SetSiteID("SiteMenuSources").
+			SetName(name).
+			SetAlias(alias).
+			SetParentID(parentID).
+			SetSequenceInt(sequence).
+			SetStatus(status)
+
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		return page
+	}
+
+	about := newPage("About", "/about", "", 0, PAGE_STATUS_ACTIVE)
+	newPage("Team", "/about/team", about.ID(), 1, PAGE_STATUS_ACTIVE)
+	history := newPage("History", "/about/history", about.ID(), 0, PAGE_STATUS_ACTIVE)
+	newPage("Founders", "/about/history/founders", history.ID(), 0, PAGE_STATUS_ACTIVE)
+	newPage("Draft", "/about/draft", about.ID(), 2, PAGE_STATUS_DRAFT)
+	newPage("Post B", "/blog/b", "", 1, PAGE_STATUS_ACTIVE)
+	newPage("Post A", "/blog/a", "", 2, PAGE_STATUS_ACTIVE)
+
+	menu := NewMenu().SetSiteID("SiteMenuSources").SetName("Main").SetStatus(MENU_STATUS_ACTIVE)
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	home := NewMenuItem().SetMenuID(menu.ID()).SetName("Home").SetSequenceInt(0).SetStatus(MENU_ITEM_STATUS_ACTIVE)
+	blog := NewMenuItem().SetMenuID(menu.ID()).SetName("Blog").SetSequenceInt(1).SetStatus(MENU_ITEM_STATUS_ACTIVE)
+	contact := NewMenuItem().SetMenuID(menu.ID()).SetName("Contact").SetSequenceInt(2).SetStatus(MENU_ITEM_STATUS_ACTIVE)
+
+	for _, item := range []MenuItemInterface{home, blog, contact} {
+		if err := store.MenuItemCreate(ctx, item); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	// Without sources the stored items are returned
+	items, err := store.MenuItemsResolve(ctx, menu.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := renderMenuItems(items); got != "Home,Blog,Contact" {
+		t.Fatal("Items MUST be Home,Blog,Contact, found:", got)
+	}
+
+	err = SetMenuSources(menu, []MenuSource{
+		{Type: MENU_SOURCE_TYPE_PAGE_CHILDREN, PageID: about.ID()},
+		{Type: MENU_SOURCE_TYPE_PAGE_QUERY, ParentItemID: blog.ID(), AliasLike: "/blog/%"},
+		{Type: MENU_SOURCE_TYPE_PAGE_CHILDREN, PageID: about.ID(), ParentItemID: contact.ID(), Depth: 1, Limit: 1},
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.MenuUpdate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	items, err = store.MenuItemsResolve(ctx, menu.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expected := "Home,Blog(Post A,Post B),Contact(History),History(Founders),Team"
+	if got := renderMenuItems(items); got != expected {
+		t.Fatal("Items MUST be "+expected+", found:", got)
+	}
+
+	for _, item := range items {
+		if item.Name() == "Team" {
+			if !MenuItemIsGenerated(item) {
+				t.Fatal("Team MUST be generated")
+			}
+
+			if item.PageID() == "" || item.MenuID() != menu.ID() || item.Status() != MENU_ITEM_STATUS_ACTIVE {
+				t.Fatal("Generated page item MUST link the page, the menu and be active")
+			}
+		}
+
+		if item.Name() == "Home" && MenuItemIsGenerated(item) {
+			t.Fatal("Home MUST NOT be generated")
+		}
+	}
+
+	// The generated IDs are stable
+	again, err := store.MenuItemsResolve(ctx, menu.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for i := range items {
+		if items[i].ID() != again[i].ID() {
+			t.Fatal("Generated IDs MUST be stable, found:", items[i].ID(), again[i].ID())
+		}
+	}
+}
+
+func TestStoreMenuItemsResolve_EntitiesAndTaxonomy(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:?parseTime=true")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	defer db.Close()
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		BlockTableName:     "block_table",
+		PageTableName:      "page_table",
+		SiteTableName:      "site_table",
+		TemplateTableName:  "template_table",
+		MenusEnabled:       true,
+		MenuTableName:      "menu_table",
+		MenuItemTableName:  "menu_item_table",
+		AutomigrateEnabled: true,
+
+		CustomEntitiesEnabled: true,
+		CustomEntityStoreOptions: CustomEntityStoreOptions{
+			TaxonomiesEnabled: true,
+		},
+		CustomEntityDefinitions: []CustomEntityDefinition{
+			{
+				Type:      "product",
+				TypeLabel: "Product",
+				Attributes: []CustomAttributeDefinition{
+					{Name: "title", Type: "string", Label: "Title", Required: true},
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	for _, title := range []string{"Lamp", "Desk & Chair"} {
+		if _, err := store.CustomEntityStore().Create(ctx, "product", map[string]interface{}{"title": title}, nil, nil); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	taxonomy, err := store.CustomEntityStore().Inner().TaxonomyCreateByOptions(ctx, entitystore.TaxonomyOptions{
+		Name: "Categories",
+		Slug: "categories",
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	newTerm := func(name, slug, parentID string, sortOrder int) entitystore.TaxonomyTermInterface {
+		term, err := store.CustomEntityStore().Inner().TaxonomyTermCreateByOptions(ctx, entitystore.TaxonomyTermOptions{
+			TaxonomyID: taxonomy.ID(),
+			Name:       name,
+			Slug:       slug,
+			ParentID:   parentID,
+			SortOrder:  sortOrder,
+		})
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		return term
+	}
+
+	furniture := newTerm("Furniture", "furniture", "", 1)
+	newTerm("Lighting", "lighting", "", 0)
+	newTerm("Tables", "tables", furniture.ID(), 0)
+
+	menu := NewMenu().SetSiteID("SiteMenuSources").SetName("Shop").SetStatus(MENU_STATUS_ACTIVE)
+
+	err = SetMenuSources(menu, []MenuSource{
+		{Type: MENU_SOURCE_TYPE_ENTITIES, EntityType: "product", LabelAttribute: "title", URLPattern: "/products/[[title]]"},
+		{Type: MENU_SOURCE_TYPE_TAXONOMY, TaxonomyID: taxonomy.ID(), URLPattern: "/category/[[slug]]"},
+		{Type: MENU_SOURCE_TYPE_ENTITIES, EntityType: "unknown", URLPattern: "/unknown/[[id]]"},
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	items, err := store.MenuItemsResolve(ctx, menu.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expected := "Desk & Chair,Lamp,Lighting,Furniture(Tables)"
+	if got := renderMenuItems(items); got != expected {
+		t.Fatal("Items MUST be "+expected+", found:", got)
+	}
+
+	urls := map[string]string{}
+	for _, item := range items {
+		urls[item.Name()] = item.URL()
+	}
+
+	if urls["Desk & Chair"] != "/products/Desk%20&%20Chair" {
+		t.Fatal("URL MUST be escaped, found:", urls["Desk & Chair"])
+	}
+
+	if urls["Tables"] != "/category/tables" {
+		t.Fatal("URL MUST be /category/tables, found:", urls["Tables"])
+	}
+}
+
+func TestMenuSourceValidate(t *testing.T) {
+	invalid := []MenuSource{
+		{Type: "unknown"},
+		{Type: MENU_SOURCE_TYPE_PAGE_CHILDREN},
+		{Type: MENU_SOURCE_TYPE_PAGE_CHILDREN, PageID: "page", Depth: -1},
+		{Type: MENU_SOURCE_TYPE_PAGE_QUERY, Limit: -1},
+		{Type: MENU_SOURCE_TYPE_ENTITIES, EntityType: "product"},
+		{Type: MENU_SOURCE_TYPE_TAXONOMY, URLPattern: "/[[slug]]"},
+	}
+
+	for _, source := range invalid {
+		if err := source.Validate(); err == nil {
+			t.Errorf("Source %+v MUST be invalid", source)
+		}
+	}
+
+	menu := NewMenu()
+
+	if err := SetMenuSources(menu, invalid[:1]); err == nil {
+		t.Fatal("Invalid sources MUST NOT be set")
+	}
+
+	if err := SetMenuSources(menu, []MenuSource{{Type: MENU_SOURCE_TYPE_PAGE_QUERY, AliasLike: "/blog/%"}}); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	sources, err := MenuSources(menu)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(sources) != 1 || sources[0].AliasLike != "/blog/%" {
+		t.Fatal("Sources MUST be stored, found:", len(sources))
+	}
+
+	if err := SetMenuSources(menu, nil); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if menu.Meta(MENU_META_SOURCES) != "" {
+		t.Fatal("Empty sources MUST remove the meta")
+	}
+}
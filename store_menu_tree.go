@@ -0,0 +1,266 @@
+package cmsstore
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/dracory/neat/contracts/database/orm"
+	"github.com/dromara/carbon/v2"
+	"github.com/samber/lo"
+)
+
+// MenuTreeNode is a menu item with its child items, as returned by MenuTree
+type MenuTreeNode struct {
+	MenuItem MenuItemInterface
+	Children []*MenuTreeNode
+}
+
+// MenuTree returns the stored menu items of the menu (of any status) as a
+// tree, the children of each item in sequence order.
+//
+// Items whose parent does not exist (i.e. was deleted) are returned
+// at the top level.
+func (store *storeImplementation) MenuTree(ctx context.Context, menuID string) ([]*MenuTreeNode, error) {
+	if store.neatDB == nil {
+		return []*MenuTreeNode{}, errors.New("menustore: database is nil")
+	}
+
+	if menuID == "" {
+		return []*MenuTreeNode{}, errors.New("menu id is empty")
+	}
+
+	items, err := store.MenuItemList(ctx, MenuItemQuery().
+		SetMenuID(menuID).
+		SetOrderBy(COLUMN_SEQUENCE).
+		SetSortOrder(SORT_ORDER_ASC))
+
+	if err != nil {
+		return []*MenuTreeNode{}, err
+	}
+
+	nodes := map[string]*MenuTreeNode{}
+	for _, item := range items {
+		nodes[item.ID()] = &MenuTreeNode{MenuItem: item, Children: []*MenuTreeNode{}}
+	}
+
+	parentIDs := menuItemParentIDs(items)
+
+	roots := []*MenuTreeNode{}
+	for _, item := range items {
+		node := nodes[item.ID()]
+		parentID, found := parentIDs[item.ID()]
+
+		if !found {
+			roots = append(roots, node)
+			continue
+		}
+
+		nodes[parentID].Children = append(nodes[parentID].Children, node)
+	}
+
+	return roots, nil
+}
+
+// MenuItemMove moves the menu item under the given parent item (empty for
+// the top level), at the given position among its new siblings.
+//
+// The new and the former siblings are renumbered from 0. The items are
+// read, renumbered and updated in a single database transaction, so the
+// menu is never left half reordered. The versions are recorded once
+// committed, as by MenuItemUpdate.
+func (store *storeImplementation) MenuItemMove(ctx context.Context, menuItemID string, parentID string, position int) error {
+	if store.neatDB == nil {
+		return errors.New("menuitemstore: database is nil")
+	}
+
+	if !store.menusEnabled {
+		return errors.New("menus are disabled")
+	}
+
+	if menuItemID == "" {
+		return errors.New("menu item id is empty")
+	}
+
+	return store.withTransaction(ctx, func(txCtx context.Context) error {
+		var changed []MenuItemInterface
+
+		// The items are read and renumbered in the transaction of the
+		// updates, so concurrent moves do not renumber stale siblings
+		err := store.neatDB.Transaction(func(tx orm.Query) error {
+			var err error
+			changed, err = store.menuItemMoveTx(tx, menuItemID, parentID, position)
+			return err
+		})
+
+		if err != nil || len(changed) == 0 {
+			return err
+		}
+
+		store.changeVersions.bump(CHANGE_KIND_MENUS)
+
+		for _, item := range changed {
+			item.MarkAsNotDirty()
+
+			if err := store.versioningTrackEntity(txCtx, VERSIONING_TYPE_MENU_ITEM, item.ID(), item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// menuItemMoveTx moves the menu item in the transaction (see MenuItemMove),
+// returns the moved item and the siblings with a changed sequence
+func (store *storeImplementation) menuItemMoveTx(tx orm.Query, menuItemID string, parentID string, position int) ([]MenuItemInterface, error) {
+	menuItem, err := store.menuItemFindByID(tx, menuItemID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if menuItem == nil {
+		return nil, errors.New("menu item not found")
+	}
+
+	items, err := store.menuItemList(tx, MenuItemQuery().
+		SetMenuID(menuItem.MenuID()).
+		SetOrderBy(COLUMN_SEQUENCE).
+		SetSortOrder(SORT_ORDER_ASC))
+
+	if err != nil {
+		return nil, err
+	}
+
+	itemsByID := map[string]MenuItemInterface{}
+	for _, item := range items {
+		itemsByID[item.ID()] = item
+	}
+
+	// The listed copy is the one renumbered and saved
+	menuItem = itemsByID[menuItem.ID()]
+
+	if menuItem == nil {
+		return nil, errors.New("menu item not found")
+	}
+
+	if parentID != "" {
+		if _, found := itemsByID[parentID]; !found {
+			return nil, errors.New("parent menu item not found in the menu")
+		}
+
+		// Walk up the ancestors of the new parent, to prevent cycles
+		visited := map[string]bool{}
+		for ancestorID := parentID; ancestorID != "" && !visited[ancestorID]; {
+			if ancestorID == menuItem.ID() {
+				return nil, errors.New("menu item cannot be moved into itself or its descendants")
+			}
+
+			visited[ancestorID] = true
+
+			ancestor, found := itemsByID[ancestorID]
+			if !found {
+				break
+			}
+
+			ancestorID = ancestor.ParentID()
+		}
+	}
+
+	// Orphaned items are top level items, as in MenuTree
+	parentIDs := menuItemParentIDs(items)
+	oldParentID := parentIDs[menuItem.ID()]
+
+	siblings := menuItemSiblings(items, parentIDs, parentID)
+
+	siblings = slices.DeleteFunc(siblings, func(sibling MenuItemInterface) bool {
+		return sibling.ID() == menuItem.ID()
+	})
+
+	position = max(0, min(position, len(siblings)))
+	siblings = slices.Insert(siblings, position, menuItem)
+
+	if menuItem.ParentID() != parentID {
+		menuItem.SetParentID(parentID)
+	}
+
+	menuItemResequence(siblings)
+
+	if oldParentID != parentID {
+		parentIDs[menuItem.ID()] = parentID
+		menuItemResequence(menuItemSiblings(items, parentIDs, oldParentID))
+	}
+
+	changed := lo.Filter(items, func(item MenuItemInterface, _ int) bool {
+		return len(item.DataChanged()) > 0
+	})
+
+	updatedAt := carbon.Now(carbon.UTC).ToDateTimeString()
+
+	// Only the parent and the sequence are changed by a move
+	sqlStr := "UPDATE " + store.menuItemTableName + " SET " +
+		COLUMN_PARENT_ID + " = ?, " +
+		COLUMN_SEQUENCE + " = ?, " +
+		COLUMN_UPDATED_AT + " = ? WHERE " +
+		COLUMN_ID + " = ?"
+
+	for _, item := range changed {
+		item.SetUpdatedAt(updatedAt)
+
+		if _, err := tx.Exec(sqlStr, item.ParentID(), item.Sequence(), item.UpdatedAt(), item.ID()); err != nil {
+			return nil, err
+		}
+	}
+
+	return changed, nil
+}
+
+// menuItemParentIDs returns the parent IDs of the menu items whose parent
+// is in the list. Cycles in corrupted data are broken, the first item of
+// a cycle in the list order goes to the top level.
+func menuItemParentIDs(items []MenuItemInterface) map[string]string {
+	itemIDs := map[string]bool{}
+	for _, item := range items {
+		itemIDs[item.ID()] = true
+	}
+
+	parentIDs := map[string]string{}
+	for _, item := range items {
+		if itemIDs[item.ParentID()] {
+			parentIDs[item.ID()] = item.ParentID()
+		}
+	}
+
+	for _, item := range items {
+		visited := map[string]bool{}
+		for id := item.ID(); id != "" && !visited[id]; id = parentIDs[id] {
+			visited[id] = true
+			if parentIDs[id] == item.ID() {
+				delete(parentIDs, item.ID())
+				break
+			}
+		}
+	}
+
+	return parentIDs
+}
+
+// menuItemSiblings returns the menu items with the given parent (empty
+// for the top level, see menuItemParentIDs), in the list order
+func menuItemSiblings(items []MenuItemInterface, parentIDs map[string]string, parentID string) []MenuItemInterface {
+	return lo.Filter(items, func(item MenuItemInterface, _ int) bool {
+		return parentIDs[item.ID()] == parentID
+	})
+}
+
+// menuItemResequence numbers the menu items in their order, from 0,
+// only the changed items are marked as dirty
+func menuItemResequence(items []MenuItemInterface) {
+	for sequence, item := range items {
+		if item.Sequence() != strconv.Itoa(sequence) {
+			item.SetSequenceInt(sequence)
+		}
+	}
+}
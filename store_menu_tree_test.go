@@ -0,0 +1,275 @@
+package cmsstore
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dracory/database"
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+)
+
+// renderMenuTree returns the names of the tree items,
+// i.e. "home,about(team,history)"
+func renderMenuTree(nodes []*MenuTreeNode) string {
+	result := []string{}
+	for _, node := range nodes {
+		name := node.MenuItem.Name()
+		if len(node.Children) > 0 {
+			name += "(" + renderMenuTree(node.Children) + ")"
+		}
+		result = append(result, name)
+	}
+	return strings.Join(result, ",")
+}
+
+func TestStoreMenuTreeAndMove(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	menu := NewMenu().SetSiteID("SiteMenuTree").SetName("Main")
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	newItem := func(name, parentID string, sequence int) MenuItemInterface {
+		item := NewMenuItem().
+			SetMenuID(menu.ID()).
+			SetName(name).
+			SetParentID(parentID).
+			SetSequenceInt(sequence)
+
+		if err := store.MenuItemCreate(ctx, item); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		return item
+	}
+
+	home := newItem("home", "", 0)
+	about := newItem("about", "", 1)
+	team := newItem("team", about.ID(), 0)
+	newItem("history", about.ID(), 1)
+	newItem("orphan", "MISSING", 0)
+
+	tree, err := store.MenuTree(ctx, menu.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := renderMenuTree(tree); got != "home,orphan,about(team,history)" {
+		t.Errorf("expected %q, got %q", "home,orphan,about(team,history)", got)
+	}
+
+	// Move into another parent, at the end
+	if err := store.MenuItemMove(ctx, home.ID(), about.ID(), 99); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	tree, err = store.MenuTree(ctx, menu.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := renderMenuTree(tree); got != "orphan,about(team,history,home)" {
+		t.Errorf("expected %q, got %q", "orphan,about(team,history,home)", got)
+	}
+
+	// Reorder among the siblings
+	if err := store.MenuItemMove(ctx, home.ID(), about.ID(), 0); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	items, err := store.MenuItemList(ctx, MenuItemQuery().SetMenuID(menu.ID()).SetOrderBy(COLUMN_SEQUENCE).SetSortOrder(SORT_ORDER_ASC))
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	sequences := []string{}
+	for _, item := range items {
+		if item.ParentID() == about.ID() {
+			sequences = append(sequences, item.Name()+":"+item.Sequence())
+		}
+	}
+
+	if got := strings.Join(sequences, ","); got != "home:0,team:1,history:2" {
+		t.Errorf("expected the siblings renumbered, got %q", got)
+	}
+
+	// Moving into a descendant is refused
+	if err := store.MenuItemMove(ctx, about.ID(), team.ID(), 0); err == nil {
+		t.Error("expected an error moving an item into its descendant")
+	}
+
+	if err := store.MenuItemMove(ctx, about.ID(), "MISSING", 0); err == nil {
+		t.Error("expected an error moving an item into a missing parent")
+	}
+
+	if err := store.MenuItemMove(ctx, "MISSING", "", 0); err == nil {
+		t.Error("expected an error moving a missing item")
+	}
+}
+
+func TestStoreMenuItemMove_Versioning(t *testing.T) {
+	db := initDB(":memory:")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                  db,
+		BlockTableName:      "block_table_menu_move_versioning",
+		PageTableName:       "page_table_menu_move_versioning",
+		SiteTableName:       "site_table_menu_move_versioning",
+		TemplateTableName:   "template_table_menu_move_versioning",
+		MenusEnabled:        true,
+		MenuTableName:       "menu_table_menu_move_versioning",
+		MenuItemTableName:   "menu_item_table_menu_move_versioning",
+		VersioningEnabled:   true,
+		VersioningTableName: "version_table_menu_move_versioning",
+		AutomigrateEnabled:  true,
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// A caller's transaction context, the versions must still be recorded
+	ctx := database.NewQueryableContext(context.Background(), db)
+
+	menu := NewMenu().SetSiteID("SiteMenuMoveVersioning").SetName("Main")
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	items := []MenuItemInterface{}
+	for i, name := range []string{"home", "about", "contact"} {
+		item := NewMenuItem().
+			SetMenuID(menu.ID()).
+			SetName(name).
+			SetSequenceInt(i)
+
+		if err := store.MenuItemCreate(ctx, item); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		items = append(items, item)
+	}
+
+	if err := store.MenuItemMove(ctx, items[2].ID(), "", 0); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for i, item := range items {
+		versions, err := store.VersioningList(context.Background(), NewVersioningQuery().
+			SetEntityType(VERSIONING_TYPE_MENU_ITEM).
+			SetEntityID(item.ID()))
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		expected := `"sequence":"` + strconv.Itoa((i+1)%3) + `"`
+
+		found := slices.ContainsFunc(versions, func(version VersioningInterface) bool {
+			return strings.Contains(version.Content(), expected)
+		})
+
+		if !found {
+			t.Fatalf("Menu item %s MUST have a version with %s, found: %d versions", item.Name(), expected, len(versions))
+		}
+	}
+}
+
+func TestStoreMenuItemMove_ReadsInTransaction(t *testing.T) {
+	db := initDB(":memory:")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		BlockTableName:     "block_table_menu_move_tx",
+		PageTableName:      "page_table_menu_move_tx",
+		SiteTableName:      "site_table_menu_move_tx",
+		TemplateTableName:  "template_table_menu_move_tx",
+		MenusEnabled:       true,
+		MenuTableName:      "menu_table_menu_move_tx",
+		MenuItemTableName:  "menu_item_table_menu_move_tx",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	menu := NewMenu().SetSiteID("SiteMenuMoveTx").SetName("Main")
+
+	if err := store.MenuCreate(ctx, menu); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	items := []MenuItemInterface{}
+	for i, name := range []string{"home", "about", "contact"} {
+		item := NewMenuItem().
+			SetMenuID(menu.ID()).
+			SetName(name).
+			SetSequenceInt(i)
+
+		if err := store.MenuItemCreate(ctx, item); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		items = append(items, item)
+	}
+
+	// The database has a single connection, held by the transaction, the
+	// move would not complete if the items were read outside of it
+	done := make(chan error, 1)
+	go func() {
+		done <- store.(*storeImplementation).neatDB.Transaction(func(tx contractsorm.Query) error {
+			changed, err := store.(*storeImplementation).menuItemMoveTx(tx, items[2].ID(), "", 0)
+
+			if err != nil {
+				return err
+			}
+
+			if len(changed) != 3 {
+				t.Errorf("Changed menu items MUST be 3, found: %d", len(changed))
+			}
+
+			return errors.New("rollback")
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || err.Error() != "rollback" {
+			t.Fatal("unexpected error:", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Move MUST read the menu items in the transaction")
+	}
+
+	for i, item := range items {
+		found, err := store.MenuItemFindByID(ctx, item.ID())
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if found.SequenceInt() != i {
+			t.Fatalf("Sequence of %s MUST be rolled back to %d, found: %d", found.Name(), i, found.SequenceInt())
+		}
+	}
+}
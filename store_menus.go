@@ -68,6 +68,8 @@ func (store *storeImplementation) MenuCreate(ctx context.Context, menu MenuInter
 
 		menu.MarkAsNotDirty()
 
+		store.changeVersions.bump(CHANGE_KIND_MENUS)
+
 		return store.versioningTrackEntity(txCtx, VERSIONING_TYPE_MENU, menu.ID(), menu)
 	})
 }
@@ -105,6 +107,10 @@ func (store *storeImplementation) MenuDeleteByID(ctx context.Context, id string)
 
 	_, err := store.neatDB.Query().Table(store.menuTableName).Where("id = ?", id).Delete()
 
+	if err == nil {
+		store.changeVersions.bump(CHANGE_KIND_MENUS)
+	}
+
 	return err
 }
 
@@ -290,6 +296,8 @@ func (store *storeImplementation) MenuUpdate(ctx context.Context, menu MenuInter
 
 		menu.MarkAsNotDirty()
 
+		store.changeVersions.bump(CHANGE_KIND_MENUS)
+
 		return store.versioningTrackEntity(txCtx, VERSIONING_TYPE_MENU, menu.ID(), menu)
 	})
 }
@@ -89,6 +89,17 @@ type NewStoreOptions struct {
 
 	// MediaTableName is the name of the media database table to be created/used
 	MediaTableName string
+
+	// FormsEnabled enables the storage of the form submissions
+	FormsEnabled bool
+
+	// FormSubmissionTableName is the name of the form submission database table to be created/used
+	FormSubmissionTableName string
+
+	// BlockTypeRegistry is the registry of the block types used by this store,
+	// and by the frontend and admin created for it.
+	// If not set, the default global registry is used
+	BlockTypeRegistry *BlockTypeRegistry
 }
 
 // NewStore creates a new CMS store based on the provided options.
@@ -121,6 +132,9 @@ func NewStore(opts NewStoreOptions) (StoreInterface, error) {
 	if opts.MediaEnabled && opts.MediaTableName == "" {
 		return nil, errors.New("cms store: MediaTableName is required")
 	}
+	if opts.FormsEnabled && opts.FormSubmissionTableName == "" {
+		return nil, errors.New("cms store: FormSubmissionTableName is required")
+	}
 
 	// Validate database connection
 	if opts.DB == nil {
@@ -191,8 +205,19 @@ func NewStore(opts NewStoreOptions) (StoreInterface, error) {
 		mediaEnabled:   opts.MediaEnabled,
 		mediaTableName: opts.MediaTableName,
 
+		formsEnabled:            opts.FormsEnabled,
+		formSubmissionTableName: opts.FormSubmissionTableName,
+
 		shortcodes:  opts.Shortcodes,
 		middlewares: opts.Middlewares,
+
+		blockTypeRegistry: opts.BlockTypeRegistry,
+
+		changeVersions: newChangeVersions(),
+	}
+
+	if customEntityStore != nil {
+		customEntityStore.changeVersions = store.changeVersions
 	}
 
 	// Perform automatic migration if enabled
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log"
+	"strconv"
 	"strings"
 
 	contractsorm "github.com/dracory/neat/contracts/database/orm"
@@ -57,6 +58,8 @@ func (store *storeImplementation) PageCreate(ctx context.Context, page PageInter
 
 		page.MarkAsNotDirty()
 
+		store.changeVersions.bump(CHANGE_KIND_PAGES)
+
 		return store.versioningTrackEntity(txCtx, VERSIONING_TYPE_PAGE, page.ID(), page)
 	})
 }
@@ -88,6 +91,10 @@ func (store *storeImplementation) PageDeleteByID(ctx context.Context, id string)
 
 	_, err := store.neatDB.Query().Table(store.pageTableName).Where("id = ?", id).Delete()
 
+	if err == nil {
+		store.changeVersions.bump(CHANGE_KIND_PAGES)
+	}
+
 	return err
 }
 
@@ -167,6 +174,8 @@ func (store *storeImplementation) PageList(ctx context.Context, query PageQueryI
 		SiteID            string `db:"site_id"`
 		TemplateID        string `db:"template_id"`
 		Name              string `db:"name"`
+		ParentID          string `db:"parent_id"`
+		Sequence          int    `db:"sequence"`
 		Handle            string `db:"handle"`
 		Alias             string `db:"alias"`
 		Status            string `db:"status"`
@@ -198,6 +207,8 @@ func (store *storeImplementation) PageList(ctx context.Context, query PageQueryI
 			"site_id":            r.SiteID,
 			"template_id":        r.TemplateID,
 			"name":               r.Name,
+			"parent_id":          r.ParentID,
+			"sequence":           strconv.Itoa(r.Sequence),
 			"handle":             r.Handle,
 			"alias":              r.Alias,
 			"status":             r.Status,
@@ -282,6 +293,8 @@ func (store *storeImplementation) PageUpdate(ctx context.Context, page PageInter
 
 		page.MarkAsNotDirty()
 
+		store.changeVersions.bump(CHANGE_KIND_PAGES)
+
 		return store.versioningTrackEntity(txCtx, VERSIONING_TYPE_PAGE, page.ID(), page)
 	})
 }
@@ -338,6 +351,10 @@ func (store *storeImplementation) pageSelectQuery(options PageQueryInterface) (q
 		}
 	}
 
+	if options.HasParentID() {
+		q = q.Where(COLUMN_PARENT_ID+" = ?", options.ParentID())
+	}
+
 	if options.HasSiteID() {
 		q = q.Where(COLUMN_SITE_ID+" = ?", options.SiteID())
 	}
@@ -0,0 +1,415 @@
+package cmsstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// SEO audit severities
+const (
+	SEO_AUDIT_SEVERITY_ERROR   = "error"
+	SEO_AUDIT_SEVERITY_WARNING = "warning"
+)
+
+// SEO audit checks, the Check of the issues
+const (
+	SEO_AUDIT_CHECK_CANONICAL   = "canonical"
+	SEO_AUDIT_CHECK_DESCRIPTION = "description"
+	SEO_AUDIT_CHECK_HEADINGS    = "headings"
+	SEO_AUDIT_CHECK_IMAGE_ALT   = "image_alt"
+	SEO_AUDIT_CHECK_LINKS       = "links"
+	SEO_AUDIT_CHECK_ROBOTS      = "robots"
+	SEO_AUDIT_CHECK_TITLE       = "title"
+)
+
+// Recommended lengths (in characters) of the title and the meta
+// description, as displayed by the search engines
+const (
+	SEO_AUDIT_TITLE_MIN_LENGTH       = 30
+	SEO_AUDIT_TITLE_MAX_LENGTH       = 60
+	SEO_AUDIT_DESCRIPTION_MIN_LENGTH = 70
+	SEO_AUDIT_DESCRIPTION_MAX_LENGTH = 160
+)
+
+// SeoAuditIssue is an issue found by the SEO audit of a page
+type SeoAuditIssue struct {
+	// Check is the check that found the issue, i.e. SEO_AUDIT_CHECK_TITLE
+	Check string `json:"check"`
+
+	// Severity is SEO_AUDIT_SEVERITY_ERROR or SEO_AUDIT_SEVERITY_WARNING
+	Severity string `json:"severity"`
+
+	// Message is the human-readable description of the issue
+	Message string `json:"message"`
+}
+
+// SeoAuditReport is the SEO audit of a page, as returned by SiteSeoAudit
+type SeoAuditReport struct {
+	Page   PageInterface
+	Issues []SeoAuditIssue
+}
+
+// Matches the page IDs of the [[PAGE_URL_id]] links
+var seoAuditPageURLPattern = regexp.MustCompile(`\[\[PAGE_URL_([a-zA-Z0-9_-]+)\]\]`)
+
+// Matches the image tags, and their alt attribute
+var seoAuditImagePattern = regexp.MustCompile(`(?is)<img\b[^>]*>`)
+var seoAuditAltPattern = regexp.MustCompile(`(?is)\salt\s*=`)
+
+// Matches the level of the heading tags
+var seoAuditHeadingPattern = regexp.MustCompile(`(?i)<h([1-6])[\s>]`)
+
+// Matches the robots meta tags, and their content
+var seoAuditRobotsMetaPattern = regexp.MustCompile(`(?is)<meta\b[^>]*\bname\s*=\s*["']?robots["']?[^>]*>`)
+var seoAuditContentPattern = regexp.MustCompile(`(?is)\bcontent\s*=\s*["']([^"']*)["']`)
+
+// PageSeoAudit audits the SEO of the page:
+//   - the title and meta description lengths, and their duplicates
+//     among the pages of the site
+//   - the canonical URL
+//   - the alt text of the images, and the heading structure
+//   - the [[PAGE_URL_id]] links to missing pages
+//   - the noindex conflicts
+//
+// The html is the rendered page, the page content is audited if empty.
+// The page content is usually an HTML fragment, the missing H1 is only
+// reported for full documents, as the H1 may be in the template.
+func (store *storeImplementation) PageSeoAudit(ctx context.Context, page PageInterface, html string) ([]SeoAuditIssue, error) {
+	if page == nil {
+		return []SeoAuditIssue{}, errors.New("page is nil")
+	}
+
+	sitePages, err := store.seoAuditSitePages(ctx, page.SiteID())
+
+	if err != nil {
+		return []SeoAuditIssue{}, err
+	}
+
+	if html == "" {
+		html = page.Content()
+	}
+
+	return store.seoAuditPage(ctx, page, html, sitePages)
+}
+
+// SiteSeoAudit audits the SEO of the pages of the site (see PageSeoAudit),
+// with their content, ordered by name
+func (store *storeImplementation) SiteSeoAudit(ctx context.Context, siteID string) ([]SeoAuditReport, error) {
+	if siteID == "" {
+		return []SeoAuditReport{}, errors.New("site id is empty")
+	}
+
+	sitePages, err := store.seoAuditSitePages(ctx, siteID)
+
+	if err != nil {
+		return []SeoAuditReport{}, err
+	}
+
+	reports := []SeoAuditReport{}
+
+	for _, page := range sitePages {
+		issues, err := store.seoAuditPage(ctx, page, page.Content(), sitePages)
+
+		if err != nil {
+			return []SeoAuditReport{}, err
+		}
+
+		reports = append(reports, SeoAuditReport{Page: page, Issues: issues})
+	}
+
+	return reports, nil
+}
+
+// seoAuditSitePages returns the pages of the site, ordered by name
+func (store *storeImplementation) seoAuditSitePages(ctx context.Context, siteID string) ([]PageInterface, error) {
+	if store.neatDB == nil {
+		return []PageInterface{}, errors.New("pagestore: database is nil")
+	}
+
+	return store.PageList(ctx, PageQuery().
+		SetSiteID(siteID).
+		SetOrderBy(COLUMN_NAME).
+		SetSortOrder(SORT_ORDER_ASC))
+}
+
+// seoAuditPage audits the page, the site pages are used to find the
+// duplicates and the links to missing pages
+func (store *storeImplementation) seoAuditPage(ctx context.Context, page PageInterface, html string, sitePages []PageInterface) ([]SeoAuditIssue, error) {
+	issues := []SeoAuditIssue{}
+
+	issues = append(issues, seoAuditLength(SEO_AUDIT_CHECK_TITLE, "Title", page.Title(), SEO_AUDIT_TITLE_MIN_LENGTH, SEO_AUDIT_TITLE_MAX_LENGTH, SEO_AUDIT_SEVERITY_ERROR)...)
+	issues = append(issues, seoAuditLength(SEO_AUDIT_CHECK_DESCRIPTION, "Meta description", page.MetaDescription(), SEO_AUDIT_DESCRIPTION_MIN_LENGTH, SEO_AUDIT_DESCRIPTION_MAX_LENGTH, SEO_AUDIT_SEVERITY_WARNING)...)
+	issues = append(issues, seoAuditDuplicates(page, sitePages)...)
+
+	if strings.TrimSpace(page.CanonicalUrl()) == "" {
+		issues = append(issues, SeoAuditIssue{
+			Check:    SEO_AUDIT_CHECK_CANONICAL,
+			Severity: SEO_AUDIT_SEVERITY_WARNING,
+			Message:  "Canonical URL is missing",
+		})
+	}
+
+	issues = append(issues, seoAuditImages(html)...)
+	issues = append(issues, seoAuditHeadings(html)...)
+
+	linkIssues, err := store.seoAuditLinks(ctx, html, sitePages)
+
+	if err != nil {
+		return []SeoAuditIssue{}, err
+	}
+
+	issues = append(issues, linkIssues...)
+	issues = append(issues, seoAuditRobots(page, html)...)
+
+	return issues, nil
+}
+
+// seoAuditLength checks the value is set, and its length is within the
+// recommended range
+func seoAuditLength(check, label, value string, minLength, maxLength int, missingSeverity string) []SeoAuditIssue {
+	length := utf8.RuneCountInString(strings.TrimSpace(value))
+
+	if length == 0 {
+		return []SeoAuditIssue{{Check: check, Severity: missingSeverity, Message: label + " is missing"}}
+	}
+
+	if length < minLength {
+		return []SeoAuditIssue{{
+			Check:    check,
+			Severity: SEO_AUDIT_SEVERITY_WARNING,
+			Message:  fmt.Sprintf("%s is too short (%d characters, recommended %d-%d)", label, length, minLength, maxLength),
+		}}
+	}
+
+	if length > maxLength {
+		return []SeoAuditIssue{{
+			Check:    check,
+			Severity: SEO_AUDIT_SEVERITY_WARNING,
+			Message:  fmt.Sprintf("%s is too long (%d characters, recommended %d-%d)", label, length, minLength, maxLength),
+		}}
+	}
+
+	return []SeoAuditIssue{}
+}
+
+// seoAuditDuplicates checks the title and the meta description are not
+// used by another page of the site (case insensitive)
+func seoAuditDuplicates(page PageInterface, sitePages []PageInterface) []SeoAuditIssue {
+	issues := []SeoAuditIssue{}
+
+	fields := []struct {
+		check string
+		label string
+		value func(PageInterface) string
+	}{
+		{SEO_AUDIT_CHECK_TITLE, "Title", PageInterface.Title},
+		{SEO_AUDIT_CHECK_DESCRIPTION, "Meta description", PageInterface.MetaDescription},
+	}
+
+	for _, field := range fields {
+		value := strings.TrimSpace(field.value(page))
+
+		if value == "" {
+			continue
+		}
+
+		duplicates := []string{}
+		for _, sitePage := range sitePages {
+			if sitePage.ID() != page.ID() && strings.EqualFold(strings.TrimSpace(field.value(sitePage)), value) {
+				duplicates = append(duplicates, sitePage.Name())
+			}
+		}
+
+		if len(duplicates) > 0 {
+			issues = append(issues, SeoAuditIssue{
+				Check:    field.check,
+				Severity: SEO_AUDIT_SEVERITY_WARNING,
+				Message:  field.label + " is also used by: " + strings.Join(duplicates, ", "),
+			})
+		}
+	}
+
+	return issues
+}
+
+// seoAuditImages checks the images have an alt attribute, an empty
+// alt text (a decorative image) is valid
+func seoAuditImages(html string) []SeoAuditIssue {
+	missing := 0
+
+	for _, image := range seoAuditImagePattern.FindAllString(html, -1) {
+		if !seoAuditAltPattern.MatchString(image) {
+			missing++
+		}
+	}
+
+	if missing == 0 {
+		return []SeoAuditIssue{}
+	}
+
+	return []SeoAuditIssue{{
+		Check:    SEO_AUDIT_CHECK_IMAGE_ALT,
+		Severity: SEO_AUDIT_SEVERITY_WARNING,
+		Message:  fmt.Sprintf("%d image(s) without alt text", missing),
+	}}
+}
+
+// seoAuditHeadings checks there is a single H1, and the heading levels
+// are not skipped (i.e. an H4 after an H2)
+func seoAuditHeadings(html string) []SeoAuditIssue {
+	issues := []SeoAuditIssue{}
+
+	h1Count := 0
+	previousLevel := 0
+	skipped := []string{}
+
+	for _, match := range seoAuditHeadingPattern.FindAllStringSubmatch(html, -1) {
+		level := int(match[1][0] - '0')
+
+		if level == 1 {
+			h1Count++
+		}
+
+		if previousLevel > 0 && level > previousLevel+1 {
+			skipped = append(skipped, fmt.Sprintf("H%d after H%d", level, previousLevel))
+		}
+
+		previousLevel = level
+	}
+
+	if h1Count == 0 && strings.Contains(strings.ToLower(html), "<body") {
+		issues = append(issues, SeoAuditIssue{
+			Check:    SEO_AUDIT_CHECK_HEADINGS,
+			Severity: SEO_AUDIT_SEVERITY_WARNING,
+			Message:  "H1 heading is missing",
+		})
+	}
+
+	if h1Count > 1 {
+		issues = append(issues, SeoAuditIssue{
+			Check:    SEO_AUDIT_CHECK_HEADINGS,
+			Severity: SEO_AUDIT_SEVERITY_WARNING,
+			Message:  fmt.Sprintf("%d H1 headings, a single H1 is recommended", h1Count),
+		})
+	}
+
+	if len(skipped) > 0 {
+		issues = append(issues, SeoAuditIssue{
+			Check:    SEO_AUDIT_CHECK_HEADINGS,
+			Severity: SEO_AUDIT_SEVERITY_WARNING,
+			Message:  "Heading levels are skipped: " + strings.Join(slices.Compact(skipped), ", "),
+		})
+	}
+
+	return issues
+}
+
+// seoAuditLinks checks the [[PAGE_URL_id]] links point to existing pages,
+// the pages of other sites are looked up in the store
+func (store *storeImplementation) seoAuditLinks(ctx context.Context, html string, sitePages []PageInterface) ([]SeoAuditIssue, error) {
+	existing := map[string]bool{}
+	for _, sitePage := range sitePages {
+		existing[sitePage.ID()] = true
+	}
+
+	broken := []string{}
+
+	for _, match := range seoAuditPageURLPattern.FindAllStringSubmatch(html, -1) {
+		pageID := match[1]
+
+		if existing[pageID] || slices.Contains(broken, pageID) {
+			continue
+		}
+
+		linkedPage, err := store.PageFindByID(ctx, pageID)
+
+		if err != nil {
+			return []SeoAuditIssue{}, err
+		}
+
+		if linkedPage != nil {
+			existing[pageID] = true
+			continue
+		}
+
+		broken = append(broken, pageID)
+	}
+
+	if len(broken) == 0 {
+		return []SeoAuditIssue{}, nil
+	}
+
+	return []SeoAuditIssue{{
+		Check:    SEO_AUDIT_CHECK_LINKS,
+		Severity: SEO_AUDIT_SEVERITY_ERROR,
+		Message:  "Links to missing pages: " + strings.Join(broken, ", "),
+	}}, nil
+}
+
+// seoAuditRobots checks the robots directives do not conflict:
+//   - index and noindex in the same directives
+//   - noindex with a canonical URL
+//   - the robots meta tags of the HTML (i.e. set by the template)
+//     disagreeing with the meta robots of the page on noindex
+func seoAuditRobots(page PageInterface, html string) []SeoAuditIssue {
+	issues := []SeoAuditIssue{}
+
+	directives := seoAuditRobotsDirectives(page.MetaRobots())
+	noindex := slices.Contains(directives, "noindex") || slices.Contains(directives, "none")
+
+	if noindex && slices.Contains(directives, "index") {
+		issues = append(issues, SeoAuditIssue{
+			Check:    SEO_AUDIT_CHECK_ROBOTS,
+			Severity: SEO_AUDIT_SEVERITY_ERROR,
+			Message:  "Meta robots contains both index and noindex",
+		})
+	}
+
+	if noindex && strings.TrimSpace(page.CanonicalUrl()) != "" {
+		issues = append(issues, SeoAuditIssue{
+			Check:    SEO_AUDIT_CHECK_ROBOTS,
+			Severity: SEO_AUDIT_SEVERITY_WARNING,
+			Message:  "Page is noindex but has a canonical URL, which sends conflicting signals",
+		})
+	}
+
+	for _, metaTag := range seoAuditRobotsMetaPattern.FindAllString(html, -1) {
+		content := seoAuditContentPattern.FindStringSubmatch(metaTag)
+
+		if content == nil {
+			continue
+		}
+
+		htmlDirectives := seoAuditRobotsDirectives(content[1])
+		htmlNoindex := slices.Contains(htmlDirectives, "noindex") || slices.Contains(htmlDirectives, "none")
+
+		if htmlNoindex != noindex {
+			issues = append(issues, SeoAuditIssue{
+				Check:    SEO_AUDIT_CHECK_ROBOTS,
+				Severity: SEO_AUDIT_SEVERITY_ERROR,
+				Message:  "Robots meta tag \"" + content[1] + "\" conflicts with the page meta robots \"" + page.MetaRobots() + "\"",
+			})
+			break
+		}
+	}
+
+	return issues
+}
+
+// seoAuditRobotsDirectives returns the lower case robots directives
+func seoAuditRobotsDirectives(robots string) []string {
+	directives := []string{}
+
+	for _, directive := range strings.Split(robots, ",") {
+		if directive = strings.ToLower(strings.TrimSpace(directive)); directive != "" {
+			directives = append(directives, directive)
+		}
+	}
+
+	return directives
+}
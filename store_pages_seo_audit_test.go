@@ -0,0 +1,134 @@
+package cmsstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// seoAuditTestMessages returns the messages of the issues of the check
+func seoAuditTestMessages(issues []SeoAuditIssue, check string) string {
+	messages := []string{}
+	for _, issue := range issues {
+		if issue.Check == check {
+			messages = append(messages, issue.Severity+": "+issue.Message)
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+func TestStorePageSeoAudit(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	newPage := func(name, title, description, content string) PageInterface {
+		page := NewPage().
+			SetSiteID("SiteSeoAudit").
+			SetName(name).
+			SetTitle(title).
+			SetMetaDescription(description).
+			SetContent(content)
+
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		return page
+	}
+
+	description := strings.Repeat("A meta description of the right length. ", 2)
+
+	about := newPage("About", "About our company and the people behind it", description, "")
+	page := newPage("Team", "About our company and the people behind it", "Too short",
+		`<h1>Team</h1><h1>Again</h1><h4>Skipped</h4>`+
+			`<img src="/a.png"><img src="/b.png" alt=""><IMG SRC="/c.png">`+
+			`<a href="[[PAGE_URL_`+about.ID()+`]]">About</a><a href="[[PAGE_URL_MISSING]]">Gone</a>`)
+
+	page.SetMetaRobots("index, noindex").SetCanonicalUrl("https://example.com/team")
+
+	issues, err := store.PageSeoAudit(ctx, page, "")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expected := map[string]string{
+		SEO_AUDIT_CHECK_TITLE:       "warning: Title is also used by: About",
+		SEO_AUDIT_CHECK_DESCRIPTION: "warning: Meta description is too short (9 characters, recommended 70-160)",
+		SEO_AUDIT_CHECK_CANONICAL:   "",
+		SEO_AUDIT_CHECK_IMAGE_ALT:   "warning: 2 image(s) without alt text",
+		SEO_AUDIT_CHECK_HEADINGS:    "warning: 2 H1 headings, a single H1 is recommended; warning: Heading levels are skipped: H4 after H1",
+		SEO_AUDIT_CHECK_LINKS:       "error: Links to missing pages: MISSING",
+		SEO_AUDIT_CHECK_ROBOTS:      "error: Meta robots contains both index and noindex; warning: Page is noindex but has a canonical URL, which sends conflicting signals",
+	}
+
+	for check, messages := range expected {
+		if got := seoAuditTestMessages(issues, check); got != messages {
+			t.Errorf("check %s: expected %q, got %q", check, messages, got)
+		}
+	}
+
+	// The rendered HTML is audited instead of the content
+	issues, err = store.PageSeoAudit(ctx, about, `<html><body><meta name="robots" content="noindex"><h2>Title</h2></body></html>`)
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := seoAuditTestMessages(issues, SEO_AUDIT_CHECK_HEADINGS); got != "warning: H1 heading is missing" {
+		t.Errorf("expected the missing H1, got %q", got)
+	}
+
+	if got := seoAuditTestMessages(issues, SEO_AUDIT_CHECK_ROBOTS); !strings.Contains(got, `Robots meta tag "noindex" conflicts`) {
+		t.Errorf("expected the robots meta tag conflict, got %q", got)
+	}
+
+	if got := seoAuditTestMessages(issues, SEO_AUDIT_CHECK_CANONICAL); got != "warning: Canonical URL is missing" {
+		t.Errorf("expected the missing canonical URL, got %q", got)
+	}
+}
+
+func TestStoreSiteSeoAudit(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	for _, name := range []string{"Home", "Contact"} {
+		page := NewPage().SetSiteID("SiteSeoAuditReport").SetName(name)
+
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	if err := store.PageCreate(ctx, NewPage().SetSiteID("OtherSite").SetName("Other")); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	reports, err := store.SiteSeoAudit(ctx, "SiteSeoAuditReport")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(reports) != 2 || reports[0].Page.Name() != "Contact" || reports[1].Page.Name() != "Home" {
+		t.Fatalf("expected the 2 pages of the site by name, got %v", reports)
+	}
+
+	if got := seoAuditTestMessages(reports[0].Issues, SEO_AUDIT_CHECK_TITLE); got != "error: Title is missing" {
+		t.Errorf("expected the missing title, got %q", got)
+	}
+
+	if _, err := store.SiteSeoAudit(ctx, ""); err == nil {
+		t.Error("expected an error for an empty site id")
+	}
+}
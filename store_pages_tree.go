@@ -0,0 +1,420 @@
+package cmsstore
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PageTreeNode is a page with its child pages, as returned by PageTree
+type PageTreeNode struct {
+	Page     PageInterface
+	Children []*PageTreeNode
+}
+
+// PageTree returns the pages of the site as a tree, the children of
+// each page in sequence order.
+//
+// Pages whose parent does not exist (i.e. was deleted, or belongs
+// to another site) are returned at the top level.
+func (store *storeImplementation) PageTree(ctx context.Context, siteID string) ([]*PageTreeNode, error) {
+	if store.neatDB == nil {
+		return []*PageTreeNode{}, errors.New("pagestore: database is nil")
+	}
+
+	if siteID == "" {
+		return []*PageTreeNode{}, errors.New("site id is empty")
+	}
+
+	pages, err := store.PageList(ctx, PageQuery().
+		SetSiteID(siteID).
+		SetOrderBy(COLUMN_SEQUENCE).
+		SetSortOrder(SORT_ORDER_ASC))
+
+	if err != nil {
+		return []*PageTreeNode{}, err
+	}
+
+	nodes := map[string]*PageTreeNode{}
+	for _, page := range pages {
+		nodes[page.ID()] = &PageTreeNode{Page: page, Children: []*PageTreeNode{}}
+	}
+
+	parentIDs := map[string]string{}
+	for _, page := range pages {
+		if _, found := nodes[page.ParentID()]; found {
+			parentIDs[page.ID()] = page.ParentID()
+		}
+	}
+
+	// Break cycles in corrupted data, the first page of a cycle
+	// found in sequence order goes to the top level
+	for _, page := range pages {
+		visited := map[string]bool{}
+		for id := page.ID(); id != "" && !visited[id]; id = parentIDs[id] {
+			visited[id] = true
+			if parentIDs[id] == page.ID() {
+				delete(parentIDs, page.ID())
+				break
+			}
+		}
+	}
+
+	roots := []*PageTreeNode{}
+	for _, page := range pages {
+		node := nodes[page.ID()]
+		parentID, found := parentIDs[page.ID()]
+
+		if !found {
+			roots = append(roots, node)
+			continue
+		}
+
+		nodes[parentID].Children = append(nodes[parentID].Children, node)
+	}
+
+	return roots, nil
+}
+
+// PageChildren returns the direct child pages of the page,
+// in sequence order
+func (store *storeImplementation) PageChildren(ctx context.Context, pageID string) ([]PageInterface, error) {
+	if store.neatDB == nil {
+		return []PageInterface{}, errors.New("pagestore: database is nil")
+	}
+
+	if pageID == "" {
+		return []PageInterface{}, errors.New("page id is empty")
+	}
+
+	page, err := store.PageFindByID(ctx, pageID)
+
+	if err != nil {
+		return []PageInterface{}, err
+	}
+
+	if page == nil {
+		return []PageInterface{}, errors.New("page not found")
+	}
+
+	return store.pageSiblings(ctx, page.SiteID(), page.ID())
+}
+
+// PageAncestors returns the ancestors of the page, from the root page
+// down to the direct parent. The page itself is not included.
+func (store *storeImplementation) PageAncestors(ctx context.Context, pageID string) ([]PageInterface, error) {
+	if store.neatDB == nil {
+		return []PageInterface{}, errors.New("pagestore: database is nil")
+	}
+
+	if pageID == "" {
+		return []PageInterface{}, errors.New("page id is empty")
+	}
+
+	page, err := store.PageFindByID(ctx, pageID)
+
+	if err != nil {
+		return []PageInterface{}, err
+	}
+
+	if page == nil {
+		return []PageInterface{}, errors.New("page not found")
+	}
+
+	ancestors := []PageInterface{}
+
+	// The visited pages guard against cycles in corrupted data
+	visited := map[string]bool{page.ID(): true}
+	for parentID := page.ParentID(); parentID != ""; {
+		parent, err := store.PageFindByID(ctx, parentID)
+
+		if err != nil {
+			return []PageInterface{}, err
+		}
+
+		if parent == nil || visited[parent.ID()] || parent.SiteID() != page.SiteID() {
+			break
+		}
+
+		visited[parent.ID()] = true
+		ancestors = append(ancestors, parent)
+		parentID = parent.ParentID()
+	}
+
+	slices.Reverse(ancestors)
+
+	return ancestors, nil
+}
+
+// PageMove moves the page under the given parent (empty for the top
+// level), at the given position among its new siblings. The siblings
+// are renumbered, and the automatic aliases of the moved page and
+// its descendants are refreshed.
+func (store *storeImplementation) PageMove(ctx context.Context, pageID string, parentID string, position int) error {
+	if store.neatDB == nil {
+		return errors.New("pagestore: database is nil")
+	}
+
+	if pageID == "" {
+		return errors.New("page id is empty")
+	}
+
+	page, err := store.PageFindByID(ctx, pageID)
+
+	if err != nil {
+		return err
+	}
+
+	if page == nil {
+		return errors.New("page not found")
+	}
+
+	if parentID != "" {
+		parent, err := store.PageFindByID(ctx, parentID)
+
+		if err != nil {
+			return err
+		}
+
+		if parent == nil {
+			return errors.New("parent page not found")
+		}
+
+		if parent.SiteID() != page.SiteID() {
+			return errors.New("parent page belongs to another site")
+		}
+
+		// Walk up the ancestors of the new parent, to prevent cycles
+		visited := map[string]bool{}
+		for ancestor := parent; ancestor != nil && !visited[ancestor.ID()]; {
+			if ancestor.ID() == page.ID() {
+				return errors.New("page cannot be moved into itself or its descendants")
+			}
+
+			visited[ancestor.ID()] = true
+
+			if ancestor.ParentID() == "" {
+				break
+			}
+
+			ancestor, err = store.PageFindByID(ctx, ancestor.ParentID())
+
+			if err != nil {
+				return err
+			}
+		}
+
+		parentID = parent.ID()
+	}
+
+	oldParentID := page.ParentID()
+
+	siblings, err := store.pageSiblings(ctx, page.SiteID(), parentID)
+
+	if err != nil {
+		return err
+	}
+
+	siblings = slices.DeleteFunc(siblings, func(sibling PageInterface) bool {
+		return sibling.ID() == page.ID()
+	})
+
+	position = max(0, min(position, len(siblings)))
+	siblings = slices.Insert(siblings, position, PageInterface(page))
+
+	page.SetParentID(parentID)
+
+	if err := store.pageResequence(ctx, siblings); err != nil {
+		return err
+	}
+
+	if oldParentID == parentID {
+		return nil
+	}
+
+	oldSiblings, err := store.pageSiblings(ctx, page.SiteID(), oldParentID)
+
+	if err != nil {
+		return err
+	}
+
+	if err := store.pageResequence(ctx, oldSiblings); err != nil {
+		return err
+	}
+
+	return store.PageAliasesRefresh(ctx, page.ID())
+}
+
+// PageAliasesRefresh re-derives the alias of the page, if it is
+// automatic (see PageAliasIsAuto), from the alias of its parent.
+// The pages below it are refreshed too, as their automatic aliases
+// depend on it.
+func (store *storeImplementation) PageAliasesRefresh(ctx context.Context, pageID string) error {
+	if store.neatDB == nil {
+		return errors.New("pagestore: database is nil")
+	}
+
+	if pageID == "" {
+		return errors.New("page id is empty")
+	}
+
+	page, err := store.PageFindByID(ctx, pageID)
+
+	if err != nil {
+		return err
+	}
+
+	if page == nil {
+		return errors.New("page not found")
+	}
+
+	return store.pageAliasesRefresh(ctx, page, map[string]bool{})
+}
+
+// pageAliasesRefresh refreshes the automatic alias of the page and its
+// descendants, the visited pages guard against cycles in corrupted data
+func (store *storeImplementation) pageAliasesRefresh(ctx context.Context, page PageInterface, visited map[string]bool) error {
+	if visited[page.ID()] {
+		return nil
+	}
+
+	visited[page.ID()] = true
+
+	if PageAliasIsAuto(page) && PageAliasSlug(page.Alias()) != "" {
+		var parent PageInterface
+
+		if page.ParentID() != "" {
+			found, err := store.PageFindByID(ctx, page.ParentID())
+
+			if err != nil {
+				return err
+			}
+
+			if found != nil && found.SiteID() == page.SiteID() {
+				parent = found
+			}
+		}
+
+		alias := PageAliasFromParent(parent, PageAliasSlug(page.Alias()))
+
+		if alias != page.Alias() {
+			page.SetAlias(alias)
+
+			if err := store.PageUpdate(ctx, page); err != nil {
+				return err
+			}
+		}
+	}
+
+	children, err := store.pageSiblings(ctx, page.SiteID(), page.ID())
+
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		if err := store.pageAliasesRefresh(ctx, child, visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pageSiblings returns the pages of the site with the given parent,
+// in sequence order
+func (store *storeImplementation) pageSiblings(ctx context.Context, siteID string, parentID string) ([]PageInterface, error) {
+	return store.PageList(ctx, PageQuery().
+		SetSiteID(siteID).
+		SetParentID(parentID).
+		SetOrderBy(COLUMN_SEQUENCE).
+		SetSortOrder(SORT_ORDER_ASC))
+}
+
+// pageResequence numbers the pages in their order, from 0,
+// only the changed pages are updated
+func (store *storeImplementation) pageResequence(ctx context.Context, pages []PageInterface) error {
+	for sequence, page := range pages {
+		if page.Sequence() != strconv.Itoa(sequence) {
+			page.SetSequenceInt(sequence)
+		}
+
+		if len(page.DataChanged()) == 0 {
+			continue
+		}
+
+		if err := store.PageUpdate(ctx, page); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PageAliasIsAuto returns whether the alias of the page is derived
+// automatically from the alias of its parent
+func PageAliasIsAuto(page PageInterface) bool {
+	if page == nil {
+		return false
+	}
+
+	return page.Meta(PAGE_META_ALIAS_AUTO) == "yes"
+}
+
+// SetPageAliasAuto sets whether the alias of the page is derived
+// automatically from the alias of its parent
+func SetPageAliasAuto(page PageInterface, auto bool) error {
+	if page == nil {
+		return errors.New("page is nil")
+	}
+
+	metas, err := page.Metas()
+	if err != nil {
+		return err
+	}
+
+	if metas == nil {
+		metas = map[string]string{}
+	}
+
+	if auto {
+		metas[PAGE_META_ALIAS_AUTO] = "yes"
+	} else {
+		delete(metas, PAGE_META_ALIAS_AUTO)
+	}
+
+	return page.SetMetas(metas)
+}
+
+// PageAliasSlug returns the last segment of the alias,
+// i.e. "team" for "/about/team"
+func PageAliasSlug(alias string) string {
+	alias = strings.Trim(alias, "/")
+
+	if index := strings.LastIndex(alias, "/"); index >= 0 {
+		return alias[index+1:]
+	}
+
+	return alias
+}
+
+// PageAliasFromParent returns the alias of a page with the given slug
+// under the parent page, i.e. "/about/team" for the slug "team" under
+// a parent with the alias "/about". A nil parent gives "/team".
+func PageAliasFromParent(parent PageInterface, slug string) string {
+	slug = strings.Trim(slug, "/")
+
+	if parent == nil {
+		return "/" + slug
+	}
+
+	parentAlias := strings.Trim(parent.Alias(), "/")
+
+	if parentAlias == "" {
+		return "/" + slug
+	}
+
+	return "/" + parentAlias + "/" + slug
+}
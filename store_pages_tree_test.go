@@ -0,0 +1,322 @@
+package cmsstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStorePageTree(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	newPage := func(name, parentID string, sequence int) PageInterface {
+		page := NewPage().
+			SetSiteID("SitePageTree").
+			SetName(name).
+			SetParentID(parentID).
+			SetSequenceInt(sequence)
+
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		return page
+	}
+
+	about := newPage("about", "", 1)
+	newPage("home", "", 0)
+	team := newPage("team", about.ID(), 1)
+	history := newPage("history", about.ID(), 0)
+	orphan := newPage("orphan", "missing", 0)
+	lead := newPage("lead", team.ID(), 0)
+
+	var render func(nodes []*PageTreeNode) string
+	render = func(nodes []*PageTreeNode) string {
+		result := []string{}
+		for _, node := range nodes {
+			name := node.Page.Name()
+			if len(node.Children) > 0 {
+				name += "(" + render(node.Children) + ")"
+			}
+			result = append(result, name)
+		}
+		return strings.Join(result, ",")
+	}
+
+	tree, err := store.PageTree(ctx, "SitePageTree")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := render(tree); got != "home,orphan,about(history,team(lead))" && got != "orphan,home,about(history,team(lead))" {
+		t.Fatal("Tree MUST be home,about(history,team(lead)) with the orphan at the top level, found:", got)
+	}
+
+	children, err := store.PageChildren(ctx, about.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(children) != 2 || children[0].ID() != history.ID() || children[1].ID() != team.ID() {
+		t.Fatal("Children of about MUST be history,team, found:", len(children))
+	}
+
+	ancestors, err := store.PageAncestors(ctx, lead.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(ancestors) != 2 || ancestors[0].ID() != about.ID() || ancestors[1].ID() != team.ID() {
+		t.Fatal("Ancestors of lead MUST be about,team, found:", len(ancestors))
+	}
+
+	ancestors, err = store.PageAncestors(ctx, orphan.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(ancestors) != 0 {
+		t.Fatal("Ancestors of the orphan MUST be empty, found:", len(ancestors))
+	}
+}
+
+func TestStorePageTree_Cycle(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	a := NewPage().SetSiteID("SitePageTreeCycle").SetName("a").SetSequenceInt(0)
+	b := NewPage().SetSiteID("SitePageTreeCycle").SetName("b").SetSequenceInt(1)
+	a.SetParentID(b.ID())
+	b.SetParentID(a.ID())
+
+	for _, page := range []PageInterface{a, b} {
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+	}
+
+	tree, err := store.PageTree(ctx, "SitePageTreeCycle")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(tree) != 1 || tree[0].Page.ID() != a.ID() || len(tree[0].Children) != 1 {
+		t.Fatal("The cycle MUST be broken at the first page, found roots:", len(tree))
+	}
+
+	ancestors, err := store.PageAncestors(ctx, a.ID())
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if len(ancestors) != 1 || ancestors[0].ID() != b.ID() {
+		t.Fatal("Ancestors of a MUST stop at the cycle, found:", len(ancestors))
+	}
+}
+
+func TestStorePageMove(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	newPage := func(name, parentID string, sequence int) PageInterface {
+		page := NewPage().
+			SetSiteID("SitePageMove").
+			SetName(name).
+			SetAlias("/" + name).
+			SetParentID(parentID).
+			SetSequenceInt(sequence)
+
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		return page
+	}
+
+	names := func(parentID string) string {
+		pages, err := store.PageList(ctx, PageQuery().
+			SetSiteID("SitePageMove").
+			SetParentID(parentID).
+			SetOrderBy(COLUMN_SEQUENCE).
+			SetSortOrder(SORT_ORDER_ASC))
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		result := []string{}
+		for i, page := range pages {
+			if page.SequenceInt() != i {
+				t.Fatalf("Sequence of %s MUST be %d, found: %d", page.Name(), i, page.SequenceInt())
+			}
+			result = append(result, page.Name())
+		}
+
+		return strings.Join(result, ",")
+	}
+
+	about := newPage("about", "", 0)
+	a := newPage("a", about.ID(), 0)
+	b := newPage("b", about.ID(), 1)
+	c := newPage("c", about.ID(), 2)
+	newPage("contact", "", 1)
+
+	// Reorder within the same parent
+	if err := store.PageMove(ctx, c.ID(), about.ID(), 0); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := names(about.ID()); got != "c,a,b" {
+		t.Fatal("Children MUST be c,a,b, found:", got)
+	}
+
+	// Move to the top level, the old siblings are renumbered
+	if err := store.PageMove(ctx, a.ID(), "", 1); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := names(""); got != "about,a,contact" {
+		t.Fatal("Top level MUST be about,a,contact, found:", got)
+	}
+
+	if got := names(about.ID()); got != "c,b" {
+		t.Fatal("Children MUST be c,b, found:", got)
+	}
+
+	// Cycles are rejected
+	if err := store.PageMove(ctx, about.ID(), b.ID(), 0); err == nil {
+		t.Fatal("Moving a page into its descendant MUST fail")
+	}
+
+	if err := store.PageMove(ctx, about.ID(), about.ID(), 0); err == nil {
+		t.Fatal("Moving a page into itself MUST fail")
+	}
+
+	// Other sites are rejected
+	other := NewPage().SetSiteID("SitePageMoveOther").SetName("other")
+	if err := store.PageCreate(ctx, other); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.PageMove(ctx, b.ID(), other.ID(), 0); err == nil {
+		t.Fatal("Moving a page under a page of another site MUST fail")
+	}
+}
+
+func TestStorePageMove_AliasAuto(t *testing.T) {
+	store, err := initStore(":memory:")
+
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	ctx := context.Background()
+
+	newPage := func(alias string, auto bool) PageInterface {
+		page := NewPage().SetSiteID("SitePageAlias").SetAlias(alias)
+
+		if err := SetPageAliasAuto(page, auto); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if err := store.PageCreate(ctx, page); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		return page
+	}
+
+	alias := func(page PageInterface) string {
+		found, err := store.PageFindByID(ctx, page.ID())
+
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		return found.Alias()
+	}
+
+	about := newPage("/about", false)
+	company := newPage("/company/", false)
+	team := newPage("/team", true)
+	lead := newPage("/lead", true)
+	manual := newPage("/manual", false)
+
+	if err := store.PageMove(ctx, lead.ID(), team.ID(), 0); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.PageMove(ctx, manual.ID(), team.ID(), 1); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if err := store.PageMove(ctx, team.ID(), about.ID(), 0); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := alias(team); got != "/about/team" {
+		t.Fatal("Alias MUST be /about/team, found:", got)
+	}
+
+	if got := alias(lead); got != "/about/team/lead" {
+		t.Fatal("Alias MUST be /about/team/lead, found:", got)
+	}
+
+	if got := alias(manual); got != "/manual" {
+		t.Fatal("Manual alias MUST be kept, found:", got)
+	}
+
+	if err := store.PageMove(ctx, team.ID(), company.ID(), 0); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := alias(lead); got != "/company/team/lead" {
+		t.Fatal("Alias MUST be /company/team/lead, found:", got)
+	}
+
+	if err := store.PageMove(ctx, team.ID(), "", 0); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if got := alias(lead); got != "/team/lead" {
+		t.Fatal("Alias MUST be /team/lead, found:", got)
+	}
+}
+
+func TestPageAliasSlug(t *testing.T) {
+	cases := map[string]string{
+		"":             "",
+		"/":            "",
+		"about":        "about",
+		"/about/team/": "team",
+		"/about/team":  "team",
+	}
+
+	for alias, expected := range cases {
+		if got := PageAliasSlug(alias); got != expected {
+			t.Errorf("PageAliasSlug(%q) MUST be %q, found: %q", alias, expected, got)
+		}
+	}
+}
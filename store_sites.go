@@ -46,6 +46,13 @@ func (store *storeImplementation) SiteCreate(ctx context.Context, site SiteInter
 	site.SetCreatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 	site.SetUpdatedAt(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
 
+	// New sites escape placeholders by default (safe default)
+	if site.Meta(SITE_META_PLACEHOLDER_ESCAPING) == "" {
+		if err := site.SetMeta(SITE_META_PLACEHOLDER_ESCAPING, PLACEHOLDER_ESCAPING_HTML); err != nil {
+			return err
+		}
+	}
+
 	return store.withTransaction(ctx, func(txCtx context.Context) error {
 		data := site.Data()
 
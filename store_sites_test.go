@@ -34,6 +34,10 @@ func TestStoreSiteCreate(t *testing.T) {
 	if err != nil {
 		t.Fatal("unexpected error:", err)
 	}
+
+	if site.Meta(SITE_META_PLACEHOLDER_ESCAPING) != PLACEHOLDER_ESCAPING_HTML {
+		t.Fatal("expected placeholder escaping to default to html, got:", site.Meta(SITE_META_PLACEHOLDER_ESCAPING))
+	}
 }
 
 func TestStoreSiteFindByHandle(t *testing.T) {
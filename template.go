@@ -193,6 +193,24 @@ func (o *templateImplementation) SetName(name string) TemplateInterface {
 	return o
 }
 
+// ParentID returns the ID of the parent template this template extends
+//
+// The parent ID is stored in the template metas, an empty string
+// means the template does not extend another template
+func (o *templateImplementation) ParentID() string {
+	return o.Meta(TEMPLATE_META_PARENT_ID)
+}
+
+// SetParentID sets the ID of the parent template this template extends
+//
+// The parent ID is stored in the template metas. Invalid metas are kept
+// as is, the parent ID is then not set, check the error of Metas first.
+func (o *templateImplementation) SetParentID(parentID string) TemplateInterface {
+	// Fails only if the metas are invalid, see above
+	o.SetMeta(TEMPLATE_META_PARENT_ID, parentID)
+	return o
+}
+
 func (o *templateImplementation) SiteID() string {
 	return o.Get(COLUMN_SITE_ID)
 }
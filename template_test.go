@@ -225,6 +225,43 @@ func TestTemplateMetasMethods(t *testing.T) {
 	}
 }
 
+func TestTemplateParentIDMethods(t *testing.T) {
+	template := NewTemplate()
+
+	if template.ParentID() != "" {
+		t.Errorf("Expected empty ParentID, got %s", template.ParentID())
+	}
+
+	if template.SetParentID("PARENT_01") != template {
+		t.Error("Expected SetParentID to return the template, for chaining")
+	}
+
+	if template.ParentID() != "PARENT_01" {
+		t.Errorf("Expected ParentID 'PARENT_01', got %s", template.ParentID())
+	}
+
+	if template.Meta(TEMPLATE_META_PARENT_ID) != "PARENT_01" {
+		t.Errorf("Expected parent ID to be stored in metas, got %s", template.Meta(TEMPLATE_META_PARENT_ID))
+	}
+
+	template.SetParentID("")
+
+	if template.ParentID() != "" {
+		t.Errorf("Expected empty ParentID after reset, got %s", template.ParentID())
+	}
+
+	invalid := NewTemplateFromExistingData(map[string]string{COLUMN_METAS: "{invalid"})
+	invalid.SetParentID("PARENT_01")
+
+	if invalid.ParentID() != "" || invalid.Data()[COLUMN_METAS] != "{invalid" {
+		t.Errorf("Expected the invalid metas to be kept, got %s", invalid.Data()[COLUMN_METAS])
+	}
+
+	if _, err := invalid.Metas(); err == nil {
+		t.Error("Expected the metas error, when the metas are invalid")
+	}
+}
+
 func TestTemplateCreatedAtMethods(t *testing.T) {
 	template := NewTemplate()
 
@@ -59,6 +59,8 @@ func InitStore(filepath string) (cmsstore.StoreInterface, error) {
 		VersioningTableName:        "versioning_table",
 		MediaEnabled:               true,
 		MediaTableName:             "media_table",
+		FormsEnabled:               true,
+		FormSubmissionTableName:    "form_submission_table",
 		AutomigrateEnabled:         true,
 	})
 
@@ -90,6 +92,8 @@ func InitStoreWithVersioning(filepath string) (cmsstore.StoreInterface, error) {
 		VersioningTableName:        "versioning_table",
 		MediaEnabled:               true,
 		MediaTableName:             "media_table",
+		FormsEnabled:               true,
+		FormSubmissionTableName:    "form_submission_table",
 		AutomigrateEnabled:         true,
 	})
 
@@ -130,6 +134,8 @@ func InitStoreWithVersioningAndDB(filepath string) (cmsstore.StoreInterface, *sq
 		VersioningTableName:        "versioning_table",
 		MediaEnabled:               true,
 		MediaTableName:             "media_table",
+		FormsEnabled:               true,
+		FormSubmissionTableName:    "form_submission_table",
 		AutomigrateEnabled:         true,
 	})
 